@@ -0,0 +1,20 @@
+package config
+
+import "time"
+
+// DefaultCacheTimeout là thời gian tối đa của một lệnh redis, kể cả khi phải mở kết nối
+const DefaultCacheTimeout = time.Second
+
+// CacheConfig là redis dùng làm cache; Addr rỗng nghĩa là không dùng cache và readiness
+// chỉ kiểm tra database. Password và DB được gửi (AUTH, SELECT) khi mở mỗi kết nối.
+type CacheConfig struct {
+	Addr     string        `yaml:"addr" env:"CACHE_ADDR"`
+	Password string        `yaml:"password" env:"CACHE_PASSWORD"`
+	DB       int           `yaml:"db" env:"CACHE_DB"`
+	Timeout  time.Duration `yaml:"timeout" env:"CACHE_TIMEOUT"`
+}
+
+func (c CacheConfig) validate(check func(bool, string, ...any)) {
+	check(c.DB >= 0, "cache.db: must not be negative")
+	check(c.Timeout > 0, "cache.timeout: must be positive")
+}
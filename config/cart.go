@@ -0,0 +1,10 @@
+package config
+
+import "time"
+
+// DefaultCartTTL là thời gian một giỏ hàng không thay đổi trước khi hết hạn
+const DefaultCartTTL = 7 * 24 * time.Hour
+
+type CartConfig struct {
+	TTL time.Duration `yaml:"ttl" env:"CART_TTL"`
+}
@@ -0,0 +1,165 @@
+// Package config đọc cấu hình ứng dụng vào struct Config theo thứ tự ưu tiên tăng dần:
+// giá trị mặc định, file YAML (-config hoặc CONFIG_FILE), biến môi trường (kể cả từ
+// .env nếu có) và flag dòng lệnh. Mỗi trường khai báo tên trong file qua tag yaml và
+// tên biến môi trường qua tag env; flag có tên là đường dẫn yaml, vd: -server.port=9090.
+package config
+
+import (
+	"errors"
+	"fmt"
+	"log/slog"
+	"slices"
+	"strings"
+	"time"
+)
+
+// Các môi trường chạy; production bắt buộc cấu hình các giá trị bí mật
+const (
+	EnvDevelopment = "development"
+	EnvProduction  = "production"
+)
+
+type Config struct {
+	Env      string         `yaml:"env" env:"APP_ENV"`
+	Server   ServerConfig   `yaml:"server"`
+	Database DatabaseConfig `yaml:"database"`
+	JWT      JWTConfig      `yaml:"jwt"`
+	Log      LogConfig      `yaml:"log"`
+	Tracing  TracingConfig  `yaml:"tracing"`
+	Cache    CacheConfig    `yaml:"cache"`
+	Storage  StorageConfig  `yaml:"storage"`
+	Payment  PaymentConfig  `yaml:"payment"`
+	Tax      TaxConfig      `yaml:"tax"`
+	Invoice  InvoiceConfig  `yaml:"invoice"`
+	Order    OrderConfig    `yaml:"order"`
+	Cart     CartConfig     `yaml:"cart"`
+	Outbox   OutboxConfig   `yaml:"outbox"`
+	Webhook  WebhookConfig  `yaml:"webhook"`
+	Features FeatureConfig  `yaml:"features"`
+
+	RateLimit    RateLimitConfig `yaml:"rate_limit"`
+	LoginLockout LockoutConfig   `yaml:"login_lockout"`
+}
+
+// FeatureConfig bật/tắt các tính năng không bắt buộc
+type FeatureConfig struct {
+	// Metrics phục vụ /metrics cho Prometheus
+	Metrics bool `yaml:"metrics" env:"FEATURE_METRICS"`
+	// Webhooks bật API quản lý webhook, sender và sink "webhook" của outbox
+	Webhooks bool `yaml:"webhooks" env:"FEATURE_WEBHOOKS"`
+	// Import bật endpoint import sách hàng loạt
+	Import bool `yaml:"import" env:"FEATURE_IMPORT"`
+}
+
+// Default trả về cấu hình mặc định cho môi trường development
+func Default() Config {
+	return Config{
+		Env: EnvDevelopment,
+		Server: ServerConfig{
+			Port:              8080,
+			RequestTimeout:    DefaultRequestTimeout,
+			ReadHeaderTimeout: 10 * time.Second,
+			IdleTimeout:       2 * time.Minute,
+			ShutdownTimeout:   DefaultShutdownTimeout,
+		},
+		Database: DatabaseConfig{
+			Host:               "127.0.0.1",
+			Port:               3306,
+			MaxOpenConns:       25,
+			MaxIdleConns:       10,
+			ConnMaxLifetime:    30 * time.Minute,
+			ConnMaxIdleTime:    5 * time.Minute,
+			SlowQueryThreshold: DefaultSlowQueryThreshold,
+		},
+		JWT:     JWTConfig{TTL: DefaultJWTTTL},
+		Cache:   CacheConfig{Timeout: DefaultCacheTimeout},
+		Log:     LogConfig{Level: "info"},
+		Tracing: TracingConfig{Exporter: "none"},
+		Storage: StorageConfig{
+			Driver:   "local",
+			LocalDir: "uploads",
+			BaseURL:  "/uploads",
+			S3:       S3Config{Region: "us-east-1", UseSSL: true},
+		},
+		Payment: PaymentConfig{Provider: "fake", WebhookSecret: defaultWebhookSecret},
+		Invoice: InvoiceConfig{SellerName: "Bookstore", NumberPrefix: "INV"},
+		Order:   OrderConfig{ReservationTTL: DefaultReservationTTL},
+		Cart:    CartConfig{TTL: DefaultCartTTL},
+		Outbox:  OutboxConfig{Sinks: []string{"log", "webhook"}, PollInterval: DefaultOutboxPollInterval},
+		Webhook: WebhookConfig{Timeout: DefaultWebhookTimeout, MaxAttempts: DefaultWebhookMaxAttempts},
+		Features: FeatureConfig{
+			Metrics:  true,
+			Webhooks: true,
+			Import:   true,
+		},
+		RateLimit: RateLimitConfig{
+			Enabled:        true,
+			Store:          "memory",
+			IPPerMinute:    120,
+			IPBurst:        60,
+			UserPerMinute:  300,
+			UserBurst:      100,
+			LoginPerMinute: 10,
+			LoginBurst:     5,
+		},
+		LoginLockout: LockoutConfig{Threshold: 5, Base: time.Minute, Max: time.Hour},
+	}
+}
+
+// Validate kiểm tra toàn bộ cấu hình và trả về mọi lỗi cùng lúc
+func (c *Config) Validate() error {
+	var errs []error
+	check := func(ok bool, format string, args ...any) {
+		if !ok {
+			errs = append(errs, fmt.Errorf(format, args...))
+		}
+	}
+
+	check(c.Env == EnvDevelopment || c.Env == EnvProduction, "env: must be %q or %q, got %q", EnvDevelopment, EnvProduction, c.Env)
+	c.Server.validate(check)
+	c.Database.validate(check)
+	c.JWT.validate(check, c.Env == EnvProduction)
+
+	var level slog.Level
+	check(level.UnmarshalText([]byte(c.Log.Level)) == nil, "log.level: unknown level %q", c.Log.Level)
+	check(slices.Contains([]string{"none", "stdout", "console", "otlp"}, c.Tracing.Exporter),
+		"tracing.exporter: unknown exporter %q", c.Tracing.Exporter)
+
+	switch c.Storage.Driver {
+	case "local":
+		check(c.Storage.LocalDir != "", "storage.local_dir: required for the local driver")
+	case "s3":
+		check(c.Storage.S3.Endpoint != "" && c.Storage.S3.Bucket != "", "storage.s3: endpoint and bucket are required for the s3 driver")
+	default:
+		check(false, "storage.driver: unknown driver %q", c.Storage.Driver)
+	}
+	c.Payment.validate(check, c.Env == EnvProduction)
+
+	check(c.Order.ReservationTTL > 0, "order.reservation_ttl: must be positive")
+	check(c.Cart.TTL > 0, "cart.ttl: must be positive")
+	for _, sink := range c.Outbox.Sinks {
+		check(slices.Contains([]string{"log", "webhook", "none"}, sink), "outbox.sinks: unknown sink %q", sink)
+	}
+	check(c.Outbox.PollInterval > 0, "outbox.poll_interval: must be positive")
+	check(c.Webhook.Timeout > 0, "webhook.timeout: must be positive")
+	check(c.Webhook.MaxAttempts > 0, "webhook.max_attempts: must be positive")
+	c.Cache.validate(check)
+	c.RateLimit.validate(check, c.Cache.Addr)
+	c.LoginLockout.validate(check)
+
+	if len(errs) > 0 {
+		return fmt.Errorf("invalid configuration:\n%w", errors.Join(errs...))
+	}
+	return nil
+}
+
+// splitList tách danh sách cách nhau bởi dấu phẩy, bỏ phần tử rỗng
+func splitList(raw string) []string {
+	var out []string
+	for _, item := range strings.Split(raw, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			out = append(out, item)
+		}
+	}
+	return out
+}
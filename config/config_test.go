@@ -0,0 +1,178 @@
+package config_test
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/maithuc2003/Test_GIN_golang/config"
+)
+
+// setDBEnv đặt các biến database bắt buộc
+func setDBEnv(t *testing.T) {
+	t.Setenv("DB_USER", "root")
+	t.Setenv("DB_NAME", "bookstore")
+}
+
+func writeFile(t *testing.T, content string) string {
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	require.NoError(t, os.WriteFile(path, []byte(content), 0o600))
+	return path
+}
+
+func TestLoad_Defaults(t *testing.T) {
+	setDBEnv(t)
+
+	cfg, err := config.Load(nil)
+	require.NoError(t, err)
+	require.Equal(t, config.EnvDevelopment, cfg.Env)
+	require.Equal(t, ":8080", cfg.Server.Addr())
+	require.Equal(t, config.DefaultRequestTimeout, cfg.Server.RequestTimeout)
+	require.Equal(t, 3306, cfg.Database.Port)
+	require.Equal(t, 25, cfg.Database.MaxOpenConns)
+	require.Equal(t, config.DefaultJWTTTL, cfg.JWT.TTL)
+	require.Equal(t, []string{"log", "webhook"}, cfg.Outbox.Sinks)
+	require.True(t, cfg.Features.Webhooks)
+	require.Equal(t, config.DefaultCacheTimeout, cfg.Cache.Timeout)
+	require.Equal(t, 10, cfg.RateLimit.LoginPerMinute)
+	require.Equal(t, 5, cfg.RateLimit.LoginBurst)
+	require.Equal(t, config.LockoutConfig{Threshold: 5, Base: time.Minute, Max: time.Hour}, cfg.LoginLockout)
+}
+
+func TestLoad_Precedence(t *testing.T) {
+	setDBEnv(t)
+	path := writeFile(t, `
+server:
+  port: 9000
+  request_timeout: 3s
+database:
+  host: db.internal
+  max_open_conns: 50
+outbox:
+  sinks: [log]
+features:
+  import: false
+`)
+	t.Setenv("CONFIG_FILE", path)
+	t.Setenv("PORT", "9001")
+	t.Setenv("DB_MAX_OPEN_CONNS", "40")
+
+	cfg, err := config.Load([]string{"-server.port=9002", "-features.import"})
+	require.NoError(t, err)
+	require.Equal(t, 9002, cfg.Server.Port, "flag beats env and file")
+	require.Equal(t, 40, cfg.Database.MaxOpenConns, "env beats file")
+	require.Equal(t, 3*time.Second, cfg.Server.RequestTimeout, "file beats default")
+	require.Equal(t, "db.internal", cfg.Database.Host)
+	require.Equal(t, []string{"log"}, cfg.Outbox.Sinks)
+	require.True(t, cfg.Features.Import, "bool flag without value means true")
+}
+
+func TestLoad_DatabasePassword(t *testing.T) {
+	setDBEnv(t)
+	t.Setenv("DB_PASS", "from-compose")
+
+	cfg, err := config.Load(nil)
+	require.NoError(t, err)
+	require.Equal(t, "from-compose", cfg.Database.Password)
+
+	t.Setenv("DB_PASSWORD", "preferred")
+	cfg, err = config.Load(nil)
+	require.NoError(t, err)
+	require.Equal(t, "preferred", cfg.Database.Password)
+}
+
+func TestLoad_Production(t *testing.T) {
+	setDBEnv(t)
+	t.Setenv("APP_ENV", "production")
+	t.Setenv("JWT_SECRET", strings.Repeat("s", 32))
+	t.Setenv("PAYMENT_WEBHOOK_SECRET", "whsec-production")
+
+	cfg, err := config.Load(nil)
+	require.NoError(t, err)
+	require.Equal(t, config.EnvProduction, cfg.Env)
+}
+
+func TestLoad_Errors(t *testing.T) {
+	tests := []struct {
+		name    string
+		env     map[string]string
+		args    []string
+		file    string
+		wantErr []string
+	}{
+		{
+			name:    "invalid env value names the variable",
+			env:     map[string]string{"REQUEST_TIMEOUT": "10"},
+			wantErr: []string{`REQUEST_TIMEOUT: invalid duration "10"`},
+		},
+		{
+			name:    "invalid flag value",
+			args:    []string{"-webhook.max_attempts=many"},
+			wantErr: []string{`invalid integer "many"`},
+		},
+		{
+			name:    "unknown key in file",
+			file:    "server:\n  prot: 9000\n",
+			wantErr: []string{"field prot not found"},
+		},
+		{
+			name: "rate limit and lockout",
+			env: map[string]string{
+				"RATE_LIMIT_STORE": "redis", "RATE_LIMIT_LOGIN_BURST": "0",
+				"LOGIN_LOCKOUT_BASE": "2h",
+			},
+			wantErr: []string{
+				"rate_limit.store: redis requires cache.addr",
+				"rate_limit.login_burst: must be positive",
+				"login_lockout: base must be positive and max at least base",
+			},
+		},
+		{
+			name:    "cache",
+			env:     map[string]string{"CACHE_DB": "-1", "CACHE_TIMEOUT": "0s"},
+			wantErr: []string{"cache.db: must not be negative", "cache.timeout: must be positive"},
+		},
+		{
+			name:    "unknown payment provider",
+			env:     map[string]string{"PAYMENT_PROVIDER": "stripe"},
+			wantErr: []string{`payment.provider: unknown provider "stripe"`},
+		},
+		{
+			name: "all validation errors reported together",
+			env: map[string]string{
+				"APP_ENV": "production", "DB_NAME": "", "STORAGE_DRIVER": "s3",
+				"OUTBOX_SINKS": "log,kafka", "DB_MAX_IDLE_CONNS": "100",
+			},
+			wantErr: []string{
+				"database.name: required",
+				"jwt.secret: must be at least 32 characters in production",
+				"storage.s3: endpoint and bucket are required",
+				`outbox.sinks: unknown sink "kafka"`,
+				"database.max_idle_conns: must not exceed max_open_conns",
+				"payment.webhook_secret: must not be the default secret in production",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			setDBEnv(t)
+			for k, v := range tt.env {
+				t.Setenv(k, v)
+			}
+			if tt.file != "" {
+				t.Setenv("CONFIG_FILE", writeFile(t, tt.file))
+			}
+
+			_, err := config.Load(tt.args)
+			require.Error(t, err)
+			for _, want := range tt.wantErr {
+				require.ErrorContains(t, err, want)
+			}
+		})
+	}
+}
@@ -2,40 +2,69 @@ package config
 
 import (
 	"fmt"
-	"log"
-	"os"
+	"log/slog"
+	"time"
 
-	"github.com/joho/godotenv"
+	"github.com/maithuc2003/Test_GIN_golang/internal/logging"
 	"gorm.io/driver/mysql"
 	"gorm.io/gorm"
 )
 
+// DefaultSlowQueryThreshold là ngưỡng query bị ghi log "slow query"
+const DefaultSlowQueryThreshold = 200 * time.Millisecond
+
 var DB *gorm.DB
 
-func ConnectDB() *gorm.DB {
-	err := godotenv.Load()
+type DatabaseConfig struct {
+	Host string `yaml:"host" env:"DB_HOST"`
+	Port int    `yaml:"port" env:"DB_PORT"`
+	User string `yaml:"user" env:"DB_USER"`
+	// DB_PASS là tên cũ trong docker-compose
+	Password string `yaml:"password" env:"DB_PASSWORD,DB_PASS"`
+	Name     string `yaml:"name" env:"DB_NAME"`
 
-	if err != nil {
-		log.Fatal("Error loading .env file")
-	}
+	MaxOpenConns    int           `yaml:"max_open_conns" env:"DB_MAX_OPEN_CONNS"`
+	MaxIdleConns    int           `yaml:"max_idle_conns" env:"DB_MAX_IDLE_CONNS"`
+	ConnMaxLifetime time.Duration `yaml:"conn_max_lifetime" env:"DB_CONN_MAX_LIFETIME"`
+	ConnMaxIdleTime time.Duration `yaml:"conn_max_idle_time" env:"DB_CONN_MAX_IDLE_TIME"`
+	// SlowQueryThreshold là ngưỡng ghi log query chậm; 0 tắt
+	SlowQueryThreshold time.Duration `yaml:"slow_query_threshold" env:"DB_SLOW_QUERY_THRESHOLD"`
+}
 
-	// Lấy giá trị từ biến môi trường
-	user := os.Getenv("DB_USER")
-	password := os.Getenv("DB_PASSWORD")
-	host := os.Getenv("DB_HOST")
-	port := os.Getenv("DB_PORT")
-	dbName := os.Getenv("DB_NAME")
+func (c DatabaseConfig) validate(check func(bool, string, ...any)) {
+	check(c.Host != "", "database.host: required")
+	check(c.Port > 0 && c.Port <= 65535, "database.port: must be between 1 and 65535, got %d", c.Port)
+	check(c.User != "", "database.user: required")
+	check(c.Name != "", "database.name: required")
+	check(c.MaxOpenConns >= 0, "database.max_open_conns: must not be negative")
+	check(c.MaxIdleConns >= 0, "database.max_idle_conns: must not be negative")
+	check(c.MaxOpenConns == 0 || c.MaxIdleConns <= c.MaxOpenConns, "database.max_idle_conns: must not exceed max_open_conns")
+	check(c.ConnMaxLifetime >= 0 && c.ConnMaxIdleTime >= 0, "database: connection lifetimes must not be negative")
+	check(c.SlowQueryThreshold >= 0, "database.slow_query_threshold: must not be negative")
+}
 
-	//Format DSN
-	dsn := fmt.Sprintf("%s:%s@tcp(%s:%s)/%s?charset=utf8mb4&parseTime=true&loc=Local", user, password, host, port, dbName)
+// ConnectDB mở kết nối MySQL và cấu hình connection pool
+func ConnectDB(c DatabaseConfig) (*gorm.DB, error) {
+	dsn := fmt.Sprintf("%s:%s@tcp(%s:%d)/%s?charset=utf8mb4&parseTime=true&loc=Local", c.User, c.Password, c.Host, c.Port, c.Name)
 
-	// Kết nối GORM
-	db, err := gorm.Open(mysql.Open(dsn), &gorm.Config{})
+	// Kết nối GORM, log query qua slog để có request_id và user_id
+	db, err := gorm.Open(mysql.Open(dsn), &gorm.Config{
+		Logger: logging.NewGormLogger(slog.Default(), c.SlowQueryThreshold),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to database: %w", err)
+	}
 
+	sqlDB, err := db.DB()
 	if err != nil {
-		log.Fatal("Failed to connect to database", err)
+		return nil, err
 	}
-	log.Println("Connected to database successfully")
+	sqlDB.SetMaxOpenConns(c.MaxOpenConns)
+	sqlDB.SetMaxIdleConns(c.MaxIdleConns)
+	sqlDB.SetConnMaxLifetime(c.ConnMaxLifetime)
+	sqlDB.SetConnMaxIdleTime(c.ConnMaxIdleTime)
+
+	slog.Info("Connected to database successfully", "host", c.Host, "database", c.Name)
 	DB = db
-	return db
+	return db, nil
 }
@@ -0,0 +1,11 @@
+package config
+
+type InvoiceConfig struct {
+	// Thông tin bên bán in trên hoá đơn
+	SellerName    string `yaml:"seller_name" env:"INVOICE_SELLER_NAME"`
+	SellerAddress string `yaml:"seller_address" env:"INVOICE_SELLER_ADDRESS"`
+	SellerTaxID   string `yaml:"seller_tax_id" env:"INVOICE_SELLER_TAX_ID"`
+	// NumberPrefix là tiền tố số hoá đơn; số hoá đơn có dạng PREFIX-YYYY-000001 và
+	// đánh lại từ 1 mỗi năm
+	NumberPrefix string `yaml:"number_prefix" env:"INVOICE_NUMBER_PREFIX"`
+}
@@ -0,0 +1,22 @@
+package config
+
+import "time"
+
+// DefaultJWTTTL là thời hạn của token đăng nhập
+const DefaultJWTTTL = 72 * time.Hour
+
+// minJWTSecretLength là độ dài tối thiểu của khoá HMAC ở production
+const minJWTSecretLength = 32
+
+type JWTConfig struct {
+	// Secret ký token HS256; để trống ở development thì dùng khoá mặc định của pkg/jwt
+	Secret string        `yaml:"secret" env:"JWT_SECRET"`
+	TTL    time.Duration `yaml:"ttl" env:"JWT_TTL"`
+}
+
+func (c JWTConfig) validate(check func(bool, string, ...any), production bool) {
+	check(c.TTL > 0, "jwt.ttl: must be positive")
+	if production {
+		check(len(c.Secret) >= minJWTSecretLength, "jwt.secret: must be at least %d characters in production", minJWTSecretLength)
+	}
+}
@@ -0,0 +1,177 @@
+package config
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/joho/godotenv"
+	"gopkg.in/yaml.v3"
+)
+
+// Load đọc cấu hình từ mặc định, file, biến môi trường và flag trong args (thường là
+// os.Args[1:]) rồi kiểm tra hợp lệ. File .env trong thư mục làm việc là tuỳ chọn:
+// trong container biến môi trường được truyền trực tiếp. Với -h trả về flag.ErrHelp.
+func Load(args []string) (*Config, error) {
+	if err := godotenv.Load(); err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return nil, fmt.Errorf("failed to load .env: %w", err)
+	}
+
+	cfg := Default()
+	fields := collect(reflect.ValueOf(&cfg).Elem(), "")
+
+	set := flag.NewFlagSet("bookstore", flag.ContinueOnError)
+	path := set.String("config", os.Getenv("CONFIG_FILE"), "path to a YAML config file (env CONFIG_FILE)")
+	// Flag được parse ngay để báo lỗi sớm nhưng chỉ ghi vào cfg sau file và env
+	var overrides []func()
+	for _, f := range fields {
+		parse := func(raw string) error {
+			v := reflect.New(f.value.Type()).Elem()
+			if err := setValue(v, raw); err != nil {
+				return err
+			}
+			overrides = append(overrides, func() { f.value.Set(v) })
+			return nil
+		}
+		if f.value.Kind() == reflect.Bool {
+			// Cho phép viết -features.import thay cho -features.import=true
+			set.BoolFunc(f.path, f.usage(), parse)
+			continue
+		}
+		set.Func(f.path, f.usage(), parse)
+	}
+	if err := set.Parse(args); err != nil {
+		return nil, err
+	}
+	if set.NArg() > 0 {
+		return nil, fmt.Errorf("unexpected arguments: %s", strings.Join(set.Args(), " "))
+	}
+
+	if *path != "" {
+		if err := readFile(&cfg, *path); err != nil {
+			return nil, err
+		}
+	}
+	for _, f := range fields {
+		if err := f.fromEnv(); err != nil {
+			return nil, err
+		}
+	}
+	for _, apply := range overrides {
+		apply()
+	}
+
+	if err := cfg.Validate(); err != nil {
+		return nil, err
+	}
+	return &cfg, nil
+}
+
+func readFile(cfg *Config, path string) error {
+	f, err := os.Open(path)
+	if err != nil {
+		return fmt.Errorf("failed to read config file: %w", err)
+	}
+	defer f.Close()
+
+	dec := yaml.NewDecoder(f)
+	// Báo lỗi khi file có khoá không tồn tại (thường là gõ sai)
+	dec.KnownFields(true)
+	if err := dec.Decode(cfg); err != nil && !errors.Is(err, io.EOF) {
+		return fmt.Errorf("failed to parse config file %s: %w", path, err)
+	}
+	return nil
+}
+
+// field là một giá trị lá trong Config cùng tên trong file/flag và các biến môi trường
+type field struct {
+	path  string
+	envs  []string
+	value reflect.Value
+}
+
+func (f field) usage() string {
+	if len(f.envs) == 0 {
+		return f.path
+	}
+	return "env " + strings.Join(f.envs, ", ")
+}
+
+// fromEnv ghi giá trị của biến môi trường đầu tiên khác rỗng trong f.envs
+func (f field) fromEnv() error {
+	for _, name := range f.envs {
+		raw := os.Getenv(name)
+		if raw == "" {
+			continue
+		}
+		if err := setValue(f.value, raw); err != nil {
+			return fmt.Errorf("%s: %w", name, err)
+		}
+		return nil
+	}
+	return nil
+}
+
+// collect duyệt struct theo tag yaml; tag env có thể liệt kê nhiều tên, tên đầu được ưu tiên
+func collect(v reflect.Value, prefix string) []field {
+	var fields []field
+	t := v.Type()
+	for i := 0; i < t.NumField(); i++ {
+		sf := t.Field(i)
+		name := strings.Split(sf.Tag.Get("yaml"), ",")[0]
+		if name == "" || name == "-" {
+			continue
+		}
+		path := prefix + name
+		if sf.Type.Kind() == reflect.Struct {
+			fields = append(fields, collect(v.Field(i), path+".")...)
+			continue
+		}
+		var envs []string
+		if tag := sf.Tag.Get("env"); tag != "" {
+			envs = strings.Split(tag, ",")
+		}
+		fields = append(fields, field{path: path, envs: envs, value: v.Field(i)})
+	}
+	return fields
+}
+
+var durationType = reflect.TypeOf(time.Duration(0))
+
+func setValue(v reflect.Value, raw string) error {
+	raw = strings.TrimSpace(raw)
+	switch {
+	case v.Type() == durationType:
+		d, err := time.ParseDuration(raw)
+		if err != nil {
+			return fmt.Errorf("invalid duration %q", raw)
+		}
+		v.SetInt(int64(d))
+	case v.Kind() == reflect.String:
+		v.SetString(raw)
+	case v.Kind() == reflect.Int:
+		n, err := strconv.Atoi(raw)
+		if err != nil {
+			return fmt.Errorf("invalid integer %q", raw)
+		}
+		v.SetInt(int64(n))
+	case v.Kind() == reflect.Bool:
+		b, err := strconv.ParseBool(raw)
+		if err != nil {
+			return fmt.Errorf("invalid boolean %q", raw)
+		}
+		v.SetBool(b)
+	case v.Kind() == reflect.Slice && v.Type().Elem().Kind() == reflect.String:
+		v.Set(reflect.ValueOf(splitList(raw)))
+	default:
+		return fmt.Errorf("unsupported config type %s", v.Type())
+	}
+	return nil
+}
@@ -0,0 +1,22 @@
+package config
+
+import (
+	"log/slog"
+	"os"
+
+	"github.com/maithuc2003/Test_GIN_golang/internal/logging"
+)
+
+type LogConfig struct {
+	// Level là debug, info, warn hoặc error; ở mức debug mọi query GORM đều được ghi
+	Level string `yaml:"level" env:"LOG_LEVEL"`
+}
+
+// Logger tạo logger JSON ghi ra stdout với mức Level (đã được Validate kiểm tra)
+func (c LogConfig) Logger() *slog.Logger {
+	var level slog.Level
+	if err := level.UnmarshalText([]byte(c.Level)); err != nil {
+		level = slog.LevelInfo
+	}
+	return logging.New(os.Stdout, level)
+}
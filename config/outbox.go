@@ -0,0 +1,13 @@
+package config
+
+import "time"
+
+// DefaultOutboxPollInterval là chu kỳ dispatcher đọc outbox
+const DefaultOutboxPollInterval = 5 * time.Second
+
+type OutboxConfig struct {
+	// Sinks là nơi nhận domain event: "log" và "webhook" (mặc định cả hai); "none" tắt
+	// dispatcher, event nằm lại trong outbox cho tới khi có sink được cấu hình
+	Sinks        []string      `yaml:"sinks" env:"OUTBOX_SINKS"`
+	PollInterval time.Duration `yaml:"poll_interval" env:"OUTBOX_POLL_INTERVAL"`
+}
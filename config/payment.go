@@ -0,0 +1,19 @@
+package config
+
+// PaymentConfig chọn cổng thanh toán. Hiện chỉ có "fake" (mặc định); khi có cổng thật
+// thì production mới cấm "fake". Chữ ký webhook dùng WebhookSecret.
+type PaymentConfig struct {
+	Provider      string `yaml:"provider" env:"PAYMENT_PROVIDER"`
+	WebhookSecret string `yaml:"webhook_secret" env:"PAYMENT_WEBHOOK_SECRET"`
+}
+
+// defaultWebhookSecret là khoá ký webhook mặc định của cổng fake, chỉ dùng ở development
+const defaultWebhookSecret = "fake-webhook-secret"
+
+func (c PaymentConfig) validate(check func(bool, string, ...any), production bool) {
+	check(c.Provider == "fake", "payment.provider: unknown provider %q", c.Provider)
+	check(c.WebhookSecret != "", "payment.webhook_secret: required")
+	if production {
+		check(c.WebhookSecret != defaultWebhookSecret, "payment.webhook_secret: must not be the default secret in production")
+	}
+}
@@ -0,0 +1,56 @@
+package config
+
+import "time"
+
+// RateLimitConfig cấu hình giới hạn request theo token bucket: số request mỗi phút (tốc
+// độ nạp, 0 là không giới hạn) và số request dồn tối đa. IP áp dụng cho request chưa
+// đăng nhập, User cho request có token hợp lệ, Login riêng cho POST /user/login theo IP.
+// Store "memory" giữ bucket trong từng instance; "redis" dùng cache.addr để các
+// instance chia sẻ giới hạn.
+type RateLimitConfig struct {
+	Enabled        bool   `yaml:"enabled" env:"RATE_LIMIT_ENABLED"`
+	Store          string `yaml:"store" env:"RATE_LIMIT_STORE"`
+	IPPerMinute    int    `yaml:"ip_per_minute" env:"RATE_LIMIT_IP_PER_MINUTE"`
+	IPBurst        int    `yaml:"ip_burst" env:"RATE_LIMIT_IP_BURST"`
+	UserPerMinute  int    `yaml:"user_per_minute" env:"RATE_LIMIT_USER_PER_MINUTE"`
+	UserBurst      int    `yaml:"user_burst" env:"RATE_LIMIT_USER_BURST"`
+	LoginPerMinute int    `yaml:"login_per_minute" env:"RATE_LIMIT_LOGIN_PER_MINUTE"`
+	LoginBurst     int    `yaml:"login_burst" env:"RATE_LIMIT_LOGIN_BURST"`
+}
+
+func (c RateLimitConfig) validate(check func(bool, string, ...any), cacheAddr string) {
+	switch c.Store {
+	case "memory":
+	case "redis":
+		check(cacheAddr != "", "rate_limit.store: redis requires cache.addr")
+	default:
+		check(false, "rate_limit.store: unknown store %q", c.Store)
+	}
+	rates := []struct {
+		name             string
+		perMinute, burst int
+	}{
+		{"ip", c.IPPerMinute, c.IPBurst},
+		{"user", c.UserPerMinute, c.UserBurst},
+		{"login", c.LoginPerMinute, c.LoginBurst},
+	}
+	for _, r := range rates {
+		check(r.perMinute >= 0, "rate_limit.%s_per_minute: must not be negative", r.name)
+		check(r.perMinute == 0 || r.burst > 0, "rate_limit.%s_burst: must be positive", r.name)
+	}
+}
+
+// LockoutConfig là chính sách khoá tài khoản sau nhiều lần đăng nhập sai liên tiếp;
+// Threshold 0 tắt tính năng
+type LockoutConfig struct {
+	Threshold int           `yaml:"threshold" env:"LOGIN_LOCKOUT_THRESHOLD"`
+	Base      time.Duration `yaml:"base" env:"LOGIN_LOCKOUT_BASE"`
+	Max       time.Duration `yaml:"max" env:"LOGIN_LOCKOUT_MAX"`
+}
+
+func (c LockoutConfig) validate(check func(bool, string, ...any)) {
+	check(c.Threshold >= 0, "login_lockout.threshold: must not be negative")
+	if c.Threshold > 0 {
+		check(c.Base > 0 && c.Max >= c.Base, "login_lockout: base must be positive and max at least base")
+	}
+}
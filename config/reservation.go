@@ -0,0 +1,11 @@
+package config
+
+import "time"
+
+// DefaultReservationTTL là thời gian giữ hàng cho một đơn chờ thanh toán
+const DefaultReservationTTL = 15 * time.Minute
+
+type OrderConfig struct {
+	// ReservationTTL là thời gian giữ hàng cho đơn pending trước khi bị huỷ
+	ReservationTTL time.Duration `yaml:"reservation_ttl" env:"RESERVATION_TTL"`
+}
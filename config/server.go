@@ -0,0 +1,44 @@
+package config
+
+import (
+	"fmt"
+	"time"
+)
+
+// DefaultRequestTimeout là thời gian tối đa một request giữ truy vấn database khi không
+// đặt REQUEST_TIMEOUT
+const DefaultRequestTimeout = 10 * time.Second
+
+// DefaultShutdownTimeout là thời gian chờ request đang chạy và worker nền kết thúc khi tắt
+const DefaultShutdownTimeout = 15 * time.Second
+
+type ServerConfig struct {
+	// Port lắng nghe; docker-compose đặt biến PORT
+	Port int `yaml:"port" env:"PORT"`
+	// RequestTimeout là deadline của context mỗi request; 0 tắt deadline
+	RequestTimeout time.Duration `yaml:"request_timeout" env:"REQUEST_TIMEOUT"`
+	// BulkRequestTimeout thay RequestTimeout cho import/export hàng loạt; 0 là không có
+	// deadline, request chỉ bị huỷ khi client ngắt kết nối
+	BulkRequestTimeout time.Duration `yaml:"bulk_request_timeout" env:"BULK_REQUEST_TIMEOUT"`
+	ReadHeaderTimeout  time.Duration `yaml:"read_header_timeout" env:"SERVER_READ_HEADER_TIMEOUT"`
+	// ReadTimeout và WriteTimeout bằng 0 là không giới hạn (upload/export lớn)
+	ReadTimeout  time.Duration `yaml:"read_timeout" env:"SERVER_READ_TIMEOUT"`
+	WriteTimeout time.Duration `yaml:"write_timeout" env:"SERVER_WRITE_TIMEOUT"`
+	IdleTimeout  time.Duration `yaml:"idle_timeout" env:"SERVER_IDLE_TIMEOUT"`
+	// ShutdownTimeout giới hạn thời gian chờ request đang chạy và worker nền khi tắt
+	ShutdownTimeout time.Duration `yaml:"shutdown_timeout" env:"SHUTDOWN_TIMEOUT"`
+}
+
+// Addr là địa chỉ lắng nghe dạng ":port"
+func (c ServerConfig) Addr() string {
+	return fmt.Sprintf(":%d", c.Port)
+}
+
+func (c ServerConfig) validate(check func(bool, string, ...any)) {
+	check(c.Port > 0 && c.Port <= 65535, "server.port: must be between 1 and 65535, got %d", c.Port)
+	check(c.RequestTimeout >= 0, "server.request_timeout: must not be negative")
+	check(c.BulkRequestTimeout >= 0, "server.bulk_request_timeout: must not be negative")
+	check(c.ReadHeaderTimeout > 0, "server.read_header_timeout: must be positive")
+	check(c.ReadTimeout >= 0 && c.WriteTimeout >= 0 && c.IdleTimeout >= 0, "server: read, write and idle timeouts must not be negative")
+	check(c.ShutdownTimeout > 0, "server.shutdown_timeout: must be positive")
+}
@@ -0,0 +1,19 @@
+package config
+
+// StorageConfig chọn nơi lưu file: "local" (mặc định) hoặc "s3"
+type StorageConfig struct {
+	Driver   string   `yaml:"driver" env:"STORAGE_DRIVER"`
+	LocalDir string   `yaml:"local_dir" env:"STORAGE_LOCAL_DIR"`
+	BaseURL  string   `yaml:"base_url" env:"STORAGE_BASE_URL"`
+	S3       S3Config `yaml:"s3"`
+}
+
+type S3Config struct {
+	Endpoint  string `yaml:"endpoint" env:"S3_ENDPOINT"`
+	Bucket    string `yaml:"bucket" env:"S3_BUCKET"`
+	AccessKey string `yaml:"access_key" env:"S3_ACCESS_KEY"`
+	SecretKey string `yaml:"secret_key" env:"S3_SECRET_KEY"`
+	Region    string `yaml:"region" env:"S3_REGION"`
+	UseSSL    bool   `yaml:"use_ssl" env:"S3_USE_SSL"`
+	PublicURL string `yaml:"public_url" env:"S3_PUBLIC_URL"`
+}
@@ -0,0 +1,14 @@
+package config
+
+type TaxConfig struct {
+	// RatesFile là file JSON chứa bảng thuế suất, vd:
+	//
+	//	{"default_region": "VN", "rules": [
+	//	  {"region": "VN", "rate": "0.05", "inclusive": true},
+	//	  {"region": "US-CA", "rate": "0.0725"},
+	//	  {"region": "US-CA", "category_id": 3, "rate": "0"}
+	//	]}
+	//
+	// Không cấu hình thì không tính thuế.
+	RatesFile string `yaml:"rates_file" env:"TAX_RATES_FILE"`
+}
@@ -0,0 +1,33 @@
+package config
+
+import (
+	"context"
+	"fmt"
+	"os"
+
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp"
+	"go.opentelemetry.io/otel/exporters/stdout/stdouttrace"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+)
+
+type TracingConfig struct {
+	// Exporter là nơi xuất span:
+	//   - "none" (mặc định): không ghi span
+	//   - "stdout" (hoặc "console"): in span dạng JSON ra stdout, dùng khi chạy local
+	//   - "otlp": gửi qua OTLP/HTTP tới OTEL_EXPORTER_OTLP_ENDPOINT (mặc định localhost:4318)
+	Exporter string `yaml:"exporter" env:"OTEL_TRACES_EXPORTER"`
+}
+
+// SpanExporter tạo exporter theo Exporter; nil nghĩa là tắt tracing
+func (c TracingConfig) SpanExporter(ctx context.Context) (sdktrace.SpanExporter, error) {
+	switch c.Exporter {
+	case "none", "":
+		return nil, nil
+	case "stdout", "console":
+		return stdouttrace.New(stdouttrace.WithWriter(os.Stdout))
+	case "otlp":
+		return otlptracehttp.New(ctx)
+	default:
+		return nil, fmt.Errorf("unknown trace exporter %q", c.Exporter)
+	}
+}
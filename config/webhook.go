@@ -0,0 +1,14 @@
+package config
+
+import "time"
+
+// DefaultWebhookTimeout là thời gian chờ phản hồi của endpoint đối tác cho mỗi lần gửi
+const DefaultWebhookTimeout = 10 * time.Second
+
+// DefaultWebhookMaxAttempts là số lần gửi tối đa trước khi delivery bị đánh dấu failed
+const DefaultWebhookMaxAttempts = 8
+
+type WebhookConfig struct {
+	Timeout     time.Duration `yaml:"timeout" env:"WEBHOOK_TIMEOUT"`
+	MaxAttempts int           `yaml:"max_attempts" env:"WEBHOOK_MAX_ATTEMPTS"`
+}
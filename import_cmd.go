@@ -0,0 +1,75 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/maithuc2003/Test_GIN_golang/internal/models"
+	"github.com/maithuc2003/Test_GIN_golang/internal/repositories/transaction"
+	"github.com/maithuc2003/Test_GIN_golang/internal/service/importer"
+	"gorm.io/gorm"
+)
+
+// runImport xử lý lệnh: go run . import -file books.csv [-format csv] [-dry-run] [-batch-size 500]
+// Trả về exit code: 0 thành công, 1 lỗi, 2 có dòng import thất bại
+func runImport(db *gorm.DB, args []string) int {
+	fs := flag.NewFlagSet("import", flag.ContinueOnError)
+	file := fs.String("file", "-", "path to CSV/NDJSON file, '-' for stdin")
+	format := fs.String("format", "", "csv or ndjson (default: detect from file extension)")
+	dryRun := fs.Bool("dry-run", false, "validate rows without writing")
+	batchSize := fs.Int("batch-size", importer.DefaultBatchSize, "rows per transaction")
+	if err := fs.Parse(args); err != nil {
+		return 1
+	}
+
+	var input io.Reader = os.Stdin
+	if *file != "-" {
+		f, err := os.Open(*file)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, "import:", err)
+			return 1
+		}
+		defer f.Close()
+		input = f
+		if *format == "" {
+			switch strings.ToLower(filepath.Ext(*file)) {
+			case ".csv":
+				*format = models.FormatCSV
+			case ".ndjson", ".jsonl":
+				*format = models.FormatNDJSON
+			}
+		}
+	}
+	if *format == "" {
+		fmt.Fprintln(os.Stderr, "import: cannot detect format, use -format csv|ndjson")
+		return 1
+	}
+
+	svc := importer.NewImportService(transaction.NewTransactionManager(db))
+	report, err := svc.ImportBooks(context.Background(), input, models.ImportOptions{
+		Format:    *format,
+		DryRun:    *dryRun,
+		BatchSize: *batchSize,
+	})
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "import:", err)
+		return 1
+	}
+
+	enc := json.NewEncoder(os.Stdout)
+	enc.SetIndent("", "  ")
+	if err := enc.Encode(report); err != nil {
+		fmt.Fprintln(os.Stderr, "import:", err)
+		return 1
+	}
+	if report.Failed > 0 {
+		return 2
+	}
+	return 0
+}
@@ -1,10 +1,23 @@
 package database
 
 import (
+	"context"
+
 	"github.com/maithuc2003/Test_GIN_golang/config"
+	"github.com/maithuc2003/Test_GIN_golang/internal/tracing"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
 )
 
-func HasAccess(userID int, accessName string) bool {
+func HasAccess(ctx context.Context, userID int, accessName string) bool {
+	// Query đi thẳng qua *sql.DB nên không có span của GORM
+	ctx, span := tracing.Start(ctx, "rbac.HasAccess", trace.WithAttributes(
+		attribute.Int("user.id", userID),
+		attribute.String("rbac.permission", accessName),
+	))
+	defer span.End()
+
 	var count int
 	query := `SELECT COUNT(*)
 		FROM users u
@@ -18,10 +31,13 @@ func HasAccess(userID int, accessName string) bool {
 		return false
 	}
 
-	err = sqlDB.QueryRow(query, userID, accessName).Scan(&count)
+	err = sqlDB.QueryRowContext(ctx, query, userID, accessName).Scan(&count)
 	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
 		return false
 	}
+	span.SetAttributes(attribute.Bool("rbac.granted", count > 0))
 
 	return count > 0
 }
@@ -0,0 +1,140 @@
+package database
+
+import (
+	"fmt"
+
+	"github.com/maithuc2003/Test_GIN_golang/internal/models"
+	"github.com/maithuc2003/Test_GIN_golang/pkg/textnorm"
+	"gorm.io/gorm"
+)
+
+const authorNormalizedNameIndex = "idx_authors_normalized_name"
+
+// Migrate tạo các bảng mới do ứng dụng quản lý. Các bảng có sẵn (users, books,
+// authors, orders, bảng RBAC) vẫn do schema hiện tại quản lý nên không nằm ở đây,
+// trừ các cột được bổ sung có chủ đích bên dưới và các quyền RBAC của route mới.
+func Migrate(db *gorm.DB) error {
+	if err := db.AutoMigrate(
+		&models.BookAuthor{},
+		&models.AuthorAlias{},
+		&models.Publisher{},
+		&models.Category{},
+		&models.BookCategory{},
+		&models.Cart{},
+		&models.CartItem{},
+		&models.StockReservation{},
+		&models.Payment{},
+		&models.ReturnRequest{},
+		&models.Coupon{},
+		&models.CouponRedemption{},
+		&models.OrderDiscount{},
+		&models.Invoice{},
+		&models.InvoiceSequence{},
+		&models.OutboxEvent{},
+		&models.WebhookSubscription{},
+		&models.WebhookDelivery{},
+		&models.WebhookDeliveryAttempt{},
+	); err != nil {
+		return err
+	}
+	if err := migrateAuthorNormalizedName(db); err != nil {
+		return err
+	}
+	if err := migrateBookMetadata(db); err != nil {
+		return err
+	}
+	if err := migrateOrderPricing(db); err != nil {
+		return err
+	}
+	if err := migrateUserLockout(db); err != nil {
+		return err
+	}
+	return SeedAccess(db)
+}
+
+// migrateUserLockout thêm các cột đếm lần đăng nhập sai và thời điểm hết khoá vào bảng users
+func migrateUserLockout(db *gorm.DB) error {
+	migrator := db.Migrator()
+	for _, field := range []string{"FailedLogins", "LockedUntil"} {
+		if migrator.HasColumn(&models.User{}, field) {
+			continue
+		}
+		if err := migrator.AddColumn(&models.User{}, field); err != nil {
+			return fmt.Errorf("failed to add users.%s: %w", field, err)
+		}
+	}
+	return nil
+}
+
+// migrateOrderPricing thêm các cột giá, giảm giá và thuế vào bảng orders
+func migrateOrderPricing(db *gorm.DB) error {
+	migrator := db.Migrator()
+	fields := []string{"Currency", "DiscountTotal", "UnitPrice", "Subtotal", "TaxRegion", "TaxRate", "TaxInclusive", "TaxTotal", "GrandTotal"}
+	for _, field := range fields {
+		if migrator.HasColumn(&models.Order{}, field) {
+			continue
+		}
+		if err := migrator.AddColumn(&models.Order{}, field); err != nil {
+			return fmt.Errorf("failed to add orders.%s: %w", field, err)
+		}
+	}
+	return nil
+}
+
+// migrateBookMetadata thêm các cột metadata (ISBN, nhà xuất bản, giá, ảnh bìa...) vào bảng books
+func migrateBookMetadata(db *gorm.DB) error {
+	migrator := db.Migrator()
+	fields := []string{"ISBN", "PublisherID", "Price", "Currency", "Language", "PageCount", "PublishedAt", "Description", "CoverKey", "Cover"}
+	for _, field := range fields {
+		if migrator.HasColumn(&models.Book{}, field) {
+			continue
+		}
+		if err := migrator.AddColumn(&models.Book{}, field); err != nil {
+			return fmt.Errorf("failed to add books.%s: %w", field, err)
+		}
+	}
+	for _, index := range []string{"idx_books_isbn", "PublisherID"} {
+		if migrator.HasIndex(&models.Book{}, index) {
+			continue
+		}
+		if err := migrator.CreateIndex(&models.Book{}, index); err != nil {
+			return fmt.Errorf("failed to create index %s on books: %w", index, err)
+		}
+	}
+	return nil
+}
+
+// migrateAuthorNormalizedName thêm cột authors.normalized_name, điền giá trị cho
+// dữ liệu cũ rồi mới tạo unique index. Nếu dữ liệu cũ đã trùng tên thì việc tạo
+// index thất bại và cần gộp các tác giả trùng trước.
+func migrateAuthorNormalizedName(db *gorm.DB) error {
+	migrator := db.Migrator()
+	if !migrator.HasColumn(&models.Author{}, "NormalizedName") {
+		if err := migrator.AddColumn(&models.Author{}, "NormalizedName"); err != nil {
+			return fmt.Errorf("failed to add authors.normalized_name: %w", err)
+		}
+	}
+
+	var authors []models.Author
+	err := db.Select("id", "name").
+		Where("normalized_name IS NULL OR normalized_name = ''").
+		FindInBatches(&authors, 500, func(tx *gorm.DB, _ int) error {
+			for _, a := range authors {
+				if err := tx.Model(&models.Author{}).Where("id = ?", a.ID).
+					UpdateColumn("normalized_name", textnorm.Name(a.Name)).Error; err != nil {
+					return err
+				}
+			}
+			return nil
+		}).Error
+	if err != nil {
+		return fmt.Errorf("failed to backfill authors.normalized_name: %w", err)
+	}
+
+	if !migrator.HasIndex(&models.Author{}, authorNormalizedNameIndex) {
+		if err := migrator.CreateIndex(&models.Author{}, authorNormalizedNameIndex); err != nil {
+			return fmt.Errorf("failed to create unique index on authors.normalized_name (merge duplicate authors first): %w", err)
+		}
+	}
+	return nil
+}
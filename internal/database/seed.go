@@ -0,0 +1,64 @@
+package database
+
+import (
+	"fmt"
+
+	"gorm.io/gorm"
+)
+
+// accessGrants là các quyền RBAC do ứng dụng thêm vào, kèm quyền có sẵn dùng làm mốc:
+// quyền mới được cấp cho mọi role đang có quyền mốc. Quyền quản trị đi theo book/create,
+// quyền của khách hàng đi theo order/create.
+var accessGrants = []struct {
+	access, like string
+}{
+	{"category/create", "book/create"},
+	{"category/update", "book/create"},
+	{"category/delete", "book/create"},
+	{"publisher/create", "book/create"},
+	{"publisher/update", "book/create"},
+	{"author/merge", "book/create"},
+	{"book/import", "book/create"},
+	{"book/export", "book/create"},
+	{"author/export", "book/create"},
+	{"order/export", "book/create"},
+	{"coupon/manage", "book/create"},
+	{"webhook/manage", "book/create"},
+	{"return/review", "book/create"},
+	{"cart/manage", "order/create"},
+	{"order/pay", "order/create"},
+	{"order/invoice", "order/create"},
+	{"order/return", "order/create"},
+}
+
+// SeedAccess tạo các quyền trong accessGrants và cấp cho role tương ứng. Chạy lại
+// nhiều lần không tạo bản ghi trùng; role đã bị thu hồi quyền mốc thì không được cấp.
+func SeedAccess(db *gorm.DB) error {
+	return db.Transaction(func(tx *gorm.DB) error {
+		for _, g := range accessGrants {
+			var count int64
+			if err := tx.Table("access").Where("access_name = ?", g.access).Count(&count).Error; err != nil {
+				return fmt.Errorf("failed to check access %s: %w", g.access, err)
+			}
+			if count == 0 {
+				if err := tx.Exec("INSERT INTO access (access_name) VALUES (?)", g.access).Error; err != nil {
+					return fmt.Errorf("failed to create access %s: %w", g.access, err)
+				}
+			}
+
+			err := tx.Exec(`INSERT INTO role_access (role_id, access_id)
+				SELECT ra.role_id, a.access_id
+				FROM role_access ra
+				JOIN access src ON src.access_id = ra.access_id
+				JOIN access a ON a.access_name = ?
+				WHERE src.access_name = ?
+				AND NOT EXISTS (
+					SELECT 1 FROM role_access x WHERE x.role_id = ra.role_id AND x.access_id = a.access_id
+				)`, g.access, g.like).Error
+			if err != nil {
+				return fmt.Errorf("failed to grant access %s: %w", g.access, err)
+			}
+		}
+		return nil
+	})
+}
@@ -0,0 +1,55 @@
+package database_test
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	sqlitedriver "gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+	_ "modernc.org/sqlite"
+
+	"github.com/maithuc2003/Test_GIN_golang/internal/database"
+)
+
+func setupRBACDB(t *testing.T) *gorm.DB {
+	dsn := fmt.Sprintf("file:testdb_%d?mode=memory&cache=shared", time.Now().UnixNano())
+	db, err := gorm.Open(sqlitedriver.New(sqlitedriver.Config{DSN: dsn, DriverName: "sqlite"}), &gorm.Config{})
+	require.NoError(t, err)
+	for _, stmt := range []string{
+		"CREATE TABLE access (access_id INTEGER PRIMARY KEY AUTOINCREMENT, access_name TEXT NOT NULL)",
+		"CREATE TABLE role_access (role_id INTEGER NOT NULL, access_id INTEGER NOT NULL)",
+		"INSERT INTO access (access_name) VALUES ('book/create'), ('order/create')",
+		// role 1 là admin, role 2 là khách hàng
+		"INSERT INTO role_access (role_id, access_id) VALUES (1, 1), (1, 2), (2, 2)",
+	} {
+		require.NoError(t, db.Exec(stmt).Error)
+	}
+	return db
+}
+
+// granted trả về các role có quyền name
+func granted(t *testing.T, db *gorm.DB, name string) []int {
+	var roles []int
+	require.NoError(t, db.Raw(`SELECT ra.role_id FROM role_access ra
+		JOIN access a ON a.access_id = ra.access_id
+		WHERE a.access_name = ? ORDER BY ra.role_id`, name).Scan(&roles).Error)
+	return roles
+}
+
+func TestSeedAccess(t *testing.T) {
+	db := setupRBACDB(t)
+
+	require.NoError(t, database.SeedAccess(db))
+	require.NoError(t, database.SeedAccess(db), "idempotent")
+
+	require.Equal(t, []int{1}, granted(t, db, "category/create"))
+	require.Equal(t, []int{1}, granted(t, db, "webhook/manage"))
+	require.Equal(t, []int{1, 2}, granted(t, db, "cart/manage"))
+	require.Equal(t, []int{1, 2}, granted(t, db, "order/pay"))
+
+	var accessCount int64
+	require.NoError(t, db.Table("access").Where("access_name = ?", "order/pay").Count(&accessCount).Error)
+	require.EqualValues(t, 1, accessCount)
+}
@@ -0,0 +1,102 @@
+package events
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/maithuc2003/Test_GIN_golang/internal/interfaces/repositories"
+)
+
+// Sink nhận event từ dispatcher. Event có thể được giao nhiều lần (khi một sink khác
+// hoặc việc đánh dấu đã giao thất bại) nên Deliver phải chịu được event trùng.
+type Sink interface {
+	Name() string
+	Deliver(ctx context.Context, event Event) error
+}
+
+const (
+	defaultBatchSize = 100
+	// defaultLease là thời gian một lượt giao giữ quyền với các event đã lấy
+	defaultLease      = time.Minute
+	defaultMaxBackoff = time.Hour
+)
+
+// Dispatcher đọc outbox và giao từng event lần lượt tới mọi sink. Event chỉ được đánh
+// dấu đã giao khi tất cả sink nhận thành công; ngược lại được thử lại với thời gian
+// chờ tăng dần. Thứ tự giao theo thứ tự ghi, trừ các event đang chờ thử lại.
+type Dispatcher struct {
+	repo       repositories.OutboxRepository
+	sinks      []Sink
+	batchSize  int
+	lease      time.Duration
+	maxBackoff time.Duration
+}
+
+func NewDispatcher(repo repositories.OutboxRepository, sinks ...Sink) *Dispatcher {
+	return &Dispatcher{
+		repo:       repo,
+		sinks:      sinks,
+		batchSize:  defaultBatchSize,
+		lease:      defaultLease,
+		maxBackoff: defaultMaxBackoff,
+	}
+}
+
+// DispatchPending giao một lượt các event tới hạn, trả về số event đã giao xong
+func (d *Dispatcher) DispatchPending(ctx context.Context) (int, error) {
+	records, err := d.repo.Claim(ctx, time.Now(), d.lease, d.batchSize)
+	if err != nil {
+		return 0, err
+	}
+	delivered := 0
+	for _, record := range records {
+		if err := d.deliver(ctx, FromRecord(record)); err != nil {
+			next := time.Now().Add(d.backoff(record.Attempts + 1))
+			if err := d.repo.MarkFailed(ctx, record.ID, next, err.Error()); err != nil {
+				return delivered, err
+			}
+			continue
+		}
+		if err := d.repo.MarkDelivered(ctx, record.ID, time.Now()); err != nil {
+			return delivered, err
+		}
+		delivered++
+	}
+	return delivered, nil
+}
+
+// Run giao event mỗi interval cho tới khi ctx bị huỷ
+func (d *Dispatcher) Run(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		if _, err := d.DispatchPending(ctx); err != nil && ctx.Err() == nil {
+			slog.ErrorContext(ctx, "failed to dispatch outbox events", "error", err)
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+func (d *Dispatcher) deliver(ctx context.Context, event Event) error {
+	for _, sink := range d.sinks {
+		if err := sink.Deliver(ctx, event); err != nil {
+			return fmt.Errorf("sink %s: %w", sink.Name(), err)
+		}
+	}
+	return nil
+}
+
+// backoff tăng gấp đôi sau mỗi lần thất bại, bắt đầu từ 1 giây và tối đa maxBackoff
+func (d *Dispatcher) backoff(attempts int) time.Duration {
+	wait := time.Second
+	for i := 1; i < attempts && wait < d.maxBackoff; i++ {
+		wait *= 2
+	}
+	return min(wait, d.maxBackoff)
+}
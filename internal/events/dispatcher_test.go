@@ -0,0 +1,114 @@
+package events_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+
+	"github.com/maithuc2003/Test_GIN_golang/internal/events"
+	"github.com/maithuc2003/Test_GIN_golang/internal/models"
+	"github.com/maithuc2003/Test_GIN_golang/internal/repositories/outbox"
+	sqlitedriver "gorm.io/driver/sqlite"
+
+	_ "modernc.org/sqlite"
+)
+
+func setupTestDB(t *testing.T) *gorm.DB {
+	dsn := fmt.Sprintf("file:testdb_%d?mode=memory&cache=shared", time.Now().UnixNano())
+	db, err := gorm.Open(sqlitedriver.New(sqlitedriver.Config{DSN: dsn, DriverName: "sqlite"}), &gorm.Config{})
+	require.NoError(t, err)
+	require.NoError(t, db.AutoMigrate(&models.OutboxEvent{}))
+	return db
+}
+
+func TestDispatcher_DispatchPending(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("delivers in order once", func(t *testing.T) {
+		db := setupTestDB(t)
+		require.NoError(t, outbox.Append(db,
+			events.OrderCreated{OrderID: 1, BookID: 2, Quantity: 3, Status: models.OrderStatusPending},
+			events.StockChanged{BookID: 2, Delta: -3, Stock: 7, Reason: events.StockReasonOrder},
+		))
+		sink := events.NewMemorySink()
+		dispatcher := events.NewDispatcher(outbox.NewOutboxRepo(db), sink)
+
+		n, err := dispatcher.DispatchPending(ctx)
+		require.NoError(t, err)
+		require.Equal(t, 2, n)
+
+		got := sink.Events()
+		require.Len(t, got, 2)
+		require.Equal(t, events.TypeOrderCreated, got[0].Type)
+		require.Equal(t, events.AggregateOrder, got[0].AggregateType)
+		require.Equal(t, uint(1), got[0].AggregateID)
+		require.Equal(t, events.TypeStockChanged, got[1].Type)
+		var stock events.StockChanged
+		require.NoError(t, json.Unmarshal(got[1].Payload, &stock))
+		require.Equal(t, events.StockChanged{BookID: 2, Delta: -3, Stock: 7, Reason: events.StockReasonOrder}, stock)
+
+		n, err = dispatcher.DispatchPending(ctx)
+		require.NoError(t, err)
+		require.Zero(t, n)
+		require.Len(t, sink.Events(), 2)
+	})
+
+	t.Run("failed delivery is retried later", func(t *testing.T) {
+		db := setupTestDB(t)
+		require.NoError(t, outbox.Append(db, events.BookCreated{BookID: 5, Title: "Dune"}))
+		healthy, flaky := events.NewMemorySink(), events.NewMemorySink()
+		flaky.FailWith(errors.New("connection refused"))
+		dispatcher := events.NewDispatcher(outbox.NewOutboxRepo(db), healthy, flaky)
+
+		n, err := dispatcher.DispatchPending(ctx)
+		require.NoError(t, err)
+		require.Zero(t, n)
+
+		var record models.OutboxEvent
+		require.NoError(t, db.First(&record).Error)
+		require.Nil(t, record.DeliveredAt)
+		require.Equal(t, 1, record.Attempts)
+		require.Contains(t, record.LastError, "sink memory: connection refused")
+		require.True(t, record.NextAttemptAt.After(time.Now()))
+
+		// Chưa tới hạn thử lại
+		n, err = dispatcher.DispatchPending(ctx)
+		require.NoError(t, err)
+		require.Zero(t, n)
+
+		flaky.FailWith(nil)
+		require.NoError(t, db.Model(&record).Update("next_attempt_at", time.Now().Add(-time.Second)).Error)
+		n, err = dispatcher.DispatchPending(ctx)
+		require.NoError(t, err)
+		require.Equal(t, 1, n)
+
+		// Giao ít nhất một lần: sink đã nhận ở lần trước nhận lại cùng event
+		require.Len(t, healthy.Events(), 2)
+		require.Equal(t, healthy.Events()[0].ID, healthy.Events()[1].ID)
+		require.Len(t, flaky.Events(), 1)
+		require.NoError(t, db.First(&record).Error)
+		require.NotNil(t, record.DeliveredAt)
+		require.Empty(t, record.LastError)
+	})
+
+	t.Run("events of a rolled back transaction are never delivered", func(t *testing.T) {
+		db := setupTestDB(t)
+		err := db.Transaction(func(tx *gorm.DB) error {
+			require.NoError(t, outbox.Append(tx, events.AuthorDeleted{AuthorID: 4, Name: "Frank Herbert"}))
+			return errors.New("delete failed")
+		})
+		require.Error(t, err)
+
+		sink := events.NewMemorySink()
+		n, err := events.NewDispatcher(outbox.NewOutboxRepo(db), sink).DispatchPending(ctx)
+		require.NoError(t, err)
+		require.Zero(t, n)
+		require.Empty(t, sink.Events())
+	})
+}
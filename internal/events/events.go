@@ -0,0 +1,139 @@
+// Package events định nghĩa các domain event được ghi vào outbox và dispatcher giao
+// chúng tới các sink.
+package events
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/maithuc2003/Test_GIN_golang/internal/models"
+	"github.com/shopspring/decimal"
+)
+
+// Tên các loại event
+const (
+	TypeOrderCreated       = "OrderCreated"
+	TypeOrderStatusChanged = "OrderStatusChanged"
+	TypeStockChanged       = "StockChanged"
+	TypeBookCreated        = "BookCreated"
+	TypeAuthorDeleted      = "AuthorDeleted"
+)
+
+// Types là danh sách mọi loại event, dùng để kiểm tra đăng ký webhook
+var Types = []string{TypeOrderCreated, TypeOrderStatusChanged, TypeStockChanged, TypeBookCreated, TypeAuthorDeleted}
+
+// Loại đối tượng phát sinh event
+const (
+	AggregateOrder  = "order"
+	AggregateBook   = "book"
+	AggregateAuthor = "author"
+)
+
+// Lý do tồn kho thay đổi trong StockChanged
+const (
+	StockReasonOrder   = "order"
+	StockReasonPayment = "payment"
+	StockReasonRestock = "restock"
+	StockReasonUpdate  = "update"
+)
+
+// Domain là một domain event; payload là chính struct được encode JSON
+type Domain interface {
+	EventType() string
+	Aggregate() (kind string, id uint)
+}
+
+type OrderCreated struct {
+	OrderID    uint            `json:"order_id"`
+	UserID     uint            `json:"user_id"`
+	BookID     uint            `json:"book_id"`
+	Quantity   int             `json:"quantity"`
+	Status     string          `json:"status"`
+	Currency   string          `json:"currency,omitempty"`
+	GrandTotal decimal.Decimal `json:"grand_total"`
+}
+
+func (OrderCreated) EventType() string           { return TypeOrderCreated }
+func (e OrderCreated) Aggregate() (string, uint) { return AggregateOrder, e.OrderID }
+
+type OrderStatusChanged struct {
+	OrderID uint   `json:"order_id"`
+	From    string `json:"from"`
+	To      string `json:"to"`
+}
+
+func (OrderStatusChanged) EventType() string           { return TypeOrderStatusChanged }
+func (e OrderStatusChanged) Aggregate() (string, uint) { return AggregateOrder, e.OrderID }
+
+// StockChanged: Stock là tồn kho sau thay đổi, Delta là lượng thay đổi (âm khi bán)
+type StockChanged struct {
+	BookID uint   `json:"book_id"`
+	Delta  int    `json:"delta"`
+	Stock  int    `json:"stock"`
+	Reason string `json:"reason"`
+}
+
+func (StockChanged) EventType() string           { return TypeStockChanged }
+func (e StockChanged) Aggregate() (string, uint) { return AggregateBook, e.BookID }
+
+type BookCreated struct {
+	BookID   uint    `json:"book_id"`
+	Title    string  `json:"title"`
+	AuthorID int     `json:"author_id"`
+	ISBN     *string `json:"isbn,omitempty"`
+	Stock    int     `json:"stock"`
+}
+
+func (BookCreated) EventType() string           { return TypeBookCreated }
+func (e BookCreated) Aggregate() (string, uint) { return AggregateBook, e.BookID }
+
+// AuthorDeleted: MergedInto khác nil khi tác giả bị xoá do gộp vào tác giả khác
+type AuthorDeleted struct {
+	AuthorID   int    `json:"author_id"`
+	Name       string `json:"name"`
+	MergedInto *int   `json:"merged_into,omitempty"`
+}
+
+func (AuthorDeleted) EventType() string           { return TypeAuthorDeleted }
+func (e AuthorDeleted) Aggregate() (string, uint) { return AggregateAuthor, uint(e.AuthorID) }
+
+// Event là một event trong outbox được giao tới sink. ID không đổi giữa các lần giao
+// lại nên bên nhận dùng nó để bỏ qua event trùng.
+type Event struct {
+	ID            uint            `json:"id"`
+	Type          string          `json:"type"`
+	AggregateType string          `json:"aggregate_type"`
+	AggregateID   uint            `json:"aggregate_id"`
+	OccurredAt    time.Time       `json:"occurred_at"`
+	Payload       json.RawMessage `json:"payload"`
+}
+
+// Record chuyển domain event thành dòng outbox sẵn sàng giao ngay
+func Record(e Domain, now time.Time) (models.OutboxEvent, error) {
+	payload, err := json.Marshal(e)
+	if err != nil {
+		return models.OutboxEvent{}, fmt.Errorf("failed to encode %s event: %w", e.EventType(), err)
+	}
+	kind, id := e.Aggregate()
+	return models.OutboxEvent{
+		Type:          e.EventType(),
+		AggregateType: kind,
+		AggregateID:   id,
+		Payload:       string(payload),
+		OccurredAt:    now,
+		NextAttemptAt: now,
+	}, nil
+}
+
+// FromRecord chuyển dòng outbox thành event giao cho sink
+func FromRecord(r models.OutboxEvent) Event {
+	return Event{
+		ID:            r.ID,
+		Type:          r.Type,
+		AggregateType: r.AggregateType,
+		AggregateID:   r.AggregateID,
+		OccurredAt:    r.OccurredAt,
+		Payload:       json.RawMessage(r.Payload),
+	}
+}
@@ -0,0 +1,59 @@
+package events
+
+import (
+	"context"
+	"log/slog"
+	"sync"
+)
+
+// LogSink ghi event ra log của ứng dụng
+type LogSink struct{}
+
+func (LogSink) Name() string { return "log" }
+
+func (LogSink) Deliver(ctx context.Context, event Event) error {
+	slog.InfoContext(ctx, "event",
+		slog.Uint64("event_id", uint64(event.ID)),
+		slog.String("type", event.Type),
+		slog.String("aggregate_type", event.AggregateType),
+		slog.Uint64("aggregate_id", uint64(event.AggregateID)),
+		slog.Any("payload", event.Payload))
+	return nil
+}
+
+// MemorySink giữ event trong bộ nhớ, dùng cho test
+type MemorySink struct {
+	mu     sync.Mutex
+	events []Event
+	err    error
+}
+
+func NewMemorySink() *MemorySink {
+	return &MemorySink{}
+}
+
+func (s *MemorySink) Name() string { return "memory" }
+
+func (s *MemorySink) Deliver(_ context.Context, event Event) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.err != nil {
+		return s.err
+	}
+	s.events = append(s.events, event)
+	return nil
+}
+
+// Events trả về các event đã nhận theo thứ tự giao
+func (s *MemorySink) Events() []Event {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]Event(nil), s.events...)
+}
+
+// FailWith làm các lần giao sau trả về err (nil để nhận lại bình thường)
+func (s *MemorySink) FailWith(err error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.err = err
+}
@@ -1,11 +1,13 @@
 package author
 
 import (
+	"errors"
 	"net/http"
 	"strconv"
 	"time"
 
 	"github.com/gin-gonic/gin"
+	"github.com/maithuc2003/Test_GIN_golang/internal/interfaces/repositories"
 	"github.com/maithuc2003/Test_GIN_golang/internal/interfaces/service"
 	"github.com/maithuc2003/Test_GIN_golang/internal/models"
 )
@@ -20,7 +22,12 @@ func NewAuthorHandler(serviceAuthor service.AuthorServiceInterface) *AuthorHandl
 
 // GET /authors
 func (h *AuthorHandler) GetAllAuthors(c *gin.Context) {
-	authors, err := h.serviceAuthor.GetAllAuthors()
+	var filter models.AuthorFilter
+	if err := c.ShouldBindQuery(&filter); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid filter parameters"})
+		return
+	}
+	authors, err := h.serviceAuthor.GetAllAuthors(c.Request.Context(), filter)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
@@ -36,7 +43,7 @@ func (h *AuthorHandler) GetByAuthorID(c *gin.Context) {
 		return
 	}
 
-	author, err := h.serviceAuthor.GetByAuthorID(id)
+	author, err := h.serviceAuthor.GetByAuthorID(c.Request.Context(), id)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
@@ -45,19 +52,36 @@ func (h *AuthorHandler) GetByAuthorID(c *gin.Context) {
 }
 
 // POST /authors
+// Body có thể kèm "books" để tạo tác giả và sách trong cùng một transaction
 func (h *AuthorHandler) CreateAuthor(c *gin.Context) {
-	var author models.Author
-	if err := c.ShouldBindJSON(&author); err != nil {
+	var req struct {
+		models.Author
+		Books []*models.Book `json:"books"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
 		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid JSON"})
 		return
 	}
+	author := req.Author
 	author.CreatedAt = time.Now()
 
-	if err := h.serviceAuthor.CreateAuthor(&author); err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	if len(req.Books) == 0 {
+		if err := h.serviceAuthor.CreateAuthor(c.Request.Context(), &author); err != nil {
+			c.JSON(writeErrorStatus(err), gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusCreated, author)
 		return
 	}
-	c.JSON(http.StatusCreated, author)
+
+	if err := h.serviceAuthor.CreateAuthorWithBooks(c.Request.Context(), &author, req.Books); err != nil {
+		c.JSON(writeErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusCreated, gin.H{
+		"author": author,
+		"books":  req.Books,
+	})
 }
 
 // DELETE /authors/:id
@@ -68,7 +92,7 @@ func (h *AuthorHandler) DeleteById(c *gin.Context) {
 		return
 	}
 
-	author, err := h.serviceAuthor.DeleteById(id)
+	author, err := h.serviceAuthor.DeleteById(c.Request.Context(), id)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
@@ -92,10 +116,53 @@ func (h *AuthorHandler) UpdateById(c *gin.Context) {
 	author.ID = id
 	author.UpdatedAt = time.Now()
 
-	updatedAuthor, err := h.serviceAuthor.UpdateById(&author)
+	updatedAuthor, err := h.serviceAuthor.UpdateById(c.Request.Context(), &author)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		c.JSON(writeErrorStatus(err), gin.H{"error": err.Error()})
 		return
 	}
 	c.JSON(http.StatusOK, updatedAuthor)
 }
+
+// POST /authors/:id/merge
+// Body: {"source_ids": [2, 3]} - gộp các tác giả nguồn vào tác giả :id
+func (h *AuthorHandler) MergeAuthors(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid ID"})
+		return
+	}
+
+	var req struct {
+		SourceIDs []int `json:"source_ids" binding:"required,min=1"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "source_ids is required"})
+		return
+	}
+	for _, sourceID := range req.SourceIDs {
+		if sourceID <= 0 || sourceID == id {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid source author ID"})
+			return
+		}
+	}
+
+	result, err := h.serviceAuthor.MergeAuthors(c.Request.Context(), id, req.SourceIDs)
+	if err != nil {
+		if errors.Is(err, repositories.ErrNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(writeErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, result)
+}
+
+// writeErrorStatus trả về 409 khi trùng tên tác giả, còn lại là 500
+func writeErrorStatus(err error) int {
+	if errors.Is(err, repositories.ErrConflict) {
+		return http.StatusConflict
+	}
+	return http.StatusInternalServerError
+}
@@ -4,6 +4,7 @@ import (
 	"bytes"
 	"encoding/json"
 	"errors"
+	"fmt"
 	"net/http"
 	"net/http/httptest"
 	"testing"
@@ -14,6 +15,7 @@ import (
 	"github.com/stretchr/testify/require"
 
 	"github.com/maithuc2003/Test_GIN_golang/internal/handler/author"
+	"github.com/maithuc2003/Test_GIN_golang/internal/interfaces/repositories"
 	mockService "github.com/maithuc2003/Test_GIN_golang/internal/mocks/service"
 	"github.com/maithuc2003/Test_GIN_golang/internal/models"
 )
@@ -46,7 +48,7 @@ func TestGetAllAuthors(t *testing.T) {
 	for _, tc := range tests {
 		t.Run(tc.name, func(t *testing.T) {
 			mockSvc := new(mockService.MockAuthorService)
-			mockSvc.On("GetAllAuthors").Return(tc.mockData, tc.mockErr)
+			mockSvc.On("GetAllAuthors", mock.Anything, mock.Anything).Return(tc.mockData, tc.mockErr)
 
 			r := gin.Default()
 			handler := author.NewAuthorHandler(mockSvc)
@@ -92,6 +94,14 @@ func TestCreateAuthor(t *testing.T) {
 			mockErr:    errors.New("failed to create"),
 			wantStatus: http.StatusInternalServerError,
 		},
+		{
+			name: "Duplicate Name",
+			input: &models.Author{
+				Name: "Nguyễn Nhật Ánh",
+			},
+			mockErr:    fmt.Errorf("failed to create author: %w", repositories.ErrConflict),
+			wantStatus: http.StatusConflict,
+		},
 	}
 
 	for _, tc := range tests {
@@ -109,7 +119,7 @@ func TestCreateAuthor(t *testing.T) {
 				tc.input.CreatedAt = time.Now()
 				body, _ := json.Marshal(tc.input)
 				req, _ = http.NewRequest("POST", "/authors", bytes.NewBuffer(body))
-				mockSvc.On("CreateAuthor", mock.Anything).Return(tc.mockErr)
+				mockSvc.On("CreateAuthor", mock.Anything, mock.Anything).Return(tc.mockErr)
 			}
 
 			req.Header.Set("Content-Type", "application/json")
@@ -122,6 +132,43 @@ func TestCreateAuthor(t *testing.T) {
 	}
 }
 
+func TestCreateAuthorWithBooks(t *testing.T) {
+	tests := []struct {
+		name       string
+		mockErr    error
+		wantStatus int
+	}{
+		{name: "Success", wantStatus: http.StatusCreated},
+		{name: "Create Failed", mockErr: errors.New("rolled back"), wantStatus: http.StatusInternalServerError},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			mockSvc := new(mockService.MockAuthorService)
+			handler := author.NewAuthorHandler(mockSvc)
+
+			r := gin.Default()
+			r.POST("/authors", handler.CreateAuthor)
+
+			body := `{"name":"Nguyen Nhat Anh","books":[{"title":"Mat Biec","stock":3}]}`
+			mockSvc.On("CreateAuthorWithBooks", mock.Anything, mock.MatchedBy(func(a *models.Author) bool {
+				return a.Name == "Nguyen Nhat Anh"
+			}), mock.MatchedBy(func(books []*models.Book) bool {
+				return len(books) == 1 && books[0].Title == "Mat Biec"
+			})).Return(tc.mockErr)
+
+			req, _ := http.NewRequest("POST", "/authors", bytes.NewBufferString(body))
+			req.Header.Set("Content-Type", "application/json")
+			w := httptest.NewRecorder()
+
+			r.ServeHTTP(w, req)
+
+			require.Equal(t, tc.wantStatus, w.Code)
+			mockSvc.AssertNotCalled(t, "CreateAuthor", mock.Anything, mock.Anything)
+		})
+	}
+}
+
 func TestGetByAuthorID(t *testing.T) {
 	type testCase struct {
 		name       string
@@ -159,7 +206,7 @@ func TestGetByAuthorID(t *testing.T) {
 		t.Run(tc.name, func(t *testing.T) {
 			mockSvc := new(mockService.MockAuthorService)
 			if tc.mockData != nil || tc.mockErr != nil {
-				mockSvc.On("GetByAuthorID", mock.Anything).Return(tc.mockData, tc.mockErr)
+				mockSvc.On("GetByAuthorID", mock.Anything, mock.Anything).Return(tc.mockData, tc.mockErr)
 			}
 
 			r := gin.Default()
@@ -211,7 +258,7 @@ func TestDeleteById(t *testing.T) {
 		t.Run(tc.name, func(t *testing.T) {
 			mockSvc := new(mockService.MockAuthorService)
 			if tc.wantStatus != http.StatusBadRequest {
-				mockSvc.On("DeleteById", mock.Anything).Return(tc.mockData, tc.mockErr)
+				mockSvc.On("DeleteById", mock.Anything, mock.Anything).Return(tc.mockData, tc.mockErr)
 			}
 
 			r := gin.Default()
@@ -274,6 +321,14 @@ func TestUpdateById(t *testing.T) {
 			mockErr:    errors.New("update failed"),
 			wantStatus: http.StatusInternalServerError,
 		},
+		{
+			name:       "Duplicate Name",
+			param:      "3",
+			input:      &models.Author{Name: "Tô Hoài"},
+			mockResult: nil,
+			mockErr:    fmt.Errorf("failed to update author : %w", repositories.ErrConflict),
+			wantStatus: http.StatusConflict,
+		},
 	}
 
 	for _, tc := range tests {
@@ -293,7 +348,7 @@ func TestUpdateById(t *testing.T) {
 				req, _ = http.NewRequest("PUT", "/authors/"+tc.param, bytes.NewBuffer(body))
 
 				if tc.wantStatus != http.StatusBadRequest {
-					mockSvc.On("UpdateById", mock.Anything).Return(tc.mockResult, tc.mockErr)
+					mockSvc.On("UpdateById", mock.Anything, mock.Anything).Return(tc.mockResult, tc.mockErr)
 				}
 			} else {
 				req, _ = http.NewRequest("PUT", "/authors/"+tc.param, nil)
@@ -307,3 +362,79 @@ func TestUpdateById(t *testing.T) {
 		})
 	}
 }
+
+func TestMergeAuthors(t *testing.T) {
+	tests := []struct {
+		name       string
+		param      string
+		body       string
+		mockResult *models.AuthorMergeResult
+		mockErr    error
+		callsSvc   bool
+		wantStatus int
+	}{
+		{
+			name:       "Success",
+			param:      "1",
+			body:       `{"source_ids":[2,3]}`,
+			mockResult: &models.AuthorMergeResult{Author: &models.Author{ID: 1}, MergedIDs: []int{2, 3}},
+			callsSvc:   true,
+			wantStatus: http.StatusOK,
+		},
+		{
+			name:       "Invalid ID Param",
+			param:      "abc",
+			body:       `{"source_ids":[2]}`,
+			wantStatus: http.StatusBadRequest,
+		},
+		{
+			name:       "Missing Sources",
+			param:      "1",
+			body:       `{"source_ids":[]}`,
+			wantStatus: http.StatusBadRequest,
+		},
+		{
+			name:       "Merge Into Itself",
+			param:      "1",
+			body:       `{"source_ids":[1]}`,
+			wantStatus: http.StatusBadRequest,
+		},
+		{
+			name:       "Author Not Found",
+			param:      "1",
+			body:       `{"source_ids":[99]}`,
+			mockErr:    fmt.Errorf("failed to merge authors: %w", repositories.ErrNotFound),
+			callsSvc:   true,
+			wantStatus: http.StatusNotFound,
+		},
+		{
+			name:       "Service Error",
+			param:      "1",
+			body:       `{"source_ids":[2]}`,
+			mockErr:    errors.New("db down"),
+			callsSvc:   true,
+			wantStatus: http.StatusInternalServerError,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			mockSvc := new(mockService.MockAuthorService)
+			handler := author.NewAuthorHandler(mockSvc)
+			if tc.callsSvc {
+				mockSvc.On("MergeAuthors", mock.Anything, 1, mock.AnythingOfType("[]int")).Return(tc.mockResult, tc.mockErr)
+			}
+
+			r := gin.Default()
+			r.POST("/authors/:id/merge", handler.MergeAuthors)
+
+			req, _ := http.NewRequest("POST", "/authors/"+tc.param+"/merge", bytes.NewBufferString(tc.body))
+			req.Header.Set("Content-Type", "application/json")
+			w := httptest.NewRecorder()
+			r.ServeHTTP(w, req)
+
+			require.Equal(t, tc.wantStatus, w.Code)
+			mockSvc.AssertExpectations(t)
+		})
+	}
+}
@@ -1,10 +1,12 @@
 package book
 
 import (
+	"errors"
 	"net/http"
 	"strconv"
 	"time"
 
+	"github.com/maithuc2003/Test_GIN_golang/internal/interfaces/repositories"
 	"github.com/maithuc2003/Test_GIN_golang/internal/interfaces/service"
 	"github.com/maithuc2003/Test_GIN_golang/internal/models"
 
@@ -27,20 +29,39 @@ func (h *BookHandler) CreateBookHandler(c *gin.Context) {
 		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 		return
 	}
-	err := h.bookService.CreateBook(&book)
+	err := h.bookService.CreateBook(c.Request.Context(), &book)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create book"})
+		writeBookError(c, err, "Failed to create book")
+		return
 	}
 	c.JSON(http.StatusCreated, book)
 }
 
 // Get/books
 func (h *BookHandler) GetAllBooksHandler(c *gin.Context) {
-	books, err := h.bookService.GetAllBooks()
+	var filter models.BookFilter
+	if err := c.ShouldBindQuery(&filter); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid filter parameters"})
+		return
+	}
+	books, err := h.bookService.GetAllBooks(c.Request.Context(), filter)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch books"})
 		return
 	}
+	// facets=categories: trả dạng {books, facets} thay vì mảng sách
+	if filter.HasFacet("categories") {
+		facets, err := h.bookService.GetCategoryFacets(c.Request.Context(), filter)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch category facets"})
+			return
+		}
+		c.IndentedJSON(http.StatusOK, models.BookListResponse{
+			Books:  books,
+			Facets: models.BookListFacets{Categories: facets},
+		})
+		return
+	}
 	// c.JSON(http.StatusOK, books)
 	c.IndentedJSON(http.StatusOK, books)
 
@@ -54,7 +75,12 @@ func (h *BookHandler) GetByBookID(c *gin.Context) {
 		return
 	}
 
-	book, err := h.bookService.GetByBookID(id)
+	var book *models.Book
+	if (models.BookFilter{Include: c.Query("include")}).Includes("author") {
+		book, err = h.bookService.GetByBookIDWithAuthors(c.Request.Context(), id)
+	} else {
+		book, err = h.bookService.GetByBookID(c.Request.Context(), id)
+	}
 	if err != nil {
 		c.JSON(http.StatusNotFound, gin.H{"error": "Book not found"})
 		return
@@ -62,6 +88,52 @@ func (h *BookHandler) GetByBookID(c *gin.Context) {
 	c.JSON(http.StatusOK, book)
 }
 
+// GET /authors/:id/books
+func (h *BookHandler) GetBooksByAuthor(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid author ID"})
+		return
+	}
+	var filter models.BookFilter
+	if err := c.ShouldBindQuery(&filter); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid filter parameters"})
+		return
+	}
+
+	books, err := h.bookService.GetBooksByAuthor(c.Request.Context(), id, filter)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, books)
+}
+
+// GET /categories/:id/books
+func (h *BookHandler) GetBooksByCategory(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil || id == 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid category ID"})
+		return
+	}
+	var filter models.BookFilter
+	if err := c.ShouldBindQuery(&filter); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid filter parameters"})
+		return
+	}
+
+	books, err := h.bookService.GetBooksByCategory(c.Request.Context(), uint(id), filter)
+	if err != nil {
+		if errors.Is(err, repositories.ErrNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch books"})
+		return
+	}
+	c.JSON(http.StatusOK, books)
+}
+
 // DELETE /books/:id
 func (h *BookHandler) DeleteById(c *gin.Context) {
 	id, err := strconv.Atoi(c.Param("id"))
@@ -70,7 +142,7 @@ func (h *BookHandler) DeleteById(c *gin.Context) {
 		return
 	}
 
-	book, err := h.bookService.DeleteById(id)
+	book, err := h.bookService.DeleteById(c.Request.Context(), id)
 	if err != nil {
 		c.JSON(http.StatusNotFound, gin.H{"error": "Delete failed or book not found"})
 		return
@@ -95,10 +167,22 @@ func (h *BookHandler) UpdateById(c *gin.Context) {
 	updateBook.ID = uint(id)
 	updateBook.UpdatedAt = time.Now()
 
-	book, err := h.bookService.UpdateById(&updateBook)
+	book, err := h.bookService.UpdateById(c.Request.Context(), &updateBook)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update book"})
+		writeBookError(c, err, "Failed to update book")
 		return
 	}
 	c.JSON(http.StatusOK, book)
 }
+
+// writeBookError trả 400 cho dữ liệu không hợp lệ, 409 khi trùng ISBN, còn lại 500
+func writeBookError(c *gin.Context, err error, fallback string) {
+	switch {
+	case errors.Is(err, service.ErrInvalidInput):
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	case errors.Is(err, repositories.ErrConflict):
+		c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+	default:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": fallback})
+	}
+}
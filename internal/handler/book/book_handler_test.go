@@ -4,12 +4,15 @@ import (
 	"bytes"
 	"encoding/json"
 	"errors"
+	"fmt"
 	"net/http"
 	"net/http/httptest"
 	"testing"
 
 	"github.com/gin-gonic/gin"
 	"github.com/maithuc2003/Test_GIN_golang/internal/handler/book"
+	"github.com/maithuc2003/Test_GIN_golang/internal/interfaces/repositories"
+	"github.com/maithuc2003/Test_GIN_golang/internal/interfaces/service"
 	mocks "github.com/maithuc2003/Test_GIN_golang/internal/mocks/service"
 	"github.com/maithuc2003/Test_GIN_golang/internal/models"
 	"github.com/stretchr/testify/mock"
@@ -51,6 +54,24 @@ func TestCreateBookHandler(t *testing.T) {
 			mockReturnErr:  errors.New("failed to create"),
 			expectedStatus: http.StatusInternalServerError,
 		},
+		{
+			name: "invalid metadata",
+			inputBody: models.Book{
+				Title:    "Book 3",
+				AuthorID: 1,
+			},
+			mockReturnErr:  fmt.Errorf("%w: isbn checksum is invalid", service.ErrInvalidInput),
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name: "duplicate isbn",
+			inputBody: models.Book{
+				Title:    "Book 4",
+				AuthorID: 1,
+			},
+			mockReturnErr:  fmt.Errorf("%w: book with ISBN 9780306406157 already exists", repositories.ErrConflict),
+			expectedStatus: http.StatusConflict,
+		},
 	}
 
 	for _, tt := range tests {
@@ -64,7 +85,7 @@ func TestCreateBookHandler(t *testing.T) {
 				reqBody = []byte(v)
 			default:
 				reqBody, _ = json.Marshal(v)
-				mockService.On("CreateBook", mock.AnythingOfType("*models.Book")).Return(tt.mockReturnErr)
+				mockService.On("CreateBook", mock.Anything, mock.AnythingOfType("*models.Book")).Return(tt.mockReturnErr)
 			}
 
 			req := httptest.NewRequest(http.MethodPost, "/books", bytes.NewBuffer(reqBody))
@@ -112,7 +133,7 @@ func TestGetAllBooksHandler(t *testing.T) {
 			mockService := new(mocks.MockBookService)
 			h := book.NewBookHandler(mockService)
 
-			mockService.On("GetAllBooks").Return(tt.mockBooks, tt.mockReturnErr)
+			mockService.On("GetAllBooks", mock.Anything, mock.Anything).Return(tt.mockBooks, tt.mockReturnErr)
 
 			req := httptest.NewRequest(http.MethodGet, "/books", nil)
 			rec := httptest.NewRecorder()
@@ -134,6 +155,105 @@ func TestGetAllBooksHandler(t *testing.T) {
 	}
 }
 
+func TestGetAllBooksHandler_CategoryFacets(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	filter := models.BookFilter{CategoryID: 1, Facets: "categories"}
+	books := []models.Book{{ID: 1, Title: "The Hobbit", AuthorID: 1}}
+	facets := []models.CategoryFacet{{CategoryID: 1, Name: "Fiction", Count: 1}}
+
+	tests := []struct {
+		name           string
+		facetErr       error
+		expectedStatus int
+	}{
+		{name: "success", expectedStatus: http.StatusOK},
+		{name: "facet error", facetErr: errors.New("db down"), expectedStatus: http.StatusInternalServerError},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockService := new(mocks.MockBookService)
+			h := book.NewBookHandler(mockService)
+			mockService.On("GetAllBooks", mock.Anything, filter).Return(books, nil)
+			mockService.On("GetCategoryFacets", mock.Anything, filter).Return(facets, tt.facetErr)
+
+			req := httptest.NewRequest(http.MethodGet, "/books?category_id=1&facets=categories", nil)
+			rec := httptest.NewRecorder()
+
+			r := gin.Default()
+			r.GET("/books", h.GetAllBooksHandler)
+			r.ServeHTTP(rec, req)
+
+			require.Equal(t, tt.expectedStatus, rec.Code)
+			mockService.AssertExpectations(t)
+			if tt.expectedStatus == http.StatusOK {
+				var got models.BookListResponse
+				require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &got))
+				require.Equal(t, books, got.Books)
+				require.Equal(t, facets, got.Facets.Categories)
+			}
+		})
+	}
+}
+
+func TestGetBooksByCategoryHandler(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	tests := []struct {
+		name           string
+		path           string
+		expectFilter   *models.BookFilter
+		mockBooks      []models.Book
+		mockReturnErr  error
+		expectedStatus int
+	}{
+		{
+			name:           "success",
+			path:           "/categories/2/books?in_stock=true",
+			expectFilter:   &models.BookFilter{InStock: func() *bool { b := true; return &b }()},
+			mockBooks:      []models.Book{{ID: 1, Title: "The Hobbit", AuthorID: 1}},
+			expectedStatus: http.StatusOK,
+		},
+		{name: "invalid ID", path: "/categories/abc/books", expectedStatus: http.StatusBadRequest},
+		{
+			name:           "category not found",
+			path:           "/categories/99/books",
+			expectFilter:   &models.BookFilter{},
+			mockReturnErr:  fmt.Errorf("%w: category with ID 99 not found", repositories.ErrNotFound),
+			expectedStatus: http.StatusNotFound,
+		},
+		{
+			name:           "service error",
+			path:           "/categories/3/books",
+			expectFilter:   &models.BookFilter{},
+			mockReturnErr:  errors.New("db down"),
+			expectedStatus: http.StatusInternalServerError,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockService := new(mocks.MockBookService)
+			h := book.NewBookHandler(mockService)
+			if tt.expectFilter != nil {
+				mockService.On("GetBooksByCategory", mock.Anything, mock.AnythingOfType("uint"), *tt.expectFilter).
+					Return(tt.mockBooks, tt.mockReturnErr)
+			}
+
+			req := httptest.NewRequest(http.MethodGet, tt.path, nil)
+			rec := httptest.NewRecorder()
+
+			r := gin.Default()
+			r.GET("/categories/:id/books", h.GetBooksByCategory)
+			r.ServeHTTP(rec, req)
+
+			require.Equal(t, tt.expectedStatus, rec.Code)
+			mockService.AssertExpectations(t)
+		})
+	}
+}
+
 func TestGetByBookIDHandler(t *testing.T) {
 	gin.SetMode(gin.TestMode)
 
@@ -170,7 +290,7 @@ func TestGetByBookIDHandler(t *testing.T) {
 			h := book.NewBookHandler(mockService)
 
 			if tt.expectedStatus != http.StatusBadRequest {
-				mockService.On("GetByBookID", mock.AnythingOfType("int")).Return(tt.mockBook, tt.mockReturnErr)
+				mockService.On("GetByBookID", mock.Anything, mock.AnythingOfType("int")).Return(tt.mockBook, tt.mockReturnErr)
 			}
 
 			req := httptest.NewRequest(http.MethodGet, "/books/"+tt.paramID, nil)
@@ -185,6 +305,80 @@ func TestGetByBookIDHandler(t *testing.T) {
 		})
 	}
 }
+func TestGetByBookIDHandler_IncludeAuthor(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	mockService := new(mocks.MockBookService)
+	h := book.NewBookHandler(mockService)
+	withAuthor := &models.Book{ID: 1, Title: "Good Omens", AuthorID: 1, Author: &models.Author{ID: 1, Name: "Neil Gaiman"}}
+	mockService.On("GetByBookIDWithAuthors", mock.Anything, 1).Return(withAuthor, nil)
+
+	req := httptest.NewRequest(http.MethodGet, "/books/1?include=author", nil)
+	rec := httptest.NewRecorder()
+
+	r := gin.Default()
+	r.GET("/books/:id", h.GetByBookID)
+	r.ServeHTTP(rec, req)
+
+	require.Equal(t, http.StatusOK, rec.Code)
+	require.Contains(t, rec.Body.String(), `"author":{"id":1,"name":"Neil Gaiman"`)
+	mockService.AssertNotCalled(t, "GetByBookID", mock.Anything, mock.Anything)
+}
+
+func TestGetBooksByAuthorHandler(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	tests := []struct {
+		name           string
+		path           string
+		expectFilter   *models.BookFilter
+		mockBooks      []models.Book
+		mockReturnErr  error
+		expectedStatus int
+	}{
+		{
+			name:           "success with include",
+			path:           "/authors/2/books?include=author",
+			expectFilter:   &models.BookFilter{Include: "author"},
+			mockBooks:      []models.Book{{ID: 1, Title: "Good Omens", AuthorID: 1}},
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:           "invalid ID",
+			path:           "/authors/abc/books",
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:           "author not found",
+			path:           "/authors/99/books",
+			expectFilter:   &models.BookFilter{},
+			mockReturnErr:  errors.New("author with ID 99 not found"),
+			expectedStatus: http.StatusNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockService := new(mocks.MockBookService)
+			h := book.NewBookHandler(mockService)
+			if tt.expectFilter != nil {
+				mockService.On("GetBooksByAuthor", mock.Anything, mock.AnythingOfType("int"), *tt.expectFilter).
+					Return(tt.mockBooks, tt.mockReturnErr)
+			}
+
+			req := httptest.NewRequest(http.MethodGet, tt.path, nil)
+			rec := httptest.NewRecorder()
+
+			r := gin.Default()
+			r.GET("/authors/:id/books", h.GetBooksByAuthor)
+			r.ServeHTTP(rec, req)
+
+			require.Equal(t, tt.expectedStatus, rec.Code)
+			mockService.AssertExpectations(t)
+		})
+	}
+}
+
 func TestUpdateBookHandler(t *testing.T) {
 	gin.SetMode(gin.TestMode)
 
@@ -245,7 +439,7 @@ func TestUpdateBookHandler(t *testing.T) {
 				reqBody = nil
 			default:
 				reqBody, _ = json.Marshal(v)
-				mockService.On("UpdateById", mock.AnythingOfType("*models.Book")).Return(tt.mockReturnBook, tt.mockReturnErr)
+				mockService.On("UpdateById", mock.Anything, mock.AnythingOfType("*models.Book")).Return(tt.mockReturnBook, tt.mockReturnErr)
 			}
 
 			req := httptest.NewRequest(http.MethodPut, "/books/"+tt.paramID, bytes.NewBuffer(reqBody))
@@ -297,7 +491,7 @@ func TestDeleteBookHandler(t *testing.T) {
 			h := book.NewBookHandler(mockService)
 
 			if tt.expectedStatus != http.StatusBadRequest {
-				mockService.On("DeleteById", mock.AnythingOfType("int")).Return(tt.mockReturnBook, tt.mockReturnErr)
+				mockService.On("DeleteById", mock.Anything, mock.AnythingOfType("int")).Return(tt.mockReturnBook, tt.mockReturnErr)
 			}
 
 			req := httptest.NewRequest(http.MethodDelete, "/books/"+tt.paramID, nil)
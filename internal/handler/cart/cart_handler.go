@@ -0,0 +1,145 @@
+package cart
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/maithuc2003/Test_GIN_golang/internal/interfaces/repositories"
+	"github.com/maithuc2003/Test_GIN_golang/internal/interfaces/service"
+	"github.com/maithuc2003/Test_GIN_golang/internal/models"
+)
+
+type CartHandler struct {
+	cartService service.CartServiceInterface
+}
+
+func NewCartHandler(cartService service.CartServiceInterface) *CartHandler {
+	return &CartHandler{cartService: cartService}
+}
+
+// GET /cart
+func (h *CartHandler) GetCart(c *gin.Context) {
+	userID, ok := currentUser(c)
+	if !ok {
+		return
+	}
+	cart, err := h.cartService.GetCart(c.Request.Context(), userID)
+	if err != nil {
+		writeError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, cart)
+}
+
+// POST /cart/items
+func (h *CartHandler) AddItem(c *gin.Context) {
+	userID, ok := currentUser(c)
+	if !ok {
+		return
+	}
+	var req models.CartItemRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request body"})
+		return
+	}
+	cart, err := h.cartService.AddItem(c.Request.Context(), userID, req)
+	if err != nil {
+		writeError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, cart)
+}
+
+// PUT /cart/items/:book_id
+func (h *CartHandler) UpdateItem(c *gin.Context) {
+	userID, ok := currentUser(c)
+	if !ok {
+		return
+	}
+	bookID, ok := bookIDParam(c)
+	if !ok {
+		return
+	}
+	var req models.CartItemRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request body"})
+		return
+	}
+	req.BookID = bookID
+
+	cart, err := h.cartService.UpdateItem(c.Request.Context(), userID, req)
+	if err != nil {
+		writeError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, cart)
+}
+
+// DELETE /cart/items/:book_id
+func (h *CartHandler) RemoveItem(c *gin.Context) {
+	userID, ok := currentUser(c)
+	if !ok {
+		return
+	}
+	bookID, ok := bookIDParam(c)
+	if !ok {
+		return
+	}
+	cart, err := h.cartService.RemoveItem(c.Request.Context(), userID, bookID)
+	if err != nil {
+		writeError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, cart)
+}
+
+// POST /cart/checkout
+func (h *CartHandler) Checkout(c *gin.Context) {
+	userID, ok := currentUser(c)
+	if !ok {
+		return
+	}
+	orders, err := h.cartService.Checkout(c.Request.Context(), userID)
+	if err != nil {
+		writeError(c, err)
+		return
+	}
+	c.JSON(http.StatusCreated, gin.H{
+		"message": "Order created successfully",
+		"orders":  orders,
+	})
+}
+
+// currentUser lấy user_id do AuthMiddleware gán vào context
+func currentUser(c *gin.Context) (uint, bool) {
+	userID := c.GetInt("user_id")
+	if userID <= 0 {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "User ID not found"})
+		return 0, false
+	}
+	return uint(userID), true
+}
+
+func bookIDParam(c *gin.Context) (uint, bool) {
+	id, err := strconv.ParseUint(c.Param("book_id"), 10, 64)
+	if err != nil || id == 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid book ID"})
+		return 0, false
+	}
+	return uint(id), true
+}
+
+func writeError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, service.ErrInvalidInput):
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	case errors.Is(err, repositories.ErrNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+	case errors.Is(err, repositories.ErrInsufficientStock):
+		c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+	default:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Cart operation failed"})
+	}
+}
@@ -0,0 +1,150 @@
+package cart_test
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/maithuc2003/Test_GIN_golang/internal/handler/cart"
+	"github.com/maithuc2003/Test_GIN_golang/internal/interfaces/repositories"
+	"github.com/maithuc2003/Test_GIN_golang/internal/interfaces/service"
+	mocks "github.com/maithuc2003/Test_GIN_golang/internal/mocks/service"
+	"github.com/maithuc2003/Test_GIN_golang/internal/models"
+)
+
+// withUser giả lập AuthMiddleware gán user_id vào context
+func withUser(userID int) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if userID > 0 {
+			c.Set("user_id", userID)
+		}
+		c.Next()
+	}
+}
+
+func TestAddItem(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	tests := []struct {
+		name           string
+		userID         int
+		body           string
+		expectReq      *models.CartItemRequest
+		mockErr        error
+		expectedStatus int
+	}{
+		{name: "success", userID: 7, body: `{"book_id":1,"quantity":2}`, expectReq: &models.CartItemRequest{BookID: 1, Quantity: 2}, expectedStatus: http.StatusOK},
+		{name: "no user", body: `{"book_id":1,"quantity":2}`, expectedStatus: http.StatusUnauthorized},
+		{name: "invalid JSON", userID: 7, body: `{"book_id":`, expectedStatus: http.StatusBadRequest},
+		{
+			name: "invalid quantity", userID: 7, body: `{"book_id":1,"quantity":0}`,
+			expectReq: &models.CartItemRequest{BookID: 1}, mockErr: fmt.Errorf("%w: quantity", service.ErrInvalidInput),
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name: "book not found", userID: 7, body: `{"book_id":9,"quantity":1}`,
+			expectReq: &models.CartItemRequest{BookID: 9, Quantity: 1}, mockErr: fmt.Errorf("book with ID 9 %w", repositories.ErrNotFound),
+			expectedStatus: http.StatusNotFound,
+		},
+		{
+			name: "out of stock", userID: 7, body: `{"book_id":1,"quantity":50}`,
+			expectReq: &models.CartItemRequest{BookID: 1, Quantity: 50}, mockErr: fmt.Errorf("%w: only 2 left", repositories.ErrInsufficientStock),
+			expectedStatus: http.StatusConflict,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockSvc := new(mocks.MockCartService)
+			h := cart.NewCartHandler(mockSvc)
+			if tt.expectReq != nil {
+				mockSvc.On("AddItem", mock.Anything, uint(tt.userID), *tt.expectReq).Return(&models.CartView{UserID: uint(tt.userID)}, tt.mockErr)
+			}
+
+			req := httptest.NewRequest(http.MethodPost, "/cart/items", bytes.NewBufferString(tt.body))
+			req.Header.Set("Content-Type", "application/json")
+			rec := httptest.NewRecorder()
+
+			r := gin.Default()
+			r.POST("/cart/items", withUser(tt.userID), h.AddItem)
+			r.ServeHTTP(rec, req)
+
+			require.Equal(t, tt.expectedStatus, rec.Code)
+			mockSvc.AssertExpectations(t)
+		})
+	}
+}
+
+func TestUpdateAndRemoveItem(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	mockSvc := new(mocks.MockCartService)
+	h := cart.NewCartHandler(mockSvc)
+	mockSvc.On("UpdateItem", mock.Anything, uint(7), models.CartItemRequest{BookID: 3, Quantity: 4}).Return(&models.CartView{}, nil)
+	mockSvc.On("RemoveItem", mock.Anything, uint(7), uint(3)).Return(&models.CartView{}, nil)
+
+	r := gin.Default()
+	r.PUT("/cart/items/:book_id", withUser(7), h.UpdateItem)
+	r.DELETE("/cart/items/:book_id", withUser(7), h.RemoveItem)
+
+	tests := []struct {
+		name           string
+		method         string
+		path           string
+		body           string
+		expectedStatus int
+	}{
+		{name: "update", method: http.MethodPut, path: "/cart/items/3", body: `{"quantity":4}`, expectedStatus: http.StatusOK},
+		{name: "update invalid book ID", method: http.MethodPut, path: "/cart/items/x", body: `{"quantity":4}`, expectedStatus: http.StatusBadRequest},
+		{name: "remove", method: http.MethodDelete, path: "/cart/items/3", expectedStatus: http.StatusOK},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(tt.method, tt.path, bytes.NewBufferString(tt.body))
+			req.Header.Set("Content-Type", "application/json")
+			rec := httptest.NewRecorder()
+			r.ServeHTTP(rec, req)
+			require.Equal(t, tt.expectedStatus, rec.Code)
+		})
+	}
+	mockSvc.AssertExpectations(t)
+}
+
+func TestCheckout(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	tests := []struct {
+		name           string
+		mockOrders     []*models.Order
+		mockErr        error
+		expectedStatus int
+	}{
+		{name: "success", mockOrders: []*models.Order{{ID: 1, BookID: 1, Quantity: 2, Status: "pending"}}, expectedStatus: http.StatusCreated},
+		{name: "empty cart", mockErr: fmt.Errorf("%w: cart is empty", service.ErrInvalidInput), expectedStatus: http.StatusBadRequest},
+		{name: "stock changed", mockErr: fmt.Errorf("checkout failed: book 1: %w", repositories.ErrInsufficientStock), expectedStatus: http.StatusConflict},
+		{name: "db error", mockErr: errors.New("checkout failed: db down"), expectedStatus: http.StatusInternalServerError},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockSvc := new(mocks.MockCartService)
+			h := cart.NewCartHandler(mockSvc)
+			mockSvc.On("Checkout", mock.Anything, uint(7)).Return(tt.mockOrders, tt.mockErr)
+
+			rec := httptest.NewRecorder()
+			r := gin.Default()
+			r.POST("/cart/checkout", withUser(7), h.Checkout)
+			r.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/cart/checkout", nil))
+
+			require.Equal(t, tt.expectedStatus, rec.Code)
+			mockSvc.AssertExpectations(t)
+		})
+	}
+}
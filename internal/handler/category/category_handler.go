@@ -0,0 +1,115 @@
+package category
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/maithuc2003/Test_GIN_golang/internal/interfaces/repositories"
+	"github.com/maithuc2003/Test_GIN_golang/internal/interfaces/service"
+	"github.com/maithuc2003/Test_GIN_golang/internal/models"
+)
+
+type CategoryHandler struct {
+	service service.CategoryServiceInterface
+}
+
+func NewCategoryHandler(service service.CategoryServiceInterface) *CategoryHandler {
+	return &CategoryHandler{service: service}
+}
+
+// GET /categories - trả về cây thể loại
+func (h *CategoryHandler) GetCategoryTree(c *gin.Context) {
+	tree, err := h.service.GetCategoryTree(c.Request.Context())
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, tree)
+}
+
+// GET /categories/:id
+func (h *CategoryHandler) GetByCategoryID(c *gin.Context) {
+	id, ok := parseID(c)
+	if !ok {
+		return
+	}
+	category, err := h.service.GetByCategoryID(c.Request.Context(), id)
+	if err != nil {
+		c.JSON(errorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, category)
+}
+
+// POST /categories/add
+func (h *CategoryHandler) CreateCategory(c *gin.Context) {
+	var category models.Category
+	if err := c.ShouldBindJSON(&category); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid JSON"})
+		return
+	}
+	if err := h.service.CreateCategory(c.Request.Context(), &category); err != nil {
+		c.JSON(errorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusCreated, category)
+}
+
+// PUT /categories/:id
+func (h *CategoryHandler) UpdateById(c *gin.Context) {
+	id, ok := parseID(c)
+	if !ok {
+		return
+	}
+	var category models.Category
+	if err := c.ShouldBindJSON(&category); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid JSON"})
+		return
+	}
+	category.ID = id
+
+	updated, err := h.service.UpdateById(c.Request.Context(), &category)
+	if err != nil {
+		c.JSON(errorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, updated)
+}
+
+// DELETE /categories/:id
+func (h *CategoryHandler) DeleteById(c *gin.Context) {
+	id, ok := parseID(c)
+	if !ok {
+		return
+	}
+	deleted, err := h.service.DeleteById(c.Request.Context(), id)
+	if err != nil {
+		c.JSON(errorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, deleted)
+}
+
+func parseID(c *gin.Context) (uint, bool) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil || id == 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid ID"})
+		return 0, false
+	}
+	return uint(id), true
+}
+
+func errorStatus(err error) int {
+	switch {
+	case errors.Is(err, service.ErrInvalidInput):
+		return http.StatusBadRequest
+	case errors.Is(err, repositories.ErrNotFound):
+		return http.StatusNotFound
+	case errors.Is(err, repositories.ErrConflict):
+		return http.StatusConflict
+	default:
+		return http.StatusInternalServerError
+	}
+}
@@ -0,0 +1,115 @@
+package category_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/maithuc2003/Test_GIN_golang/internal/handler/category"
+	"github.com/maithuc2003/Test_GIN_golang/internal/interfaces/repositories"
+	"github.com/maithuc2003/Test_GIN_golang/internal/interfaces/service"
+	mocks "github.com/maithuc2003/Test_GIN_golang/internal/mocks/service"
+	"github.com/maithuc2003/Test_GIN_golang/internal/models"
+)
+
+func TestGetCategoryTree(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	mockSvc := new(mocks.MockCategoryService)
+	h := category.NewCategoryHandler(mockSvc)
+	tree := []models.Category{{ID: 1, Name: "Fiction", Children: []models.Category{{ID: 2, Name: "Fantasy"}}}}
+	mockSvc.On("GetCategoryTree", mock.Anything).Return(tree, nil)
+
+	rec := httptest.NewRecorder()
+	r := gin.Default()
+	r.GET("/categories", h.GetCategoryTree)
+	r.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/categories", nil))
+
+	require.Equal(t, http.StatusOK, rec.Code)
+	var got []models.Category
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &got))
+	require.Equal(t, "Fantasy", got[0].Children[0].Name)
+}
+
+func TestCreateCategory(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	tests := []struct {
+		name       string
+		body       string
+		mockErr    error
+		callsSvc   bool
+		wantStatus int
+	}{
+		{name: "success", body: `{"name":"Fantasy","parent_id":1}`, callsSvc: true, wantStatus: http.StatusCreated},
+		{name: "invalid JSON", body: `{"name":`, wantStatus: http.StatusBadRequest},
+		{name: "empty name", body: `{"name":""}`, mockErr: fmt.Errorf("%w: empty name", service.ErrInvalidInput), callsSvc: true, wantStatus: http.StatusBadRequest},
+		{name: "parent not found", body: `{"name":"Fantasy","parent_id":9}`, mockErr: fmt.Errorf("%w: parent", repositories.ErrNotFound), callsSvc: true, wantStatus: http.StatusNotFound},
+		{name: "duplicate", body: `{"name":"Fantasy"}`, mockErr: fmt.Errorf("%w: exists", repositories.ErrConflict), callsSvc: true, wantStatus: http.StatusConflict},
+		{name: "service error", body: `{"name":"Fantasy"}`, mockErr: errors.New("db down"), callsSvc: true, wantStatus: http.StatusInternalServerError},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockSvc := new(mocks.MockCategoryService)
+			h := category.NewCategoryHandler(mockSvc)
+			if tt.callsSvc {
+				mockSvc.On("CreateCategory", mock.Anything, mock.AnythingOfType("*models.Category")).Return(tt.mockErr)
+			}
+
+			req := httptest.NewRequest(http.MethodPost, "/categories/add", bytes.NewBufferString(tt.body))
+			req.Header.Set("Content-Type", "application/json")
+			rec := httptest.NewRecorder()
+
+			r := gin.Default()
+			r.POST("/categories/add", h.CreateCategory)
+			r.ServeHTTP(rec, req)
+
+			require.Equal(t, tt.wantStatus, rec.Code)
+			mockSvc.AssertExpectations(t)
+		})
+	}
+}
+
+func TestDeleteCategory(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	tests := []struct {
+		name       string
+		param      string
+		mockResult *models.Category
+		mockErr    error
+		wantStatus int
+	}{
+		{name: "deleted", param: "2", mockResult: &models.Category{ID: 2, Name: "Fantasy"}, wantStatus: http.StatusOK},
+		{name: "invalid ID", param: "abc", wantStatus: http.StatusBadRequest},
+		{name: "not found", param: "9", mockErr: fmt.Errorf("%w: missing", repositories.ErrNotFound), wantStatus: http.StatusNotFound},
+		{name: "has children", param: "1", mockErr: fmt.Errorf("%w: has subcategories", repositories.ErrConflict), wantStatus: http.StatusConflict},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockSvc := new(mocks.MockCategoryService)
+			h := category.NewCategoryHandler(mockSvc)
+			if tt.wantStatus != http.StatusBadRequest {
+				mockSvc.On("DeleteById", mock.Anything, mock.AnythingOfType("uint")).Return(tt.mockResult, tt.mockErr)
+			}
+
+			rec := httptest.NewRecorder()
+			r := gin.Default()
+			r.DELETE("/categories/:id", h.DeleteById)
+			r.ServeHTTP(rec, httptest.NewRequest(http.MethodDelete, "/categories/"+tt.param, nil))
+
+			require.Equal(t, tt.wantStatus, rec.Code)
+			mockSvc.AssertExpectations(t)
+		})
+	}
+}
@@ -0,0 +1,119 @@
+package coupon
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/maithuc2003/Test_GIN_golang/internal/interfaces/repositories"
+	"github.com/maithuc2003/Test_GIN_golang/internal/interfaces/service"
+	"github.com/maithuc2003/Test_GIN_golang/internal/models"
+)
+
+type CouponHandler struct {
+	service service.CouponServiceInterface
+}
+
+func NewCouponHandler(service service.CouponServiceInterface) *CouponHandler {
+	return &CouponHandler{service: service}
+}
+
+// GET /coupons
+func (h *CouponHandler) GetAllCoupons(c *gin.Context) {
+	coupons, err := h.service.GetAllCoupons(c.Request.Context())
+	if err != nil {
+		writeError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, coupons)
+}
+
+// GET /coupons/:id
+func (h *CouponHandler) GetByCouponID(c *gin.Context) {
+	id, ok := idParam(c)
+	if !ok {
+		return
+	}
+	coupon, err := h.service.GetByCouponID(c.Request.Context(), id)
+	if err != nil {
+		writeError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, coupon)
+}
+
+// POST /coupons
+func (h *CouponHandler) CreateCoupon(c *gin.Context) {
+	// Coupon mới mặc định active nếu body không gửi "active"
+	coupon := models.Coupon{Active: true}
+	if err := c.ShouldBindJSON(&coupon); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid JSON"})
+		return
+	}
+	if err := h.service.CreateCoupon(c.Request.Context(), &coupon); err != nil {
+		writeError(c, err)
+		return
+	}
+	c.JSON(http.StatusCreated, coupon)
+}
+
+// PUT /coupons/:id
+func (h *CouponHandler) UpdateById(c *gin.Context) {
+	id, ok := idParam(c)
+	if !ok {
+		return
+	}
+	coupon := models.Coupon{Active: true}
+	if err := c.ShouldBindJSON(&coupon); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid JSON"})
+		return
+	}
+	coupon.ID = id
+
+	updated, err := h.service.UpdateById(c.Request.Context(), &coupon)
+	if err != nil {
+		writeError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, updated)
+}
+
+// DELETE /coupons/:id
+func (h *CouponHandler) DeleteById(c *gin.Context) {
+	id, ok := idParam(c)
+	if !ok {
+		return
+	}
+	coupon, err := h.service.DeleteById(c.Request.Context(), id)
+	if err != nil {
+		writeError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{
+		"message": "Coupon deleted successfully",
+		"coupon":  coupon,
+	})
+}
+
+func idParam(c *gin.Context) (uint, bool) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil || id == 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid ID"})
+		return 0, false
+	}
+	return uint(id), true
+}
+
+func writeError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, service.ErrInvalidInput):
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	case errors.Is(err, repositories.ErrNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+	case errors.Is(err, repositories.ErrConflict):
+		c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+	default:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Coupon operation failed"})
+	}
+}
@@ -0,0 +1,112 @@
+package coupon_test
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/maithuc2003/Test_GIN_golang/internal/handler/coupon"
+	"github.com/maithuc2003/Test_GIN_golang/internal/interfaces/repositories"
+	"github.com/maithuc2003/Test_GIN_golang/internal/interfaces/service"
+	mocks "github.com/maithuc2003/Test_GIN_golang/internal/mocks/service"
+	"github.com/maithuc2003/Test_GIN_golang/internal/models"
+)
+
+func TestCreateCoupon(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	tests := []struct {
+		name           string
+		body           string
+		wantActive     bool
+		callService    bool
+		mockErr        error
+		expectedStatus int
+	}{
+		{name: "success defaults to active", body: `{"code":"SUMMER10","type":"percentage","value":"10"}`, wantActive: true, callService: true, expectedStatus: http.StatusCreated},
+		{name: "created inactive", body: `{"code":"SUMMER10","type":"percentage","value":"10","active":false}`, callService: true, expectedStatus: http.StatusCreated},
+		{name: "invalid JSON", body: `{"code":`, expectedStatus: http.StatusBadRequest},
+		{
+			name: "validation error", body: `{"code":"X","type":"percentage","value":"10"}`, wantActive: true, callService: true,
+			mockErr: fmt.Errorf("%w: code", service.ErrInvalidInput), expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name: "duplicate code", body: `{"code":"SUMMER10","type":"percentage","value":"10"}`, wantActive: true, callService: true,
+			mockErr: fmt.Errorf("%w: coupon SUMMER10 already exists", repositories.ErrConflict), expectedStatus: http.StatusConflict,
+		},
+		{
+			name: "db error", body: `{"code":"SUMMER10","type":"percentage","value":"10"}`, wantActive: true, callService: true,
+			mockErr: errors.New("db down"), expectedStatus: http.StatusInternalServerError,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockSvc := new(mocks.MockCouponService)
+			h := coupon.NewCouponHandler(mockSvc)
+			if tt.callService {
+				mockSvc.On("CreateCoupon", mock.Anything, mock.MatchedBy(func(c *models.Coupon) bool {
+					return c.Active == tt.wantActive
+				})).Return(tt.mockErr)
+			}
+
+			req := httptest.NewRequest(http.MethodPost, "/coupons", bytes.NewBufferString(tt.body))
+			req.Header.Set("Content-Type", "application/json")
+			rec := httptest.NewRecorder()
+
+			r := gin.Default()
+			r.POST("/coupons", h.CreateCoupon)
+			r.ServeHTTP(rec, req)
+
+			require.Equal(t, tt.expectedStatus, rec.Code)
+			mockSvc.AssertExpectations(t)
+		})
+	}
+}
+
+func TestCouponByID(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	mockSvc := new(mocks.MockCouponService)
+	h := coupon.NewCouponHandler(mockSvc)
+	mockSvc.On("GetByCouponID", mock.Anything, uint(1)).Return(&models.Coupon{ID: 1}, nil)
+	mockSvc.On("GetByCouponID", mock.Anything, uint(9)).Return(nil, fmt.Errorf("%w: coupon with ID 9 not found", repositories.ErrNotFound))
+	mockSvc.On("UpdateById", mock.Anything, mock.MatchedBy(func(c *models.Coupon) bool { return c.ID == 1 })).Return(&models.Coupon{ID: 1}, nil)
+	mockSvc.On("DeleteById", mock.Anything, uint(1)).Return(nil, fmt.Errorf("%w: coupon has been used", repositories.ErrConflict))
+
+	r := gin.Default()
+	r.GET("/coupons/:id", h.GetByCouponID)
+	r.PUT("/coupons/:id", h.UpdateById)
+	r.DELETE("/coupons/:id", h.DeleteById)
+
+	tests := []struct {
+		name           string
+		method         string
+		path           string
+		body           string
+		expectedStatus int
+	}{
+		{name: "get", method: http.MethodGet, path: "/coupons/1", expectedStatus: http.StatusOK},
+		{name: "get not found", method: http.MethodGet, path: "/coupons/9", expectedStatus: http.StatusNotFound},
+		{name: "get invalid ID", method: http.MethodGet, path: "/coupons/x", expectedStatus: http.StatusBadRequest},
+		{name: "update", method: http.MethodPut, path: "/coupons/1", body: `{"code":"SUMMER10","type":"percentage","value":"10"}`, expectedStatus: http.StatusOK},
+		{name: "delete used coupon", method: http.MethodDelete, path: "/coupons/1", expectedStatus: http.StatusConflict},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(tt.method, tt.path, bytes.NewBufferString(tt.body))
+			req.Header.Set("Content-Type", "application/json")
+			rec := httptest.NewRecorder()
+			r.ServeHTTP(rec, req)
+			require.Equal(t, tt.expectedStatus, rec.Code)
+		})
+	}
+	mockSvc.AssertExpectations(t)
+}
@@ -0,0 +1,74 @@
+package cover
+
+import (
+	"errors"
+	"io"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/maithuc2003/Test_GIN_golang/internal/interfaces/repositories"
+	"github.com/maithuc2003/Test_GIN_golang/internal/interfaces/service"
+)
+
+// multipartOverhead là phần dư cho boundary/header của form ngoài dung lượng ảnh
+const multipartOverhead = 1 << 20
+
+type CoverHandler struct {
+	coverService service.CoverServiceInterface
+}
+
+func NewCoverHandler(coverService service.CoverServiceInterface) *CoverHandler {
+	return &CoverHandler{coverService: coverService}
+}
+
+// POST /books/:id/cover (multipart, field "cover")
+func (h *CoverHandler) UploadCover(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil || id <= 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid book ID"})
+		return
+	}
+
+	c.Request.Body = http.MaxBytesReader(c.Writer, c.Request.Body, service.MaxCoverSize+multipartOverhead)
+	fileHeader, err := c.FormFile("cover")
+	if err != nil {
+		var tooLarge *http.MaxBytesError
+		if errors.As(err, &tooLarge) {
+			c.JSON(http.StatusRequestEntityTooLarge, gin.H{"error": "Cover image is too large"})
+			return
+		}
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Missing cover file"})
+		return
+	}
+	if fileHeader.Size > service.MaxCoverSize {
+		c.JSON(http.StatusRequestEntityTooLarge, gin.H{"error": "Cover image is too large"})
+		return
+	}
+
+	file, err := fileHeader.Open()
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Cannot read cover file"})
+		return
+	}
+	defer file.Close()
+	data, err := io.ReadAll(file)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Cannot read cover file"})
+		return
+	}
+
+	book, err := h.coverService.UploadCover(c.Request.Context(), id, data)
+	if err != nil {
+		switch {
+		case errors.Is(err, service.ErrInvalidInput):
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		case errors.Is(err, repositories.ErrNotFound):
+			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		default:
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to upload cover"})
+		}
+		return
+	}
+	c.JSON(http.StatusOK, book)
+}
@@ -0,0 +1,114 @@
+package cover_test
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/maithuc2003/Test_GIN_golang/internal/handler/cover"
+	"github.com/maithuc2003/Test_GIN_golang/internal/interfaces/repositories"
+	"github.com/maithuc2003/Test_GIN_golang/internal/interfaces/service"
+	mocks "github.com/maithuc2003/Test_GIN_golang/internal/mocks/service"
+	"github.com/maithuc2003/Test_GIN_golang/internal/models"
+)
+
+func multipartBody(t *testing.T, field string, content []byte) (*bytes.Buffer, string) {
+	var buf bytes.Buffer
+	w := multipart.NewWriter(&buf)
+	part, err := w.CreateFormFile(field, "cover.png")
+	require.NoError(t, err)
+	_, err = part.Write(content)
+	require.NoError(t, err)
+	require.NoError(t, w.Close())
+	return &buf, w.FormDataContentType()
+}
+
+func TestUploadCover(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	image := []byte("\x89PNG\r\n\x1a\nfake")
+
+	tests := []struct {
+		name           string
+		path           string
+		field          string
+		content        []byte
+		mockBook       *models.Book
+		mockErr        error
+		callsSvc       bool
+		expectedStatus int
+	}{
+		{
+			name:           "success",
+			path:           "/books/1/cover",
+			field:          "cover",
+			content:        image,
+			mockBook:       &models.Book{ID: 1, Cover: &models.BookCover{URL: "/uploads/covers/1/a/original.png"}},
+			callsSvc:       true,
+			expectedStatus: http.StatusOK,
+		},
+		{name: "invalid ID", path: "/books/abc/cover", field: "cover", content: image, expectedStatus: http.StatusBadRequest},
+		{name: "missing file field", path: "/books/1/cover", field: "file", content: image, expectedStatus: http.StatusBadRequest},
+		{name: "file too large", path: "/books/1/cover", field: "cover", content: make([]byte, service.MaxCoverSize+1), expectedStatus: http.StatusRequestEntityTooLarge},
+		{
+			name:           "invalid image",
+			path:           "/books/1/cover",
+			field:          "cover",
+			content:        []byte("hello"),
+			mockErr:        fmt.Errorf("%w: unsupported cover image type text/plain", service.ErrInvalidInput),
+			callsSvc:       true,
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:           "book not found",
+			path:           "/books/9/cover",
+			field:          "cover",
+			content:        image,
+			mockErr:        fmt.Errorf("book with ID 9 %w", repositories.ErrNotFound),
+			callsSvc:       true,
+			expectedStatus: http.StatusNotFound,
+		},
+		{
+			name:           "storage error",
+			path:           "/books/1/cover",
+			field:          "cover",
+			content:        image,
+			mockErr:        errors.New("failed to store cover: s3 down"),
+			callsSvc:       true,
+			expectedStatus: http.StatusInternalServerError,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockSvc := new(mocks.MockCoverService)
+			h := cover.NewCoverHandler(mockSvc)
+			if tt.callsSvc {
+				mockSvc.On("UploadCover", mock.Anything, mock.AnythingOfType("int"), tt.content).Return(tt.mockBook, tt.mockErr)
+			}
+
+			body, contentType := multipartBody(t, tt.field, tt.content)
+			req := httptest.NewRequest(http.MethodPost, tt.path, body)
+			req.Header.Set("Content-Type", contentType)
+			rec := httptest.NewRecorder()
+
+			r := gin.Default()
+			r.POST("/books/:id/cover", h.UploadCover)
+			r.ServeHTTP(rec, req)
+
+			require.Equal(t, tt.expectedStatus, rec.Code)
+			if tt.expectedStatus == http.StatusOK {
+				require.Contains(t, rec.Body.String(), `"cover":{"url":"/uploads/covers/1/a/original.png"`)
+			}
+			mockSvc.AssertExpectations(t)
+		})
+	}
+}
@@ -0,0 +1,88 @@
+package export
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/maithuc2003/Test_GIN_golang/internal/interfaces/service"
+	"github.com/maithuc2003/Test_GIN_golang/internal/models"
+)
+
+var contentTypes = map[string]string{
+	models.FormatCSV:    "text/csv; charset=utf-8",
+	models.FormatNDJSON: "application/x-ndjson",
+	models.FormatXLSX:   "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet",
+}
+
+type ExportHandler struct {
+	exportService service.ExportServiceInterface
+}
+
+func NewExportHandler(exportService service.ExportServiceInterface) *ExportHandler {
+	return &ExportHandler{exportService: exportService}
+}
+
+// GET /export/books?format=csv|ndjson|xlsx (cùng bộ lọc với GET /books)
+func (h *ExportHandler) ExportBooks(c *gin.Context) {
+	var filter models.BookFilter
+	if err := c.ShouldBindQuery(&filter); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid filter parameters"})
+		return
+	}
+	h.stream(c, "books", func(w io.Writer, format string) error {
+		return h.exportService.ExportBooks(c.Request.Context(), w, format, filter)
+	})
+}
+
+// GET /export/authors?format=csv|ndjson|xlsx (cùng bộ lọc với GET /authors)
+func (h *ExportHandler) ExportAuthors(c *gin.Context) {
+	var filter models.AuthorFilter
+	if err := c.ShouldBindQuery(&filter); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid filter parameters"})
+		return
+	}
+	h.stream(c, "authors", func(w io.Writer, format string) error {
+		return h.exportService.ExportAuthors(c.Request.Context(), w, format, filter)
+	})
+}
+
+// GET /export/orders?format=csv|ndjson|xlsx (cùng bộ lọc với GET /orders)
+func (h *ExportHandler) ExportOrders(c *gin.Context) {
+	var filter models.OrderFilter
+	if err := c.ShouldBindQuery(&filter); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid filter parameters"})
+		return
+	}
+	h.stream(c, "orders", func(w io.Writer, format string) error {
+		return h.exportService.ExportOrders(c.Request.Context(), w, format, filter)
+	})
+}
+
+func (h *ExportHandler) stream(c *gin.Context, name string, export func(w io.Writer, format string) error) {
+	format := strings.ToLower(c.DefaultQuery("format", models.FormatCSV))
+	contentType, ok := contentTypes[format]
+	if !ok {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Unsupported format, use csv, ndjson or xlsx"})
+		return
+	}
+
+	filename := fmt.Sprintf("%s-%s.%s", name, time.Now().Format("20060102-150405"), format)
+	c.Header("Content-Type", contentType)
+	c.Header("Content-Disposition", fmt.Sprintf(`attachment; filename="%s"`, filename))
+	c.Status(http.StatusOK)
+
+	if err := export(c.Writer, format); err != nil {
+		if !c.Writer.Written() {
+			c.Writer.Header().Del("Content-Disposition")
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to export " + name})
+			return
+		}
+		// Đã gửi một phần dữ liệu -> chỉ có thể ngắt stream
+		_ = c.Error(err)
+		c.Abort()
+	}
+}
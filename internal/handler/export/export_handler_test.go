@@ -0,0 +1,119 @@
+package export_test
+
+import (
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/maithuc2003/Test_GIN_golang/internal/handler/export"
+	mockService "github.com/maithuc2003/Test_GIN_golang/internal/mocks/service"
+	"github.com/maithuc2003/Test_GIN_golang/internal/models"
+)
+
+func TestExportBooks(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	tests := []struct {
+		name             string
+		query            string
+		expectFormat     string
+		expectFilter     models.BookFilter
+		mockErr          error
+		expectedStatus   int
+		expectedType     string
+		expectAttachment bool
+	}{
+		{
+			name:             "default csv with filter",
+			query:            "?author_id=2&title=biec",
+			expectFormat:     models.FormatCSV,
+			expectFilter:     models.BookFilter{AuthorID: 2, Title: "biec"},
+			expectedStatus:   http.StatusOK,
+			expectedType:     "text/csv; charset=utf-8",
+			expectAttachment: true,
+		},
+		{
+			name:             "xlsx",
+			query:            "?format=XLSX",
+			expectFormat:     models.FormatXLSX,
+			expectedStatus:   http.StatusOK,
+			expectedType:     "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet",
+			expectAttachment: true,
+		},
+		{
+			name:           "unsupported format",
+			query:          "?format=pdf",
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:           "invalid filter",
+			query:          "?author_id=abc",
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:           "service error before streaming",
+			query:          "?format=ndjson",
+			expectFormat:   models.FormatNDJSON,
+			mockErr:        errors.New("db down"),
+			expectedStatus: http.StatusInternalServerError,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockSvc := new(mockService.MockExportService)
+			h := export.NewExportHandler(mockSvc)
+			if tt.expectFormat != "" {
+				mockSvc.On("ExportBooks", mock.Anything, mock.Anything, tt.expectFormat, tt.expectFilter).
+					Run(func(args mock.Arguments) {
+						if tt.mockErr == nil {
+							_, _ = io.WriteString(args.Get(1).(io.Writer), "id,title\n")
+						}
+					}).
+					Return(tt.mockErr)
+			}
+
+			r := gin.New()
+			r.GET("/export/books", h.ExportBooks)
+
+			req := httptest.NewRequest(http.MethodGet, "/export/books"+tt.query, nil)
+			w := httptest.NewRecorder()
+			r.ServeHTTP(w, req)
+
+			require.Equal(t, tt.expectedStatus, w.Code)
+			if tt.expectedType != "" {
+				require.Equal(t, tt.expectedType, w.Header().Get("Content-Type"))
+			}
+			if tt.expectAttachment {
+				require.Contains(t, w.Header().Get("Content-Disposition"), "attachment; filename=\"books-")
+			} else {
+				require.Empty(t, w.Header().Get("Content-Disposition"))
+			}
+			mockSvc.AssertExpectations(t)
+		})
+	}
+}
+
+func TestExportOrders(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	mockSvc := new(mockService.MockExportService)
+	h := export.NewExportHandler(mockSvc)
+	mockSvc.On("ExportOrders", mock.Anything, mock.Anything, models.FormatCSV, models.OrderFilter{Status: "pending", UserID: 3}).Return(nil)
+
+	r := gin.New()
+	r.GET("/export/orders", h.ExportOrders)
+
+	req := httptest.NewRequest(http.MethodGet, "/export/orders?status=pending&user_id=3", nil)
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+
+	require.Equal(t, http.StatusOK, w.Code)
+	mockSvc.AssertExpectations(t)
+}
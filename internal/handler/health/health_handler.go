@@ -0,0 +1,50 @@
+package health
+
+import (
+	"context"
+	"log/slog"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/maithuc2003/Test_GIN_golang/internal/health"
+)
+
+// checkTimeout giới hạn thời gian của một lần kiểm tra readiness
+const checkTimeout = 2 * time.Second
+
+type HealthHandler struct {
+	checker *health.Checker
+}
+
+func NewHealthHandler(checker *health.Checker) *HealthHandler {
+	return &HealthHandler{checker: checker}
+}
+
+// GET /healthz: tiến trình còn chạy và phục vụ được request, không kiểm tra phụ thuộc
+func (h *HealthHandler) Healthz(c *gin.Context) {
+	c.JSON(http.StatusOK, gin.H{"status": "ok"})
+}
+
+// GET /readyz: 200 khi mọi phụ thuộc sẵn sàng, ngược lại 503. Chi tiết lỗi chỉ ghi log.
+func (h *HealthHandler) Readyz(c *gin.Context) {
+	ctx, cancel := context.WithTimeout(c.Request.Context(), checkTimeout)
+	defer cancel()
+
+	result := h.checker.Run(ctx)
+	checks := make(map[string]string, len(result.Checks))
+	for name, err := range result.Checks {
+		if err != nil {
+			slog.WarnContext(ctx, "readiness check failed", "check", name, "error", err)
+			checks[name] = "unavailable"
+			continue
+		}
+		checks[name] = "ok"
+	}
+
+	if !result.Ready {
+		c.JSON(http.StatusServiceUnavailable, gin.H{"status": "unavailable", "checks": checks})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"status": "ok", "checks": checks})
+}
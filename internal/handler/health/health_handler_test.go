@@ -0,0 +1,71 @@
+package health_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/require"
+
+	handler "github.com/maithuc2003/Test_GIN_golang/internal/handler/health"
+	"github.com/maithuc2003/Test_GIN_golang/internal/health"
+)
+
+func TestReadyz(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	ok := func(context.Context) error { return nil }
+	down := func(context.Context) error { return errors.New("connection refused") }
+
+	tests := []struct {
+		name           string
+		cache          health.Check
+		expectedStatus int
+		expectedChecks map[string]string
+	}{
+		{name: "all ready", cache: ok, expectedStatus: http.StatusOK, expectedChecks: map[string]string{"database": "ok", "cache": "ok"}},
+		{name: "cache down", cache: down, expectedStatus: http.StatusServiceUnavailable, expectedChecks: map[string]string{"database": "ok", "cache": "unavailable"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			checker := health.NewChecker()
+			checker.Add("database", ok)
+			checker.Add("cache", tt.cache)
+			h := handler.NewHealthHandler(checker)
+
+			r := gin.New()
+			r.GET("/readyz", h.Readyz)
+			rec := httptest.NewRecorder()
+			r.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/readyz", nil))
+
+			require.Equal(t, tt.expectedStatus, rec.Code)
+			var body struct {
+				Checks map[string]string `json:"checks"`
+			}
+			require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &body))
+			require.Equal(t, tt.expectedChecks, body.Checks)
+			require.NotContains(t, rec.Body.String(), "connection refused", "error details stay in the log")
+		})
+	}
+}
+
+func TestHealthz(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	checker := health.NewChecker()
+	checker.Add("database", func(context.Context) error { return errors.New("down") })
+	h := handler.NewHealthHandler(checker)
+
+	r := gin.New()
+	r.GET("/healthz", h.Healthz)
+	rec := httptest.NewRecorder()
+	r.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/healthz", nil))
+
+	require.Equal(t, http.StatusOK, rec.Code, "liveness ignores dependencies")
+	require.JSONEq(t, `{"status":"ok"}`, rec.Body.String())
+}
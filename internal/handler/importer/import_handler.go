@@ -0,0 +1,104 @@
+package importer
+
+import (
+	"io"
+	"mime"
+	"net/http"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/maithuc2003/Test_GIN_golang/internal/interfaces/service"
+	"github.com/maithuc2003/Test_GIN_golang/internal/models"
+)
+
+type ImportHandler struct {
+	importService service.ImportServiceInterface
+}
+
+func NewImportHandler(importService service.ImportServiceInterface) *ImportHandler {
+	return &ImportHandler{importService: importService}
+}
+
+// POST /import/books?format=csv|ndjson&dry_run=true
+// Nhận file multipart (field "file") hoặc body thô
+func (h *ImportHandler) ImportBooks(c *gin.Context) {
+	opts := models.ImportOptions{Format: strings.ToLower(c.Query("format"))}
+
+	if raw := c.Query("dry_run"); raw != "" {
+		dryRun, err := strconv.ParseBool(raw)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid dry_run parameter"})
+			return
+		}
+		opts.DryRun = dryRun
+	}
+	if raw := c.Query("batch_size"); raw != "" {
+		size, err := strconv.Atoi(raw)
+		if err != nil || size <= 0 {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid batch_size parameter"})
+			return
+		}
+		opts.BatchSize = size
+	}
+
+	var body io.Reader = c.Request.Body
+	contentType, _, _ := mime.ParseMediaType(c.GetHeader("Content-Type"))
+	if contentType == "multipart/form-data" {
+		fileHeader, err := c.FormFile("file")
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Missing upload file"})
+			return
+		}
+		file, err := fileHeader.Open()
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Cannot read upload file"})
+			return
+		}
+		defer file.Close()
+		body = file
+		if opts.Format == "" {
+			opts.Format = formatFromName(fileHeader.Filename)
+		}
+	} else if opts.Format == "" {
+		opts.Format = formatFromContentType(contentType)
+	}
+
+	if opts.Format == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Cannot detect import format, use ?format=csv or ?format=ndjson"})
+		return
+	}
+
+	report, err := h.importService.ImportBooks(c.Request.Context(), body, opts)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	status := http.StatusOK
+	if !report.DryRun && report.Created > 0 {
+		status = http.StatusCreated
+	}
+	c.JSON(status, report)
+}
+
+func formatFromName(name string) string {
+	switch strings.ToLower(filepath.Ext(name)) {
+	case ".csv":
+		return models.FormatCSV
+	case ".ndjson", ".jsonl":
+		return models.FormatNDJSON
+	}
+	return ""
+}
+
+func formatFromContentType(contentType string) string {
+	switch contentType {
+	case "text/csv":
+		return models.FormatCSV
+	case "application/x-ndjson", "application/ndjson", "application/jsonl":
+		return models.FormatNDJSON
+	}
+	return ""
+}
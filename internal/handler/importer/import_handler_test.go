@@ -0,0 +1,108 @@
+package importer_test
+
+import (
+	"bytes"
+	"errors"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/maithuc2003/Test_GIN_golang/internal/handler/importer"
+	mockService "github.com/maithuc2003/Test_GIN_golang/internal/mocks/service"
+	"github.com/maithuc2003/Test_GIN_golang/internal/models"
+)
+
+func multipartBody(t *testing.T, filename, content string) (*bytes.Buffer, string) {
+	var buf bytes.Buffer
+	w := multipart.NewWriter(&buf)
+	part, err := w.CreateFormFile("file", filename)
+	require.NoError(t, err)
+	_, err = part.Write([]byte(content))
+	require.NoError(t, err)
+	require.NoError(t, w.Close())
+	return &buf, w.FormDataContentType()
+}
+
+func TestImportBooks(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	csvBody, csvType := multipartBody(t, "books.csv", "title,author\nDune,Frank Herbert\n")
+
+	tests := []struct {
+		name           string
+		query          string
+		body           *bytes.Buffer
+		contentType    string
+		expectOpts     *models.ImportOptions
+		mockReport     *models.ImportReport
+		mockErr        error
+		expectedStatus int
+	}{
+		{
+			name:           "multipart csv detected from file name",
+			body:           csvBody,
+			contentType:    csvType,
+			expectOpts:     &models.ImportOptions{Format: models.FormatCSV},
+			mockReport:     &models.ImportReport{Total: 1, Created: 1},
+			expectedStatus: http.StatusCreated,
+		},
+		{
+			name:           "raw ndjson dry run",
+			query:          "?dry_run=true&batch_size=10",
+			body:           bytes.NewBufferString(`{"title":"Dune","author":"Frank Herbert"}`),
+			contentType:    "application/x-ndjson",
+			expectOpts:     &models.ImportOptions{Format: models.FormatNDJSON, DryRun: true, BatchSize: 10},
+			mockReport:     &models.ImportReport{DryRun: true, Total: 1, Created: 1},
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:           "unknown format",
+			body:           bytes.NewBufferString("data"),
+			contentType:    "text/plain",
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:           "invalid dry_run",
+			query:          "?format=csv&dry_run=maybe",
+			body:           bytes.NewBufferString("title,author\n"),
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:           "service error",
+			query:          "?format=csv",
+			body:           bytes.NewBufferString("title\n"),
+			contentType:    "text/csv",
+			expectOpts:     &models.ImportOptions{Format: models.FormatCSV},
+			mockErr:        errors.New(`csv header must contain column "author"`),
+			expectedStatus: http.StatusBadRequest,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockSvc := new(mockService.MockImportService)
+			h := importer.NewImportHandler(mockSvc)
+			if tt.expectOpts != nil {
+				mockSvc.On("ImportBooks", mock.Anything, mock.Anything, *tt.expectOpts).Return(tt.mockReport, tt.mockErr)
+			}
+
+			r := gin.New()
+			r.POST("/import/books", h.ImportBooks)
+
+			req := httptest.NewRequest(http.MethodPost, "/import/books"+tt.query, tt.body)
+			if tt.contentType != "" {
+				req.Header.Set("Content-Type", tt.contentType)
+			}
+			w := httptest.NewRecorder()
+			r.ServeHTTP(w, req)
+
+			require.Equal(t, tt.expectedStatus, w.Code)
+			mockSvc.AssertExpectations(t)
+		})
+	}
+}
@@ -0,0 +1,83 @@
+package invoice
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/maithuc2003/Test_GIN_golang/internal/interfaces/repositories"
+	"github.com/maithuc2003/Test_GIN_golang/internal/interfaces/service"
+	"github.com/maithuc2003/Test_GIN_golang/internal/models"
+)
+
+type InvoiceHandler struct {
+	invoiceService service.InvoiceServiceInterface
+}
+
+func NewInvoiceHandler(invoiceService service.InvoiceServiceInterface) *InvoiceHandler {
+	return &InvoiceHandler{invoiceService: invoiceService}
+}
+
+// GET /orders/:id/invoice?format=html|pdf - mặc định html
+func (h *InvoiceHandler) GetInvoice(c *gin.Context) {
+	userID, ok := currentUser(c)
+	if !ok {
+		return
+	}
+	orderID, ok := orderIDParam(c)
+	if !ok {
+		return
+	}
+	format := c.DefaultQuery("format", models.InvoiceFormatHTML)
+	if format != models.InvoiceFormatHTML && format != models.InvoiceFormatPDF {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Unsupported format, use html or pdf"})
+		return
+	}
+
+	issued, err := h.invoiceService.GetInvoice(c.Request.Context(), userID, orderID)
+	if err != nil {
+		writeError(c, err)
+		return
+	}
+	c.Header("X-Invoice-Number", issued.Number)
+	if format == models.InvoiceFormatPDF {
+		c.Header("Content-Disposition", fmt.Sprintf(`attachment; filename="%s.pdf"`, issued.Number))
+		c.Data(http.StatusOK, "application/pdf", issued.PDF)
+		return
+	}
+	c.Header("Content-Disposition", fmt.Sprintf(`inline; filename="%s.html"`, issued.Number))
+	c.Data(http.StatusOK, "text/html; charset=utf-8", issued.HTML)
+}
+
+func currentUser(c *gin.Context) (uint, bool) {
+	userID := c.GetInt("user_id")
+	if userID <= 0 {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "User ID not found"})
+		return 0, false
+	}
+	return uint(userID), true
+}
+
+func orderIDParam(c *gin.Context) (uint, bool) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil || id == 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid order ID"})
+		return 0, false
+	}
+	return uint(id), true
+}
+
+func writeError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, service.ErrInvalidInput):
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	case errors.Is(err, repositories.ErrNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+	case errors.Is(err, repositories.ErrConflict):
+		c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+	default:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get invoice"})
+	}
+}
@@ -0,0 +1,100 @@
+package invoice_test
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	handler "github.com/maithuc2003/Test_GIN_golang/internal/handler/invoice"
+	"github.com/maithuc2003/Test_GIN_golang/internal/interfaces/repositories"
+	mocks "github.com/maithuc2003/Test_GIN_golang/internal/mocks/service"
+	"github.com/maithuc2003/Test_GIN_golang/internal/models"
+)
+
+// withUser giả lập AuthMiddleware gán user_id vào context
+func withUser(userID int) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if userID > 0 {
+			c.Set("user_id", userID)
+		}
+		c.Next()
+	}
+}
+
+func TestGetInvoice(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	issued := &models.Invoice{OrderID: 3, Number: "INV-2025-000001", HTML: []byte("<html></html>"), PDF: []byte("%PDF-1.3")}
+
+	tests := []struct {
+		name                string
+		userID              int
+		path                string
+		callService         bool
+		mockErr             error
+		expectedStatus      int
+		expectedType        string
+		expectedDisposition string
+		expectedBody        string
+	}{
+		{
+			name: "html by default", userID: 7, path: "/orders/3/invoice", callService: true,
+			expectedStatus: http.StatusOK, expectedType: "text/html; charset=utf-8",
+			expectedDisposition: `inline; filename="INV-2025-000001.html"`, expectedBody: "<html></html>",
+		},
+		{
+			name: "pdf", userID: 7, path: "/orders/3/invoice?format=pdf", callService: true,
+			expectedStatus: http.StatusOK, expectedType: "application/pdf",
+			expectedDisposition: `attachment; filename="INV-2025-000001.pdf"`, expectedBody: "%PDF-1.3",
+		},
+		{name: "no user", path: "/orders/3/invoice", expectedStatus: http.StatusUnauthorized},
+		{name: "invalid order ID", userID: 7, path: "/orders/abc/invoice", expectedStatus: http.StatusBadRequest},
+		{name: "unsupported format", userID: 7, path: "/orders/3/invoice?format=docx", expectedStatus: http.StatusBadRequest},
+		{
+			name: "not owner", userID: 7, path: "/orders/3/invoice", callService: true,
+			mockErr: fmt.Errorf("order with ID 3 %w", repositories.ErrNotFound), expectedStatus: http.StatusNotFound,
+		},
+		{
+			name: "not paid", userID: 7, path: "/orders/3/invoice", callService: true,
+			mockErr: fmt.Errorf("%w: order 3 is pending", repositories.ErrConflict), expectedStatus: http.StatusConflict,
+		},
+		{
+			name: "database error", userID: 7, path: "/orders/3/invoice", callService: true,
+			mockErr: errors.New("db down"), expectedStatus: http.StatusInternalServerError,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockService := new(mocks.MockInvoiceService)
+			h := handler.NewInvoiceHandler(mockService)
+			if tt.callService {
+				var result *models.Invoice
+				if tt.mockErr == nil {
+					result = issued
+				}
+				mockService.On("GetInvoice", mock.Anything, uint(tt.userID), uint(3)).Return(result, tt.mockErr)
+			}
+
+			router := gin.New()
+			router.GET("/orders/:id/invoice", withUser(tt.userID), h.GetInvoice)
+
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, tt.path, nil))
+
+			require.Equal(t, tt.expectedStatus, w.Code, w.Body.String())
+			if tt.expectedType != "" {
+				require.Equal(t, tt.expectedType, w.Header().Get("Content-Type"))
+				require.Equal(t, tt.expectedDisposition, w.Header().Get("Content-Disposition"))
+				require.Equal(t, issued.Number, w.Header().Get("X-Invoice-Number"))
+				require.Equal(t, tt.expectedBody, w.Body.String())
+			}
+			mockService.AssertExpectations(t)
+		})
+	}
+}
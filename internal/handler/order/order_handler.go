@@ -1,11 +1,13 @@
 package order
 
 import (
+	"errors"
 	"net/http"
 	"strconv"
 	"time"
 
 	"github.com/gin-gonic/gin"
+	"github.com/maithuc2003/Test_GIN_golang/internal/interfaces/repositories"
 	"github.com/maithuc2003/Test_GIN_golang/internal/interfaces/service"
 	"github.com/maithuc2003/Test_GIN_golang/internal/models"
 )
@@ -26,8 +28,13 @@ func (h *OrderHandler) CreateOrder(c *gin.Context) {
 	}
 	order.OrderedAt = time.Now()
 
-	if err := h.serviceOrder.CreateOrder(&order); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	if err := h.serviceOrder.CreateOrder(c.Request.Context(), &order); err != nil {
+		status := http.StatusBadRequest
+		// Coupon đã hết lượt dùng trong lúc tạo đơn
+		if errors.Is(err, repositories.ErrConflict) {
+			status = http.StatusConflict
+		}
+		c.JSON(status, gin.H{"error": err.Error()})
 		return
 	}
 
@@ -38,7 +45,12 @@ func (h *OrderHandler) CreateOrder(c *gin.Context) {
 }
 
 func (h *OrderHandler) GetAllOrders(c *gin.Context) {
-	orders, err := h.serviceOrder.GetAllOrders()
+	var filter models.OrderFilter
+	if err := c.ShouldBindQuery(&filter); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid filter parameters"})
+		return
+	}
+	orders, err := h.serviceOrder.GetAllOrders(c.Request.Context(), filter)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get orders"})
 		return
@@ -55,7 +67,7 @@ func (h *OrderHandler) GetByOrderID(c *gin.Context) {
 		return
 	}
 
-	order, err := h.serviceOrder.GetByOrderID(id)
+	order, err := h.serviceOrder.GetByOrderID(c.Request.Context(), id)
 	if err != nil {
 		c.JSON(http.StatusNotFound, gin.H{"error": "Order not found"})
 		return
@@ -71,7 +83,7 @@ func (h *OrderHandler) DeleteByOrderID(c *gin.Context) {
 		return
 	}
 
-	order, err := h.serviceOrder.DeleteByOrderID(id)
+	order, err := h.serviceOrder.DeleteByOrderID(c.Request.Context(), id)
 	if err != nil {
 		if err.Error() == "not found" {
 			c.JSON(http.StatusNotFound, gin.H{"error": "Order not found"})
@@ -107,7 +119,7 @@ func (h *OrderHandler) UpdateByOrderID(c *gin.Context) {
 	updateOrder.ID = uint(id)
 	updateOrder.UpdatedAt = time.Now()
 
-	order, err := h.serviceOrder.UpdateByOrderID(&updateOrder)
+	order, err := h.serviceOrder.UpdateByOrderID(c.Request.Context(), &updateOrder)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update order"})
 		return
@@ -66,7 +66,7 @@ func TestCreateOrder(t *testing.T) {
 				body = []byte(v)
 			default:
 				body, _ = json.Marshal(v)
-				mockOrderService.On("CreateOrder", mock.Anything).Return(tt.mockErr)
+				mockOrderService.On("CreateOrder", mock.Anything, mock.Anything).Return(tt.mockErr)
 			}
 
 			req := httptest.NewRequest(http.MethodPost, "/orders", bytes.NewBuffer(body))
@@ -110,7 +110,7 @@ func TestGetAllOrders(t *testing.T) {
 			mockOrderService := new(mockService.MockOrderService)
 			h := order.NewOrderHandler(mockOrderService)
 
-			mockOrderService.On("GetAllOrders").Return(tt.mockOrders, tt.mockErr)
+			mockOrderService.On("GetAllOrders", mock.Anything, mock.Anything).Return(tt.mockOrders, tt.mockErr)
 
 			req := httptest.NewRequest(http.MethodGet, "/orders", nil)
 			w := httptest.NewRecorder()
@@ -159,7 +159,7 @@ func TestGetByOrderID(t *testing.T) {
 
 			if tt.expectedStatus != http.StatusBadRequest {
 				mockOrderService.
-					On("GetByOrderID", mock.Anything).
+					On("GetByOrderID", mock.Anything, mock.Anything).
 					Return(tt.mockOrder, tt.mockErr)
 			}
 
@@ -226,7 +226,7 @@ func TestDeleteByOrderID(t *testing.T) {
 
 			if tt.expectedStatus != http.StatusBadRequest {
 				mockOrderService.
-					On("DeleteByOrderID", mock.Anything).
+					On("DeleteByOrderID", mock.Anything, mock.Anything).
 					Return(tt.mockOrder, tt.mockErr)
 			}
 
@@ -289,7 +289,7 @@ func TestUpdateByOrderID(t *testing.T) {
 				body = []byte(v)
 			default:
 				body, _ = json.Marshal(v)
-				mockOrderService.On("UpdateByOrderID", mock.Anything).Return(tt.mockReturn, tt.mockErr)
+				mockOrderService.On("UpdateByOrderID", mock.Anything, mock.Anything).Return(tt.mockReturn, tt.mockErr)
 			}
 
 			req := httptest.NewRequest(http.MethodPut, "/orders/"+tt.param, bytes.NewBuffer(body))
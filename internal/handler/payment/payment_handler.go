@@ -0,0 +1,127 @@
+package payment
+
+import (
+	"errors"
+	"io"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/maithuc2003/Test_GIN_golang/internal/interfaces/repositories"
+	"github.com/maithuc2003/Test_GIN_golang/internal/interfaces/service"
+	"github.com/maithuc2003/Test_GIN_golang/internal/models"
+	"github.com/maithuc2003/Test_GIN_golang/internal/payment"
+)
+
+// maxWebhookSize giới hạn kích thước body webhook từ cổng thanh toán
+const maxWebhookSize = 64 << 10
+
+type PaymentHandler struct {
+	paymentService service.PaymentServiceInterface
+}
+
+func NewPaymentHandler(paymentService service.PaymentServiceInterface) *PaymentHandler {
+	return &PaymentHandler{paymentService: paymentService}
+}
+
+// POST /orders/:id/pay
+func (h *PaymentHandler) Pay(c *gin.Context) {
+	userID, ok := currentUser(c)
+	if !ok {
+		return
+	}
+	orderID, ok := orderIDParam(c)
+	if !ok {
+		return
+	}
+	var req models.PayRequest
+	// Body rỗng được chấp nhận (cổng thanh toán giả lập không cần token)
+	if c.Request.ContentLength != 0 {
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request body"})
+			return
+		}
+	}
+
+	record, err := h.paymentService.Pay(c.Request.Context(), userID, orderID, req)
+	if err != nil {
+		writeError(c, err)
+		return
+	}
+	switch record.Status {
+	case models.PaymentStatusFailed:
+		c.JSON(http.StatusPaymentRequired, gin.H{"error": record.FailureReason, "payment": record})
+	case models.PaymentStatusPending:
+		c.JSON(http.StatusAccepted, record)
+	default:
+		c.JSON(http.StatusOK, record)
+	}
+}
+
+// GET /orders/:id/payments
+func (h *PaymentHandler) GetPayments(c *gin.Context) {
+	userID, ok := currentUser(c)
+	if !ok {
+		return
+	}
+	orderID, ok := orderIDParam(c)
+	if !ok {
+		return
+	}
+	payments, err := h.paymentService.GetPayments(c.Request.Context(), userID, orderID)
+	if err != nil {
+		writeError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, payments)
+}
+
+// POST /payments/webhook - gọi từ cổng thanh toán, xác thực bằng chữ ký thay vì JWT
+func (h *PaymentHandler) Webhook(c *gin.Context) {
+	payload, err := io.ReadAll(http.MaxBytesReader(c.Writer, c.Request.Body, maxWebhookSize))
+	if err != nil {
+		c.JSON(http.StatusRequestEntityTooLarge, gin.H{"error": "Webhook payload is too large"})
+		return
+	}
+	err = h.paymentService.HandleWebhook(c.Request.Context(), payload, c.GetHeader(payment.SignatureHeader))
+	if err != nil {
+		if errors.Is(err, payment.ErrInvalidSignature) {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": err.Error()})
+			return
+		}
+		writeError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "Webhook processed"})
+}
+
+func currentUser(c *gin.Context) (uint, bool) {
+	userID := c.GetInt("user_id")
+	if userID <= 0 {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "User ID not found"})
+		return 0, false
+	}
+	return uint(userID), true
+}
+
+func orderIDParam(c *gin.Context) (uint, bool) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil || id == 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid order ID"})
+		return 0, false
+	}
+	return uint(id), true
+}
+
+func writeError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, service.ErrInvalidInput):
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	case errors.Is(err, repositories.ErrNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+	case errors.Is(err, repositories.ErrConflict):
+		c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+	default:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Payment failed"})
+	}
+}
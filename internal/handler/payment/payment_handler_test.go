@@ -0,0 +1,164 @@
+package payment_test
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	handler "github.com/maithuc2003/Test_GIN_golang/internal/handler/payment"
+	"github.com/maithuc2003/Test_GIN_golang/internal/interfaces/repositories"
+	mocks "github.com/maithuc2003/Test_GIN_golang/internal/mocks/service"
+	"github.com/maithuc2003/Test_GIN_golang/internal/models"
+	"github.com/maithuc2003/Test_GIN_golang/internal/payment"
+)
+
+// withUser giả lập AuthMiddleware gán user_id vào context
+func withUser(userID int) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if userID > 0 {
+			c.Set("user_id", userID)
+		}
+		c.Next()
+	}
+}
+
+func TestPay(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	tests := []struct {
+		name           string
+		userID         int
+		path           string
+		body           string
+		expectReq      *models.PayRequest
+		mockPayment    *models.Payment
+		mockErr        error
+		expectedStatus int
+	}{
+		{
+			name: "captured", userID: 7, path: "/orders/3/pay", body: `{"token":"tok_visa"}`,
+			expectReq: &models.PayRequest{Token: "tok_visa"}, mockPayment: &models.Payment{Status: models.PaymentStatusCaptured},
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name: "empty body", userID: 7, path: "/orders/3/pay",
+			expectReq: &models.PayRequest{}, mockPayment: &models.Payment{Status: models.PaymentStatusPending},
+			expectedStatus: http.StatusAccepted,
+		},
+		{
+			name: "declined", userID: 7, path: "/orders/3/pay", body: `{"token":"tok_declined"}`,
+			expectReq: &models.PayRequest{Token: "tok_declined"}, mockPayment: &models.Payment{Status: models.PaymentStatusFailed, FailureReason: "card declined"},
+			expectedStatus: http.StatusPaymentRequired,
+		},
+		{name: "no user", path: "/orders/3/pay", expectedStatus: http.StatusUnauthorized},
+		{name: "invalid order ID", userID: 7, path: "/orders/abc/pay", expectedStatus: http.StatusBadRequest},
+		{name: "invalid JSON", userID: 7, path: "/orders/3/pay", body: `{"token":`, expectedStatus: http.StatusBadRequest},
+		{
+			name: "order not found", userID: 7, path: "/orders/3/pay", expectReq: &models.PayRequest{},
+			mockErr: fmt.Errorf("order with ID 3 %w", repositories.ErrNotFound), expectedStatus: http.StatusNotFound,
+		},
+		{
+			name: "already paid", userID: 7, path: "/orders/3/pay", expectReq: &models.PayRequest{},
+			mockErr: fmt.Errorf("%w: order 3 is paid", repositories.ErrConflict), expectedStatus: http.StatusConflict,
+		},
+		{
+			name: "provider error", userID: 7, path: "/orders/3/pay", expectReq: &models.PayRequest{},
+			mockErr: errors.New("gateway timeout"), expectedStatus: http.StatusInternalServerError,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockService := new(mocks.MockPaymentService)
+			h := handler.NewPaymentHandler(mockService)
+			if tt.expectReq != nil {
+				mockService.On("Pay", mock.Anything, uint(tt.userID), uint(3), *tt.expectReq).Return(tt.mockPayment, tt.mockErr)
+			}
+
+			router := gin.New()
+			router.POST("/orders/:id/pay", withUser(tt.userID), h.Pay)
+
+			req := httptest.NewRequest(http.MethodPost, tt.path, bytes.NewBufferString(tt.body))
+			req.Header.Set("Content-Type", "application/json")
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+
+			require.Equal(t, tt.expectedStatus, w.Code, w.Body.String())
+			mockService.AssertExpectations(t)
+		})
+	}
+}
+
+func TestGetPayments(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	tests := []struct {
+		name           string
+		userID         int
+		mockPayments   []models.Payment
+		mockErr        error
+		expectedStatus int
+	}{
+		{name: "success", userID: 7, mockPayments: []models.Payment{{ID: 1}}, expectedStatus: http.StatusOK},
+		{name: "not owner", userID: 8, mockErr: fmt.Errorf("order with ID 3 %w", repositories.ErrNotFound), expectedStatus: http.StatusNotFound},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockService := new(mocks.MockPaymentService)
+			h := handler.NewPaymentHandler(mockService)
+			mockService.On("GetPayments", mock.Anything, uint(tt.userID), uint(3)).Return(tt.mockPayments, tt.mockErr)
+
+			router := gin.New()
+			router.GET("/orders/:id/payments", withUser(tt.userID), h.GetPayments)
+
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/orders/3/payments", nil))
+
+			require.Equal(t, tt.expectedStatus, w.Code)
+			mockService.AssertExpectations(t)
+		})
+	}
+}
+
+func TestWebhook(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	payload := `{"reference":"fake_1","status":"captured"}`
+
+	tests := []struct {
+		name           string
+		mockErr        error
+		expectedStatus int
+	}{
+		{name: "processed", expectedStatus: http.StatusOK},
+		{name: "invalid signature", mockErr: payment.ErrInvalidSignature, expectedStatus: http.StatusUnauthorized},
+		{name: "unknown payment", mockErr: fmt.Errorf("%w: payment", repositories.ErrNotFound), expectedStatus: http.StatusNotFound},
+		{name: "invalid transition", mockErr: fmt.Errorf("%w: cannot move payment", repositories.ErrConflict), expectedStatus: http.StatusConflict},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockService := new(mocks.MockPaymentService)
+			h := handler.NewPaymentHandler(mockService)
+			mockService.On("HandleWebhook", mock.Anything, []byte(payload), "sig").Return(tt.mockErr)
+
+			router := gin.New()
+			router.POST("/payments/webhook", h.Webhook)
+
+			req := httptest.NewRequest(http.MethodPost, "/payments/webhook", bytes.NewBufferString(payload))
+			req.Header.Set(payment.SignatureHeader, "sig")
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+
+			require.Equal(t, tt.expectedStatus, w.Code)
+			mockService.AssertExpectations(t)
+		})
+	}
+}
@@ -0,0 +1,95 @@
+package publisher
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/maithuc2003/Test_GIN_golang/internal/interfaces/repositories"
+	"github.com/maithuc2003/Test_GIN_golang/internal/interfaces/service"
+	"github.com/maithuc2003/Test_GIN_golang/internal/models"
+)
+
+type PublisherHandler struct {
+	service service.PublisherServiceInterface
+}
+
+func NewPublisherHandler(service service.PublisherServiceInterface) *PublisherHandler {
+	return &PublisherHandler{service: service}
+}
+
+// GET /publishers
+func (h *PublisherHandler) GetAllPublishers(c *gin.Context) {
+	publishers, err := h.service.GetAllPublishers(c.Request.Context())
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, publishers)
+}
+
+// GET /publishers/:id
+func (h *PublisherHandler) GetByPublisherID(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid ID"})
+		return
+	}
+
+	publisher, err := h.service.GetByPublisherID(c.Request.Context(), uint(id))
+	if err != nil {
+		c.JSON(errorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, publisher)
+}
+
+// POST /publishers/add
+func (h *PublisherHandler) CreatePublisher(c *gin.Context) {
+	var publisher models.Publisher
+	if err := c.ShouldBindJSON(&publisher); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid JSON"})
+		return
+	}
+
+	if err := h.service.CreatePublisher(c.Request.Context(), &publisher); err != nil {
+		c.JSON(errorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusCreated, publisher)
+}
+
+// PUT /publishers/:id
+func (h *PublisherHandler) UpdateById(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid ID"})
+		return
+	}
+
+	var publisher models.Publisher
+	if err := c.ShouldBindJSON(&publisher); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid JSON"})
+		return
+	}
+	publisher.ID = uint(id)
+
+	updated, err := h.service.UpdateById(c.Request.Context(), &publisher)
+	if err != nil {
+		c.JSON(errorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, updated)
+}
+
+func errorStatus(err error) int {
+	switch {
+	case errors.Is(err, repositories.ErrNotFound):
+		return http.StatusNotFound
+	case errors.Is(err, repositories.ErrConflict):
+		return http.StatusConflict
+	default:
+		return http.StatusInternalServerError
+	}
+}
@@ -0,0 +1,133 @@
+package publisher_test
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/maithuc2003/Test_GIN_golang/internal/handler/publisher"
+	"github.com/maithuc2003/Test_GIN_golang/internal/interfaces/repositories"
+	mocks "github.com/maithuc2003/Test_GIN_golang/internal/mocks/service"
+	"github.com/maithuc2003/Test_GIN_golang/internal/models"
+)
+
+func TestCreatePublisher(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	tests := []struct {
+		name       string
+		body       string
+		mockErr    error
+		callsSvc   bool
+		wantStatus int
+	}{
+		{name: "success", body: `{"name":"NXB Trẻ","country":"VN"}`, callsSvc: true, wantStatus: http.StatusCreated},
+		{name: "invalid JSON", body: `{"name":`, wantStatus: http.StatusBadRequest},
+		{name: "duplicate", body: `{"name":"NXB Trẻ"}`, mockErr: fmt.Errorf("%w: publisher exists", repositories.ErrConflict), callsSvc: true, wantStatus: http.StatusConflict},
+		{name: "service error", body: `{"name":"NXB Trẻ"}`, mockErr: errors.New("db down"), callsSvc: true, wantStatus: http.StatusInternalServerError},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockSvc := new(mocks.MockPublisherService)
+			h := publisher.NewPublisherHandler(mockSvc)
+			if tt.callsSvc {
+				mockSvc.On("CreatePublisher", mock.Anything, mock.AnythingOfType("*models.Publisher")).Return(tt.mockErr)
+			}
+
+			req := httptest.NewRequest(http.MethodPost, "/publishers/add", bytes.NewBufferString(tt.body))
+			req.Header.Set("Content-Type", "application/json")
+			rec := httptest.NewRecorder()
+
+			r := gin.Default()
+			r.POST("/publishers/add", h.CreatePublisher)
+			r.ServeHTTP(rec, req)
+
+			require.Equal(t, tt.wantStatus, rec.Code)
+			mockSvc.AssertExpectations(t)
+		})
+	}
+}
+
+func TestGetByPublisherID(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	tests := []struct {
+		name       string
+		param      string
+		mockResult *models.Publisher
+		mockErr    error
+		wantStatus int
+	}{
+		{name: "found", param: "1", mockResult: &models.Publisher{ID: 1, Name: "NXB Trẻ"}, wantStatus: http.StatusOK},
+		{name: "invalid ID", param: "abc", wantStatus: http.StatusBadRequest},
+		{name: "not found", param: "9", mockErr: fmt.Errorf("%w: publisher with ID 9 not found", repositories.ErrNotFound), wantStatus: http.StatusNotFound},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockSvc := new(mocks.MockPublisherService)
+			h := publisher.NewPublisherHandler(mockSvc)
+			if tt.wantStatus != http.StatusBadRequest {
+				mockSvc.On("GetByPublisherID", mock.Anything, mock.AnythingOfType("uint")).Return(tt.mockResult, tt.mockErr)
+			}
+
+			req := httptest.NewRequest(http.MethodGet, "/publishers/"+tt.param, nil)
+			rec := httptest.NewRecorder()
+
+			r := gin.Default()
+			r.GET("/publishers/:id", h.GetByPublisherID)
+			r.ServeHTTP(rec, req)
+
+			require.Equal(t, tt.wantStatus, rec.Code)
+			mockSvc.AssertExpectations(t)
+		})
+	}
+}
+
+func TestUpdatePublisher(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	tests := []struct {
+		name       string
+		param      string
+		body       string
+		mockResult *models.Publisher
+		mockErr    error
+		callsSvc   bool
+		wantStatus int
+	}{
+		{name: "success", param: "1", body: `{"name":"NXB Trẻ"}`, mockResult: &models.Publisher{ID: 1, Name: "NXB Trẻ"}, callsSvc: true, wantStatus: http.StatusOK},
+		{name: "invalid ID", param: "x", body: `{}`, wantStatus: http.StatusBadRequest},
+		{name: "invalid JSON", param: "1", body: `nope`, wantStatus: http.StatusBadRequest},
+		{name: "not found", param: "9", body: `{"name":"A"}`, mockErr: fmt.Errorf("%w", repositories.ErrNotFound), callsSvc: true, wantStatus: http.StatusNotFound},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockSvc := new(mocks.MockPublisherService)
+			h := publisher.NewPublisherHandler(mockSvc)
+			if tt.callsSvc {
+				mockSvc.On("UpdateById", mock.Anything, mock.AnythingOfType("*models.Publisher")).Return(tt.mockResult, tt.mockErr)
+			}
+
+			req := httptest.NewRequest(http.MethodPut, "/publishers/"+tt.param, bytes.NewBufferString(tt.body))
+			req.Header.Set("Content-Type", "application/json")
+			rec := httptest.NewRecorder()
+
+			r := gin.Default()
+			r.PUT("/publishers/:id", h.UpdateById)
+			r.ServeHTTP(rec, req)
+
+			require.Equal(t, tt.wantStatus, rec.Code)
+			mockSvc.AssertExpectations(t)
+		})
+	}
+}
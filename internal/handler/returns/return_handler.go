@@ -0,0 +1,145 @@
+package returns
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/maithuc2003/Test_GIN_golang/internal/interfaces/repositories"
+	"github.com/maithuc2003/Test_GIN_golang/internal/interfaces/service"
+	"github.com/maithuc2003/Test_GIN_golang/internal/models"
+)
+
+type ReturnHandler struct {
+	returnService service.ReturnServiceInterface
+}
+
+func NewReturnHandler(returnService service.ReturnServiceInterface) *ReturnHandler {
+	return &ReturnHandler{returnService: returnService}
+}
+
+// POST /orders/:id/returns
+func (h *ReturnHandler) RequestReturn(c *gin.Context) {
+	userID, ok := currentUser(c)
+	if !ok {
+		return
+	}
+	orderID, ok := idParam(c, "Invalid order ID")
+	if !ok {
+		return
+	}
+	var req models.ReturnCreateRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request body"})
+		return
+	}
+	ret, err := h.returnService.RequestReturn(c.Request.Context(), userID, orderID, req)
+	if err != nil {
+		writeError(c, err)
+		return
+	}
+	c.JSON(http.StatusCreated, ret)
+}
+
+// GET /orders/:id/returns
+func (h *ReturnHandler) GetOrderReturns(c *gin.Context) {
+	userID, ok := currentUser(c)
+	if !ok {
+		return
+	}
+	orderID, ok := idParam(c, "Invalid order ID")
+	if !ok {
+		return
+	}
+	returns, err := h.returnService.GetOrderReturns(c.Request.Context(), userID, orderID)
+	if err != nil {
+		writeError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, returns)
+}
+
+// GET /returns?status=requested&order_id=
+func (h *ReturnHandler) ListReturns(c *gin.Context) {
+	var filter models.ReturnFilter
+	if err := c.ShouldBindQuery(&filter); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid filter parameters"})
+		return
+	}
+	returns, err := h.returnService.ListReturns(c.Request.Context(), filter)
+	if err != nil {
+		writeError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, returns)
+}
+
+// POST /returns/:id/approve
+func (h *ReturnHandler) Approve(c *gin.Context) {
+	h.review(c, h.returnService.Approve)
+}
+
+// POST /returns/:id/reject
+func (h *ReturnHandler) Reject(c *gin.Context) {
+	h.review(c, h.returnService.Reject)
+}
+
+type reviewFunc func(ctx context.Context, reviewerID, returnID uint, req models.ReturnReviewRequest) (*models.ReturnRequest, error)
+
+func (h *ReturnHandler) review(c *gin.Context, fn reviewFunc) {
+	reviewerID, ok := currentUser(c)
+	if !ok {
+		return
+	}
+	returnID, ok := idParam(c, "Invalid return ID")
+	if !ok {
+		return
+	}
+	var req models.ReturnReviewRequest
+	// Ghi chú là tuỳ chọn nên cho phép body rỗng
+	if c.Request.ContentLength != 0 {
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request body"})
+			return
+		}
+	}
+	ret, err := fn(c.Request.Context(), reviewerID, returnID, req)
+	if err != nil {
+		writeError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, ret)
+}
+
+func currentUser(c *gin.Context) (uint, bool) {
+	userID := c.GetInt("user_id")
+	if userID <= 0 {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "User ID not found"})
+		return 0, false
+	}
+	return uint(userID), true
+}
+
+func idParam(c *gin.Context, message string) (uint, bool) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil || id == 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": message})
+		return 0, false
+	}
+	return uint(id), true
+}
+
+func writeError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, service.ErrInvalidInput):
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	case errors.Is(err, repositories.ErrNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+	case errors.Is(err, repositories.ErrConflict):
+		c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+	default:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Return operation failed"})
+	}
+}
@@ -0,0 +1,159 @@
+package returns_test
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	handler "github.com/maithuc2003/Test_GIN_golang/internal/handler/returns"
+	"github.com/maithuc2003/Test_GIN_golang/internal/interfaces/repositories"
+	"github.com/maithuc2003/Test_GIN_golang/internal/interfaces/service"
+	mocks "github.com/maithuc2003/Test_GIN_golang/internal/mocks/service"
+	"github.com/maithuc2003/Test_GIN_golang/internal/models"
+)
+
+// withUser giả lập AuthMiddleware gán user_id vào context
+func withUser(userID int) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if userID > 0 {
+			c.Set("user_id", userID)
+		}
+		c.Next()
+	}
+}
+
+func TestRequestReturn(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	tests := []struct {
+		name           string
+		userID         int
+		path           string
+		body           string
+		expectReq      *models.ReturnCreateRequest
+		mockErr        error
+		expectedStatus int
+	}{
+		{
+			name: "created", userID: 7, path: "/orders/3/returns", body: `{"quantity":1,"reason":"damaged"}`,
+			expectReq: &models.ReturnCreateRequest{Quantity: 1, Reason: "damaged"}, expectedStatus: http.StatusCreated,
+		},
+		{name: "no user", path: "/orders/3/returns", body: `{}`, expectedStatus: http.StatusUnauthorized},
+		{name: "invalid order ID", userID: 7, path: "/orders/0/returns", body: `{}`, expectedStatus: http.StatusBadRequest},
+		{name: "invalid JSON", userID: 7, path: "/orders/3/returns", body: `{"quantity":`, expectedStatus: http.StatusBadRequest},
+		{
+			name: "too many copies", userID: 7, path: "/orders/3/returns", body: `{"quantity":9,"reason":"damaged"}`,
+			expectReq: &models.ReturnCreateRequest{Quantity: 9, Reason: "damaged"},
+			mockErr:   fmt.Errorf("%w: only 3 of 3 copies can still be returned", service.ErrInvalidInput), expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name: "order not paid", userID: 7, path: "/orders/3/returns", body: `{"quantity":1,"reason":"damaged"}`,
+			expectReq: &models.ReturnCreateRequest{Quantity: 1, Reason: "damaged"},
+			mockErr:   fmt.Errorf("%w: order 3 is pending", repositories.ErrConflict), expectedStatus: http.StatusConflict,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockService := new(mocks.MockReturnService)
+			h := handler.NewReturnHandler(mockService)
+			if tt.expectReq != nil {
+				var ret *models.ReturnRequest
+				if tt.mockErr == nil {
+					ret = &models.ReturnRequest{ID: 1, Status: models.ReturnStatusRequested}
+				}
+				mockService.On("RequestReturn", mock.Anything, uint(tt.userID), uint(3), *tt.expectReq).Return(ret, tt.mockErr)
+			}
+
+			router := gin.New()
+			router.POST("/orders/:id/returns", withUser(tt.userID), h.RequestReturn)
+
+			req := httptest.NewRequest(http.MethodPost, tt.path, bytes.NewBufferString(tt.body))
+			req.Header.Set("Content-Type", "application/json")
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+
+			require.Equal(t, tt.expectedStatus, w.Code, w.Body.String())
+			mockService.AssertExpectations(t)
+		})
+	}
+}
+
+func TestReview(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	tests := []struct {
+		name           string
+		method         string
+		path           string
+		body           string
+		expectReq      *models.ReturnReviewRequest
+		mockErr        error
+		expectedStatus int
+	}{
+		{name: "approve", method: "Approve", path: "/returns/5/approve", body: `{"note":"ok"}`, expectReq: &models.ReturnReviewRequest{Note: "ok"}, expectedStatus: http.StatusOK},
+		{name: "reject without note", method: "Reject", path: "/returns/5/reject", expectReq: &models.ReturnReviewRequest{}, expectedStatus: http.StatusOK},
+		{name: "invalid return ID", path: "/returns/x/approve", expectedStatus: http.StatusBadRequest},
+		{
+			name: "already reviewed", method: "Approve", path: "/returns/5/approve", expectReq: &models.ReturnReviewRequest{},
+			mockErr: fmt.Errorf("%w: return request 5 is already approved", repositories.ErrConflict), expectedStatus: http.StatusConflict,
+		},
+		{
+			name: "not found", method: "Reject", path: "/returns/5/reject", expectReq: &models.ReturnReviewRequest{},
+			mockErr: fmt.Errorf("%w: return request", repositories.ErrNotFound), expectedStatus: http.StatusNotFound,
+		},
+		{
+			name: "refund failed", method: "Approve", path: "/returns/5/approve", expectReq: &models.ReturnReviewRequest{},
+			mockErr: errors.New("refund failed: gateway down"), expectedStatus: http.StatusInternalServerError,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockService := new(mocks.MockReturnService)
+			h := handler.NewReturnHandler(mockService)
+			if tt.expectReq != nil {
+				var ret *models.ReturnRequest
+				if tt.mockErr == nil {
+					ret = &models.ReturnRequest{ID: 5}
+				}
+				mockService.On(tt.method, mock.Anything, uint(1), uint(5), *tt.expectReq).Return(ret, tt.mockErr)
+			}
+
+			router := gin.New()
+			router.POST("/returns/:id/approve", withUser(1), h.Approve)
+			router.POST("/returns/:id/reject", withUser(1), h.Reject)
+
+			req := httptest.NewRequest(http.MethodPost, tt.path, bytes.NewBufferString(tt.body))
+			req.Header.Set("Content-Type", "application/json")
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+
+			require.Equal(t, tt.expectedStatus, w.Code, w.Body.String())
+			mockService.AssertExpectations(t)
+		})
+	}
+}
+
+func TestListReturns(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	mockService := new(mocks.MockReturnService)
+	h := handler.NewReturnHandler(mockService)
+	mockService.On("ListReturns", mock.Anything, models.ReturnFilter{Status: models.ReturnStatusRequested}).
+		Return([]models.ReturnRequest{{ID: 1}}, nil)
+
+	router := gin.New()
+	router.GET("/returns", h.ListReturns)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/returns?status=requested", nil))
+
+	require.Equal(t, http.StatusOK, w.Code)
+	mockService.AssertExpectations(t)
+}
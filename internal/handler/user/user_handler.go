@@ -1,8 +1,13 @@
 package user
 
 import (
+	"errors"
+	"net/http"
+	"time"
+
 	"github.com/gin-gonic/gin"
 	"github.com/maithuc2003/Test_GIN_golang/internal/interfaces/service"
+	"github.com/maithuc2003/Test_GIN_golang/internal/middleware"
 	jwtutil "github.com/maithuc2003/Test_GIN_golang/pkg/jwt"
 )
 
@@ -27,7 +32,7 @@ func (h *UserHandler) GetByUsername(c *gin.Context) {
 		c.JSON(400, gin.H{"error": "Username is required"})
 		return
 	}
-	user, err := h.userService.GetByUsername(username)
+	user, err := h.userService.GetByUsername(c.Request.Context(), username)
 	if err != nil {
 		c.JSON(404, gin.H{"error": "User not found"})
 		return
@@ -50,7 +55,13 @@ func (h *UserHandler) LoginUser(c *gin.Context) {
 		return
 	}
 
-	user, err := h.userService.LoginUser(req.Username, req.Password)
+	user, err := h.userService.LoginUser(c.Request.Context(), req.Username, req.Password)
+	var locked *service.AccountLockedError
+	if errors.As(err, &locked) {
+		c.Header("Retry-After", middleware.RetryAfter(time.Until(locked.Until)))
+		c.JSON(http.StatusTooManyRequests, gin.H{"error": "Account temporarily locked due to too many failed login attempts"})
+		return
+	}
 	if err != nil {
 		c.JSON(401, gin.H{"error": "Invalid username or password"})
 		return
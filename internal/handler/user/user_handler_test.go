@@ -7,12 +7,15 @@ import (
 	"net/http"
 	"net/http/httptest"
 	"testing"
+	"time"
 
 	"github.com/gin-gonic/gin"
 	"github.com/maithuc2003/Test_GIN_golang/internal/handler/user"
+	"github.com/maithuc2003/Test_GIN_golang/internal/interfaces/service"
 	mocks "github.com/maithuc2003/Test_GIN_golang/internal/mocks/service"
 	"github.com/maithuc2003/Test_GIN_golang/internal/models"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
 )
 
 func TestGetByUsernameHandler(t *testing.T) {
@@ -61,7 +64,7 @@ func TestGetByUsernameHandler(t *testing.T) {
 
 			if tt.queryParam != "" {
 				mockUserService.
-					On("GetByUsername", tt.queryParam).
+					On("GetByUsername", mock.Anything, tt.queryParam).
 					Return(tt.mockReturnUser, tt.mockReturnErr)
 			}
 
@@ -91,6 +94,7 @@ func TestLoginUserHandler(t *testing.T) {
 		expectedCode     int
 		expectedResponse string
 		expectedContains string // dùng khi không so sánh JSON chính xác (token thay đổi)
+		expectedRetry    string
 		mockJWTFunc      func(userID uint, username string) (string, error)
 	}{
 		{
@@ -110,6 +114,17 @@ func TestLoginUserHandler(t *testing.T) {
 			expectedCode:     http.StatusUnauthorized,
 			expectedResponse: `{"error":"Invalid username or password"}`,
 		},
+		{
+			name: "Login failed - account locked",
+			requestBody: map[string]string{
+				"username": "john",
+				"password": "wrongpassword",
+			},
+			mockReturnErr:    &service.AccountLockedError{Until: time.Now().Add(90 * time.Second)},
+			expectedCode:     http.StatusTooManyRequests,
+			expectedResponse: `{"error":"Account temporarily locked due to too many failed login attempts"}`,
+			expectedRetry:    "90",
+		},
 		{
 			name: "Login success - mocked JWT",
 			requestBody: map[string]string{
@@ -175,7 +190,7 @@ func TestLoginUserHandler(t *testing.T) {
 			// Gán mock LoginUser nếu requestBody hợp lệ
 			if tt.requestBody != nil {
 				mockUserService.
-					On("LoginUser", tt.requestBody["username"], tt.requestBody["password"]).
+					On("LoginUser", mock.Anything, tt.requestBody["username"], tt.requestBody["password"]).
 					Return(tt.mockReturnUser, tt.mockReturnErr)
 			}
 
@@ -196,6 +211,7 @@ func TestLoginUserHandler(t *testing.T) {
 			router.ServeHTTP(resp, req)
 
 			assert.Equal(t, tt.expectedCode, resp.Code)
+			assert.Equal(t, tt.expectedRetry, resp.Header().Get("Retry-After"))
 
 			if tt.expectedResponse != "" {
 				assert.JSONEq(t, tt.expectedResponse, resp.Body.String())
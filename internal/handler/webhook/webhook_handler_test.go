@@ -0,0 +1,92 @@
+package webhook_test
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/maithuc2003/Test_GIN_golang/internal/handler/webhook"
+	"github.com/maithuc2003/Test_GIN_golang/internal/interfaces/repositories"
+	"github.com/maithuc2003/Test_GIN_golang/internal/interfaces/service"
+	mocks "github.com/maithuc2003/Test_GIN_golang/internal/mocks/service"
+	"github.com/maithuc2003/Test_GIN_golang/internal/models"
+)
+
+func TestCreateSubscription(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	tests := []struct {
+		name           string
+		body           string
+		wantActive     bool
+		callService    bool
+		mockErr        error
+		expectedStatus int
+	}{
+		{name: "success defaults to active", body: `{"url":"https://p.example","event_types":["OrderCreated"]}`, wantActive: true, callService: true, expectedStatus: http.StatusCreated},
+		{name: "created inactive", body: `{"url":"https://p.example","event_types":["OrderCreated"],"active":false}`, callService: true, expectedStatus: http.StatusCreated},
+		{name: "invalid JSON", body: `{"url":`, expectedStatus: http.StatusBadRequest},
+		{
+			name: "validation error", body: `{"url":"p.example","event_types":["OrderCreated"]}`, wantActive: true, callService: true,
+			mockErr: fmt.Errorf("%w: url", service.ErrInvalidInput), expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name: "db error", body: `{"url":"https://p.example","event_types":["OrderCreated"]}`, wantActive: true, callService: true,
+			mockErr: errors.New("db down"), expectedStatus: http.StatusInternalServerError,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockSvc := new(mocks.MockWebhookService)
+			h := webhook.NewWebhookHandler(mockSvc)
+			if tt.callService {
+				mockSvc.On("CreateSubscription", mock.Anything, mock.MatchedBy(func(s *models.WebhookSubscription) bool {
+					return s.Active == tt.wantActive
+				})).Return(tt.mockErr)
+			}
+
+			req := httptest.NewRequest(http.MethodPost, "/webhooks", bytes.NewBufferString(tt.body))
+			req.Header.Set("Content-Type", "application/json")
+			rec := httptest.NewRecorder()
+
+			r := gin.Default()
+			r.POST("/webhooks", h.CreateSubscription)
+			r.ServeHTTP(rec, req)
+
+			require.Equal(t, tt.expectedStatus, rec.Code)
+			mockSvc.AssertExpectations(t)
+		})
+	}
+}
+
+func TestRedeliver(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	mockSvc := new(mocks.MockWebhookService)
+	h := webhook.NewWebhookHandler(mockSvc)
+	mockSvc.On("Redeliver", mock.Anything, uint(1), uint(5)).Return(&models.WebhookDelivery{ID: 5, Status: models.WebhookDeliveryPending}, nil)
+	mockSvc.On("Redeliver", mock.Anything, uint(1), uint(6)).Return(nil, fmt.Errorf("%w: webhook delivery 6 not found", repositories.ErrNotFound))
+
+	r := gin.Default()
+	r.POST("/webhooks/:id/deliveries/:delivery_id/redeliver", h.Redeliver)
+
+	for path, want := range map[string]int{
+		"/webhooks/1/deliveries/5/redeliver": http.StatusAccepted,
+		"/webhooks/1/deliveries/6/redeliver": http.StatusNotFound,
+		"/webhooks/1/deliveries/x/redeliver": http.StatusBadRequest,
+		"/webhooks/0/deliveries/5/redeliver": http.StatusBadRequest,
+	} {
+		rec := httptest.NewRecorder()
+		r.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, path, nil))
+		require.Equal(t, want, rec.Code, path)
+	}
+	mockSvc.AssertExpectations(t)
+}
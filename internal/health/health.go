@@ -0,0 +1,74 @@
+// Package health kiểm tra các phụ thuộc (database, cache) cho endpoint readiness.
+package health
+
+import (
+	"context"
+	"sync"
+
+	"github.com/maithuc2003/Test_GIN_golang/internal/redis"
+	"gorm.io/gorm"
+)
+
+// Check trả về lỗi khi phụ thuộc chưa sẵn sàng
+type Check func(ctx context.Context) error
+
+// Result là kết quả của một lần kiểm tra; Checks chứa lỗi theo tên check (nil là đạt)
+type Result struct {
+	Ready  bool
+	Checks map[string]error
+}
+
+// Checker chạy song song các check đã đăng ký
+type Checker struct {
+	names  []string
+	checks []Check
+}
+
+func NewChecker() *Checker {
+	return &Checker{}
+}
+
+// Add đăng ký check với tên hiển thị trong /readyz
+func (c *Checker) Add(name string, check Check) {
+	c.names = append(c.names, name)
+	c.checks = append(c.checks, check)
+}
+
+// Run chạy mọi check, mỗi check dùng chung deadline của ctx
+func (c *Checker) Run(ctx context.Context) Result {
+	errs := make([]error, len(c.checks))
+	var wg sync.WaitGroup
+	for i, check := range c.checks {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			errs[i] = check(ctx)
+		}()
+	}
+	wg.Wait()
+
+	result := Result{Ready: true, Checks: make(map[string]error, len(errs))}
+	for i, err := range errs {
+		result.Checks[c.names[i]] = err
+		if err != nil {
+			result.Ready = false
+		}
+	}
+	return result
+}
+
+// DB ping connection pool của GORM
+func DB(db *gorm.DB) Check {
+	return func(ctx context.Context) error {
+		sqlDB, err := db.DB()
+		if err != nil {
+			return err
+		}
+		return sqlDB.PingContext(ctx)
+	}
+}
+
+// Redis gửi PING qua client và chờ PONG
+func Redis(client *redis.Client) Check {
+	return client.Ping
+}
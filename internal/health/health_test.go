@@ -0,0 +1,88 @@
+package health_test
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+
+	"github.com/maithuc2003/Test_GIN_golang/internal/health"
+	"github.com/maithuc2003/Test_GIN_golang/internal/redis"
+	sqlitedriver "gorm.io/driver/sqlite"
+
+	_ "modernc.org/sqlite"
+)
+
+func TestChecker_Run(t *testing.T) {
+	down := errors.New("down")
+	checker := health.NewChecker()
+	checker.Add("database", func(context.Context) error { return nil })
+	checker.Add("cache", func(context.Context) error { return down })
+
+	result := checker.Run(context.Background())
+	require.False(t, result.Ready)
+	require.NoError(t, result.Checks["database"])
+	require.ErrorIs(t, result.Checks["cache"], down)
+
+	require.True(t, health.NewChecker().Run(context.Background()).Ready, "no checks means ready")
+}
+
+func TestDB(t *testing.T) {
+	dsn := fmt.Sprintf("file:testdb_%d?mode=memory&cache=shared", time.Now().UnixNano())
+	db, err := gorm.Open(sqlitedriver.New(sqlitedriver.Config{DSN: dsn, DriverName: "sqlite"}), &gorm.Config{})
+	require.NoError(t, err)
+
+	check := health.DB(db)
+	require.NoError(t, check(context.Background()))
+
+	sqlDB, err := db.DB()
+	require.NoError(t, err)
+	require.NoError(t, sqlDB.Close())
+	require.Error(t, check(context.Background()))
+}
+
+// fakeRedis trả lời mỗi lệnh bằng reply rồi đóng kết nối
+func fakeRedis(t *testing.T, reply string) string {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	t.Cleanup(func() { ln.Close() })
+	go func() {
+		for {
+			conn, err := ln.Accept()
+			if err != nil {
+				return
+			}
+			// Đọc đủ lệnh PING (3 dòng RESP) trước khi trả lời
+			r := bufio.NewReader(conn)
+			for range 3 {
+				if _, err := r.ReadString('\n'); err != nil {
+					break
+				}
+			}
+			conn.Write([]byte(reply))
+			conn.Close()
+		}
+	}()
+	return ln.Addr().String()
+}
+
+func TestRedis(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+
+	require.NoError(t, health.Redis(redis.NewClient(redis.Config{Addr: fakeRedis(t, "+PONG\r\n")}))(ctx))
+	require.ErrorContains(t, health.Redis(redis.NewClient(redis.Config{Addr: fakeRedis(t, "-NOAUTH Authentication required.\r\n")}))(ctx), "NOAUTH")
+
+	// Cổng không có ai lắng nghe
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	addr := ln.Addr().String()
+	ln.Close()
+	require.Error(t, health.Redis(redis.NewClient(redis.Config{Addr: addr}))(ctx))
+}
@@ -1,11 +1,18 @@
 package repositories
 
-import "github.com/maithuc2003/Test_GIN_golang/internal/models"
+import (
+	"context"
+
+	"github.com/maithuc2003/Test_GIN_golang/internal/models"
+)
 
 type AuthorRepositoriesInterface interface {
-	GetByAuthorID(id int) (*models.Author, error)
-	GetAllAuthors() ([]*models.Author, error)
-	CreateAuthor(author *models.Author) error
-	UpdateById(author *models.Author) (*models.Author, error)
-	DeleteById(id int) (*models.Author, error)
+	GetByAuthorID(ctx context.Context, id int) (*models.Author, error)
+	GetAllAuthors(ctx context.Context, filter models.AuthorFilter) ([]*models.Author, error)
+	GetByName(ctx context.Context, name string) (*models.Author, error)
+	CreateAuthor(ctx context.Context, author *models.Author) error
+	UpdateById(ctx context.Context, author *models.Author) (*models.Author, error)
+	DeleteById(ctx context.Context, id int) (*models.Author, error)
+	// Merge chuyển sách và alias của các tác giả nguồn sang tác giả đích rồi xoá nguồn
+	Merge(ctx context.Context, targetID int, sourceIDs []int) (*models.AuthorMergeResult, error)
 }
@@ -1,11 +1,25 @@
 package repositories
 
-import "github.com/maithuc2003/Test_GIN_golang/internal/models"
+import (
+	"context"
+
+	"github.com/maithuc2003/Test_GIN_golang/internal/models"
+)
 
 type BookRepository interface {
-	CreateBook(book *models.Book) error
-	GetAllBooks() ([]models.Book, error)
-	GetByBookID(id int) (*models.Book, error)
-	DeleteById(id int) (*models.Book, error)
-	UpdateById(book *models.Book) (*models.Book, error)
+	CreateBook(ctx context.Context, book *models.Book) error
+	GetAllBooks(ctx context.Context, filter models.BookFilter) ([]models.Book, error)
+	GetByBookID(ctx context.Context, id int) (*models.Book, error)
+	GetByBookIDWithAuthors(ctx context.Context, id int) (*models.Book, error)
+	GetBooksByAuthor(ctx context.Context, authorID int, filter models.BookFilter) ([]models.Book, error)
+	GetBooksByCategory(ctx context.Context, categoryID uint, filter models.BookFilter) ([]models.Book, error)
+	CategoryFacets(ctx context.Context, filter models.BookFilter) ([]models.CategoryFacet, error)
+	// UpdateCover lưu thư mục ảnh bìa (key) và URL ảnh bìa của sách
+	UpdateCover(ctx context.Context, id int, key string, cover *models.BookCover) error
+	// Restock cộng quantity cuốn trả lại vào tồn kho
+	Restock(ctx context.Context, id int, quantity int) error
+	GetByTitleAndAuthor(ctx context.Context, title string, authorID int) (*models.Book, error)
+	DeleteById(ctx context.Context, id int) (*models.Book, error)
+	// UpdateById ghi đè mọi cột sửa được (trừ ảnh bìa); trường rỗng hoặc nil xoá giá trị cũ
+	UpdateById(ctx context.Context, book *models.Book) (*models.Book, error)
 }
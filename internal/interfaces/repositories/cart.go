@@ -0,0 +1,20 @@
+package repositories
+
+import (
+	"context"
+	"time"
+
+	"github.com/maithuc2003/Test_GIN_golang/internal/models"
+)
+
+type CartRepository interface {
+	// GetCart trả về giỏ (kèm Items.Book) của user, ErrNotFound nếu chưa có giỏ
+	GetCart(ctx context.Context, userID uint) (*models.Cart, error)
+	// SaveItem đặt số lượng của sách trong giỏ (tạo giỏ nếu chưa có) và gia hạn giỏ tới expiresAt
+	SaveItem(ctx context.Context, userID, bookID uint, quantity int, expiresAt time.Time) error
+	// RemoveItem bỏ sách khỏi giỏ, ErrNotFound nếu sách không có trong giỏ
+	RemoveItem(ctx context.Context, userID, bookID uint, expiresAt time.Time) error
+	Clear(ctx context.Context, userID uint) error
+	// DeleteExpired xoá các giỏ hết hạn trước now, trả về số giỏ đã xoá
+	DeleteExpired(ctx context.Context, now time.Time) (int64, error)
+}
@@ -0,0 +1,17 @@
+package repositories
+
+import (
+	"context"
+
+	"github.com/maithuc2003/Test_GIN_golang/internal/models"
+)
+
+type CategoryRepository interface {
+	GetAllCategories(ctx context.Context) ([]models.Category, error)
+	GetByCategoryID(ctx context.Context, id uint) (*models.Category, error)
+	// DescendantIDs trả về id của thể loại id và toàn bộ thể loại con cháu
+	DescendantIDs(ctx context.Context, id uint) ([]uint, error)
+	CreateCategory(ctx context.Context, category *models.Category) error
+	UpdateById(ctx context.Context, category *models.Category) (*models.Category, error)
+	DeleteById(ctx context.Context, id uint) (*models.Category, error)
+}
@@ -0,0 +1,25 @@
+package repositories
+
+import (
+	"context"
+
+	"github.com/maithuc2003/Test_GIN_golang/internal/models"
+)
+
+type CouponRepository interface {
+	List(ctx context.Context) ([]models.Coupon, error)
+	GetByID(ctx context.Context, id uint) (*models.Coupon, error)
+	// GetByCode tìm theo mã đã chuẩn hoá (chữ hoa); ErrNotFound nếu không có
+	GetByCode(ctx context.Context, code string) (*models.Coupon, error)
+	Create(ctx context.Context, coupon *models.Coupon) error
+	Update(ctx context.Context, coupon *models.Coupon) (*models.Coupon, error)
+	// Delete xoá coupon chưa từng được dùng; ErrConflict nếu đã có đơn dùng
+	Delete(ctx context.Context, id uint) (*models.Coupon, error)
+	// BookCategoryIDs trả về các thể loại của sách kèm toàn bộ thể loại cha của chúng
+	BookCategoryIDs(ctx context.Context, bookID uint) ([]uint, error)
+	// Redeem khoá dòng coupon, kiểm tra giới hạn tổng và theo user rồi ghi nhận một lần dùng;
+	// ErrConflict nếu đã hết lượt
+	Redeem(ctx context.Context, couponID, userID, orderID uint) error
+	// Release trả lại các lượt dùng coupon của đơn bị huỷ
+	Release(ctx context.Context, orderID uint) error
+}
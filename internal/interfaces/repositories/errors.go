@@ -0,0 +1,13 @@
+package repositories
+
+import "errors"
+
+// ErrConflict được trả về khi thao tác vi phạm ràng buộc unique trong DB.
+// Handler dùng errors.Is để trả về 409.
+var ErrConflict = errors.New("conflict")
+
+// ErrNotFound được trả về khi bản ghi được tham chiếu không tồn tại.
+var ErrNotFound = errors.New("not found")
+
+// ErrInsufficientStock được trả về khi tồn kho không đủ cho số lượng yêu cầu.
+var ErrInsufficientStock = errors.New("not enough stock available")
@@ -0,0 +1,14 @@
+package repositories
+
+import (
+	"context"
+
+	"github.com/maithuc2003/Test_GIN_golang/internal/models"
+)
+
+// ExportRepository đọc dữ liệu theo từng batch (keyset theo id) để export với bộ nhớ cố định
+type ExportRepository interface {
+	StreamBooks(ctx context.Context, filter models.BookFilter, batchSize int, fn func([]models.Book) error) error
+	StreamAuthors(ctx context.Context, filter models.AuthorFilter, batchSize int, fn func([]models.Author) error) error
+	StreamOrders(ctx context.Context, filter models.OrderFilter, batchSize int, fn func([]models.OrderExportRow) error) error
+}
@@ -0,0 +1,17 @@
+package repositories
+
+import (
+	"context"
+
+	"github.com/maithuc2003/Test_GIN_golang/internal/models"
+)
+
+type InvoiceRepository interface {
+	// GetByOrderID trả về hoá đơn đã phát hành của đơn; ErrNotFound nếu chưa có
+	GetByOrderID(ctx context.Context, orderID uint) (*models.Invoice, error)
+	// NextNumber cấp số tiếp theo của dãy series; cần gọi trong transaction cùng Create
+	// để số bị trả lại khi phát hành thất bại
+	NextNumber(ctx context.Context, series string) (int64, error)
+	// Create lưu hoá đơn; ErrConflict nếu đơn đã có hoá đơn
+	Create(ctx context.Context, invoice *models.Invoice) error
+}
@@ -1,11 +1,20 @@
 package repositories
 
-import "github.com/maithuc2003/Test_GIN_golang/internal/models"
+import (
+	"context"
+
+	"github.com/maithuc2003/Test_GIN_golang/internal/models"
+)
 
 type OrderRepositoryInterface interface {
-	GetByOrderID(id uint) (*models.Order, error)
-	GetAllOrders() ([]*models.Order, error)
-	UpdateByOrderID(order *models.Order) (*models.Order, error)
-	DeleteByOrderID(id uint) (*models.Order, error)
-	Create(order *models.Order) error
+	GetByOrderID(ctx context.Context, id uint) (*models.Order, error)
+	// GetForUpdate đọc đơn và khóa dòng tới hết transaction để các thao tác trên cùng
+	// đơn chạy lần lượt; chỉ có tác dụng khi gọi trong transaction
+	GetForUpdate(ctx context.Context, id uint) (*models.Order, error)
+	GetAllOrders(ctx context.Context, filter models.OrderFilter) ([]*models.Order, error)
+	UpdateByOrderID(ctx context.Context, order *models.Order) (*models.Order, error)
+	DeleteByOrderID(ctx context.Context, id uint) (*models.Order, error)
+	Create(ctx context.Context, order *models.Order) error
+	// UpdateStatus chỉ chuyển trạng thái khi đơn đang ở from; ErrConflict nếu trạng thái đã khác
+	UpdateStatus(ctx context.Context, id uint, from, to string) error
 }
@@ -0,0 +1,17 @@
+package repositories
+
+import (
+	"context"
+	"time"
+
+	"github.com/maithuc2003/Test_GIN_golang/internal/models"
+)
+
+type OutboxRepository interface {
+	// Claim lấy tối đa limit event chưa giao đã tới hạn (theo thứ tự ghi) và dời hạn của
+	// chúng thêm lease để dispatcher khác không lấy trùng trong lúc đang giao
+	Claim(ctx context.Context, now time.Time, lease time.Duration, limit int) ([]models.OutboxEvent, error)
+	MarkDelivered(ctx context.Context, id uint, at time.Time) error
+	// MarkFailed tăng số lần thử và hẹn lần giao tiếp theo
+	MarkFailed(ctx context.Context, id uint, nextAttemptAt time.Time, reason string) error
+}
@@ -0,0 +1,20 @@
+package repositories
+
+import (
+	"context"
+
+	"github.com/maithuc2003/Test_GIN_golang/internal/models"
+	"github.com/shopspring/decimal"
+)
+
+type PaymentRepository interface {
+	Create(ctx context.Context, payment *models.Payment) error
+	// Update ghi lại trạng thái, lý do lỗi và số tiền đã hoàn của payment
+	Update(ctx context.Context, payment *models.Payment) error
+	// AddRefund cộng amount vào số tiền đã hoàn bằng một câu UPDATE (chuyển sang refunded
+	// khi hoàn đủ) rồi trả về payment sau khi cập nhật
+	AddRefund(ctx context.Context, id uint, amount decimal.Decimal) (*models.Payment, error)
+	// GetByReference tìm payment theo mã giao dịch của cổng thanh toán; ErrNotFound nếu không có
+	GetByReference(ctx context.Context, provider, reference string) (*models.Payment, error)
+	ListByOrder(ctx context.Context, orderID uint) ([]models.Payment, error)
+}
@@ -0,0 +1,14 @@
+package repositories
+
+import (
+	"context"
+
+	"github.com/maithuc2003/Test_GIN_golang/internal/models"
+)
+
+type PublisherRepository interface {
+	GetAllPublishers(ctx context.Context) ([]models.Publisher, error)
+	GetByPublisherID(ctx context.Context, id uint) (*models.Publisher, error)
+	CreatePublisher(ctx context.Context, publisher *models.Publisher) error
+	UpdateById(ctx context.Context, publisher *models.Publisher) (*models.Publisher, error)
+}
@@ -0,0 +1,17 @@
+package repositories
+
+import (
+	"context"
+	"time"
+)
+
+type ReservationRepository interface {
+	// Commit trừ hẳn Book.Stock theo reservation của đơn rồi xoá reservation;
+	// ErrNotFound nếu đơn không còn reservation (đã hết hạn hoặc đã commit)
+	Commit(ctx context.Context, orderID uint) error
+	// Release trả lại hàng đang giữ cho đơn; không có reservation thì không lỗi
+	Release(ctx context.Context, orderID uint) error
+	// ReleaseExpired trả lại hàng của các reservation hết hạn trước now và chuyển
+	// các đơn pending tương ứng sang expired; trả về số reservation đã giải phóng
+	ReleaseExpired(ctx context.Context, now time.Time) (int64, error)
+}
@@ -0,0 +1,17 @@
+package repositories
+
+import (
+	"context"
+
+	"github.com/maithuc2003/Test_GIN_golang/internal/models"
+)
+
+type ReturnRepository interface {
+	Create(ctx context.Context, ret *models.ReturnRequest) error
+	GetByID(ctx context.Context, id uint) (*models.ReturnRequest, error)
+	List(ctx context.Context, filter models.ReturnFilter) ([]models.ReturnRequest, error)
+	// ReturnedQuantity cộng số lượng các yêu cầu của đơn có trạng thái thuộc statuses
+	ReturnedQuantity(ctx context.Context, orderID uint, statuses ...string) (int, error)
+	// SaveReview ghi kết quả duyệt khi yêu cầu vẫn đang requested; ErrConflict nếu đã được xử lý
+	SaveReview(ctx context.Context, ret *models.ReturnRequest) error
+}
@@ -0,0 +1,23 @@
+package repositories
+
+import "context"
+
+// Repositories gom các repository được gắn vào cùng một transaction.
+type Repositories struct {
+	Authors      AuthorRepositoriesInterface
+	Books        BookRepository
+	Carts        CartRepository
+	Coupons      CouponRepository
+	Invoices     InvoiceRepository
+	Orders       OrderRepositoryInterface
+	Payments     PaymentRepository
+	Reservations ReservationRepository
+	Returns      ReturnRepository
+	Users        UserRepository
+}
+
+// TransactionManager chạy fn trong một transaction; fn trả lỗi hoặc panic thì rollback.
+// Gọi lồng nhau với ctx nhận được từ fn sẽ tạo savepoint thay vì transaction mới.
+type TransactionManager interface {
+	WithinTransaction(ctx context.Context, fn func(ctx context.Context, repos Repositories) error) error
+}
@@ -1,8 +1,19 @@
 package repositories
 
-import "github.com/maithuc2003/Test_GIN_golang/internal/models"
+import (
+	"context"
+	"time"
+
+	"github.com/maithuc2003/Test_GIN_golang/internal/models"
+)
 
 type UserRepository interface {
-	GetByUsername(username string) (*models.User, error)
-	LoginUser(username string, password string) (*models.User, error)
+	GetByID(ctx context.Context, id uint) (*models.User, error)
+	GetByUsername(ctx context.Context, username string) (*models.User, error)
+	LoginUser(ctx context.Context, username string, password string) (*models.User, error)
+	// RecordFailedLogin tăng số lần đăng nhập sai liên tiếp và trả về giá trị mới
+	RecordFailedLogin(ctx context.Context, id uint) (int, error)
+	LockUntil(ctx context.Context, id uint, until time.Time) error
+	// ResetFailedLogins xoá bộ đếm và khoá sau khi đăng nhập thành công
+	ResetFailedLogins(ctx context.Context, id uint) error
 }
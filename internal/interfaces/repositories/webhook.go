@@ -0,0 +1,31 @@
+package repositories
+
+import (
+	"context"
+	"time"
+
+	"github.com/maithuc2003/Test_GIN_golang/internal/models"
+)
+
+type WebhookRepository interface {
+	ListSubscriptions(ctx context.Context) ([]models.WebhookSubscription, error)
+	// ActiveSubscriptions trả về các subscription đang bật đăng ký eventType
+	ActiveSubscriptions(ctx context.Context, eventType string) ([]models.WebhookSubscription, error)
+	GetSubscription(ctx context.Context, id uint) (*models.WebhookSubscription, error)
+	CreateSubscription(ctx context.Context, sub *models.WebhookSubscription) error
+	// UpdateSubscription giữ secret cũ nếu sub.Secret rỗng
+	UpdateSubscription(ctx context.Context, sub *models.WebhookSubscription) (*models.WebhookSubscription, error)
+	// DeleteSubscription xoá subscription cùng lịch sử giao của nó
+	DeleteSubscription(ctx context.Context, id uint) (*models.WebhookSubscription, error)
+
+	// EnqueueDeliveries tạo các delivery mới; delivery trùng (cùng subscription và event) bị bỏ qua
+	EnqueueDeliveries(ctx context.Context, deliveries []models.WebhookDelivery) error
+	// ClaimDeliveries lấy tối đa limit delivery pending đã tới hạn và dời hạn thêm lease
+	ClaimDeliveries(ctx context.Context, now time.Time, lease time.Duration, limit int) ([]models.WebhookDelivery, error)
+	// RecordAttempt lưu lần gửi vào log và cập nhật trạng thái delivery
+	RecordAttempt(ctx context.Context, delivery *models.WebhookDelivery, attempt *models.WebhookDeliveryAttempt) error
+	// ListDeliveries trả về tối đa limit delivery mới nhất của subscription, kèm log
+	ListDeliveries(ctx context.Context, subscriptionID uint, limit int) ([]models.WebhookDelivery, error)
+	// Redeliver đưa delivery về pending để gửi lại ngay, log các lần trước được giữ nguyên
+	Redeliver(ctx context.Context, subscriptionID, deliveryID uint, now time.Time) (*models.WebhookDelivery, error)
+}
@@ -1,12 +1,17 @@
 package service
 
-import "github.com/maithuc2003/Test_GIN_golang/internal/models"
+import (
+	"context"
 
+	"github.com/maithuc2003/Test_GIN_golang/internal/models"
+)
 
 type AuthorServiceInterface interface {
-	CreateAuthor(author *models.Author) error
-	GetAllAuthors() ([]*models.Author, error)
-	GetByAuthorID(id int) (*models.Author, error)
-	DeleteById(id int) (*models.Author, error)
-	UpdateById(author *models.Author) (*models.Author, error)
+	CreateAuthor(ctx context.Context, author *models.Author) error
+	CreateAuthorWithBooks(ctx context.Context, author *models.Author, books []*models.Book) error
+	GetAllAuthors(ctx context.Context, filter models.AuthorFilter) ([]*models.Author, error)
+	GetByAuthorID(ctx context.Context, id int) (*models.Author, error)
+	DeleteById(ctx context.Context, id int) (*models.Author, error)
+	UpdateById(ctx context.Context, author *models.Author) (*models.Author, error)
+	MergeAuthors(ctx context.Context, targetID int, sourceIDs []int) (*models.AuthorMergeResult, error)
 }
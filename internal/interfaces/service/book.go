@@ -1,11 +1,19 @@
 package service
 
-import "github.com/maithuc2003/Test_GIN_golang/internal/models"
+import (
+	"context"
+
+	"github.com/maithuc2003/Test_GIN_golang/internal/models"
+)
 
 type BookServiceInterface interface {
-	CreateBook(book *models.Book) error
-	GetAllBooks() ([]models.Book, error)
-	GetByBookID(id int) (*models.Book, error)
-	DeleteById(id int) (*models.Book, error)
-	UpdateById(book *models.Book) (*models.Book, error)
+	CreateBook(ctx context.Context, book *models.Book) error
+	GetAllBooks(ctx context.Context, filter models.BookFilter) ([]models.Book, error)
+	GetByBookID(ctx context.Context, id int) (*models.Book, error)
+	GetByBookIDWithAuthors(ctx context.Context, id int) (*models.Book, error)
+	GetBooksByAuthor(ctx context.Context, authorID int, filter models.BookFilter) ([]models.Book, error)
+	GetBooksByCategory(ctx context.Context, categoryID uint, filter models.BookFilter) ([]models.Book, error)
+	GetCategoryFacets(ctx context.Context, filter models.BookFilter) ([]models.CategoryFacet, error)
+	DeleteById(ctx context.Context, id int) (*models.Book, error)
+	UpdateById(ctx context.Context, book *models.Book) (*models.Book, error)
 }
@@ -0,0 +1,21 @@
+package service
+
+import (
+	"context"
+
+	"github.com/maithuc2003/Test_GIN_golang/internal/models"
+)
+
+type CartServiceInterface interface {
+	// GetCart trả về giỏ hiện tại; giỏ chưa có hoặc đã hết hạn được trả về rỗng
+	GetCart(ctx context.Context, userID uint) (*models.CartView, error)
+	// AddItem cộng thêm số lượng sách vào giỏ
+	AddItem(ctx context.Context, userID uint, item models.CartItemRequest) (*models.CartView, error)
+	// UpdateItem đặt lại số lượng sách trong giỏ; quantity = 0 là bỏ sách khỏi giỏ
+	UpdateItem(ctx context.Context, userID uint, item models.CartItemRequest) (*models.CartView, error)
+	RemoveItem(ctx context.Context, userID, bookID uint) (*models.CartView, error)
+	// Checkout tạo một đơn hàng cho mỗi dòng trong giỏ (tất cả hoặc không) rồi xoá giỏ
+	Checkout(ctx context.Context, userID uint) ([]*models.Order, error)
+	// PurgeExpired dọn các giỏ đã hết hạn, trả về số giỏ đã xoá
+	PurgeExpired(ctx context.Context) (int64, error)
+}
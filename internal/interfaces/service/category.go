@@ -0,0 +1,16 @@
+package service
+
+import (
+	"context"
+
+	"github.com/maithuc2003/Test_GIN_golang/internal/models"
+)
+
+type CategoryServiceInterface interface {
+	// GetCategoryTree trả về các thể loại gốc, mỗi nút kèm Children
+	GetCategoryTree(ctx context.Context) ([]models.Category, error)
+	GetByCategoryID(ctx context.Context, id uint) (*models.Category, error)
+	CreateCategory(ctx context.Context, category *models.Category) error
+	UpdateById(ctx context.Context, category *models.Category) (*models.Category, error)
+	DeleteById(ctx context.Context, id uint) (*models.Category, error)
+}
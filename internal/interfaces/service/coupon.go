@@ -0,0 +1,16 @@
+package service
+
+import (
+	"context"
+
+	"github.com/maithuc2003/Test_GIN_golang/internal/models"
+)
+
+type CouponServiceInterface interface {
+	GetAllCoupons(ctx context.Context) ([]models.Coupon, error)
+	GetByCouponID(ctx context.Context, id uint) (*models.Coupon, error)
+	CreateCoupon(ctx context.Context, coupon *models.Coupon) error
+	UpdateById(ctx context.Context, coupon *models.Coupon) (*models.Coupon, error)
+	// DeleteById chỉ xoá được coupon chưa dùng; coupon đã dùng thì tắt Active
+	DeleteById(ctx context.Context, id uint) (*models.Coupon, error)
+}
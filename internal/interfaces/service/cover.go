@@ -0,0 +1,15 @@
+package service
+
+import (
+	"context"
+
+	"github.com/maithuc2003/Test_GIN_golang/internal/models"
+)
+
+// MaxCoverSize là dung lượng tối đa của một file ảnh bìa (5 MB)
+const MaxCoverSize = 5 << 20
+
+type CoverServiceInterface interface {
+	// UploadCover lưu ảnh bìa kèm các thumbnail cho sách và trả về sách đã cập nhật
+	UploadCover(ctx context.Context, bookID int, data []byte) (*models.Book, error)
+}
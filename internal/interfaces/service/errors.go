@@ -0,0 +1,25 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"time"
+)
+
+// ErrInvalidInput được service trả về (bọc bằng %w) khi dữ liệu đầu vào không hợp lệ.
+// Handler dùng errors.Is để trả về 400 thay vì 500.
+var ErrInvalidInput = errors.New("invalid input")
+
+// ErrAccountLocked được trả về khi tài khoản đang bị khoá tạm thời do đăng nhập sai nhiều lần
+var ErrAccountLocked = errors.New("account temporarily locked")
+
+// AccountLockedError cho biết tài khoản bị khoá tới Until; errors.Is khớp với ErrAccountLocked
+type AccountLockedError struct {
+	Until time.Time
+}
+
+func (e *AccountLockedError) Error() string {
+	return fmt.Sprintf("%s until %s", ErrAccountLocked, e.Until.Format(time.RFC3339))
+}
+
+func (e *AccountLockedError) Is(target error) bool { return target == ErrAccountLocked }
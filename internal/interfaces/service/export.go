@@ -0,0 +1,14 @@
+package service
+
+import (
+	"context"
+	"io"
+
+	"github.com/maithuc2003/Test_GIN_golang/internal/models"
+)
+
+type ExportServiceInterface interface {
+	ExportBooks(ctx context.Context, w io.Writer, format string, filter models.BookFilter) error
+	ExportAuthors(ctx context.Context, w io.Writer, format string, filter models.AuthorFilter) error
+	ExportOrders(ctx context.Context, w io.Writer, format string, filter models.OrderFilter) error
+}
@@ -0,0 +1,12 @@
+package service
+
+import (
+	"context"
+	"io"
+
+	"github.com/maithuc2003/Test_GIN_golang/internal/models"
+)
+
+type ImportServiceInterface interface {
+	ImportBooks(ctx context.Context, r io.Reader, opts models.ImportOptions) (*models.ImportReport, error)
+}
@@ -0,0 +1,13 @@
+package service
+
+import (
+	"context"
+
+	"github.com/maithuc2003/Test_GIN_golang/internal/models"
+)
+
+type InvoiceServiceInterface interface {
+	// GetInvoice trả về hoá đơn của đơn thuộc userID, phát hành ở lần gọi đầu tiên
+	// sau khi đơn đã thanh toán; các lần sau trả về đúng bản đã lưu
+	GetInvoice(ctx context.Context, userID, orderID uint) (*models.Invoice, error)
+}
@@ -1,11 +1,17 @@
 package service
 
-import "github.com/maithuc2003/Test_GIN_golang/internal/models"
+import (
+	"context"
+
+	"github.com/maithuc2003/Test_GIN_golang/internal/models"
+)
 
 type OrderServiceInterface interface {
-	CreateOrder(order *models.Order) error
-	GetAllOrders() ([]*models.Order, error)
-	GetByOrderID(id int) (*models.Order, error)
-	DeleteByOrderID(id int) (*models.Order, error)
-	UpdateByOrderID(order *models.Order) (*models.Order, error)
+	CreateOrder(ctx context.Context, order *models.Order) error
+	GetAllOrders(ctx context.Context, filter models.OrderFilter) ([]*models.Order, error)
+	GetByOrderID(ctx context.Context, id int) (*models.Order, error)
+	DeleteByOrderID(ctx context.Context, id int) (*models.Order, error)
+	UpdateByOrderID(ctx context.Context, order *models.Order) (*models.Order, error)
+	// ReleaseExpiredReservations huỷ các đơn pending quá hạn giữ hàng, trả về số reservation đã giải phóng
+	ReleaseExpiredReservations(ctx context.Context) (int64, error)
 }
@@ -0,0 +1,15 @@
+package service
+
+import (
+	"context"
+
+	"github.com/maithuc2003/Test_GIN_golang/internal/models"
+)
+
+type PaymentServiceInterface interface {
+	// Pay thanh toán đơn pending của userID; thẻ bị từ chối trả về payment failed (không lỗi)
+	Pay(ctx context.Context, userID, orderID uint, req models.PayRequest) (*models.Payment, error)
+	// HandleWebhook áp dụng kết quả cổng thanh toán gửi về; gửi lại cùng sự kiện không có tác dụng
+	HandleWebhook(ctx context.Context, payload []byte, signature string) error
+	GetPayments(ctx context.Context, userID, orderID uint) ([]models.Payment, error)
+}
@@ -0,0 +1,18 @@
+package service
+
+import (
+	"context"
+
+	"github.com/maithuc2003/Test_GIN_golang/internal/models"
+)
+
+type ReturnServiceInterface interface {
+	// RequestReturn tạo yêu cầu trả hàng cho đơn đã thanh toán của userID
+	RequestReturn(ctx context.Context, userID, orderID uint, req models.ReturnCreateRequest) (*models.ReturnRequest, error)
+	GetOrderReturns(ctx context.Context, userID, orderID uint) ([]models.ReturnRequest, error)
+	// ListReturns dành cho nhân viên, lọc theo đơn và trạng thái
+	ListReturns(ctx context.Context, filter models.ReturnFilter) ([]models.ReturnRequest, error)
+	// Approve nhập lại kho số lượng trả và hoàn tiền tương ứng qua cổng thanh toán
+	Approve(ctx context.Context, reviewerID, returnID uint, req models.ReturnReviewRequest) (*models.ReturnRequest, error)
+	Reject(ctx context.Context, reviewerID, returnID uint, req models.ReturnReviewRequest) (*models.ReturnRequest, error)
+}
@@ -1,8 +1,12 @@
 package service
 
-import "github.com/maithuc2003/Test_GIN_golang/internal/models"
+import (
+	"context"
+
+	"github.com/maithuc2003/Test_GIN_golang/internal/models"
+)
 
 type UserServiceInterface interface {
-	GetByUsername(username string) (*models.User, error)
-	LoginUser(username string, password string) (*models.User, error)
+	GetByUsername(ctx context.Context, username string) (*models.User, error)
+	LoginUser(ctx context.Context, username string, password string) (*models.User, error)
 }
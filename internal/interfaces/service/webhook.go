@@ -0,0 +1,19 @@
+package service
+
+import (
+	"context"
+
+	"github.com/maithuc2003/Test_GIN_golang/internal/models"
+)
+
+type WebhookServiceInterface interface {
+	GetAllSubscriptions(ctx context.Context) ([]models.WebhookSubscription, error)
+	GetBySubscriptionID(ctx context.Context, id uint) (*models.WebhookSubscription, error)
+	// CreateSubscription sinh secret nếu không truyền vào; secret chỉ được trả về lúc này
+	CreateSubscription(ctx context.Context, sub *models.WebhookSubscription) error
+	// UpdateById giữ secret cũ nếu sub.Secret rỗng
+	UpdateById(ctx context.Context, sub *models.WebhookSubscription) (*models.WebhookSubscription, error)
+	DeleteById(ctx context.Context, id uint) (*models.WebhookSubscription, error)
+	GetDeliveries(ctx context.Context, subscriptionID uint) ([]models.WebhookDelivery, error)
+	Redeliver(ctx context.Context, subscriptionID, deliveryID uint) (*models.WebhookDelivery, error)
+}
@@ -0,0 +1,89 @@
+// Package invoice dựng nội dung hoá đơn từ đơn hàng đã chốt giá và render ra HTML/PDF.
+package invoice
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/maithuc2003/Test_GIN_golang/internal/models"
+	"github.com/shopspring/decimal"
+)
+
+// Issuer là thông tin bên bán in trên hoá đơn
+type Issuer struct {
+	Name    string
+	Address string
+	TaxID   string
+}
+
+// Document là dữ liệu của một hoá đơn; HTML và PDF được render từ cùng một Document
+// nên hai định dạng luôn khớp nhau
+type Document struct {
+	Number    string
+	IssuedAt  time.Time
+	Issuer    Issuer
+	Customer  string
+	OrderID   uint
+	OrderedAt time.Time
+
+	Item          string
+	Quantity      int
+	UnitPrice     decimal.Decimal
+	Subtotal      decimal.Decimal
+	Discounts     []models.OrderDiscount
+	DiscountTotal decimal.Decimal
+	TaxRate       decimal.Decimal
+	TaxInclusive  bool
+	TaxTotal      decimal.Decimal
+	GrandTotal    decimal.Decimal
+	Currency      string
+}
+
+// NewDocument lấy các khoản tiền đã chốt trên đơn, không tính lại theo giá hiện tại
+func NewDocument(number string, issuedAt time.Time, issuer Issuer, order *models.Order, item, customer string) Document {
+	return Document{
+		Number:        number,
+		IssuedAt:      issuedAt,
+		Issuer:        issuer,
+		Customer:      customer,
+		OrderID:       order.ID,
+		OrderedAt:     order.OrderedAt,
+		Item:          item,
+		Quantity:      order.Quantity,
+		UnitPrice:     order.UnitPrice,
+		Subtotal:      order.Subtotal,
+		Discounts:     order.Discounts,
+		DiscountTotal: order.DiscountTotal,
+		TaxRate:       order.TaxRate,
+		TaxInclusive:  order.TaxInclusive,
+		TaxTotal:      order.TaxTotal,
+		GrandTotal:    order.GrandTotal,
+		Currency:      order.Currency,
+	}
+}
+
+// Number ghép số hoá đơn dạng PREFIX-YYYY-000001 từ dãy và số thứ tự trong dãy
+func Number(series string, seq int64) string {
+	return fmt.Sprintf("%s-%06d", series, seq)
+}
+
+// Series là dãy số hoá đơn theo năm phát hành, vd: INV-2025
+func Series(prefix string, issuedAt time.Time) string {
+	return fmt.Sprintf("%s-%d", prefix, issuedAt.Year())
+}
+
+func money(d decimal.Decimal) string {
+	return d.StringFixed(2)
+}
+
+func percent(rate decimal.Decimal) string {
+	return rate.Mul(decimal.NewFromInt(100)).String() + "%"
+}
+
+func taxLabel(d Document) string {
+	label := "Tax " + percent(d.TaxRate)
+	if d.TaxInclusive {
+		label += " (included)"
+	}
+	return label
+}
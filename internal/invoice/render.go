@@ -0,0 +1,106 @@
+package invoice
+
+import (
+	"bytes"
+	"embed"
+	"fmt"
+	"html/template"
+
+	"github.com/go-pdf/fpdf"
+)
+
+//go:embed templates/invoice.html
+var templates embed.FS
+
+var htmlTemplate = template.Must(template.New("invoice.html").Funcs(template.FuncMap{
+	"money":    money,
+	"taxLabel": taxLabel,
+}).ParseFS(templates, "templates/invoice.html"))
+
+// RenderHTML render hoá đơn theo template HTML nhúng sẵn
+func RenderHTML(doc Document) ([]byte, error) {
+	var buf bytes.Buffer
+	if err := htmlTemplate.Execute(&buf, doc); err != nil {
+		return nil, fmt.Errorf("failed to render invoice HTML: %w", err)
+	}
+	return buf.Bytes(), nil
+}
+
+// RenderPDF vẽ hoá đơn khổ A4 với cùng bố cục như bản HTML. Font chuẩn của PDF chỉ
+// có bảng mã cp1252 nên ký tự ngoài bảng mã (vd: tiếng Việt có dấu) không hiển thị đúng.
+func RenderPDF(doc Document) ([]byte, error) {
+	pdf := fpdf.New("P", "mm", "A4", "")
+	pdf.SetTitle("Invoice "+doc.Number, true)
+	pdf.SetCreationDate(doc.IssuedAt)
+	pdf.SetModificationDate(doc.IssuedAt)
+	// Sắp xếp font và tài nguyên theo tên để cùng dữ liệu cho ra cùng file
+	pdf.SetCatalogSort(true)
+	tr := pdf.UnicodeTranslatorFromDescriptor("")
+	pdf.AddPage()
+
+	pdf.SetFont("Helvetica", "B", 18)
+	pdf.Cell(0, 10, tr("Invoice "+doc.Number))
+	pdf.Ln(10)
+	pdf.SetFont("Helvetica", "", 10)
+	pdf.Cell(0, 6, tr(fmt.Sprintf("Issued %s - Order #%d placed %s",
+		doc.IssuedAt.Format("2006-01-02"), doc.OrderID, doc.OrderedAt.Format("2006-01-02"))))
+	pdf.Ln(12)
+
+	from := doc.Issuer.Name
+	if doc.Issuer.Address != "" {
+		from += "\n" + doc.Issuer.Address
+	}
+	if doc.Issuer.TaxID != "" {
+		from += "\nTax ID: " + doc.Issuer.TaxID
+	}
+	top := pdf.GetY()
+	pdf.SetFont("Helvetica", "B", 10)
+	pdf.Cell(95, 6, "From")
+	pdf.Cell(95, 6, "Bill to")
+	pdf.Ln(6)
+	pdf.SetFont("Helvetica", "", 10)
+	pdf.MultiCell(95, 5, tr(from), "", "L", false)
+	bottom := pdf.GetY()
+	pdf.SetXY(105, top+6)
+	pdf.MultiCell(95, 5, tr(doc.Customer), "", "L", false)
+	pdf.SetY(max(bottom, pdf.GetY()) + 8)
+
+	widths := []float64{100, 20, 35, 35}
+	row := func(cells []string, border string) {
+		for i, text := range cells {
+			align := "R"
+			if i == 0 {
+				align = "L"
+			}
+			pdf.CellFormat(widths[i], 7, tr(text), border, 0, align, false, 0, "")
+		}
+		pdf.Ln(7)
+	}
+	summary := func(label, amount string) {
+		pdf.CellFormat(widths[0]+widths[1]+widths[2], 7, tr(label), "", 0, "L", false, 0, "")
+		pdf.CellFormat(widths[3], 7, tr(amount), "", 0, "R", false, 0, "")
+		pdf.Ln(7)
+	}
+
+	pdf.SetFont("Helvetica", "B", 10)
+	row([]string{"Item", "Qty", "Unit price", "Amount"}, "B")
+	pdf.SetFont("Helvetica", "", 10)
+	row([]string{doc.Item, fmt.Sprint(doc.Quantity), money(doc.UnitPrice), money(doc.Subtotal)}, "B")
+	summary("Subtotal", money(doc.Subtotal))
+	for _, d := range doc.Discounts {
+		label := "Discount " + d.Code
+		if d.Description != "" {
+			label += " (" + d.Description + ")"
+		}
+		summary(label, "-"+money(d.Amount))
+	}
+	summary(taxLabel(doc), money(doc.TaxTotal))
+	pdf.SetFont("Helvetica", "B", 11)
+	summary(fmt.Sprintf("Total (%s)", doc.Currency), money(doc.GrandTotal))
+
+	var buf bytes.Buffer
+	if err := pdf.Output(&buf); err != nil {
+		return nil, fmt.Errorf("failed to render invoice PDF: %w", err)
+	}
+	return buf.Bytes(), nil
+}
@@ -0,0 +1,63 @@
+package invoice_test
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/require"
+
+	"github.com/maithuc2003/Test_GIN_golang/internal/invoice"
+	"github.com/maithuc2003/Test_GIN_golang/internal/models"
+)
+
+func document() invoice.Document {
+	order := &models.Order{
+		ID: 3, BookID: 1, UserID: 7, Quantity: 2, OrderedAt: time.Date(2025, 3, 1, 9, 0, 0, 0, time.UTC),
+		Currency: "USD", UnitPrice: decimal.RequireFromString("10.50"), Subtotal: decimal.RequireFromString("21.00"),
+		Discounts:     []models.OrderDiscount{{Code: "SPRING", Description: "10% off", Amount: decimal.RequireFromString("2.10")}},
+		DiscountTotal: decimal.RequireFromString("2.10"),
+		TaxRate:       decimal.RequireFromString("0.08"), TaxTotal: decimal.RequireFromString("1.51"),
+		GrandTotal: decimal.RequireFromString("20.41"),
+	}
+	issuer := invoice.Issuer{Name: "Bookstore", Address: "1 Main St", TaxID: "0101"}
+	number := invoice.Number(invoice.Series("INV", time.Date(2025, 3, 2, 0, 0, 0, 0, time.UTC)), 12)
+	return invoice.NewDocument(number, time.Date(2025, 3, 2, 10, 0, 0, 0, time.UTC), issuer, order, "Dune <Special>", "alice")
+}
+
+func TestNumber(t *testing.T) {
+	require.Equal(t, "INV-2025-000012", document().Number)
+}
+
+func TestRenderHTML(t *testing.T) {
+	html, err := invoice.RenderHTML(document())
+	require.NoError(t, err)
+	body := string(html)
+	for _, want := range []string{
+		"Invoice INV-2025-000012", "Issued 2025-03-02", "Order #3", "Tax ID: 0101", "alice",
+		"Dune &lt;Special&gt;", "10.50", "21.00", "Discount SPRING (10% off)", "-2.10", "Tax 8%", "1.51", "Total (USD)", "20.41",
+	} {
+		require.Contains(t, body, want)
+	}
+	require.NotContains(t, body, "(included)")
+
+	doc := document()
+	doc.TaxInclusive = true
+	html, err = invoice.RenderHTML(doc)
+	require.NoError(t, err)
+	require.Contains(t, string(html), "Tax 8% (included)")
+}
+
+func TestRenderPDF(t *testing.T) {
+	first, err := invoice.RenderPDF(document())
+	require.NoError(t, err)
+	require.True(t, bytes.HasPrefix(first, []byte("%PDF-")))
+	require.True(t, strings.HasSuffix(strings.TrimSpace(string(first)), "%%EOF"))
+
+	// Cùng dữ liệu thì cùng nội dung
+	second, err := invoice.RenderPDF(document())
+	require.NoError(t, err)
+	require.Equal(t, first, second)
+}
@@ -0,0 +1,117 @@
+package logging_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel/trace"
+	"gorm.io/gorm"
+	gormlogger "gorm.io/gorm/logger"
+
+	"github.com/maithuc2003/Test_GIN_golang/internal/logging"
+)
+
+// lines giải mã từng dòng log JSON
+func lines(t *testing.T, buf *bytes.Buffer) []map[string]any {
+	var out []map[string]any
+	for _, line := range strings.Split(strings.TrimSpace(buf.String()), "\n") {
+		if line == "" {
+			continue
+		}
+		var entry map[string]any
+		require.NoError(t, json.Unmarshal([]byte(line), &entry))
+		out = append(out, entry)
+	}
+	return out
+}
+
+func TestContextAttributes(t *testing.T) {
+	var buf bytes.Buffer
+	logger := logging.New(&buf, slog.LevelInfo)
+
+	ctx := logging.WithUserID(logging.WithRequestID(context.Background(), "req-1"), 7)
+	logger.InfoContext(ctx, "hello", "k", "v")
+	logger.With("component", "test").InfoContext(context.Background(), "no request")
+	logger.DebugContext(ctx, "hidden")
+
+	got := lines(t, &buf)
+	require.Len(t, got, 2)
+	require.Equal(t, "hello", got[0]["msg"])
+	require.Equal(t, "req-1", got[0]["request_id"])
+	require.EqualValues(t, 7, got[0]["user_id"])
+	require.Equal(t, "v", got[0]["k"])
+	require.NotContains(t, got[1], "request_id")
+	require.NotContains(t, got[1], "user_id")
+	require.Equal(t, "test", got[1]["component"])
+	require.NotContains(t, got[0], "trace_id")
+}
+
+func TestTraceAttributes(t *testing.T) {
+	var buf bytes.Buffer
+	logger := logging.New(&buf, slog.LevelInfo)
+
+	sc := trace.NewSpanContext(trace.SpanContextConfig{
+		TraceID: trace.TraceID{0x4b, 0xf9, 0x2f, 0x35, 0x77, 0xb3, 0x4d, 0xa6, 0xa3, 0xce, 0x92, 0x9d, 0x0e, 0x0e, 0x47, 0x36},
+		SpanID:  trace.SpanID{0x00, 0xf0, 0x67, 0xaa, 0x0b, 0xa9, 0x02, 0xb7},
+	})
+	logger.InfoContext(trace.ContextWithSpanContext(context.Background(), sc), "traced")
+
+	got := lines(t, &buf)
+	require.Len(t, got, 1)
+	require.Equal(t, "4bf92f3577b34da6a3ce929d0e0e4736", got[0]["trace_id"])
+	require.Equal(t, "00f067aa0ba902b7", got[0]["span_id"])
+}
+
+func TestGormLogger(t *testing.T) {
+	ctx := logging.WithRequestID(context.Background(), "req-2")
+	query := func() (string, int64) { return "SELECT * FROM books", 3 }
+
+	tests := []struct {
+		name      string
+		level     slog.Level
+		elapsed   time.Duration
+		err       error
+		wantMsg   string
+		wantLevel string
+	}{
+		{name: "fast query hidden at info", level: slog.LevelInfo, elapsed: time.Millisecond},
+		{name: "fast query at debug", level: slog.LevelDebug, elapsed: time.Millisecond, wantMsg: "query", wantLevel: "DEBUG"},
+		{name: "slow query", level: slog.LevelInfo, elapsed: time.Second, wantMsg: "slow query", wantLevel: "WARN"},
+		{name: "failed query", level: slog.LevelInfo, elapsed: time.Millisecond, err: errors.New("deadlock"), wantMsg: "query failed", wantLevel: "ERROR"},
+		{name: "record not found is not an error", level: slog.LevelInfo, elapsed: time.Millisecond, err: gorm.ErrRecordNotFound},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			l := logging.NewGormLogger(logging.New(&buf, tt.level), 100*time.Millisecond)
+			l.Trace(ctx, time.Now().Add(-tt.elapsed), query, tt.err)
+
+			got := lines(t, &buf)
+			if tt.wantMsg == "" {
+				require.Empty(t, got)
+				return
+			}
+			require.Len(t, got, 1)
+			require.Equal(t, tt.wantMsg, got[0]["msg"])
+			require.Equal(t, tt.wantLevel, got[0]["level"])
+			require.Equal(t, "SELECT * FROM books", got[0]["sql"])
+			require.EqualValues(t, 3, got[0]["rows"])
+			require.Equal(t, "req-2", got[0]["request_id"])
+		})
+	}
+
+	t.Run("silent mode", func(t *testing.T) {
+		var buf bytes.Buffer
+		l := logging.NewGormLogger(logging.New(&buf, slog.LevelDebug), 0).LogMode(gormlogger.Silent)
+		l.Trace(ctx, time.Now().Add(-time.Second), query, errors.New("boom"))
+		require.Empty(t, buf.String())
+	})
+}
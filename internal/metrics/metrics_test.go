@@ -0,0 +1,66 @@
+package metrics_test
+
+import (
+	"fmt"
+	"io"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	dto "github.com/prometheus/client_model/go"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+
+	"github.com/maithuc2003/Test_GIN_golang/internal/metrics"
+	"github.com/maithuc2003/Test_GIN_golang/internal/models"
+	sqlitedriver "gorm.io/driver/sqlite"
+
+	_ "modernc.org/sqlite"
+)
+
+func TestGormPlugin(t *testing.T) {
+	dsn := fmt.Sprintf("file:testdb_%d?mode=memory&cache=shared", time.Now().UnixNano())
+	db, err := gorm.Open(sqlitedriver.New(sqlitedriver.Config{DSN: dsn, DriverName: "sqlite"}), &gorm.Config{})
+	require.NoError(t, err)
+	require.NoError(t, db.AutoMigrate(&models.Author{}))
+	require.NoError(t, db.Use(metrics.GormPlugin{}))
+
+	before := testutil.CollectAndCount(metrics.DBQueryDuration)
+	require.NoError(t, db.Create(&models.Author{Name: "Ann"}).Error)
+	var author models.Author
+	require.NoError(t, db.First(&author).Error)
+	require.ErrorIs(t, db.First(&author, 999).Error, gorm.ErrRecordNotFound)
+	require.Error(t, db.Exec("SELECT * FROM missing_table").Error)
+
+	require.Greater(t, testutil.CollectAndCount(metrics.DBQueryDuration), before)
+	require.EqualValues(t, 1, queryCount(t, "create", "authors", "ok"))
+	require.EqualValues(t, 2, queryCount(t, "query", "authors", "ok"), "record not found is not an error")
+	require.EqualValues(t, 1, queryCount(t, "raw", "unknown", "error"))
+}
+
+// queryCount trả về số query đã đo với bộ nhãn đã cho
+func queryCount(t *testing.T, operation, table, result string) uint64 {
+	var m dto.Metric
+	require.NoError(t, metrics.DBQueryDuration.WithLabelValues(operation, table, result).(prometheus.Metric).Write(&m))
+	return m.GetHistogram().GetSampleCount()
+}
+
+func TestHandler(t *testing.T) {
+	metrics.ObserveHTTPRequest("GET", "/books/:id", 200, 20*time.Millisecond)
+	metrics.OrdersCreated.WithLabelValues(metrics.SourceOrder).Inc()
+
+	rec := httptest.NewRecorder()
+	metrics.Handler().ServeHTTP(rec, httptest.NewRequest("GET", "/metrics", nil))
+	body, err := io.ReadAll(rec.Body)
+	require.NoError(t, err)
+	for _, want := range []string{
+		`bookstore_http_requests_total{method="GET",route="/books/:id",status="200"}`,
+		`bookstore_http_request_duration_seconds_bucket{method="GET",route="/books/:id",status="200",le="0.025"}`,
+		`bookstore_orders_created_total{source="order"}`,
+		"go_goroutines",
+	} {
+		require.Contains(t, string(body), want)
+	}
+}
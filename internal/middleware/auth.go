@@ -6,6 +6,7 @@ import (
 
 	"github.com/gin-gonic/gin"
 	"github.com/golang-jwt/jwt/v5"
+	"github.com/maithuc2003/Test_GIN_golang/internal/logging"
 	jwtutil "github.com/maithuc2003/Test_GIN_golang/pkg/jwt"
 )
 
@@ -18,17 +19,7 @@ func AuthMiddleware() gin.HandlerFunc {
 			return
 		}
 
-		// Cắt "Bearer " để lấy token thật sự
-		tokenString := strings.TrimPrefix(authHeader, "Bearer ")
-
-		// Parse token và xác minh chữ ký
-		token, err := jwt.Parse(tokenString, func(t *jwt.Token) (interface{}, error) {
-			if _, ok := t.Method.(*jwt.SigningMethodHMAC); !ok {
-				return nil, jwt.ErrSignatureInvalid
-			}
-			return jwtutil.JwtSecret(), nil
-		})
-
+		token, err := requestToken(c)
 		if err != nil || !token.Valid {
 			c.JSON(http.StatusUnauthorized, gin.H{"error": "Token is invalid or expired"})
 			c.Abort()
@@ -39,6 +30,8 @@ func AuthMiddleware() gin.HandlerFunc {
 		if claims, ok := token.Claims.(jwt.MapClaims); ok {
 			if userID, ok := claims["user_id"].(float64); ok {
 				c.Set("user_id", int(userID))
+				// Gắn vào context để log của request (kể cả query GORM) có user_id
+				c.Request = c.Request.WithContext(logging.WithUserID(c.Request.Context(), int(userID)))
 			}
 			if username, ok := claims["username"].(string); ok {
 				c.Set("username", username)
@@ -56,3 +49,34 @@ func AuthMiddleware() gin.HandlerFunc {
 		c.Next()
 	}
 }
+
+// parsedTokenKey là key trong gin context giữ kết quả xác minh token của request
+const parsedTokenKey = "parsed_token"
+
+type parsedToken struct {
+	token *jwt.Token
+	err   error
+}
+
+// requestToken xác minh token trong header "auth" một lần cho mỗi request; rate limit
+// và AuthMiddleware dùng chung kết quả đã lưu trong context
+func requestToken(c *gin.Context) (*jwt.Token, error) {
+	if v, ok := c.Get(parsedTokenKey); ok {
+		parsed := v.(parsedToken)
+		return parsed.token, parsed.err
+	}
+	token, err := parseToken(c.GetHeader("auth"))
+	c.Set(parsedTokenKey, parsedToken{token: token, err: err})
+	return token, err
+}
+
+// parseToken cắt "Bearer " khỏi header và xác minh chữ ký của token
+func parseToken(authHeader string) (*jwt.Token, error) {
+	tokenString := strings.TrimPrefix(authHeader, "Bearer ")
+	return jwt.Parse(tokenString, func(t *jwt.Token) (interface{}, error) {
+		if _, ok := t.Method.(*jwt.SigningMethodHMAC); !ok {
+			return nil, jwt.ErrSignatureInvalid
+		}
+		return jwtutil.JwtSecret(), nil
+	})
+}
@@ -0,0 +1,96 @@
+package middleware_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/require"
+
+	"github.com/maithuc2003/Test_GIN_golang/internal/logging"
+	"github.com/maithuc2003/Test_GIN_golang/internal/middleware"
+)
+
+func TestRequestIDMiddleware(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	tests := []struct {
+		name      string
+		header    string
+		keepGiven bool
+	}{
+		{name: "accepts client ID", header: "abc-123", keepGiven: true},
+		{name: "generates when missing"},
+		{name: "replaces ID with spaces", header: "a b"},
+		{name: "replaces too long ID", header: strings.Repeat("x", 129)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var seen string
+			r := gin.New()
+			r.Use(middleware.RequestIDMiddleware())
+			r.GET("/", func(c *gin.Context) {
+				seen = logging.RequestID(c.Request.Context())
+				c.Status(http.StatusOK)
+			})
+
+			req := httptest.NewRequest(http.MethodGet, "/", nil)
+			if tt.header != "" {
+				req.Header.Set(middleware.RequestIDHeader, tt.header)
+			}
+			rec := httptest.NewRecorder()
+			r.ServeHTTP(rec, req)
+
+			got := rec.Header().Get(middleware.RequestIDHeader)
+			require.Equal(t, got, seen)
+			if tt.keepGiven {
+				require.Equal(t, tt.header, got)
+			} else {
+				require.Len(t, got, 32)
+			}
+		})
+	}
+}
+
+func TestAccessLogMiddleware(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	var buf bytes.Buffer
+	logger := logging.New(&buf, slog.LevelInfo)
+	r := gin.New()
+	r.Use(middleware.RequestIDMiddleware(), middleware.AccessLogMiddleware(logger), middleware.RecoveryMiddleware(logger))
+	r.GET("/books/:id", func(c *gin.Context) {
+		// Giống AuthMiddleware: user ID được gắn sau access log
+		c.Request = c.Request.WithContext(logging.WithUserID(c.Request.Context(), 5))
+		c.JSON(http.StatusNotFound, gin.H{"error": "not found"})
+	})
+	r.GET("/panic", func(c *gin.Context) { panic("boom") })
+
+	req := httptest.NewRequest(http.MethodGet, "/books/9", nil)
+	req.Header.Set(middleware.RequestIDHeader, "req-9")
+	r.ServeHTTP(httptest.NewRecorder(), req)
+
+	var entry map[string]any
+	require.NoError(t, json.Unmarshal(buf.Bytes(), &entry))
+	require.Equal(t, "request", entry["msg"])
+	require.Equal(t, "WARN", entry["level"])
+	require.Equal(t, "/books/9", entry["path"])
+	require.Equal(t, "/books/:id", entry["route"])
+	require.EqualValues(t, http.StatusNotFound, entry["status"])
+	require.Equal(t, "req-9", entry["request_id"])
+	require.EqualValues(t, 5, entry["user_id"])
+
+	buf.Reset()
+	rec := httptest.NewRecorder()
+	r.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/panic", nil))
+	require.Equal(t, http.StatusInternalServerError, rec.Code)
+	logged := buf.String()
+	require.Contains(t, logged, `"msg":"panic recovered"`)
+	require.Contains(t, logged, `"level":"ERROR","msg":"request"`)
+}
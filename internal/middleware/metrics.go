@@ -0,0 +1,25 @@
+package middleware
+
+import (
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/maithuc2003/Test_GIN_golang/internal/metrics"
+)
+
+// unmatchedRoute gom các đường dẫn không khớp route nào vào một nhãn để số series không tăng theo URL
+const unmatchedRoute = "unmatched"
+
+// MetricsMiddleware đếm request và đo độ trễ theo route template và mã trạng thái
+func MetricsMiddleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		start := time.Now()
+		c.Next()
+
+		route := c.FullPath()
+		if route == "" {
+			route = unmatchedRoute
+		}
+		metrics.ObserveHTTPRequest(c.Request.Method, route, c.Writer.Status(), time.Since(start))
+	}
+}
@@ -0,0 +1,33 @@
+package middleware_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/require"
+
+	"github.com/maithuc2003/Test_GIN_golang/internal/metrics"
+	"github.com/maithuc2003/Test_GIN_golang/internal/middleware"
+)
+
+func TestMetricsMiddleware(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	r := gin.New()
+	r.Use(middleware.MetricsMiddleware())
+	r.GET("/books/:id", func(c *gin.Context) { c.Status(http.StatusOK) })
+
+	found := metrics.HTTPRequests.WithLabelValues(http.MethodGet, "/books/:id", "200")
+	missing := metrics.HTTPRequests.WithLabelValues(http.MethodGet, "unmatched", "404")
+	beforeFound, beforeMissing := testutil.ToFloat64(found), testutil.ToFloat64(missing)
+
+	for _, path := range []string{"/books/1", "/books/2", "/nope/3"} {
+		r.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, path, nil))
+	}
+
+	require.Equal(t, beforeFound+2, testutil.ToFloat64(found), "labelled by route template, not path")
+	require.Equal(t, beforeMissing+1, testutil.ToFloat64(missing))
+}
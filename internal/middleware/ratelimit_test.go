@@ -0,0 +1,114 @@
+package middleware_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/require"
+
+	"github.com/maithuc2003/Test_GIN_golang/internal/metrics"
+	"github.com/maithuc2003/Test_GIN_golang/internal/middleware"
+	"github.com/maithuc2003/Test_GIN_golang/internal/ratelimit"
+)
+
+type failingStore struct{}
+
+func (failingStore) Allow(context.Context, string, ratelimit.Limit, time.Time) (ratelimit.Result, error) {
+	return ratelimit.Result{}, errors.New("redis down")
+}
+
+func setupRateLimitRouter(store ratelimit.Store, limit ratelimit.Limit) *gin.Engine {
+	r := gin.New()
+	r.Use(
+		middleware.RateLimitMiddleware(store, "user", limit, middleware.UserKey),
+		middleware.RateLimitMiddleware(store, "ip", limit, middleware.AnonymousIPKey),
+	)
+	r.GET("/books", func(c *gin.Context) { c.Status(http.StatusOK) })
+	r.GET("/authors", func(c *gin.Context) { c.Status(http.StatusOK) })
+	return r
+}
+
+// get gửi request từ ip, kèm token nếu khác rỗng
+func get(r *gin.Engine, path, ip, token string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(http.MethodGet, path, nil)
+	req.RemoteAddr = ip + ":1234"
+	if token != "" {
+		req.Header.Set("auth", "Bearer "+token)
+	}
+	rec := httptest.NewRecorder()
+	r.ServeHTTP(rec, req)
+	return rec
+}
+
+func TestRateLimitMiddleware(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	limit := ratelimit.Limit{Rate: 0.5, Burst: 2}
+	r := setupRateLimitRouter(ratelimit.NewMemoryStore(), limit)
+	denied := metrics.RateLimited.WithLabelValues("ip")
+	before := testutil.ToFloat64(denied)
+
+	rec := get(r, "/books", "10.0.0.1", "")
+	require.Equal(t, http.StatusOK, rec.Code)
+	require.Equal(t, "2", rec.Header().Get("X-RateLimit-Limit"))
+	require.Equal(t, "1", rec.Header().Get("X-RateLimit-Remaining"))
+	require.Equal(t, http.StatusOK, get(r, "/books", "10.0.0.1", "").Code)
+
+	rec = get(r, "/books", "10.0.0.1", "")
+	require.Equal(t, http.StatusTooManyRequests, rec.Code)
+	require.Equal(t, "2", rec.Header().Get("Retry-After"))
+	require.JSONEq(t, `{"error":"Too many requests"}`, rec.Body.String())
+	require.Equal(t, before+1, testutil.ToFloat64(denied))
+
+	// Bucket riêng theo route và theo IP
+	require.Equal(t, http.StatusOK, get(r, "/authors", "10.0.0.1", "").Code)
+	require.Equal(t, http.StatusOK, get(r, "/books", "10.0.0.2", "").Code)
+
+	// Người dùng đã đăng nhập cùng IP dùng bucket theo user
+	alice, bob := generateToken(1, "alice", "admin", false), generateToken(2, "bob", "admin", false)
+	for range 2 {
+		require.Equal(t, http.StatusOK, get(r, "/books", "10.0.0.1", alice).Code)
+	}
+	require.Equal(t, http.StatusTooManyRequests, get(r, "/books", "10.0.0.1", alice).Code)
+	require.Equal(t, http.StatusOK, get(r, "/books", "10.0.0.1", bob).Code)
+
+	// Token giả mạo bị tính theo IP
+	require.Equal(t, http.StatusTooManyRequests, get(r, "/books", "10.0.0.1", generateRSAToken(t)).Code)
+}
+
+func TestRateLimitMiddleware_ParsesTokenOnce(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	r.Use(
+		middleware.RateLimitMiddleware(ratelimit.NewMemoryStore(), "user", ratelimit.Limit{Rate: 1, Burst: 10}, middleware.UserKey),
+		// Header bị đổi sau khi rate limit đã xác minh token: AuthMiddleware dùng lại kết
+		// quả đó thay vì parse lại
+		func(c *gin.Context) { c.Request.Header.Set("auth", "Bearer "+generateRSAToken(t)) },
+		middleware.AuthMiddleware(),
+	)
+	r.GET("/books", func(c *gin.Context) { c.JSON(http.StatusOK, gin.H{"user_id": c.GetInt("user_id")}) })
+
+	rec := get(r, "/books", "10.0.0.1", generateToken(7, "alice", "admin", false))
+	require.Equal(t, http.StatusOK, rec.Code)
+	require.JSONEq(t, `{"user_id":7}`, rec.Body.String())
+}
+
+func TestRateLimitMiddleware_StoreError(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	r := setupRateLimitRouter(failingStore{}, ratelimit.Limit{Rate: 1, Burst: 1})
+
+	for range 3 {
+		require.Equal(t, http.StatusOK, get(r, "/books", "10.0.0.1", "").Code, "fails open")
+	}
+}
+
+func TestRetryAfter(t *testing.T) {
+	require.Equal(t, "1", middleware.RetryAfter(0))
+	require.Equal(t, "1", middleware.RetryAfter(200*time.Millisecond))
+	require.Equal(t, "3", middleware.RetryAfter(2100*time.Millisecond))
+}
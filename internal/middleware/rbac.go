@@ -5,6 +5,7 @@ import (
 
 	"github.com/gin-gonic/gin"
 	"github.com/maithuc2003/Test_GIN_golang/internal/database"
+	"github.com/maithuc2003/Test_GIN_golang/internal/metrics"
 )
 
 func RBACMiddleware(permission string) gin.HandlerFunc {
@@ -16,7 +17,8 @@ func RBACMiddleware(permission string) gin.HandlerFunc {
 			return
 		}
 
-		if !database.HasAccess(userID.(int), permission) {
+		if !database.HasAccess(c.Request.Context(), userID.(int), permission) {
+			metrics.RBACDenied.WithLabelValues(permission).Inc()
 			c.JSON(http.StatusForbidden, gin.H{"error": "Permission denied"})
 			c.Abort()
 			return
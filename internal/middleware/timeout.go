@@ -0,0 +1,41 @@
+package middleware
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// TimeoutMiddleware gắn deadline vào context của request để các query GORM
+// phía dưới bị huỷ khi quá thời gian hoặc khi client ngắt kết nối. Route bắt đầu
+// bằng một prefix trong overrides (vd: import/export hàng loạt) dùng thời gian tương
+// ứng thay cho timeout; 0 là không có deadline.
+func TimeoutMiddleware(timeout time.Duration, overrides map[string]time.Duration) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		timeout := timeout
+		for prefix, d := range overrides {
+			if strings.HasPrefix(c.FullPath(), prefix) {
+				timeout = d
+				break
+			}
+		}
+		if timeout <= 0 {
+			c.Next()
+			return
+		}
+
+		ctx, cancel := context.WithTimeout(c.Request.Context(), timeout)
+		defer cancel()
+		c.Request = c.Request.WithContext(ctx)
+
+		c.Next()
+
+		if errors.Is(ctx.Err(), context.DeadlineExceeded) && !c.Writer.Written() {
+			c.AbortWithStatusJSON(http.StatusGatewayTimeout, gin.H{"error": "Request timed out"})
+		}
+	}
+}
@@ -0,0 +1,94 @@
+package middleware_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/require"
+
+	"github.com/maithuc2003/Test_GIN_golang/internal/middleware"
+)
+
+func TestTimeoutMiddleware(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	tests := []struct {
+		name           string
+		timeout        time.Duration
+		handler        gin.HandlerFunc
+		expectedStatus int
+		expectedBody   string
+	}{
+		{
+			name:    "deadline attached to request context",
+			timeout: time.Second,
+			handler: func(c *gin.Context) {
+				_, ok := c.Request.Context().Deadline()
+				require.True(t, ok)
+				c.JSON(http.StatusOK, gin.H{"message": "Success"})
+			},
+			expectedStatus: http.StatusOK,
+			expectedBody:   "Success",
+		},
+		{
+			name:    "zero timeout disables deadline",
+			timeout: 0,
+			handler: func(c *gin.Context) {
+				_, ok := c.Request.Context().Deadline()
+				require.False(t, ok)
+				c.JSON(http.StatusOK, gin.H{"message": "Success"})
+			},
+			expectedStatus: http.StatusOK,
+			expectedBody:   "Success",
+		},
+		{
+			name:    "handler exceeds deadline without writing",
+			timeout: 10 * time.Millisecond,
+			handler: func(c *gin.Context) {
+				<-c.Request.Context().Done()
+			},
+			expectedStatus: http.StatusGatewayTimeout,
+			expectedBody:   "Request timed out",
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			r := gin.New()
+			r.Use(middleware.TimeoutMiddleware(tc.timeout, nil))
+			r.GET("/test", tc.handler)
+
+			req := httptest.NewRequest(http.MethodGet, "/test", nil)
+			w := httptest.NewRecorder()
+			r.ServeHTTP(w, req)
+
+			require.Equal(t, tc.expectedStatus, w.Code)
+			require.Contains(t, w.Body.String(), tc.expectedBody)
+		})
+	}
+}
+
+func TestTimeoutMiddleware_Overrides(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	r.Use(middleware.TimeoutMiddleware(10*time.Millisecond, map[string]time.Duration{"/import/": 0}))
+	hasDeadline := func(c *gin.Context) {
+		_, ok := c.Request.Context().Deadline()
+		c.JSON(http.StatusOK, gin.H{"deadline": ok})
+	}
+	r.POST("/import/books", hasDeadline)
+	r.GET("/books", hasDeadline)
+
+	for path, want := range map[string]string{"/import/books": `{"deadline":false}`, "/books": `{"deadline":true}`} {
+		method := http.MethodGet
+		if path == "/import/books" {
+			method = http.MethodPost
+		}
+		rec := httptest.NewRecorder()
+		r.ServeHTTP(rec, httptest.NewRequest(method, path, nil))
+		require.JSONEq(t, want, rec.Body.String(), path)
+	}
+}
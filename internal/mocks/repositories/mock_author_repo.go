@@ -1,6 +1,8 @@
 package mocks
 
 import (
+	"context"
+
 	"github.com/maithuc2003/Test_GIN_golang/internal/models"
 	"github.com/stretchr/testify/mock"
 )
@@ -10,23 +12,32 @@ type MockAuthorRepository struct {
 }
 
 // GetAllAuthors mocks retrieving all authors
-func (m *MockAuthorRepository) GetAllAuthors() ([]*models.Author, error) {
-	args := m.Called()
+func (m *MockAuthorRepository) GetAllAuthors(ctx context.Context, filter models.AuthorFilter) ([]*models.Author, error) {
+	args := m.Called(ctx, filter)
 	if authors, ok := args.Get(0).([]*models.Author); ok {
 		return authors, args.Error(1)
 	}
 	return nil, args.Error(1)
 }
 
+// GetByName mocks looking up an author by name
+func (m *MockAuthorRepository) GetByName(ctx context.Context, name string) (*models.Author, error) {
+	args := m.Called(ctx, name)
+	if author, ok := args.Get(0).(*models.Author); ok {
+		return author, args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
 // CreateAuthor mocks creating a new author
-func (m *MockAuthorRepository) CreateAuthor(author *models.Author) error {
-	args := m.Called(author)
+func (m *MockAuthorRepository) CreateAuthor(ctx context.Context, author *models.Author) error {
+	args := m.Called(ctx, author)
 	return args.Error(0)
 }
 
 // GetByAuthorID mocks retrieving an author by ID
-func (m *MockAuthorRepository) GetByAuthorID(id int) (*models.Author, error) {
-	args := m.Called(id)
+func (m *MockAuthorRepository) GetByAuthorID(ctx context.Context, id int) (*models.Author, error) {
+	args := m.Called(ctx, id)
 	if author, ok := args.Get(0).(*models.Author); ok {
 		return author, args.Error(1)
 	}
@@ -34,8 +45,8 @@ func (m *MockAuthorRepository) GetByAuthorID(id int) (*models.Author, error) {
 }
 
 // UpdateById mocks updating an author
-func (m *MockAuthorRepository) UpdateById(author *models.Author) (*models.Author, error) {
-	args := m.Called(author)
+func (m *MockAuthorRepository) UpdateById(ctx context.Context, author *models.Author) (*models.Author, error) {
+	args := m.Called(ctx, author)
 	if updated, ok := args.Get(0).(*models.Author); ok {
 		return updated, args.Error(1)
 	}
@@ -43,10 +54,19 @@ func (m *MockAuthorRepository) UpdateById(author *models.Author) (*models.Author
 }
 
 // DeleteById mocks deleting an author by ID
-func (m *MockAuthorRepository) DeleteById(id int) (*models.Author, error) {
-	args := m.Called(id)
+func (m *MockAuthorRepository) DeleteById(ctx context.Context, id int) (*models.Author, error) {
+	args := m.Called(ctx, id)
 	if deleted, ok := args.Get(0).(*models.Author); ok {
 		return deleted, args.Error(1)
 	}
 	return nil, args.Error(1)
 }
+
+// Merge mocks merging source authors into a target author
+func (m *MockAuthorRepository) Merge(ctx context.Context, targetID int, sourceIDs []int) (*models.AuthorMergeResult, error) {
+	args := m.Called(ctx, targetID, sourceIDs)
+	if result, ok := args.Get(0).(*models.AuthorMergeResult); ok {
+		return result, args.Error(1)
+	}
+	return nil, args.Error(1)
+}
@@ -1,6 +1,8 @@
 package mocks
 
 import (
+	"context"
+
 	"github.com/maithuc2003/Test_GIN_golang/internal/models"
 	"github.com/stretchr/testify/mock"
 )
@@ -9,27 +11,67 @@ type MockBookRepo struct {
 	mock.Mock
 }
 
-func (m *MockBookRepo) CreateBook(book *models.Book) error {
-	args := m.Called(book)
+func (m *MockBookRepo) CreateBook(ctx context.Context, book *models.Book) error {
+	args := m.Called(ctx, book)
 	return args.Error(0)
 }
 
-func (m *MockBookRepo) GetAllBooks() ([]models.Book, error) {
-	args := m.Called()
+func (m *MockBookRepo) GetAllBooks(ctx context.Context, filter models.BookFilter) ([]models.Book, error) {
+	args := m.Called(ctx, filter)
 	return args.Get(0).([]models.Book), args.Error(1)
 }
 
-func (m *MockBookRepo) GetByBookID(id int) (*models.Book, error) {
-	args := m.Called(id)
+func (m *MockBookRepo) GetByBookID(ctx context.Context, id int) (*models.Book, error) {
+	args := m.Called(ctx, id)
 	return args.Get(0).(*models.Book), args.Error(1)
 }
 
-func (m *MockBookRepo) DeleteById(id int) (*models.Book, error) {
-	args := m.Called(id)
+func (m *MockBookRepo) GetByBookIDWithAuthors(ctx context.Context, id int) (*models.Book, error) {
+	args := m.Called(ctx, id)
+	book, _ := args.Get(0).(*models.Book)
+	return book, args.Error(1)
+}
+
+func (m *MockBookRepo) GetBooksByAuthor(ctx context.Context, authorID int, filter models.BookFilter) ([]models.Book, error) {
+	args := m.Called(ctx, authorID, filter)
+	books, _ := args.Get(0).([]models.Book)
+	return books, args.Error(1)
+}
+
+func (m *MockBookRepo) GetBooksByCategory(ctx context.Context, categoryID uint, filter models.BookFilter) ([]models.Book, error) {
+	args := m.Called(ctx, categoryID, filter)
+	books, _ := args.Get(0).([]models.Book)
+	return books, args.Error(1)
+}
+
+func (m *MockBookRepo) CategoryFacets(ctx context.Context, filter models.BookFilter) ([]models.CategoryFacet, error) {
+	args := m.Called(ctx, filter)
+	facets, _ := args.Get(0).([]models.CategoryFacet)
+	return facets, args.Error(1)
+}
+
+func (m *MockBookRepo) Restock(ctx context.Context, id int, quantity int) error {
+	args := m.Called(ctx, id, quantity)
+	return args.Error(0)
+}
+
+func (m *MockBookRepo) UpdateCover(ctx context.Context, id int, key string, cover *models.BookCover) error {
+	args := m.Called(ctx, id, key, cover)
+	return args.Error(0)
+}
+
+func (m *MockBookRepo) GetByTitleAndAuthor(ctx context.Context, title string, authorID int) (*models.Book, error) {
+	args := m.Called(ctx, title, authorID)
+	book, _ := args.Get(0).(*models.Book)
+	return book, args.Error(1)
+}
+
+func (m *MockBookRepo) DeleteById(ctx context.Context, id int) (*models.Book, error) {
+	args := m.Called(ctx, id)
 	return args.Get(0).(*models.Book), args.Error(1)
 }
 
-func (m *MockBookRepo) UpdateById(book *models.Book) (*models.Book, error) {
-	args := m.Called(book)
+func (m *MockBookRepo) UpdateById(ctx context.Context, book *models.Book) (*models.Book, error) {
+	args := m.Called(ctx, book)
 	return args.Get(0).(*models.Book), args.Error(1)
 }
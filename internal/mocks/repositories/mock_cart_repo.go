@@ -0,0 +1,39 @@
+package mocks
+
+import (
+	"context"
+	"time"
+
+	"github.com/maithuc2003/Test_GIN_golang/internal/models"
+	"github.com/stretchr/testify/mock"
+)
+
+type MockCartRepo struct {
+	mock.Mock
+}
+
+func (m *MockCartRepo) GetCart(ctx context.Context, userID uint) (*models.Cart, error) {
+	args := m.Called(ctx, userID)
+	cart, _ := args.Get(0).(*models.Cart)
+	return cart, args.Error(1)
+}
+
+func (m *MockCartRepo) SaveItem(ctx context.Context, userID, bookID uint, quantity int, expiresAt time.Time) error {
+	args := m.Called(ctx, userID, bookID, quantity, expiresAt)
+	return args.Error(0)
+}
+
+func (m *MockCartRepo) RemoveItem(ctx context.Context, userID, bookID uint, expiresAt time.Time) error {
+	args := m.Called(ctx, userID, bookID, expiresAt)
+	return args.Error(0)
+}
+
+func (m *MockCartRepo) Clear(ctx context.Context, userID uint) error {
+	args := m.Called(ctx, userID)
+	return args.Error(0)
+}
+
+func (m *MockCartRepo) DeleteExpired(ctx context.Context, now time.Time) (int64, error) {
+	args := m.Called(ctx, now)
+	return args.Get(0).(int64), args.Error(1)
+}
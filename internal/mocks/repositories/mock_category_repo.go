@@ -0,0 +1,47 @@
+package mocks
+
+import (
+	"context"
+
+	"github.com/maithuc2003/Test_GIN_golang/internal/models"
+	"github.com/stretchr/testify/mock"
+)
+
+type MockCategoryRepo struct {
+	mock.Mock
+}
+
+func (m *MockCategoryRepo) GetAllCategories(ctx context.Context) ([]models.Category, error) {
+	args := m.Called(ctx)
+	categories, _ := args.Get(0).([]models.Category)
+	return categories, args.Error(1)
+}
+
+func (m *MockCategoryRepo) GetByCategoryID(ctx context.Context, id uint) (*models.Category, error) {
+	args := m.Called(ctx, id)
+	category, _ := args.Get(0).(*models.Category)
+	return category, args.Error(1)
+}
+
+func (m *MockCategoryRepo) DescendantIDs(ctx context.Context, id uint) ([]uint, error) {
+	args := m.Called(ctx, id)
+	ids, _ := args.Get(0).([]uint)
+	return ids, args.Error(1)
+}
+
+func (m *MockCategoryRepo) CreateCategory(ctx context.Context, category *models.Category) error {
+	args := m.Called(ctx, category)
+	return args.Error(0)
+}
+
+func (m *MockCategoryRepo) UpdateById(ctx context.Context, category *models.Category) (*models.Category, error) {
+	args := m.Called(ctx, category)
+	updated, _ := args.Get(0).(*models.Category)
+	return updated, args.Error(1)
+}
+
+func (m *MockCategoryRepo) DeleteById(ctx context.Context, id uint) (*models.Category, error) {
+	args := m.Called(ctx, id)
+	deleted, _ := args.Get(0).(*models.Category)
+	return deleted, args.Error(1)
+}
@@ -0,0 +1,63 @@
+package mocks
+
+import (
+	"context"
+
+	"github.com/maithuc2003/Test_GIN_golang/internal/models"
+	"github.com/stretchr/testify/mock"
+)
+
+type MockCouponRepo struct {
+	mock.Mock
+}
+
+func (m *MockCouponRepo) List(ctx context.Context) ([]models.Coupon, error) {
+	args := m.Called(ctx)
+	coupons, _ := args.Get(0).([]models.Coupon)
+	return coupons, args.Error(1)
+}
+
+func (m *MockCouponRepo) GetByID(ctx context.Context, id uint) (*models.Coupon, error) {
+	args := m.Called(ctx, id)
+	coupon, _ := args.Get(0).(*models.Coupon)
+	return coupon, args.Error(1)
+}
+
+func (m *MockCouponRepo) GetByCode(ctx context.Context, code string) (*models.Coupon, error) {
+	args := m.Called(ctx, code)
+	coupon, _ := args.Get(0).(*models.Coupon)
+	return coupon, args.Error(1)
+}
+
+func (m *MockCouponRepo) Create(ctx context.Context, coupon *models.Coupon) error {
+	args := m.Called(ctx, coupon)
+	return args.Error(0)
+}
+
+func (m *MockCouponRepo) Update(ctx context.Context, coupon *models.Coupon) (*models.Coupon, error) {
+	args := m.Called(ctx, coupon)
+	updated, _ := args.Get(0).(*models.Coupon)
+	return updated, args.Error(1)
+}
+
+func (m *MockCouponRepo) Delete(ctx context.Context, id uint) (*models.Coupon, error) {
+	args := m.Called(ctx, id)
+	coupon, _ := args.Get(0).(*models.Coupon)
+	return coupon, args.Error(1)
+}
+
+func (m *MockCouponRepo) BookCategoryIDs(ctx context.Context, bookID uint) ([]uint, error) {
+	args := m.Called(ctx, bookID)
+	ids, _ := args.Get(0).([]uint)
+	return ids, args.Error(1)
+}
+
+func (m *MockCouponRepo) Redeem(ctx context.Context, couponID, userID, orderID uint) error {
+	args := m.Called(ctx, couponID, userID, orderID)
+	return args.Error(0)
+}
+
+func (m *MockCouponRepo) Release(ctx context.Context, orderID uint) error {
+	args := m.Called(ctx, orderID)
+	return args.Error(0)
+}
@@ -0,0 +1,29 @@
+package mocks
+
+import (
+	"context"
+
+	"github.com/maithuc2003/Test_GIN_golang/internal/models"
+	"github.com/stretchr/testify/mock"
+)
+
+type MockInvoiceRepo struct {
+	mock.Mock
+}
+
+func (m *MockInvoiceRepo) GetByOrderID(ctx context.Context, orderID uint) (*models.Invoice, error) {
+	args := m.Called(ctx, orderID)
+	invoice, _ := args.Get(0).(*models.Invoice)
+	return invoice, args.Error(1)
+}
+
+func (m *MockInvoiceRepo) NextNumber(ctx context.Context, series string) (int64, error) {
+	args := m.Called(ctx, series)
+	next, _ := args.Get(0).(int64)
+	return next, args.Error(1)
+}
+
+func (m *MockInvoiceRepo) Create(ctx context.Context, invoice *models.Invoice) error {
+	args := m.Called(ctx, invoice)
+	return args.Error(0)
+}
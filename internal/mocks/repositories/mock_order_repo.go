@@ -1,6 +1,8 @@
 package mocks
 
 import (
+	"context"
+
 	"github.com/maithuc2003/Test_GIN_golang/internal/models"
 	"github.com/stretchr/testify/mock"
 )
@@ -10,27 +12,38 @@ type MockOrderRepository struct {
 	mock.Mock
 }
 
-func (m *MockOrderRepository) Create(order *models.Order) error {
-	args := m.Called(order)
+func (m *MockOrderRepository) Create(ctx context.Context, order *models.Order) error {
+	args := m.Called(ctx, order)
 	return args.Error(0)
 }
 
-func (m *MockOrderRepository) GetAllOrders() ([]*models.Order, error) {
-	args := m.Called()
+func (m *MockOrderRepository) GetAllOrders(ctx context.Context, filter models.OrderFilter) ([]*models.Order, error) {
+	args := m.Called(ctx, filter)
 	return args.Get(0).([]*models.Order), args.Error(1)
 }
 
-func (m *MockOrderRepository) GetByOrderID(id uint) (*models.Order, error) {
-	args := m.Called(id)
+func (m *MockOrderRepository) GetByOrderID(ctx context.Context, id uint) (*models.Order, error) {
+	args := m.Called(ctx, id)
 	return args.Get(0).(*models.Order), args.Error(1)
 }
 
-func (m *MockOrderRepository) UpdateByOrderID(order *models.Order) (*models.Order, error) {
-	args := m.Called(order)
+func (m *MockOrderRepository) GetForUpdate(ctx context.Context, id uint) (*models.Order, error) {
+	args := m.Called(ctx, id)
+	order, _ := args.Get(0).(*models.Order)
+	return order, args.Error(1)
+}
+
+func (m *MockOrderRepository) UpdateByOrderID(ctx context.Context, order *models.Order) (*models.Order, error) {
+	args := m.Called(ctx, order)
 	return args.Get(0).(*models.Order), args.Error(1)
 }
 
-func (m *MockOrderRepository) DeleteByOrderID(id uint) (*models.Order, error) {
-	args := m.Called(id)
+func (m *MockOrderRepository) UpdateStatus(ctx context.Context, id uint, from, to string) error {
+	args := m.Called(ctx, id, from, to)
+	return args.Error(0)
+}
+
+func (m *MockOrderRepository) DeleteByOrderID(ctx context.Context, id uint) (*models.Order, error) {
+	args := m.Called(ctx, id)
 	return args.Get(0).(*models.Order), args.Error(1)
 }
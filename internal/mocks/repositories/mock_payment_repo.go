@@ -0,0 +1,41 @@
+package mocks
+
+import (
+	"context"
+
+	"github.com/maithuc2003/Test_GIN_golang/internal/models"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/mock"
+)
+
+type MockPaymentRepo struct {
+	mock.Mock
+}
+
+func (m *MockPaymentRepo) Create(ctx context.Context, payment *models.Payment) error {
+	args := m.Called(ctx, payment)
+	return args.Error(0)
+}
+
+func (m *MockPaymentRepo) Update(ctx context.Context, payment *models.Payment) error {
+	args := m.Called(ctx, payment)
+	return args.Error(0)
+}
+
+func (m *MockPaymentRepo) AddRefund(ctx context.Context, id uint, amount decimal.Decimal) (*models.Payment, error) {
+	args := m.Called(ctx, id, amount)
+	payment, _ := args.Get(0).(*models.Payment)
+	return payment, args.Error(1)
+}
+
+func (m *MockPaymentRepo) GetByReference(ctx context.Context, provider, reference string) (*models.Payment, error) {
+	args := m.Called(ctx, provider, reference)
+	payment, _ := args.Get(0).(*models.Payment)
+	return payment, args.Error(1)
+}
+
+func (m *MockPaymentRepo) ListByOrder(ctx context.Context, orderID uint) ([]models.Payment, error) {
+	args := m.Called(ctx, orderID)
+	payments, _ := args.Get(0).([]models.Payment)
+	return payments, args.Error(1)
+}
@@ -0,0 +1,41 @@
+package mocks
+
+import (
+	"context"
+
+	"github.com/maithuc2003/Test_GIN_golang/internal/models"
+	"github.com/stretchr/testify/mock"
+)
+
+type MockPublisherRepo struct {
+	mock.Mock
+}
+
+func (m *MockPublisherRepo) GetAllPublishers(ctx context.Context) ([]models.Publisher, error) {
+	args := m.Called(ctx)
+	if publishers, ok := args.Get(0).([]models.Publisher); ok {
+		return publishers, args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+func (m *MockPublisherRepo) GetByPublisherID(ctx context.Context, id uint) (*models.Publisher, error) {
+	args := m.Called(ctx, id)
+	if publisher, ok := args.Get(0).(*models.Publisher); ok {
+		return publisher, args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+func (m *MockPublisherRepo) CreatePublisher(ctx context.Context, publisher *models.Publisher) error {
+	args := m.Called(ctx, publisher)
+	return args.Error(0)
+}
+
+func (m *MockPublisherRepo) UpdateById(ctx context.Context, publisher *models.Publisher) (*models.Publisher, error) {
+	args := m.Called(ctx, publisher)
+	if updated, ok := args.Get(0).(*models.Publisher); ok {
+		return updated, args.Error(1)
+	}
+	return nil, args.Error(1)
+}
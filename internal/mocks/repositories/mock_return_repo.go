@@ -0,0 +1,39 @@
+package mocks
+
+import (
+	"context"
+
+	"github.com/maithuc2003/Test_GIN_golang/internal/models"
+	"github.com/stretchr/testify/mock"
+)
+
+type MockReturnRepo struct {
+	mock.Mock
+}
+
+func (m *MockReturnRepo) Create(ctx context.Context, ret *models.ReturnRequest) error {
+	args := m.Called(ctx, ret)
+	return args.Error(0)
+}
+
+func (m *MockReturnRepo) GetByID(ctx context.Context, id uint) (*models.ReturnRequest, error) {
+	args := m.Called(ctx, id)
+	ret, _ := args.Get(0).(*models.ReturnRequest)
+	return ret, args.Error(1)
+}
+
+func (m *MockReturnRepo) List(ctx context.Context, filter models.ReturnFilter) ([]models.ReturnRequest, error) {
+	args := m.Called(ctx, filter)
+	returns, _ := args.Get(0).([]models.ReturnRequest)
+	return returns, args.Error(1)
+}
+
+func (m *MockReturnRepo) ReturnedQuantity(ctx context.Context, orderID uint, statuses ...string) (int, error) {
+	args := m.Called(ctx, orderID, statuses)
+	return args.Int(0), args.Error(1)
+}
+
+func (m *MockReturnRepo) SaveReview(ctx context.Context, ret *models.ReturnRequest) error {
+	args := m.Called(ctx, ret)
+	return args.Error(0)
+}
@@ -0,0 +1,23 @@
+package mocks
+
+import (
+	"context"
+
+	"github.com/maithuc2003/Test_GIN_golang/internal/interfaces/repositories"
+	"github.com/stretchr/testify/mock"
+)
+
+// MockTransactionManager chạy fn ngay với Repos (thường là các mock repository)
+// trừ khi expectation trả về lỗi.
+type MockTransactionManager struct {
+	mock.Mock
+	Repos repositories.Repositories
+}
+
+func (m *MockTransactionManager) WithinTransaction(ctx context.Context, fn func(ctx context.Context, repos repositories.Repositories) error) error {
+	args := m.Called(ctx, fn)
+	if err := args.Error(0); err != nil {
+		return err
+	}
+	return fn(ctx, m.Repos)
+}
@@ -1,6 +1,9 @@
 package mocks
 
 import (
+	"context"
+	"time"
+
 	"github.com/maithuc2003/Test_GIN_golang/internal/models"
 	"github.com/stretchr/testify/mock"
 )
@@ -9,17 +12,38 @@ type MockUserRepo struct {
 	mock.Mock
 }
 
-func (m *MockUserRepo) GetByUsername(username string) (*models.User, error) {
-	args := m.Called(username)
+func (m *MockUserRepo) GetByID(ctx context.Context, id uint) (*models.User, error) {
+	args := m.Called(ctx, id)
+	user, _ := args.Get(0).(*models.User)
+	return user, args.Error(1)
+}
+
+func (m *MockUserRepo) GetByUsername(ctx context.Context, username string) (*models.User, error) {
+	args := m.Called(ctx, username)
 	user, _ := args.Get(0).(*models.User)
 	return user, args.Error(1)
 }
 
-func (m *MockUserRepo) LoginUser(username string, password string) (*models.User, error) {
-	args := m.Called(username, password)
+func (m *MockUserRepo) LoginUser(ctx context.Context, username string, password string) (*models.User, error) {
+	args := m.Called(ctx, username, password)
 	user, ok := args.Get(0).(*models.User)
 	if !ok {
 		return nil, args.Error(1)
 	}
 	return user, args.Error(1)
 }
+
+func (m *MockUserRepo) RecordFailedLogin(ctx context.Context, id uint) (int, error) {
+	args := m.Called(ctx, id)
+	return args.Int(0), args.Error(1)
+}
+
+func (m *MockUserRepo) LockUntil(ctx context.Context, id uint, until time.Time) error {
+	args := m.Called(ctx, id, until)
+	return args.Error(0)
+}
+
+func (m *MockUserRepo) ResetFailedLogins(ctx context.Context, id uint) error {
+	args := m.Called(ctx, id)
+	return args.Error(0)
+}
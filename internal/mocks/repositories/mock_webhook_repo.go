@@ -0,0 +1,76 @@
+package mocks
+
+import (
+	"context"
+	"time"
+
+	"github.com/maithuc2003/Test_GIN_golang/internal/models"
+	"github.com/stretchr/testify/mock"
+)
+
+type MockWebhookRepo struct {
+	mock.Mock
+}
+
+func (m *MockWebhookRepo) ListSubscriptions(ctx context.Context) ([]models.WebhookSubscription, error) {
+	args := m.Called(ctx)
+	subs, _ := args.Get(0).([]models.WebhookSubscription)
+	return subs, args.Error(1)
+}
+
+func (m *MockWebhookRepo) ActiveSubscriptions(ctx context.Context, eventType string) ([]models.WebhookSubscription, error) {
+	args := m.Called(ctx, eventType)
+	subs, _ := args.Get(0).([]models.WebhookSubscription)
+	return subs, args.Error(1)
+}
+
+func (m *MockWebhookRepo) GetSubscription(ctx context.Context, id uint) (*models.WebhookSubscription, error) {
+	args := m.Called(ctx, id)
+	sub, _ := args.Get(0).(*models.WebhookSubscription)
+	return sub, args.Error(1)
+}
+
+func (m *MockWebhookRepo) CreateSubscription(ctx context.Context, sub *models.WebhookSubscription) error {
+	args := m.Called(ctx, sub)
+	return args.Error(0)
+}
+
+func (m *MockWebhookRepo) UpdateSubscription(ctx context.Context, sub *models.WebhookSubscription) (*models.WebhookSubscription, error) {
+	args := m.Called(ctx, sub)
+	updated, _ := args.Get(0).(*models.WebhookSubscription)
+	return updated, args.Error(1)
+}
+
+func (m *MockWebhookRepo) DeleteSubscription(ctx context.Context, id uint) (*models.WebhookSubscription, error) {
+	args := m.Called(ctx, id)
+	sub, _ := args.Get(0).(*models.WebhookSubscription)
+	return sub, args.Error(1)
+}
+
+func (m *MockWebhookRepo) EnqueueDeliveries(ctx context.Context, deliveries []models.WebhookDelivery) error {
+	args := m.Called(ctx, deliveries)
+	return args.Error(0)
+}
+
+func (m *MockWebhookRepo) ClaimDeliveries(ctx context.Context, now time.Time, lease time.Duration, limit int) ([]models.WebhookDelivery, error) {
+	args := m.Called(ctx, now, lease, limit)
+	deliveries, _ := args.Get(0).([]models.WebhookDelivery)
+	return deliveries, args.Error(1)
+}
+
+func (m *MockWebhookRepo) RecordAttempt(ctx context.Context, delivery *models.WebhookDelivery, attempt *models.WebhookDeliveryAttempt) error {
+	args := m.Called(ctx, delivery, attempt)
+	return args.Error(0)
+}
+
+func (m *MockWebhookRepo) ListDeliveries(ctx context.Context, subscriptionID uint, limit int) ([]models.WebhookDelivery, error) {
+	args := m.Called(ctx, subscriptionID, limit)
+	deliveries, _ := args.Get(0).([]models.WebhookDelivery)
+	return deliveries, args.Error(1)
+}
+
+func (m *MockWebhookRepo) Redeliver(ctx context.Context, subscriptionID, deliveryID uint, now time.Time) (*models.WebhookDelivery, error) {
+	args := m.Called(ctx, subscriptionID, deliveryID, now)
+	delivery, _ := args.Get(0).(*models.WebhookDelivery)
+	return delivery, args.Error(1)
+}
@@ -1,6 +1,8 @@
 package mocks
 
 import (
+	"context"
+
 	"github.com/maithuc2003/Test_GIN_golang/internal/models"
 	"github.com/stretchr/testify/mock"
 )
@@ -9,27 +11,40 @@ type MockAuthorService struct {
 	mock.Mock
 }
 
-func (m *MockAuthorService) GetAllAuthors() ([]*models.Author, error) {
-	args := m.Called()
+func (m *MockAuthorService) GetAllAuthors(ctx context.Context, filter models.AuthorFilter) ([]*models.Author, error) {
+	args := m.Called(ctx, filter)
 	return args.Get(0).([]*models.Author), args.Error(1)
 }
 
-func (m *MockAuthorService) GetByAuthorID(id int) (*models.Author, error) {
-	args := m.Called(id)
+func (m *MockAuthorService) GetByAuthorID(ctx context.Context, id int) (*models.Author, error) {
+	args := m.Called(ctx, id)
 	return args.Get(0).(*models.Author), args.Error(1)
 }
 
-func (m *MockAuthorService) CreateAuthor(author *models.Author) error {
-	args := m.Called(author)
+func (m *MockAuthorService) CreateAuthor(ctx context.Context, author *models.Author) error {
+	args := m.Called(ctx, author)
+	return args.Error(0)
+}
+
+func (m *MockAuthorService) CreateAuthorWithBooks(ctx context.Context, author *models.Author, books []*models.Book) error {
+	args := m.Called(ctx, author, books)
 	return args.Error(0)
 }
 
-func (m *MockAuthorService) DeleteById(id int) (*models.Author, error) {
-	args := m.Called(id)
+func (m *MockAuthorService) DeleteById(ctx context.Context, id int) (*models.Author, error) {
+	args := m.Called(ctx, id)
 	return args.Get(0).(*models.Author), args.Error(1)
 }
 
-func (m *MockAuthorService) UpdateById(author *models.Author) (*models.Author, error) {
-	args := m.Called(author)
+func (m *MockAuthorService) UpdateById(ctx context.Context, author *models.Author) (*models.Author, error) {
+	args := m.Called(ctx, author)
 	return args.Get(0).(*models.Author), args.Error(1)
 }
+
+func (m *MockAuthorService) MergeAuthors(ctx context.Context, targetID int, sourceIDs []int) (*models.AuthorMergeResult, error) {
+	args := m.Called(ctx, targetID, sourceIDs)
+	if result, ok := args.Get(0).(*models.AuthorMergeResult); ok {
+		return result, args.Error(1)
+	}
+	return nil, args.Error(1)
+}
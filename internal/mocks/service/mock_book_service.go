@@ -1,6 +1,8 @@
 package mocks
 
 import (
+	"context"
+
 	"github.com/maithuc2003/Test_GIN_golang/internal/models"
 	"github.com/stretchr/testify/mock"
 )
@@ -9,27 +11,51 @@ type MockBookService struct {
 	mock.Mock
 }
 
-func (m *MockBookService) CreateBook(book *models.Book) error {
-	args := m.Called(book)
+func (m *MockBookService) CreateBook(ctx context.Context, book *models.Book) error {
+	args := m.Called(ctx, book)
 	return args.Error(0)
 }
 
-func (m *MockBookService) GetAllBooks() ([]models.Book, error) {
-	args := m.Called()
+func (m *MockBookService) GetAllBooks(ctx context.Context, filter models.BookFilter) ([]models.Book, error) {
+	args := m.Called(ctx, filter)
 	return args.Get(0).([]models.Book), args.Error(1)
 }
 
-func (m *MockBookService) GetByBookID(id int) (*models.Book, error) {
-	args := m.Called(id)
+func (m *MockBookService) GetByBookIDWithAuthors(ctx context.Context, id int) (*models.Book, error) {
+	args := m.Called(ctx, id)
+	book, _ := args.Get(0).(*models.Book)
+	return book, args.Error(1)
+}
+
+func (m *MockBookService) GetBooksByAuthor(ctx context.Context, authorID int, filter models.BookFilter) ([]models.Book, error) {
+	args := m.Called(ctx, authorID, filter)
+	books, _ := args.Get(0).([]models.Book)
+	return books, args.Error(1)
+}
+
+func (m *MockBookService) GetBooksByCategory(ctx context.Context, categoryID uint, filter models.BookFilter) ([]models.Book, error) {
+	args := m.Called(ctx, categoryID, filter)
+	books, _ := args.Get(0).([]models.Book)
+	return books, args.Error(1)
+}
+
+func (m *MockBookService) GetCategoryFacets(ctx context.Context, filter models.BookFilter) ([]models.CategoryFacet, error) {
+	args := m.Called(ctx, filter)
+	facets, _ := args.Get(0).([]models.CategoryFacet)
+	return facets, args.Error(1)
+}
+
+func (m *MockBookService) GetByBookID(ctx context.Context, id int) (*models.Book, error) {
+	args := m.Called(ctx, id)
 	return args.Get(0).(*models.Book), args.Error(1)
 }
 
-func (m *MockBookService) DeleteById(id int) (*models.Book, error) {
-	args := m.Called(id)
+func (m *MockBookService) DeleteById(ctx context.Context, id int) (*models.Book, error) {
+	args := m.Called(ctx, id)
 	return args.Get(0).(*models.Book), args.Error(1)
 }
 
-func (m *MockBookService) UpdateById(book *models.Book) (*models.Book, error) {
-	args := m.Called(book)
+func (m *MockBookService) UpdateById(ctx context.Context, book *models.Book) (*models.Book, error) {
+	args := m.Called(ctx, book)
 	return args.Get(0).(*models.Book), args.Error(1)
 }
@@ -0,0 +1,47 @@
+package mocks
+
+import (
+	"context"
+
+	"github.com/maithuc2003/Test_GIN_golang/internal/models"
+	"github.com/stretchr/testify/mock"
+)
+
+type MockCartService struct {
+	mock.Mock
+}
+
+func (m *MockCartService) GetCart(ctx context.Context, userID uint) (*models.CartView, error) {
+	args := m.Called(ctx, userID)
+	cart, _ := args.Get(0).(*models.CartView)
+	return cart, args.Error(1)
+}
+
+func (m *MockCartService) AddItem(ctx context.Context, userID uint, item models.CartItemRequest) (*models.CartView, error) {
+	args := m.Called(ctx, userID, item)
+	cart, _ := args.Get(0).(*models.CartView)
+	return cart, args.Error(1)
+}
+
+func (m *MockCartService) UpdateItem(ctx context.Context, userID uint, item models.CartItemRequest) (*models.CartView, error) {
+	args := m.Called(ctx, userID, item)
+	cart, _ := args.Get(0).(*models.CartView)
+	return cart, args.Error(1)
+}
+
+func (m *MockCartService) RemoveItem(ctx context.Context, userID, bookID uint) (*models.CartView, error) {
+	args := m.Called(ctx, userID, bookID)
+	cart, _ := args.Get(0).(*models.CartView)
+	return cart, args.Error(1)
+}
+
+func (m *MockCartService) Checkout(ctx context.Context, userID uint) ([]*models.Order, error) {
+	args := m.Called(ctx, userID)
+	orders, _ := args.Get(0).([]*models.Order)
+	return orders, args.Error(1)
+}
+
+func (m *MockCartService) PurgeExpired(ctx context.Context) (int64, error) {
+	args := m.Called(ctx)
+	return args.Get(0).(int64), args.Error(1)
+}
@@ -0,0 +1,41 @@
+package mocks
+
+import (
+	"context"
+
+	"github.com/maithuc2003/Test_GIN_golang/internal/models"
+	"github.com/stretchr/testify/mock"
+)
+
+type MockCategoryService struct {
+	mock.Mock
+}
+
+func (m *MockCategoryService) GetCategoryTree(ctx context.Context) ([]models.Category, error) {
+	args := m.Called(ctx)
+	tree, _ := args.Get(0).([]models.Category)
+	return tree, args.Error(1)
+}
+
+func (m *MockCategoryService) GetByCategoryID(ctx context.Context, id uint) (*models.Category, error) {
+	args := m.Called(ctx, id)
+	category, _ := args.Get(0).(*models.Category)
+	return category, args.Error(1)
+}
+
+func (m *MockCategoryService) CreateCategory(ctx context.Context, category *models.Category) error {
+	args := m.Called(ctx, category)
+	return args.Error(0)
+}
+
+func (m *MockCategoryService) UpdateById(ctx context.Context, category *models.Category) (*models.Category, error) {
+	args := m.Called(ctx, category)
+	updated, _ := args.Get(0).(*models.Category)
+	return updated, args.Error(1)
+}
+
+func (m *MockCategoryService) DeleteById(ctx context.Context, id uint) (*models.Category, error) {
+	args := m.Called(ctx, id)
+	deleted, _ := args.Get(0).(*models.Category)
+	return deleted, args.Error(1)
+}
@@ -0,0 +1,41 @@
+package mocks
+
+import (
+	"context"
+
+	"github.com/maithuc2003/Test_GIN_golang/internal/models"
+	"github.com/stretchr/testify/mock"
+)
+
+type MockCouponService struct {
+	mock.Mock
+}
+
+func (m *MockCouponService) GetAllCoupons(ctx context.Context) ([]models.Coupon, error) {
+	args := m.Called(ctx)
+	coupons, _ := args.Get(0).([]models.Coupon)
+	return coupons, args.Error(1)
+}
+
+func (m *MockCouponService) GetByCouponID(ctx context.Context, id uint) (*models.Coupon, error) {
+	args := m.Called(ctx, id)
+	coupon, _ := args.Get(0).(*models.Coupon)
+	return coupon, args.Error(1)
+}
+
+func (m *MockCouponService) CreateCoupon(ctx context.Context, coupon *models.Coupon) error {
+	args := m.Called(ctx, coupon)
+	return args.Error(0)
+}
+
+func (m *MockCouponService) UpdateById(ctx context.Context, coupon *models.Coupon) (*models.Coupon, error) {
+	args := m.Called(ctx, coupon)
+	updated, _ := args.Get(0).(*models.Coupon)
+	return updated, args.Error(1)
+}
+
+func (m *MockCouponService) DeleteById(ctx context.Context, id uint) (*models.Coupon, error) {
+	args := m.Called(ctx, id)
+	coupon, _ := args.Get(0).(*models.Coupon)
+	return coupon, args.Error(1)
+}
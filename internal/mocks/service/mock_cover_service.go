@@ -0,0 +1,18 @@
+package mocks
+
+import (
+	"context"
+
+	"github.com/maithuc2003/Test_GIN_golang/internal/models"
+	"github.com/stretchr/testify/mock"
+)
+
+type MockCoverService struct {
+	mock.Mock
+}
+
+func (m *MockCoverService) UploadCover(ctx context.Context, bookID int, data []byte) (*models.Book, error) {
+	args := m.Called(ctx, bookID, data)
+	book, _ := args.Get(0).(*models.Book)
+	return book, args.Error(1)
+}
@@ -0,0 +1,28 @@
+package mocks
+
+import (
+	"context"
+	"io"
+
+	"github.com/maithuc2003/Test_GIN_golang/internal/models"
+	"github.com/stretchr/testify/mock"
+)
+
+type MockExportService struct {
+	mock.Mock
+}
+
+func (m *MockExportService) ExportBooks(ctx context.Context, w io.Writer, format string, filter models.BookFilter) error {
+	args := m.Called(ctx, w, format, filter)
+	return args.Error(0)
+}
+
+func (m *MockExportService) ExportAuthors(ctx context.Context, w io.Writer, format string, filter models.AuthorFilter) error {
+	args := m.Called(ctx, w, format, filter)
+	return args.Error(0)
+}
+
+func (m *MockExportService) ExportOrders(ctx context.Context, w io.Writer, format string, filter models.OrderFilter) error {
+	args := m.Called(ctx, w, format, filter)
+	return args.Error(0)
+}
@@ -0,0 +1,19 @@
+package mocks
+
+import (
+	"context"
+	"io"
+
+	"github.com/maithuc2003/Test_GIN_golang/internal/models"
+	"github.com/stretchr/testify/mock"
+)
+
+type MockImportService struct {
+	mock.Mock
+}
+
+func (m *MockImportService) ImportBooks(ctx context.Context, r io.Reader, opts models.ImportOptions) (*models.ImportReport, error) {
+	args := m.Called(ctx, r, opts)
+	report, _ := args.Get(0).(*models.ImportReport)
+	return report, args.Error(1)
+}
@@ -0,0 +1,18 @@
+package mocks
+
+import (
+	"context"
+
+	"github.com/maithuc2003/Test_GIN_golang/internal/models"
+	"github.com/stretchr/testify/mock"
+)
+
+type MockInvoiceService struct {
+	mock.Mock
+}
+
+func (m *MockInvoiceService) GetInvoice(ctx context.Context, userID, orderID uint) (*models.Invoice, error) {
+	args := m.Called(ctx, userID, orderID)
+	invoice, _ := args.Get(0).(*models.Invoice)
+	return invoice, args.Error(1)
+}
@@ -1,6 +1,8 @@
 package mocks
 
 import (
+	"context"
+
 	"github.com/maithuc2003/Test_GIN_golang/internal/models"
 	"github.com/stretchr/testify/mock"
 )
@@ -9,37 +11,42 @@ type MockOrderService struct {
 	mock.Mock
 }
 
-func (m *MockOrderService) CreateOrder(order *models.Order) error {
-	args := m.Called(order)
+func (m *MockOrderService) CreateOrder(ctx context.Context, order *models.Order) error {
+	args := m.Called(ctx, order)
 	return args.Error(0)
 }
 
-func (m *MockOrderService) GetAllOrders() ([]*models.Order, error) {
-	args := m.Called()
+func (m *MockOrderService) GetAllOrders(ctx context.Context, filter models.OrderFilter) ([]*models.Order, error) {
+	args := m.Called(ctx, filter)
 	return args.Get(0).([]*models.Order), args.Error(1)
 }
 
 
-func (m *MockOrderService) GetByOrderID(id int) (*models.Order, error) {
-	args := m.Called(id)
+func (m *MockOrderService) GetByOrderID(ctx context.Context, id int) (*models.Order, error) {
+	args := m.Called(ctx, id)
 	if args.Get(0) == nil {
 		return nil, args.Error(1)
 	}
 	return args.Get(0).(*models.Order), args.Error(1)
 }
 
-func (m *MockOrderService) DeleteByOrderID(id int) (*models.Order, error) {
-	args := m.Called(id)
+func (m *MockOrderService) DeleteByOrderID(ctx context.Context, id int) (*models.Order, error) {
+	args := m.Called(ctx, id)
 	if args.Get(0) != nil {
 		return args.Get(0).(*models.Order), args.Error(1)
 	}
 	return nil, args.Error(1)
 }
 
-func (m *MockOrderService) UpdateByOrderID(order *models.Order) (*models.Order, error) {
-	args := m.Called(order)
+func (m *MockOrderService) UpdateByOrderID(ctx context.Context, order *models.Order) (*models.Order, error) {
+	args := m.Called(ctx, order)
 	if args.Get(0) != nil {
 		return args.Get(0).(*models.Order), args.Error(1)
 	}
 	return nil, args.Error(1)
 }
+
+func (m *MockOrderService) ReleaseExpiredReservations(ctx context.Context) (int64, error) {
+	args := m.Called(ctx)
+	return args.Get(0).(int64), args.Error(1)
+}
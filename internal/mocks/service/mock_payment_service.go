@@ -0,0 +1,29 @@
+package mocks
+
+import (
+	"context"
+
+	"github.com/maithuc2003/Test_GIN_golang/internal/models"
+	"github.com/stretchr/testify/mock"
+)
+
+type MockPaymentService struct {
+	mock.Mock
+}
+
+func (m *MockPaymentService) Pay(ctx context.Context, userID, orderID uint, req models.PayRequest) (*models.Payment, error) {
+	args := m.Called(ctx, userID, orderID, req)
+	payment, _ := args.Get(0).(*models.Payment)
+	return payment, args.Error(1)
+}
+
+func (m *MockPaymentService) HandleWebhook(ctx context.Context, payload []byte, signature string) error {
+	args := m.Called(ctx, payload, signature)
+	return args.Error(0)
+}
+
+func (m *MockPaymentService) GetPayments(ctx context.Context, userID, orderID uint) ([]models.Payment, error) {
+	args := m.Called(ctx, userID, orderID)
+	payments, _ := args.Get(0).([]models.Payment)
+	return payments, args.Error(1)
+}
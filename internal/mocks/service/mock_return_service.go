@@ -0,0 +1,42 @@
+package mocks
+
+import (
+	"context"
+
+	"github.com/maithuc2003/Test_GIN_golang/internal/models"
+	"github.com/stretchr/testify/mock"
+)
+
+type MockReturnService struct {
+	mock.Mock
+}
+
+func (m *MockReturnService) RequestReturn(ctx context.Context, userID, orderID uint, req models.ReturnCreateRequest) (*models.ReturnRequest, error) {
+	args := m.Called(ctx, userID, orderID, req)
+	ret, _ := args.Get(0).(*models.ReturnRequest)
+	return ret, args.Error(1)
+}
+
+func (m *MockReturnService) GetOrderReturns(ctx context.Context, userID, orderID uint) ([]models.ReturnRequest, error) {
+	args := m.Called(ctx, userID, orderID)
+	returns, _ := args.Get(0).([]models.ReturnRequest)
+	return returns, args.Error(1)
+}
+
+func (m *MockReturnService) ListReturns(ctx context.Context, filter models.ReturnFilter) ([]models.ReturnRequest, error) {
+	args := m.Called(ctx, filter)
+	returns, _ := args.Get(0).([]models.ReturnRequest)
+	return returns, args.Error(1)
+}
+
+func (m *MockReturnService) Approve(ctx context.Context, reviewerID, returnID uint, req models.ReturnReviewRequest) (*models.ReturnRequest, error) {
+	args := m.Called(ctx, reviewerID, returnID, req)
+	ret, _ := args.Get(0).(*models.ReturnRequest)
+	return ret, args.Error(1)
+}
+
+func (m *MockReturnService) Reject(ctx context.Context, reviewerID, returnID uint, req models.ReturnReviewRequest) (*models.ReturnRequest, error) {
+	args := m.Called(ctx, reviewerID, returnID, req)
+	ret, _ := args.Get(0).(*models.ReturnRequest)
+	return ret, args.Error(1)
+}
@@ -1,6 +1,8 @@
 package mocks
 
 import (
+	"context"
+
 	"github.com/maithuc2003/Test_GIN_golang/internal/models"
 	"github.com/stretchr/testify/mock"
 )
@@ -9,14 +11,14 @@ type MockUserService struct {
 	mock.Mock
 }
 
-func (m *MockUserService) GetByUsername(username string) (*models.User, error) {
-	args := m.Called(username)
+func (m *MockUserService) GetByUsername(ctx context.Context, username string) (*models.User, error) {
+	args := m.Called(ctx, username)
 	user, _ := args.Get(0).(*models.User)
 	return user, args.Error(1)
 }
 
-func (m *MockUserService) LoginUser(username string, password string) (*models.User, error) {
-	args := m.Called(username, password)
+func (m *MockUserService) LoginUser(ctx context.Context, username string, password string) (*models.User, error) {
+	args := m.Called(ctx, username, password)
 	user, ok := args.Get(0).(*models.User)
 	if !ok {
 		return nil, args.Error(1)
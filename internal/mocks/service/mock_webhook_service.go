@@ -0,0 +1,53 @@
+package mocks
+
+import (
+	"context"
+
+	"github.com/maithuc2003/Test_GIN_golang/internal/models"
+	"github.com/stretchr/testify/mock"
+)
+
+type MockWebhookService struct {
+	mock.Mock
+}
+
+func (m *MockWebhookService) GetAllSubscriptions(ctx context.Context) ([]models.WebhookSubscription, error) {
+	args := m.Called(ctx)
+	subs, _ := args.Get(0).([]models.WebhookSubscription)
+	return subs, args.Error(1)
+}
+
+func (m *MockWebhookService) GetBySubscriptionID(ctx context.Context, id uint) (*models.WebhookSubscription, error) {
+	args := m.Called(ctx, id)
+	sub, _ := args.Get(0).(*models.WebhookSubscription)
+	return sub, args.Error(1)
+}
+
+func (m *MockWebhookService) CreateSubscription(ctx context.Context, sub *models.WebhookSubscription) error {
+	args := m.Called(ctx, sub)
+	return args.Error(0)
+}
+
+func (m *MockWebhookService) UpdateById(ctx context.Context, sub *models.WebhookSubscription) (*models.WebhookSubscription, error) {
+	args := m.Called(ctx, sub)
+	updated, _ := args.Get(0).(*models.WebhookSubscription)
+	return updated, args.Error(1)
+}
+
+func (m *MockWebhookService) DeleteById(ctx context.Context, id uint) (*models.WebhookSubscription, error) {
+	args := m.Called(ctx, id)
+	sub, _ := args.Get(0).(*models.WebhookSubscription)
+	return sub, args.Error(1)
+}
+
+func (m *MockWebhookService) GetDeliveries(ctx context.Context, subscriptionID uint) ([]models.WebhookDelivery, error) {
+	args := m.Called(ctx, subscriptionID)
+	deliveries, _ := args.Get(0).([]models.WebhookDelivery)
+	return deliveries, args.Error(1)
+}
+
+func (m *MockWebhookService) Redeliver(ctx context.Context, subscriptionID, deliveryID uint) (*models.WebhookDelivery, error) {
+	args := m.Called(ctx, subscriptionID, deliveryID)
+	delivery, _ := args.Get(0).(*models.WebhookDelivery)
+	return delivery, args.Error(1)
+}
@@ -1,11 +1,52 @@
 package models
 
-import "time"
+import (
+	"time"
+
+	"github.com/maithuc2003/Test_GIN_golang/pkg/textnorm"
+	"gorm.io/gorm"
+)
 
 type Author struct {
-	ID          int       `gorm:"primaryKey;autoIncrement" json:"id"`
-	Name        string    `gorm:"type:varchar(100);not null" json:"name"`
-	Nationality string    `gorm:"type:varchar(100)" json:"nationality"`
-	CreatedAt   time.Time `json:"created_at"`
-	UpdatedAt   time.Time `json:"updated_at"`
+	ID          int    `gorm:"primaryKey;autoIncrement" json:"id"`
+	Name        string `gorm:"type:varchar(100);not null" json:"name"`
+	Nationality string `gorm:"type:varchar(100)" json:"nationality"`
+	// NormalizedName là tên đã bỏ dấu, chữ thường; unique index đảm bảo không trùng tên
+	NormalizedName string    `gorm:"type:varchar(100);uniqueIndex:idx_authors_normalized_name" json:"-"`
+	Books          []Book    `gorm:"many2many:book_authors" json:"books,omitempty"`
+	CreatedAt      time.Time `json:"created_at"`
+	UpdatedAt      time.Time `json:"updated_at"`
+}
+
+// BeforeSave luôn tính lại NormalizedName từ Name
+func (a *Author) BeforeSave(tx *gorm.DB) error {
+	a.NormalizedName = textnorm.Name(a.Name)
+	return nil
+}
+
+// AuthorAlias lưu tên cũ của tác giả đã được gộp để tra cứu theo tên vẫn ra tác giả đích
+type AuthorAlias struct {
+	ID             uint      `gorm:"primaryKey;autoIncrement" json:"id"`
+	AuthorID       int       `gorm:"not null;index" json:"author_id"`
+	Name           string    `gorm:"type:varchar(100);not null" json:"name"`
+	NormalizedName string    `gorm:"type:varchar(100);uniqueIndex:idx_author_aliases_normalized_name" json:"-"`
+	CreatedAt      time.Time `json:"created_at"`
+}
+
+func (AuthorAlias) TableName() string {
+	return "author_aliases"
+}
+
+// BeforeSave luôn tính lại NormalizedName từ Name
+func (a *AuthorAlias) BeforeSave(tx *gorm.DB) error {
+	a.NormalizedName = textnorm.Name(a.Name)
+	return nil
+}
+
+// AuthorMergeResult mô tả kết quả gộp các tác giả nguồn vào tác giả đích
+type AuthorMergeResult struct {
+	Author     *Author  `json:"author"`
+	MergedIDs  []int    `json:"merged_ids"`
+	Aliases    []string `json:"aliases"`
+	BooksMoved int64    `json:"books_moved"`
 }
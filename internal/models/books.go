@@ -2,13 +2,61 @@ package models
 
 import (
 	"time"
+
+	"github.com/shopspring/decimal"
 )
 
 type Book struct {
-	ID        uint       `json:"id" gorm:"primaryKey;autoIncrement"`
-	Title     string    `json:"title"`
-	Stock     int       `json:"stock"`
-	AuthorID  int       `json:"author_id"`
-	CreatedAt time.Time `json:"created_at"`
-	UpdatedAt time.Time `json:"updated_at"`
+	ID       uint   `json:"id" gorm:"primaryKey;autoIncrement"`
+	Title    string `json:"title"`
+	Stock    int    `json:"stock"`
+	AuthorID int    `json:"author_id"`
+	// ReservedStock là số cuốn đang giữ cho đơn chờ thanh toán, AvailableStock =
+	// Stock - ReservedStock là số còn bán được; chỉ điền khi đọc
+	ReservedStock  int `json:"reserved_stock" gorm:"-"`
+	AvailableStock int `json:"available_stock" gorm:"-"`
+	// Author là tác giả chính (belongs-to qua AuthorID)
+	Author *Author `json:"author,omitempty" gorm:"foreignKey:AuthorID"`
+	// Authors là toàn bộ tác giả của sách đồng tác giả (many-to-many qua book_authors)
+	Authors []Author `json:"authors,omitempty" gorm:"many2many:book_authors"`
+	// AuthorIDs chỉ dùng cho input: danh sách đồng tác giả cần gán
+	AuthorIDs []int `json:"author_ids,omitempty" gorm:"-"`
+	// Categories là các thể loại của sách (many-to-many qua book_categories)
+	Categories []Category `json:"categories,omitempty" gorm:"many2many:book_categories"`
+	// CategoryIDs chỉ dùng cho input: danh sách thể loại cần gán
+	CategoryIDs []uint `json:"category_ids,omitempty" gorm:"-"`
+	// ISBN luôn được lưu ở dạng ISBN-13 không dấu gạch
+	ISBN        *string    `json:"isbn,omitempty" gorm:"type:varchar(13);uniqueIndex:idx_books_isbn"`
+	PublisherID *uint      `json:"publisher_id,omitempty" gorm:"index"`
+	Publisher   *Publisher `json:"publisher,omitempty" gorm:"foreignKey:PublisherID"`
+	// Price là giá bìa, Currency là mã ISO 4217 (VND, USD...)
+	Price    *decimal.Decimal `json:"price,omitempty" gorm:"type:decimal(12,2)"`
+	Currency string           `json:"currency,omitempty" gorm:"type:char(3)"`
+	// Language là BCP 47 tag (vi, en, en-US...)
+	Language    string     `json:"language,omitempty" gorm:"type:varchar(35)"`
+	PageCount   int        `json:"page_count,omitempty"`
+	PublishedAt *time.Time `json:"published_at,omitempty" gorm:"type:date"`
+	Description string     `json:"description,omitempty" gorm:"type:text"`
+	// CoverKey là thư mục chứa ảnh bìa trong storage (vd: covers/12/9f3a...)
+	CoverKey  string     `json:"-" gorm:"type:varchar(255)"`
+	Cover     *BookCover `json:"cover,omitempty" gorm:"serializer:json;type:text"`
+	CreatedAt time.Time  `json:"created_at"`
+	UpdatedAt time.Time  `json:"updated_at"`
+}
+
+// BookCover chứa URL ảnh bìa gốc và các thumbnail đã sinh sẵn
+type BookCover struct {
+	URL          string `json:"url"`
+	MediumURL    string `json:"medium_url"`
+	ThumbnailURL string `json:"thumbnail_url"`
+}
+
+// BookAuthor là bảng nối many-to-many giữa books và authors
+type BookAuthor struct {
+	BookID   uint `gorm:"primaryKey"`
+	AuthorID int  `gorm:"primaryKey;index"`
+}
+
+func (BookAuthor) TableName() string {
+	return "book_authors"
 }
@@ -0,0 +1,57 @@
+package models
+
+import (
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+// Cart là giỏ hàng của một user; mỗi user có tối đa một giỏ. ExpiresAt được gia hạn
+// mỗi lần giỏ thay đổi, quá hạn thì giỏ bị coi như rỗng và bị dọn.
+type Cart struct {
+	ID        uint       `gorm:"primaryKey;autoIncrement" json:"id"`
+	UserID    uint       `gorm:"uniqueIndex" json:"user_id"`
+	Items     []CartItem `gorm:"foreignKey:CartID;constraint:OnDelete:CASCADE" json:"items"`
+	ExpiresAt time.Time  `gorm:"index" json:"expires_at"`
+	CreatedAt time.Time  `json:"created_at"`
+	UpdatedAt time.Time  `json:"updated_at"`
+}
+
+type CartItem struct {
+	ID        uint      `gorm:"primaryKey;autoIncrement" json:"id"`
+	CartID    uint      `gorm:"uniqueIndex:idx_cart_items_cart_book" json:"cart_id"`
+	BookID    uint      `gorm:"uniqueIndex:idx_cart_items_cart_book" json:"book_id"`
+	Book      *Book     `gorm:"foreignKey:BookID" json:"book,omitempty"`
+	Quantity  int       `json:"quantity"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// CartView là giỏ hàng trả cho client, kèm giá và tồn kho hiện tại của từng sách
+type CartView struct {
+	UserID uint       `json:"user_id"`
+	Lines  []CartLine `json:"lines"`
+	// Totals là tổng tiền theo từng loại tiền tệ (giỏ có thể chứa sách khác currency)
+	Totals    map[string]decimal.Decimal `json:"totals"`
+	ExpiresAt *time.Time                 `json:"expires_at,omitempty"`
+}
+
+type CartLine struct {
+	BookID    uint             `json:"book_id"`
+	Title     string           `json:"title"`
+	Quantity  int              `json:"quantity"`
+	UnitPrice *decimal.Decimal `json:"unit_price,omitempty"`
+	LineTotal *decimal.Decimal `json:"line_total,omitempty"`
+	Currency  string           `json:"currency,omitempty"`
+	Stock     int              `json:"stock"`
+	// AvailableStock là tồn kho còn bán được (đã trừ phần đang giữ cho đơn chờ thanh toán)
+	AvailableStock int `json:"available_stock"`
+	// Available cho biết tồn kho còn bán được có đủ cho số lượng trong giỏ hay không
+	Available bool `json:"available"`
+}
+
+// CartItemRequest là body của các endpoint thêm / sửa dòng trong giỏ
+type CartItemRequest struct {
+	BookID   uint `json:"book_id"`
+	Quantity int  `json:"quantity"`
+}
@@ -0,0 +1,41 @@
+package models
+
+import "time"
+
+// Category là một nút trong cây thể loại; ParentID = nil là thể loại gốc
+type Category struct {
+	ID        uint       `json:"id" gorm:"primaryKey;autoIncrement"`
+	Name      string     `json:"name" gorm:"type:varchar(100);not null;uniqueIndex:idx_categories_parent_name,priority:2"`
+	ParentID  *uint      `json:"parent_id,omitempty" gorm:"index;uniqueIndex:idx_categories_parent_name,priority:1"`
+	Children  []Category `json:"children,omitempty" gorm:"foreignKey:ParentID"`
+	CreatedAt time.Time  `json:"created_at"`
+	UpdatedAt time.Time  `json:"updated_at"`
+}
+
+// BookCategory là bảng nối nhiều-nhiều giữa sách và thể loại
+type BookCategory struct {
+	BookID     uint `gorm:"primaryKey"`
+	CategoryID uint `gorm:"primaryKey;index"`
+}
+
+func (BookCategory) TableName() string {
+	return "book_categories"
+}
+
+// CategoryFacet là số sách khớp bộ lọc trong một thể loại (tính cả thể loại con)
+type CategoryFacet struct {
+	CategoryID uint   `json:"category_id"`
+	Name       string `json:"name"`
+	ParentID   *uint  `json:"parent_id,omitempty"`
+	Count      int    `json:"count"`
+}
+
+// BookListResponse được trả về thay cho mảng sách khi client yêu cầu facets
+type BookListResponse struct {
+	Books  []Book         `json:"books"`
+	Facets BookListFacets `json:"facets"`
+}
+
+type BookListFacets struct {
+	Categories []CategoryFacet `json:"categories"`
+}
@@ -0,0 +1,48 @@
+package models
+
+import (
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+// Loại giảm giá của coupon
+const (
+	// CouponTypePercentage: Value là phần trăm (0-100] trên giá trị được giảm
+	CouponTypePercentage = "percentage"
+	// CouponTypeFixed: Value là số tiền theo Currency
+	CouponTypeFixed = "fixed"
+)
+
+// Coupon là một chương trình khuyến mãi dùng bằng mã. BookIDs/CategoryIDs rỗng thì áp
+// dụng cho mọi sách; thể loại cha bao gồm cả sách của các thể loại con.
+type Coupon struct {
+	ID          uint            `gorm:"primaryKey;autoIncrement" json:"id"`
+	Code        string          `gorm:"type:varchar(64);uniqueIndex" json:"code"`
+	Description string          `gorm:"type:varchar(255)" json:"description,omitempty"`
+	Type        string          `gorm:"type:varchar(16)" json:"type"`
+	Value       decimal.Decimal `gorm:"type:decimal(12,2)" json:"value"`
+	// Currency bắt buộc với coupon fixed hoặc có MinOrderValue
+	Currency      string           `gorm:"type:char(3)" json:"currency,omitempty"`
+	BookIDs       []uint           `gorm:"serializer:json;type:text" json:"book_ids,omitempty"`
+	CategoryIDs   []uint           `gorm:"serializer:json;type:text" json:"category_ids,omitempty"`
+	MinOrderValue *decimal.Decimal `gorm:"type:decimal(12,2)" json:"min_order_value,omitempty"`
+	// MaxUses giới hạn tổng số lần dùng, MaxUsesPerUser giới hạn mỗi user; nil là không giới hạn
+	MaxUses        *int       `json:"max_uses,omitempty"`
+	MaxUsesPerUser *int       `json:"max_uses_per_user,omitempty"`
+	UsedCount      int        `gorm:"default:0" json:"used_count"`
+	StartsAt       *time.Time `json:"starts_at,omitempty"`
+	EndsAt         *time.Time `json:"ends_at,omitempty"`
+	Active         bool       `json:"active"`
+	CreatedAt      time.Time  `json:"created_at"`
+	UpdatedAt      time.Time  `json:"updated_at"`
+}
+
+// CouponRedemption ghi lại mỗi lần coupon được dùng cho một đơn
+type CouponRedemption struct {
+	ID        uint `gorm:"primaryKey;autoIncrement"`
+	CouponID  uint `gorm:"index:idx_coupon_redemptions_coupon_user"`
+	UserID    uint `gorm:"index:idx_coupon_redemptions_coupon_user"`
+	OrderID   uint `gorm:"index"`
+	CreatedAt time.Time
+}
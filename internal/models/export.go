@@ -0,0 +1,25 @@
+package models
+
+import (
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+// OrderExportRow là đơn hàng kèm tên sách, username và số tiền đã chốt để xuất file
+type OrderExportRow struct {
+	ID            uint            `json:"id"`
+	BookID        uint            `json:"book_id"`
+	BookTitle     string          `json:"book_title"`
+	UserID        uint            `json:"user_id"`
+	Username      string          `json:"username"`
+	Quantity      int             `json:"quantity"`
+	Status        string          `json:"status"`
+	Currency      string          `json:"currency"`
+	Subtotal      decimal.Decimal `json:"subtotal"`
+	DiscountTotal decimal.Decimal `json:"discount_total"`
+	TaxTotal      decimal.Decimal `json:"tax_total"`
+	GrandTotal    decimal.Decimal `json:"grand_total"`
+	OrderedAt     time.Time       `json:"ordered_at"`
+	UpdatedAt     time.Time       `json:"updated_at"`
+}
@@ -0,0 +1,59 @@
+package models
+
+import (
+	"strings"
+	"time"
+)
+
+// Bộ lọc dùng chung cho endpoint danh sách và export, bind từ query string
+
+type BookFilter struct {
+	Title    string `form:"title"`
+	AuthorID int    `form:"author_id"`
+	InStock  *bool  `form:"in_stock"`
+	// Include=author,publisher,categories để preload quan hệ của sách
+	Include string `form:"include"`
+	// CategoryID lọc sách thuộc thể loại này hoặc các thể loại con
+	CategoryID uint `form:"category_id"`
+	// Facets=categories để trả kèm số sách theo thể loại
+	Facets      string   `form:"facets"`
+	ISBN        string   `form:"isbn"`
+	PublisherID uint     `form:"publisher_id"`
+	Language    string   `form:"language"`
+	Currency    string   `form:"currency"`
+	MinPrice    *float64 `form:"min_price"`
+	MaxPrice    *float64 `form:"max_price"`
+	// Khoảng ngày xuất bản, định dạng YYYY-MM-DD
+	PublishedFrom time.Time `form:"published_from" time_format:"2006-01-02"`
+	PublishedTo   time.Time `form:"published_to" time_format:"2006-01-02"`
+}
+
+// Includes kiểm tra include có chứa quan hệ name hay không (vd: include=author,categories)
+func (f BookFilter) Includes(name string) bool {
+	return listContains(f.Include, name)
+}
+
+// HasFacet kiểm tra facets có yêu cầu name hay không (vd: facets=categories)
+func (f BookFilter) HasFacet(name string) bool {
+	return listContains(f.Facets, name)
+}
+
+func listContains(list, name string) bool {
+	for _, part := range strings.Split(list, ",") {
+		if strings.TrimSpace(part) == name {
+			return true
+		}
+	}
+	return false
+}
+
+type AuthorFilter struct {
+	Name        string `form:"name"`
+	Nationality string `form:"nationality"`
+}
+
+type OrderFilter struct {
+	UserID uint   `form:"user_id"`
+	BookID uint   `form:"book_id"`
+	Status string `form:"status"`
+}
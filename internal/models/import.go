@@ -0,0 +1,40 @@
+package models
+
+// Định dạng dữ liệu import/export
+const (
+	FormatCSV    = "csv"
+	FormatNDJSON = "ndjson"
+	FormatXLSX   = "xlsx"
+)
+
+// Trạng thái của từng dòng trong báo cáo import
+const (
+	ImportRowCreated = "created"
+	ImportRowUpdated = "updated"
+	ImportRowFailed  = "failed"
+)
+
+type ImportOptions struct {
+	Format    string `json:"format"`
+	DryRun    bool   `json:"dry_run"`
+	BatchSize int    `json:"batch_size"`
+}
+
+type ImportRowResult struct {
+	Row      int    `json:"row"`
+	Status   string `json:"status"`
+	Title    string `json:"title,omitempty"`
+	Author   string `json:"author,omitempty"`
+	BookID   uint   `json:"book_id,omitempty"`
+	AuthorID int    `json:"author_id,omitempty"`
+	Error    string `json:"error,omitempty"`
+}
+
+type ImportReport struct {
+	DryRun  bool              `json:"dry_run"`
+	Total   int               `json:"total"`
+	Created int               `json:"created"`
+	Updated int               `json:"updated"`
+	Failed  int               `json:"failed"`
+	Rows    []ImportRowResult `json:"rows"`
+}
@@ -0,0 +1,34 @@
+package models
+
+import (
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+// Định dạng hoá đơn trả về cho client
+const (
+	InvoiceFormatHTML = "html"
+	InvoiceFormatPDF  = "pdf"
+)
+
+// Invoice là hoá đơn đã phát hành cho một đơn. Nội dung HTML/PDF được render một lần
+// khi phát hành và lưu lại để các lần tải sau nhận đúng bản đã phát hành.
+type Invoice struct {
+	ID         uint            `gorm:"primaryKey;autoIncrement" json:"id"`
+	OrderID    uint            `gorm:"uniqueIndex" json:"order_id"`
+	Number     string          `gorm:"type:varchar(32);uniqueIndex" json:"number"`
+	IssuedAt   time.Time       `json:"issued_at"`
+	Currency   string          `gorm:"type:char(3)" json:"currency"`
+	GrandTotal decimal.Decimal `gorm:"type:decimal(12,2)" json:"grand_total"`
+	HTML       []byte          `json:"-"`
+	PDF        []byte          `json:"-"`
+	CreatedAt  time.Time       `json:"created_at"`
+}
+
+// InvoiceSequence là bộ đếm số hoá đơn của một dãy (vd: INV-2025); dòng được khoá
+// khi cấp số để số hoá đơn liên tục, không trùng
+type InvoiceSequence struct {
+	Name       string `gorm:"type:varchar(32);primaryKey"`
+	LastNumber int64
+}
@@ -1,6 +1,20 @@
 package models
 
-import "time"
+import (
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+// Trạng thái đơn hàng do hệ thống gán
+const (
+	OrderStatusPending = "pending"
+	// OrderStatusExpired: đơn pending quá hạn giữ hàng, tồn kho đã được trả lại
+	OrderStatusExpired = "expired"
+	// OrderStatusPaid: thanh toán đã được capture, tồn kho đã bị trừ
+	OrderStatusPaid     = "paid"
+	OrderStatusRefunded = "refunded"
+)
 
 type Order struct {
 	ID        uint      `gorm:"primaryKey;autoIncrement" json:"id"`
@@ -10,4 +24,34 @@ type Order struct {
 	Status    string    `json:"status"`
 	OrderedAt time.Time `gorm:"autoCreateTime" json:"ordered_at"`
 	UpdatedAt time.Time `gorm:"autoUpdateTime" json:"updated_at"`
+	// ReservedUntil khác nil thì Create chỉ giữ hàng tới thời điểm này thay vì trừ tồn kho ngay
+	ReservedUntil *time.Time `gorm:"-" json:"reserved_until,omitempty"`
+	// CouponCodes chỉ dùng cho input: các mã giảm giá áp dụng theo thứ tự khi tạo đơn
+	CouponCodes []string `gorm:"-" json:"coupon_codes,omitempty"`
+	// Discounts là chi tiết giảm giá của từng coupon, DiscountTotal là tổng (theo Currency)
+	Discounts     []OrderDiscount `gorm:"foreignKey:OrderID" json:"discounts,omitempty"`
+	DiscountTotal decimal.Decimal `gorm:"type:decimal(12,2);default:0" json:"discount_total"`
+	Currency      string          `gorm:"type:char(3)" json:"currency,omitempty"`
+	// Giá được chốt khi tạo đơn: Subtotal = UnitPrice × Quantity, GrandTotal là số tiền
+	// phải trả sau giảm giá và thuế. Các khoản này không đổi sau khi đơn đã thanh toán.
+	UnitPrice decimal.Decimal `gorm:"type:decimal(12,2);default:0" json:"unit_price"`
+	Subtotal  decimal.Decimal `gorm:"type:decimal(12,2);default:0" json:"subtotal"`
+	// TaxRegion là vùng tính thuế (vd: VN, US-CA); để trống thì dùng vùng mặc định
+	TaxRegion string          `gorm:"type:varchar(16)" json:"tax_region,omitempty"`
+	TaxRate   decimal.Decimal `gorm:"type:decimal(6,4);default:0" json:"tax_rate"`
+	// TaxInclusive: giá bìa đã gồm thuế, TaxTotal được tách ra từ GrandTotal
+	TaxInclusive bool            `json:"tax_inclusive"`
+	TaxTotal     decimal.Decimal `gorm:"type:decimal(12,2);default:0" json:"tax_total"`
+	GrandTotal   decimal.Decimal `gorm:"type:decimal(12,2);default:0" json:"grand_total"`
+}
+
+// OrderDiscount ghi lại số tiền một coupon đã giảm cho đơn tại thời điểm tạo đơn
+type OrderDiscount struct {
+	ID       uint   `gorm:"primaryKey;autoIncrement" json:"-"`
+	OrderID  uint   `gorm:"index" json:"-"`
+	CouponID uint   `json:"coupon_id"`
+	Code     string `gorm:"type:varchar(64)" json:"code"`
+	// Description mô tả cách tính, vd: "10% off" hoặc "5.00 USD off"
+	Description string          `gorm:"type:varchar(255)" json:"description"`
+	Amount      decimal.Decimal `gorm:"type:decimal(12,2)" json:"amount"`
 }
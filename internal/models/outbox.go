@@ -0,0 +1,20 @@
+package models
+
+import "time"
+
+// OutboxEvent là một domain event được ghi cùng transaction với thay đổi sinh ra nó;
+// dispatcher đọc bảng này để giao event tới các sink (ít nhất một lần)
+type OutboxEvent struct {
+	ID            uint   `gorm:"primaryKey;autoIncrement"`
+	Type          string `gorm:"type:varchar(64)"`
+	AggregateType string `gorm:"type:varchar(32);index:idx_outbox_aggregate"`
+	AggregateID   uint   `gorm:"index:idx_outbox_aggregate"`
+	// Payload là JSON của event
+	Payload    string `gorm:"type:text"`
+	OccurredAt time.Time
+	// DeliveredAt nil là chưa giao; NextAttemptAt là lúc được thử giao lần tới
+	DeliveredAt   *time.Time `gorm:"index:idx_outbox_pending,priority:1"`
+	NextAttemptAt time.Time  `gorm:"index:idx_outbox_pending,priority:2"`
+	Attempts      int
+	LastError     string `gorm:"type:text"`
+}
@@ -0,0 +1,38 @@
+package models
+
+import (
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+// Trạng thái của một lần thanh toán
+const (
+	// PaymentStatusPending: cổng thanh toán chưa có kết quả, chờ webhook
+	PaymentStatusPending    = "pending"
+	PaymentStatusAuthorized = "authorized"
+	PaymentStatusCaptured   = "captured"
+	PaymentStatusFailed     = "failed"
+	PaymentStatusRefunded   = "refunded"
+)
+
+// Payment lưu mỗi lần thanh toán của đơn hàng; Reference là mã giao dịch phía cổng thanh toán
+type Payment struct {
+	ID        uint            `gorm:"primaryKey;autoIncrement" json:"id"`
+	OrderID   uint            `gorm:"index" json:"order_id"`
+	Provider  string          `gorm:"type:varchar(32);uniqueIndex:idx_payments_provider_reference" json:"provider"`
+	Reference string          `gorm:"type:varchar(128);uniqueIndex:idx_payments_provider_reference" json:"reference"`
+	Status    string          `gorm:"type:varchar(16);index" json:"status"`
+	Amount    decimal.Decimal `gorm:"type:decimal(12,2)" json:"amount"`
+	Currency  string          `gorm:"type:char(3)" json:"currency"`
+	// RefundedAmount là tổng đã hoàn; payment chuyển sang refunded khi hoàn đủ Amount
+	RefundedAmount decimal.Decimal `gorm:"type:decimal(12,2);default:0" json:"refunded_amount"`
+	FailureReason  string          `gorm:"type:varchar(255)" json:"failure_reason,omitempty"`
+	CreatedAt      time.Time       `json:"created_at"`
+	UpdatedAt      time.Time       `json:"updated_at"`
+}
+
+// PayRequest là body của POST /orders/:id/pay; Token đại diện cho phương thức thanh toán
+type PayRequest struct {
+	Token string `json:"token"`
+}
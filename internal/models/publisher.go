@@ -0,0 +1,12 @@
+package models
+
+import "time"
+
+type Publisher struct {
+	ID        uint      `json:"id" gorm:"primaryKey;autoIncrement"`
+	Name      string    `json:"name" gorm:"type:varchar(255);not null;uniqueIndex:idx_publishers_name"`
+	Country   string    `json:"country,omitempty" gorm:"type:varchar(100)"`
+	Website   string    `json:"website,omitempty" gorm:"type:varchar(255)"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
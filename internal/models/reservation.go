@@ -0,0 +1,14 @@
+package models
+
+import "time"
+
+// StockReservation giữ Quantity cuốn của sách cho một đơn đang chờ thanh toán tới
+// ExpiresAt. Book.Stock chỉ bị trừ khi reservation được commit (đơn đã thanh toán).
+type StockReservation struct {
+	ID        uint      `gorm:"primaryKey;autoIncrement" json:"id"`
+	OrderID   uint      `gorm:"uniqueIndex" json:"order_id"`
+	BookID    uint      `gorm:"index" json:"book_id"`
+	Quantity  int       `json:"quantity"`
+	ExpiresAt time.Time `gorm:"index" json:"expires_at"`
+	CreatedAt time.Time `json:"created_at"`
+}
@@ -0,0 +1,49 @@
+package models
+
+import (
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+// Trạng thái yêu cầu trả hàng
+const (
+	ReturnStatusRequested = "requested"
+	// ReturnStatusApproved: đã nhập lại kho và hoàn tiền
+	ReturnStatusApproved = "approved"
+	ReturnStatusRejected = "rejected"
+)
+
+// ReturnRequest là yêu cầu trả lại Quantity cuốn của một đơn đã thanh toán; một đơn
+// có thể trả nhiều lần miễn tổng số lượng không vượt quá số lượng đã mua
+type ReturnRequest struct {
+	ID       uint   `gorm:"primaryKey;autoIncrement" json:"id"`
+	OrderID  uint   `gorm:"index" json:"order_id"`
+	UserID   uint   `gorm:"index" json:"user_id"`
+	Quantity int    `json:"quantity"`
+	Reason   string `gorm:"type:text" json:"reason"`
+	Status   string `gorm:"type:varchar(16);index" json:"status"`
+	// RefundAmount chỉ có khi đã duyệt
+	RefundAmount *decimal.Decimal `gorm:"type:decimal(12,2)" json:"refund_amount,omitempty"`
+	ReviewedBy   *uint            `json:"reviewed_by,omitempty"`
+	ReviewNote   string           `gorm:"type:text" json:"review_note,omitempty"`
+	ReviewedAt   *time.Time       `json:"reviewed_at,omitempty"`
+	CreatedAt    time.Time        `json:"created_at"`
+	UpdatedAt    time.Time        `json:"updated_at"`
+}
+
+// ReturnCreateRequest là body của POST /orders/:id/returns
+type ReturnCreateRequest struct {
+	Quantity int    `json:"quantity"`
+	Reason   string `json:"reason"`
+}
+
+// ReturnReviewRequest là body khi nhân viên duyệt hoặc từ chối yêu cầu trả hàng
+type ReturnReviewRequest struct {
+	Note string `json:"note"`
+}
+
+type ReturnFilter struct {
+	OrderID uint   `form:"order_id"`
+	Status  string `form:"status"`
+}
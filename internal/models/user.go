@@ -8,4 +8,7 @@ type User struct {
 	Password  string
 	CreatedAt time.Time
 	UpdatedAt time.Time
+	// Số lần đăng nhập sai liên tiếp và thời điểm hết khoá tài khoản
+	FailedLogins int `gorm:"not null;default:0"`
+	LockedUntil  *time.Time
 }
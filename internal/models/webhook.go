@@ -0,0 +1,56 @@
+package models
+
+import "time"
+
+// Trạng thái một lần giao webhook
+const (
+	WebhookDeliveryPending   = "pending"
+	WebhookDeliverySucceeded = "succeeded"
+	// WebhookDeliveryFailed: đã thử hết số lần cho phép, chỉ giao lại khi redeliver
+	WebhookDeliveryFailed = "failed"
+)
+
+// WebhookSubscription là một endpoint của đối tác nhận các loại event đã đăng ký.
+// Secret dùng để ký HMAC; chỉ trả về khi tạo hoặc đổi secret.
+type WebhookSubscription struct {
+	ID          uint      `gorm:"primaryKey;autoIncrement" json:"id"`
+	URL         string    `gorm:"type:varchar(2048)" json:"url"`
+	EventTypes  []string  `gorm:"serializer:json" json:"event_types"`
+	Secret      string    `gorm:"type:varchar(128)" json:"secret,omitempty"`
+	Description string    `gorm:"type:varchar(255)" json:"description,omitempty"`
+	Active      bool      `json:"active"`
+	CreatedAt   time.Time `json:"created_at"`
+	UpdatedAt   time.Time `json:"updated_at"`
+}
+
+// WebhookDelivery là việc giao một event tới một subscription. Body được chốt khi tạo
+// nên các lần thử lại và redeliver gửi đúng cùng nội dung.
+type WebhookDelivery struct {
+	ID             uint   `gorm:"primaryKey;autoIncrement" json:"id"`
+	SubscriptionID uint   `gorm:"uniqueIndex:idx_webhook_delivery_event" json:"subscription_id"`
+	EventID        uint   `gorm:"uniqueIndex:idx_webhook_delivery_event" json:"event_id"`
+	EventType      string `gorm:"type:varchar(64)" json:"event_type"`
+	Body           string `gorm:"type:text" json:"body"`
+	Status         string `gorm:"type:varchar(16);index:idx_webhook_delivery_pending,priority:1" json:"status"`
+	Attempts       int    `json:"attempts"`
+	// ResponseCode là mã HTTP của lần thử gần nhất (0 nếu không kết nối được)
+	ResponseCode  int                      `json:"response_code"`
+	LastError     string                   `gorm:"type:text" json:"last_error,omitempty"`
+	NextAttemptAt time.Time                `gorm:"index:idx_webhook_delivery_pending,priority:2" json:"next_attempt_at"`
+	DeliveredAt   *time.Time               `json:"delivered_at,omitempty"`
+	CreatedAt     time.Time                `json:"created_at"`
+	UpdatedAt     time.Time                `json:"updated_at"`
+	Log           []WebhookDeliveryAttempt `gorm:"foreignKey:DeliveryID" json:"log,omitempty"`
+}
+
+// WebhookDeliveryAttempt ghi lại một lần gửi HTTP của delivery
+type WebhookDeliveryAttempt struct {
+	ID           uint   `gorm:"primaryKey;autoIncrement" json:"-"`
+	DeliveryID   uint   `gorm:"index" json:"-"`
+	ResponseCode int    `json:"response_code"`
+	ResponseBody string `gorm:"type:text" json:"response_body,omitempty"`
+	Error        string `gorm:"type:text" json:"error,omitempty"`
+	// DurationMs là thời gian chờ phản hồi tính bằng mili giây
+	DurationMs int64     `json:"duration_ms"`
+	CreatedAt  time.Time `json:"created_at"`
+}
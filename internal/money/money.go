@@ -0,0 +1,29 @@
+// Package money làm tròn số tiền theo số chữ số thập phân của từng tiền tệ (ISO 4217,
+// dữ liệu CLDR của golang.org/x/text/currency): VND và JPY không có phần lẻ, USD có 2.
+// pricing, promotion và hoàn tiền dùng chung để các khoản tiền của một đơn khớp nhau.
+package money
+
+import (
+	"strings"
+
+	"github.com/shopspring/decimal"
+	"golang.org/x/text/currency"
+)
+
+// defaultScale dùng cho mã tiền tệ không hợp lệ hoặc để trống
+const defaultScale = 2
+
+// Scale trả về số chữ số thập phân của tiền tệ code
+func Scale(code string) int32 {
+	unit, err := currency.ParseISO(strings.ToUpper(code))
+	if err != nil {
+		return defaultScale
+	}
+	scale, _ := currency.Standard.Rounding(unit)
+	return int32(scale)
+}
+
+// Round làm tròn amount tới đơn vị nhỏ nhất của tiền tệ code
+func Round(amount decimal.Decimal, code string) decimal.Decimal {
+	return amount.Round(Scale(code))
+}
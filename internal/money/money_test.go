@@ -0,0 +1,29 @@
+package money_test
+
+import (
+	"testing"
+
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/require"
+
+	"github.com/maithuc2003/Test_GIN_golang/internal/money"
+)
+
+func TestRound(t *testing.T) {
+	tests := []struct {
+		amount, currency, want string
+	}{
+		{"10.335", "USD", "10.34"},
+		{"89000.5", "VND", "89001"},
+		{"1234.4", "jpy", "1234"},
+		{"1.2345", "KWD", "1.235"},
+		{"1.005", "", "1.01"},
+		{"1.005", "???", "1.01"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.currency, func(t *testing.T) {
+			got := money.Round(decimal.RequireFromString(tt.amount), tt.currency)
+			require.True(t, decimal.RequireFromString(tt.want).Equal(got), "got %s", got)
+		})
+	}
+}
@@ -0,0 +1,163 @@
+package payment
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"sync"
+
+	"github.com/maithuc2003/Test_GIN_golang/internal/models"
+	"github.com/shopspring/decimal"
+)
+
+// Token đặc biệt của FakeProvider; token khác được chấp nhận như thẻ hợp lệ
+const (
+	FakeTokenDeclined = "tok_declined"
+	// FakeTokenAsync trả về pending, kết quả cuối cùng gửi qua webhook
+	FakeTokenAsync = "tok_async"
+)
+
+// FakeProvider là cổng thanh toán giả lập trong bộ nhớ cho môi trường local và test
+type FakeProvider struct {
+	secret []byte
+
+	mu           sync.Mutex
+	transactions map[string]*fakeTransaction
+}
+
+type fakeTransaction struct {
+	status   string
+	amount   decimal.Decimal
+	refunded decimal.Decimal
+}
+
+func NewFakeProvider(secret string) *FakeProvider {
+	return &FakeProvider{secret: []byte(secret), transactions: map[string]*fakeTransaction{}}
+}
+
+func (p *FakeProvider) Name() string {
+	return "fake"
+}
+
+func (p *FakeProvider) Authorize(_ context.Context, req AuthorizeRequest) (*Result, error) {
+	if !req.Amount.IsPositive() {
+		return nil, fmt.Errorf("amount must be positive")
+	}
+	reference, err := newReference()
+	if err != nil {
+		return nil, err
+	}
+
+	result := &Result{Reference: reference, Status: models.PaymentStatusAuthorized}
+	switch req.Token {
+	case FakeTokenDeclined:
+		result.Status = models.PaymentStatusFailed
+		result.FailureReason = "card declined"
+	case FakeTokenAsync:
+		result.Status = models.PaymentStatusPending
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.transactions[reference] = &fakeTransaction{status: result.Status, amount: req.Amount}
+	return result, nil
+}
+
+func (p *FakeProvider) Capture(_ context.Context, reference string, amount decimal.Decimal) (*Result, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	tx, ok := p.transactions[reference]
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", ErrUnknownReference, reference)
+	}
+	if tx.status != models.PaymentStatusAuthorized && tx.status != models.PaymentStatusPending {
+		return nil, fmt.Errorf("cannot capture %s payment", tx.status)
+	}
+	if amount.GreaterThan(tx.amount) {
+		return nil, fmt.Errorf("capture amount %s exceeds authorized %s", amount, tx.amount)
+	}
+	tx.status = models.PaymentStatusCaptured
+	tx.amount = amount
+	return &Result{Reference: reference, Status: tx.status}, nil
+}
+
+// Refund cho phép hoàn nhiều lần; giao dịch chỉ chuyển sang refunded khi đã hoàn đủ
+func (p *FakeProvider) Refund(_ context.Context, reference string, amount decimal.Decimal) (*Result, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	tx, ok := p.transactions[reference]
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", ErrUnknownReference, reference)
+	}
+	if tx.status != models.PaymentStatusCaptured {
+		return nil, fmt.Errorf("cannot refund %s payment", tx.status)
+	}
+	if !amount.IsPositive() || tx.refunded.Add(amount).GreaterThan(tx.amount) {
+		return nil, fmt.Errorf("refund amount %s exceeds remaining %s", amount, tx.amount.Sub(tx.refunded))
+	}
+	tx.refunded = tx.refunded.Add(amount)
+	status := models.PaymentStatusCaptured
+	if tx.refunded.Equal(tx.amount) {
+		tx.status = models.PaymentStatusRefunded
+		status = tx.status
+	}
+	return &Result{Reference: reference, Status: status}, nil
+}
+
+func (p *FakeProvider) VerifyWebhook(payload []byte, signature string) (*Event, error) {
+	expected, err := hex.DecodeString(signature)
+	if err != nil || !hmac.Equal(expected, p.mac(payload)) {
+		return nil, ErrInvalidSignature
+	}
+	var event Event
+	if err := json.Unmarshal(payload, &event); err != nil {
+		return nil, fmt.Errorf("invalid webhook payload: %w", err)
+	}
+	if event.Reference == "" || event.Status == "" {
+		return nil, fmt.Errorf("invalid webhook payload: reference and status are required")
+	}
+	return &event, nil
+}
+
+// Complete kết thúc một giao dịch pending (captured hoặc failed) như cổng thanh toán thật
+// và trả về payload webhook đã ký tương ứng
+func (p *FakeProvider) Complete(reference, status, failureReason string) ([]byte, string, error) {
+	p.mu.Lock()
+	tx, ok := p.transactions[reference]
+	if ok {
+		tx.status = status
+	}
+	p.mu.Unlock()
+	if !ok {
+		return nil, "", fmt.Errorf("%w: %s", ErrUnknownReference, reference)
+	}
+
+	payload, err := json.Marshal(Event{Reference: reference, Status: status, FailureReason: failureReason})
+	if err != nil {
+		return nil, "", err
+	}
+	return payload, p.Sign(payload), nil
+}
+
+// Sign trả về chữ ký hợp lệ cho payload, dùng để giả lập webhook khi chạy local và test
+func (p *FakeProvider) Sign(payload []byte) string {
+	return hex.EncodeToString(p.mac(payload))
+}
+
+func (p *FakeProvider) mac(payload []byte) []byte {
+	h := hmac.New(sha256.New, p.secret)
+	h.Write(payload)
+	return h.Sum(nil)
+}
+
+func newReference() (string, error) {
+	b := make([]byte, 12)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return "fake_" + hex.EncodeToString(b), nil
+}
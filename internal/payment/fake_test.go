@@ -0,0 +1,105 @@
+package payment_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/maithuc2003/Test_GIN_golang/internal/models"
+	"github.com/maithuc2003/Test_GIN_golang/internal/payment"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFakeProvider_Authorize(t *testing.T) {
+	tests := []struct {
+		name       string
+		token      string
+		amount     string
+		wantStatus string
+		wantErr    bool
+	}{
+		{name: "card accepted", token: "tok_visa", amount: "10", wantStatus: models.PaymentStatusAuthorized},
+		{name: "card declined", token: payment.FakeTokenDeclined, amount: "10", wantStatus: models.PaymentStatusFailed},
+		{name: "async", token: payment.FakeTokenAsync, amount: "10", wantStatus: models.PaymentStatusPending},
+		{name: "zero amount", token: "tok_visa", amount: "0", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := payment.NewFakeProvider("secret")
+			result, err := p.Authorize(context.Background(), payment.AuthorizeRequest{
+				OrderID: 1, Amount: decimal.RequireFromString(tt.amount), Currency: "USD", Token: tt.token,
+			})
+			if tt.wantErr {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			require.NotEmpty(t, result.Reference)
+			require.Equal(t, tt.wantStatus, result.Status)
+		})
+	}
+}
+
+func TestFakeProvider_CaptureAndRefund(t *testing.T) {
+	ctx := context.Background()
+	p := payment.NewFakeProvider("secret")
+	auth, err := p.Authorize(ctx, payment.AuthorizeRequest{Amount: decimal.NewFromInt(30), Currency: "USD"})
+	require.NoError(t, err)
+
+	_, err = p.Refund(ctx, auth.Reference, decimal.NewFromInt(1))
+	require.Error(t, err, "refund before capture")
+
+	captured, err := p.Capture(ctx, auth.Reference, decimal.NewFromInt(30))
+	require.NoError(t, err)
+	require.Equal(t, models.PaymentStatusCaptured, captured.Status)
+
+	partial, err := p.Refund(ctx, auth.Reference, decimal.NewFromInt(10))
+	require.NoError(t, err)
+	require.Equal(t, models.PaymentStatusCaptured, partial.Status)
+
+	_, err = p.Refund(ctx, auth.Reference, decimal.NewFromInt(25))
+	require.Error(t, err, "refund above remaining amount")
+
+	full, err := p.Refund(ctx, auth.Reference, decimal.NewFromInt(20))
+	require.NoError(t, err)
+	require.Equal(t, models.PaymentStatusRefunded, full.Status)
+
+	_, err = p.Capture(ctx, "fake_unknown", decimal.NewFromInt(1))
+	require.ErrorIs(t, err, payment.ErrUnknownReference)
+}
+
+func TestFakeProvider_VerifyWebhook(t *testing.T) {
+	p := payment.NewFakeProvider("secret")
+	payload := []byte(`{"reference":"fake_1","status":"captured"}`)
+
+	tests := []struct {
+		name      string
+		payload   []byte
+		signature string
+		wantErrIs error
+		wantErr   bool
+	}{
+		{name: "valid", payload: payload, signature: p.Sign(payload)},
+		{name: "missing signature", payload: payload, wantErrIs: payment.ErrInvalidSignature},
+		{name: "signed with another secret", payload: payload, signature: payment.NewFakeProvider("other").Sign(payload), wantErrIs: payment.ErrInvalidSignature},
+		{name: "tampered payload", payload: []byte(`{"reference":"fake_2","status":"captured"}`), signature: p.Sign(payload), wantErrIs: payment.ErrInvalidSignature},
+		{name: "missing status", payload: []byte(`{"reference":"fake_1"}`), signature: p.Sign([]byte(`{"reference":"fake_1"}`)), wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			event, err := p.VerifyWebhook(tt.payload, tt.signature)
+			switch {
+			case tt.wantErrIs != nil:
+				require.ErrorIs(t, err, tt.wantErrIs)
+			case tt.wantErr:
+				require.Error(t, err)
+			default:
+				require.NoError(t, err)
+				require.Equal(t, "fake_1", event.Reference)
+				require.Equal(t, models.PaymentStatusCaptured, event.Status)
+			}
+		})
+	}
+}
@@ -0,0 +1,52 @@
+package payment
+
+import (
+	"context"
+	"errors"
+
+	"github.com/shopspring/decimal"
+)
+
+// SignatureHeader là header chứa chữ ký của webhook gửi từ cổng thanh toán
+const SignatureHeader = "X-Payment-Signature"
+
+var (
+	// ErrInvalidSignature: webhook không có hoặc sai chữ ký
+	ErrInvalidSignature = errors.New("invalid webhook signature")
+	// ErrUnknownReference: cổng thanh toán không biết giao dịch
+	ErrUnknownReference = errors.New("unknown payment reference")
+)
+
+// Provider là cổng thanh toán. Authorize giữ tiền, Capture thu tiền đã giữ, Refund
+// hoàn tiền đã thu; kết quả bất đồng bộ được gửi về qua webhook đã ký.
+type Provider interface {
+	// Name là mã cổng thanh toán, lưu cùng Reference để tra cứu giao dịch
+	Name() string
+	Authorize(ctx context.Context, req AuthorizeRequest) (*Result, error)
+	Capture(ctx context.Context, reference string, amount decimal.Decimal) (*Result, error)
+	Refund(ctx context.Context, reference string, amount decimal.Decimal) (*Result, error)
+	// VerifyWebhook kiểm tra chữ ký rồi giải mã payload thành Event
+	VerifyWebhook(payload []byte, signature string) (*Event, error)
+}
+
+type AuthorizeRequest struct {
+	OrderID  uint
+	Amount   decimal.Decimal
+	Currency string
+	// Token đại diện cho thẻ/ví do client lấy từ cổng thanh toán
+	Token string
+}
+
+// Result là kết quả đồng bộ; Status là một trong models.PaymentStatus*
+type Result struct {
+	Reference     string
+	Status        string
+	FailureReason string
+}
+
+// Event là thông báo từ webhook về trạng thái mới của một giao dịch
+type Event struct {
+	Reference     string `json:"reference"`
+	Status        string `json:"status"`
+	FailureReason string `json:"failure_reason,omitempty"`
+}
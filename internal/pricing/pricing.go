@@ -0,0 +1,116 @@
+// Package pricing chốt giá cho đơn hàng: đơn giá, giảm giá từ coupon, thuế và tổng
+// tiền, tính bằng decimal và làm tròn tới đơn vị nhỏ nhất của tiền tệ (xem package money).
+package pricing
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/maithuc2003/Test_GIN_golang/internal/interfaces/repositories"
+	"github.com/maithuc2003/Test_GIN_golang/internal/interfaces/service"
+	"github.com/maithuc2003/Test_GIN_golang/internal/models"
+	"github.com/maithuc2003/Test_GIN_golang/internal/money"
+	"github.com/maithuc2003/Test_GIN_golang/internal/promotion"
+	"github.com/shopspring/decimal"
+)
+
+// Totals là các khoản tiền của một dòng đơn
+type Totals struct {
+	Subtotal decimal.Decimal
+	Discount decimal.Decimal
+	Tax      decimal.Decimal
+	Grand    decimal.Decimal
+}
+
+// Calculate tính thuế trên phần sau giảm giá, làm tròn theo currency. Thuế cộng thêm:
+// Grand = net + net×rate. Thuế đã gồm trong giá: Grand = net và phần thuế là net×rate/(1+rate).
+func Calculate(unitPrice decimal.Decimal, quantity int, discount decimal.Decimal, rule TaxRule, currency string) Totals {
+	subtotal := money.Round(unitPrice.Mul(decimal.NewFromInt(int64(quantity))), currency)
+	net := subtotal.Sub(discount)
+	if net.IsNegative() {
+		net = decimal.Zero
+	}
+
+	totals := Totals{Subtotal: subtotal, Discount: subtotal.Sub(net)}
+	if rule.Inclusive {
+		totals.Tax = money.Round(net.Mul(rule.Rate).Div(decimal.NewFromInt(1).Add(rule.Rate)), currency)
+		totals.Grand = net
+	} else {
+		totals.Tax = money.Round(net.Mul(rule.Rate), currency)
+		totals.Grand = net.Add(totals.Tax)
+	}
+	return totals
+}
+
+// Calculator chốt giá cho đơn theo giá bìa hiện tại, coupon và bảng thuế
+type Calculator struct {
+	taxes *TaxTable
+}
+
+// NewCalculator nhận bảng thuế; nil nghĩa là không tính thuế
+func NewCalculator(taxes *TaxTable) *Calculator {
+	if taxes == nil {
+		taxes = &TaxTable{}
+	}
+	return &Calculator{taxes: taxes}
+}
+
+// Price gán đơn giá, giảm giá (theo order.CouponCodes, xét hiệu lực tại order.OrderedAt),
+// thuế và tổng tiền cho order. Lượt dùng coupon do bên gọi ghi nhận sau khi tạo đơn.
+func (c *Calculator) Price(ctx context.Context, repos repositories.Repositories, order *models.Order) error {
+	book, err := repos.Books.GetByBookID(ctx, int(order.BookID))
+	if err != nil {
+		return err
+	}
+	if book.Price == nil || book.Currency == "" {
+		return fmt.Errorf("%w: book %d has no price", service.ErrInvalidInput, book.ID)
+	}
+
+	var categoryIDs []uint
+	if len(order.CouponCodes) > 0 || c.taxes.HasCategoryRules() {
+		if categoryIDs, err = repos.Coupons.BookCategoryIDs(ctx, book.ID); err != nil {
+			return err
+		}
+	}
+
+	coupons := make([]models.Coupon, 0, len(order.CouponCodes))
+	for _, code := range order.CouponCodes {
+		coupon, err := repos.Coupons.GetByCode(ctx, promotion.NormalizeCode(code))
+		if err != nil {
+			if errors.Is(err, repositories.ErrNotFound) {
+				return fmt.Errorf("%w: coupon %s does not exist", service.ErrInvalidInput, code)
+			}
+			return err
+		}
+		coupons = append(coupons, *coupon)
+	}
+
+	unitPrice := money.Round(*book.Price, book.Currency)
+	line := promotion.Line{
+		BookID:      book.ID,
+		CategoryIDs: categoryIDs,
+		Amount:      unitPrice.Mul(decimal.NewFromInt(int64(order.Quantity))),
+		Currency:    book.Currency,
+	}
+	discounts, discountTotal, err := promotion.Apply(coupons, line, order.OrderedAt)
+	if err != nil {
+		return err
+	}
+
+	region := c.taxes.Region(order.TaxRegion)
+	rule := c.taxes.Lookup(region, categoryIDs)
+	totals := Calculate(unitPrice, order.Quantity, discountTotal, rule, book.Currency)
+
+	order.Currency = book.Currency
+	order.UnitPrice = unitPrice
+	order.Subtotal = totals.Subtotal
+	order.Discounts = discounts
+	order.DiscountTotal = totals.Discount
+	order.TaxRegion = region
+	order.TaxRate = rule.Rate
+	order.TaxInclusive = rule.Inclusive
+	order.TaxTotal = totals.Tax
+	order.GrandTotal = totals.Grand
+	return nil
+}
@@ -0,0 +1,109 @@
+package pricing_test
+
+import (
+	"cmp"
+	"testing"
+
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/require"
+
+	"github.com/maithuc2003/Test_GIN_golang/internal/pricing"
+)
+
+func dec(s string) decimal.Decimal {
+	return decimal.RequireFromString(s)
+}
+
+func TestCalculate(t *testing.T) {
+	tests := []struct {
+		name      string
+		unitPrice string
+		quantity  int
+		discount  string
+		rule      pricing.TaxRule
+		currency  string
+		want      [4]string // subtotal, discount, tax, grand
+	}{
+		{name: "no tax", unitPrice: "10.50", quantity: 3, discount: "0", want: [4]string{"31.50", "0", "0", "31.50"}},
+		{
+			name: "exclusive tax after discount", unitPrice: "10.00", quantity: 2, discount: "5.00",
+			rule: pricing.TaxRule{Rate: dec("0.0725")}, want: [4]string{"20.00", "5.00", "1.09", "16.09"},
+		},
+		{
+			name: "inclusive tax extracted from total", unitPrice: "110000", quantity: 1, discount: "0", currency: "VND",
+			rule: pricing.TaxRule{Rate: dec("0.10"), Inclusive: true}, want: [4]string{"110000", "0", "10000", "110000"},
+		},
+		{
+			name: "zero-decimal currency rounds to whole units", unitPrice: "89000", quantity: 1, discount: "0", currency: "VND",
+			rule: pricing.TaxRule{Rate: dec("0.0725")}, want: [4]string{"89000", "0", "6453", "95453"},
+		},
+		{
+			name: "inclusive tax rounded to cents", unitPrice: "19.99", quantity: 1, discount: "0",
+			rule: pricing.TaxRule{Rate: dec("0.05"), Inclusive: true}, want: [4]string{"19.99", "0", "0.95", "19.99"},
+		},
+		{
+			name: "discount capped at subtotal", unitPrice: "4.00", quantity: 1, discount: "10.00",
+			rule: pricing.TaxRule{Rate: dec("0.08")}, want: [4]string{"4.00", "4.00", "0", "0"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := pricing.Calculate(dec(tt.unitPrice), tt.quantity, dec(tt.discount), tt.rule, cmp.Or(tt.currency, "USD"))
+			for i, v := range []decimal.Decimal{got.Subtotal, got.Discount, got.Tax, got.Grand} {
+				require.True(t, dec(tt.want[i]).Equal(v), "field %d = %s, want %s", i, v, tt.want[i])
+			}
+		})
+	}
+}
+
+func TestTaxTable(t *testing.T) {
+	table, err := pricing.ParseTaxTable([]byte(`{
+		"default_region": "vn",
+		"rules": [
+			{"region": "VN", "rate": "0.10", "inclusive": true},
+			{"region": "vn", "category_id": 3, "rate": "0.05", "inclusive": true},
+			{"region": "US-CA", "rate": "0.0725"}
+		]
+	}`))
+	require.NoError(t, err)
+	require.True(t, table.HasCategoryRules())
+	require.Equal(t, "VN", table.Region(""))
+	require.Equal(t, "US-CA", table.Region(" us-ca "))
+
+	tests := []struct {
+		name        string
+		region      string
+		categoryIDs []uint
+		wantRate    string
+		inclusive   bool
+	}{
+		{name: "region default", region: "VN", categoryIDs: []uint{7}, wantRate: "0.10", inclusive: true},
+		{name: "category rule", region: "VN", categoryIDs: []uint{7, 3}, wantRate: "0.05", inclusive: true},
+		{name: "category rule only in its region", region: "US-CA", categoryIDs: []uint{3}, wantRate: "0.0725"},
+		{name: "unknown region", region: "FR", wantRate: "0"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rule := table.Lookup(tt.region, tt.categoryIDs)
+			require.True(t, dec(tt.wantRate).Equal(rule.Rate), "rate = %s", rule.Rate)
+			require.Equal(t, tt.inclusive, rule.Inclusive)
+		})
+	}
+}
+
+func TestParseTaxTable_Invalid(t *testing.T) {
+	tests := map[string]string{
+		"bad json":       `{"rules": [`,
+		"missing region": `{"rules": [{"rate": "0.1"}]}`,
+		"negative rate":  `{"rules": [{"region": "VN", "rate": "-0.1"}]}`,
+		"rate of 100%":   `{"rules": [{"region": "VN", "rate": "1"}]}`,
+		"duplicate rule": `{"rules": [{"region": "VN", "rate": "0.1"}, {"region": "vn", "rate": "0.2"}]}`,
+	}
+	for name, data := range tests {
+		t.Run(name, func(t *testing.T) {
+			_, err := pricing.ParseTaxTable([]byte(data))
+			require.Error(t, err)
+		})
+	}
+}
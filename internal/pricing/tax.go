@@ -0,0 +1,97 @@
+package pricing
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/shopspring/decimal"
+)
+
+// TaxRule là thuế suất của một vùng; CategoryID = 0 là mức chung của vùng,
+// khác 0 thì chỉ áp cho sách thuộc thể loại đó (kể cả thể loại con)
+type TaxRule struct {
+	Region     string `json:"region"`
+	CategoryID uint   `json:"category_id,omitempty"`
+	// Rate là tỉ lệ, vd: 0.08 là 8%
+	Rate decimal.Decimal `json:"rate"`
+	// Inclusive: giá bìa đã gồm thuế, thuế được tách ra thay vì cộng thêm
+	Inclusive bool `json:"inclusive"`
+}
+
+// TaxTable là bảng thuế suất theo vùng và thể loại
+type TaxTable struct {
+	// DefaultRegion dùng cho đơn không ghi vùng tính thuế
+	DefaultRegion string    `json:"default_region"`
+	Rules         []TaxRule `json:"rules"`
+}
+
+// ParseTaxTable đọc bảng thuế dạng JSON và kiểm tra các thuế suất
+func ParseTaxTable(data []byte) (*TaxTable, error) {
+	var table TaxTable
+	if err := json.Unmarshal(data, &table); err != nil {
+		return nil, fmt.Errorf("invalid tax table: %w", err)
+	}
+	table.DefaultRegion = NormalizeRegion(table.DefaultRegion)
+
+	seen := make(map[string]bool, len(table.Rules))
+	one := decimal.NewFromInt(1)
+	for i := range table.Rules {
+		rule := &table.Rules[i]
+		rule.Region = NormalizeRegion(rule.Region)
+		if rule.Region == "" {
+			return nil, fmt.Errorf("invalid tax table: rule %d has no region", i)
+		}
+		if rule.Rate.IsNegative() || rule.Rate.GreaterThanOrEqual(one) {
+			return nil, fmt.Errorf("invalid tax table: rule %d rate must be in [0, 1)", i)
+		}
+		key := fmt.Sprintf("%s/%d", rule.Region, rule.CategoryID)
+		if seen[key] {
+			return nil, fmt.Errorf("invalid tax table: duplicate rule for region %s category %d", rule.Region, rule.CategoryID)
+		}
+		seen[key] = true
+	}
+	return &table, nil
+}
+
+// NormalizeRegion chuẩn hoá mã vùng về chữ hoa
+func NormalizeRegion(region string) string {
+	return strings.ToUpper(strings.TrimSpace(region))
+}
+
+// Region trả về vùng tính thuế của đơn, rỗng thì dùng DefaultRegion
+func (t *TaxTable) Region(region string) string {
+	if region = NormalizeRegion(region); region != "" {
+		return region
+	}
+	return t.DefaultRegion
+}
+
+// HasCategoryRules cho biết có cần biết thể loại của sách để tra thuế hay không
+func (t *TaxTable) HasCategoryRules() bool {
+	for _, rule := range t.Rules {
+		if rule.CategoryID != 0 {
+			return true
+		}
+	}
+	return false
+}
+
+// Lookup tìm thuế suất cho vùng region. categoryIDs xếp theo độ ưu tiên (thể loại của
+// sách trước thể loại cha); không có luật riêng thì dùng mức chung của vùng, không có
+// luật nào thì thuế suất 0.
+func (t *TaxTable) Lookup(region string, categoryIDs []uint) TaxRule {
+	for _, id := range categoryIDs {
+		for _, rule := range t.Rules {
+			if rule.Region == region && rule.CategoryID == id {
+				return rule
+			}
+		}
+	}
+	for _, rule := range t.Rules {
+		if rule.Region == region && rule.CategoryID == 0 {
+			return rule
+		}
+	}
+	return TaxRule{Region: region}
+}
@@ -0,0 +1,126 @@
+// Package promotion tính giảm giá của coupon cho một dòng đơn hàng. Engine không truy
+// cập DB: giới hạn số lượt dùng được repository kiểm tra khi ghi nhận lượt dùng.
+package promotion
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/maithuc2003/Test_GIN_golang/internal/interfaces/service"
+	"github.com/maithuc2003/Test_GIN_golang/internal/models"
+	"github.com/maithuc2003/Test_GIN_golang/internal/money"
+	"github.com/shopspring/decimal"
+)
+
+// MaxCoupons là số mã tối đa được dùng cho một đơn
+const MaxCoupons = 5
+
+// Line là phần đơn hàng được tính giảm giá
+type Line struct {
+	BookID uint
+	// CategoryIDs gồm thể loại của sách và mọi thể loại cha
+	CategoryIDs []uint
+	// Amount là đơn giá × số lượng, trước giảm giá
+	Amount   decimal.Decimal
+	Currency string
+}
+
+// NormalizeCode chuẩn hoá mã coupon về dạng lưu trong DB
+func NormalizeCode(code string) string {
+	return strings.ToUpper(strings.TrimSpace(code))
+}
+
+// Apply áp lần lượt các coupon lên line: mỗi coupon tính trên phần còn lại sau các
+// coupon trước nên tổng giảm không bao giờ vượt Amount. Coupon không áp dụng được
+// trả về ErrInvalidInput kèm lý do.
+func Apply(coupons []models.Coupon, line Line, now time.Time) ([]models.OrderDiscount, decimal.Decimal, error) {
+	if len(coupons) > MaxCoupons {
+		return nil, decimal.Zero, fmt.Errorf("%w: at most %d coupons per order", service.ErrInvalidInput, MaxCoupons)
+	}
+
+	discounts := make([]models.OrderDiscount, 0, len(coupons))
+	remaining := line.Amount
+	seen := make(map[uint]bool, len(coupons))
+	for i := range coupons {
+		c := &coupons[i]
+		if seen[c.ID] {
+			return nil, decimal.Zero, fmt.Errorf("%w: coupon %s is applied more than once", service.ErrInvalidInput, c.Code)
+		}
+		seen[c.ID] = true
+
+		if err := Check(c, line, now); err != nil {
+			return nil, decimal.Zero, err
+		}
+
+		amount, description := discountFor(c, remaining, line.Currency)
+		remaining = remaining.Sub(amount)
+		discounts = append(discounts, models.OrderDiscount{
+			CouponID:    c.ID,
+			Code:        c.Code,
+			Description: description,
+			Amount:      amount,
+		})
+	}
+	return discounts, line.Amount.Sub(remaining), nil
+}
+
+// Check kiểm tra coupon có dùng được cho line tại thời điểm now hay không
+func Check(c *models.Coupon, line Line, now time.Time) error {
+	if !c.Active {
+		return fmt.Errorf("%w: coupon %s is not active", service.ErrInvalidInput, c.Code)
+	}
+	if c.StartsAt != nil && now.Before(*c.StartsAt) {
+		return fmt.Errorf("%w: coupon %s is not valid yet", service.ErrInvalidInput, c.Code)
+	}
+	if c.EndsAt != nil && !now.Before(*c.EndsAt) {
+		return fmt.Errorf("%w: coupon %s has expired", service.ErrInvalidInput, c.Code)
+	}
+	if (c.Type == models.CouponTypeFixed || c.MinOrderValue != nil) && c.Currency != line.Currency {
+		return fmt.Errorf("%w: coupon %s is only valid for %s orders", service.ErrInvalidInput, c.Code, c.Currency)
+	}
+	if !targets(c, line) {
+		return fmt.Errorf("%w: coupon %s does not apply to book %d", service.ErrInvalidInput, c.Code, line.BookID)
+	}
+	if c.MinOrderValue != nil && line.Amount.LessThan(*c.MinOrderValue) {
+		return fmt.Errorf("%w: coupon %s requires a minimum order value of %s %s", service.ErrInvalidInput, c.Code, c.MinOrderValue.StringFixed(2), c.Currency)
+	}
+	return nil
+}
+
+// targets: coupon không giới hạn sách/thể loại thì áp dụng cho mọi sách
+func targets(c *models.Coupon, line Line) bool {
+	if len(c.BookIDs) == 0 && len(c.CategoryIDs) == 0 {
+		return true
+	}
+	for _, id := range c.BookIDs {
+		if id == line.BookID {
+			return true
+		}
+	}
+	for _, want := range c.CategoryIDs {
+		for _, id := range line.CategoryIDs {
+			if id == want {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func discountFor(c *models.Coupon, remaining decimal.Decimal, currency string) (decimal.Decimal, string) {
+	var amount decimal.Decimal
+	var description string
+	switch c.Type {
+	case models.CouponTypePercentage:
+		amount = money.Round(remaining.Mul(c.Value).Div(decimal.NewFromInt(100)), currency)
+		description = fmt.Sprintf("%s%% off", c.Value.String())
+	default:
+		amount = c.Value
+		description = fmt.Sprintf("%s %s off", c.Value.StringFixed(2), c.Currency)
+	}
+	if amount.GreaterThan(remaining) {
+		amount = remaining
+	}
+	return amount, description
+}
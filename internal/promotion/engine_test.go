@@ -0,0 +1,190 @@
+package promotion_test
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/require"
+
+	"github.com/maithuc2003/Test_GIN_golang/internal/interfaces/service"
+	"github.com/maithuc2003/Test_GIN_golang/internal/models"
+	"github.com/maithuc2003/Test_GIN_golang/internal/promotion"
+)
+
+func dec(s string) decimal.Decimal {
+	return decimal.RequireFromString(s)
+}
+
+func decPtr(s string) *decimal.Decimal {
+	d := dec(s)
+	return &d
+}
+
+func TestApply(t *testing.T) {
+	now := time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC)
+	past := now.Add(-24 * time.Hour)
+	future := now.Add(24 * time.Hour)
+
+	// Sách 1 thuộc thể loại 5, thể loại cha của 5 là 2
+	line := promotion.Line{BookID: 1, CategoryIDs: []uint{5, 2}, Amount: dec("40.00"), Currency: "USD"}
+	percent := func(id uint, v string) models.Coupon {
+		return models.Coupon{ID: id, Code: "P" + v, Type: models.CouponTypePercentage, Value: dec(v), Active: true}
+	}
+	fixed := func(id uint, v string) models.Coupon {
+		return models.Coupon{ID: id, Code: "F" + v, Type: models.CouponTypeFixed, Value: dec(v), Currency: "USD", Active: true}
+	}
+
+	tests := []struct {
+		name      string
+		coupons   []models.Coupon
+		wantTotal string
+		wantLines []string
+		wantErr   bool
+	}{
+		{name: "percentage", coupons: []models.Coupon{percent(1, "15")}, wantTotal: "6", wantLines: []string{"6"}},
+		{name: "fixed", coupons: []models.Coupon{fixed(1, "5")}, wantTotal: "5", wantLines: []string{"5"}},
+		{name: "fixed capped at amount", coupons: []models.Coupon{fixed(1, "50")}, wantTotal: "40", wantLines: []string{"40"}},
+		{
+			name:      "stacked on remaining amount",
+			coupons:   []models.Coupon{fixed(1, "10"), percent(2, "10")},
+			wantTotal: "13", wantLines: []string{"10", "3"},
+		},
+		{
+			name:      "percentage rounded to cents",
+			coupons:   []models.Coupon{percent(1, "33.33")},
+			wantTotal: "13.33", wantLines: []string{"13.33"},
+		},
+		{
+			name: "targets book",
+			coupons: []models.Coupon{func() models.Coupon {
+				c := percent(1, "10")
+				c.BookIDs = []uint{9, 1}
+				return c
+			}()},
+			wantTotal: "4", wantLines: []string{"4"},
+		},
+		{
+			name: "targets parent category",
+			coupons: []models.Coupon{func() models.Coupon {
+				c := percent(1, "10")
+				c.CategoryIDs = []uint{2}
+				return c
+			}()},
+			wantTotal: "4", wantLines: []string{"4"},
+		},
+		{
+			name: "other book and category",
+			coupons: []models.Coupon{func() models.Coupon {
+				c := percent(1, "10")
+				c.BookIDs = []uint{9}
+				c.CategoryIDs = []uint{7}
+				return c
+			}()},
+			wantErr: true,
+		},
+		{
+			name: "inactive",
+			coupons: []models.Coupon{func() models.Coupon {
+				c := percent(1, "10")
+				c.Active = false
+				return c
+			}()},
+			wantErr: true,
+		},
+		{
+			name: "not started",
+			coupons: []models.Coupon{func() models.Coupon {
+				c := percent(1, "10")
+				c.StartsAt = &future
+				return c
+			}()},
+			wantErr: true,
+		},
+		{
+			name: "expired",
+			coupons: []models.Coupon{func() models.Coupon {
+				c := percent(1, "10")
+				c.EndsAt = &past
+				return c
+			}()},
+			wantErr: true,
+		},
+		{
+			name: "inside window",
+			coupons: []models.Coupon{func() models.Coupon {
+				c := percent(1, "10")
+				c.StartsAt, c.EndsAt = &past, &future
+				return c
+			}()},
+			wantTotal: "4", wantLines: []string{"4"},
+		},
+		{
+			name: "minimum order value met",
+			coupons: []models.Coupon{func() models.Coupon {
+				c := fixed(1, "5")
+				c.MinOrderValue = decPtr("40")
+				return c
+			}()},
+			wantTotal: "5", wantLines: []string{"5"},
+		},
+		{
+			name: "minimum order value not met",
+			coupons: []models.Coupon{func() models.Coupon {
+				c := fixed(1, "5")
+				c.MinOrderValue = decPtr("40.01")
+				return c
+			}()},
+			wantErr: true,
+		},
+		{
+			name: "fixed in other currency",
+			coupons: []models.Coupon{func() models.Coupon {
+				c := fixed(1, "5")
+				c.Currency = "EUR"
+				return c
+			}()},
+			wantErr: true,
+		},
+		{name: "same coupon twice", coupons: []models.Coupon{percent(1, "10"), percent(1, "10")}, wantErr: true},
+		{
+			name:    "too many coupons",
+			coupons: []models.Coupon{percent(1, "1"), percent(2, "1"), percent(3, "1"), percent(4, "1"), percent(5, "1"), percent(6, "1")},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			discounts, total, err := promotion.Apply(tt.coupons, line, now)
+			if tt.wantErr {
+				require.Error(t, err)
+				require.True(t, errors.Is(err, service.ErrInvalidInput))
+				return
+			}
+			require.NoError(t, err)
+			require.True(t, dec(tt.wantTotal).Equal(total), "total = %s", total)
+			require.Len(t, discounts, len(tt.wantLines))
+			for i, want := range tt.wantLines {
+				require.True(t, dec(want).Equal(discounts[i].Amount), "discount %d = %s", i, discounts[i].Amount)
+				require.Equal(t, tt.coupons[i].ID, discounts[i].CouponID)
+				require.Equal(t, tt.coupons[i].Code, discounts[i].Code)
+			}
+		})
+	}
+}
+
+func TestApply_ZeroDecimalCurrency(t *testing.T) {
+	line := promotion.Line{BookID: 1, Amount: dec("89000"), Currency: "VND"}
+	coupon := models.Coupon{ID: 1, Code: "P7", Type: models.CouponTypePercentage, Value: dec("7.25"), Active: true}
+
+	discounts, total, err := promotion.Apply([]models.Coupon{coupon}, line, time.Now())
+	require.NoError(t, err)
+	require.True(t, dec("6453").Equal(total), "total = %s", total)
+	require.True(t, dec("6453").Equal(discounts[0].Amount))
+}
+
+func TestNormalizeCode(t *testing.T) {
+	require.Equal(t, "SUMMER-10", promotion.NormalizeCode("  summer-10 "))
+}
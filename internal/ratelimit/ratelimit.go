@@ -0,0 +1,119 @@
+// Package ratelimit giới hạn tần suất request bằng token bucket, lưu trong bộ nhớ
+// (một instance) hoặc redis (dùng chung giữa các instance).
+package ratelimit
+
+import (
+	"context"
+	"math"
+	"sync"
+	"time"
+)
+
+// Limit là một token bucket: nạp Rate token mỗi giây, chứa tối đa Burst token.
+// Mỗi request tiêu một token. Limit có Rate hoặc Burst bằng 0 là không giới hạn.
+type Limit struct {
+	Rate  float64
+	Burst int
+}
+
+// PerMinute tạo Limit cho phép n request mỗi phút, dồn tối đa burst request
+func PerMinute(n, burst int) Limit {
+	return Limit{Rate: float64(n) / 60, Burst: burst}
+}
+
+// Unlimited cho biết Limit không chặn request nào
+func (l Limit) Unlimited() bool {
+	return l.Rate <= 0 || l.Burst <= 0
+}
+
+// Result là kết quả của một lần lấy token
+type Result struct {
+	Allowed bool
+	// Remaining là số token còn lại sau request này
+	Remaining int
+	// RetryAfter là thời gian tới khi có token tiếp theo; 0 khi Allowed
+	RetryAfter time.Duration
+}
+
+// Store lấy một token của bucket key tại thời điểm now
+type Store interface {
+	Allow(ctx context.Context, key string, limit Limit, now time.Time) (Result, error)
+}
+
+// take áp dụng thuật toán token bucket lên số token hiện có, trả về số token mới và kết quả
+func take(tokens float64, elapsed time.Duration, limit Limit) (float64, Result) {
+	// Đồng hồ giữa các instance có thể lệch nhau
+	elapsed = max(elapsed, 0)
+	tokens = math.Min(float64(limit.Burst), tokens+elapsed.Seconds()*limit.Rate)
+	if tokens >= 1 {
+		tokens--
+		return tokens, Result{Allowed: true, Remaining: int(tokens)}
+	}
+	wait := time.Duration((1 - tokens) / limit.Rate * float64(time.Second))
+	return tokens, Result{RetryAfter: wait}
+}
+
+// refillTime là thời gian để bucket từ tokens nạp lại đầy
+func refillTime(tokens float64, limit Limit) time.Duration {
+	return time.Duration((float64(limit.Burst) - tokens) / limit.Rate * float64(time.Second))
+}
+
+// sweepInterval là chu kỳ MemoryStore xoá các bucket đã nạp đầy
+const sweepInterval = time.Minute
+
+// MemoryStore giữ bucket trong bộ nhớ của tiến trình. Bucket đã nạp đầy tương đương
+// bucket mới nên được xoá định kỳ để map không lớn dần theo số IP.
+type MemoryStore struct {
+	mu        sync.Mutex
+	buckets   map[string]*bucket
+	lastSweep time.Time
+}
+
+type bucket struct {
+	tokens  float64
+	updated time.Time
+	// full là thời điểm bucket nạp đầy trở lại
+	full time.Time
+}
+
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{buckets: map[string]*bucket{}}
+}
+
+func (s *MemoryStore) Allow(_ context.Context, key string, limit Limit, now time.Time) (Result, error) {
+	if limit.Unlimited() {
+		return Result{Allowed: true}, nil
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if now.Sub(s.lastSweep) >= sweepInterval {
+		s.sweep(now)
+	}
+
+	b, ok := s.buckets[key]
+	if !ok {
+		b = &bucket{tokens: float64(limit.Burst), updated: now}
+		s.buckets[key] = b
+	}
+	tokens, result := take(b.tokens, now.Sub(b.updated), limit)
+	b.tokens, b.updated = tokens, now
+	b.full = now.Add(refillTime(tokens, limit))
+	return result, nil
+}
+
+func (s *MemoryStore) sweep(now time.Time) {
+	for key, b := range s.buckets {
+		if !now.Before(b.full) {
+			delete(s.buckets, key)
+		}
+	}
+	s.lastSweep = now
+}
+
+// Len trả về số bucket đang giữ
+func (s *MemoryStore) Len() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return len(s.buckets)
+}
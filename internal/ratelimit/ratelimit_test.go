@@ -0,0 +1,157 @@
+package ratelimit_test
+
+import (
+	"bufio"
+	"context"
+	"io"
+	"net"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/maithuc2003/Test_GIN_golang/internal/ratelimit"
+	"github.com/maithuc2003/Test_GIN_golang/internal/redis"
+)
+
+func TestMemoryStore(t *testing.T) {
+	ctx := context.Background()
+	store := ratelimit.NewMemoryStore()
+	limit := ratelimit.Limit{Rate: 1, Burst: 2}
+	now := time.Now()
+
+	r, err := store.Allow(ctx, "ip:1", limit, now)
+	require.NoError(t, err)
+	require.Equal(t, ratelimit.Result{Allowed: true, Remaining: 1}, r)
+	r, _ = store.Allow(ctx, "ip:1", limit, now)
+	require.Equal(t, ratelimit.Result{Allowed: true, Remaining: 0}, r)
+
+	r, _ = store.Allow(ctx, "ip:1", limit, now.Add(500*time.Millisecond))
+	require.False(t, r.Allowed)
+	require.Equal(t, 500*time.Millisecond, r.RetryAfter)
+
+	// Key khác có bucket riêng
+	r, _ = store.Allow(ctx, "ip:2", limit, now)
+	require.True(t, r.Allowed)
+
+	r, _ = store.Allow(ctx, "ip:1", limit, now.Add(time.Second))
+	require.True(t, r.Allowed, "one token refilled after a second")
+
+	// Bucket đã nạp đầy bị xoá ở lần dọn tiếp theo
+	r, _ = store.Allow(ctx, "ip:3", limit, now.Add(2*time.Minute))
+	require.True(t, r.Allowed)
+	require.Equal(t, 1, store.Len())
+}
+
+func TestUnlimited(t *testing.T) {
+	store := ratelimit.NewMemoryStore()
+	for range 10 {
+		r, err := store.Allow(context.Background(), "k", ratelimit.Limit{}, time.Now())
+		require.NoError(t, err)
+		require.True(t, r.Allowed)
+	}
+	require.Zero(t, store.Len())
+}
+
+func TestPerMinute(t *testing.T) {
+	require.Equal(t, ratelimit.Limit{Rate: 0.5, Burst: 5}, ratelimit.PerMinute(30, 5))
+}
+
+// fakeRedis đọc một lệnh RESP, gửi lệnh đó qua commands và trả về reply
+func fakeRedis(t *testing.T, reply string) (string, <-chan []string) {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	t.Cleanup(func() { ln.Close() })
+	commands := make(chan []string, 10)
+	go func() {
+		for {
+			conn, err := ln.Accept()
+			if err != nil {
+				return
+			}
+			go func() {
+				defer conn.Close()
+				r := bufio.NewReader(conn)
+				for {
+					header, err := r.ReadString('\n')
+					if err != nil {
+						return
+					}
+					n, _ := strconv.Atoi(strings.TrimSpace(header[1:]))
+					args := make([]string, n)
+					for i := range args {
+						size, _ := r.ReadString('\n')
+						length, _ := strconv.Atoi(strings.TrimSpace(size[1:]))
+						buf := make([]byte, length+2)
+						if _, err := io.ReadFull(r, buf); err != nil {
+							return
+						}
+						args[i] = string(buf[:length])
+					}
+					commands <- args
+					conn.Write([]byte(reply))
+				}
+			}()
+		}
+	}()
+	return ln.Addr().String(), commands
+}
+
+func TestRedisStore(t *testing.T) {
+	ctx := context.Background()
+	now := time.UnixMilli(1_700_000_000_000)
+	limit := ratelimit.Limit{Rate: 2, Burst: 10}
+
+	t.Run("allowed", func(t *testing.T) {
+		addr, commands := fakeRedis(t, "*2\r\n:1\r\n$3\r\n7.5\r\n")
+		client := redis.NewClient(redis.Config{Addr: addr})
+		defer client.Close()
+		store := ratelimit.NewRedisStore(client, "rl:")
+
+		r, err := store.Allow(ctx, "ip:/books:1.2.3.4", limit, now)
+		require.NoError(t, err)
+		require.Equal(t, ratelimit.Result{Allowed: true, Remaining: 7}, r)
+
+		cmd := <-commands
+		require.Equal(t, "EVAL", cmd[0])
+		require.Equal(t, []string{"1", "rl:ip:/books:1.2.3.4", "2", "10", "1700000000000"}, cmd[2:])
+
+		// Kết nối được dùng lại cho lệnh sau
+		_, err = store.Allow(ctx, "ip:/books:1.2.3.4", limit, now)
+		require.NoError(t, err)
+	})
+
+	t.Run("denied", func(t *testing.T) {
+		addr, _ := fakeRedis(t, "*2\r\n:0\r\n$4\r\n0.25\r\n")
+		client := redis.NewClient(redis.Config{Addr: addr})
+		defer client.Close()
+		store := ratelimit.NewRedisStore(client, "rl:")
+
+		r, err := store.Allow(ctx, "k", limit, now)
+		require.NoError(t, err)
+		require.False(t, r.Allowed)
+		require.Equal(t, 375*time.Millisecond, r.RetryAfter)
+	})
+
+	t.Run("redis error", func(t *testing.T) {
+		addr, _ := fakeRedis(t, "-NOSCRIPT no scripting\r\n")
+		client := redis.NewClient(redis.Config{Addr: addr})
+		defer client.Close()
+		store := ratelimit.NewRedisStore(client, "rl:")
+
+		_, err := store.Allow(ctx, "k", limit, now)
+		require.ErrorContains(t, err, "NOSCRIPT")
+	})
+
+	t.Run("unreachable", func(t *testing.T) {
+		ln, err := net.Listen("tcp", "127.0.0.1:0")
+		require.NoError(t, err)
+		addr := ln.Addr().String()
+		ln.Close()
+
+		_, err = ratelimit.NewRedisStore(redis.NewClient(redis.Config{Addr: addr}), "rl:").Allow(ctx, "k", limit, now)
+		require.Error(t, err)
+	})
+}
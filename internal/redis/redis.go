@@ -0,0 +1,248 @@
+// Package redis là client RESP tối giản dùng chung cho readiness và rate limit: giữ một
+// pool kết nối tới một địa chỉ và gửi từng lệnh, chờ reply.
+package redis
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"strconv"
+	"strings"
+	"sync"
+	"syscall"
+	"time"
+)
+
+// DefaultTimeout giới hạn một lệnh (kể cả khi phải mở kết nối mới) để redis chậm không
+// kéo dài request gọi tới nó
+const DefaultTimeout = time.Second
+
+// maxIdleConns là số kết nối giữ lại để dùng lại
+const maxIdleConns = 16
+
+// Config là địa chỉ redis và thông tin đăng nhập; Password rỗng thì không gửi AUTH, DB 0
+// thì không gửi SELECT, Timeout 0 dùng DefaultTimeout
+type Config struct {
+	Addr     string
+	Password string
+	DB       int
+	Timeout  time.Duration
+}
+
+// Client gửi lệnh tới redis theo Config; an toàn khi dùng từ nhiều goroutine
+type Client struct {
+	cfg  Config
+	idle chan *conn
+
+	mu     sync.Mutex
+	closed bool
+}
+
+// conn là một kết nối cùng reader của nó; reader giữ lại theo kết nối vì có thể còn dữ
+// liệu đã đọc vào buffer
+type conn struct {
+	net.Conn
+	r *bufio.Reader
+}
+
+// NewClient tạo client theo cfg; kết nối được mở khi gửi lệnh đầu tiên
+func NewClient(cfg Config) *Client {
+	if cfg.Timeout <= 0 {
+		cfg.Timeout = DefaultTimeout
+	}
+	return &Client{cfg: cfg, idle: make(chan *conn, maxIdleConns)}
+}
+
+// Error là lỗi redis trả về (reply bắt đầu bằng "-"); kết nối vẫn dùng lại được
+type Error string
+
+func (e Error) Error() string { return "redis: " + string(e) }
+
+// Ping gửi PING và chờ PONG; "-NOAUTH ..." khi redis yêu cầu mật khẩu
+func (c *Client) Ping(ctx context.Context) error {
+	reply, err := c.Do(ctx, "PING")
+	if err != nil {
+		return err
+	}
+	if reply != "PONG" {
+		return fmt.Errorf("redis: unexpected reply %v", reply)
+	}
+	return nil
+}
+
+// Do gửi một lệnh và đọc reply: string, int64, nil (bulk string không tồn tại) hoặc
+// []any. Kết nối bị đóng khi có lỗi mạng hoặc lỗi giao thức. Kết nối lấy từ pool có thể
+// đã bị redis đóng (idle timeout, restart); khi đó lệnh được gửi lại một lần trên kết nối
+// mới, vì redis chưa nhận được lệnh trên kết nối cũ.
+func (c *Client) Do(ctx context.Context, args ...string) (any, error) {
+	ctx, cancel := context.WithTimeout(ctx, c.cfg.Timeout)
+	defer cancel()
+
+	cn, pooled := c.pooled()
+	if cn == nil {
+		var err error
+		if cn, err = c.dial(ctx); err != nil {
+			return nil, err
+		}
+	}
+	reply, err := cn.do(ctx, args)
+	if err != nil && pooled && stale(err) {
+		cn.Close()
+		if cn, err = c.dial(ctx); err != nil {
+			return nil, err
+		}
+		reply, err = cn.do(ctx, args)
+	}
+	var replyErr Error
+	if err != nil && !errors.As(err, &replyErr) {
+		cn.Close()
+		return nil, err
+	}
+	c.put(cn)
+	return reply, err
+}
+
+// Close đóng các kết nối đang rảnh; kết nối đang dùng bị đóng khi lệnh của nó xong
+func (c *Client) Close() error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.closed = true
+	for {
+		select {
+		case cn := <-c.idle:
+			cn.Close()
+		default:
+			return nil
+		}
+	}
+}
+
+func (c *Client) pooled() (*conn, bool) {
+	select {
+	case cn := <-c.idle:
+		return cn, true
+	default:
+		return nil, false
+	}
+}
+
+func (c *Client) put(cn *conn) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.closed {
+		cn.Close()
+		return
+	}
+	select {
+	case c.idle <- cn:
+	default:
+		cn.Close()
+	}
+}
+
+// dial mở kết nối mới, đăng nhập bằng Password và chọn DB
+func (c *Client) dial(ctx context.Context) (*conn, error) {
+	var dialer net.Dialer
+	nc, err := dialer.DialContext(ctx, "tcp", c.cfg.Addr)
+	if err != nil {
+		return nil, err
+	}
+	cn := &conn{Conn: nc, r: bufio.NewReader(nc)}
+	var setup [][]string
+	if c.cfg.Password != "" {
+		setup = append(setup, []string{"AUTH", c.cfg.Password})
+	}
+	if c.cfg.DB != 0 {
+		setup = append(setup, []string{"SELECT", strconv.Itoa(c.cfg.DB)})
+	}
+	for _, args := range setup {
+		if _, err := cn.do(ctx, args); err != nil {
+			cn.Close()
+			return nil, fmt.Errorf("redis: %s failed: %w", args[0], err)
+		}
+	}
+	return cn, nil
+}
+
+// do gửi args và đọc một reply trong deadline của ctx
+func (cn *conn) do(ctx context.Context, args []string) (any, error) {
+	deadline, _ := ctx.Deadline()
+	if err := cn.SetDeadline(deadline); err != nil {
+		return nil, err
+	}
+	var cmd strings.Builder
+	fmt.Fprintf(&cmd, "*%d\r\n", len(args))
+	for _, arg := range args {
+		fmt.Fprintf(&cmd, "$%d\r\n%s\r\n", len(arg), arg)
+	}
+	if _, err := io.WriteString(cn, cmd.String()); err != nil {
+		return nil, err
+	}
+	return readReply(cn.r)
+}
+
+// stale cho biết lỗi là do redis đã đóng kết nối trước khi nhận lệnh: EOF trước byte
+// đầu tiên của reply, hoặc kết nối bị reset. Timeout không tính vì lệnh có thể đã chạy.
+func stale(err error) bool {
+	return errors.Is(err, io.EOF) || errors.Is(err, syscall.ECONNRESET) || errors.Is(err, syscall.EPIPE)
+}
+
+// readReply đọc một reply RESP: chuỗi, lỗi, số nguyên, bulk string (nil khi không
+// tồn tại) hoặc mảng các reply. io.EOF chỉ trả về khi chưa đọc được byte nào; đứt giữa
+// chừng là io.ErrUnexpectedEOF.
+func readReply(r *bufio.Reader) (any, error) {
+	line, err := r.ReadString('\n')
+	if err != nil {
+		if err == io.EOF && line != "" {
+			err = io.ErrUnexpectedEOF
+		}
+		return nil, err
+	}
+	line = strings.TrimSuffix(line, "\r\n")
+	if line == "" {
+		return nil, errors.New("redis: empty reply")
+	}
+
+	switch body := line[1:]; line[0] {
+	case '+':
+		return body, nil
+	case '-':
+		return nil, Error(body)
+	case ':':
+		return strconv.ParseInt(body, 10, 64)
+	case '$':
+		n, err := strconv.Atoi(body)
+		if err != nil || n < 0 {
+			return nil, err
+		}
+		buf := make([]byte, n+2)
+		if _, err := io.ReadFull(r, buf); err != nil {
+			return nil, unexpectedEOF(err)
+		}
+		return string(buf[:n]), nil
+	case '*':
+		n, err := strconv.Atoi(body)
+		if err != nil || n < 0 {
+			return nil, err
+		}
+		values := make([]any, n)
+		for i := range values {
+			if values[i], err = readReply(r); err != nil {
+				return nil, unexpectedEOF(err)
+			}
+		}
+		return values, nil
+	default:
+		return nil, fmt.Errorf("redis: unknown reply type %q", line[0])
+	}
+}
+
+func unexpectedEOF(err error) error {
+	if err == io.EOF {
+		return io.ErrUnexpectedEOF
+	}
+	return err
+}
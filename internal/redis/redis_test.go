@@ -0,0 +1,144 @@
+package redis_test
+
+import (
+	"bufio"
+	"context"
+	"net"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/maithuc2003/Test_GIN_golang/internal/redis"
+)
+
+// fakeRedis trả lời lần lượt các lệnh bằng replies (lệnh thứ i nhận replies[i]) và
+// đếm số kết nối đã mở
+func fakeRedis(t *testing.T, replies ...string) (string, *atomic.Int32) {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	t.Cleanup(func() { ln.Close() })
+	var conns, next atomic.Int32
+	go func() {
+		for {
+			conn, err := ln.Accept()
+			if err != nil {
+				return
+			}
+			conns.Add(1)
+			go func() {
+				defer conn.Close()
+				r := bufio.NewReader(conn)
+				for {
+					header, err := r.ReadString('\n')
+					if err != nil {
+						return
+					}
+					// Bỏ qua các dòng độ dài và giá trị của từng tham số
+					n, _ := strconv.Atoi(strings.TrimSpace(header[1:]))
+					for range 2 * n {
+						if _, err := r.ReadString('\n'); err != nil {
+							return
+						}
+					}
+					conn.Write([]byte(replies[int(next.Add(1)-1)%len(replies)]))
+				}
+			}()
+		}
+	}()
+	return ln.Addr().String(), &conns
+}
+
+func TestClient_Do(t *testing.T) {
+	ctx := context.Background()
+	addr, conns := fakeRedis(t, "$3\r\nbar\r\n", "$-1\r\n", ":42\r\n", "*2\r\n+OK\r\n$0\r\n\r\n")
+	client := redis.NewClient(redis.Config{Addr: addr})
+	defer client.Close()
+
+	want := []any{"bar", nil, int64(42), []any{"OK", ""}}
+	for _, expected := range want {
+		reply, err := client.Do(ctx, "GET", "foo")
+		require.NoError(t, err)
+		require.Equal(t, expected, reply)
+	}
+	require.Equal(t, int32(1), conns.Load(), "connection is reused")
+}
+
+func TestClient_Ping(t *testing.T) {
+	ctx := context.Background()
+
+	addr, _ := fakeRedis(t, "+PONG\r\n")
+	require.NoError(t, redis.NewClient(redis.Config{Addr: addr}).Ping(ctx))
+
+	addr, conns := fakeRedis(t, "-NOAUTH Authentication required.\r\n")
+	client := redis.NewClient(redis.Config{Addr: addr})
+	err := client.Ping(ctx)
+	var replyErr redis.Error
+	require.ErrorAs(t, err, &replyErr)
+	require.ErrorContains(t, err, "NOAUTH")
+	// Lỗi do redis trả về không làm hỏng kết nối
+	require.Error(t, client.Ping(ctx))
+	require.Equal(t, int32(1), conns.Load())
+
+	addr, _ = fakeRedis(t, "+OK\r\n")
+	require.ErrorContains(t, redis.NewClient(redis.Config{Addr: addr}).Ping(ctx), "unexpected reply")
+
+	// Cổng không có ai lắng nghe
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	closed := ln.Addr().String()
+	ln.Close()
+	require.Error(t, redis.NewClient(redis.Config{Addr: closed}).Ping(ctx))
+}
+
+func TestClient_AuthAndSelect(t *testing.T) {
+	ctx := context.Background()
+
+	// AUTH và SELECT trả +OK trước reply của PING
+	addr, conns := fakeRedis(t, "+OK\r\n", "+OK\r\n", "+PONG\r\n")
+	client := redis.NewClient(redis.Config{Addr: addr, Password: "secret", DB: 2})
+	defer client.Close()
+	require.NoError(t, client.Ping(ctx))
+	require.Equal(t, int32(1), conns.Load())
+
+	addr, _ = fakeRedis(t, "-WRONGPASS invalid username-password pair\r\n")
+	err := redis.NewClient(redis.Config{Addr: addr, Password: "wrong"}).Ping(ctx)
+	require.ErrorContains(t, err, "AUTH failed")
+	require.ErrorContains(t, err, "WRONGPASS")
+}
+
+func TestClient_StaleConnection(t *testing.T) {
+	ctx := context.Background()
+
+	// Redis đóng kết nối sau mỗi lệnh, như khi hết idle timeout hoặc khởi động lại
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	t.Cleanup(func() { ln.Close() })
+	var conns atomic.Int32
+	go func() {
+		for {
+			conn, err := ln.Accept()
+			if err != nil {
+				return
+			}
+			conns.Add(1)
+			r := bufio.NewReader(conn)
+			for range 3 {
+				if _, err := r.ReadString('\n'); err != nil {
+					break
+				}
+			}
+			conn.Write([]byte("+PONG\r\n"))
+			conn.Close()
+		}
+	}()
+
+	client := redis.NewClient(redis.Config{Addr: ln.Addr().String()})
+	defer client.Close()
+	require.NoError(t, client.Ping(ctx))
+	// Kết nối trong pool đã bị đóng: lệnh được gửi lại trên kết nối mới
+	require.NoError(t, client.Ping(ctx))
+	require.Equal(t, int32(2), conns.Load())
+}
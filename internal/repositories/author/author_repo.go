@@ -1,10 +1,18 @@
 package author
 
 import (
+	"context"
+	"errors"
 	"fmt"
+	"strings"
 
+	"github.com/maithuc2003/Test_GIN_golang/internal/events"
 	"github.com/maithuc2003/Test_GIN_golang/internal/interfaces/repositories"
 	"github.com/maithuc2003/Test_GIN_golang/internal/models"
+	"github.com/maithuc2003/Test_GIN_golang/internal/repositories/dberr"
+	"github.com/maithuc2003/Test_GIN_golang/internal/repositories/outbox"
+	"github.com/maithuc2003/Test_GIN_golang/internal/tracing"
+	"github.com/maithuc2003/Test_GIN_golang/pkg/textnorm"
 
 	"gorm.io/gorm"
 	"gorm.io/gorm/clause"
@@ -18,17 +26,36 @@ func NewAuthorRepo(db *gorm.DB) repositories.AuthorRepositoriesInterface {
 	return &authorRepo{db: db}
 }
 
-func (r *authorRepo) GetAllAuthors() ([]*models.Author, error) {
+// FilterScope áp dụng bộ lọc tác giả; dùng chung cho danh sách và export
+func FilterScope(filter models.AuthorFilter) func(*gorm.DB) *gorm.DB {
+	return func(db *gorm.DB) *gorm.DB {
+		if filter.Name != "" {
+			db = db.Where("LOWER(authors.name) LIKE ?", "%"+strings.ToLower(filter.Name)+"%")
+		}
+		if filter.Nationality != "" {
+			db = db.Where("LOWER(authors.nationality) = ?", strings.ToLower(filter.Nationality))
+		}
+		return db
+	}
+}
+
+func (r *authorRepo) GetAllAuthors(ctx context.Context, filter models.AuthorFilter) ([]*models.Author, error) {
+	ctx, span := tracing.Start(ctx, "authorRepo.GetAllAuthors")
+	defer span.End()
+
 	var authors []*models.Author
-	if err := r.db.Find(&authors).Error; err != nil {
+	if err := r.db.WithContext(ctx).Scopes(FilterScope(filter)).Find(&authors).Error; err != nil {
 		return nil, fmt.Errorf("failed to query authors: %w", err)
 	}
 	return authors, nil
 }
 
-func (r *authorRepo) GetByAuthorID(id int) (*models.Author, error) {
+func (r *authorRepo) GetByAuthorID(ctx context.Context, id int) (*models.Author, error) {
+	ctx, span := tracing.Start(ctx, "authorRepo.GetByAuthorID")
+	defer span.End()
+
 	var author models.Author
-	if err := r.db.First(&author, id).Error; err != nil {
+	if err := r.db.WithContext(ctx).First(&author, id).Error; err != nil {
 		if err == gorm.ErrRecordNotFound {
 			return nil, fmt.Errorf("author with ID %d not found", id)
 		}
@@ -37,40 +64,206 @@ func (r *authorRepo) GetByAuthorID(id int) (*models.Author, error) {
 	return &author, nil
 }
 
-func (r *authorRepo) CreateAuthor(author *models.Author) error {
-	if err := r.db.Create(author).Error; err != nil {
+// GetByName tìm tác giả theo tên, không phân biệt hoa thường và dấu; trả về nil nếu không có
+func (r *authorRepo) GetByName(ctx context.Context, name string) (*models.Author, error) {
+	ctx, span := tracing.Start(ctx, "authorRepo.GetByName")
+	defer span.End()
+
+	var author models.Author
+	normalized := textnorm.Name(name)
+	err := r.db.WithContext(ctx).Where("normalized_name = ?", normalized).First(&author).Error
+	if err == nil {
+		return &author, nil
+	}
+	if !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, fmt.Errorf("failed to fetch author: %w", err)
+	}
+
+	// Tên cũ của tác giả đã bị gộp -> trả về tác giả đích
+	err = r.db.WithContext(ctx).
+		Joins("JOIN author_aliases ON author_aliases.author_id = authors.id").
+		Where("author_aliases.normalized_name = ?", normalized).
+		First(&author).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to fetch author by alias: %w", err)
+	}
+	return &author, nil
+}
+
+func (r *authorRepo) CreateAuthor(ctx context.Context, author *models.Author) error {
+	ctx, span := tracing.Start(ctx, "authorRepo.CreateAuthor")
+	defer span.End()
+
+	if err := r.db.WithContext(ctx).Create(author).Error; err != nil {
+		if dberr.IsUniqueViolation(err) {
+			return duplicateName(author.Name)
+		}
 		return fmt.Errorf("failed to create author: %w", err)
 	}
 	return nil
 }
 
-func (r *authorRepo) DeleteById(id int) (*models.Author, error) {
-	author, err := r.GetByAuthorID(id)
+func (r *authorRepo) DeleteById(ctx context.Context, id int) (*models.Author, error) {
+	ctx, span := tracing.Start(ctx, "authorRepo.DeleteById")
+	defer span.End()
+
+	author, err := r.GetByAuthorID(ctx, id)
 	if err != nil {
 		return nil, err
 	}
-	if err := r.db.Delete(&models.Author{}, id).Error; err != nil {
+	err = r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Delete(&models.Author{}, id).Error; err != nil {
+			return err
+		}
+		return outbox.Append(tx, events.AuthorDeleted{AuthorID: author.ID, Name: author.Name})
+	})
+	if err != nil {
 		// Check if it's a foreign key constraint
 		return nil, fmt.Errorf("failed to delete author: %w", err)
 	}
 	return author, nil
 }
 
-func (r *authorRepo) UpdateById(author *models.Author) (*models.Author, error) {
+func (r *authorRepo) UpdateById(ctx context.Context, author *models.Author) (*models.Author, error) {
+	ctx, span := tracing.Start(ctx, "authorRepo.UpdateById")
+	defer span.End()
+
 	var existing models.Author
-	if err := r.db.First(&existing, author.ID).Error; err != nil {
+	if err := r.db.WithContext(ctx).First(&existing, author.ID).Error; err != nil {
 		return nil, fmt.Errorf("author_id %d does not exist", author.ID)
 	}
 
 	// Cập nhật thông tin
-	if err := r.db.Model(&existing).
+	if err := r.db.WithContext(ctx).Model(&existing).
 		Clauses(clause.Returning{}).
 		Updates(map[string]interface{}{
-			"name":        author.Name,
-			"nationality": author.Nationality,
-			"updated_at":  author.UpdatedAt,
+			"name":            author.Name,
+			"normalized_name": textnorm.Name(author.Name),
+			"nationality":     author.Nationality,
+			"updated_at":      author.UpdatedAt,
 		}).Error; err != nil {
+		if dberr.IsUniqueViolation(err) {
+			return nil, duplicateName(author.Name)
+		}
 		return nil, fmt.Errorf("failed to update author: %w", err)
 	}
 	return &existing, nil
 }
+
+// Merge gộp các tác giả nguồn vào tác giả đích trong một transaction: chuyển sách
+// (tác giả chính và đồng tác giả), lưu tên nguồn làm alias rồi xoá tác giả nguồn.
+func (r *authorRepo) Merge(ctx context.Context, targetID int, sourceIDs []int) (*models.AuthorMergeResult, error) {
+	ctx, span := tracing.Start(ctx, "authorRepo.Merge")
+	defer span.End()
+
+	result := &models.AuthorMergeResult{MergedIDs: sourceIDs}
+
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var target models.Author
+		if err := tx.First(&target, targetID).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return fmt.Errorf("%w: author with ID %d not found", repositories.ErrNotFound, targetID)
+			}
+			return fmt.Errorf("failed to fetch author: %w", err)
+		}
+
+		var sources []models.Author
+		if err := tx.Where("id IN ?", sourceIDs).Find(&sources).Error; err != nil {
+			return fmt.Errorf("failed to fetch source authors: %w", err)
+		}
+		if missing := missingAuthorID(sources, sourceIDs); missing != 0 {
+			return fmt.Errorf("%w: author with ID %d not found", repositories.ErrNotFound, missing)
+		}
+
+		moved := tx.Model(&models.Book{}).Where("author_id IN ?", sourceIDs).Update("author_id", targetID)
+		if moved.Error != nil {
+			return fmt.Errorf("failed to move books: %w", moved.Error)
+		}
+		result.BooksMoved = moved.RowsAffected
+
+		if err := moveCoAuthorLinks(tx, targetID, sourceIDs); err != nil {
+			return err
+		}
+
+		// Alias cũ của nguồn chuyển sang đích, sau đó thêm tên của chính các nguồn
+		if err := tx.Model(&models.AuthorAlias{}).Where("author_id IN ?", sourceIDs).
+			Update("author_id", targetID).Error; err != nil {
+			return fmt.Errorf("failed to move aliases: %w", err)
+		}
+		for _, source := range sources {
+			alias := models.AuthorAlias{AuthorID: targetID, Name: source.Name}
+			if err := tx.Clauses(clause.OnConflict{
+				Columns:   []clause.Column{{Name: "normalized_name"}},
+				DoUpdates: clause.AssignmentColumns([]string{"author_id"}),
+			}).Create(&alias).Error; err != nil {
+				return fmt.Errorf("failed to record alias %q: %w", source.Name, err)
+			}
+			result.Aliases = append(result.Aliases, source.Name)
+		}
+
+		if err := tx.Where("id IN ?", sourceIDs).Delete(&models.Author{}).Error; err != nil {
+			return fmt.Errorf("failed to delete source authors: %w", err)
+		}
+		deleted := make([]events.Domain, len(sources))
+		for i, source := range sources {
+			deleted[i] = events.AuthorDeleted{AuthorID: source.ID, Name: source.Name, MergedInto: &target.ID}
+		}
+		if err := outbox.Append(tx, deleted...); err != nil {
+			return err
+		}
+		result.Author = &target
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// moveCoAuthorLinks chuyển các dòng book_authors của nguồn sang đích, bỏ dòng trùng
+func moveCoAuthorLinks(tx *gorm.DB, targetID int, sourceIDs []int) error {
+	var links []models.BookAuthor
+	if err := tx.Where("author_id IN ?", sourceIDs).Find(&links).Error; err != nil {
+		return fmt.Errorf("failed to fetch co-author links: %w", err)
+	}
+	if len(links) == 0 {
+		return nil
+	}
+	if err := tx.Where("author_id IN ?", sourceIDs).Delete(&models.BookAuthor{}).Error; err != nil {
+		return fmt.Errorf("failed to remove co-author links: %w", err)
+	}
+
+	seen := make(map[uint]bool, len(links))
+	moved := make([]models.BookAuthor, 0, len(links))
+	for _, link := range links {
+		if seen[link.BookID] {
+			continue
+		}
+		seen[link.BookID] = true
+		moved = append(moved, models.BookAuthor{BookID: link.BookID, AuthorID: targetID})
+	}
+	if err := tx.Clauses(clause.OnConflict{DoNothing: true}).Create(&moved).Error; err != nil {
+		return fmt.Errorf("failed to link books to author %d: %w", targetID, err)
+	}
+	return nil
+}
+
+func missingAuthorID(found []models.Author, ids []int) int {
+	exists := make(map[int]bool, len(found))
+	for _, a := range found {
+		exists[a.ID] = true
+	}
+	for _, id := range ids {
+		if !exists[id] {
+			return id
+		}
+	}
+	return 0
+}
+
+func duplicateName(name string) error {
+	return fmt.Errorf("%w: author with the same name already exists: %q", repositories.ErrConflict, name)
+}
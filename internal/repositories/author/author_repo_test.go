@@ -1,12 +1,17 @@
 package author_test
 
 import (
+	"context"
+	"errors"
+	"fmt"
 	"testing"
 	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 
+	"github.com/maithuc2003/Test_GIN_golang/internal/events"
+	"github.com/maithuc2003/Test_GIN_golang/internal/interfaces/repositories"
 	"github.com/maithuc2003/Test_GIN_golang/internal/models"
 	"github.com/maithuc2003/Test_GIN_golang/internal/repositories/author"
 
@@ -19,15 +24,18 @@ import (
 
 // setupTestDB dùng modernc.org/sqlite driver, không cần cgo
 func setupTestDB(t *testing.T) *gorm.DB {
+	// Mỗi test một DB riêng để unique index trên tên tác giả không ảnh hưởng test khác
+	dsn := fmt.Sprintf("file:testdb_%d?mode=memory&cache=shared", time.Now().UnixNano())
+
 	// Tạo config cho sqlite driver với driverName "sqlite" là driver của modernc
 	db, err := gorm.Open(sqlitedriver.New(sqlitedriver.Config{
-		DSN:        "file::memory:?cache=shared",
+		DSN:        dsn,
 		DriverName: "sqlite",
 	}), &gorm.Config{})
 
 	require.NoError(t, err)
 
-	err = db.AutoMigrate(&models.Author{})
+	err = db.AutoMigrate(&models.Author{}, &models.AuthorAlias{}, &models.Book{}, &models.OutboxEvent{})
 	require.NoError(t, err)
 
 	return db
@@ -43,7 +51,7 @@ func TestAuthorRepo_GetAllAuthors(t *testing.T) {
 		{Name: "Author2", Nationality: "UK"},
 	}
 	for _, a := range authors {
-		err := repo.CreateAuthor(&a)
+		err := repo.CreateAuthor(context.Background(), &a)
 		require.NoError(t, err)
 	}
 
@@ -61,7 +69,7 @@ func TestAuthorRepo_GetAllAuthors(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			got, err := repo.GetAllAuthors()
+			got, err := repo.GetAllAuthors(context.Background(), models.AuthorFilter{})
 			if tt.wantErr {
 				assert.Error(t, err)
 				return
@@ -76,7 +84,7 @@ func TestAuthorRepo_GetByAuthorID(t *testing.T) {
 	repo := author.NewAuthorRepo(db)
 
 	author := models.Author{Name: "Author1", Nationality: "US"}
-	err := repo.CreateAuthor(&author)
+	err := repo.CreateAuthor(context.Background(), &author)
 	require.NoError(t, err)
 
 	tests := []struct {
@@ -91,7 +99,7 @@ func TestAuthorRepo_GetByAuthorID(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			got, err := repo.GetByAuthorID(tt.id)
+			got, err := repo.GetByAuthorID(context.Background(), tt.id)
 			if tt.wantErr {
 				// Kiểm tra có lỗi, và object trả về phải nil hoặc empty
 				assert.Error(t, err)
@@ -121,7 +129,7 @@ func TestAuthorRepo_CreateAuthor(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			err := repo.CreateAuthor(&tt.input)
+			err := repo.CreateAuthor(context.Background(), &tt.input)
 			if tt.wantErr {
 				assert.Error(t, err)
 				return
@@ -137,7 +145,7 @@ func TestAuthorRepo_DeleteById(t *testing.T) {
 	repo := author.NewAuthorRepo(db)
 
 	author := models.Author{Name: "AuthorToDelete", Nationality: "US"}
-	err := repo.CreateAuthor(&author)
+	err := repo.CreateAuthor(context.Background(), &author)
 	require.NoError(t, err)
 
 	tests := []struct {
@@ -151,7 +159,7 @@ func TestAuthorRepo_DeleteById(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			got, err := repo.DeleteById(tt.id)
+			got, err := repo.DeleteById(context.Background(), tt.id)
 			if tt.wantErr {
 				assert.Error(t, err)
 				assert.Nil(t, got)
@@ -169,7 +177,7 @@ func TestAuthorRepo_UpdateById(t *testing.T) {
 	repo := author.NewAuthorRepo(db)
 
 	author := models.Author{Name: "Original", Nationality: "US", UpdatedAt: time.Now()}
-	err := repo.CreateAuthor(&author)
+	err := repo.CreateAuthor(context.Background(), &author)
 	require.NoError(t, err)
 
 	tests := []struct {
@@ -195,7 +203,7 @@ func TestAuthorRepo_UpdateById(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			got, err := repo.UpdateById(&tt.updateData)
+			got, err := repo.UpdateById(context.Background(), &tt.updateData)
 			if tt.wantErr {
 				assert.Error(t, err)
 				return
@@ -206,3 +214,138 @@ func TestAuthorRepo_UpdateById(t *testing.T) {
 		})
 	}
 }
+
+func TestAuthorRepo_UniqueName(t *testing.T) {
+	db := setupTestDB(t)
+	repo := author.NewAuthorRepo(db)
+	ctx := context.Background()
+
+	existing := models.Author{Name: "Nguyễn Nhật Ánh", Nationality: "VN"}
+	require.NoError(t, repo.CreateAuthor(ctx, &existing))
+	other := models.Author{Name: "Đặng Thùy Trâm", Nationality: "VN"}
+	require.NoError(t, repo.CreateAuthor(ctx, &other))
+
+	t.Run("create conflicts regardless of case and diacritics", func(t *testing.T) {
+		for _, name := range []string{"nguyen nhat anh", "NGUYỄN  NHẬT ÁNH", "Nguyen Nhat Anh"} {
+			err := repo.CreateAuthor(ctx, &models.Author{Name: name})
+			require.Error(t, err)
+			assert.True(t, errors.Is(err, repositories.ErrConflict), name)
+		}
+	})
+
+	t.Run("update to an existing name conflicts", func(t *testing.T) {
+		_, err := repo.UpdateById(ctx, &models.Author{ID: other.ID, Name: "nguyen nhat anh", UpdatedAt: time.Now()})
+		require.Error(t, err)
+		assert.True(t, errors.Is(err, repositories.ErrConflict))
+	})
+
+	t.Run("author keeps own name with different casing", func(t *testing.T) {
+		got, err := repo.UpdateById(ctx, &models.Author{ID: other.ID, Name: "dang thuy tram", UpdatedAt: time.Now()})
+		require.NoError(t, err)
+		assert.Equal(t, "dang thuy tram", got.Name)
+	})
+
+	t.Run("lookup by name ignores diacritics", func(t *testing.T) {
+		got, err := repo.GetByName(ctx, "nguyen nhat anh")
+		require.NoError(t, err)
+		require.NotNil(t, got)
+		assert.Equal(t, existing.ID, got.ID)
+	})
+}
+
+func TestAuthorRepo_Merge(t *testing.T) {
+	db := setupTestDB(t)
+	repo := author.NewAuthorRepo(db)
+	ctx := context.Background()
+
+	target := models.Author{Name: "Nguyễn Nhật Ánh", Nationality: "VN"}
+	require.NoError(t, repo.CreateAuthor(ctx, &target))
+	dup1 := models.Author{Name: "Nguyen N. Anh"}
+	require.NoError(t, repo.CreateAuthor(ctx, &dup1))
+	dup2 := models.Author{Name: "N. N. Anh"}
+	require.NoError(t, repo.CreateAuthor(ctx, &dup2))
+	require.NoError(t, db.Create(&models.AuthorAlias{AuthorID: dup2.ID, Name: "Anh Nguyen"}).Error)
+
+	primary := models.Book{Title: "Mắt Biếc", AuthorID: dup1.ID}
+	require.NoError(t, db.Create(&primary).Error)
+	shared := models.Book{Title: "Tuyển tập", AuthorID: target.ID}
+	require.NoError(t, db.Create(&shared).Error)
+	require.NoError(t, db.Create(&[]models.BookAuthor{
+		{BookID: shared.ID, AuthorID: dup1.ID},
+		{BookID: shared.ID, AuthorID: dup2.ID},
+	}).Error)
+
+	t.Run("missing source rolls back", func(t *testing.T) {
+		_, err := repo.Merge(ctx, target.ID, []int{dup1.ID, 999})
+		require.Error(t, err)
+		assert.True(t, errors.Is(err, repositories.ErrNotFound))
+
+		got, err := repo.GetByAuthorID(ctx, dup1.ID)
+		require.NoError(t, err)
+		assert.Equal(t, dup1.Name, got.Name)
+	})
+
+	t.Run("missing target", func(t *testing.T) {
+		_, err := repo.Merge(ctx, 999, []int{dup1.ID})
+		assert.True(t, errors.Is(err, repositories.ErrNotFound))
+	})
+
+	t.Run("merge moves books and records aliases", func(t *testing.T) {
+		result, err := repo.Merge(ctx, target.ID, []int{dup1.ID, dup2.ID})
+		require.NoError(t, err)
+		assert.Equal(t, target.ID, result.Author.ID)
+		assert.Equal(t, int64(1), result.BooksMoved)
+		assert.ElementsMatch(t, []string{"Nguyen N. Anh", "N. N. Anh"}, result.Aliases)
+
+		var moved models.Book
+		require.NoError(t, db.First(&moved, primary.ID).Error)
+		assert.Equal(t, target.ID, moved.AuthorID)
+
+		var links []models.BookAuthor
+		require.NoError(t, db.Find(&links).Error)
+		assert.Equal(t, []models.BookAuthor{{BookID: shared.ID, AuthorID: target.ID}}, links)
+
+		for _, id := range []int{dup1.ID, dup2.ID} {
+			_, err := repo.GetByAuthorID(ctx, id)
+			assert.Error(t, err)
+		}
+	})
+
+	t.Run("lookup by merged name resolves to target", func(t *testing.T) {
+		for _, name := range []string{"nguyen n. anh", "N. N. Anh", "Anh Nguyễn"} {
+			got, err := repo.GetByName(ctx, name)
+			require.NoError(t, err)
+			require.NotNil(t, got, name)
+			assert.Equal(t, target.ID, got.ID)
+			assert.Equal(t, target.Name, got.Name)
+		}
+	})
+}
+
+func TestAuthorRepo_OutboxEvents(t *testing.T) {
+	db := setupTestDB(t)
+	repo := author.NewAuthorRepo(db)
+	ctx := context.Background()
+
+	target := models.Author{Name: "Frank Herbert"}
+	require.NoError(t, repo.CreateAuthor(ctx, &target))
+	dup := models.Author{Name: "F. Herbert"}
+	require.NoError(t, repo.CreateAuthor(ctx, &dup))
+	other := models.Author{Name: "Isaac Asimov"}
+	require.NoError(t, repo.CreateAuthor(ctx, &other))
+
+	_, err := repo.Merge(ctx, target.ID, []int{dup.ID})
+	require.NoError(t, err)
+	_, err = repo.DeleteById(ctx, other.ID)
+	require.NoError(t, err)
+
+	var records []models.OutboxEvent
+	require.NoError(t, db.Order("id").Find(&records).Error)
+	require.Len(t, records, 2)
+	for _, r := range records {
+		assert.Equal(t, events.TypeAuthorDeleted, r.Type)
+		assert.Equal(t, events.AggregateAuthor, r.AggregateType)
+	}
+	assert.JSONEq(t, fmt.Sprintf(`{"author_id":%d,"name":"F. Herbert","merged_into":%d}`, dup.ID, target.ID), records[0].Payload)
+	assert.JSONEq(t, fmt.Sprintf(`{"author_id":%d,"name":"Isaac Asimov"}`, other.ID), records[1].Payload)
+}
@@ -1,13 +1,25 @@
 package book
 
 import (
+	"context"
 	"errors"
 	"fmt"
+	"strings"
+	"time"
 
+	"github.com/maithuc2003/Test_GIN_golang/internal/events"
 	"github.com/maithuc2003/Test_GIN_golang/internal/interfaces/repositories"
 	"github.com/maithuc2003/Test_GIN_golang/internal/models"
+	"github.com/maithuc2003/Test_GIN_golang/internal/repositories/category"
+	"github.com/maithuc2003/Test_GIN_golang/internal/repositories/dberr"
+	"github.com/maithuc2003/Test_GIN_golang/internal/repositories/outbox"
+	"github.com/maithuc2003/Test_GIN_golang/internal/repositories/reservation"
+	"github.com/maithuc2003/Test_GIN_golang/internal/tracing"
+	"github.com/maithuc2003/Test_GIN_golang/pkg/isbn"
 
+	"golang.org/x/text/language"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
 type bookRepo struct {
@@ -18,22 +30,429 @@ func NewRepository(db *gorm.DB) repositories.BookRepository {
 	return &bookRepo{db: db}
 }
 
-func (r *bookRepo) CreateBook(book *models.Book) error {
-	return r.db.Create(book).Error
+func (r *bookRepo) CreateBook(ctx context.Context, book *models.Book) error {
+	ctx, span := tracing.Start(ctx, "bookRepo.CreateBook")
+	defer span.End()
+
+	err := r.withAssociations(ctx, book, func(db *gorm.DB) error {
+		if err := checkPublisher(db, book.PublisherID); err != nil {
+			return err
+		}
+		if err := db.Omit(clause.Associations).Create(book).Error; err != nil {
+			return err
+		}
+		return outbox.Append(db, events.BookCreated{
+			BookID:   book.ID,
+			Title:    book.Title,
+			AuthorID: book.AuthorID,
+			ISBN:     book.ISBN,
+			Stock:    book.Stock,
+		})
+	})
+	return translateError(err, book)
+}
+
+// checkPublisher đảm bảo nhà xuất bản được tham chiếu có tồn tại
+func checkPublisher(db *gorm.DB, publisherID *uint) error {
+	if publisherID == nil {
+		return nil
+	}
+	var count int64
+	if err := db.Model(&models.Publisher{}).Where("id = ?", *publisherID).Count(&count).Error; err != nil {
+		return err
+	}
+	if count == 0 {
+		return errors.New("publisher not found")
+	}
+	return nil
 }
 
-func (r *bookRepo) GetAllBooks() ([]models.Book, error) {
+// translateError đổi lỗi trùng ISBN của từng driver thành ErrConflict
+func translateError(err error, book *models.Book) error {
+	if err != nil && dberr.IsUniqueViolation(err) && book.ISBN != nil {
+		return fmt.Errorf("%w: book with ISBN %s already exists", repositories.ErrConflict, *book.ISBN)
+	}
+	return err
+}
+
+// withAssociations chạy op trong transaction (cùng với event op ghi vào outbox); nếu
+// sách có danh sách đồng tác giả hoặc thể loại thì việc gán vào book_authors /
+// book_categories cũng chạy trong transaction đó
+func (r *bookRepo) withAssociations(ctx context.Context, book *models.Book, op func(db *gorm.DB) error) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := op(tx); err != nil {
+			return err
+		}
+		if book.AuthorIDs != nil {
+			if err := replaceAuthors(tx, book); err != nil {
+				return err
+			}
+		}
+		if book.CategoryIDs != nil {
+			return replaceCategories(tx, book)
+		}
+		return nil
+	})
+}
+
+// replaceAuthors gán lại toàn bộ tác giả (tác giả chính + đồng tác giả) cho sách
+func replaceAuthors(tx *gorm.DB, book *models.Book) error {
+	seen := map[int]bool{}
+	ids := make([]int, 0, len(book.AuthorIDs)+1)
+	for _, id := range append([]int{book.AuthorID}, book.AuthorIDs...) {
+		if id > 0 && !seen[id] {
+			seen[id] = true
+			ids = append(ids, id)
+		}
+	}
+
+	var authors []models.Author
+	if err := tx.Where("id IN ?", ids).Find(&authors).Error; err != nil {
+		return err
+	}
+	if len(authors) != len(ids) {
+		return errors.New("author not found")
+	}
+	if err := tx.Where("book_id = ?", book.ID).Delete(&models.BookAuthor{}).Error; err != nil {
+		return fmt.Errorf("failed to assign authors: %w", err)
+	}
+	links := make([]models.BookAuthor, len(ids))
+	for i, id := range ids {
+		links[i] = models.BookAuthor{BookID: book.ID, AuthorID: id}
+	}
+	if err := tx.Create(&links).Error; err != nil {
+		return fmt.Errorf("failed to assign authors: %w", err)
+	}
+	book.Authors = authors
+	return nil
+}
+
+// replaceCategories gán lại toàn bộ thể loại cho sách; danh sách rỗng là bỏ hết thể loại
+func replaceCategories(tx *gorm.DB, book *models.Book) error {
+	seen := map[uint]bool{}
+	ids := make([]uint, 0, len(book.CategoryIDs))
+	for _, id := range book.CategoryIDs {
+		if !seen[id] {
+			seen[id] = true
+			ids = append(ids, id)
+		}
+	}
+
+	var categories []models.Category
+	if len(ids) > 0 {
+		if err := tx.Where("id IN ?", ids).Find(&categories).Error; err != nil {
+			return err
+		}
+		if len(categories) != len(ids) {
+			return errors.New("category not found")
+		}
+	}
+	if err := tx.Where("book_id = ?", book.ID).Delete(&models.BookCategory{}).Error; err != nil {
+		return fmt.Errorf("failed to assign categories: %w", err)
+	}
+	if len(ids) > 0 {
+		links := make([]models.BookCategory, len(ids))
+		for i, id := range ids {
+			links[i] = models.BookCategory{BookID: book.ID, CategoryID: id}
+		}
+		if err := tx.Create(&links).Error; err != nil {
+			return fmt.Errorf("failed to assign categories: %w", err)
+		}
+	}
+	book.Categories = categories
+	return nil
+}
+
+// preloadAuthors nạp tác giả chính và các đồng tác giả
+func preloadAuthors(db *gorm.DB) *gorm.DB {
+	return db.Preload("Author").Preload("Authors")
+}
+
+// preloadIncludes nạp các quan hệ được yêu cầu qua include=author,publisher
+func preloadIncludes(filter models.BookFilter) func(*gorm.DB) *gorm.DB {
+	return func(db *gorm.DB) *gorm.DB {
+		if filter.Includes("author") {
+			db = db.Scopes(preloadAuthors)
+		}
+		if filter.Includes("publisher") {
+			db = db.Preload("Publisher")
+		}
+		if filter.Includes("categories") {
+			db = db.Preload("Categories")
+		}
+		return db
+	}
+}
+
+// FilterScope áp dụng bộ lọc sách; dùng chung cho danh sách và export
+func FilterScope(filter models.BookFilter) func(*gorm.DB) *gorm.DB {
+	return func(db *gorm.DB) *gorm.DB {
+		if filter.Title != "" {
+			db = db.Where("LOWER(books.title) LIKE ?", "%"+strings.ToLower(filter.Title)+"%")
+		}
+		if filter.AuthorID > 0 {
+			db = db.Where("books.author_id = ?", filter.AuthorID)
+		}
+		if filter.InStock != nil {
+			if *filter.InStock {
+				db = db.Where("books.stock > 0")
+			} else {
+				db = db.Where("books.stock <= 0")
+			}
+		}
+		if filter.ISBN != "" {
+			// ISBN-10 hay ISBN-13 đều được đổi về dạng đã lưu; ISBN sai thì không khớp sách nào
+			code, err := isbn.Normalize(filter.ISBN)
+			if err != nil {
+				code = filter.ISBN
+			}
+			db = db.Where("books.isbn = ?", code)
+		}
+		if filter.PublisherID > 0 {
+			db = db.Where("books.publisher_id = ?", filter.PublisherID)
+		}
+		if filter.CategoryID > 0 {
+			// Thể loại cha bao gồm sách của mọi thể loại con cháu
+			ids, err := category.SubtreeIDs(db.Session(&gorm.Session{NewDB: true}), filter.CategoryID)
+			if err != nil {
+				_ = db.AddError(err)
+				return db
+			}
+			db = db.Where("books.id IN (?)", db.Session(&gorm.Session{NewDB: true}).
+				Model(&models.BookCategory{}).Select("book_id").Where("category_id IN ?", ids))
+		}
+		if filter.Language != "" {
+			lang := filter.Language
+			if tag, err := language.Parse(lang); err == nil {
+				lang = tag.String()
+			}
+			db = db.Where("books.language = ?", lang)
+		}
+		if filter.Currency != "" {
+			db = db.Where("books.currency = ?", strings.ToUpper(filter.Currency))
+		}
+		if filter.MinPrice != nil {
+			db = db.Where("books.price >= ?", *filter.MinPrice)
+		}
+		if filter.MaxPrice != nil {
+			db = db.Where("books.price <= ?", *filter.MaxPrice)
+		}
+		if !filter.PublishedFrom.IsZero() {
+			db = db.Where("books.published_at >= ?", filter.PublishedFrom)
+		}
+		if !filter.PublishedTo.IsZero() {
+			db = db.Where("books.published_at <= ?", filter.PublishedTo)
+		}
+		return db
+	}
+}
+
+func (r *bookRepo) GetAllBooks(ctx context.Context, filter models.BookFilter) ([]models.Book, error) {
+	ctx, span := tracing.Start(ctx, "bookRepo.GetAllBooks")
+	defer span.End()
+
 	var books []models.Book
-	err := r.db.Find(&books).Error
-	return books, err
+	if err := r.db.WithContext(ctx).Scopes(FilterScope(filter), preloadIncludes(filter)).Find(&books).Error; err != nil {
+		return nil, err
+	}
+	return books, r.fillAvailability(ctx, books...)
+}
+
+// fillAvailability điền tồn kho đang giữ và còn bán được cho các sách vừa đọc
+func (r *bookRepo) fillAvailability(ctx context.Context, books ...models.Book) error {
+	if len(books) == 0 {
+		return nil
+	}
+	ptrs := make([]*models.Book, len(books))
+	for i := range books {
+		ptrs[i] = &books[i]
+	}
+	return reservation.FillAvailability(r.db.WithContext(ctx), ptrs)
+}
+
+// GetBooksByAuthor lấy sách có tác giả chính hoặc đồng tác giả là authorID
+func (r *bookRepo) GetBooksByAuthor(ctx context.Context, authorID int, filter models.BookFilter) ([]models.Book, error) {
+	ctx, span := tracing.Start(ctx, "bookRepo.GetBooksByAuthor")
+	defer span.End()
+
+	var count int64
+	if err := r.db.WithContext(ctx).Model(&models.Author{}).Where("id = ?", authorID).Count(&count).Error; err != nil {
+		return nil, err
+	}
+	if count == 0 {
+		return nil, fmt.Errorf("author with ID %d not found", authorID)
+	}
+
+	var books []models.Book
+	query := r.db.WithContext(ctx).
+		Where("books.author_id = ? OR books.id IN (?)", authorID,
+			r.db.Model(&models.BookAuthor{}).Select("book_id").Where("author_id = ?", authorID)).
+		Scopes(FilterScope(filter), preloadIncludes(filter))
+	if err := query.Order("books.id").Find(&books).Error; err != nil {
+		return nil, err
+	}
+	return books, r.fillAvailability(ctx, books...)
+}
+
+// GetBooksByCategory lấy sách thuộc thể loại categoryID hoặc các thể loại con cháu
+func (r *bookRepo) GetBooksByCategory(ctx context.Context, categoryID uint, filter models.BookFilter) ([]models.Book, error) {
+	ctx, span := tracing.Start(ctx, "bookRepo.GetBooksByCategory")
+	defer span.End()
+
+	var count int64
+	if err := r.db.WithContext(ctx).Model(&models.Category{}).Where("id = ?", categoryID).Count(&count).Error; err != nil {
+		return nil, err
+	}
+	if count == 0 {
+		return nil, fmt.Errorf("%w: category with ID %d not found", repositories.ErrNotFound, categoryID)
+	}
+
+	filter.CategoryID = categoryID
+	var books []models.Book
+	if err := r.db.WithContext(ctx).Scopes(FilterScope(filter), preloadIncludes(filter)).Order("books.id").Find(&books).Error; err != nil {
+		return nil, err
+	}
+	return books, r.fillAvailability(ctx, books...)
+}
+
+// CategoryFacets đếm số sách khớp filter trong từng thể loại; sách ở thể loại con
+// được tính cho cả các thể loại tổ tiên (mỗi sách chỉ tính một lần cho mỗi thể loại)
+func (r *bookRepo) CategoryFacets(ctx context.Context, filter models.BookFilter) ([]models.CategoryFacet, error) {
+	ctx, span := tracing.Start(ctx, "bookRepo.CategoryFacets")
+	defer span.End()
+
+	db := r.db.WithContext(ctx)
+
+	var categories []models.Category
+	if err := db.Select("id", "name", "parent_id").Order("id").Find(&categories).Error; err != nil {
+		return nil, err
+	}
+	parents := make(map[uint]*uint, len(categories))
+	for _, c := range categories {
+		parents[c.ID] = c.ParentID
+	}
+
+	var links []models.BookCategory
+	matching := db.Model(&models.Book{}).Select("books.id").Scopes(FilterScope(filter))
+	if err := db.Where("book_id IN (?)", matching).Find(&links).Error; err != nil {
+		return nil, err
+	}
+
+	books := make(map[uint]map[uint]bool)
+	for _, link := range links {
+		// Đi ngược lên gốc; seen chặn vòng lặp nếu dữ liệu cây bị lỗi
+		seen := map[uint]bool{}
+		for id := &link.CategoryID; id != nil && !seen[*id]; id = parents[*id] {
+			seen[*id] = true
+			if books[*id] == nil {
+				books[*id] = map[uint]bool{}
+			}
+			books[*id][link.BookID] = true
+		}
+	}
+
+	facets := make([]models.CategoryFacet, 0, len(books))
+	for _, c := range categories {
+		if n := len(books[c.ID]); n > 0 {
+			facets = append(facets, models.CategoryFacet{CategoryID: c.ID, Name: c.Name, ParentID: c.ParentID, Count: n})
+		}
+	}
+	return facets, nil
 }
 
 // Lấy sách theo ID
-func (r *bookRepo) GetByBookID(id int) (*models.Book, error) {
+func (r *bookRepo) GetByBookID(ctx context.Context, id int) (*models.Book, error) {
+	ctx, span := tracing.Start(ctx, "bookRepo.GetByBookID")
+	defer span.End()
+
 	var book models.Book
-	if err := r.db.First(&book, id).Error; err != nil {
+	if err := r.db.WithContext(ctx).First(&book, id).Error; err != nil {
 		if err == gorm.ErrRecordNotFound {
-			return nil, fmt.Errorf("book with ID %d not found", id)
+			return nil, fmt.Errorf("book with ID %d %w", id, repositories.ErrNotFound)
+		}
+		return nil, err
+	}
+	if err := reservation.FillAvailability(r.db.WithContext(ctx), []*models.Book{&book}); err != nil {
+		return nil, err
+	}
+	return &book, nil
+}
+
+// Lấy sách theo ID kèm tác giả
+func (r *bookRepo) GetByBookIDWithAuthors(ctx context.Context, id int) (*models.Book, error) {
+	ctx, span := tracing.Start(ctx, "bookRepo.GetByBookIDWithAuthors")
+	defer span.End()
+
+	var book models.Book
+	if err := r.db.WithContext(ctx).Scopes(preloadAuthors).Preload("Publisher").First(&book, id).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, fmt.Errorf("book with ID %d %w", id, repositories.ErrNotFound)
+		}
+		return nil, err
+	}
+	if err := reservation.FillAvailability(r.db.WithContext(ctx), []*models.Book{&book}); err != nil {
+		return nil, err
+	}
+	return &book, nil
+}
+
+// UpdateCover chỉ cập nhật ảnh bìa, không đụng tới các cột khác của sách
+func (r *bookRepo) UpdateCover(ctx context.Context, id int, key string, cover *models.BookCover) error {
+	ctx, span := tracing.Start(ctx, "bookRepo.UpdateCover")
+	defer span.End()
+
+	result := r.db.WithContext(ctx).Model(&models.Book{}).Where("id = ?", id).
+		Select("cover_key", "cover", "updated_at").
+		Updates(&models.Book{CoverKey: key, Cover: cover, UpdatedAt: time.Now()})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return fmt.Errorf("book with ID %d %w", id, repositories.ErrNotFound)
+	}
+	return nil
+}
+
+func (r *bookRepo) Restock(ctx context.Context, id int, quantity int) error {
+	ctx, span := tracing.Start(ctx, "bookRepo.Restock")
+	defer span.End()
+
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		result := tx.Model(&models.Book{}).Where("id = ?", id).
+			Update("stock", gorm.Expr("stock + ?", quantity))
+		if result.Error != nil {
+			return fmt.Errorf("failed to restock book: %w", result.Error)
+		}
+		if result.RowsAffected == 0 {
+			return fmt.Errorf("book with ID %d %w", id, repositories.ErrNotFound)
+		}
+		stock, err := currentStock(tx, id)
+		if err != nil {
+			return err
+		}
+		return outbox.Append(tx, events.StockChanged{BookID: uint(id), Delta: quantity, Stock: stock, Reason: events.StockReasonRestock})
+	})
+}
+
+// currentStock đọc tồn kho hiện tại của sách (0 nếu không có sách)
+func currentStock(db *gorm.DB, id int) (int, error) {
+	var stock int
+	if err := db.Model(&models.Book{}).Select("stock").Where("id = ?", id).Scan(&stock).Error; err != nil {
+		return 0, fmt.Errorf("failed to read book stock: %w", err)
+	}
+	return stock, nil
+}
+
+// Tìm sách theo tiêu đề và tác giả; trả về nil nếu không có
+func (r *bookRepo) GetByTitleAndAuthor(ctx context.Context, title string, authorID int) (*models.Book, error) {
+	ctx, span := tracing.Start(ctx, "bookRepo.GetByTitleAndAuthor")
+	defer span.End()
+
+	var book models.Book
+	err := r.db.WithContext(ctx).Where("title = ? AND author_id = ?", title, authorID).First(&book).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
 		}
 		return nil, err
 	}
@@ -41,13 +460,16 @@ func (r *bookRepo) GetByBookID(id int) (*models.Book, error) {
 }
 
 // Xoá sách theo ID
-func (r *bookRepo) DeleteById(id int) (*models.Book, error) {
-	book, err := r.GetByBookID(id)
+func (r *bookRepo) DeleteById(ctx context.Context, id int) (*models.Book, error) {
+	ctx, span := tracing.Start(ctx, "bookRepo.DeleteById")
+	defer span.End()
+
+	book, err := r.GetByBookID(ctx, id)
 	if err != nil {
 		return nil, err
 	}
 	// GORM sẽ tự xử lý foreign key nếu được định nghĩa trong DB
-	if err := r.db.Delete(&models.Book{}, id).Error; err != nil {
+	if err := r.db.WithContext(ctx).Delete(&models.Book{}, id).Error; err != nil {
 		return nil, fmt.Errorf("failed to delete book: %w", err)
 	}
 	return book, nil
@@ -56,27 +478,60 @@ func (r *bookRepo) DeleteById(id int) (*models.Book, error) {
 // 1. Kiểm tra author tồn tại
 // 2. UPDATE book
 
-func (r *bookRepo) UpdateById(book *models.Book) (*models.Book, error) {
-	// Ví dụ, dùng GORM Update:
-	var count int64
-	if err := r.db.Model(&models.Author{}).Where("id = ?", book.AuthorID).Count(&count).Error; err != nil {
-		return nil, err
-	}
-	if count == 0 {
-		return nil, errors.New("author not found")
-	}
+func (r *bookRepo) UpdateById(ctx context.Context, book *models.Book) (*models.Book, error) {
+	ctx, span := tracing.Start(ctx, "bookRepo.UpdateById")
+	defer span.End()
 
-	result := r.db.Model(&models.Book{}).Where("id = ?", book.ID).Updates(models.Book{
-		Title:     book.Title,
-		AuthorID:  book.AuthorID,
-		Stock:     book.Stock,
-		UpdatedAt: book.UpdatedAt,
+	err := r.withAssociations(ctx, book, func(db *gorm.DB) error {
+		// Ví dụ, dùng GORM Update:
+		var count int64
+		if err := db.Model(&models.Author{}).Where("id = ?", book.AuthorID).Count(&count).Error; err != nil {
+			return err
+		}
+		if count == 0 {
+			return errors.New("author not found")
+		}
+		if err := checkPublisher(db, book.PublisherID); err != nil {
+			return err
+		}
+		// Khoá dòng sách để so sánh tồn kho trước/sau khi cập nhật cho event StockChanged
+		before, err := currentStock(db.Clauses(clause.Locking{Strength: "UPDATE"}), int(book.ID))
+		if err != nil {
+			return err
+		}
+
+		// Select để ghi cả giá trị rỗng (stock 0, bỏ ISBN, mô tả...); ảnh bìa có UpdateCover riêng
+		result := db.Model(&models.Book{}).Where("id = ?", book.ID).
+			Select("title", "author_id", "stock", "isbn", "publisher_id", "price", "currency",
+				"language", "page_count", "published_at", "description", "updated_at").
+			Updates(models.Book{
+				Title:       book.Title,
+				AuthorID:    book.AuthorID,
+				Stock:       book.Stock,
+				ISBN:        book.ISBN,
+				PublisherID: book.PublisherID,
+				Price:       book.Price,
+				Currency:    book.Currency,
+				Language:    book.Language,
+				PageCount:   book.PageCount,
+				PublishedAt: book.PublishedAt,
+				Description: book.Description,
+				UpdatedAt:   book.UpdatedAt,
+			})
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return errors.New("no book updated")
+		}
+		after, err := currentStock(db, int(book.ID))
+		if err != nil || after == before {
+			return err
+		}
+		return outbox.Append(db, events.StockChanged{BookID: book.ID, Delta: after - before, Stock: after, Reason: events.StockReasonUpdate})
 	})
-	if result.Error != nil {
-		return nil, result.Error
-	}
-	if result.RowsAffected == 0 {
-		return nil, errors.New("no book updated")
+	if err != nil {
+		return nil, translateError(err, book)
 	}
 
 	return book, nil
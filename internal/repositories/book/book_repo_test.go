@@ -1,6 +1,7 @@
 package book_test
 
 import (
+	"context"
 	"errors"
 	"fmt"
 	"regexp"
@@ -8,11 +9,17 @@ import (
 	"time"
 
 	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/maithuc2003/Test_GIN_golang/internal/events"
+	"github.com/maithuc2003/Test_GIN_golang/internal/interfaces/repositories"
 	"github.com/maithuc2003/Test_GIN_golang/internal/models"
 	"github.com/maithuc2003/Test_GIN_golang/internal/repositories/book"
+	"github.com/shopspring/decimal"
 	"github.com/stretchr/testify/require"
 	"gorm.io/driver/postgres"
+	sqlitedriver "gorm.io/driver/sqlite"
 	"gorm.io/gorm"
+
+	_ "modernc.org/sqlite"
 )
 
 func newMockDB(t *testing.T) (*gorm.DB, sqlmock.Sqlmock) {
@@ -47,8 +54,10 @@ func TestCreateBook(t *testing.T) {
 			mockExpectFn: func(mock sqlmock.Sqlmock, book *models.Book) {
 				mock.ExpectBegin()
 				mock.ExpectQuery(regexp.QuoteMeta(
-					`INSERT INTO "books" ("title","stock","author_id","created_at","updated_at") VALUES ($1,$2,$3,$4,$5) RETURNING "id"`)).
-					WithArgs(book.Title, book.Stock, book.AuthorID, sqlmock.AnyArg(), sqlmock.AnyArg()).
+					`INSERT INTO "books" ("title","stock","author_id","isbn","publisher_id","price","currency","language","page_count","published_at","description","cover_key","cover","created_at","updated_at") VALUES ($1,$2,$3,$4,$5,$6,$7,$8,$9,$10,$11,$12,$13,$14,$15) RETURNING "id"`)).
+					WithArgs(book.Title, book.Stock, book.AuthorID, nil, nil, nil, "", "", 0, nil, "", "", nil, sqlmock.AnyArg(), sqlmock.AnyArg()).
+					WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(1))
+				mock.ExpectQuery(regexp.QuoteMeta(`INSERT INTO "outbox_events"`)).
 					WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(1))
 				mock.ExpectCommit()
 			},
@@ -64,8 +73,8 @@ func TestCreateBook(t *testing.T) {
 			mockExpectFn: func(mock sqlmock.Sqlmock, book *models.Book) {
 				mock.ExpectBegin()
 				mock.ExpectQuery(regexp.QuoteMeta(
-					`INSERT INTO "books" ("title","stock","author_id","created_at","updated_at") VALUES ($1,$2,$3,$4,$5) RETURNING "id"`)).
-					WithArgs(book.Title, book.Stock, book.AuthorID, sqlmock.AnyArg(), sqlmock.AnyArg()).
+					`INSERT INTO "books" ("title","stock","author_id","isbn","publisher_id","price","currency","language","page_count","published_at","description","cover_key","cover","created_at","updated_at") VALUES ($1,$2,$3,$4,$5,$6,$7,$8,$9,$10,$11,$12,$13,$14,$15) RETURNING "id"`)).
+					WithArgs(book.Title, book.Stock, book.AuthorID, nil, nil, nil, "", "", 0, nil, "", "", nil, sqlmock.AnyArg(), sqlmock.AnyArg()).
 					WillReturnError(gorm.ErrInvalidData)
 				mock.ExpectRollback()
 			},
@@ -82,7 +91,7 @@ func TestCreateBook(t *testing.T) {
 			tt.mockExpectFn(mock, tt.book)
 			repo := book.NewRepository(db)
 
-			err := repo.CreateBook(tt.book)
+			err := repo.CreateBook(context.Background(), tt.book)
 			if (err != nil) != tt.expectErr {
 				t.Errorf("CreateBook() error = %v, expectErr %v", err, tt.expectErr)
 			}
@@ -92,6 +101,12 @@ func TestCreateBook(t *testing.T) {
 	}
 }
 
+// expectReservedStock khớp truy vấn tồn kho đang giữ chạy sau mỗi lần đọc sách
+func expectReservedStock(mock sqlmock.Sqlmock) {
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT book_id, SUM(quantity) AS reserved FROM "stock_reservations"`)).
+		WillReturnRows(sqlmock.NewRows([]string{"book_id", "reserved"}))
+}
+
 func TestGetAllBooks(t *testing.T) {
 	t.Parallel()
 
@@ -110,6 +125,7 @@ func TestGetAllBooks(t *testing.T) {
 
 				mock.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM "books"`)).
 					WillReturnRows(rows)
+				expectReservedStock(mock)
 			},
 			wantCount: 2,
 			expectErr: false,
@@ -134,7 +150,7 @@ func TestGetAllBooks(t *testing.T) {
 			tt.mockExpectFn(mock)
 			repo := book.NewRepository(db)
 
-			books, err := repo.GetAllBooks()
+			books, err := repo.GetAllBooks(context.Background(), models.BookFilter{})
 			if (err != nil) != tt.expectErr {
 				t.Errorf("GetAllBooks() error = %v, expectErr %v", err, tt.expectErr)
 			}
@@ -173,6 +189,7 @@ func TestBookRepo_GetByBookID(t *testing.T) {
 					`SELECT * FROM "books" WHERE "books"."id" = $1 ORDER BY "books"."id" LIMIT $2`)).
 					WithArgs(id, 1).
 					WillReturnRows(rows)
+				expectReservedStock(mock)
 			},
 			wantTitle: "Golang Mastery",
 			expectErr: false,
@@ -212,7 +229,7 @@ func TestBookRepo_GetByBookID(t *testing.T) {
 			tt.mockExpectFn(mock, tt.args.id)
 
 			repo := book.NewRepository(db)
-			gotBook, err := repo.GetByBookID(tt.args.id)
+			gotBook, err := repo.GetByBookID(context.Background(), tt.args.id)
 
 			if tt.expectErr {
 				require.Error(t, err)
@@ -251,6 +268,7 @@ func TestBookRepo_DeleteById(t *testing.T) {
 					WithArgs(id, 1).
 					WillReturnRows(sqlmock.NewRows([]string{"id", "title", "stock", "author_id", "created_at", "updated_at"}).
 						AddRow(id, "Delete Me", 5, 1, now, now))
+				expectReservedStock(mock)
 
 				mock.ExpectBegin()
 				mock.ExpectExec(regexp.QuoteMeta(`DELETE FROM "books" WHERE "books"."id" = $1`)).
@@ -281,6 +299,7 @@ func TestBookRepo_DeleteById(t *testing.T) {
 					WithArgs(id, 1).
 					WillReturnRows(sqlmock.NewRows([]string{"id", "title", "stock", "author_id", "created_at", "updated_at"}).
 						AddRow(id, "Will Fail", 3, 1, now, now))
+				expectReservedStock(mock)
 
 				mock.ExpectBegin()
 				mock.ExpectExec(regexp.QuoteMeta(`DELETE FROM "books" WHERE "books"."id" = $1`)).
@@ -303,7 +322,7 @@ func TestBookRepo_DeleteById(t *testing.T) {
 
 			tt.mockExpectFn(mock, tt.args.id)
 
-			deletedBook, err := repo.DeleteById(tt.args.id)
+			deletedBook, err := repo.DeleteById(context.Background(), tt.args.id)
 
 			if tt.expectErr {
 				require.Error(t, err)
@@ -327,6 +346,10 @@ func TestBookRepo_UpdateById(t *testing.T) {
 	require.NoError(t, err)
 	repo := book.NewRepository(gormDB)
 	now := time.Now()
+	selectStock := regexp.QuoteMeta(`SELECT "stock" FROM "books" WHERE id = $1`)
+	// Mọi cột sửa được đều có trong SET, kể cả giá trị rỗng
+	updateBook := regexp.QuoteMeta(`UPDATE "books" SET "title"=$1,"stock"=$2,"author_id"=$3,"isbn"=$4,"publisher_id"=$5,` +
+		`"price"=$6,"currency"=$7,"language"=$8,"page_count"=$9,"published_at"=$10,"description"=$11,"updated_at"=$12 WHERE id = $13`)
 	tests := []struct {
 		name         string
 		book         *models.Book
@@ -338,17 +361,25 @@ func TestBookRepo_UpdateById(t *testing.T) {
 			name: "success update",
 			book: &models.Book{ID: 1, Title: "Updated Title", AuthorID: 1, Stock: 10, UpdatedAt: time.Now()},
 			mockExpect: func() {
+				mock.ExpectBegin()
 				mock.ExpectQuery(`SELECT count\(\*\) FROM "authors" WHERE id = \$1`).
 					WithArgs(1).
 					WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(1))
+				mock.ExpectQuery(selectStock + ` FOR UPDATE`).
+					WithArgs(1).
+					WillReturnRows(sqlmock.NewRows([]string{"stock"}).AddRow(4))
 
-				mock.ExpectBegin() // 👈 THÊM DÒNG NÀY
-
-				mock.ExpectExec(`UPDATE "books" SET "title"=\$1,"stock"=\$2,"author_id"=\$3,"updated_at"=\$4 WHERE id = \$5`).
-					WithArgs("Updated Title", 10, 1, sqlmock.AnyArg(), 1).
+				mock.ExpectExec(updateBook).
+					WithArgs("Updated Title", 10, 1, nil, nil, nil, "", "", 0, nil, "", sqlmock.AnyArg(), 1).
 					WillReturnResult(sqlmock.NewResult(0, 1))
 
-				mock.ExpectCommit() // 👈 THÊM DÒNG NÀY
+				// Tồn kho đổi từ 4 lên 10 nên ghi event StockChanged
+				mock.ExpectQuery(selectStock).
+					WithArgs(1).
+					WillReturnRows(sqlmock.NewRows([]string{"stock"}).AddRow(10))
+				mock.ExpectQuery(regexp.QuoteMeta(`INSERT INTO "outbox_events"`)).
+					WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(1))
+				mock.ExpectCommit()
 			},
 			expectedErr:  "",
 			expectResult: true,
@@ -357,9 +388,11 @@ func TestBookRepo_UpdateById(t *testing.T) {
 			name: "author not found",
 			book: &models.Book{ID: 2, Title: "New Title", AuthorID: 99, Stock: 10, UpdatedAt: now},
 			mockExpect: func() {
+				mock.ExpectBegin()
 				mock.ExpectQuery(`SELECT count\(\*\) FROM "authors" WHERE id = \$1`).
 					WithArgs(99).
 					WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(0))
+				mock.ExpectRollback()
 			},
 			expectedErr:  "author not found",
 			expectResult: false,
@@ -368,17 +401,19 @@ func TestBookRepo_UpdateById(t *testing.T) {
 			name: "book not found",
 			book: &models.Book{ID: 999, Title: "Title", AuthorID: 1, Stock: 5, UpdatedAt: now},
 			mockExpect: func() {
+				mock.ExpectBegin()
 				mock.ExpectQuery(`SELECT count\(\*\) FROM "authors" WHERE id = \$1`).
 					WithArgs(1).
 					WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(1))
+				mock.ExpectQuery(selectStock + ` FOR UPDATE`).
+					WithArgs(999).
+					WillReturnRows(sqlmock.NewRows([]string{"stock"}))
 
-				mock.ExpectBegin()
-
-				mock.ExpectExec(`UPDATE "books" SET "title"=\$1,"stock"=\$2,"author_id"=\$3,"updated_at"=\$4 WHERE id = \$5`).
-					WithArgs("Title", 5, 1, sqlmock.AnyArg(), 999).
+				mock.ExpectExec(updateBook).
+					WithArgs("Title", 5, 1, nil, nil, nil, "", "", 0, nil, "", sqlmock.AnyArg(), 999).
 					WillReturnResult(sqlmock.NewResult(0, 0))
 
-				mock.ExpectCommit()
+				mock.ExpectRollback()
 			},
 			expectedErr:  "no book updated",
 			expectResult: false,
@@ -387,14 +422,16 @@ func TestBookRepo_UpdateById(t *testing.T) {
 			name: "update error",
 			book: &models.Book{ID: 3, Title: "Error Title", AuthorID: 1, Stock: 5, UpdatedAt: now},
 			mockExpect: func() {
+				mock.ExpectBegin()
 				mock.ExpectQuery(`SELECT count\(\*\) FROM "authors" WHERE id = \$1`).
 					WithArgs(1).
 					WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(1))
+				mock.ExpectQuery(selectStock + ` FOR UPDATE`).
+					WithArgs(3).
+					WillReturnRows(sqlmock.NewRows([]string{"stock"}).AddRow(5))
 
-				mock.ExpectBegin()
-
-				mock.ExpectExec(`UPDATE "books" SET "title"=\$1,"stock"=\$2,"author_id"=\$3,"updated_at"=\$4 WHERE id = \$5`).
-					WithArgs("Error Title", 5, 1, sqlmock.AnyArg(), 3).
+				mock.ExpectExec(updateBook).
+					WithArgs("Error Title", 5, 1, nil, nil, nil, "", "", 0, nil, "", sqlmock.AnyArg(), 3).
 					WillReturnError(errors.New("failed to update book"))
 
 				mock.ExpectRollback()
@@ -407,7 +444,7 @@ func TestBookRepo_UpdateById(t *testing.T) {
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			tt.mockExpect()
-			result, err := repo.UpdateById(tt.book)
+			result, err := repo.UpdateById(context.Background(), tt.book)
 
 			if tt.expectedErr != "" {
 				require.Error(t, err)
@@ -427,3 +464,332 @@ func TestBookRepo_UpdateById(t *testing.T) {
 		})
 	}
 }
+
+func newSQLiteDB(t *testing.T) *gorm.DB {
+	t.Helper()
+	dsn := fmt.Sprintf("file:bookdb_%d?mode=memory&cache=shared", time.Now().UnixNano())
+	gdb, err := gorm.Open(sqlitedriver.New(sqlitedriver.Config{
+		DSN:        dsn,
+		DriverName: "sqlite",
+	}), &gorm.Config{})
+	require.NoError(t, err)
+	require.NoError(t, gdb.AutoMigrate(&models.Author{}, &models.Publisher{}, &models.Category{}, &models.BookCategory{}, &models.Book{}, &models.StockReservation{}, &models.OutboxEvent{}))
+	return gdb
+}
+
+func TestBookRepo_CoAuthors(t *testing.T) {
+	db := newSQLiteDB(t)
+	repo := book.NewRepository(db)
+	ctx := context.Background()
+
+	a1 := models.Author{Name: "Neil Gaiman"}
+	a2 := models.Author{Name: "Terry Pratchett"}
+	a3 := models.Author{Name: "Frank Herbert"}
+	require.NoError(t, db.Create(&a1).Error)
+	require.NoError(t, db.Create(&a2).Error)
+	require.NoError(t, db.Create(&a3).Error)
+
+	goodOmens := &models.Book{Title: "Good Omens", Stock: 3, AuthorID: a1.ID, AuthorIDs: []int{a2.ID}}
+	require.NoError(t, repo.CreateBook(ctx, goodOmens))
+	require.Len(t, goodOmens.Authors, 2)
+
+	dune := &models.Book{Title: "Dune", Stock: 1, AuthorID: a3.ID}
+	require.NoError(t, repo.CreateBook(ctx, dune))
+
+	t.Run("unknown co-author rolls back", func(t *testing.T) {
+		err := repo.CreateBook(ctx, &models.Book{Title: "Ghost", AuthorID: a1.ID, AuthorIDs: []int{999}})
+		require.ErrorContains(t, err, "author not found")
+		var count int64
+		require.NoError(t, db.Model(&models.Book{}).Where("title = ?", "Ghost").Count(&count).Error)
+		require.Zero(t, count)
+	})
+
+	t.Run("books by co-author", func(t *testing.T) {
+		books, err := repo.GetBooksByAuthor(ctx, a2.ID, models.BookFilter{})
+		require.NoError(t, err)
+		require.Len(t, books, 1)
+		require.Equal(t, "Good Omens", books[0].Title)
+		require.Nil(t, books[0].Author)
+	})
+
+	t.Run("books by primary author with include", func(t *testing.T) {
+		books, err := repo.GetBooksByAuthor(ctx, a3.ID, models.BookFilter{Include: "author"})
+		require.NoError(t, err)
+		require.Len(t, books, 1)
+		require.NotNil(t, books[0].Author)
+		require.Equal(t, "Frank Herbert", books[0].Author.Name)
+	})
+
+	t.Run("unknown author", func(t *testing.T) {
+		_, err := repo.GetBooksByAuthor(ctx, 999, models.BookFilter{})
+		require.ErrorContains(t, err, "not found")
+	})
+
+	t.Run("get by id with authors", func(t *testing.T) {
+		got, err := repo.GetByBookIDWithAuthors(ctx, int(goodOmens.ID))
+		require.NoError(t, err)
+		require.Equal(t, "Neil Gaiman", got.Author.Name)
+		require.Len(t, got.Authors, 2)
+	})
+
+	t.Run("update replaces co-authors", func(t *testing.T) {
+		goodOmens.AuthorIDs = []int{a3.ID}
+		goodOmens.UpdatedAt = time.Now()
+		_, err := repo.UpdateById(ctx, goodOmens)
+		require.NoError(t, err)
+
+		books, err := repo.GetBooksByAuthor(ctx, a2.ID, models.BookFilter{})
+		require.NoError(t, err)
+		require.Empty(t, books)
+
+		all, err := repo.GetAllBooks(ctx, models.BookFilter{Include: "author", AuthorID: a1.ID})
+		require.NoError(t, err)
+		require.Len(t, all, 1)
+		require.Len(t, all[0].Authors, 2)
+	})
+}
+
+func TestBookRepo_Metadata(t *testing.T) {
+	db := newSQLiteDB(t)
+	repo := book.NewRepository(db)
+	ctx := context.Background()
+
+	author := models.Author{Name: "Nguyen Nhat Anh"}
+	require.NoError(t, db.Create(&author).Error)
+	kimDong := models.Publisher{Name: "NXB Kim Dong"}
+	require.NoError(t, db.Create(&kimDong).Error)
+	tre := models.Publisher{Name: "NXB Tre"}
+	require.NoError(t, db.Create(&tre).Error)
+
+	isbn1, isbn2 := "9780306406157", "9780804429573"
+	price := func(s string) *decimal.Decimal { d := decimal.RequireFromString(s); return &d }
+	date := func(s string) *time.Time { d, _ := time.Parse("2006-01-02", s); return &d }
+	books := []*models.Book{
+		{Title: "Mat Biec", AuthorID: author.ID, ISBN: &isbn1, PublisherID: &tre.ID, Price: price("89000"), Currency: "VND", Language: "vi", PublishedAt: date("1990-01-01")},
+		{Title: "Kinh Van Hoa", AuthorID: author.ID, ISBN: &isbn2, PublisherID: &kimDong.ID, Price: price("45000"), Currency: "VND", Language: "vi", PublishedAt: date("2005-06-01")},
+		{Title: "Translated", AuthorID: author.ID, Price: price("12.50"), Currency: "USD", Language: "en"},
+	}
+	for _, b := range books {
+		require.NoError(t, repo.CreateBook(ctx, b))
+	}
+
+	t.Run("duplicate isbn conflicts", func(t *testing.T) {
+		dup := isbn1
+		err := repo.CreateBook(ctx, &models.Book{Title: "Copy", AuthorID: author.ID, ISBN: &dup})
+		require.ErrorIs(t, err, repositories.ErrConflict)
+	})
+
+	t.Run("unknown publisher", func(t *testing.T) {
+		missing := uint(999)
+		err := repo.CreateBook(ctx, &models.Book{Title: "Nowhere", AuthorID: author.ID, PublisherID: &missing})
+		require.ErrorContains(t, err, "publisher not found")
+	})
+
+	minPrice, maxPrice := 40000.0, 50000.0
+	tests := []struct {
+		name      string
+		filter    models.BookFilter
+		wantTitle []string
+	}{
+		{name: "isbn-10 matches stored isbn-13", filter: models.BookFilter{ISBN: "0-306-40615-2"}, wantTitle: []string{"Mat Biec"}},
+		{name: "publisher", filter: models.BookFilter{PublisherID: kimDong.ID}, wantTitle: []string{"Kinh Van Hoa"}},
+		{name: "language", filter: models.BookFilter{Language: "EN"}, wantTitle: []string{"Translated"}},
+		{name: "currency and price range", filter: models.BookFilter{Currency: "vnd", MinPrice: &minPrice, MaxPrice: &maxPrice}, wantTitle: []string{"Kinh Van Hoa"}},
+		{name: "published range", filter: models.BookFilter{PublishedFrom: *date("2000-01-01"), PublishedTo: *date("2010-12-31")}, wantTitle: []string{"Kinh Van Hoa"}},
+		{name: "invalid isbn matches nothing", filter: models.BookFilter{ISBN: "123"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := repo.GetAllBooks(ctx, tt.filter)
+			require.NoError(t, err)
+			titles := []string{}
+			for _, b := range got {
+				titles = append(titles, b.Title)
+			}
+			if tt.wantTitle == nil {
+				tt.wantTitle = []string{}
+			}
+			require.Equal(t, tt.wantTitle, titles)
+		})
+	}
+
+	t.Run("include publisher", func(t *testing.T) {
+		got, err := repo.GetAllBooks(ctx, models.BookFilter{Include: "publisher", PublisherID: tre.ID})
+		require.NoError(t, err)
+		require.Len(t, got, 1)
+		require.Equal(t, "NXB Tre", got[0].Publisher.Name)
+		require.True(t, price("89000").Equal(*got[0].Price))
+	})
+
+	t.Run("update clears metadata", func(t *testing.T) {
+		cleared := &models.Book{ID: books[0].ID, Title: "Mat Biec", AuthorID: author.ID, Stock: 0, UpdatedAt: time.Now()}
+		_, err := repo.UpdateById(ctx, cleared)
+		require.NoError(t, err)
+
+		got, err := repo.GetByBookID(ctx, int(books[0].ID))
+		require.NoError(t, err)
+		require.Nil(t, got.ISBN)
+		require.Nil(t, got.PublisherID)
+		require.Nil(t, got.Price)
+		require.Nil(t, got.PublishedAt)
+		require.Empty(t, got.Currency)
+		require.Empty(t, got.Language)
+		require.Empty(t, got.Description)
+		require.Zero(t, got.PageCount)
+	})
+}
+
+func TestBookRepo_Categories(t *testing.T) {
+	db := newSQLiteDB(t)
+	repo := book.NewRepository(db)
+	ctx := context.Background()
+
+	author := models.Author{Name: "J.R.R. Tolkien"}
+	require.NoError(t, db.Create(&author).Error)
+	fiction := models.Category{Name: "Fiction"}
+	require.NoError(t, db.Create(&fiction).Error)
+	fantasy := models.Category{Name: "Fantasy", ParentID: &fiction.ID}
+	require.NoError(t, db.Create(&fantasy).Error)
+	science := models.Category{Name: "Science"}
+	require.NoError(t, db.Create(&science).Error)
+
+	hobbit := models.Book{Title: "The Hobbit", AuthorID: author.ID, CategoryIDs: []uint{fantasy.ID, fantasy.ID}}
+	require.NoError(t, repo.CreateBook(ctx, &hobbit))
+	require.Len(t, hobbit.Categories, 1)
+	// Sách thuộc cả thể loại cha lẫn con chỉ được đếm một lần ở thể loại cha
+	silmarillion := models.Book{Title: "The Silmarillion", AuthorID: author.ID, Stock: 3, CategoryIDs: []uint{fiction.ID, fantasy.ID}}
+	require.NoError(t, repo.CreateBook(ctx, &silmarillion))
+	cosmos := models.Book{Title: "Cosmos", AuthorID: author.ID, Stock: 1, CategoryIDs: []uint{science.ID}}
+	require.NoError(t, repo.CreateBook(ctx, &cosmos))
+
+	t.Run("unknown category", func(t *testing.T) {
+		err := repo.CreateBook(ctx, &models.Book{Title: "Ghost", AuthorID: author.ID, CategoryIDs: []uint{999}})
+		require.EqualError(t, err, "category not found")
+		var count int64
+		require.NoError(t, db.Model(&models.Book{}).Where("title = ?", "Ghost").Count(&count).Error)
+		require.Zero(t, count, "book insert must roll back")
+	})
+
+	t.Run("parent includes descendants", func(t *testing.T) {
+		books, err := repo.GetBooksByCategory(ctx, fiction.ID, models.BookFilter{Include: "categories"})
+		require.NoError(t, err)
+		require.Len(t, books, 2)
+		require.Equal(t, "The Hobbit", books[0].Title)
+		require.NotEmpty(t, books[0].Categories)
+	})
+
+	t.Run("combined with other filters", func(t *testing.T) {
+		inStock := true
+		books, err := repo.GetAllBooks(ctx, models.BookFilter{CategoryID: fiction.ID, InStock: &inStock})
+		require.NoError(t, err)
+		require.Len(t, books, 1)
+		require.Equal(t, "The Silmarillion", books[0].Title)
+	})
+
+	t.Run("unknown category not found", func(t *testing.T) {
+		_, err := repo.GetBooksByCategory(ctx, 999, models.BookFilter{})
+		require.ErrorIs(t, err, repositories.ErrNotFound)
+	})
+
+	t.Run("facets roll up to ancestors", func(t *testing.T) {
+		facets, err := repo.CategoryFacets(ctx, models.BookFilter{})
+		require.NoError(t, err)
+		counts := map[string]int{}
+		for _, f := range facets {
+			counts[f.Name] = f.Count
+		}
+		require.Equal(t, map[string]int{"Fiction": 2, "Fantasy": 2, "Science": 1}, counts)
+
+		facets, err = repo.CategoryFacets(ctx, models.BookFilter{Title: "Cosmos"})
+		require.NoError(t, err)
+		require.Len(t, facets, 1)
+		require.Equal(t, science.ID, facets[0].CategoryID)
+	})
+
+	t.Run("update replaces categories", func(t *testing.T) {
+		cosmos.CategoryIDs = []uint{}
+		_, err := repo.UpdateById(ctx, &cosmos)
+		require.NoError(t, err)
+		books, err := repo.GetBooksByCategory(ctx, science.ID, models.BookFilter{})
+		require.NoError(t, err)
+		require.Empty(t, books)
+	})
+}
+
+func TestBookRepo_UpdateCover(t *testing.T) {
+	db := newSQLiteDB(t)
+	repo := book.NewRepository(db)
+	ctx := context.Background()
+
+	author := models.Author{Name: "Frank Herbert"}
+	require.NoError(t, db.Create(&author).Error)
+	dune := models.Book{Title: "Dune", AuthorID: author.ID, Stock: 2}
+	require.NoError(t, repo.CreateBook(ctx, &dune))
+
+	cover := &models.BookCover{URL: "/uploads/covers/1/a/original.png", MediumURL: "/uploads/covers/1/a/medium.jpg", ThumbnailURL: "/uploads/covers/1/a/thumb.jpg"}
+	require.NoError(t, repo.UpdateCover(ctx, int(dune.ID), "covers/1/a", cover))
+
+	got, err := repo.GetByBookID(ctx, int(dune.ID))
+	require.NoError(t, err)
+	require.Equal(t, "covers/1/a", got.CoverKey)
+	require.Equal(t, cover, got.Cover)
+	require.Equal(t, 2, got.Stock, "cover update must not touch other columns")
+
+	err = repo.UpdateCover(ctx, 999, "covers/999/a", cover)
+	require.ErrorIs(t, err, repositories.ErrNotFound)
+	_, err = repo.GetByBookID(ctx, 999)
+	require.ErrorIs(t, err, repositories.ErrNotFound)
+}
+
+func TestBookRepo_RestockAndAvailability(t *testing.T) {
+	db := newSQLiteDB(t)
+	repo := book.NewRepository(db)
+	ctx := context.Background()
+
+	author := models.Author{Name: "Frank Herbert"}
+	require.NoError(t, db.Create(&author).Error)
+	dune := models.Book{Title: "Dune", AuthorID: author.ID, Stock: 2}
+	require.NoError(t, repo.CreateBook(ctx, &dune))
+	require.NoError(t, db.Create(&models.StockReservation{OrderID: 1, BookID: dune.ID, Quantity: 1, ExpiresAt: time.Now().Add(time.Minute)}).Error)
+
+	require.NoError(t, repo.Restock(ctx, int(dune.ID), 3))
+	require.ErrorIs(t, repo.Restock(ctx, 999, 1), repositories.ErrNotFound)
+
+	got, err := repo.GetByBookID(ctx, int(dune.ID))
+	require.NoError(t, err)
+	require.Equal(t, 5, got.Stock)
+	require.Equal(t, 1, got.ReservedStock)
+	require.Equal(t, 4, got.AvailableStock)
+
+	books, err := repo.GetAllBooks(ctx, models.BookFilter{})
+	require.NoError(t, err)
+	require.Len(t, books, 1)
+	require.Equal(t, 4, books[0].AvailableStock)
+}
+
+func TestBookRepo_OutboxEvents(t *testing.T) {
+	db := newSQLiteDB(t)
+	repo := book.NewRepository(db)
+	ctx := context.Background()
+
+	author := models.Author{Name: "Frank Herbert"}
+	require.NoError(t, db.Create(&author).Error)
+	dune := models.Book{Title: "Dune", AuthorID: author.ID, Stock: 2}
+	require.NoError(t, repo.CreateBook(ctx, &dune))
+	require.NoError(t, repo.Restock(ctx, int(dune.ID), 3))
+	dune.Stock = 4
+	_, err := repo.UpdateById(ctx, &dune)
+	require.NoError(t, err)
+	// Đổi tiêu đề không đổi tồn kho thì không có StockChanged
+	dune.Title = "Dune (Deluxe)"
+	_, err = repo.UpdateById(ctx, &dune)
+	require.NoError(t, err)
+
+	var records []models.OutboxEvent
+	require.NoError(t, db.Order("id").Find(&records).Error)
+	require.Len(t, records, 3)
+	require.Equal(t, events.TypeBookCreated, records[0].Type)
+	require.JSONEq(t, fmt.Sprintf(`{"book_id":%d,"title":"Dune","author_id":%d,"stock":2}`, dune.ID, author.ID), records[0].Payload)
+	require.JSONEq(t, fmt.Sprintf(`{"book_id":%d,"delta":3,"stock":5,"reason":"restock"}`, dune.ID), records[1].Payload)
+	require.JSONEq(t, fmt.Sprintf(`{"book_id":%d,"delta":-1,"stock":4,"reason":"update"}`, dune.ID), records[2].Payload)
+}
@@ -0,0 +1,153 @@
+package cart
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/maithuc2003/Test_GIN_golang/internal/interfaces/repositories"
+	"github.com/maithuc2003/Test_GIN_golang/internal/models"
+	"github.com/maithuc2003/Test_GIN_golang/internal/repositories/reservation"
+	"github.com/maithuc2003/Test_GIN_golang/internal/tracing"
+	"gorm.io/gorm"
+)
+
+type cartRepo struct {
+	db *gorm.DB
+}
+
+func NewCartRepo(db *gorm.DB) repositories.CartRepository {
+	return &cartRepo{db: db}
+}
+
+func (r *cartRepo) GetCart(ctx context.Context, userID uint) (*models.Cart, error) {
+	ctx, span := tracing.Start(ctx, "cartRepo.GetCart")
+	defer span.End()
+
+	var cart models.Cart
+	err := r.db.WithContext(ctx).
+		Preload("Items", func(db *gorm.DB) *gorm.DB { return db.Order("cart_items.id") }).
+		Preload("Items.Book").
+		Where("user_id = ?", userID).First(&cart).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, fmt.Errorf("%w: cart for user %d not found", repositories.ErrNotFound, userID)
+		}
+		return nil, err
+	}
+
+	books := make([]*models.Book, 0, len(cart.Items))
+	for _, item := range cart.Items {
+		if item.Book != nil {
+			books = append(books, item.Book)
+		}
+	}
+	if err := reservation.FillAvailability(r.db.WithContext(ctx), books); err != nil {
+		return nil, err
+	}
+	return &cart, nil
+}
+
+func (r *cartRepo) SaveItem(ctx context.Context, userID, bookID uint, quantity int, expiresAt time.Time) error {
+	ctx, span := tracing.Start(ctx, "cartRepo.SaveItem")
+	defer span.End()
+
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		cart, err := touchCart(tx, userID, expiresAt, true)
+		if err != nil {
+			return err
+		}
+
+		var item models.CartItem
+		err = tx.Where("cart_id = ? AND book_id = ?", cart.ID, bookID).First(&item).Error
+		switch {
+		case errors.Is(err, gorm.ErrRecordNotFound):
+			item = models.CartItem{CartID: cart.ID, BookID: bookID, Quantity: quantity}
+			return tx.Create(&item).Error
+		case err != nil:
+			return err
+		}
+		return tx.Model(&item).Update("quantity", quantity).Error
+	})
+}
+
+func (r *cartRepo) RemoveItem(ctx context.Context, userID, bookID uint, expiresAt time.Time) error {
+	ctx, span := tracing.Start(ctx, "cartRepo.RemoveItem")
+	defer span.End()
+
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		cart, err := touchCart(tx, userID, expiresAt, false)
+		if err != nil {
+			return err
+		}
+		result := tx.Where("cart_id = ? AND book_id = ?", cart.ID, bookID).Delete(&models.CartItem{})
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return fmt.Errorf("%w: book %d is not in the cart", repositories.ErrNotFound, bookID)
+		}
+		return nil
+	})
+}
+
+func (r *cartRepo) Clear(ctx context.Context, userID uint) error {
+	ctx, span := tracing.Start(ctx, "cartRepo.Clear")
+	defer span.End()
+
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		carts := tx.Model(&models.Cart{}).Select("id").Where("user_id = ?", userID)
+		if err := tx.Where("cart_id IN (?)", carts).Delete(&models.CartItem{}).Error; err != nil {
+			return err
+		}
+		return tx.Where("user_id = ?", userID).Delete(&models.Cart{}).Error
+	})
+}
+
+func (r *cartRepo) DeleteExpired(ctx context.Context, now time.Time) (int64, error) {
+	ctx, span := tracing.Start(ctx, "cartRepo.DeleteExpired")
+	defer span.End()
+
+	var deleted int64
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		expired := tx.Model(&models.Cart{}).Select("id").Where("expires_at < ?", now)
+		if err := tx.Where("cart_id IN (?)", expired).Delete(&models.CartItem{}).Error; err != nil {
+			return err
+		}
+		result := tx.Where("expires_at < ?", now).Delete(&models.Cart{})
+		deleted = result.RowsAffected
+		return result.Error
+	})
+	return deleted, err
+}
+
+// touchCart lấy giỏ của user và gia hạn tới expiresAt. Giỏ đã hết hạn được làm rỗng
+// trước khi dùng lại; create=false thì trả ErrNotFound khi user chưa có giỏ.
+func touchCart(tx *gorm.DB, userID uint, expiresAt time.Time, create bool) (*models.Cart, error) {
+	var cart models.Cart
+	err := tx.Where("user_id = ?", userID).First(&cart).Error
+	switch {
+	case errors.Is(err, gorm.ErrRecordNotFound):
+		if !create {
+			return nil, fmt.Errorf("%w: cart for user %d not found", repositories.ErrNotFound, userID)
+		}
+		cart = models.Cart{UserID: userID, ExpiresAt: expiresAt}
+		if err := tx.Create(&cart).Error; err != nil {
+			return nil, err
+		}
+		return &cart, nil
+	case err != nil:
+		return nil, err
+	}
+
+	if cart.ExpiresAt.Before(time.Now()) {
+		if err := tx.Where("cart_id = ?", cart.ID).Delete(&models.CartItem{}).Error; err != nil {
+			return nil, err
+		}
+	}
+	if err := tx.Model(&cart).Update("expires_at", expiresAt).Error; err != nil {
+		return nil, err
+	}
+	return &cart, nil
+}
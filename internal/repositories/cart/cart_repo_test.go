@@ -0,0 +1,111 @@
+package cart_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+
+	"github.com/maithuc2003/Test_GIN_golang/internal/interfaces/repositories"
+	"github.com/maithuc2003/Test_GIN_golang/internal/models"
+	"github.com/maithuc2003/Test_GIN_golang/internal/repositories/cart"
+	sqlitedriver "gorm.io/driver/sqlite"
+
+	_ "modernc.org/sqlite"
+)
+
+func setupTestDB(t *testing.T) *gorm.DB {
+	dsn := fmt.Sprintf("file:testdb_%d?mode=memory&cache=shared", time.Now().UnixNano())
+
+	db, err := gorm.Open(sqlitedriver.New(sqlitedriver.Config{
+		DSN:        dsn,
+		DriverName: "sqlite",
+	}), &gorm.Config{})
+
+	require.NoError(t, err)
+	require.NoError(t, db.AutoMigrate(&models.Author{}, &models.Book{}, &models.Cart{}, &models.CartItem{}, &models.StockReservation{}))
+
+	return db
+}
+
+func TestCartRepo_Items(t *testing.T) {
+	db := setupTestDB(t)
+	repo := cart.NewCartRepo(db)
+	ctx := context.Background()
+
+	dune := models.Book{Title: "Dune", AuthorID: 1, Stock: 5}
+	require.NoError(t, db.Create(&dune).Error)
+	emma := models.Book{Title: "Emma", AuthorID: 1, Stock: 1}
+	require.NoError(t, db.Create(&emma).Error)
+	expires := time.Now().Add(time.Hour)
+
+	_, err := repo.GetCart(ctx, 1)
+	require.True(t, errors.Is(err, repositories.ErrNotFound))
+
+	require.NoError(t, repo.SaveItem(ctx, 1, dune.ID, 2, expires))
+	require.NoError(t, repo.SaveItem(ctx, 1, emma.ID, 1, expires))
+	require.NoError(t, repo.SaveItem(ctx, 1, dune.ID, 3, expires), "saving an existing line overwrites its quantity")
+
+	got, err := repo.GetCart(ctx, 1)
+	require.NoError(t, err)
+	require.Len(t, got.Items, 2)
+	require.Equal(t, 3, got.Items[0].Quantity)
+	require.Equal(t, "Dune", got.Items[0].Book.Title)
+
+	tests := []struct {
+		name    string
+		userID  uint
+		bookID  uint
+		wantErr error
+	}{
+		{name: "remove line", userID: 1, bookID: emma.ID},
+		{name: "line not in cart", userID: 1, bookID: emma.ID, wantErr: repositories.ErrNotFound},
+		{name: "user without cart", userID: 2, bookID: dune.ID, wantErr: repositories.ErrNotFound},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := repo.RemoveItem(ctx, tt.userID, tt.bookID, expires)
+			if tt.wantErr != nil {
+				require.True(t, errors.Is(err, tt.wantErr), "got %v", err)
+				return
+			}
+			require.NoError(t, err)
+		})
+	}
+
+	require.NoError(t, repo.Clear(ctx, 1))
+	_, err = repo.GetCart(ctx, 1)
+	require.True(t, errors.Is(err, repositories.ErrNotFound))
+	var items int64
+	require.NoError(t, db.Model(&models.CartItem{}).Count(&items).Error)
+	require.Zero(t, items)
+}
+
+func TestCartRepo_Expiry(t *testing.T) {
+	db := setupTestDB(t)
+	repo := cart.NewCartRepo(db)
+	ctx := context.Background()
+
+	require.NoError(t, repo.SaveItem(ctx, 1, 10, 1, time.Now().Add(-time.Minute)))
+	require.NoError(t, repo.SaveItem(ctx, 2, 10, 1, time.Now().Add(time.Hour)))
+
+	// Thêm vào giỏ đã hết hạn thì dùng lại giỏ nhưng bỏ các dòng cũ
+	require.NoError(t, repo.SaveItem(ctx, 1, 11, 2, time.Now().Add(-time.Minute)))
+	got, err := repo.GetCart(ctx, 1)
+	require.NoError(t, err)
+	require.Len(t, got.Items, 1)
+	require.Equal(t, uint(11), got.Items[0].BookID)
+
+	deleted, err := repo.DeleteExpired(ctx, time.Now())
+	require.NoError(t, err)
+	require.Equal(t, int64(1), deleted)
+
+	_, err = repo.GetCart(ctx, 1)
+	require.True(t, errors.Is(err, repositories.ErrNotFound))
+	_, err = repo.GetCart(ctx, 2)
+	require.NoError(t, err)
+}
@@ -0,0 +1,241 @@
+package category
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/maithuc2003/Test_GIN_golang/internal/interfaces/repositories"
+	"github.com/maithuc2003/Test_GIN_golang/internal/models"
+	"github.com/maithuc2003/Test_GIN_golang/internal/repositories/dberr"
+	"github.com/maithuc2003/Test_GIN_golang/internal/tracing"
+
+	"gorm.io/gorm"
+)
+
+type categoryRepo struct {
+	db *gorm.DB
+}
+
+func NewCategoryRepo(db *gorm.DB) repositories.CategoryRepository {
+	return &categoryRepo{db: db}
+}
+
+func (r *categoryRepo) GetAllCategories(ctx context.Context) ([]models.Category, error) {
+	ctx, span := tracing.Start(ctx, "categoryRepo.GetAllCategories")
+	defer span.End()
+
+	var categories []models.Category
+	if err := r.db.WithContext(ctx).Order("name").Find(&categories).Error; err != nil {
+		return nil, fmt.Errorf("failed to query categories: %w", err)
+	}
+	return categories, nil
+}
+
+func (r *categoryRepo) GetByCategoryID(ctx context.Context, id uint) (*models.Category, error) {
+	ctx, span := tracing.Start(ctx, "categoryRepo.GetByCategoryID")
+	defer span.End()
+
+	var category models.Category
+	err := r.db.WithContext(ctx).
+		Preload("Children", func(db *gorm.DB) *gorm.DB { return db.Order("name") }).
+		First(&category, id).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, fmt.Errorf("%w: category with ID %d not found", repositories.ErrNotFound, id)
+		}
+		return nil, fmt.Errorf("failed to fetch category: %w", err)
+	}
+	return &category, nil
+}
+
+func (r *categoryRepo) DescendantIDs(ctx context.Context, id uint) ([]uint, error) {
+	ctx, span := tracing.Start(ctx, "categoryRepo.DescendantIDs")
+	defer span.End()
+
+	if _, err := r.GetByCategoryID(ctx, id); err != nil {
+		return nil, err
+	}
+	return SubtreeIDs(r.db.WithContext(ctx), id)
+}
+
+// SubtreeIDs trả về rootID cùng id của mọi thể loại con cháu. Cây thể loại nhỏ nên
+// nạp toàn bộ cặp (id, parent_id) rồi duyệt trong Go, tránh phụ thuộc WITH RECURSIVE.
+func SubtreeIDs(db *gorm.DB, rootID uint) ([]uint, error) {
+	var nodes []struct {
+		ID       uint
+		ParentID *uint
+	}
+	if err := db.Model(&models.Category{}).Select("id", "parent_id").Find(&nodes).Error; err != nil {
+		return nil, fmt.Errorf("failed to load category tree: %w", err)
+	}
+
+	children := make(map[uint][]uint, len(nodes))
+	for _, n := range nodes {
+		if n.ParentID != nil {
+			children[*n.ParentID] = append(children[*n.ParentID], n.ID)
+		}
+	}
+
+	ids := []uint{rootID}
+	seen := map[uint]bool{rootID: true}
+	for i := 0; i < len(ids); i++ {
+		for _, child := range children[ids[i]] {
+			if !seen[child] {
+				seen[child] = true
+				ids = append(ids, child)
+			}
+		}
+	}
+	return ids, nil
+}
+
+// AncestorIDs trả về các id truyền vào cùng toàn bộ thể loại cha của chúng (bỏ trùng)
+func AncestorIDs(db *gorm.DB, ids []uint) ([]uint, error) {
+	if len(ids) == 0 {
+		return nil, nil
+	}
+	var nodes []struct {
+		ID       uint
+		ParentID *uint
+	}
+	if err := db.Model(&models.Category{}).Select("id", "parent_id").Find(&nodes).Error; err != nil {
+		return nil, fmt.Errorf("failed to load category tree: %w", err)
+	}
+
+	parent := make(map[uint]uint, len(nodes))
+	for _, n := range nodes {
+		if n.ParentID != nil {
+			parent[n.ID] = *n.ParentID
+		}
+	}
+
+	var result []uint
+	seen := make(map[uint]bool, len(ids))
+	for _, id := range ids {
+		for !seen[id] {
+			seen[id] = true
+			result = append(result, id)
+			p, ok := parent[id]
+			if !ok {
+				break
+			}
+			id = p
+		}
+	}
+	return result, nil
+}
+
+func (r *categoryRepo) CreateCategory(ctx context.Context, category *models.Category) error {
+	ctx, span := tracing.Start(ctx, "categoryRepo.CreateCategory")
+	defer span.End()
+
+	db := r.db.WithContext(ctx)
+	if err := checkParent(db, category.ParentID); err != nil {
+		return err
+	}
+	if err := checkDuplicateName(db, category, 0); err != nil {
+		return err
+	}
+	if err := db.Omit("Children").Create(category).Error; err != nil {
+		if dberr.IsUniqueViolation(err) {
+			return duplicateName(category.Name)
+		}
+		return fmt.Errorf("failed to create category: %w", err)
+	}
+	return nil
+}
+
+func (r *categoryRepo) UpdateById(ctx context.Context, category *models.Category) (*models.Category, error) {
+	ctx, span := tracing.Start(ctx, "categoryRepo.UpdateById")
+	defer span.End()
+
+	db := r.db.WithContext(ctx)
+	var existing models.Category
+	if err := db.First(&existing, category.ID).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, fmt.Errorf("%w: category with ID %d not found", repositories.ErrNotFound, category.ID)
+		}
+		return nil, fmt.Errorf("failed to fetch category: %w", err)
+	}
+	if err := checkParent(db, category.ParentID); err != nil {
+		return nil, err
+	}
+	if err := checkDuplicateName(db, category, category.ID); err != nil {
+		return nil, err
+	}
+
+	if err := db.Model(&existing).Updates(map[string]interface{}{
+		"name":       category.Name,
+		"parent_id":  category.ParentID,
+		"updated_at": category.UpdatedAt,
+	}).Error; err != nil {
+		if dberr.IsUniqueViolation(err) {
+			return nil, duplicateName(category.Name)
+		}
+		return nil, fmt.Errorf("failed to update category: %w", err)
+	}
+	return &existing, nil
+}
+
+// DeleteById chỉ xoá thể loại lá; liên kết sách - thể loại bị xoá cùng transaction
+func (r *categoryRepo) DeleteById(ctx context.Context, id uint) (*models.Category, error) {
+	ctx, span := tracing.Start(ctx, "categoryRepo.DeleteById")
+	defer span.End()
+
+	category, err := r.GetByCategoryID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if len(category.Children) > 0 {
+		return nil, fmt.Errorf("%w: category %d still has subcategories", repositories.ErrConflict, id)
+	}
+
+	err = r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("category_id = ?", id).Delete(&models.BookCategory{}).Error; err != nil {
+			return err
+		}
+		return tx.Delete(&models.Category{}, id).Error
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to delete category: %w", err)
+	}
+	return category, nil
+}
+
+func checkParent(db *gorm.DB, parentID *uint) error {
+	if parentID == nil {
+		return nil
+	}
+	var count int64
+	if err := db.Model(&models.Category{}).Where("id = ?", *parentID).Count(&count).Error; err != nil {
+		return err
+	}
+	if count == 0 {
+		return fmt.Errorf("%w: parent category with ID %d not found", repositories.ErrNotFound, *parentID)
+	}
+	return nil
+}
+
+// checkDuplicateName kiểm tra trùng tên cùng cấp. Unique index (parent_id, name) không
+// chặn được thể loại gốc vì NULL luôn khác nhau, nên phải kiểm tra thêm ở đây.
+func checkDuplicateName(db *gorm.DB, category *models.Category, excludeID uint) error {
+	query := db.Model(&models.Category{}).Where("name = ? AND id <> ?", category.Name, excludeID)
+	if category.ParentID == nil {
+		query = query.Where("parent_id IS NULL")
+	} else {
+		query = query.Where("parent_id = ?", *category.ParentID)
+	}
+	var count int64
+	if err := query.Count(&count).Error; err != nil {
+		return err
+	}
+	if count > 0 {
+		return duplicateName(category.Name)
+	}
+	return nil
+}
+
+func duplicateName(name string) error {
+	return fmt.Errorf("%w: category %q already exists under the same parent", repositories.ErrConflict, name)
+}
@@ -0,0 +1,120 @@
+package category_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+
+	"github.com/maithuc2003/Test_GIN_golang/internal/interfaces/repositories"
+	"github.com/maithuc2003/Test_GIN_golang/internal/models"
+	"github.com/maithuc2003/Test_GIN_golang/internal/repositories/category"
+	sqlitedriver "gorm.io/driver/sqlite"
+
+	_ "modernc.org/sqlite"
+)
+
+func setupTestDB(t *testing.T) *gorm.DB {
+	dsn := fmt.Sprintf("file:testdb_%d?mode=memory&cache=shared", time.Now().UnixNano())
+
+	db, err := gorm.Open(sqlitedriver.New(sqlitedriver.Config{
+		DSN:        dsn,
+		DriverName: "sqlite",
+	}), &gorm.Config{})
+
+	require.NoError(t, err)
+	require.NoError(t, db.AutoMigrate(&models.Category{}, &models.BookCategory{}))
+
+	return db
+}
+
+func uintPtr(v uint) *uint { return &v }
+
+func TestCategoryRepo_CreateAndTree(t *testing.T) {
+	db := setupTestDB(t)
+	repo := category.NewCategoryRepo(db)
+	ctx := context.Background()
+
+	fiction := models.Category{Name: "Fiction"}
+	require.NoError(t, repo.CreateCategory(ctx, &fiction))
+	fantasy := models.Category{Name: "Fantasy", ParentID: &fiction.ID}
+	require.NoError(t, repo.CreateCategory(ctx, &fantasy))
+	epic := models.Category{Name: "Epic", ParentID: &fantasy.ID}
+	require.NoError(t, repo.CreateCategory(ctx, &epic))
+
+	tests := []struct {
+		name      string
+		input     models.Category
+		expectErr error
+	}{
+		{name: "duplicate root", input: models.Category{Name: "Fiction"}, expectErr: repositories.ErrConflict},
+		{name: "duplicate sibling", input: models.Category{Name: "Fantasy", ParentID: &fiction.ID}, expectErr: repositories.ErrConflict},
+		{name: "same name under other parent", input: models.Category{Name: "Fantasy", ParentID: &fantasy.ID}},
+		{name: "missing parent", input: models.Category{Name: "Poetry", ParentID: uintPtr(999)}, expectErr: repositories.ErrNotFound},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := repo.CreateCategory(ctx, &tt.input)
+			if tt.expectErr != nil {
+				require.True(t, errors.Is(err, tt.expectErr), "got %v", err)
+				return
+			}
+			require.NoError(t, err)
+		})
+	}
+
+	got, err := repo.GetByCategoryID(ctx, fiction.ID)
+	require.NoError(t, err)
+	require.Len(t, got.Children, 1)
+
+	ids, err := repo.DescendantIDs(ctx, fiction.ID)
+	require.NoError(t, err)
+	var nested models.Category
+	require.NoError(t, db.Where("name = ? AND parent_id = ?", "Fantasy", fantasy.ID).First(&nested).Error)
+	require.ElementsMatch(t, []uint{fiction.ID, fantasy.ID, epic.ID, nested.ID}, ids)
+
+	_, err = repo.DescendantIDs(ctx, 999)
+	require.True(t, errors.Is(err, repositories.ErrNotFound))
+}
+
+func TestCategoryRepo_UpdateAndDelete(t *testing.T) {
+	db := setupTestDB(t)
+	repo := category.NewCategoryRepo(db)
+	ctx := context.Background()
+
+	fiction := models.Category{Name: "Fiction"}
+	require.NoError(t, repo.CreateCategory(ctx, &fiction))
+	science := models.Category{Name: "Science"}
+	require.NoError(t, repo.CreateCategory(ctx, &science))
+	fantasy := models.Category{Name: "Fantasy", ParentID: &fiction.ID}
+	require.NoError(t, repo.CreateCategory(ctx, &fantasy))
+	require.NoError(t, db.Create(&models.BookCategory{BookID: 1, CategoryID: fantasy.ID}).Error)
+
+	updated, err := repo.UpdateById(ctx, &models.Category{ID: fantasy.ID, Name: "Fantasy", ParentID: &science.ID})
+	require.NoError(t, err)
+	require.Equal(t, science.ID, *updated.ParentID)
+
+	_, err = repo.UpdateById(ctx, &models.Category{ID: science.ID, Name: "Fiction"})
+	require.True(t, errors.Is(err, repositories.ErrConflict))
+
+	_, err = repo.UpdateById(ctx, &models.Category{ID: 999, Name: "Nope"})
+	require.True(t, errors.Is(err, repositories.ErrNotFound))
+
+	_, err = repo.DeleteById(ctx, science.ID)
+	require.True(t, errors.Is(err, repositories.ErrConflict), "category with children must not be deleted")
+
+	deleted, err := repo.DeleteById(ctx, fantasy.ID)
+	require.NoError(t, err)
+	require.Equal(t, "Fantasy", deleted.Name)
+
+	var links int64
+	require.NoError(t, db.Model(&models.BookCategory{}).Count(&links).Error)
+	require.Zero(t, links)
+
+	_, err = repo.DeleteById(ctx, fantasy.ID)
+	require.True(t, errors.Is(err, repositories.ErrNotFound))
+}
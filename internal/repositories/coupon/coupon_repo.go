@@ -0,0 +1,222 @@
+package coupon
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+
+	"github.com/maithuc2003/Test_GIN_golang/internal/interfaces/repositories"
+	"github.com/maithuc2003/Test_GIN_golang/internal/models"
+	"github.com/maithuc2003/Test_GIN_golang/internal/repositories/category"
+	"github.com/maithuc2003/Test_GIN_golang/internal/repositories/dberr"
+	"github.com/maithuc2003/Test_GIN_golang/internal/tracing"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type couponRepo struct {
+	db *gorm.DB
+}
+
+func NewCouponRepo(db *gorm.DB) repositories.CouponRepository {
+	return &couponRepo{db: db}
+}
+
+func (r *couponRepo) List(ctx context.Context) ([]models.Coupon, error) {
+	ctx, span := tracing.Start(ctx, "couponRepo.List")
+	defer span.End()
+
+	var coupons []models.Coupon
+	if err := r.db.WithContext(ctx).Order("id").Find(&coupons).Error; err != nil {
+		return nil, fmt.Errorf("failed to query coupons: %w", err)
+	}
+	return coupons, nil
+}
+
+func (r *couponRepo) GetByID(ctx context.Context, id uint) (*models.Coupon, error) {
+	ctx, span := tracing.Start(ctx, "couponRepo.GetByID")
+	defer span.End()
+
+	var coupon models.Coupon
+	if err := r.db.WithContext(ctx).First(&coupon, id).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, fmt.Errorf("%w: coupon with ID %d not found", repositories.ErrNotFound, id)
+		}
+		return nil, fmt.Errorf("failed to fetch coupon: %w", err)
+	}
+	return &coupon, nil
+}
+
+func (r *couponRepo) GetByCode(ctx context.Context, code string) (*models.Coupon, error) {
+	ctx, span := tracing.Start(ctx, "couponRepo.GetByCode")
+	defer span.End()
+
+	var coupon models.Coupon
+	if err := r.db.WithContext(ctx).Where("code = ?", code).First(&coupon).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, fmt.Errorf("%w: coupon %s not found", repositories.ErrNotFound, code)
+		}
+		return nil, fmt.Errorf("failed to fetch coupon: %w", err)
+	}
+	return &coupon, nil
+}
+
+func (r *couponRepo) Create(ctx context.Context, coupon *models.Coupon) error {
+	ctx, span := tracing.Start(ctx, "couponRepo.Create")
+	defer span.End()
+
+	if err := r.db.WithContext(ctx).Create(coupon).Error; err != nil {
+		if dberr.IsUniqueViolation(err) {
+			return duplicateCode(coupon.Code)
+		}
+		return fmt.Errorf("failed to create coupon: %w", err)
+	}
+	return nil
+}
+
+// Update ghi đè cấu hình coupon; UsedCount và CreatedAt giữ nguyên
+func (r *couponRepo) Update(ctx context.Context, coupon *models.Coupon) (*models.Coupon, error) {
+	ctx, span := tracing.Start(ctx, "couponRepo.Update")
+	defer span.End()
+
+	existing, err := r.GetByID(ctx, coupon.ID)
+	if err != nil {
+		return nil, err
+	}
+	err = r.db.WithContext(ctx).Model(existing).
+		Select("code", "description", "type", "value", "currency", "book_ids", "category_ids",
+			"min_order_value", "max_uses", "max_uses_per_user", "starts_at", "ends_at", "active", "updated_at").
+		Updates(coupon).Error
+	if err != nil {
+		if dberr.IsUniqueViolation(err) {
+			return nil, duplicateCode(coupon.Code)
+		}
+		return nil, fmt.Errorf("failed to update coupon: %w", err)
+	}
+	return r.GetByID(ctx, coupon.ID)
+}
+
+func (r *couponRepo) Delete(ctx context.Context, id uint) (*models.Coupon, error) {
+	ctx, span := tracing.Start(ctx, "couponRepo.Delete")
+	defer span.End()
+
+	coupon, err := r.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	// Coupon đã dùng được giữ lại để đối chiếu với OrderDiscount; tắt Active thay vì xoá
+	result := r.db.WithContext(ctx).Where("id = ? AND used_count = 0", id).Delete(&models.Coupon{})
+	if result.Error != nil {
+		return nil, fmt.Errorf("failed to delete coupon: %w", result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return nil, fmt.Errorf("%w: coupon %s has been used, deactivate it instead", repositories.ErrConflict, coupon.Code)
+	}
+	return coupon, nil
+}
+
+func (r *couponRepo) BookCategoryIDs(ctx context.Context, bookID uint) ([]uint, error) {
+	ctx, span := tracing.Start(ctx, "couponRepo.BookCategoryIDs")
+	defer span.End()
+
+	db := r.db.WithContext(ctx)
+	var ids []uint
+	if err := db.Model(&models.BookCategory{}).Where("book_id = ?", bookID).Pluck("category_id", &ids).Error; err != nil {
+		return nil, fmt.Errorf("failed to load book categories: %w", err)
+	}
+	return category.AncestorIDs(db, ids)
+}
+
+func (r *couponRepo) Redeem(ctx context.Context, couponID, userID, orderID uint) error {
+	ctx, span := tracing.Start(ctx, "couponRepo.Redeem")
+	defer span.End()
+
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var coupon models.Coupon
+		// Khoá dòng coupon để hai đơn đồng thời không cùng vượt giới hạn
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).First(&coupon, couponID).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return fmt.Errorf("%w: coupon with ID %d not found", repositories.ErrNotFound, couponID)
+			}
+			return fmt.Errorf("failed to lock coupon: %w", err)
+		}
+
+		if coupon.MaxUses != nil && coupon.UsedCount >= *coupon.MaxUses {
+			return fmt.Errorf("%w: coupon %s has reached its usage limit", repositories.ErrConflict, coupon.Code)
+		}
+		if coupon.MaxUsesPerUser != nil {
+			var used int64
+			if err := tx.Model(&models.CouponRedemption{}).
+				Where("coupon_id = ? AND user_id = ?", couponID, userID).
+				Count(&used).Error; err != nil {
+				return fmt.Errorf("failed to count coupon redemptions: %w", err)
+			}
+			if used >= int64(*coupon.MaxUsesPerUser) {
+				return fmt.Errorf("%w: coupon %s has already been used the maximum number of times by this user", repositories.ErrConflict, coupon.Code)
+			}
+		}
+
+		redemption := models.CouponRedemption{CouponID: couponID, UserID: userID, OrderID: orderID}
+		if err := tx.Create(&redemption).Error; err != nil {
+			return fmt.Errorf("failed to record coupon redemption: %w", err)
+		}
+		if err := tx.Model(&models.Coupon{}).Where("id = ?", couponID).
+			Update("used_count", gorm.Expr("used_count + 1")).Error; err != nil {
+			return fmt.Errorf("failed to update coupon usage: %w", err)
+		}
+		return nil
+	})
+}
+
+func (r *couponRepo) Release(ctx context.Context, orderID uint) error {
+	ctx, span := tracing.Start(ctx, "couponRepo.Release")
+	defer span.End()
+
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		return ReleaseRedemptions(tx, orderID)
+	})
+}
+
+// ReleaseRedemptions xoá lượt dùng coupon của các đơn và trả lại used_count, dùng trong
+// transaction huỷ hoặc xoá đơn. Coupon được khoá theo thứ tự id để tránh deadlock
+func ReleaseRedemptions(tx *gorm.DB, orderIDs ...uint) error {
+	if len(orderIDs) == 0 {
+		return nil
+	}
+	var redemptions []models.CouponRedemption
+	if err := tx.Where("order_id IN ?", orderIDs).Find(&redemptions).Error; err != nil {
+		return fmt.Errorf("failed to load coupon redemptions: %w", err)
+	}
+	if len(redemptions) == 0 {
+		return nil
+	}
+
+	uses := make(map[uint]int)
+	ids := make([]uint, 0, len(redemptions))
+	for _, redemption := range redemptions {
+		uses[redemption.CouponID]++
+		ids = append(ids, redemption.ID)
+	}
+	couponIDs := make([]uint, 0, len(uses))
+	for id := range uses {
+		couponIDs = append(couponIDs, id)
+	}
+	slices.Sort(couponIDs)
+
+	for _, id := range couponIDs {
+		if err := tx.Model(&models.Coupon{}).Where("id = ?", id).
+			Update("used_count", gorm.Expr("used_count - ?", uses[id])).Error; err != nil {
+			return fmt.Errorf("failed to release coupon usage: %w", err)
+		}
+	}
+	if err := tx.Delete(&models.CouponRedemption{}, ids).Error; err != nil {
+		return fmt.Errorf("failed to delete coupon redemptions: %w", err)
+	}
+	return nil
+}
+
+func duplicateCode(code string) error {
+	return fmt.Errorf("%w: coupon %s already exists", repositories.ErrConflict, code)
+}
@@ -0,0 +1,151 @@
+package coupon_test
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+
+	"github.com/maithuc2003/Test_GIN_golang/internal/interfaces/repositories"
+	"github.com/maithuc2003/Test_GIN_golang/internal/models"
+	"github.com/maithuc2003/Test_GIN_golang/internal/repositories/coupon"
+	sqlitedriver "gorm.io/driver/sqlite"
+
+	_ "modernc.org/sqlite"
+)
+
+func setupTestDB(t *testing.T) *gorm.DB {
+	dsn := fmt.Sprintf("file:testdb_%d?mode=memory&cache=shared", time.Now().UnixNano())
+
+	db, err := gorm.Open(sqlitedriver.New(sqlitedriver.Config{
+		DSN:        dsn,
+		DriverName: "sqlite",
+	}), &gorm.Config{})
+
+	require.NoError(t, err)
+	require.NoError(t, db.AutoMigrate(&models.Category{}, &models.BookCategory{}, &models.Coupon{}, &models.CouponRedemption{}))
+	return db
+}
+
+func intPtr(v int) *int {
+	return &v
+}
+
+func TestCouponRepo_CRUD(t *testing.T) {
+	ctx := context.Background()
+	repo := coupon.NewCouponRepo(setupTestDB(t))
+
+	c := &models.Coupon{
+		Code: "SUMMER10", Type: models.CouponTypePercentage, Value: decimal.NewFromInt(10),
+		BookIDs: []uint{1, 2}, CategoryIDs: []uint{3}, Active: true,
+	}
+	require.NoError(t, repo.Create(ctx, c))
+	err := repo.Create(ctx, &models.Coupon{Code: "SUMMER10", Type: models.CouponTypeFixed, Value: decimal.NewFromInt(1)})
+	require.ErrorIs(t, err, repositories.ErrConflict)
+
+	got, err := repo.GetByCode(ctx, "SUMMER10")
+	require.NoError(t, err)
+	require.Equal(t, []uint{1, 2}, got.BookIDs)
+	require.Equal(t, []uint{3}, got.CategoryIDs)
+	_, err = repo.GetByCode(ctx, "NOPE")
+	require.ErrorIs(t, err, repositories.ErrNotFound)
+
+	c.Active = false
+	c.BookIDs = nil
+	c.MaxUses = intPtr(3)
+	updated, err := repo.Update(ctx, c)
+	require.NoError(t, err)
+	require.False(t, updated.Active)
+	require.Empty(t, updated.BookIDs)
+	require.Equal(t, 3, *updated.MaxUses)
+
+	_, err = repo.Update(ctx, &models.Coupon{ID: 99, Code: "X"})
+	require.ErrorIs(t, err, repositories.ErrNotFound)
+
+	list, err := repo.List(ctx)
+	require.NoError(t, err)
+	require.Len(t, list, 1)
+
+	deleted, err := repo.Delete(ctx, c.ID)
+	require.NoError(t, err)
+	require.Equal(t, "SUMMER10", deleted.Code)
+	_, err = repo.GetByID(ctx, c.ID)
+	require.ErrorIs(t, err, repositories.ErrNotFound)
+}
+
+func TestCouponRepo_Redeem(t *testing.T) {
+	ctx := context.Background()
+	db := setupTestDB(t)
+	repo := coupon.NewCouponRepo(db)
+
+	c := &models.Coupon{
+		Code: "TWICE", Type: models.CouponTypeFixed, Value: decimal.NewFromInt(5), Currency: "USD",
+		MaxUses: intPtr(3), MaxUsesPerUser: intPtr(2), Active: true,
+	}
+	require.NoError(t, repo.Create(ctx, c))
+
+	require.NoError(t, repo.Redeem(ctx, c.ID, 7, 1))
+	require.NoError(t, repo.Redeem(ctx, c.ID, 7, 2))
+	require.ErrorIs(t, repo.Redeem(ctx, c.ID, 7, 3), repositories.ErrConflict, "per-user limit")
+	require.NoError(t, repo.Redeem(ctx, c.ID, 8, 4))
+	require.ErrorIs(t, repo.Redeem(ctx, c.ID, 9, 5), repositories.ErrConflict, "overall limit")
+	require.ErrorIs(t, repo.Redeem(ctx, 99, 9, 5), repositories.ErrNotFound)
+
+	got, err := repo.GetByID(ctx, c.ID)
+	require.NoError(t, err)
+	require.Equal(t, 3, got.UsedCount)
+	var redemptions int64
+	require.NoError(t, db.Model(&models.CouponRedemption{}).Count(&redemptions).Error)
+	require.Equal(t, int64(3), redemptions)
+
+	_, err = repo.Delete(ctx, c.ID)
+	require.ErrorIs(t, err, repositories.ErrConflict)
+}
+
+func TestCouponRepo_Release(t *testing.T) {
+	ctx := context.Background()
+	db := setupTestDB(t)
+	repo := coupon.NewCouponRepo(db)
+
+	c := &models.Coupon{
+		Code: "ONCE", Type: models.CouponTypeFixed, Value: decimal.NewFromInt(5), Currency: "USD",
+		MaxUses: intPtr(2), MaxUsesPerUser: intPtr(1), Active: true,
+	}
+	require.NoError(t, repo.Create(ctx, c))
+	require.NoError(t, repo.Redeem(ctx, c.ID, 7, 1))
+	require.NoError(t, repo.Redeem(ctx, c.ID, 8, 2))
+
+	// Đơn 1 bị huỷ: user 7 dùng lại được và tổng lượt dùng giảm
+	require.NoError(t, repo.Release(ctx, 1))
+	require.NoError(t, repo.Release(ctx, 99))
+	got, err := repo.GetByID(ctx, c.ID)
+	require.NoError(t, err)
+	require.Equal(t, 1, got.UsedCount)
+	require.NoError(t, repo.Redeem(ctx, c.ID, 7, 3))
+}
+
+func TestCouponRepo_BookCategoryIDs(t *testing.T) {
+	ctx := context.Background()
+	db := setupTestDB(t)
+	repo := coupon.NewCouponRepo(db)
+
+	fiction := models.Category{Name: "Fiction"}
+	require.NoError(t, db.Create(&fiction).Error)
+	fantasy := models.Category{Name: "Fantasy", ParentID: &fiction.ID}
+	require.NoError(t, db.Create(&fantasy).Error)
+	poetry := models.Category{Name: "Poetry"}
+	require.NoError(t, db.Create(&poetry).Error)
+	require.NoError(t, db.Create(&[]models.BookCategory{{BookID: 1, CategoryID: fantasy.ID}, {BookID: 1, CategoryID: poetry.ID}}).Error)
+
+	ids, err := repo.BookCategoryIDs(ctx, 1)
+	require.NoError(t, err)
+	require.ElementsMatch(t, []uint{fiction.ID, fantasy.ID, poetry.ID}, ids)
+
+	ids, err = repo.BookCategoryIDs(ctx, 2)
+	require.NoError(t, err)
+	require.Empty(t, ids)
+}
@@ -0,0 +1,47 @@
+package dberr
+
+import (
+	"errors"
+	"strings"
+
+	"github.com/go-sql-driver/mysql"
+	"gorm.io/gorm"
+)
+
+const (
+	mysqlDuplicateEntry      = 1062
+	postgresUniqueViolation  = "23505"
+	sqliteConstraintPrimary  = 1555
+	sqliteConstraintUnique   = 2067
+	sqliteUniqueErrorMessage = "UNIQUE constraint failed"
+)
+
+// IsUniqueViolation nhận diện lỗi vi phạm unique của MySQL, Postgres và SQLite.
+// Postgres (pgconn) và SQLite (modernc) được nhận qua method nên không cần import driver.
+func IsUniqueViolation(err error) bool {
+	if err == nil {
+		return false
+	}
+	if errors.Is(err, gorm.ErrDuplicatedKey) {
+		return true
+	}
+
+	var mysqlErr *mysql.MySQLError
+	if errors.As(err, &mysqlErr) {
+		return mysqlErr.Number == mysqlDuplicateEntry
+	}
+
+	var pgErr interface{ SQLState() string }
+	if errors.As(err, &pgErr) {
+		return pgErr.SQLState() == postgresUniqueViolation
+	}
+
+	var sqliteErr interface{ Code() int }
+	if errors.As(err, &sqliteErr) {
+		code := sqliteErr.Code()
+		if code == sqliteConstraintUnique || code == sqliteConstraintPrimary {
+			return true
+		}
+	}
+	return strings.Contains(err.Error(), sqliteUniqueErrorMessage)
+}
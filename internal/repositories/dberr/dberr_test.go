@@ -0,0 +1,44 @@
+package dberr_test
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/go-sql-driver/mysql"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+
+	"github.com/maithuc2003/Test_GIN_golang/internal/repositories/dberr"
+)
+
+type sqliteError struct{ code int }
+
+func (e *sqliteError) Error() string { return "constraint failed" }
+func (e *sqliteError) Code() int     { return e.code }
+
+func TestIsUniqueViolation(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want bool
+	}{
+		{"nil", nil, false},
+		{"gorm translated", gorm.ErrDuplicatedKey, true},
+		{"mysql duplicate entry", &mysql.MySQLError{Number: 1062, Message: "Duplicate entry"}, true},
+		{"mysql other error", &mysql.MySQLError{Number: 1452}, false},
+		{"postgres unique violation", &pgconn.PgError{Code: "23505"}, true},
+		{"postgres foreign key", &pgconn.PgError{Code: "23503"}, false},
+		{"sqlite unique code", &sqliteError{code: 2067}, true},
+		{"sqlite message", errors.New("UNIQUE constraint failed: authors.normalized_name"), true},
+		{"wrapped", fmt.Errorf("failed to create author: %w", &pgconn.PgError{Code: "23505"}), true},
+		{"other error", errors.New("connection refused"), false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			require.Equal(t, tt.want, dberr.IsUniqueViolation(tt.err))
+		})
+	}
+}
@@ -0,0 +1,82 @@
+package export
+
+import (
+	"context"
+
+	"github.com/maithuc2003/Test_GIN_golang/internal/interfaces/repositories"
+	"github.com/maithuc2003/Test_GIN_golang/internal/models"
+	"github.com/maithuc2003/Test_GIN_golang/internal/repositories/author"
+	"github.com/maithuc2003/Test_GIN_golang/internal/repositories/book"
+	"github.com/maithuc2003/Test_GIN_golang/internal/repositories/order"
+	"github.com/maithuc2003/Test_GIN_golang/internal/tracing"
+
+	"gorm.io/gorm"
+)
+
+type exportRepo struct {
+	db *gorm.DB
+}
+
+func NewExportRepo(db *gorm.DB) repositories.ExportRepository {
+	return &exportRepo{db: db}
+}
+
+func (r *exportRepo) StreamBooks(ctx context.Context, filter models.BookFilter, batchSize int, fn func([]models.Book) error) error {
+	ctx, span := tracing.Start(ctx, "exportRepo.StreamBooks")
+	defer span.End()
+
+	query := func() *gorm.DB {
+		return r.db.WithContext(ctx).Model(&models.Book{}).Scopes(book.FilterScope(filter))
+	}
+	return streamByID(query, "books.id", batchSize, func(b *models.Book) int64 { return int64(b.ID) }, fn)
+}
+
+func (r *exportRepo) StreamAuthors(ctx context.Context, filter models.AuthorFilter, batchSize int, fn func([]models.Author) error) error {
+	ctx, span := tracing.Start(ctx, "exportRepo.StreamAuthors")
+	defer span.End()
+
+	query := func() *gorm.DB {
+		return r.db.WithContext(ctx).Model(&models.Author{}).Scopes(author.FilterScope(filter))
+	}
+	return streamByID(query, "authors.id", batchSize, func(a *models.Author) int64 { return int64(a.ID) }, fn)
+}
+
+func (r *exportRepo) StreamOrders(ctx context.Context, filter models.OrderFilter, batchSize int, fn func([]models.OrderExportRow) error) error {
+	ctx, span := tracing.Start(ctx, "exportRepo.StreamOrders")
+	defer span.End()
+
+	query := func() *gorm.DB {
+		return r.db.WithContext(ctx).Table("orders").
+			Select("orders.id, orders.book_id, books.title AS book_title, orders.user_id, users.username, " +
+				"orders.quantity, orders.status, orders.currency, orders.subtotal, orders.discount_total, " +
+				"orders.tax_total, orders.grand_total, orders.ordered_at, orders.updated_at").
+			Joins("LEFT JOIN books ON books.id = orders.book_id").
+			Joins("LEFT JOIN users ON users.id = orders.user_id").
+			Scopes(order.FilterScope(filter))
+	}
+	return streamByID(query, "orders.id", batchSize, func(o *models.OrderExportRow) int64 { return int64(o.ID) }, fn)
+}
+
+// streamByID đọc theo keyset (id > lastID ORDER BY id LIMIT n) để không giữ cả bảng trong bộ nhớ
+func streamByID[T any](query func() *gorm.DB, idColumn string, batchSize int, idOf func(*T) int64, fn func([]T) error) error {
+	if batchSize <= 0 {
+		batchSize = 500
+	}
+	var lastID int64
+	for {
+		batch := make([]T, 0, batchSize)
+		if err := query().Where(idColumn+" > ?", lastID).Order(idColumn).Limit(batchSize).Find(&batch).Error; err != nil {
+			return err
+		}
+		if len(batch) == 0 {
+			return nil
+		}
+		if err := fn(batch); err != nil {
+			return err
+		}
+		if len(batch) < batchSize {
+			return nil
+		}
+		lastID = idOf(&batch[len(batch)-1])
+	}
+}
@@ -0,0 +1,80 @@
+package invoice
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/maithuc2003/Test_GIN_golang/internal/interfaces/repositories"
+	"github.com/maithuc2003/Test_GIN_golang/internal/models"
+	"github.com/maithuc2003/Test_GIN_golang/internal/repositories/dberr"
+	"github.com/maithuc2003/Test_GIN_golang/internal/tracing"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type invoiceRepo struct {
+	db *gorm.DB
+}
+
+func NewInvoiceRepo(db *gorm.DB) repositories.InvoiceRepository {
+	return &invoiceRepo{db: db}
+}
+
+func (r *invoiceRepo) GetByOrderID(ctx context.Context, orderID uint) (*models.Invoice, error) {
+	ctx, span := tracing.Start(ctx, "invoiceRepo.GetByOrderID")
+	defer span.End()
+
+	var invoice models.Invoice
+	if err := r.db.WithContext(ctx).Where("order_id = ?", orderID).First(&invoice).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, fmt.Errorf("%w: invoice for order %d not found", repositories.ErrNotFound, orderID)
+		}
+		return nil, fmt.Errorf("failed to fetch invoice: %w", err)
+	}
+	return &invoice, nil
+}
+
+// NextNumber tạo dòng bộ đếm nếu chưa có (bỏ qua khi đã tồn tại) rồi khoá dòng đó
+// và tăng số; các lần phát hành đồng thời phải chờ nhau tại đây
+func (r *invoiceRepo) NextNumber(ctx context.Context, series string) (int64, error) {
+	ctx, span := tracing.Start(ctx, "invoiceRepo.NextNumber")
+	defer span.End()
+
+	var next int64
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Clauses(clause.OnConflict{DoNothing: true}).
+			Create(&models.InvoiceSequence{Name: series}).Error; err != nil {
+			return fmt.Errorf("failed to create invoice sequence: %w", err)
+		}
+		var seq models.InvoiceSequence
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+			Where("name = ?", series).First(&seq).Error; err != nil {
+			return fmt.Errorf("failed to lock invoice sequence: %w", err)
+		}
+		next = seq.LastNumber + 1
+		if err := tx.Model(&models.InvoiceSequence{}).Where("name = ?", series).
+			Update("last_number", next).Error; err != nil {
+			return fmt.Errorf("failed to update invoice sequence: %w", err)
+		}
+		return nil
+	})
+	if err != nil {
+		return 0, err
+	}
+	return next, nil
+}
+
+func (r *invoiceRepo) Create(ctx context.Context, invoice *models.Invoice) error {
+	ctx, span := tracing.Start(ctx, "invoiceRepo.Create")
+	defer span.End()
+
+	if err := r.db.WithContext(ctx).Create(invoice).Error; err != nil {
+		if dberr.IsUniqueViolation(err) {
+			return fmt.Errorf("%w: order %d already has an invoice", repositories.ErrConflict, invoice.OrderID)
+		}
+		return fmt.Errorf("failed to create invoice: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,84 @@
+package invoice_test
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+
+	"github.com/maithuc2003/Test_GIN_golang/internal/interfaces/repositories"
+	"github.com/maithuc2003/Test_GIN_golang/internal/models"
+	"github.com/maithuc2003/Test_GIN_golang/internal/repositories/invoice"
+	sqlitedriver "gorm.io/driver/sqlite"
+
+	_ "modernc.org/sqlite"
+)
+
+func setupTestDB(t *testing.T) *gorm.DB {
+	dsn := fmt.Sprintf("file:testdb_%d?mode=memory&cache=shared", time.Now().UnixNano())
+
+	db, err := gorm.Open(sqlitedriver.New(sqlitedriver.Config{
+		DSN:        dsn,
+		DriverName: "sqlite",
+	}), &gorm.Config{})
+
+	require.NoError(t, err)
+	require.NoError(t, db.AutoMigrate(&models.Invoice{}, &models.InvoiceSequence{}))
+	return db
+}
+
+func TestInvoiceRepo_NextNumber(t *testing.T) {
+	ctx := context.Background()
+	db := setupTestDB(t)
+	repo := invoice.NewInvoiceRepo(db)
+
+	for want := int64(1); want <= 3; want++ {
+		got, err := repo.NextNumber(ctx, "INV-2025")
+		require.NoError(t, err)
+		require.Equal(t, want, got)
+	}
+	// Mỗi dãy đếm riêng
+	got, err := repo.NextNumber(ctx, "INV-2026")
+	require.NoError(t, err)
+	require.Equal(t, int64(1), got)
+
+	// Số đã cấp trong transaction bị rollback được cấp lại
+	err = db.Transaction(func(tx *gorm.DB) error {
+		n, err := invoice.NewInvoiceRepo(tx).NextNumber(ctx, "INV-2025")
+		require.NoError(t, err)
+		require.Equal(t, int64(4), n)
+		return fmt.Errorf("render failed")
+	})
+	require.Error(t, err)
+	got, err = repo.NextNumber(ctx, "INV-2025")
+	require.NoError(t, err)
+	require.Equal(t, int64(4), got)
+}
+
+func TestInvoiceRepo_CreateAndGet(t *testing.T) {
+	ctx := context.Background()
+	db := setupTestDB(t)
+	repo := invoice.NewInvoiceRepo(db)
+
+	_, err := repo.GetByOrderID(ctx, 3)
+	require.ErrorIs(t, err, repositories.ErrNotFound)
+
+	issued := &models.Invoice{
+		OrderID: 3, Number: "INV-2025-000001", IssuedAt: time.Now(), Currency: "USD",
+		GrandTotal: decimal.RequireFromString("21.00"), HTML: []byte("<html></html>"), PDF: []byte("%PDF-1.3"),
+	}
+	require.NoError(t, repo.Create(ctx, issued))
+
+	got, err := repo.GetByOrderID(ctx, 3)
+	require.NoError(t, err)
+	require.Equal(t, issued.Number, got.Number)
+	require.Equal(t, issued.HTML, got.HTML)
+	require.Equal(t, issued.PDF, got.PDF)
+
+	err = repo.Create(ctx, &models.Invoice{OrderID: 3, Number: "INV-2025-000002"})
+	require.ErrorIs(t, err, repositories.ErrConflict)
+}
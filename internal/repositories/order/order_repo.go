@@ -1,11 +1,18 @@
 package order
 
 import (
+	"context"
 	"errors"
 	"fmt"
+	"time"
 
+	"github.com/maithuc2003/Test_GIN_golang/internal/events"
 	"github.com/maithuc2003/Test_GIN_golang/internal/interfaces/repositories"
 	"github.com/maithuc2003/Test_GIN_golang/internal/models"
+	"github.com/maithuc2003/Test_GIN_golang/internal/repositories/coupon"
+	"github.com/maithuc2003/Test_GIN_golang/internal/repositories/outbox"
+	"github.com/maithuc2003/Test_GIN_golang/internal/repositories/reservation"
+	"github.com/maithuc2003/Test_GIN_golang/internal/tracing"
 
 	"gorm.io/gorm"
 	"gorm.io/gorm/clause"
@@ -20,89 +27,379 @@ func NewOrderRepo(db *gorm.DB) repositories.OrderRepositoryInterface {
 	return &orderRepo{db: db}
 }
 
-// Tạo đơn hàng, giảm stock sách trong transaction có khóa dòng (pessimistic lock)
-func (r *orderRepo) Create(order *models.Order) error {
-	return r.db.Transaction(func(tx *gorm.DB) error {
+// Tạo đơn hàng trong transaction có khóa dòng sách (pessimistic lock). Số lượng được
+// kiểm tra với tồn kho khả dụng (trừ phần đang giữ); nếu order.ReservedUntil khác nil
+// thì chỉ tạo reservation tới thời điểm đó, ngược lại trừ stock ngay. Event OrderCreated
+// (và StockChanged khi trừ stock) được ghi vào outbox trong cùng transaction.
+func (r *orderRepo) Create(ctx context.Context, order *models.Order) error {
+	ctx, span := tracing.Start(ctx, "orderRepo.Create")
+	defer span.End()
+
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
 		var book models.Book
 		// Khóa bản ghi sách đang xử lý để tránh race condition
 		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).First(&book, order.BookID).Error; err != nil {
 			return fmt.Errorf("book not found: %w", err)
 		}
 
-		if book.Stock < order.Quantity {
-			return fmt.Errorf("not enough stock available")
+		reserved, err := reservation.ReservedByBook(tx, []uint{book.ID}, time.Now())
+		if err != nil {
+			return err
+		}
+		if book.Stock-reserved[book.ID] < order.Quantity {
+			return repositories.ErrInsufficientStock
 		}
 
 		if err := tx.Create(order).Error; err != nil {
 			return fmt.Errorf("failed to create order: %w", err)
 		}
+		if err := outbox.Append(tx, events.OrderCreated{
+			OrderID:    order.ID,
+			UserID:     order.UserID,
+			BookID:     order.BookID,
+			Quantity:   order.Quantity,
+			Status:     order.Status,
+			Currency:   order.Currency,
+			GrandTotal: order.GrandTotal,
+		}); err != nil {
+			return err
+		}
 
-		if err := tx.Model(&book).Update("stock", book.Stock-order.Quantity).Error; err != nil {
-			return fmt.Errorf("failed to update book stock: %w", err)
+		if order.ReservedUntil != nil {
+			hold := models.StockReservation{
+				OrderID:   order.ID,
+				BookID:    book.ID,
+				Quantity:  order.Quantity,
+				ExpiresAt: *order.ReservedUntil,
+			}
+			if err := tx.Create(&hold).Error; err != nil {
+				return fmt.Errorf("failed to reserve stock: %w", err)
+			}
+			return nil
 		}
 
-		return nil
+		remaining := book.Stock - order.Quantity
+		if err := tx.Model(&book).Update("stock", remaining).Error; err != nil {
+			return fmt.Errorf("failed to update book stock: %w", err)
+		}
+		return outbox.Append(tx, events.StockChanged{
+			BookID: book.ID,
+			Delta:  -order.Quantity,
+			Stock:  remaining,
+			Reason: events.StockReasonOrder,
+		})
 	})
 }
 
+// FilterScope áp dụng bộ lọc đơn hàng; dùng chung cho danh sách và export
+func FilterScope(filter models.OrderFilter) func(*gorm.DB) *gorm.DB {
+	return func(db *gorm.DB) *gorm.DB {
+		if filter.UserID > 0 {
+			db = db.Where("orders.user_id = ?", filter.UserID)
+		}
+		if filter.BookID > 0 {
+			db = db.Where("orders.book_id = ?", filter.BookID)
+		}
+		if filter.Status != "" {
+			db = db.Where("orders.status = ?", filter.Status)
+		}
+		return db
+	}
+}
+
 // Lấy tất cả đơn hàng
-func (r *orderRepo) GetAllOrders() ([]*models.Order, error) {
+func (r *orderRepo) GetAllOrders(ctx context.Context, filter models.OrderFilter) ([]*models.Order, error) {
+	ctx, span := tracing.Start(ctx, "orderRepo.GetAllOrders")
+	defer span.End()
+
 	var orders []*models.Order
-	if err := r.db.Find(&orders).Error; err != nil {
+	if err := r.db.WithContext(ctx).Scopes(FilterScope(filter)).Preload("Discounts").Find(&orders).Error; err != nil {
 		return nil, err
 	}
 	return orders, nil
 }
 
 // Lấy đơn hàng theo ID
-func (r *orderRepo) GetByOrderID(id uint) (*models.Order, error) {
+func (r *orderRepo) GetByOrderID(ctx context.Context, id uint) (*models.Order, error) {
+	ctx, span := tracing.Start(ctx, "orderRepo.GetByOrderID")
+	defer span.End()
+
+	var order models.Order
+	if err := r.db.WithContext(ctx).Preload("Discounts").First(&order, id).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, fmt.Errorf("order with ID %d %w", id, repositories.ErrNotFound)
+		}
+		return nil, err
+	}
+	return &order, nil
+}
+
+// GetForUpdate đọc đơn hàng kèm khóa dòng (SELECT ... FOR UPDATE)
+func (r *orderRepo) GetForUpdate(ctx context.Context, id uint) (*models.Order, error) {
+	ctx, span := tracing.Start(ctx, "orderRepo.GetForUpdate")
+	defer span.End()
+
 	var order models.Order
-	if err := r.db.First(&order, id).Error; err != nil {
+	if err := r.db.WithContext(ctx).Clauses(clause.Locking{Strength: "UPDATE"}).First(&order, id).Error; err != nil {
 		if errors.Is(err, gorm.ErrRecordNotFound) {
-			return nil, fmt.Errorf("order with ID %d not found", id)
+			return nil, fmt.Errorf("order with ID %d %w", id, repositories.ErrNotFound)
 		}
 		return nil, err
 	}
 	return &order, nil
 }
 
-// Xóa đơn hàng theo ID và trả về đơn hàng đã xóa
-func (r *orderRepo) DeleteByOrderID(id uint) (*models.Order, error) {
-	order, err := r.GetByOrderID(id)
+// Xóa đơn hàng theo ID (kèm hàng đang giữ và chi tiết giảm giá của đơn) và trả về đơn hàng đã xóa
+func (r *orderRepo) DeleteByOrderID(ctx context.Context, id uint) (*models.Order, error) {
+	ctx, span := tracing.Start(ctx, "orderRepo.DeleteByOrderID")
+	defer span.End()
+
+	order, err := r.GetByOrderID(ctx, id)
 	if err != nil {
 		return nil, err
 	}
 
-	if err := r.db.Delete(&models.Order{}, id).Error; err != nil {
+	err = r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("order_id = ?", id).Delete(&models.StockReservation{}).Error; err != nil {
+			return err
+		}
+		if err := tx.Where("order_id = ?", id).Delete(&models.OrderDiscount{}).Error; err != nil {
+			return err
+		}
+		if err := coupon.ReleaseRedemptions(tx, id); err != nil {
+			return err
+		}
+		return tx.Delete(&models.Order{}, id).Error
+	})
+	if err != nil {
 		return nil, fmt.Errorf("failed to delete order: %w", err)
 	}
 
 	return order, nil
 }
 
-// Cập nhật đơn hàng theo ID, trả về đơn hàng đã cập nhật
-func (r *orderRepo) UpdateByOrderID(order *models.Order) (*models.Order, error) {
-	result := r.db.Model(&models.Order{}).
-		Where("id = ?", order.ID).
-		Updates(map[string]interface{}{
+// Chuyển trạng thái đơn hàng có điều kiện để hai luồng (thanh toán, webhook, sweeper) không ghi đè nhau
+func (r *orderRepo) UpdateStatus(ctx context.Context, id uint, from, to string) error {
+	ctx, span := tracing.Start(ctx, "orderRepo.UpdateStatus")
+	defer span.End()
+
+	var updated int64
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		result := tx.Model(&models.Order{}).
+			Where("id = ? AND status = ?", id, from).
+			Update("status", to)
+		if result.Error != nil {
+			return fmt.Errorf("failed to update order status: %w", result.Error)
+		}
+		if updated = result.RowsAffected; updated == 0 {
+			return nil
+		}
+		return outbox.Append(tx, events.OrderStatusChanged{OrderID: id, From: from, To: to})
+	})
+	if err != nil {
+		return err
+	}
+	if updated == 0 {
+		if _, err := r.GetByOrderID(ctx, id); err != nil {
+			return err
+		}
+		return fmt.Errorf("%w: order %d is no longer %s", repositories.ErrConflict, id, from)
+	}
+	return nil
+}
+
+// Cập nhật đơn hàng theo ID (kèm giá đã chốt và chi tiết giảm giá), trả về đơn hàng
+// đã cập nhật. Đơn đã thanh toán hoặc hoàn tiền không đổi được trạng thái, người mua, sách,
+// số lượng và tiền; điều kiện nằm trong câu UPDATE để không bị ghi đè khi thanh toán chạy song song.
+// Khi đổi sách, số lượng hoặc trạng thái, phần hàng đơn đang giữ (reservation hoặc stock
+// đã trừ) được trả lại rồi giữ lại theo giá trị mới trong cùng transaction.
+func (r *orderRepo) UpdateByOrderID(ctx context.Context, order *models.Order) (*models.Order, error) {
+	ctx, span := tracing.Start(ctx, "orderRepo.UpdateByOrderID")
+	defer span.End()
+
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var current models.Order
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).Select("id", "book_id", "quantity", "status").First(&current, order.ID).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return fmt.Errorf("no order updated with id %d", order.ID)
+			}
+			return err
+		}
+
+		fields := map[string]interface{}{
 			"book_id":  order.BookID,
 			"user_id":  order.UserID,
 			"quantity": order.Quantity,
 			"status":   order.Status,
-		})
+		}
+		settled := settledStatus(current.Status)
+		if !settled {
+			fields["currency"] = order.Currency
+			fields["unit_price"] = order.UnitPrice
+			fields["subtotal"] = order.Subtotal
+			fields["discount_total"] = order.DiscountTotal
+			fields["tax_region"] = order.TaxRegion
+			fields["tax_rate"] = order.TaxRate
+			fields["tax_inclusive"] = order.TaxInclusive
+			fields["tax_total"] = order.TaxTotal
+			fields["grand_total"] = order.GrandTotal
+		}
+		result := tx.Model(&models.Order{}).
+			Where("id = ?", order.ID).
+			Where("status NOT IN ? OR (status = ? AND user_id = ? AND book_id = ? AND quantity = ? AND grand_total = ?)",
+				[]string{models.OrderStatusPaid, models.OrderStatusRefunded}, order.Status, order.UserID, order.BookID, order.Quantity, order.GrandTotal).
+			Updates(fields)
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return fmt.Errorf("%w: order %d has been paid, its status, owner, items and totals can no longer change", repositories.ErrConflict, order.ID)
+		}
+		if err := moveStock(tx, &current, order); err != nil {
+			return err
+		}
+		if current.Status != order.Status {
+			if err := outbox.Append(tx, events.OrderStatusChanged{OrderID: order.ID, From: current.Status, To: order.Status}); err != nil {
+				return err
+			}
+		}
+
+		if settled {
+			return nil
+		}
+		if err := tx.Where("order_id = ?", order.ID).Delete(&models.OrderDiscount{}).Error; err != nil {
+			return fmt.Errorf("failed to replace order discounts: %w", err)
+		}
+		if len(order.Discounts) == 0 {
+			return nil
+		}
+		discounts := make([]models.OrderDiscount, len(order.Discounts))
+		for i, d := range order.Discounts {
+			d.ID = 0
+			d.OrderID = order.ID
+			discounts[i] = d
+		}
+		if err := tx.Create(&discounts).Error; err != nil {
+			return fmt.Errorf("failed to replace order discounts: %w", err)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return r.GetByOrderID(ctx, order.ID)
+}
+
+// settledStatus cho biết đơn đã qua thanh toán: giá và giảm giá đã chốt, trạng thái chỉ
+// đổi qua luồng thanh toán/hoàn tiền
+func settledStatus(status string) bool {
+	return status == models.OrderStatusPaid || status == models.OrderStatusRefunded
+}
+
+// stockHold là cách một đơn giữ tồn kho theo trạng thái
+type stockHold int
+
+const (
+	holdNone     stockHold = iota // expired: hàng đã được trả lại
+	holdReserved                  // pending: giữ bằng reservation tới khi thanh toán
+	holdDeducted                  // các trạng thái nhập tay khác: đã trừ stock
+)
 
-	if result.Error != nil {
-		return nil, result.Error
+func holdOf(status string) stockHold {
+	switch status {
+	case models.OrderStatusPending:
+		return holdReserved
+	case models.OrderStatusExpired:
+		return holdNone
+	default:
+		return holdDeducted
+	}
+}
+
+// moveStock trả lại phần hàng current đang giữ rồi giữ theo sách, số lượng và trạng
+// thái của order. Đơn paid/refunded do luồng thanh toán và trả hàng quản lý tồn kho nên
+// không bị động tới. Reservation mới giữ nguyên hạn của reservation cũ, nếu không có thì
+// dùng order.ReservedUntil.
+func moveStock(tx *gorm.DB, current, order *models.Order) error {
+	if settledStatus(current.Status) || settledStatus(order.Status) {
+		return nil
+	}
+	from, to := holdOf(current.Status), holdOf(order.Status)
+	if from == to && current.BookID == order.BookID && current.Quantity == order.Quantity {
+		return nil
 	}
 
-	if result.RowsAffected == 0 {
-		return nil, fmt.Errorf("no order updated with id %d", order.ID)
+	// Khóa cả sách cũ và sách mới theo thứ tự id để hai cập nhật chéo không deadlock
+	var books []models.Book
+	if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+		Where("id IN ?", []uint{current.BookID, order.BookID}).Order("id").Find(&books).Error; err != nil {
+		return err
+	}
+	byID := make(map[uint]*models.Book, len(books))
+	for i := range books {
+		byID[books[i].ID] = &books[i]
+	}
+	book, ok := byID[order.BookID]
+	if !ok {
+		return fmt.Errorf("book not found: %w", gorm.ErrRecordNotFound)
 	}
 
-	updatedOrder, err := r.GetByOrderID(order.ID)
+	expiresAt := order.ReservedUntil
+	switch from {
+	case holdReserved:
+		var hold models.StockReservation
+		err := tx.Where("order_id = ?", current.ID).First(&hold).Error
+		if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+			return err
+		}
+		if err == nil {
+			expiresAt = &hold.ExpiresAt
+			if err := tx.Delete(&hold).Error; err != nil {
+				return fmt.Errorf("failed to release stock reservation: %w", err)
+			}
+		}
+	case holdDeducted:
+		if old, ok := byID[current.BookID]; ok {
+			if err := adjustStock(tx, old, current.Quantity); err != nil {
+				return err
+			}
+		}
+	}
+	if to == holdNone {
+		return nil
+	}
+
+	reserved, err := reservation.ReservedByBook(tx, []uint{book.ID}, time.Now())
 	if err != nil {
-		return nil, err
+		return err
+	}
+	if book.Stock-reserved[book.ID] < order.Quantity {
+		return repositories.ErrInsufficientStock
+	}
+	if to == holdDeducted {
+		return adjustStock(tx, book, -order.Quantity)
 	}
+	if expiresAt == nil {
+		return fmt.Errorf("%w: reservation deadline required to move order %d back to pending", repositories.ErrConflict, order.ID)
+	}
+	hold := models.StockReservation{OrderID: order.ID, BookID: book.ID, Quantity: order.Quantity, ExpiresAt: *expiresAt}
+	if err := tx.Create(&hold).Error; err != nil {
+		return fmt.Errorf("failed to reserve stock: %w", err)
+	}
+	return nil
+}
 
-	return updatedOrder, nil
+// adjustStock cộng delta vào stock của book (đã khóa) và ghi StockChanged
+func adjustStock(tx *gorm.DB, book *models.Book, delta int) error {
+	book.Stock += delta
+	if err := tx.Model(book).Update("stock", book.Stock).Error; err != nil {
+		return fmt.Errorf("failed to update book stock: %w", err)
+	}
+	return outbox.Append(tx, events.StockChanged{
+		BookID: book.ID,
+		Delta:  delta,
+		Stock:  book.Stock,
+		Reason: events.StockReasonUpdate,
+	})
 }
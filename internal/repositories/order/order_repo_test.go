@@ -1,15 +1,20 @@
 package order_test
 
 import (
+	"context"
 	"fmt"
 	"testing"
 	"time"
 
+	"github.com/shopspring/decimal"
 	"github.com/stretchr/testify/require"
 	"gorm.io/gorm"
 
+	"github.com/maithuc2003/Test_GIN_golang/internal/events"
+	"github.com/maithuc2003/Test_GIN_golang/internal/interfaces/repositories"
 	"github.com/maithuc2003/Test_GIN_golang/internal/models"
 	"github.com/maithuc2003/Test_GIN_golang/internal/repositories/order"
+	"github.com/maithuc2003/Test_GIN_golang/internal/repositories/reservation"
 	sqlitedriver "gorm.io/driver/sqlite"
 
 	_ "modernc.org/sqlite"
@@ -24,7 +29,7 @@ func setupTestDB(t *testing.T) *gorm.DB {
 	}), &gorm.Config{})
 
 	require.NoError(t, err)
-	require.NoError(t, db.AutoMigrate(&models.Book{}, &models.Order{}))
+	require.NoError(t, db.AutoMigrate(&models.Book{}, &models.Order{}, &models.OrderDiscount{}, &models.StockReservation{}, &models.Coupon{}, &models.CouponRedemption{}, &models.OutboxEvent{}))
 
 	return db
 }
@@ -79,7 +84,7 @@ func TestOrderRepo_Create(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			err := repo.Create(&tt.order)
+			err := repo.Create(context.Background(), &tt.order)
 			if tt.expectedErr != "" {
 				require.Error(t, err)
 				require.Contains(t, err.Error(), tt.expectedErr)
@@ -90,13 +95,107 @@ func TestOrderRepo_Create(t *testing.T) {
 	}
 }
 
+func TestOrderRepo_CreateReserved(t *testing.T) {
+	db := setupTestDB(t)
+	repo := order.NewOrderRepo(db)
+	book := seedBook(t, db, 5)
+	until := time.Now().Add(15 * time.Minute)
+
+	first := models.Order{BookID: book.ID, UserID: 1, Quantity: 3, Status: models.OrderStatusPending, ReservedUntil: &until}
+	require.NoError(t, repo.Create(context.Background(), &first))
+
+	// Stock chưa bị trừ nhưng phần đang giữ không bán tiếp được
+	var stored models.Book
+	require.NoError(t, db.First(&stored, book.ID).Error)
+	require.Equal(t, 5, stored.Stock)
+
+	var hold models.StockReservation
+	require.NoError(t, db.Where("order_id = ?", first.ID).First(&hold).Error)
+	require.Equal(t, 3, hold.Quantity)
+
+	second := models.Order{BookID: book.ID, UserID: 2, Quantity: 3, ReservedUntil: &until}
+	require.ErrorIs(t, repo.Create(context.Background(), &second), repositories.ErrInsufficientStock)
+
+	// Reservation đã hết hạn không còn chặn tồn kho
+	require.NoError(t, db.Model(&hold).Update("expires_at", time.Now().Add(-time.Minute)).Error)
+	require.NoError(t, repo.Create(context.Background(), &second))
+
+	_, err := repo.DeleteByOrderID(context.Background(), second.ID)
+	require.NoError(t, err)
+	var count int64
+	require.NoError(t, db.Model(&models.StockReservation{}).Where("order_id = ?", second.ID).Count(&count).Error)
+	require.Zero(t, count)
+}
+
+func TestOrderRepo_CreateWithDiscounts(t *testing.T) {
+	db := setupTestDB(t)
+	repo := order.NewOrderRepo(db)
+	book := seedBook(t, db, 5)
+
+	o := models.Order{
+		BookID: book.ID, UserID: 1, Quantity: 1, Status: "confirmed", Currency: "USD",
+		DiscountTotal: decimal.RequireFromString("7.50"),
+		Discounts: []models.OrderDiscount{
+			{CouponID: 1, Code: "FIVE", Description: "5.00 USD off", Amount: decimal.NewFromInt(5)},
+			{CouponID: 2, Code: "TENOFF", Description: "10% off", Amount: decimal.RequireFromString("2.50")},
+		},
+	}
+	require.NoError(t, repo.Create(context.Background(), &o))
+
+	stored, err := repo.GetByOrderID(context.Background(), o.ID)
+	require.NoError(t, err)
+	require.Equal(t, "USD", stored.Currency)
+	require.True(t, decimal.RequireFromString("7.50").Equal(stored.DiscountTotal))
+	require.Len(t, stored.Discounts, 2)
+	require.Equal(t, "FIVE", stored.Discounts[0].Code)
+
+	_, err = repo.DeleteByOrderID(context.Background(), o.ID)
+	require.NoError(t, err)
+	var count int64
+	require.NoError(t, db.Model(&models.OrderDiscount{}).Where("order_id = ?", o.ID).Count(&count).Error)
+	require.Zero(t, count)
+}
+
+func TestOrderRepo_UpdateStatus(t *testing.T) {
+	db := setupTestDB(t)
+	repo := order.NewOrderRepo(db)
+	book := seedBook(t, db, 10)
+	o := models.Order{BookID: book.ID, UserID: 1, Quantity: 1, Status: models.OrderStatusPending}
+	require.NoError(t, repo.Create(context.Background(), &o))
+
+	tests := []struct {
+		name      string
+		id        uint
+		from, to  string
+		wantErrIs error
+	}{
+		{name: "success", id: o.ID, from: models.OrderStatusPending, to: models.OrderStatusPaid},
+		{name: "status already changed", id: o.ID, from: models.OrderStatusPending, to: models.OrderStatusExpired, wantErrIs: repositories.ErrConflict},
+		{name: "order not found", id: 9999, from: models.OrderStatusPending, to: models.OrderStatusPaid, wantErrIs: repositories.ErrNotFound},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := repo.UpdateStatus(context.Background(), tt.id, tt.from, tt.to)
+			if tt.wantErrIs != nil {
+				require.ErrorIs(t, err, tt.wantErrIs)
+				return
+			}
+			require.NoError(t, err)
+			stored, err := repo.GetByOrderID(context.Background(), tt.id)
+			require.NoError(t, err)
+			require.Equal(t, tt.to, stored.Status)
+		})
+	}
+}
+
 func TestOrderRepo_GetByOrderID(t *testing.T) {
 	db := setupTestDB(t)
 	repo := order.NewOrderRepo(db)
 	book := seedBook(t, db, 5)
 
 	order := models.Order{BookID: book.ID, UserID: 1, Quantity: 1}
-	require.NoError(t, repo.Create(&order))
+	require.NoError(t, repo.Create(context.Background(), &order))
 
 	tests := []struct {
 		name        string
@@ -117,7 +216,7 @@ func TestOrderRepo_GetByOrderID(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			got, err := repo.GetByOrderID(tt.id)
+			got, err := repo.GetByOrderID(context.Background(), tt.id)
 			if tt.expectFound {
 				require.NoError(t, err)
 				require.Equal(t, order.BookID, got.BookID)
@@ -139,14 +238,21 @@ func TestOrderRepo_GetAllOrders(t *testing.T) {
 	}
 
 	for _, o := range orders {
-		require.NoError(t, repo.Create(&o))
+		require.NoError(t, repo.Create(context.Background(), &o))
 	}
 
 	t.Run("get all orders", func(t *testing.T) {
-		results, err := repo.GetAllOrders()
+		results, err := repo.GetAllOrders(context.Background(), models.OrderFilter{})
 		require.NoError(t, err)
 		require.Len(t, results, len(orders))
 	})
+
+	t.Run("filter by user", func(t *testing.T) {
+		results, err := repo.GetAllOrders(context.Background(), models.OrderFilter{UserID: 2, BookID: book.ID})
+		require.NoError(t, err)
+		require.Len(t, results, 1)
+		require.Equal(t, uint(2), results[0].UserID)
+	})
 }
 
 func TestOrderRepo_DeleteByOrderID(t *testing.T) {
@@ -155,7 +261,10 @@ func TestOrderRepo_DeleteByOrderID(t *testing.T) {
 	book := seedBook(t, db, 5)
 
 	order := models.Order{BookID: book.ID, UserID: 1, Quantity: 1}
-	require.NoError(t, repo.Create(&order))
+	require.NoError(t, repo.Create(context.Background(), &order))
+	c := models.Coupon{Code: "SALE", Type: models.CouponTypeFixed, Currency: "USD", UsedCount: 1, Active: true}
+	require.NoError(t, db.Create(&c).Error)
+	require.NoError(t, db.Create(&models.CouponRedemption{CouponID: c.ID, UserID: 1, OrderID: order.ID}).Error)
 
 	tests := []struct {
 		name        string
@@ -179,15 +288,18 @@ func TestOrderRepo_DeleteByOrderID(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			deleted, err := repo.DeleteByOrderID(tt.id)
+			deleted, err := repo.DeleteByOrderID(context.Background(), tt.id)
 			if tt.expectErr {
 				require.Error(t, err)
 			} else {
 				require.NoError(t, err)
 				require.Equal(t, tt.id, deleted.ID)
 
-				_, err := repo.GetByOrderID(tt.id)
+				_, err := repo.GetByOrderID(context.Background(), tt.id)
 				require.Error(t, err)
+
+				require.NoError(t, db.First(&c, c.ID).Error)
+				require.Zero(t, c.UsedCount)
 			}
 		})
 	}
@@ -199,7 +311,7 @@ func TestOrderRepo_UpdateByOrderID(t *testing.T) {
 	book := seedBook(t, db, 5)
 
 	order := models.Order{BookID: book.ID, UserID: 1, Quantity: 1, Status: "Pending"}
-	require.NoError(t, repo.Create(&order))
+	require.NoError(t, repo.Create(context.Background(), &order))
 
 	tests := []struct {
 		name        string
@@ -231,7 +343,7 @@ func TestOrderRepo_UpdateByOrderID(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			updated, err := repo.UpdateByOrderID(&tt.update)
+			updated, err := repo.UpdateByOrderID(context.Background(), &tt.update)
 			if tt.expectedErr != "" {
 				require.Error(t, err)
 				require.Contains(t, err.Error(), tt.expectedErr)
@@ -242,3 +354,188 @@ func TestOrderRepo_UpdateByOrderID(t *testing.T) {
 		})
 	}
 }
+
+func TestOrderRepo_UpdateByOrderID_PaidTotals(t *testing.T) {
+	db := setupTestDB(t)
+	repo := order.NewOrderRepo(db)
+	book := seedBook(t, db, 5)
+
+	o := models.Order{
+		BookID: book.ID, UserID: 1, Quantity: 1, Status: models.OrderStatusPaid, Currency: "USD",
+		UnitPrice: decimal.NewFromInt(10), Subtotal: decimal.NewFromInt(10), GrandTotal: decimal.NewFromInt(10),
+		Discounts: []models.OrderDiscount{{CouponID: 1, Code: "OLD", Amount: decimal.Zero}},
+	}
+	require.NoError(t, repo.Create(context.Background(), &o))
+
+	changed := o
+	changed.Quantity = 2
+	changed.GrandTotal = decimal.NewFromInt(20)
+	_, err := repo.UpdateByOrderID(context.Background(), &changed)
+	require.ErrorIs(t, err, repositories.ErrConflict)
+
+	// Về pending rồi đổi số lượng ở lần sau: bước đầu đã bị chặn
+	reopened := o
+	reopened.Status = models.OrderStatusPending
+	_, err = repo.UpdateByOrderID(context.Background(), &reopened)
+	require.ErrorIs(t, err, repositories.ErrConflict)
+	reopened.Quantity = 2
+	_, err = repo.UpdateByOrderID(context.Background(), &reopened)
+	require.ErrorIs(t, err, repositories.ErrConflict)
+
+	refunded := o
+	refunded.Status = models.OrderStatusRefunded
+	_, err = repo.UpdateByOrderID(context.Background(), &refunded)
+	require.ErrorIs(t, err, repositories.ErrConflict)
+
+	// Đơn đã trả tiền không chuyển được sang user khác
+	moved := o
+	moved.UserID = 2
+	_, err = repo.UpdateByOrderID(context.Background(), &moved)
+	require.ErrorIs(t, err, repositories.ErrConflict)
+
+	// Giữ nguyên trạng thái, người mua, sách và số lượng thì được cập nhật nhưng tiền và giảm giá không đổi
+	same := o
+	same.Subtotal = decimal.NewFromInt(1)
+	same.Discounts = []models.OrderDiscount{{CouponID: 2, Code: "NEW", Amount: decimal.Zero}}
+	updated, err := repo.UpdateByOrderID(context.Background(), &same)
+	require.NoError(t, err)
+	require.Equal(t, models.OrderStatusPaid, updated.Status)
+	require.Equal(t, uint(1), updated.UserID)
+	require.True(t, decimal.NewFromInt(10).Equal(updated.Subtotal))
+	require.True(t, decimal.NewFromInt(10).Equal(updated.GrandTotal))
+	require.Len(t, updated.Discounts, 1)
+	require.Equal(t, "OLD", updated.Discounts[0].Code)
+}
+
+func TestOrderRepo_UpdateByOrderID_Stock(t *testing.T) {
+	ctx := context.Background()
+	reservedUntil := time.Now().Add(time.Hour)
+
+	// reservedQty trả về số lượng reservation đang giữ cho đơn, 0 nếu không có
+	reservedQty := func(t *testing.T, db *gorm.DB, orderID uint) int {
+		var holds []models.StockReservation
+		require.NoError(t, db.Where("order_id = ?", orderID).Find(&holds).Error)
+		if len(holds) == 0 {
+			return 0
+		}
+		require.Len(t, holds, 1)
+		return holds[0].Quantity
+	}
+	stockOf := func(t *testing.T, db *gorm.DB, id uint) int {
+		var b models.Book
+		require.NoError(t, db.First(&b, id).Error)
+		return b.Stock
+	}
+
+	t.Run("pending quantity change rewrites reservation", func(t *testing.T) {
+		db := setupTestDB(t)
+		repo := order.NewOrderRepo(db)
+		book := seedBook(t, db, 5)
+		o := models.Order{BookID: book.ID, UserID: 1, Quantity: 1, Status: models.OrderStatusPending, ReservedUntil: &reservedUntil}
+		require.NoError(t, repo.Create(ctx, &o))
+
+		o.Quantity = 100
+		_, err := repo.UpdateByOrderID(ctx, &o)
+		require.ErrorIs(t, err, repositories.ErrInsufficientStock)
+		require.Equal(t, 1, reservedQty(t, db, o.ID), "failed update keeps the old hold")
+
+		o.Quantity = 5
+		_, err = repo.UpdateByOrderID(ctx, &o)
+		require.NoError(t, err)
+		require.Equal(t, 5, reservedQty(t, db, o.ID))
+		require.Equal(t, 5, stockOf(t, db, book.ID))
+	})
+
+	t.Run("pending book change moves reservation", func(t *testing.T) {
+		db := setupTestDB(t)
+		repo := order.NewOrderRepo(db)
+		first, second := seedBook(t, db, 5), seedBook(t, db, 2)
+		o := models.Order{BookID: first.ID, UserID: 1, Quantity: 2, Status: models.OrderStatusPending, ReservedUntil: &reservedUntil}
+		require.NoError(t, repo.Create(ctx, &o))
+
+		o.BookID = second.ID
+		_, err := repo.UpdateByOrderID(ctx, &o)
+		require.NoError(t, err)
+		reserved, err := reservation.ReservedByBook(db, []uint{first.ID, second.ID}, time.Now())
+		require.NoError(t, err)
+		require.Equal(t, map[uint]int{second.ID: 2}, reserved)
+	})
+
+	t.Run("leaving pending deducts stock", func(t *testing.T) {
+		db := setupTestDB(t)
+		repo := order.NewOrderRepo(db)
+		book := seedBook(t, db, 5)
+		o := models.Order{BookID: book.ID, UserID: 1, Quantity: 2, Status: models.OrderStatusPending, ReservedUntil: &reservedUntil}
+		require.NoError(t, repo.Create(ctx, &o))
+
+		o.Status = "shipped"
+		_, err := repo.UpdateByOrderID(ctx, &o)
+		require.NoError(t, err)
+		require.Zero(t, reservedQty(t, db, o.ID))
+		require.Equal(t, 3, stockOf(t, db, book.ID))
+
+		// Đổi số lượng của đơn đã trừ stock thì trừ phần chênh lệch
+		o.Quantity = 4
+		_, err = repo.UpdateByOrderID(ctx, &o)
+		require.NoError(t, err)
+		require.Equal(t, 1, stockOf(t, db, book.ID))
+	})
+
+	t.Run("expiring releases hold", func(t *testing.T) {
+		db := setupTestDB(t)
+		repo := order.NewOrderRepo(db)
+		book := seedBook(t, db, 5)
+		o := models.Order{BookID: book.ID, UserID: 1, Quantity: 2, Status: models.OrderStatusPending, ReservedUntil: &reservedUntil}
+		require.NoError(t, repo.Create(ctx, &o))
+
+		o.Status = models.OrderStatusExpired
+		_, err := repo.UpdateByOrderID(ctx, &o)
+		require.NoError(t, err)
+		require.Zero(t, reservedQty(t, db, o.ID))
+		require.Equal(t, 5, stockOf(t, db, book.ID))
+	})
+}
+
+// outboxTypes trả về loại các event đã ghi vào outbox theo thứ tự ghi
+func outboxTypes(t *testing.T, db *gorm.DB) []string {
+	var types []string
+	require.NoError(t, db.Model(&models.OutboxEvent{}).Order("id").Pluck("type", &types).Error)
+	return types
+}
+
+func TestOrderRepo_OutboxEvents(t *testing.T) {
+	db := setupTestDB(t)
+	repo := order.NewOrderRepo(db)
+	ctx := context.Background()
+	book := seedBook(t, db, 5)
+
+	o := models.Order{BookID: book.ID, UserID: 1, Quantity: 2, Status: models.OrderStatusPending}
+	require.NoError(t, repo.Create(ctx, &o))
+	require.Equal(t, []string{events.TypeOrderCreated, events.TypeStockChanged}, outboxTypes(t, db))
+
+	var stock models.OutboxEvent
+	require.NoError(t, db.Where("type = ?", events.TypeStockChanged).First(&stock).Error)
+	require.Equal(t, events.AggregateBook, stock.AggregateType)
+	require.JSONEq(t, fmt.Sprintf(`{"book_id":%d,"delta":-2,"stock":3,"reason":"order"}`, book.ID), stock.Payload)
+
+	// Đơn không tạo được thì không có event
+	require.Error(t, repo.Create(ctx, &models.Order{BookID: book.ID, UserID: 1, Quantity: 99}))
+	require.Len(t, outboxTypes(t, db), 2)
+
+	require.NoError(t, repo.UpdateStatus(ctx, o.ID, models.OrderStatusPending, models.OrderStatusPaid))
+	require.ErrorIs(t, repo.UpdateStatus(ctx, o.ID, models.OrderStatusPending, models.OrderStatusPaid), repositories.ErrConflict)
+	var changed models.OutboxEvent
+	require.NoError(t, db.Where("type = ?", events.TypeOrderStatusChanged).First(&changed).Error)
+	require.Equal(t, o.ID, changed.AggregateID)
+	require.JSONEq(t, fmt.Sprintf(`{"order_id":%d,"from":"pending","to":"paid"}`, o.ID), changed.Payload)
+
+	// Cập nhật không đổi trạng thái thì không ghi OrderStatusChanged
+	stored, err := repo.GetByOrderID(ctx, o.ID)
+	require.NoError(t, err)
+	_, err = repo.UpdateByOrderID(ctx, stored)
+	require.NoError(t, err)
+	require.NoError(t, repo.UpdateStatus(ctx, o.ID, models.OrderStatusPaid, models.OrderStatusRefunded))
+	require.Equal(t, []string{
+		events.TypeOrderCreated, events.TypeStockChanged, events.TypeOrderStatusChanged, events.TypeOrderStatusChanged,
+	}, outboxTypes(t, db))
+}
@@ -0,0 +1,97 @@
+package outbox
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/maithuc2003/Test_GIN_golang/internal/events"
+	"github.com/maithuc2003/Test_GIN_golang/internal/interfaces/repositories"
+	"github.com/maithuc2003/Test_GIN_golang/internal/models"
+	"github.com/maithuc2003/Test_GIN_golang/internal/tracing"
+
+	"gorm.io/gorm"
+)
+
+type outboxRepo struct {
+	db *gorm.DB
+}
+
+func NewOutboxRepo(db *gorm.DB) repositories.OutboxRepository {
+	return &outboxRepo{db: db}
+}
+
+// Append ghi các domain event vào outbox bằng db. Các repository gọi hàm này với
+// transaction đang thực hiện thay đổi để event chỉ tồn tại khi thay đổi được commit.
+func Append(db *gorm.DB, domain ...events.Domain) error {
+	if len(domain) == 0 {
+		return nil
+	}
+	now := time.Now()
+	records := make([]models.OutboxEvent, len(domain))
+	for i, e := range domain {
+		record, err := events.Record(e, now)
+		if err != nil {
+			return err
+		}
+		records[i] = record
+	}
+	if err := db.Create(&records).Error; err != nil {
+		return fmt.Errorf("failed to write outbox events: %w", err)
+	}
+	return nil
+}
+
+// Claim không dùng SELECT ... FOR UPDATE SKIP LOCKED (cần MySQL 8): mỗi event được nhận
+// bằng một UPDATE có điều kiện next_attempt_at <= now, nên khi hai dispatcher đọc trùng
+// thì chỉ bên cập nhật trước nhận được event
+func (r *outboxRepo) Claim(ctx context.Context, now time.Time, lease time.Duration, limit int) ([]models.OutboxEvent, error) {
+	ctx, span := tracing.Start(ctx, "outboxRepo.Claim")
+	defer span.End()
+
+	db := r.db.WithContext(ctx)
+	var due []models.OutboxEvent
+	if err := db.Where("delivered_at IS NULL AND next_attempt_at <= ?", now).
+		Order("id").Limit(limit).Find(&due).Error; err != nil {
+		return nil, fmt.Errorf("failed to load outbox events: %w", err)
+	}
+	claimed := due[:0]
+	for _, e := range due {
+		result := db.Model(&models.OutboxEvent{}).
+			Where("id = ? AND delivered_at IS NULL AND next_attempt_at <= ?", e.ID, now).
+			Update("next_attempt_at", now.Add(lease))
+		if result.Error != nil {
+			return nil, fmt.Errorf("failed to claim outbox event %d: %w", e.ID, result.Error)
+		}
+		if result.RowsAffected == 1 {
+			claimed = append(claimed, e)
+		}
+	}
+	return claimed, nil
+}
+
+func (r *outboxRepo) MarkDelivered(ctx context.Context, id uint, at time.Time) error {
+	ctx, span := tracing.Start(ctx, "outboxRepo.MarkDelivered")
+	defer span.End()
+
+	if err := r.db.WithContext(ctx).Model(&models.OutboxEvent{}).Where("id = ?", id).
+		Updates(map[string]interface{}{"delivered_at": at, "last_error": ""}).Error; err != nil {
+		return fmt.Errorf("failed to mark outbox event %d delivered: %w", id, err)
+	}
+	return nil
+}
+
+func (r *outboxRepo) MarkFailed(ctx context.Context, id uint, nextAttemptAt time.Time, reason string) error {
+	ctx, span := tracing.Start(ctx, "outboxRepo.MarkFailed")
+	defer span.End()
+
+	if err := r.db.WithContext(ctx).Model(&models.OutboxEvent{}).Where("id = ?", id).
+		Updates(map[string]interface{}{
+			"attempts":        gorm.Expr("attempts + 1"),
+			"next_attempt_at": nextAttemptAt,
+			"last_error":      reason,
+		}).Error; err != nil {
+		return fmt.Errorf("failed to reschedule outbox event %d: %w", id, err)
+	}
+	return nil
+}
@@ -0,0 +1,62 @@
+package outbox_test
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+
+	"github.com/maithuc2003/Test_GIN_golang/internal/events"
+	"github.com/maithuc2003/Test_GIN_golang/internal/models"
+	"github.com/maithuc2003/Test_GIN_golang/internal/repositories/outbox"
+	sqlitedriver "gorm.io/driver/sqlite"
+
+	_ "modernc.org/sqlite"
+)
+
+func setupTestDB(t *testing.T) *gorm.DB {
+	dsn := fmt.Sprintf("file:testdb_%d?mode=memory&cache=shared", time.Now().UnixNano())
+	db, err := gorm.Open(sqlitedriver.New(sqlitedriver.Config{DSN: dsn, DriverName: "sqlite"}), &gorm.Config{})
+	require.NoError(t, err)
+	require.NoError(t, db.AutoMigrate(&models.OutboxEvent{}))
+	return db
+}
+
+func TestOutboxRepo_Claim(t *testing.T) {
+	ctx := context.Background()
+	db := setupTestDB(t)
+	repo := outbox.NewOutboxRepo(db)
+
+	require.NoError(t, outbox.Append(db,
+		events.OrderStatusChanged{OrderID: 1, From: models.OrderStatusPending, To: models.OrderStatusPaid},
+		events.OrderStatusChanged{OrderID: 2, From: models.OrderStatusPending, To: models.OrderStatusExpired},
+		events.OrderStatusChanged{OrderID: 3, From: models.OrderStatusPending, To: models.OrderStatusPaid},
+	))
+	now := time.Now().Add(time.Second)
+
+	claimed, err := repo.Claim(ctx, now, time.Minute, 2)
+	require.NoError(t, err)
+	require.Len(t, claimed, 2)
+	require.Equal(t, uint(1), claimed[0].AggregateID, "oldest first")
+
+	// Dispatcher thứ hai chỉ nhận event chưa bị lease
+	other, err := repo.Claim(ctx, now, time.Minute, 10)
+	require.NoError(t, err)
+	require.Len(t, other, 1)
+	require.Equal(t, uint(3), other[0].AggregateID)
+
+	require.NoError(t, repo.MarkDelivered(ctx, claimed[0].ID, now))
+	require.NoError(t, repo.MarkFailed(ctx, claimed[1].ID, now, "sink unavailable"))
+
+	// Hết lease: event giao lỗi được nhận lại, event đã giao thì không
+	again, err := repo.Claim(ctx, now.Add(2*time.Minute), time.Minute, 10)
+	require.NoError(t, err)
+	require.Len(t, again, 2)
+	require.Equal(t, claimed[1].ID, again[0].ID)
+	require.Equal(t, 1, again[0].Attempts)
+	require.Equal(t, "sink unavailable", again[0].LastError)
+	require.Equal(t, other[0].ID, again[1].ID)
+}
@@ -0,0 +1,100 @@
+package payment
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/maithuc2003/Test_GIN_golang/internal/interfaces/repositories"
+	"github.com/maithuc2003/Test_GIN_golang/internal/models"
+	"github.com/maithuc2003/Test_GIN_golang/internal/tracing"
+	"github.com/shopspring/decimal"
+
+	"gorm.io/gorm"
+)
+
+type paymentRepo struct {
+	db *gorm.DB
+}
+
+func NewPaymentRepo(db *gorm.DB) repositories.PaymentRepository {
+	return &paymentRepo{db: db}
+}
+
+func (r *paymentRepo) Create(ctx context.Context, payment *models.Payment) error {
+	ctx, span := tracing.Start(ctx, "paymentRepo.Create")
+	defer span.End()
+
+	if err := r.db.WithContext(ctx).Create(payment).Error; err != nil {
+		return fmt.Errorf("failed to create payment: %w", err)
+	}
+	return nil
+}
+
+func (r *paymentRepo) Update(ctx context.Context, payment *models.Payment) error {
+	ctx, span := tracing.Start(ctx, "paymentRepo.Update")
+	defer span.End()
+
+	result := r.db.WithContext(ctx).Model(payment).
+		Select("status", "failure_reason", "refunded_amount", "updated_at").
+		Updates(payment)
+	if result.Error != nil {
+		return fmt.Errorf("failed to update payment: %w", result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return fmt.Errorf("%w: payment with ID %d not found", repositories.ErrNotFound, payment.ID)
+	}
+	return nil
+}
+
+func (r *paymentRepo) AddRefund(ctx context.Context, id uint, amount decimal.Decimal) (*models.Payment, error) {
+	ctx, span := tracing.Start(ctx, "paymentRepo.AddRefund")
+	defer span.End()
+
+	db := r.db.WithContext(ctx)
+	// status đứng trước refunded_amount vì MySQL gán lần lượt theo thứ tự trong SET
+	result := db.Exec(`UPDATE payments
+		SET status = CASE WHEN refunded_amount + ? >= amount THEN ? ELSE status END,
+			refunded_amount = refunded_amount + ?,
+			updated_at = ?
+		WHERE id = ? AND status = ?`,
+		amount, models.PaymentStatusRefunded, amount, time.Now(), id, models.PaymentStatusCaptured)
+	if result.Error != nil {
+		return nil, fmt.Errorf("failed to record refund: %w", result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return nil, fmt.Errorf("%w: payment %d is not captured", repositories.ErrConflict, id)
+	}
+	var payment models.Payment
+	if err := db.First(&payment, id).Error; err != nil {
+		return nil, err
+	}
+	return &payment, nil
+}
+
+func (r *paymentRepo) GetByReference(ctx context.Context, provider, reference string) (*models.Payment, error) {
+	ctx, span := tracing.Start(ctx, "paymentRepo.GetByReference")
+	defer span.End()
+
+	var payment models.Payment
+	err := r.db.WithContext(ctx).Where("provider = ? AND reference = ?", provider, reference).First(&payment).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, fmt.Errorf("%w: payment %s/%s not found", repositories.ErrNotFound, provider, reference)
+		}
+		return nil, err
+	}
+	return &payment, nil
+}
+
+func (r *paymentRepo) ListByOrder(ctx context.Context, orderID uint) ([]models.Payment, error) {
+	ctx, span := tracing.Start(ctx, "paymentRepo.ListByOrder")
+	defer span.End()
+
+	var payments []models.Payment
+	if err := r.db.WithContext(ctx).Where("order_id = ?", orderID).Order("id").Find(&payments).Error; err != nil {
+		return nil, fmt.Errorf("failed to query payments: %w", err)
+	}
+	return payments, nil
+}
@@ -0,0 +1,96 @@
+package payment_test
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+
+	"github.com/maithuc2003/Test_GIN_golang/internal/interfaces/repositories"
+	"github.com/maithuc2003/Test_GIN_golang/internal/models"
+	"github.com/maithuc2003/Test_GIN_golang/internal/repositories/payment"
+	sqlitedriver "gorm.io/driver/sqlite"
+
+	_ "modernc.org/sqlite"
+)
+
+func setupTestDB(t *testing.T) *gorm.DB {
+	dsn := fmt.Sprintf("file:testdb_%d?mode=memory&cache=shared", time.Now().UnixNano())
+
+	db, err := gorm.Open(sqlitedriver.New(sqlitedriver.Config{
+		DSN:        dsn,
+		DriverName: "sqlite",
+	}), &gorm.Config{})
+
+	require.NoError(t, err)
+	require.NoError(t, db.AutoMigrate(&models.Payment{}))
+	return db
+}
+
+func TestPaymentRepo(t *testing.T) {
+	ctx := context.Background()
+	db := setupTestDB(t)
+	repo := payment.NewPaymentRepo(db)
+
+	first := &models.Payment{OrderID: 1, Provider: "fake", Reference: "fake_a", Status: models.PaymentStatusFailed, Amount: decimal.RequireFromString("21.00"), Currency: "USD"}
+	second := &models.Payment{OrderID: 1, Provider: "fake", Reference: "fake_b", Status: models.PaymentStatusPending, Amount: decimal.RequireFromString("21.00"), Currency: "USD"}
+	require.NoError(t, repo.Create(ctx, first))
+	require.NoError(t, repo.Create(ctx, second))
+	require.NoError(t, repo.Create(ctx, &models.Payment{OrderID: 2, Provider: "fake", Reference: "fake_c", Status: models.PaymentStatusCaptured}))
+
+	t.Run("duplicate reference", func(t *testing.T) {
+		err := repo.Create(ctx, &models.Payment{OrderID: 3, Provider: "fake", Reference: "fake_a"})
+		require.Error(t, err)
+	})
+
+	t.Run("list by order", func(t *testing.T) {
+		payments, err := repo.ListByOrder(ctx, 1)
+		require.NoError(t, err)
+		require.Len(t, payments, 2)
+		require.Equal(t, "fake_a", payments[0].Reference)
+		require.True(t, payments[0].Amount.Equal(decimal.NewFromInt(21)))
+	})
+
+	t.Run("update status", func(t *testing.T) {
+		second.Status = models.PaymentStatusCaptured
+		require.NoError(t, repo.Update(ctx, second))
+
+		found, err := repo.GetByReference(ctx, "fake", "fake_b")
+		require.NoError(t, err)
+		require.Equal(t, models.PaymentStatusCaptured, found.Status)
+	})
+
+	t.Run("reference not found", func(t *testing.T) {
+		_, err := repo.GetByReference(ctx, "other", "fake_a")
+		require.ErrorIs(t, err, repositories.ErrNotFound)
+
+		err = repo.Update(ctx, &models.Payment{ID: 999, Status: models.PaymentStatusFailed})
+		require.ErrorIs(t, err, repositories.ErrNotFound)
+	})
+}
+
+func TestPaymentRepo_AddRefund(t *testing.T) {
+	ctx := context.Background()
+	db := setupTestDB(t)
+	repo := payment.NewPaymentRepo(db)
+
+	captured := &models.Payment{OrderID: 1, Provider: "fake", Reference: "fake_a", Status: models.PaymentStatusCaptured, Amount: decimal.RequireFromString("31.00"), Currency: "USD"}
+	require.NoError(t, repo.Create(ctx, captured))
+
+	updated, err := repo.AddRefund(ctx, captured.ID, decimal.RequireFromString("10.33"))
+	require.NoError(t, err)
+	require.Equal(t, models.PaymentStatusCaptured, updated.Status)
+	require.True(t, updated.RefundedAmount.Equal(decimal.RequireFromString("10.33")))
+
+	updated, err = repo.AddRefund(ctx, captured.ID, decimal.RequireFromString("20.67"))
+	require.NoError(t, err)
+	require.Equal(t, models.PaymentStatusRefunded, updated.Status)
+	require.True(t, updated.RefundedAmount.Equal(decimal.RequireFromString("31.00")))
+
+	_, err = repo.AddRefund(ctx, captured.ID, decimal.RequireFromString("1.00"))
+	require.ErrorIs(t, err, repositories.ErrConflict)
+}
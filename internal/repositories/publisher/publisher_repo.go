@@ -0,0 +1,87 @@
+package publisher
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/maithuc2003/Test_GIN_golang/internal/interfaces/repositories"
+	"github.com/maithuc2003/Test_GIN_golang/internal/models"
+	"github.com/maithuc2003/Test_GIN_golang/internal/repositories/dberr"
+	"github.com/maithuc2003/Test_GIN_golang/internal/tracing"
+
+	"gorm.io/gorm"
+)
+
+type publisherRepo struct {
+	db *gorm.DB
+}
+
+func NewPublisherRepo(db *gorm.DB) repositories.PublisherRepository {
+	return &publisherRepo{db: db}
+}
+
+func (r *publisherRepo) GetAllPublishers(ctx context.Context) ([]models.Publisher, error) {
+	ctx, span := tracing.Start(ctx, "publisherRepo.GetAllPublishers")
+	defer span.End()
+
+	var publishers []models.Publisher
+	if err := r.db.WithContext(ctx).Order("name").Find(&publishers).Error; err != nil {
+		return nil, fmt.Errorf("failed to query publishers: %w", err)
+	}
+	return publishers, nil
+}
+
+func (r *publisherRepo) GetByPublisherID(ctx context.Context, id uint) (*models.Publisher, error) {
+	ctx, span := tracing.Start(ctx, "publisherRepo.GetByPublisherID")
+	defer span.End()
+
+	var publisher models.Publisher
+	if err := r.db.WithContext(ctx).First(&publisher, id).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, fmt.Errorf("%w: publisher with ID %d not found", repositories.ErrNotFound, id)
+		}
+		return nil, fmt.Errorf("failed to fetch publisher: %w", err)
+	}
+	return &publisher, nil
+}
+
+func (r *publisherRepo) CreatePublisher(ctx context.Context, publisher *models.Publisher) error {
+	ctx, span := tracing.Start(ctx, "publisherRepo.CreatePublisher")
+	defer span.End()
+
+	if err := r.db.WithContext(ctx).Create(publisher).Error; err != nil {
+		if dberr.IsUniqueViolation(err) {
+			return duplicateName(publisher.Name)
+		}
+		return fmt.Errorf("failed to create publisher: %w", err)
+	}
+	return nil
+}
+
+func (r *publisherRepo) UpdateById(ctx context.Context, publisher *models.Publisher) (*models.Publisher, error) {
+	ctx, span := tracing.Start(ctx, "publisherRepo.UpdateById")
+	defer span.End()
+
+	existing, err := r.GetByPublisherID(ctx, publisher.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := r.db.WithContext(ctx).Model(existing).Updates(map[string]interface{}{
+		"name":       publisher.Name,
+		"country":    publisher.Country,
+		"website":    publisher.Website,
+		"updated_at": publisher.UpdatedAt,
+	}).Error; err != nil {
+		if dberr.IsUniqueViolation(err) {
+			return nil, duplicateName(publisher.Name)
+		}
+		return nil, fmt.Errorf("failed to update publisher: %w", err)
+	}
+	return existing, nil
+}
+
+func duplicateName(name string) error {
+	return fmt.Errorf("%w: publisher %q already exists", repositories.ErrConflict, name)
+}
@@ -0,0 +1,107 @@
+package publisher_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+
+	"github.com/maithuc2003/Test_GIN_golang/internal/interfaces/repositories"
+	"github.com/maithuc2003/Test_GIN_golang/internal/models"
+	"github.com/maithuc2003/Test_GIN_golang/internal/repositories/publisher"
+	sqlitedriver "gorm.io/driver/sqlite"
+
+	_ "modernc.org/sqlite"
+)
+
+func setupTestDB(t *testing.T) *gorm.DB {
+	dsn := fmt.Sprintf("file:testdb_%d?mode=memory&cache=shared", time.Now().UnixNano())
+
+	db, err := gorm.Open(sqlitedriver.New(sqlitedriver.Config{
+		DSN:        dsn,
+		DriverName: "sqlite",
+	}), &gorm.Config{})
+
+	require.NoError(t, err)
+	require.NoError(t, db.AutoMigrate(&models.Publisher{}))
+
+	return db
+}
+
+func TestPublisherRepo_CreateAndGet(t *testing.T) {
+	db := setupTestDB(t)
+	repo := publisher.NewPublisherRepo(db)
+	ctx := context.Background()
+
+	kimDong := models.Publisher{Name: "NXB Kim Đồng", Country: "VN"}
+	require.NoError(t, repo.CreatePublisher(ctx, &kimDong))
+	require.NoError(t, repo.CreatePublisher(ctx, &models.Publisher{Name: "NXB Trẻ", Country: "VN"}))
+
+	tests := []struct {
+		name      string
+		input     models.Publisher
+		expectErr error
+	}{
+		{name: "duplicate name", input: models.Publisher{Name: "NXB Kim Đồng"}, expectErr: repositories.ErrConflict},
+		{name: "new publisher", input: models.Publisher{Name: "Penguin", Country: "UK"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := repo.CreatePublisher(ctx, &tt.input)
+			if tt.expectErr != nil {
+				require.True(t, errors.Is(err, tt.expectErr))
+				return
+			}
+			require.NoError(t, err)
+			require.NotZero(t, tt.input.ID)
+		})
+	}
+
+	all, err := repo.GetAllPublishers(ctx)
+	require.NoError(t, err)
+	require.Len(t, all, 3)
+
+	got, err := repo.GetByPublisherID(ctx, kimDong.ID)
+	require.NoError(t, err)
+	require.Equal(t, "NXB Kim Đồng", got.Name)
+
+	_, err = repo.GetByPublisherID(ctx, 999)
+	require.True(t, errors.Is(err, repositories.ErrNotFound))
+}
+
+func TestPublisherRepo_UpdateById(t *testing.T) {
+	db := setupTestDB(t)
+	repo := publisher.NewPublisherRepo(db)
+	ctx := context.Background()
+
+	first := models.Publisher{Name: "NXB Trẻ"}
+	require.NoError(t, repo.CreatePublisher(ctx, &first))
+	second := models.Publisher{Name: "NXB Văn Học"}
+	require.NoError(t, repo.CreatePublisher(ctx, &second))
+
+	tests := []struct {
+		name      string
+		input     models.Publisher
+		expectErr error
+	}{
+		{name: "update success", input: models.Publisher{ID: first.ID, Name: "Nhà xuất bản Trẻ", Country: "VN"}},
+		{name: "rename to existing", input: models.Publisher{ID: second.ID, Name: "Nhà xuất bản Trẻ"}, expectErr: repositories.ErrConflict},
+		{name: "not found", input: models.Publisher{ID: 999, Name: "Ghost"}, expectErr: repositories.ErrNotFound},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := repo.UpdateById(ctx, &tt.input)
+			if tt.expectErr != nil {
+				require.True(t, errors.Is(err, tt.expectErr), err)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tt.input.Name, got.Name)
+			require.Equal(t, "VN", got.Country)
+		})
+	}
+}
@@ -0,0 +1,149 @@
+package reservation
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/maithuc2003/Test_GIN_golang/internal/events"
+	"github.com/maithuc2003/Test_GIN_golang/internal/interfaces/repositories"
+	"github.com/maithuc2003/Test_GIN_golang/internal/models"
+	"github.com/maithuc2003/Test_GIN_golang/internal/repositories/coupon"
+	"github.com/maithuc2003/Test_GIN_golang/internal/repositories/outbox"
+	"github.com/maithuc2003/Test_GIN_golang/internal/tracing"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type reservationRepo struct {
+	db *gorm.DB
+}
+
+func NewReservationRepo(db *gorm.DB) repositories.ReservationRepository {
+	return &reservationRepo{db: db}
+}
+
+// ReservedByBook trả về tổng số cuốn đang được giữ (reservation chưa hết hạn) của từng sách
+func ReservedByBook(db *gorm.DB, bookIDs []uint, now time.Time) (map[uint]int, error) {
+	reserved := make(map[uint]int, len(bookIDs))
+	if len(bookIDs) == 0 {
+		return reserved, nil
+	}
+	var rows []struct {
+		BookID   uint
+		Reserved int
+	}
+	err := db.Model(&models.StockReservation{}).
+		Select("book_id, SUM(quantity) AS reserved").
+		Where("book_id IN ? AND expires_at > ?", bookIDs, now).
+		Group("book_id").Scan(&rows).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to load reserved stock: %w", err)
+	}
+	for _, row := range rows {
+		reserved[row.BookID] = row.Reserved
+	}
+	return reserved, nil
+}
+
+// FillAvailability điền ReservedStock và AvailableStock cho các sách đã nạp
+func FillAvailability(db *gorm.DB, books []*models.Book) error {
+	ids := make([]uint, len(books))
+	for i, b := range books {
+		ids[i] = b.ID
+	}
+	reserved, err := ReservedByBook(db, ids, time.Now())
+	if err != nil {
+		return err
+	}
+	for _, b := range books {
+		b.ReservedStock = reserved[b.ID]
+		b.AvailableStock = b.Stock - b.ReservedStock
+		if b.AvailableStock < 0 {
+			b.AvailableStock = 0
+		}
+	}
+	return nil
+}
+
+func (r *reservationRepo) Commit(ctx context.Context, orderID uint) error {
+	ctx, span := tracing.Start(ctx, "reservationRepo.Commit")
+	defer span.End()
+
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var reservation models.StockReservation
+		err := tx.Where("order_id = ? AND expires_at > ?", orderID, time.Now()).First(&reservation).Error
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return fmt.Errorf("%w: no active stock reservation for order %d", repositories.ErrNotFound, orderID)
+		}
+		if err != nil {
+			return err
+		}
+
+		var book models.Book
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).First(&book, reservation.BookID).Error; err != nil {
+			return fmt.Errorf("book not found: %w", err)
+		}
+		// Tồn kho có thể đã bị sửa tay xuống thấp hơn số đang giữ
+		if book.Stock < reservation.Quantity {
+			return repositories.ErrInsufficientStock
+		}
+		remaining := book.Stock - reservation.Quantity
+		if err := tx.Model(&book).Update("stock", remaining).Error; err != nil {
+			return fmt.Errorf("failed to update book stock: %w", err)
+		}
+		if err := outbox.Append(tx, events.StockChanged{
+			BookID: book.ID,
+			Delta:  -reservation.Quantity,
+			Stock:  remaining,
+			Reason: events.StockReasonPayment,
+		}); err != nil {
+			return err
+		}
+		return tx.Delete(&reservation).Error
+	})
+}
+
+func (r *reservationRepo) Release(ctx context.Context, orderID uint) error {
+	ctx, span := tracing.Start(ctx, "reservationRepo.Release")
+	defer span.End()
+
+	return r.db.WithContext(ctx).Where("order_id = ?", orderID).Delete(&models.StockReservation{}).Error
+}
+
+func (r *reservationRepo) ReleaseExpired(ctx context.Context, now time.Time) (int64, error) {
+	ctx, span := tracing.Start(ctx, "reservationRepo.ReleaseExpired")
+	defer span.End()
+
+	var released int64
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		expired := tx.Model(&models.StockReservation{}).Select("order_id").Where("expires_at <= ?", now)
+		var orderIDs []uint
+		if err := tx.Model(&models.Order{}).Clauses(clause.Locking{Strength: "UPDATE"}).
+			Where("id IN (?) AND status = ?", expired, models.OrderStatusPending).
+			Pluck("id", &orderIDs).Error; err != nil {
+			return fmt.Errorf("failed to load expired orders: %w", err)
+		}
+		if len(orderIDs) > 0 {
+			if err := tx.Model(&models.Order{}).Where("id IN ?", orderIDs).
+				Update("status", models.OrderStatusExpired).Error; err != nil {
+				return fmt.Errorf("failed to expire orders: %w", err)
+			}
+			changes := make([]events.Domain, len(orderIDs))
+			for i, id := range orderIDs {
+				changes[i] = events.OrderStatusChanged{OrderID: id, From: models.OrderStatusPending, To: models.OrderStatusExpired}
+			}
+			if err := outbox.Append(tx, changes...); err != nil {
+				return err
+			}
+			if err := coupon.ReleaseRedemptions(tx, orderIDs...); err != nil {
+				return err
+			}
+		}
+		result := tx.Where("expires_at <= ?", now).Delete(&models.StockReservation{})
+		released = result.RowsAffected
+		return result.Error
+	})
+	return released, err
+}
@@ -0,0 +1,160 @@
+package reservation_test
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+
+	"github.com/maithuc2003/Test_GIN_golang/internal/events"
+	"github.com/maithuc2003/Test_GIN_golang/internal/interfaces/repositories"
+	"github.com/maithuc2003/Test_GIN_golang/internal/models"
+	"github.com/maithuc2003/Test_GIN_golang/internal/repositories/reservation"
+	sqlitedriver "gorm.io/driver/sqlite"
+
+	_ "modernc.org/sqlite"
+)
+
+func setupTestDB(t *testing.T) *gorm.DB {
+	dsn := fmt.Sprintf("file:testdb_%d?mode=memory&cache=shared", time.Now().UnixNano())
+
+	db, err := gorm.Open(sqlitedriver.New(sqlitedriver.Config{
+		DSN:        dsn,
+		DriverName: "sqlite",
+	}), &gorm.Config{})
+
+	require.NoError(t, err)
+	require.NoError(t, db.AutoMigrate(&models.Book{}, &models.Order{}, &models.OrderDiscount{}, &models.StockReservation{}, &models.Coupon{}, &models.CouponRedemption{}, &models.OutboxEvent{}))
+	return db
+}
+
+// seedReservation tạo sách, đơn pending và reservation hết hạn sau ttl
+func seedReservation(t *testing.T, db *gorm.DB, stock, quantity int, ttl time.Duration) (models.Book, models.Order) {
+	book := models.Book{Title: "Test Book", Stock: stock, AuthorID: 1}
+	require.NoError(t, db.Create(&book).Error)
+	order := models.Order{BookID: book.ID, UserID: 1, Quantity: quantity, Status: models.OrderStatusPending}
+	require.NoError(t, db.Create(&order).Error)
+	require.NoError(t, db.Create(&models.StockReservation{
+		OrderID:   order.ID,
+		BookID:    book.ID,
+		Quantity:  quantity,
+		ExpiresAt: time.Now().Add(ttl),
+	}).Error)
+	return book, order
+}
+
+func TestReservationRepo_Commit(t *testing.T) {
+	tests := []struct {
+		name          string
+		stock         int
+		ttl           time.Duration
+		expectedErr   error
+		expectedStock int
+	}{
+		{name: "success", stock: 5, ttl: time.Minute, expectedStock: 3},
+		{name: "expired", stock: 5, ttl: -time.Minute, expectedErr: repositories.ErrNotFound, expectedStock: 5},
+		{name: "stock lowered below reservation", stock: 1, ttl: time.Minute, expectedErr: repositories.ErrInsufficientStock, expectedStock: 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			db := setupTestDB(t)
+			repo := reservation.NewReservationRepo(db)
+			book, order := seedReservation(t, db, tt.stock, 2, tt.ttl)
+
+			err := repo.Commit(context.Background(), order.ID)
+			if tt.expectedErr != nil {
+				require.ErrorIs(t, err, tt.expectedErr)
+			} else {
+				require.NoError(t, err)
+				var count int64
+				require.NoError(t, db.Model(&models.StockReservation{}).Count(&count).Error)
+				require.Zero(t, count)
+			}
+
+			var stored models.Book
+			require.NoError(t, db.First(&stored, book.ID).Error)
+			require.Equal(t, tt.expectedStock, stored.Stock)
+		})
+	}
+}
+
+func TestReservationRepo_Release(t *testing.T) {
+	db := setupTestDB(t)
+	repo := reservation.NewReservationRepo(db)
+	book, order := seedReservation(t, db, 5, 2, time.Minute)
+
+	require.NoError(t, repo.Release(context.Background(), order.ID))
+	// Gọi lại khi không còn reservation vẫn không lỗi
+	require.NoError(t, repo.Release(context.Background(), order.ID))
+
+	reserved, err := reservation.ReservedByBook(db, []uint{book.ID}, time.Now())
+	require.NoError(t, err)
+	require.Zero(t, reserved[book.ID])
+}
+
+func TestReservationRepo_ReleaseExpired(t *testing.T) {
+	db := setupTestDB(t)
+	repo := reservation.NewReservationRepo(db)
+	_, expired := seedReservation(t, db, 5, 2, -time.Minute)
+	_, active := seedReservation(t, db, 5, 1, time.Hour)
+	c := models.Coupon{Code: "SALE", Type: models.CouponTypeFixed, Currency: "USD", UsedCount: 2, Active: true}
+	require.NoError(t, db.Create(&c).Error)
+	for _, o := range []models.Order{expired, active} {
+		require.NoError(t, db.Create(&models.CouponRedemption{CouponID: c.ID, UserID: o.UserID, OrderID: o.ID}).Error)
+	}
+
+	released, err := repo.ReleaseExpired(context.Background(), time.Now())
+	require.NoError(t, err)
+	require.Equal(t, int64(1), released)
+
+	var expiredOrder, activeOrder models.Order
+	require.NoError(t, db.First(&expiredOrder, expired.ID).Error)
+	require.Equal(t, models.OrderStatusExpired, expiredOrder.Status)
+	require.NoError(t, db.First(&activeOrder, active.ID).Error)
+	require.Equal(t, models.OrderStatusPending, activeOrder.Status)
+
+	var count int64
+	require.NoError(t, db.Model(&models.StockReservation{}).Count(&count).Error)
+	require.Equal(t, int64(1), count)
+
+	// Lượt dùng coupon của đơn hết hạn được trả lại
+	require.NoError(t, db.First(&c, c.ID).Error)
+	require.Equal(t, 1, c.UsedCount)
+	require.NoError(t, db.Model(&models.CouponRedemption{}).Where("order_id = ?", expired.ID).Count(&count).Error)
+	require.Zero(t, count)
+}
+
+func TestFillAvailability(t *testing.T) {
+	db := setupTestDB(t)
+	book, _ := seedReservation(t, db, 5, 2, time.Minute)
+	seedReservation(t, db, 5, 1, -time.Minute)
+
+	books := []*models.Book{&book}
+	require.NoError(t, reservation.FillAvailability(db, books))
+	require.Equal(t, 2, book.ReservedStock)
+	require.Equal(t, 3, book.AvailableStock)
+}
+
+func TestReservationRepo_OutboxEvents(t *testing.T) {
+	db := setupTestDB(t)
+	repo := reservation.NewReservationRepo(db)
+	ctx := context.Background()
+	book, paid := seedReservation(t, db, 5, 2, time.Minute)
+	_, expired := seedReservation(t, db, 5, 1, -time.Minute)
+
+	require.NoError(t, repo.Commit(ctx, paid.ID))
+	_, err := repo.ReleaseExpired(ctx, time.Now())
+	require.NoError(t, err)
+
+	var records []models.OutboxEvent
+	require.NoError(t, db.Order("id").Find(&records).Error)
+	require.Len(t, records, 2)
+	require.Equal(t, events.TypeStockChanged, records[0].Type)
+	require.JSONEq(t, fmt.Sprintf(`{"book_id":%d,"delta":-2,"stock":3,"reason":"payment"}`, book.ID), records[0].Payload)
+	require.Equal(t, events.TypeOrderStatusChanged, records[1].Type)
+	require.JSONEq(t, fmt.Sprintf(`{"order_id":%d,"from":"pending","to":"expired"}`, expired.ID), records[1].Payload)
+}
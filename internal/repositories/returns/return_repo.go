@@ -0,0 +1,100 @@
+package returns
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/maithuc2003/Test_GIN_golang/internal/interfaces/repositories"
+	"github.com/maithuc2003/Test_GIN_golang/internal/models"
+	"github.com/maithuc2003/Test_GIN_golang/internal/tracing"
+
+	"gorm.io/gorm"
+)
+
+type returnRepo struct {
+	db *gorm.DB
+}
+
+func NewReturnRepo(db *gorm.DB) repositories.ReturnRepository {
+	return &returnRepo{db: db}
+}
+
+func (r *returnRepo) Create(ctx context.Context, ret *models.ReturnRequest) error {
+	ctx, span := tracing.Start(ctx, "returnRepo.Create")
+	defer span.End()
+
+	if err := r.db.WithContext(ctx).Create(ret).Error; err != nil {
+		return fmt.Errorf("failed to create return request: %w", err)
+	}
+	return nil
+}
+
+func (r *returnRepo) GetByID(ctx context.Context, id uint) (*models.ReturnRequest, error) {
+	ctx, span := tracing.Start(ctx, "returnRepo.GetByID")
+	defer span.End()
+
+	var ret models.ReturnRequest
+	if err := r.db.WithContext(ctx).First(&ret, id).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, fmt.Errorf("%w: return request with ID %d not found", repositories.ErrNotFound, id)
+		}
+		return nil, err
+	}
+	return &ret, nil
+}
+
+func (r *returnRepo) List(ctx context.Context, filter models.ReturnFilter) ([]models.ReturnRequest, error) {
+	ctx, span := tracing.Start(ctx, "returnRepo.List")
+	defer span.End()
+
+	query := r.db.WithContext(ctx).Order("id")
+	if filter.OrderID > 0 {
+		query = query.Where("order_id = ?", filter.OrderID)
+	}
+	if filter.Status != "" {
+		query = query.Where("status = ?", filter.Status)
+	}
+	var returns []models.ReturnRequest
+	if err := query.Find(&returns).Error; err != nil {
+		return nil, fmt.Errorf("failed to query return requests: %w", err)
+	}
+	return returns, nil
+}
+
+func (r *returnRepo) ReturnedQuantity(ctx context.Context, orderID uint, statuses ...string) (int, error) {
+	ctx, span := tracing.Start(ctx, "returnRepo.ReturnedQuantity")
+	defer span.End()
+
+	var total int
+	err := r.db.WithContext(ctx).Model(&models.ReturnRequest{}).
+		Select("COALESCE(SUM(quantity), 0)").
+		Where("order_id = ? AND status IN ?", orderID, statuses).
+		Scan(&total).Error
+	if err != nil {
+		return 0, fmt.Errorf("failed to sum returned quantity: %w", err)
+	}
+	return total, nil
+}
+
+func (r *returnRepo) SaveReview(ctx context.Context, ret *models.ReturnRequest) error {
+	ctx, span := tracing.Start(ctx, "returnRepo.SaveReview")
+	defer span.End()
+
+	result := r.db.WithContext(ctx).Model(&models.ReturnRequest{}).
+		Where("id = ? AND status = ?", ret.ID, models.ReturnStatusRequested).
+		Updates(map[string]interface{}{
+			"status":        ret.Status,
+			"refund_amount": ret.RefundAmount,
+			"reviewed_by":   ret.ReviewedBy,
+			"review_note":   ret.ReviewNote,
+			"reviewed_at":   ret.ReviewedAt,
+		})
+	if result.Error != nil {
+		return fmt.Errorf("failed to save return review: %w", result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return fmt.Errorf("%w: return request %d has already been reviewed", repositories.ErrConflict, ret.ID)
+	}
+	return nil
+}
@@ -0,0 +1,95 @@
+package returns_test
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+
+	"github.com/maithuc2003/Test_GIN_golang/internal/interfaces/repositories"
+	"github.com/maithuc2003/Test_GIN_golang/internal/models"
+	"github.com/maithuc2003/Test_GIN_golang/internal/repositories/returns"
+	sqlitedriver "gorm.io/driver/sqlite"
+
+	_ "modernc.org/sqlite"
+)
+
+func setupTestDB(t *testing.T) *gorm.DB {
+	dsn := fmt.Sprintf("file:testdb_%d?mode=memory&cache=shared", time.Now().UnixNano())
+
+	db, err := gorm.Open(sqlitedriver.New(sqlitedriver.Config{
+		DSN:        dsn,
+		DriverName: "sqlite",
+	}), &gorm.Config{})
+
+	require.NoError(t, err)
+	require.NoError(t, db.AutoMigrate(&models.ReturnRequest{}))
+	return db
+}
+
+func TestReturnRepo(t *testing.T) {
+	ctx := context.Background()
+	db := setupTestDB(t)
+	repo := returns.NewReturnRepo(db)
+
+	seed := []*models.ReturnRequest{
+		{OrderID: 1, UserID: 7, Quantity: 1, Reason: "damaged", Status: models.ReturnStatusApproved},
+		{OrderID: 1, UserID: 7, Quantity: 2, Reason: "wrong book", Status: models.ReturnStatusRequested},
+		{OrderID: 1, UserID: 7, Quantity: 4, Reason: "changed mind", Status: models.ReturnStatusRejected},
+		{OrderID: 2, UserID: 8, Quantity: 1, Reason: "damaged", Status: models.ReturnStatusRequested},
+	}
+	for _, ret := range seed {
+		require.NoError(t, repo.Create(ctx, ret))
+	}
+
+	t.Run("returned quantity", func(t *testing.T) {
+		total, err := repo.ReturnedQuantity(ctx, 1, models.ReturnStatusRequested, models.ReturnStatusApproved)
+		require.NoError(t, err)
+		require.Equal(t, 3, total)
+
+		total, err = repo.ReturnedQuantity(ctx, 3, models.ReturnStatusApproved)
+		require.NoError(t, err)
+		require.Zero(t, total)
+	})
+
+	t.Run("list with filter", func(t *testing.T) {
+		list, err := repo.List(ctx, models.ReturnFilter{Status: models.ReturnStatusRequested})
+		require.NoError(t, err)
+		require.Len(t, list, 2)
+
+		list, err = repo.List(ctx, models.ReturnFilter{OrderID: 1})
+		require.NoError(t, err)
+		require.Len(t, list, 3)
+	})
+
+	t.Run("save review only once", func(t *testing.T) {
+		ret, err := repo.GetByID(ctx, seed[1].ID)
+		require.NoError(t, err)
+
+		amount := decimal.RequireFromString("21.00")
+		reviewer := uint(1)
+		now := time.Now()
+		ret.Status = models.ReturnStatusApproved
+		ret.RefundAmount = &amount
+		ret.ReviewedBy = &reviewer
+		ret.ReviewedAt = &now
+		require.NoError(t, repo.SaveReview(ctx, ret))
+
+		stored, err := repo.GetByID(ctx, ret.ID)
+		require.NoError(t, err)
+		require.Equal(t, models.ReturnStatusApproved, stored.Status)
+		require.True(t, stored.RefundAmount.Equal(amount))
+
+		ret.Status = models.ReturnStatusRejected
+		require.ErrorIs(t, repo.SaveReview(ctx, ret), repositories.ErrConflict)
+	})
+
+	t.Run("not found", func(t *testing.T) {
+		_, err := repo.GetByID(ctx, 999)
+		require.ErrorIs(t, err, repositories.ErrNotFound)
+	})
+}
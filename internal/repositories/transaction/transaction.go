@@ -0,0 +1,62 @@
+package transaction
+
+import (
+	"context"
+
+	"github.com/maithuc2003/Test_GIN_golang/internal/interfaces/repositories"
+	"github.com/maithuc2003/Test_GIN_golang/internal/repositories/author"
+	"github.com/maithuc2003/Test_GIN_golang/internal/repositories/book"
+	"github.com/maithuc2003/Test_GIN_golang/internal/repositories/cart"
+	"github.com/maithuc2003/Test_GIN_golang/internal/repositories/coupon"
+	"github.com/maithuc2003/Test_GIN_golang/internal/repositories/invoice"
+	"github.com/maithuc2003/Test_GIN_golang/internal/repositories/order"
+	"github.com/maithuc2003/Test_GIN_golang/internal/repositories/payment"
+	"github.com/maithuc2003/Test_GIN_golang/internal/repositories/reservation"
+	"github.com/maithuc2003/Test_GIN_golang/internal/repositories/returns"
+	"github.com/maithuc2003/Test_GIN_golang/internal/repositories/user"
+	"github.com/maithuc2003/Test_GIN_golang/internal/tracing"
+
+	"gorm.io/gorm"
+)
+
+type txKey struct{}
+
+type txManager struct {
+	db *gorm.DB
+}
+
+func NewTransactionManager(db *gorm.DB) repositories.TransactionManager {
+	return &txManager{db: db}
+}
+
+func (m *txManager) WithinTransaction(ctx context.Context, fn func(ctx context.Context, repos repositories.Repositories) error) error {
+	ctx, span := tracing.Start(ctx, "txManager.WithinTransaction")
+	defer span.End()
+
+	db := m.db
+	// Đã có transaction trong ctx -> GORM tự dùng SAVEPOINT cho lần gọi lồng
+	if tx, ok := ctx.Value(txKey{}).(*gorm.DB); ok {
+		db = tx
+	}
+
+	return db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		txCtx := context.WithValue(ctx, txKey{}, tx)
+		return fn(txCtx, NewRepositories(tx))
+	})
+}
+
+// NewRepositories tạo các repository dùng chung kết nối db (hoặc tx) được truyền vào
+func NewRepositories(db *gorm.DB) repositories.Repositories {
+	return repositories.Repositories{
+		Authors:      author.NewAuthorRepo(db),
+		Books:        book.NewRepository(db),
+		Carts:        cart.NewCartRepo(db),
+		Coupons:      coupon.NewCouponRepo(db),
+		Invoices:     invoice.NewInvoiceRepo(db),
+		Orders:       order.NewOrderRepo(db),
+		Payments:     payment.NewPaymentRepo(db),
+		Reservations: reservation.NewReservationRepo(db),
+		Returns:      returns.NewReturnRepo(db),
+		Users:        user.NewRepository(db),
+	}
+}
@@ -0,0 +1,131 @@
+package transaction_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+
+	"github.com/maithuc2003/Test_GIN_golang/internal/interfaces/repositories"
+	"github.com/maithuc2003/Test_GIN_golang/internal/models"
+	"github.com/maithuc2003/Test_GIN_golang/internal/repositories/transaction"
+	sqlitedriver "gorm.io/driver/sqlite"
+
+	_ "modernc.org/sqlite"
+)
+
+func setupTestDB(t *testing.T) *gorm.DB {
+	dsn := fmt.Sprintf("file:testdb_%d?mode=memory&cache=shared", time.Now().UnixNano())
+
+	db, err := gorm.Open(sqlitedriver.New(sqlitedriver.Config{
+		DSN:        dsn,
+		DriverName: "sqlite",
+	}), &gorm.Config{})
+
+	require.NoError(t, err)
+	require.NoError(t, db.AutoMigrate(&models.Author{}, &models.Book{}, &models.Order{}, &models.OrderDiscount{}, &models.StockReservation{}, &models.OutboxEvent{}))
+
+	return db
+}
+
+func countRows(t *testing.T, db *gorm.DB, model interface{}) int64 {
+	var n int64
+	require.NoError(t, db.Model(model).Count(&n).Error)
+	return n
+}
+
+func createAuthorAndBook(ctx context.Context, repos repositories.Repositories) error {
+	author := &models.Author{Name: "Nguyen Nhat Anh"}
+	if err := repos.Authors.CreateAuthor(ctx, author); err != nil {
+		return err
+	}
+	return repos.Books.CreateBook(ctx, &models.Book{Title: "Mat Biec", Stock: 5, AuthorID: author.ID})
+}
+
+func TestWithinTransaction(t *testing.T) {
+	tests := []struct {
+		name        string
+		fn          func(ctx context.Context, repos repositories.Repositories) error
+		expectErr   bool
+		expectPanic bool
+		wantAuthors int64
+		wantBooks   int64
+	}{
+		{
+			name:        "commit",
+			fn:          createAuthorAndBook,
+			wantAuthors: 1,
+			wantBooks:   1,
+		},
+		{
+			name: "rollback on error",
+			fn: func(ctx context.Context, repos repositories.Repositories) error {
+				if err := createAuthorAndBook(ctx, repos); err != nil {
+					return err
+				}
+				return errors.New("boom")
+			},
+			expectErr: true,
+		},
+		{
+			name: "rollback on panic",
+			fn: func(ctx context.Context, repos repositories.Repositories) error {
+				if err := createAuthorAndBook(ctx, repos); err != nil {
+					return err
+				}
+				panic("boom")
+			},
+			expectPanic: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			db := setupTestDB(t)
+			tm := transaction.NewTransactionManager(db)
+
+			run := func() error { return tm.WithinTransaction(context.Background(), tt.fn) }
+			if tt.expectPanic {
+				require.Panics(t, func() { _ = run() })
+			} else if err := run(); tt.expectErr {
+				require.Error(t, err)
+			} else {
+				require.NoError(t, err)
+			}
+
+			require.Equal(t, tt.wantAuthors, countRows(t, db, &models.Author{}))
+			require.Equal(t, tt.wantBooks, countRows(t, db, &models.Book{}))
+		})
+	}
+}
+
+func TestWithinTransaction_NestedSavepoint(t *testing.T) {
+	db := setupTestDB(t)
+	tm := transaction.NewTransactionManager(db)
+
+	err := tm.WithinTransaction(context.Background(), func(ctx context.Context, repos repositories.Repositories) error {
+		if err := repos.Authors.CreateAuthor(ctx, &models.Author{Name: "Outer"}); err != nil {
+			return err
+		}
+
+		// Lỗi ở transaction lồng chỉ rollback về savepoint
+		nestedErr := tm.WithinTransaction(ctx, func(ctx context.Context, repos repositories.Repositories) error {
+			if err := repos.Authors.CreateAuthor(ctx, &models.Author{Name: "Inner"}); err != nil {
+				return err
+			}
+			return errors.New("inner failed")
+		})
+		require.Error(t, nestedErr)
+		return nil
+	})
+	require.NoError(t, err)
+
+	var authors []models.Author
+	require.NoError(t, db.Find(&authors).Error)
+	require.Len(t, authors, 1)
+	require.Equal(t, "Outer", authors[0].Name)
+}
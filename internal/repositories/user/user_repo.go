@@ -1,8 +1,14 @@
 package user
 
 import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
 	"github.com/maithuc2003/Test_GIN_golang/internal/interfaces/repositories"
 	"github.com/maithuc2003/Test_GIN_golang/internal/models"
+	"github.com/maithuc2003/Test_GIN_golang/internal/tracing"
 	"gorm.io/gorm"
 )
 
@@ -14,18 +20,73 @@ func NewRepository(db *gorm.DB) repositories.UserRepository {
 	return &userRepo{db: db}
 }
 
-func (r *userRepo) GetByUsername(username string) (*models.User, error) {
+func (r *userRepo) GetByID(ctx context.Context, id uint) (*models.User, error) {
+	ctx, span := tracing.Start(ctx, "userRepo.GetByID")
+	defer span.End()
+
+	var user models.User
+	if err := r.db.WithContext(ctx).First(&user, id).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, fmt.Errorf("%w: user with ID %d not found", repositories.ErrNotFound, id)
+		}
+		return nil, err
+	}
+	return &user, nil
+}
+
+func (r *userRepo) GetByUsername(ctx context.Context, username string) (*models.User, error) {
+	ctx, span := tracing.Start(ctx, "userRepo.GetByUsername")
+	defer span.End()
+
 	var user models.User
-	if err := r.db.Where("username = ? ", username).First(&user).Error; err != nil {
+	if err := r.db.WithContext(ctx).Where("username = ? ", username).First(&user).Error; err != nil {
 		return nil, err
 	}
 	return &user, nil
 }
 
-func (r *userRepo) LoginUser(username string, password string) (*models.User, error) {
+func (r *userRepo) LoginUser(ctx context.Context, username string, password string) (*models.User, error) {
+	ctx, span := tracing.Start(ctx, "userRepo.LoginUser")
+	defer span.End()
+
 	var user models.User
-	if err := r.db.Where("username = ?", username).First(&user).Error; err != nil {
+	if err := r.db.WithContext(ctx).Where("username = ?", username).First(&user).Error; err != nil {
 		return nil, err
 	}
 	return &user, nil
 }
+
+func (r *userRepo) RecordFailedLogin(ctx context.Context, id uint) (int, error) {
+	ctx, span := tracing.Start(ctx, "userRepo.RecordFailedLogin")
+	defer span.End()
+
+	var failures int
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		// Tăng trong câu UPDATE để các lần đăng nhập song song không đếm trùng
+		if err := tx.Model(&models.User{}).Where("id = ?", id).
+			UpdateColumn("failed_logins", gorm.Expr("failed_logins + 1")).Error; err != nil {
+			return err
+		}
+		return tx.Model(&models.User{}).Where("id = ?", id).Pluck("failed_logins", &failures).Error
+	})
+	if err != nil {
+		return 0, fmt.Errorf("failed to record failed login: %w", err)
+	}
+	return failures, nil
+}
+
+func (r *userRepo) LockUntil(ctx context.Context, id uint, until time.Time) error {
+	ctx, span := tracing.Start(ctx, "userRepo.LockUntil")
+	defer span.End()
+
+	return r.db.WithContext(ctx).Model(&models.User{}).Where("id = ?", id).
+		UpdateColumn("locked_until", until).Error
+}
+
+func (r *userRepo) ResetFailedLogins(ctx context.Context, id uint) error {
+	ctx, span := tracing.Start(ctx, "userRepo.ResetFailedLogins")
+	defer span.End()
+
+	return r.db.WithContext(ctx).Model(&models.User{}).Where("id = ?", id).
+		UpdateColumns(map[string]interface{}{"failed_logins": 0, "locked_until": nil}).Error
+}
@@ -1,9 +1,12 @@
 package user_test
 
 import (
+	"context"
 	"testing"
+	"time"
 
 	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/maithuc2003/Test_GIN_golang/internal/interfaces/repositories"
 	Repo "github.com/maithuc2003/Test_GIN_golang/internal/repositories/user"
 	"github.com/stretchr/testify/require"
 	"gorm.io/driver/postgres"
@@ -60,7 +63,7 @@ func TestGetByUsername(t *testing.T) {
 			tt.mockExpectFn(mock, tt.username)
 			repo := Repo.NewRepository(db)
 
-			_, err := repo.GetByUsername(tt.username)
+			_, err := repo.GetByUsername(context.Background(), tt.username)
 			if tt.expectErr {
 				require.Error(t, err)
 			} else {
@@ -72,6 +75,54 @@ func TestGetByUsername(t *testing.T) {
 	}
 }
 
+func TestGetByID(t *testing.T) {
+	tests := []struct {
+		name         string
+		id           uint
+		mockExpectFn func(sqlmock.Sqlmock, uint)
+		expectErr    error
+	}{
+		{
+			name: "user found",
+			id:   7,
+			mockExpectFn: func(mock sqlmock.Sqlmock, id uint) {
+				rows := sqlmock.NewRows([]string{"id", "username", "password"}).AddRow(id, "john", "hashed_pw")
+				mock.ExpectQuery(`SELECT \* FROM "users" WHERE "users"\."id" = \$1 ORDER BY "users"\."id" LIMIT \$2`).
+					WithArgs(id, 1).
+					WillReturnRows(rows)
+			},
+		},
+		{
+			name: "user not found",
+			id:   8,
+			mockExpectFn: func(mock sqlmock.Sqlmock, id uint) {
+				mock.ExpectQuery(`SELECT \* FROM "users" WHERE "users"\."id" = \$1 ORDER BY "users"\."id" LIMIT \$2`).
+					WithArgs(id, 1).
+					WillReturnError(gorm.ErrRecordNotFound)
+			},
+			expectErr: repositories.ErrNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			db, mock := newMockDB(t)
+			tt.mockExpectFn(mock, tt.id)
+			repo := Repo.NewRepository(db)
+
+			user, err := repo.GetByID(context.Background(), tt.id)
+			if tt.expectErr != nil {
+				require.ErrorIs(t, err, tt.expectErr)
+			} else {
+				require.NoError(t, err)
+				require.Equal(t, tt.id, user.ID)
+			}
+
+			require.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
+
 func TestLoginUser(t *testing.T) {
 	tests := []struct {
 		name         string
@@ -110,7 +161,7 @@ func TestLoginUser(t *testing.T) {
 			tt.mockExpectFn(mock, tt.username)
 			repo := Repo.NewRepository(db)
 
-			_, err := repo.LoginUser(tt.username, "any_password")
+			_, err := repo.LoginUser(context.Background(), tt.username, "any_password")
 			if tt.expectErr {
 				require.Error(t, err)
 			} else {
@@ -121,3 +172,40 @@ func TestLoginUser(t *testing.T) {
 		})
 	}
 }
+
+func TestRecordFailedLogin(t *testing.T) {
+	db, mock := newMockDB(t)
+	mock.ExpectBegin()
+	mock.ExpectExec(`UPDATE "users" SET "failed_logins"=failed_logins \+ 1 WHERE id = \$1`).
+		WithArgs(1).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectQuery(`SELECT "failed_logins" FROM "users" WHERE id = \$1`).
+		WithArgs(1).
+		WillReturnRows(sqlmock.NewRows([]string{"failed_logins"}).AddRow(4))
+	mock.ExpectCommit()
+
+	failures, err := Repo.NewRepository(db).RecordFailedLogin(context.Background(), 1)
+	require.NoError(t, err)
+	require.Equal(t, 4, failures)
+	require.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestLockUntilAndReset(t *testing.T) {
+	db, mock := newMockDB(t)
+	until := time.Now().Add(time.Minute)
+	mock.ExpectBegin()
+	mock.ExpectExec(`UPDATE "users" SET "locked_until"=\$1 WHERE id = \$2`).
+		WithArgs(until, 1).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+	mock.ExpectBegin()
+	mock.ExpectExec(`UPDATE "users" SET "failed_logins"=\$1,"locked_until"=\$2 WHERE id = \$3`).
+		WithArgs(0, nil, 1).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+
+	repo := Repo.NewRepository(db)
+	require.NoError(t, repo.LockUntil(context.Background(), 1, until))
+	require.NoError(t, repo.ResetFailedLogins(context.Background(), 1))
+	require.NoError(t, mock.ExpectationsWereMet())
+}
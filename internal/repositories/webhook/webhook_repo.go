@@ -0,0 +1,219 @@
+package webhook
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/maithuc2003/Test_GIN_golang/internal/interfaces/repositories"
+	"github.com/maithuc2003/Test_GIN_golang/internal/models"
+	"github.com/maithuc2003/Test_GIN_golang/internal/tracing"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type webhookRepo struct {
+	db *gorm.DB
+}
+
+func NewWebhookRepo(db *gorm.DB) repositories.WebhookRepository {
+	return &webhookRepo{db: db}
+}
+
+func (r *webhookRepo) ListSubscriptions(ctx context.Context) ([]models.WebhookSubscription, error) {
+	ctx, span := tracing.Start(ctx, "webhookRepo.ListSubscriptions")
+	defer span.End()
+
+	var subs []models.WebhookSubscription
+	if err := r.db.WithContext(ctx).Order("id").Find(&subs).Error; err != nil {
+		return nil, fmt.Errorf("failed to list webhook subscriptions: %w", err)
+	}
+	return subs, nil
+}
+
+// ActiveSubscriptions lọc loại event trong Go vì event_types được lưu dạng JSON
+func (r *webhookRepo) ActiveSubscriptions(ctx context.Context, eventType string) ([]models.WebhookSubscription, error) {
+	ctx, span := tracing.Start(ctx, "webhookRepo.ActiveSubscriptions")
+	defer span.End()
+
+	var subs []models.WebhookSubscription
+	if err := r.db.WithContext(ctx).Where("active = ?", true).Order("id").Find(&subs).Error; err != nil {
+		return nil, fmt.Errorf("failed to load webhook subscriptions: %w", err)
+	}
+	matched := subs[:0]
+	for _, sub := range subs {
+		if slices.Contains(sub.EventTypes, eventType) {
+			matched = append(matched, sub)
+		}
+	}
+	return matched, nil
+}
+
+func (r *webhookRepo) GetSubscription(ctx context.Context, id uint) (*models.WebhookSubscription, error) {
+	ctx, span := tracing.Start(ctx, "webhookRepo.GetSubscription")
+	defer span.End()
+
+	var sub models.WebhookSubscription
+	if err := r.db.WithContext(ctx).First(&sub, id).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, fmt.Errorf("%w: webhook subscription %d not found", repositories.ErrNotFound, id)
+		}
+		return nil, fmt.Errorf("failed to fetch webhook subscription: %w", err)
+	}
+	return &sub, nil
+}
+
+func (r *webhookRepo) CreateSubscription(ctx context.Context, sub *models.WebhookSubscription) error {
+	ctx, span := tracing.Start(ctx, "webhookRepo.CreateSubscription")
+	defer span.End()
+
+	if err := r.db.WithContext(ctx).Create(sub).Error; err != nil {
+		return fmt.Errorf("failed to create webhook subscription: %w", err)
+	}
+	return nil
+}
+
+func (r *webhookRepo) UpdateSubscription(ctx context.Context, sub *models.WebhookSubscription) (*models.WebhookSubscription, error) {
+	ctx, span := tracing.Start(ctx, "webhookRepo.UpdateSubscription")
+	defer span.End()
+
+	columns := []string{"url", "event_types", "description", "active", "updated_at"}
+	if sub.Secret != "" {
+		columns = append(columns, "secret")
+	}
+	result := r.db.WithContext(ctx).Model(&models.WebhookSubscription{ID: sub.ID}).Select(columns).Updates(sub)
+	if result.Error != nil {
+		return nil, fmt.Errorf("failed to update webhook subscription: %w", result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return nil, fmt.Errorf("%w: webhook subscription %d not found", repositories.ErrNotFound, sub.ID)
+	}
+	return r.GetSubscription(ctx, sub.ID)
+}
+
+func (r *webhookRepo) DeleteSubscription(ctx context.Context, id uint) (*models.WebhookSubscription, error) {
+	ctx, span := tracing.Start(ctx, "webhookRepo.DeleteSubscription")
+	defer span.End()
+
+	sub, err := r.GetSubscription(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	err = r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		deliveries := tx.Model(&models.WebhookDelivery{}).Select("id").Where("subscription_id = ?", id)
+		if err := tx.Where("delivery_id IN (?)", deliveries).Delete(&models.WebhookDeliveryAttempt{}).Error; err != nil {
+			return err
+		}
+		if err := tx.Where("subscription_id = ?", id).Delete(&models.WebhookDelivery{}).Error; err != nil {
+			return err
+		}
+		return tx.Delete(&models.WebhookSubscription{}, id).Error
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to delete webhook subscription: %w", err)
+	}
+	return sub, nil
+}
+
+func (r *webhookRepo) EnqueueDeliveries(ctx context.Context, deliveries []models.WebhookDelivery) error {
+	ctx, span := tracing.Start(ctx, "webhookRepo.EnqueueDeliveries")
+	defer span.End()
+
+	if len(deliveries) == 0 {
+		return nil
+	}
+	if err := r.db.WithContext(ctx).Clauses(clause.OnConflict{DoNothing: true}).Create(&deliveries).Error; err != nil {
+		return fmt.Errorf("failed to enqueue webhook deliveries: %w", err)
+	}
+	return nil
+}
+
+// ClaimDeliveries nhận từng delivery bằng UPDATE có điều kiện next_attempt_at <= now thay
+// cho SKIP LOCKED (cần MySQL 8); delivery đã bị worker khác nhận trước thì bị bỏ qua
+func (r *webhookRepo) ClaimDeliveries(ctx context.Context, now time.Time, lease time.Duration, limit int) ([]models.WebhookDelivery, error) {
+	ctx, span := tracing.Start(ctx, "webhookRepo.ClaimDeliveries")
+	defer span.End()
+
+	db := r.db.WithContext(ctx)
+	var due []models.WebhookDelivery
+	if err := db.Where("status = ? AND next_attempt_at <= ?", models.WebhookDeliveryPending, now).
+		Order("id").Limit(limit).Find(&due).Error; err != nil {
+		return nil, fmt.Errorf("failed to load webhook deliveries: %w", err)
+	}
+	claimed := due[:0]
+	for _, d := range due {
+		result := db.Model(&models.WebhookDelivery{}).
+			Where("id = ? AND status = ? AND next_attempt_at <= ?", d.ID, models.WebhookDeliveryPending, now).
+			Update("next_attempt_at", now.Add(lease))
+		if result.Error != nil {
+			return nil, fmt.Errorf("failed to claim webhook delivery %d: %w", d.ID, result.Error)
+		}
+		if result.RowsAffected == 1 {
+			claimed = append(claimed, d)
+		}
+	}
+	return claimed, nil
+}
+
+func (r *webhookRepo) RecordAttempt(ctx context.Context, delivery *models.WebhookDelivery, attempt *models.WebhookDeliveryAttempt) error {
+	ctx, span := tracing.Start(ctx, "webhookRepo.RecordAttempt")
+	defer span.End()
+
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		attempt.DeliveryID = delivery.ID
+		if err := tx.Create(attempt).Error; err != nil {
+			return fmt.Errorf("failed to record webhook attempt: %w", err)
+		}
+		if err := tx.Model(&models.WebhookDelivery{ID: delivery.ID}).
+			Select("status", "attempts", "response_code", "last_error", "next_attempt_at", "delivered_at", "updated_at").
+			Updates(delivery).Error; err != nil {
+			return fmt.Errorf("failed to update webhook delivery: %w", err)
+		}
+		return nil
+	})
+}
+
+func (r *webhookRepo) ListDeliveries(ctx context.Context, subscriptionID uint, limit int) ([]models.WebhookDelivery, error) {
+	ctx, span := tracing.Start(ctx, "webhookRepo.ListDeliveries")
+	defer span.End()
+
+	if _, err := r.GetSubscription(ctx, subscriptionID); err != nil {
+		return nil, err
+	}
+	var deliveries []models.WebhookDelivery
+	if err := r.db.WithContext(ctx).Where("subscription_id = ?", subscriptionID).
+		Preload("Log", func(db *gorm.DB) *gorm.DB { return db.Order("id") }).
+		Order("id DESC").Limit(limit).Find(&deliveries).Error; err != nil {
+		return nil, fmt.Errorf("failed to list webhook deliveries: %w", err)
+	}
+	return deliveries, nil
+}
+
+func (r *webhookRepo) Redeliver(ctx context.Context, subscriptionID, deliveryID uint, now time.Time) (*models.WebhookDelivery, error) {
+	ctx, span := tracing.Start(ctx, "webhookRepo.Redeliver")
+	defer span.End()
+
+	result := r.db.WithContext(ctx).Model(&models.WebhookDelivery{}).
+		Where("id = ? AND subscription_id = ?", deliveryID, subscriptionID).
+		Updates(map[string]interface{}{
+			"status":          models.WebhookDeliveryPending,
+			"attempts":        0,
+			"next_attempt_at": now,
+			"delivered_at":    nil,
+		})
+	if result.Error != nil {
+		return nil, fmt.Errorf("failed to redeliver webhook: %w", result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return nil, fmt.Errorf("%w: webhook delivery %d not found", repositories.ErrNotFound, deliveryID)
+	}
+	var delivery models.WebhookDelivery
+	if err := r.db.WithContext(ctx).Preload("Log", func(db *gorm.DB) *gorm.DB { return db.Order("id") }).
+		First(&delivery, deliveryID).Error; err != nil {
+		return nil, fmt.Errorf("failed to fetch webhook delivery: %w", err)
+	}
+	return &delivery, nil
+}
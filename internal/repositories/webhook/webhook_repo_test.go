@@ -0,0 +1,133 @@
+package webhook_test
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+
+	"github.com/maithuc2003/Test_GIN_golang/internal/interfaces/repositories"
+	"github.com/maithuc2003/Test_GIN_golang/internal/models"
+	"github.com/maithuc2003/Test_GIN_golang/internal/repositories/webhook"
+	sqlitedriver "gorm.io/driver/sqlite"
+
+	_ "modernc.org/sqlite"
+)
+
+func setupTestDB(t *testing.T) *gorm.DB {
+	dsn := fmt.Sprintf("file:testdb_%d?mode=memory&cache=shared", time.Now().UnixNano())
+	db, err := gorm.Open(sqlitedriver.New(sqlitedriver.Config{DSN: dsn, DriverName: "sqlite"}), &gorm.Config{})
+	require.NoError(t, err)
+	require.NoError(t, db.AutoMigrate(&models.WebhookSubscription{}, &models.WebhookDelivery{}, &models.WebhookDeliveryAttempt{}))
+	return db
+}
+
+func TestSubscriptions(t *testing.T) {
+	ctx := context.Background()
+	db := setupTestDB(t)
+	repo := webhook.NewWebhookRepo(db)
+
+	orders := models.WebhookSubscription{URL: "https://a.example/hook", EventTypes: []string{"OrderCreated", "OrderStatusChanged"}, Secret: "secret-1", Active: true}
+	stock := models.WebhookSubscription{URL: "https://b.example/hook", EventTypes: []string{"StockChanged"}, Secret: "secret-2", Active: true}
+	off := models.WebhookSubscription{URL: "https://c.example/hook", EventTypes: []string{"OrderCreated"}, Secret: "secret-3"}
+	for _, sub := range []*models.WebhookSubscription{&orders, &stock, &off} {
+		require.NoError(t, repo.CreateSubscription(ctx, sub))
+	}
+
+	active, err := repo.ActiveSubscriptions(ctx, "OrderCreated")
+	require.NoError(t, err)
+	require.Len(t, active, 1)
+	require.Equal(t, orders.ID, active[0].ID)
+	require.Equal(t, []string{"OrderCreated", "OrderStatusChanged"}, active[0].EventTypes)
+
+	t.Run("update keeps secret when empty", func(t *testing.T) {
+		updated, err := repo.UpdateSubscription(ctx, &models.WebhookSubscription{ID: orders.ID, URL: "https://a.example/v2", EventTypes: []string{"OrderCreated"}})
+		require.NoError(t, err)
+		require.Equal(t, "https://a.example/v2", updated.URL)
+		require.False(t, updated.Active)
+		require.Equal(t, "secret-1", updated.Secret)
+
+		updated, err = repo.UpdateSubscription(ctx, &models.WebhookSubscription{ID: orders.ID, URL: "https://a.example/v2", EventTypes: []string{"OrderCreated"}, Secret: "rotated", Active: true})
+		require.NoError(t, err)
+		require.Equal(t, "rotated", updated.Secret)
+
+		_, err = repo.UpdateSubscription(ctx, &models.WebhookSubscription{ID: 99, URL: "https://x.example"})
+		require.ErrorIs(t, err, repositories.ErrNotFound)
+	})
+
+	t.Run("delete removes delivery log", func(t *testing.T) {
+		require.NoError(t, repo.EnqueueDeliveries(ctx, []models.WebhookDelivery{{SubscriptionID: stock.ID, EventID: 1, Status: models.WebhookDeliveryPending}}))
+		require.NoError(t, db.Create(&models.WebhookDeliveryAttempt{DeliveryID: 1, ResponseCode: 500}).Error)
+
+		deleted, err := repo.DeleteSubscription(ctx, stock.ID)
+		require.NoError(t, err)
+		require.Equal(t, stock.URL, deleted.URL)
+		var deliveries, attempts int64
+		require.NoError(t, db.Model(&models.WebhookDelivery{}).Count(&deliveries).Error)
+		require.NoError(t, db.Model(&models.WebhookDeliveryAttempt{}).Count(&attempts).Error)
+		require.Zero(t, deliveries)
+		require.Zero(t, attempts)
+
+		_, err = repo.DeleteSubscription(ctx, stock.ID)
+		require.ErrorIs(t, err, repositories.ErrNotFound)
+	})
+}
+
+func TestDeliveries(t *testing.T) {
+	ctx := context.Background()
+	db := setupTestDB(t)
+	repo := webhook.NewWebhookRepo(db)
+	sub := models.WebhookSubscription{URL: "https://a.example/hook", EventTypes: []string{"OrderCreated"}, Secret: "secret", Active: true}
+	require.NoError(t, repo.CreateSubscription(ctx, &sub))
+
+	now := time.Now()
+	pending := func(eventID uint, due time.Time) models.WebhookDelivery {
+		return models.WebhookDelivery{SubscriptionID: sub.ID, EventID: eventID, EventType: "OrderCreated", Body: "{}", Status: models.WebhookDeliveryPending, NextAttemptAt: due}
+	}
+	require.NoError(t, repo.EnqueueDeliveries(ctx, []models.WebhookDelivery{pending(1, now.Add(-time.Second)), pending(2, now.Add(time.Hour))}))
+	// Enqueue lại cùng event được bỏ qua
+	require.NoError(t, repo.EnqueueDeliveries(ctx, []models.WebhookDelivery{pending(1, now.Add(-time.Second))}))
+
+	claimed, err := repo.ClaimDeliveries(ctx, now, time.Minute, 10)
+	require.NoError(t, err)
+	require.Len(t, claimed, 1)
+	require.Equal(t, uint(1), claimed[0].EventID)
+
+	again, err := repo.ClaimDeliveries(ctx, now, time.Minute, 10)
+	require.NoError(t, err)
+	require.Empty(t, again, "claimed delivery is leased")
+
+	// Hết lease mà worker chưa ghi kết quả thì delivery được nhận lại
+	expired, err := repo.ClaimDeliveries(ctx, now.Add(2*time.Minute), time.Minute, 10)
+	require.NoError(t, err)
+	require.Len(t, expired, 1)
+	require.Equal(t, claimed[0].ID, expired[0].ID)
+
+	d := claimed[0]
+	d.Status = models.WebhookDeliveryFailed
+	d.Attempts = 1
+	d.ResponseCode = 500
+	d.LastError = "endpoint responded 500"
+	require.NoError(t, repo.RecordAttempt(ctx, &d, &models.WebhookDeliveryAttempt{ResponseCode: 500, CreatedAt: now}))
+
+	deliveries, err := repo.ListDeliveries(ctx, sub.ID, 10)
+	require.NoError(t, err)
+	require.Len(t, deliveries, 2)
+	require.Equal(t, uint(2), deliveries[0].EventID, "newest first")
+	require.Equal(t, models.WebhookDeliveryFailed, deliveries[1].Status)
+	require.Len(t, deliveries[1].Log, 1)
+
+	redelivered, err := repo.Redeliver(ctx, sub.ID, d.ID, now)
+	require.NoError(t, err)
+	require.Equal(t, models.WebhookDeliveryPending, redelivered.Status)
+	require.Zero(t, redelivered.Attempts)
+	require.Len(t, redelivered.Log, 1)
+
+	_, err = repo.Redeliver(ctx, sub.ID+1, d.ID, now)
+	require.ErrorIs(t, err, repositories.ErrNotFound)
+	_, err = repo.ListDeliveries(ctx, sub.ID+1, 10)
+	require.ErrorIs(t, err, repositories.ErrNotFound)
+}
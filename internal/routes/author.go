@@ -7,13 +7,14 @@ import (
 	ServiceInterface "github.com/maithuc2003/Test_GIN_golang/internal/interfaces/service"
 	"github.com/maithuc2003/Test_GIN_golang/internal/middleware"
 	Repo "github.com/maithuc2003/Test_GIN_golang/internal/repositories/author"
+	TxManager "github.com/maithuc2003/Test_GIN_golang/internal/repositories/transaction"
 	ServiceImp "github.com/maithuc2003/Test_GIN_golang/internal/service/author"
 	"gorm.io/gorm"
 )
 
 func RegisterAuthorRoutes(r *gin.Engine, db *gorm.DB) {
 	var authorRepo RepInterface.AuthorRepositoriesInterface = Repo.NewAuthorRepo(db)
-	var authorService ServiceInterface.AuthorServiceInterface = ServiceImp.NewAuthorService(authorRepo)
+	var authorService ServiceInterface.AuthorServiceInterface = ServiceImp.NewAuthorService(authorRepo, TxManager.NewTransactionManager(db))
 	authorHandler := author.NewAuthorHandler(authorService)
 
 	// // Public routes
@@ -40,6 +41,7 @@ func RegisterAuthorRoutes(r *gin.Engine, db *gorm.DB) {
 		auth.POST("/add", middleware.RBACMiddleware("author/create"), authorHandler.CreateAuthor)
 		auth.PUT("/:id", middleware.RBACMiddleware("author/update"), authorHandler.UpdateById)
 		auth.DELETE("/:id", middleware.RBACMiddleware("author/delete"), authorHandler.DeleteById)
+		auth.POST("/:id/merge", middleware.RBACMiddleware("author/merge"), authorHandler.MergeAuthors)
 	}
 
 }
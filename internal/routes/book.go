@@ -22,6 +22,8 @@ func RegisterBookRoutes(r *gin.Engine, db *gorm.DB) {
 		bookRoutes.GET("", bookHandler.GetAllBooksHandler)
 		bookRoutes.GET("/:id", bookHandler.GetByBookID)
 	}
+	r.GET("/authors/:id/books", bookHandler.GetBooksByAuthor)
+	r.GET("/categories/:id/books", bookHandler.GetBooksByCategory)
 
 	// Protected routes with Auth + RBAC
 	auth := r.Group("/books", middleware.AuthMiddleware())
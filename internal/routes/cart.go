@@ -0,0 +1,47 @@
+package routes
+
+import (
+	"context"
+	"log/slog"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/maithuc2003/Test_GIN_golang/internal/handler/cart"
+	RepInterface "github.com/maithuc2003/Test_GIN_golang/internal/interfaces/repositories"
+	ServiceInterface "github.com/maithuc2003/Test_GIN_golang/internal/interfaces/service"
+	"github.com/maithuc2003/Test_GIN_golang/internal/middleware"
+	"github.com/maithuc2003/Test_GIN_golang/internal/pricing"
+	BookRepo "github.com/maithuc2003/Test_GIN_golang/internal/repositories/book"
+	Repo "github.com/maithuc2003/Test_GIN_golang/internal/repositories/cart"
+	TxManager "github.com/maithuc2003/Test_GIN_golang/internal/repositories/transaction"
+	ServiceImp "github.com/maithuc2003/Test_GIN_golang/internal/service/cart"
+	"github.com/maithuc2003/Test_GIN_golang/internal/worker"
+	"gorm.io/gorm"
+)
+
+// cartPurgeInterval là chu kỳ dọn các giỏ hàng đã hết hạn
+const cartPurgeInterval = time.Hour
+
+func RegisterCartRoutes(r *gin.Engine, db *gorm.DB, workers *worker.Group, calculator *pricing.Calculator, cartTTL, reservationTTL time.Duration) {
+	var cartRepo RepInterface.CartRepository = Repo.NewCartRepo(db)
+	var bookRepo RepInterface.BookRepository = BookRepo.NewRepository(db)
+	var cartService ServiceInterface.CartServiceInterface = ServiceImp.NewCartService(cartRepo, bookRepo, TxManager.NewTransactionManager(db), calculator, cartTTL, reservationTTL)
+	cartHandler := cart.NewCartHandler(cartService)
+
+	workers.Every(cartPurgeInterval, func(ctx context.Context) {
+		if n, err := cartService.PurgeExpired(ctx); err != nil {
+			slog.Error("failed to purge expired carts", "error", err)
+		} else if n > 0 {
+			slog.Info("purged expired carts", "count", n)
+		}
+	})
+
+	auth := r.Group("/cart", middleware.AuthMiddleware())
+	{
+		auth.GET("", middleware.RBACMiddleware("cart/manage"), cartHandler.GetCart)
+		auth.POST("/items", middleware.RBACMiddleware("cart/manage"), cartHandler.AddItem)
+		auth.PUT("/items/:book_id", middleware.RBACMiddleware("cart/manage"), cartHandler.UpdateItem)
+		auth.DELETE("/items/:book_id", middleware.RBACMiddleware("cart/manage"), cartHandler.RemoveItem)
+		auth.POST("/checkout", middleware.RBACMiddleware("order/create"), cartHandler.Checkout)
+	}
+}
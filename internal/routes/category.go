@@ -0,0 +1,33 @@
+package routes
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/maithuc2003/Test_GIN_golang/internal/handler/category"
+	RepInterface "github.com/maithuc2003/Test_GIN_golang/internal/interfaces/repositories"
+	ServiceInterface "github.com/maithuc2003/Test_GIN_golang/internal/interfaces/service"
+	"github.com/maithuc2003/Test_GIN_golang/internal/middleware"
+	Repo "github.com/maithuc2003/Test_GIN_golang/internal/repositories/category"
+	ServiceImp "github.com/maithuc2003/Test_GIN_golang/internal/service/category"
+	"gorm.io/gorm"
+)
+
+func RegisterCategoryRoutes(r *gin.Engine, db *gorm.DB) {
+	var categoryRepo RepInterface.CategoryRepository = Repo.NewCategoryRepo(db)
+	var categoryService ServiceInterface.CategoryServiceInterface = ServiceImp.NewCategoryService(categoryRepo)
+	categoryHandler := category.NewCategoryHandler(categoryService)
+
+	// Public routes (GET /categories/:id/books nằm trong RegisterBookRoutes)
+	categoryRoutes := r.Group("/categories")
+	{
+		categoryRoutes.GET("", categoryHandler.GetCategoryTree)
+		categoryRoutes.GET("/:id", categoryHandler.GetByCategoryID)
+	}
+
+	// Protected routes with Auth + RBAC
+	auth := r.Group("/categories", middleware.AuthMiddleware())
+	{
+		auth.POST("/add", middleware.RBACMiddleware("category/create"), categoryHandler.CreateCategory)
+		auth.PUT("/:id", middleware.RBACMiddleware("category/update"), categoryHandler.UpdateById)
+		auth.DELETE("/:id", middleware.RBACMiddleware("category/delete"), categoryHandler.DeleteById)
+	}
+}
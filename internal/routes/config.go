@@ -0,0 +1,106 @@
+package routes
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/maithuc2003/Test_GIN_golang/config"
+	"github.com/maithuc2003/Test_GIN_golang/internal/events"
+	"github.com/maithuc2003/Test_GIN_golang/internal/invoice"
+	"github.com/maithuc2003/Test_GIN_golang/internal/payment"
+	"github.com/maithuc2003/Test_GIN_golang/internal/pricing"
+	"github.com/maithuc2003/Test_GIN_golang/internal/ratelimit"
+	"github.com/maithuc2003/Test_GIN_golang/internal/redis"
+	WebhookRepo "github.com/maithuc2003/Test_GIN_golang/internal/repositories/webhook"
+	"github.com/maithuc2003/Test_GIN_golang/internal/service/user"
+	"github.com/maithuc2003/Test_GIN_golang/internal/storage"
+	"github.com/maithuc2003/Test_GIN_golang/internal/webhook"
+	"gorm.io/gorm"
+)
+
+// Các hàm dựng dependency từ cấu hình; package config chỉ giữ dữ liệu và kiểm tra hợp lệ
+
+// newRateLimitStore tạo store theo cấu hình, store redis dùng cache (client của
+// cache.addr); nil khi tắt rate limit
+func newRateLimitStore(c config.RateLimitConfig, cache *redis.Client) ratelimit.Store {
+	if !c.Enabled {
+		return nil
+	}
+	if c.Store == "redis" {
+		return ratelimit.NewRedisStore(cache, "ratelimit:")
+	}
+	return ratelimit.NewMemoryStore()
+}
+
+// lockoutPolicy chuyển cấu hình khoá tài khoản sang chính sách của user service
+func lockoutPolicy(c config.LockoutConfig) user.Lockout {
+	return user.Lockout{Threshold: c.Threshold, Base: c.Base, Max: c.Max}
+}
+
+// openStorage tạo storage theo Driver
+func openStorage(c config.StorageConfig) (storage.Storage, error) {
+	switch c.Driver {
+	case "", "local":
+		return storage.NewLocalStorage(c.LocalDir, c.BaseURL)
+	case "s3":
+		return storage.NewS3Storage(storage.S3Config{
+			Endpoint:  c.S3.Endpoint,
+			Bucket:    c.S3.Bucket,
+			AccessKey: c.S3.AccessKey,
+			SecretKey: c.S3.SecretKey,
+			Region:    c.S3.Region,
+			UseSSL:    c.S3.UseSSL,
+			PublicURL: c.S3.PublicURL,
+		})
+	default:
+		return nil, fmt.Errorf("unknown storage driver %q", c.Driver)
+	}
+}
+
+// newPaymentProvider tạo cổng thanh toán theo Provider
+func newPaymentProvider(c config.PaymentConfig) (payment.Provider, error) {
+	switch c.Provider {
+	case "", "fake":
+		return payment.NewFakeProvider(c.WebhookSecret), nil
+	default:
+		return nil, fmt.Errorf("unknown payment provider %q", c.Provider)
+	}
+}
+
+// loadTaxTable đọc bảng thuế suất từ RatesFile; không cấu hình thì không tính thuế
+func loadTaxTable(c config.TaxConfig) (*pricing.TaxTable, error) {
+	if c.RatesFile == "" {
+		return &pricing.TaxTable{}, nil
+	}
+	data, err := os.ReadFile(c.RatesFile)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read tax rates file: %w", err)
+	}
+	return pricing.ParseTaxTable(data)
+}
+
+// invoiceIssuer là thông tin bên bán in trên hoá đơn
+func invoiceIssuer(c config.InvoiceConfig) invoice.Issuer {
+	return invoice.Issuer{
+		Name:    c.SellerName,
+		Address: c.SellerAddress,
+		TaxID:   c.SellerTaxID,
+	}
+}
+
+// newOutboxSinks tạo các sink đã cấu hình. Sink "webhook" bị bỏ qua khi tắt tính năng
+// webhook vì không có sender gửi các delivery của nó.
+func newOutboxSinks(c config.OutboxConfig, db *gorm.DB, webhooks bool) []events.Sink {
+	var sinks []events.Sink
+	for _, name := range c.Sinks {
+		switch name {
+		case "log":
+			sinks = append(sinks, events.LogSink{})
+		case "webhook":
+			if webhooks {
+				sinks = append(sinks, webhook.NewSink(WebhookRepo.NewWebhookRepo(db)))
+			}
+		}
+	}
+	return sinks
+}
@@ -0,0 +1,28 @@
+package routes
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/maithuc2003/Test_GIN_golang/internal/handler/coupon"
+	RepInterface "github.com/maithuc2003/Test_GIN_golang/internal/interfaces/repositories"
+	ServiceInterface "github.com/maithuc2003/Test_GIN_golang/internal/interfaces/service"
+	"github.com/maithuc2003/Test_GIN_golang/internal/middleware"
+	Repo "github.com/maithuc2003/Test_GIN_golang/internal/repositories/coupon"
+	ServiceImp "github.com/maithuc2003/Test_GIN_golang/internal/service/coupon"
+	"gorm.io/gorm"
+)
+
+func RegisterCouponRoutes(r *gin.Engine, db *gorm.DB) {
+	var couponRepo RepInterface.CouponRepository = Repo.NewCouponRepo(db)
+	var couponService ServiceInterface.CouponServiceInterface = ServiceImp.NewCouponService(couponRepo)
+	couponHandler := coupon.NewCouponHandler(couponService)
+
+	// Chỉ admin quản lý chương trình khuyến mãi
+	auth := r.Group("/coupons", middleware.AuthMiddleware())
+	{
+		auth.GET("", middleware.RBACMiddleware("coupon/manage"), couponHandler.GetAllCoupons)
+		auth.GET("/:id", middleware.RBACMiddleware("coupon/manage"), couponHandler.GetByCouponID)
+		auth.POST("", middleware.RBACMiddleware("coupon/manage"), couponHandler.CreateCoupon)
+		auth.PUT("/:id", middleware.RBACMiddleware("coupon/manage"), couponHandler.UpdateById)
+		auth.DELETE("/:id", middleware.RBACMiddleware("coupon/manage"), couponHandler.DeleteById)
+	}
+}
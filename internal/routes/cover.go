@@ -0,0 +1,31 @@
+package routes
+
+import (
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/maithuc2003/Test_GIN_golang/internal/handler/cover"
+	RepInterface "github.com/maithuc2003/Test_GIN_golang/internal/interfaces/repositories"
+	ServiceInterface "github.com/maithuc2003/Test_GIN_golang/internal/interfaces/service"
+	"github.com/maithuc2003/Test_GIN_golang/internal/middleware"
+	Repo "github.com/maithuc2003/Test_GIN_golang/internal/repositories/book"
+	ServiceImp "github.com/maithuc2003/Test_GIN_golang/internal/service/cover"
+	"github.com/maithuc2003/Test_GIN_golang/internal/storage"
+	"gorm.io/gorm"
+)
+
+func RegisterCoverRoutes(r *gin.Engine, db *gorm.DB, store storage.Storage) {
+	var bookRepo RepInterface.BookRepository = Repo.NewRepository(db)
+	var coverService ServiceInterface.CoverServiceInterface = ServiceImp.NewCoverService(bookRepo, store)
+	coverHandler := cover.NewCoverHandler(coverService)
+
+	// Ảnh lưu trên đĩa được phục vụ trực tiếp khi base URL là đường dẫn của chính server
+	if local, ok := store.(*storage.LocalStorage); ok && strings.HasPrefix(local.BaseURL(), "/") {
+		r.Static(local.BaseURL(), local.Root())
+	}
+
+	auth := r.Group("/books", middleware.AuthMiddleware())
+	{
+		auth.POST("/:id/cover", middleware.RBACMiddleware("book/update"), coverHandler.UploadCover)
+	}
+}
@@ -0,0 +1,25 @@
+package routes
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/maithuc2003/Test_GIN_golang/internal/handler/export"
+	RepInterface "github.com/maithuc2003/Test_GIN_golang/internal/interfaces/repositories"
+	ServiceInterface "github.com/maithuc2003/Test_GIN_golang/internal/interfaces/service"
+	"github.com/maithuc2003/Test_GIN_golang/internal/middleware"
+	Repo "github.com/maithuc2003/Test_GIN_golang/internal/repositories/export"
+	ServiceImp "github.com/maithuc2003/Test_GIN_golang/internal/service/export"
+	"gorm.io/gorm"
+)
+
+func RegisterExportRoutes(r *gin.Engine, db *gorm.DB) {
+	var exportRepo RepInterface.ExportRepository = Repo.NewExportRepo(db)
+	var exportService ServiceInterface.ExportServiceInterface = ServiceImp.NewExportService(exportRepo)
+	exportHandler := export.NewExportHandler(exportService)
+
+	auth := r.Group("/export", middleware.AuthMiddleware())
+	{
+		auth.GET("/books", middleware.RBACMiddleware("book/export"), exportHandler.ExportBooks)
+		auth.GET("/authors", middleware.RBACMiddleware("author/export"), exportHandler.ExportAuthors)
+		auth.GET("/orders", middleware.RBACMiddleware("order/export"), exportHandler.ExportOrders)
+	}
+}
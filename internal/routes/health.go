@@ -0,0 +1,23 @@
+package routes
+
+import (
+	"github.com/gin-gonic/gin"
+	handler "github.com/maithuc2003/Test_GIN_golang/internal/handler/health"
+	"github.com/maithuc2003/Test_GIN_golang/internal/health"
+	"github.com/maithuc2003/Test_GIN_golang/internal/redis"
+	"gorm.io/gorm"
+)
+
+// RegisterHealthRoutes phục vụ /healthz (liveness) và /readyz (readiness: ping database
+// và cache nếu cache khác nil). Không cần đăng nhập để orchestrator gọi được.
+func RegisterHealthRoutes(r *gin.Engine, db *gorm.DB, cache *redis.Client) {
+	checker := health.NewChecker()
+	checker.Add("database", health.DB(db))
+	if cache != nil {
+		checker.Add("cache", health.Redis(cache))
+	}
+	healthHandler := handler.NewHealthHandler(checker)
+
+	r.GET("/healthz", healthHandler.Healthz)
+	r.GET("/readyz", healthHandler.Readyz)
+}
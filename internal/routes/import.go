@@ -0,0 +1,21 @@
+package routes
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/maithuc2003/Test_GIN_golang/internal/handler/importer"
+	ServiceInterface "github.com/maithuc2003/Test_GIN_golang/internal/interfaces/service"
+	"github.com/maithuc2003/Test_GIN_golang/internal/middleware"
+	TxManager "github.com/maithuc2003/Test_GIN_golang/internal/repositories/transaction"
+	ServiceImp "github.com/maithuc2003/Test_GIN_golang/internal/service/importer"
+	"gorm.io/gorm"
+)
+
+func RegisterImportRoutes(r *gin.Engine, db *gorm.DB) {
+	var importService ServiceInterface.ImportServiceInterface = ServiceImp.NewImportService(TxManager.NewTransactionManager(db))
+	importHandler := importer.NewImportHandler(importService)
+
+	auth := r.Group("/import", middleware.AuthMiddleware())
+	{
+		auth.POST("/books", middleware.RBACMiddleware("book/import"), importHandler.ImportBooks)
+	}
+}
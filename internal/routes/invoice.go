@@ -0,0 +1,27 @@
+package routes
+
+import (
+	"github.com/gin-gonic/gin"
+	handler "github.com/maithuc2003/Test_GIN_golang/internal/handler/invoice"
+	RepInterface "github.com/maithuc2003/Test_GIN_golang/internal/interfaces/repositories"
+	ServiceInterface "github.com/maithuc2003/Test_GIN_golang/internal/interfaces/service"
+	"github.com/maithuc2003/Test_GIN_golang/internal/invoice"
+	"github.com/maithuc2003/Test_GIN_golang/internal/middleware"
+	Repo "github.com/maithuc2003/Test_GIN_golang/internal/repositories/invoice"
+	OrderRepo "github.com/maithuc2003/Test_GIN_golang/internal/repositories/order"
+	TxManager "github.com/maithuc2003/Test_GIN_golang/internal/repositories/transaction"
+	ServiceImp "github.com/maithuc2003/Test_GIN_golang/internal/service/invoice"
+	"gorm.io/gorm"
+)
+
+func RegisterInvoiceRoutes(r *gin.Engine, db *gorm.DB, issuer invoice.Issuer, numberPrefix string) {
+	var orderRepo RepInterface.OrderRepositoryInterface = OrderRepo.NewOrderRepo(db)
+	var invoiceRepo RepInterface.InvoiceRepository = Repo.NewInvoiceRepo(db)
+	var invoiceService ServiceInterface.InvoiceServiceInterface = ServiceImp.NewInvoiceService(orderRepo, invoiceRepo, TxManager.NewTransactionManager(db), issuer, numberPrefix)
+	invoiceHandler := handler.NewInvoiceHandler(invoiceService)
+
+	auth := r.Group("/orders", middleware.AuthMiddleware())
+	{
+		auth.GET("/:id/invoice", middleware.RBACMiddleware("order/invoice"), invoiceHandler.GetInvoice)
+	}
+}
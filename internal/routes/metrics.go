@@ -0,0 +1,24 @@
+package routes
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/maithuc2003/Test_GIN_golang/internal/metrics"
+	"gorm.io/gorm"
+)
+
+// RegisterMetricsRoutes gắn plugin đo query GORM, thống kê connection pool và phục vụ
+// /metrics cho Prometheus
+func RegisterMetricsRoutes(r *gin.Engine, db *gorm.DB) error {
+	if err := db.Use(metrics.GormPlugin{}); err != nil {
+		return err
+	}
+	sqlDB, err := db.DB()
+	if err != nil {
+		return err
+	}
+	if err := metrics.RegisterDB(sqlDB, db.Name()); err != nil {
+		return err
+	}
+	r.GET("/metrics", gin.WrapH(metrics.Handler()))
+	return nil
+}
@@ -1,21 +1,41 @@
 package routes
 
 import (
+	"context"
+	"log/slog"
+	"time"
+
 	"github.com/gin-gonic/gin"
 	"github.com/maithuc2003/Test_GIN_golang/internal/handler/order"
 	RepInterface "github.com/maithuc2003/Test_GIN_golang/internal/interfaces/repositories"
 	ServiceInterface "github.com/maithuc2003/Test_GIN_golang/internal/interfaces/service"
 	"github.com/maithuc2003/Test_GIN_golang/internal/middleware"
+	"github.com/maithuc2003/Test_GIN_golang/internal/pricing"
 	Repo "github.com/maithuc2003/Test_GIN_golang/internal/repositories/order"
+	ReservationRepo "github.com/maithuc2003/Test_GIN_golang/internal/repositories/reservation"
+	TxManager "github.com/maithuc2003/Test_GIN_golang/internal/repositories/transaction"
 	ServiceImp "github.com/maithuc2003/Test_GIN_golang/internal/service/order"
+	"github.com/maithuc2003/Test_GIN_golang/internal/worker"
 	"gorm.io/gorm"
 )
 
-func RegisterOrderRoutes(r *gin.Engine, db *gorm.DB) {
+// reservationSweepInterval là chu kỳ trả lại tồn kho của các đơn pending quá hạn giữ hàng
+const reservationSweepInterval = time.Minute
+
+func RegisterOrderRoutes(r *gin.Engine, db *gorm.DB, workers *worker.Group, calculator *pricing.Calculator, reservationTTL time.Duration) {
 	var orderRepo RepInterface.OrderRepositoryInterface = Repo.NewOrderRepo(db)
-	var orderService ServiceInterface.OrderServiceInterface = ServiceImp.NewOrderService(orderRepo)
+	var reservationRepo RepInterface.ReservationRepository = ReservationRepo.NewReservationRepo(db)
+	var orderService ServiceInterface.OrderServiceInterface = ServiceImp.NewOrderService(orderRepo, reservationRepo, TxManager.NewTransactionManager(db), calculator, reservationTTL)
 	orderHandler := order.NewOrderHandler(orderService)
 
+	workers.Every(reservationSweepInterval, func(ctx context.Context) {
+		if n, err := orderService.ReleaseExpiredReservations(ctx); err != nil {
+			slog.Error("failed to release expired reservations", "error", err)
+		} else if n > 0 {
+			slog.Info("released expired stock reservations", "count", n)
+		}
+	})
+
 	authorRoutes := r.Group("/orders")
 	{
 		authorRoutes.GET("", orderHandler.GetAllOrders)
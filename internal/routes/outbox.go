@@ -0,0 +1,21 @@
+package routes
+
+import (
+	"context"
+	"time"
+
+	"github.com/maithuc2003/Test_GIN_golang/internal/events"
+	Repo "github.com/maithuc2003/Test_GIN_golang/internal/repositories/outbox"
+	"github.com/maithuc2003/Test_GIN_golang/internal/worker"
+	"gorm.io/gorm"
+)
+
+// StartOutboxDispatcher chạy dispatcher giao domain event trong outbox tới các sink;
+// không có sink thì không chạy
+func StartOutboxDispatcher(workers *worker.Group, db *gorm.DB, sinks []events.Sink, interval time.Duration) {
+	if len(sinks) == 0 {
+		return
+	}
+	dispatcher := events.NewDispatcher(Repo.NewOutboxRepo(db), sinks...)
+	workers.Go(func(ctx context.Context) { dispatcher.Run(ctx, interval) })
+}
@@ -0,0 +1,31 @@
+package routes
+
+import (
+	"github.com/gin-gonic/gin"
+	handler "github.com/maithuc2003/Test_GIN_golang/internal/handler/payment"
+	RepInterface "github.com/maithuc2003/Test_GIN_golang/internal/interfaces/repositories"
+	ServiceInterface "github.com/maithuc2003/Test_GIN_golang/internal/interfaces/service"
+	"github.com/maithuc2003/Test_GIN_golang/internal/middleware"
+	"github.com/maithuc2003/Test_GIN_golang/internal/payment"
+	OrderRepo "github.com/maithuc2003/Test_GIN_golang/internal/repositories/order"
+	Repo "github.com/maithuc2003/Test_GIN_golang/internal/repositories/payment"
+	TxManager "github.com/maithuc2003/Test_GIN_golang/internal/repositories/transaction"
+	ServiceImp "github.com/maithuc2003/Test_GIN_golang/internal/service/payment"
+	"gorm.io/gorm"
+)
+
+func RegisterPaymentRoutes(r *gin.Engine, db *gorm.DB, provider payment.Provider) {
+	var orderRepo RepInterface.OrderRepositoryInterface = OrderRepo.NewOrderRepo(db)
+	var paymentRepo RepInterface.PaymentRepository = Repo.NewPaymentRepo(db)
+	var paymentService ServiceInterface.PaymentServiceInterface = ServiceImp.NewPaymentService(orderRepo, paymentRepo, TxManager.NewTransactionManager(db), provider)
+	paymentHandler := handler.NewPaymentHandler(paymentService)
+
+	// Webhook xác thực bằng chữ ký của cổng thanh toán nên không qua AuthMiddleware
+	r.POST("/payments/webhook", paymentHandler.Webhook)
+
+	auth := r.Group("/orders", middleware.AuthMiddleware())
+	{
+		auth.POST("/:id/pay", middleware.RBACMiddleware("order/pay"), paymentHandler.Pay)
+		auth.GET("/:id/payments", middleware.RBACMiddleware("order/pay"), paymentHandler.GetPayments)
+	}
+}
@@ -0,0 +1,32 @@
+package routes
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/maithuc2003/Test_GIN_golang/internal/handler/publisher"
+	RepInterface "github.com/maithuc2003/Test_GIN_golang/internal/interfaces/repositories"
+	ServiceInterface "github.com/maithuc2003/Test_GIN_golang/internal/interfaces/service"
+	"github.com/maithuc2003/Test_GIN_golang/internal/middleware"
+	Repo "github.com/maithuc2003/Test_GIN_golang/internal/repositories/publisher"
+	ServiceImp "github.com/maithuc2003/Test_GIN_golang/internal/service/publisher"
+	"gorm.io/gorm"
+)
+
+func RegisterPublisherRoutes(r *gin.Engine, db *gorm.DB) {
+	var publisherRepo RepInterface.PublisherRepository = Repo.NewPublisherRepo(db)
+	var publisherService ServiceInterface.PublisherServiceInterface = ServiceImp.NewPublisherService(publisherRepo)
+	publisherHandler := publisher.NewPublisherHandler(publisherService)
+
+	// Public routes
+	publisherRoutes := r.Group("/publishers")
+	{
+		publisherRoutes.GET("", publisherHandler.GetAllPublishers)
+		publisherRoutes.GET("/:id", publisherHandler.GetByPublisherID)
+	}
+
+	// Protected routes with Auth + RBAC
+	auth := r.Group("/publishers", middleware.AuthMiddleware())
+	{
+		auth.POST("/add", middleware.RBACMiddleware("publisher/create"), publisherHandler.CreatePublisher)
+		auth.PUT("/:id", middleware.RBACMiddleware("publisher/update"), publisherHandler.UpdateById)
+	}
+}
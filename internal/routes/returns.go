@@ -0,0 +1,37 @@
+package routes
+
+import (
+	"github.com/gin-gonic/gin"
+	handler "github.com/maithuc2003/Test_GIN_golang/internal/handler/returns"
+	RepInterface "github.com/maithuc2003/Test_GIN_golang/internal/interfaces/repositories"
+	ServiceInterface "github.com/maithuc2003/Test_GIN_golang/internal/interfaces/service"
+	"github.com/maithuc2003/Test_GIN_golang/internal/middleware"
+	"github.com/maithuc2003/Test_GIN_golang/internal/payment"
+	OrderRepo "github.com/maithuc2003/Test_GIN_golang/internal/repositories/order"
+	Repo "github.com/maithuc2003/Test_GIN_golang/internal/repositories/returns"
+	TxManager "github.com/maithuc2003/Test_GIN_golang/internal/repositories/transaction"
+	ServiceImp "github.com/maithuc2003/Test_GIN_golang/internal/service/returns"
+	"gorm.io/gorm"
+)
+
+func RegisterReturnRoutes(r *gin.Engine, db *gorm.DB, provider payment.Provider) {
+	var orderRepo RepInterface.OrderRepositoryInterface = OrderRepo.NewOrderRepo(db)
+	var returnRepo RepInterface.ReturnRepository = Repo.NewReturnRepo(db)
+	var returnService ServiceInterface.ReturnServiceInterface = ServiceImp.NewReturnService(orderRepo, returnRepo, TxManager.NewTransactionManager(db), provider)
+	returnHandler := handler.NewReturnHandler(returnService)
+
+	// Khách hàng gửi và xem yêu cầu trả hàng của đơn mình
+	orders := r.Group("/orders", middleware.AuthMiddleware())
+	{
+		orders.POST("/:id/returns", middleware.RBACMiddleware("order/return"), returnHandler.RequestReturn)
+		orders.GET("/:id/returns", middleware.RBACMiddleware("order/return"), returnHandler.GetOrderReturns)
+	}
+
+	// Nhân viên duyệt yêu cầu trả hàng
+	staff := r.Group("/returns", middleware.AuthMiddleware())
+	{
+		staff.GET("", middleware.RBACMiddleware("return/review"), returnHandler.ListReturns)
+		staff.POST("/:id/approve", middleware.RBACMiddleware("return/review"), returnHandler.Approve)
+		staff.POST("/:id/reject", middleware.RBACMiddleware("return/review"), returnHandler.Reject)
+	}
+}
@@ -1,16 +1,99 @@
 package routes
 
 import (
+	"log/slog"
+	"os"
+	"time"
+
 	"github.com/gin-gonic/gin"
+	"github.com/maithuc2003/Test_GIN_golang/config"
+	"github.com/maithuc2003/Test_GIN_golang/internal/middleware"
+	"github.com/maithuc2003/Test_GIN_golang/internal/pricing"
+	"github.com/maithuc2003/Test_GIN_golang/internal/ratelimit"
+	"github.com/maithuc2003/Test_GIN_golang/internal/redis"
+	"github.com/maithuc2003/Test_GIN_golang/internal/tracing"
+	"github.com/maithuc2003/Test_GIN_golang/internal/worker"
+	"go.opentelemetry.io/contrib/instrumentation/github.com/gin-gonic/gin/otelgin"
 	"gorm.io/gorm"
 )
 
-func SetupRouter(db *gorm.DB) *gin.Engine {
-	r := gin.Default()
+// SetupRouter dựng router theo cfg và khởi động các worker nền trong workers; dừng
+// workers khi tắt ứng dụng. Readiness và rate limit dùng chung cache (nil khi không cấu
+// hình redis); người gọi đóng cache khi tắt ứng dụng.
+func SetupRouter(cfg *config.Config, db *gorm.DB, cache *redis.Client, workers *worker.Group) *gin.Engine {
+	limiter := newRateLimitStore(cfg.RateLimit, cache)
+	r := gin.New()
+	r.Use(
+		// Span gốc của request; các middleware sau nhận context đã có span
+		otelgin.Middleware(tracing.ServiceName, otelgin.WithGinFilter(func(c *gin.Context) bool {
+			switch c.FullPath() {
+			case "/metrics", "/healthz", "/readyz":
+				return false
+			}
+			return true
+		})),
+		middleware.RequestIDMiddleware(),
+		middleware.AccessLogMiddleware(slog.Default()),
+		middleware.MetricsMiddleware(),
+		middleware.RecoveryMiddleware(slog.Default()),
+		middleware.TimeoutMiddleware(cfg.Server.RequestTimeout, map[string]time.Duration{
+			"/import/": cfg.Server.BulkRequestTimeout,
+			"/export/": cfg.Server.BulkRequestTimeout,
+		}),
+		middleware.RateLimitMiddleware(limiter, "user", ratelimit.PerMinute(cfg.RateLimit.UserPerMinute, cfg.RateLimit.UserBurst), middleware.UserKey),
+		middleware.RateLimitMiddleware(limiter, "ip", ratelimit.PerMinute(cfg.RateLimit.IPPerMinute, cfg.RateLimit.IPBurst), middleware.AnonymousIPKey),
+	)
+
+	RegisterHealthRoutes(r, db, cache)
+	if cfg.Features.Metrics {
+		if err := RegisterMetricsRoutes(r, db); err != nil {
+			fatal("Failed to register metrics", err)
+		}
+	}
+	if err := db.Use(tracing.GormPlugin{}); err != nil {
+		fatal("Failed to register tracing", err)
+	}
+
+	store, err := openStorage(cfg.Storage)
+	if err != nil {
+		fatal("Failed to configure storage", err)
+	}
+	paymentProvider, err := newPaymentProvider(cfg.Payment)
+	if err != nil {
+		fatal("Failed to configure payment provider", err)
+	}
+	taxes, err := loadTaxTable(cfg.Tax)
+	if err != nil {
+		fatal("Failed to load tax rates", err)
+	}
+	calculator := pricing.NewCalculator(taxes)
+	sinks := newOutboxSinks(cfg.Outbox, db, cfg.Features.Webhooks)
+	StartOutboxDispatcher(workers, db, sinks, cfg.Outbox.PollInterval)
 
 	RegisterBookRoutes(r, db)
-	RegisterUserRoutes(r, db)
+	RegisterCoverRoutes(r, db, store)
+	RegisterUserRoutes(r, db, lockoutPolicy(cfg.LoginLockout),
+		middleware.RateLimitMiddleware(limiter, "login", ratelimit.PerMinute(cfg.RateLimit.LoginPerMinute, cfg.RateLimit.LoginBurst), middleware.ClientIPKey))
 	RegisterAuthorRoutes(r, db)
-	RegisterOrderRoutes(r, db)
+	RegisterPublisherRoutes(r, db)
+	RegisterCategoryRoutes(r, db)
+	RegisterOrderRoutes(r, db, workers, calculator, cfg.Order.ReservationTTL)
+	RegisterPaymentRoutes(r, db, paymentProvider)
+	RegisterReturnRoutes(r, db, paymentProvider)
+	RegisterInvoiceRoutes(r, db, invoiceIssuer(cfg.Invoice), cfg.Invoice.NumberPrefix)
+	RegisterCouponRoutes(r, db)
+	if cfg.Features.Webhooks {
+		RegisterWebhookRoutes(r, db, workers, cfg.Webhook.Timeout, cfg.Webhook.MaxAttempts)
+	}
+	RegisterCartRoutes(r, db, workers, calculator, cfg.Cart.TTL, cfg.Order.ReservationTTL)
+	if cfg.Features.Import {
+		RegisterImportRoutes(r, db)
+	}
+	RegisterExportRoutes(r, db)
 	return r
 }
+
+func fatal(msg string, err error) {
+	slog.Error(msg, "error", err)
+	os.Exit(1)
+}
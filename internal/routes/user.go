@@ -10,11 +10,13 @@ import (
 	"gorm.io/gorm"
 )
 
-func RegisterUserRoutes(r *gin.Engine, db *gorm.DB) {
+// RegisterUserRoutes đăng ký route user; loginLimit chạy trước handler đăng nhập để
+// chặn dò mật khẩu theo IP, lockout khoá tài khoản bị đoán sai nhiều lần
+func RegisterUserRoutes(r *gin.Engine, db *gorm.DB, lockout ServiceImp.Lockout, loginLimit gin.HandlerFunc) {
 	var userRepo repositories.UserRepository = Repo.NewRepository(db)
-	var userService ServiceInterface.UserServiceInterface = ServiceImp.NewUserService(userRepo)
+	var userService ServiceInterface.UserServiceInterface = ServiceImp.NewUserService(userRepo, lockout)
 	userHandler := user.NewUserHandler(userService)
 
 	r.GET("/users", userHandler.GetByUsername)
-	r.POST("/user/login", userHandler.LoginUser)
+	r.POST("/user/login", loginLimit, userHandler.LoginUser)
 }
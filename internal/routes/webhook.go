@@ -0,0 +1,41 @@
+package routes
+
+import (
+	"context"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/maithuc2003/Test_GIN_golang/internal/handler/webhook"
+	RepInterface "github.com/maithuc2003/Test_GIN_golang/internal/interfaces/repositories"
+	ServiceInterface "github.com/maithuc2003/Test_GIN_golang/internal/interfaces/service"
+	"github.com/maithuc2003/Test_GIN_golang/internal/middleware"
+	Repo "github.com/maithuc2003/Test_GIN_golang/internal/repositories/webhook"
+	ServiceImp "github.com/maithuc2003/Test_GIN_golang/internal/service/webhook"
+	Sender "github.com/maithuc2003/Test_GIN_golang/internal/webhook"
+	"github.com/maithuc2003/Test_GIN_golang/internal/worker"
+	"gorm.io/gorm"
+)
+
+// webhookSendInterval là chu kỳ gửi các delivery webhook tới hạn
+const webhookSendInterval = 5 * time.Second
+
+func RegisterWebhookRoutes(r *gin.Engine, db *gorm.DB, workers *worker.Group, timeout time.Duration, maxAttempts int) {
+	var webhookRepo RepInterface.WebhookRepository = Repo.NewWebhookRepo(db)
+	var webhookService ServiceInterface.WebhookServiceInterface = ServiceImp.NewWebhookService(webhookRepo)
+	webhookHandler := webhook.NewWebhookHandler(webhookService)
+
+	sender := Sender.NewSender(webhookRepo, timeout, maxAttempts)
+	workers.Go(func(ctx context.Context) { sender.Run(ctx, webhookSendInterval) })
+
+	// Chỉ admin quản lý webhook của đối tác
+	auth := r.Group("/webhooks", middleware.AuthMiddleware())
+	{
+		auth.GET("", middleware.RBACMiddleware("webhook/manage"), webhookHandler.GetAllSubscriptions)
+		auth.GET("/:id", middleware.RBACMiddleware("webhook/manage"), webhookHandler.GetBySubscriptionID)
+		auth.POST("", middleware.RBACMiddleware("webhook/manage"), webhookHandler.CreateSubscription)
+		auth.PUT("/:id", middleware.RBACMiddleware("webhook/manage"), webhookHandler.UpdateById)
+		auth.DELETE("/:id", middleware.RBACMiddleware("webhook/manage"), webhookHandler.DeleteById)
+		auth.GET("/:id/deliveries", middleware.RBACMiddleware("webhook/manage"), webhookHandler.GetDeliveries)
+		auth.POST("/:id/deliveries/:delivery_id/redeliver", middleware.RBACMiddleware("webhook/manage"), webhookHandler.Redeliver)
+	}
+}
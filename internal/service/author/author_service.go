@@ -1,48 +1,83 @@
 package author
 
 import (
+	"context"
 	"errors"
 	"fmt"
 	"strings"
+	"time"
 
 	"github.com/maithuc2003/Test_GIN_golang/internal/interfaces/repositories"
 	"github.com/maithuc2003/Test_GIN_golang/internal/models"
+	"github.com/maithuc2003/Test_GIN_golang/internal/tracing"
 )
 
 type AuthorService struct {
 	repo repositories.AuthorRepositoriesInterface
+	tx   repositories.TransactionManager
 }
 
-func NewAuthorService(repo repositories.AuthorRepositoriesInterface) *AuthorService {
-	return &AuthorService{repo: repo}
+func NewAuthorService(repo repositories.AuthorRepositoriesInterface, tx repositories.TransactionManager) *AuthorService {
+	return &AuthorService{repo: repo, tx: tx}
 }
 
-func (s *AuthorService) CreateAuthor(author *models.Author) error {
+func (s *AuthorService) CreateAuthor(ctx context.Context, author *models.Author) error {
+	ctx, span := tracing.Start(ctx, "AuthorService.CreateAuthor")
+	defer span.End()
+
+	return createAuthor(ctx, s.repo, author)
+}
+
+// CreateAuthorWithBooks tạo tác giả và các sách của họ trong cùng một transaction
+func (s *AuthorService) CreateAuthorWithBooks(ctx context.Context, author *models.Author, books []*models.Book) error {
+	ctx, span := tracing.Start(ctx, "AuthorService.CreateAuthorWithBooks")
+	defer span.End()
+
+	for _, book := range books {
+		if book == nil || strings.TrimSpace(book.Title) == "" {
+			return errors.New("invalid book data: title required")
+		}
+		if book.Stock < 0 {
+			return errors.New("book quantity cannot be negative")
+		}
+	}
+
+	return s.tx.WithinTransaction(ctx, func(ctx context.Context, repos repositories.Repositories) error {
+		if err := createAuthor(ctx, repos.Authors, author); err != nil {
+			return err
+		}
+		now := time.Now()
+		for _, book := range books {
+			book.AuthorID = author.ID
+			book.CreatedAt = now
+			book.UpdatedAt = now
+			if err := repos.Books.CreateBook(ctx, book); err != nil {
+				return fmt.Errorf("failed to create book %q: %v", book.Title, err)
+			}
+		}
+		return nil
+	})
+}
+
+func createAuthor(ctx context.Context, repo repositories.AuthorRepositoriesInterface, author *models.Author) error {
 	if author == nil {
 		return errors.New("author is nil")
 	}
 	if strings.TrimSpace(author.Name) == "" {
 		return errors.New("author name cannot be empty")
 	}
-	existingAuthors, err := s.repo.GetAllAuthors()
-
-	if err != nil {
-		return fmt.Errorf("failed to fetch authors for validation: %v", err)
-	}
-	for _, existing := range existingAuthors {
-		if strings.EqualFold(existing.Name, author.Name) {
-			return errors.New("author with the same name already exists")
-		}
-	}
-	err = s.repo.CreateAuthor(author)
-	if err != nil {
-		return fmt.Errorf("failed to create author: %v", err)
+	// Trùng tên do unique index trên normalized_name chặn, repo trả về ErrConflict
+	if err := repo.CreateAuthor(ctx, author); err != nil {
+		return fmt.Errorf("failed to create author: %w", err)
 	}
 	return nil
 }
 
-func (s *AuthorService) GetAllAuthors() ([]*models.Author, error) {
-	authors, err := s.repo.GetAllAuthors()
+func (s *AuthorService) GetAllAuthors(ctx context.Context, filter models.AuthorFilter) ([]*models.Author, error) {
+	ctx, span := tracing.Start(ctx, "AuthorService.GetAllAuthors")
+	defer span.End()
+
+	authors, err := s.repo.GetAllAuthors(ctx, filter)
 	if err != nil {
 		return nil, err
 	}
@@ -52,11 +87,14 @@ func (s *AuthorService) GetAllAuthors() ([]*models.Author, error) {
 	return authors, nil
 }
 
-func (s *AuthorService) GetByAuthorID(id int) (*models.Author, error) {
+func (s *AuthorService) GetByAuthorID(ctx context.Context, id int) (*models.Author, error) {
+	ctx, span := tracing.Start(ctx, "AuthorService.GetByAuthorID")
+	defer span.End()
+
 	if id <= 0 {
 		return nil, errors.New("invalid author ID")
 	}
-	author, err := s.repo.GetByAuthorID(id)
+	author, err := s.repo.GetByAuthorID(ctx, id)
 	if err != nil {
 		return nil, fmt.Errorf("failed to retrieve author: %v", err)
 	}
@@ -66,12 +104,15 @@ func (s *AuthorService) GetByAuthorID(id int) (*models.Author, error) {
 	return author, nil
 }
 
-func (s *AuthorService) DeleteById(id int) (*models.Author, error) {
+func (s *AuthorService) DeleteById(ctx context.Context, id int) (*models.Author, error) {
+	ctx, span := tracing.Start(ctx, "AuthorService.DeleteById")
+	defer span.End()
+
 	if id <= 0 {
 		return nil, errors.New("invalid author ID")
 	}
 
-	deletedAuthor, err := s.repo.DeleteById(id)
+	deletedAuthor, err := s.repo.DeleteById(ctx, id)
 	if err != nil {
 		return nil, fmt.Errorf("failed to delete author: %v", err)
 	}
@@ -81,7 +122,10 @@ func (s *AuthorService) DeleteById(id int) (*models.Author, error) {
 	return deletedAuthor, nil
 }
 
-func (s *AuthorService) UpdateById(author *models.Author) (*models.Author, error) {
+func (s *AuthorService) UpdateById(ctx context.Context, author *models.Author) (*models.Author, error) {
+	ctx, span := tracing.Start(ctx, "AuthorService.UpdateById")
+	defer span.End()
+
 	if author == nil {
 		return nil, errors.New("author is nil")
 	}
@@ -94,30 +138,51 @@ func (s *AuthorService) UpdateById(author *models.Author) (*models.Author, error
 		return nil, errors.New("author name cannot be empty")
 	}
 	// Check if the author with the given ID actually exists
-	existring, err := s.repo.GetByAuthorID(author.ID)
+	existring, err := s.repo.GetByAuthorID(ctx, author.ID)
 	if err != nil {
 		return nil, fmt.Errorf("failed to fetch existing author: %v", err)
 	}
 	if existring == nil {
 		return nil, errors.New("author not found")
 	}
-	//Ensure the new same does not conflict with any other author's name
-	authors, err := s.repo.GetAllAuthors()
+	// Attempt to update the author in the repository; a duplicate name surfaces as ErrConflict
+	updateAuthor, err := s.repo.UpdateById(ctx, author)
 	if err != nil {
-		return nil, fmt.Errorf("failed to validate author name: %v", err)
+		return nil, fmt.Errorf("failed to update author : %w", err)
+	}
+	return updateAuthor, nil
+}
+
+// MergeAuthors gộp các tác giả nguồn (trùng lặp) vào tác giả đích
+func (s *AuthorService) MergeAuthors(ctx context.Context, targetID int, sourceIDs []int) (*models.AuthorMergeResult, error) {
+	ctx, span := tracing.Start(ctx, "AuthorService.MergeAuthors")
+	defer span.End()
+
+	if targetID <= 0 {
+		return nil, errors.New("invalid author ID")
+	}
+	if len(sourceIDs) == 0 {
+		return nil, errors.New("source author IDs are required")
 	}
 
-	for _, a := range authors {
-		//Allow the current author to keep their name, but prevent duplicate
-		if a.ID != author.ID && strings.EqualFold(a.Name, author.Name) {
-			return nil, errors.New("another author with the same name already exists")
+	seen := make(map[int]bool, len(sourceIDs))
+	ids := make([]int, 0, len(sourceIDs))
+	for _, id := range sourceIDs {
+		if id <= 0 {
+			return nil, fmt.Errorf("invalid source author ID %d", id)
+		}
+		if id == targetID {
+			return nil, errors.New("cannot merge an author into itself")
+		}
+		if !seen[id] {
+			seen[id] = true
+			ids = append(ids, id)
 		}
 	}
 
-	// Attempt to update the author in the repository
-	updateAuthor, err := s.repo.UpdateById(author)
+	result, err := s.repo.Merge(ctx, targetID, ids)
 	if err != nil {
-		return nil, fmt.Errorf("failed to update author : %v", err)
+		return nil, fmt.Errorf("failed to merge authors: %w", err)
 	}
-	return updateAuthor, nil
+	return result, nil
 }
@@ -1,28 +1,30 @@
 package author_test
 
 import (
+	"context"
 	"errors"
 	"strings"
 	"testing"
 	"time"
 
+	"github.com/maithuc2003/Test_GIN_golang/internal/interfaces/repositories"
 	mockrepo "github.com/maithuc2003/Test_GIN_golang/internal/mocks/repositories"
 	"github.com/maithuc2003/Test_GIN_golang/internal/models"
 	"github.com/maithuc2003/Test_GIN_golang/internal/service/author"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
 )
 
 func TestCreateAuthor(t *testing.T) {
 	now := time.Now()
 
 	tests := []struct {
-		name          string
-		input         *models.Author
-		mockAuthors   []*models.Author
-		mockGetAllErr error
-		mockCreateErr error
-		expectError   bool
-		errorMessage  string
+		name           string
+		input          *models.Author
+		mockCreateErr  error
+		expectError    bool
+		expectConflict bool
+		errorMessage   string
 	}{
 		{
 			name:         "nil author",
@@ -37,25 +39,16 @@ func TestCreateAuthor(t *testing.T) {
 			errorMessage: "author name cannot be empty",
 		},
 		{
-			name:  "duplicate author name",
-			input: &models.Author{Name: "John", Nationality: "US"},
-			mockAuthors: []*models.Author{
-				{Name: "john"},
-			},
-			expectError:  true,
-			errorMessage: "author with the same name already exists",
-		},
-		{
-			name:          "get all authors error",
-			input:         &models.Author{Name: "NewAuthor"},
-			mockGetAllErr: errors.New("DB error"),
-			expectError:   true,
-			errorMessage:  "failed to fetch authors for validation: DB error",
+			name:           "duplicate author name",
+			input:          &models.Author{Name: "John", Nationality: "US"},
+			mockCreateErr:  repositories.ErrConflict,
+			expectError:    true,
+			expectConflict: true,
+			errorMessage:   "failed to create author: conflict",
 		},
 		{
 			name:          "create author error",
 			input:         &models.Author{Name: "NewAuthor"},
-			mockAuthors:   []*models.Author{},
 			mockCreateErr: errors.New("insert error"),
 			expectError:   true,
 			errorMessage:  "failed to create author: insert error",
@@ -63,7 +56,6 @@ func TestCreateAuthor(t *testing.T) {
 		{
 			name:        "successful create",
 			input:       &models.Author{Name: "Unique", Nationality: "JP", CreatedAt: now, UpdatedAt: now},
-			mockAuthors: []*models.Author{},
 			expectError: false,
 		},
 	}
@@ -71,39 +63,24 @@ func TestCreateAuthor(t *testing.T) {
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			mockRepo := new(mockrepo.MockAuthorRepository)
-			svc := author.NewAuthorService(mockRepo)
-
-			if tt.input != nil {
-				// Không gọi GetAll nếu tên rỗng (early return)
-				if tt.input.Name != "   " {
-					mockRepo.On("GetAllAuthors").Return(tt.mockAuthors, tt.mockGetAllErr)
-
-					if tt.mockGetAllErr == nil {
-						// Check nếu không trùng tên và không expect lỗi → gọi CreateAuthor
-						isDuplicate := false
-						for _, a := range tt.mockAuthors {
-							if a.Name == tt.input.Name || a.Name == "john" && tt.input.Name == "John" {
-								isDuplicate = true
-								break
-							}
-						}
-
-						if !isDuplicate {
-							mockRepo.On("CreateAuthor", tt.input).Return(tt.mockCreateErr)
-						}
-					}
-				}
+			svc := author.NewAuthorService(mockRepo, new(mockrepo.MockTransactionManager))
+
+			// Không gọi repo nếu input không hợp lệ (early return)
+			if tt.input != nil && strings.TrimSpace(tt.input.Name) != "" {
+				mockRepo.On("CreateAuthor", mock.Anything, tt.input).Return(tt.mockCreateErr)
 			}
 
-			err := svc.CreateAuthor(tt.input)
+			err := svc.CreateAuthor(context.Background(), tt.input)
 
 			if tt.expectError {
 				assert.Error(t, err)
 				assert.EqualError(t, err, tt.errorMessage)
+				assert.Equal(t, tt.expectConflict, errors.Is(err, repositories.ErrConflict))
 			} else {
 				assert.NoError(t, err)
 			}
 
+			mockRepo.AssertNotCalled(t, "GetAllAuthors", mock.Anything, mock.Anything)
 			mockRepo.AssertExpectations(t)
 		})
 	}
@@ -142,11 +119,11 @@ func TestGetAllAuthors(t *testing.T) {
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			mockRepo := new(mockrepo.MockAuthorRepository)
-			svc := author.NewAuthorService(mockRepo)
+			svc := author.NewAuthorService(mockRepo, new(mockrepo.MockTransactionManager))
 
-			mockRepo.On("GetAllAuthors").Return(tt.mockAuthors, tt.mockError)
+			mockRepo.On("GetAllAuthors", mock.Anything, mock.Anything).Return(tt.mockAuthors, tt.mockError)
 
-			result, err := svc.GetAllAuthors()
+			result, err := svc.GetAllAuthors(context.Background(), models.AuthorFilter{})
 
 			if tt.expectError {
 				assert.Nil(t, result)
@@ -204,13 +181,13 @@ func TestGetByAuthorID(t *testing.T) {
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			mockRepo := new(mockrepo.MockAuthorRepository)
-			svc := author.NewAuthorService(mockRepo)
+			svc := author.NewAuthorService(mockRepo, new(mockrepo.MockTransactionManager))
 
 			if tt.inputID > 0 {
-				mockRepo.On("GetByAuthorID", tt.inputID).Return(tt.mockResult, tt.mockError)
+				mockRepo.On("GetByAuthorID", mock.Anything, tt.inputID).Return(tt.mockResult, tt.mockError)
 			}
 
-			result, err := svc.GetByAuthorID(tt.inputID)
+			result, err := svc.GetByAuthorID(context.Background(), tt.inputID)
 
 			if tt.expectError {
 				assert.Nil(t, result)
@@ -267,13 +244,13 @@ func TestDeleteById(t *testing.T) {
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			mockRepo := new(mockrepo.MockAuthorRepository)
-			svc := author.NewAuthorService(mockRepo)
+			svc := author.NewAuthorService(mockRepo, new(mockrepo.MockTransactionManager))
 
 			if tt.inputID > 0 {
-				mockRepo.On("DeleteById", tt.inputID).Return(tt.mockResult, tt.mockError)
+				mockRepo.On("DeleteById", mock.Anything, tt.inputID).Return(tt.mockResult, tt.mockError)
 			}
 
-			result, err := svc.DeleteById(tt.inputID)
+			result, err := svc.DeleteById(context.Background(), tt.inputID)
 
 			if tt.expectError {
 				assert.Nil(t, result)
@@ -294,9 +271,7 @@ func TestUpdateById(t *testing.T) {
 		name           string
 		inputAuthor    *models.Author
 		existingAuthor *models.Author
-		allAuthors     []*models.Author
 		mockGetErr     error
-		mockGetAllErr  error
 		mockUpdateErr  error
 		mockUpdateRes  *models.Author
 		expectError    bool
@@ -338,23 +313,14 @@ func TestUpdateById(t *testing.T) {
 			name:           "duplicate name found",
 			inputAuthor:    &models.Author{ID: 2, Name: "Jane"},
 			existingAuthor: &models.Author{ID: 2, Name: "Jane"},
-			allAuthors:     []*models.Author{{ID: 3, Name: "jane"}},
+			mockUpdateErr:  repositories.ErrConflict,
 			expectError:    true,
-			errorMessage:   "another author with the same name already exists",
-		},
-		{
-			name:           "error when getting all authors",
-			inputAuthor:    &models.Author{ID: 2, Name: "Jane"},
-			existingAuthor: &models.Author{ID: 2, Name: "Jane"},
-			mockGetAllErr:  errors.New("getAll error"),
-			expectError:    true,
-			errorMessage:   "failed to validate author name: getAll error",
+			errorMessage:   "failed to update author : conflict",
 		},
 		{
 			name:           "update error",
 			inputAuthor:    &models.Author{ID: 2, Name: "Jane"},
 			existingAuthor: &models.Author{ID: 2, Name: "Jane"},
-			allAuthors:     []*models.Author{{ID: 2, Name: "Jane"}},
 			mockUpdateErr:  errors.New("update failed"),
 			expectError:    true,
 			errorMessage:   "failed to update author : update failed",
@@ -363,7 +329,6 @@ func TestUpdateById(t *testing.T) {
 			name:           "successful update",
 			inputAuthor:    &models.Author{ID: 2, Name: "Updated", Nationality: "US", CreatedAt: now, UpdatedAt: now},
 			existingAuthor: &models.Author{ID: 2, Name: "OldName"},
-			allAuthors:     []*models.Author{{ID: 2, Name: "OldName"}},
 			mockUpdateRes:  &models.Author{ID: 2, Name: "Updated", Nationality: "US", CreatedAt: now, UpdatedAt: now},
 			expectError:    false,
 		},
@@ -372,20 +337,16 @@ func TestUpdateById(t *testing.T) {
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			mockRepo := new(mockrepo.MockAuthorRepository)
-			svc := author.NewAuthorService(mockRepo)
+			svc := author.NewAuthorService(mockRepo, new(mockrepo.MockTransactionManager))
 			if tt.inputAuthor != nil && tt.inputAuthor.ID > 0 && strings.TrimSpace(tt.inputAuthor.Name) != "" {
-				mockRepo.On("GetByAuthorID", tt.inputAuthor.ID).Return(tt.existingAuthor, tt.mockGetErr)
+				mockRepo.On("GetByAuthorID", mock.Anything, tt.inputAuthor.ID).Return(tt.existingAuthor, tt.mockGetErr)
 
 				if tt.mockGetErr == nil && tt.existingAuthor != nil {
-					mockRepo.On("GetAllAuthors").Return(tt.allAuthors, tt.mockGetAllErr)
-
-					if tt.mockGetAllErr == nil && (tt.mockUpdateRes != nil || tt.mockUpdateErr != nil) {
-						mockRepo.On("UpdateById", tt.inputAuthor).Return(tt.mockUpdateRes, tt.mockUpdateErr)
-					}
+					mockRepo.On("UpdateById", mock.Anything, tt.inputAuthor).Return(tt.mockUpdateRes, tt.mockUpdateErr)
 				}
 			}
 
-			result, err := svc.UpdateById(tt.inputAuthor)
+			result, err := svc.UpdateById(context.Background(), tt.inputAuthor)
 
 			if tt.expectError {
 				assert.Nil(t, result)
@@ -399,3 +360,144 @@ func TestUpdateById(t *testing.T) {
 		})
 	}
 }
+
+func TestCreateAuthorWithBooks(t *testing.T) {
+	tests := []struct {
+		name         string
+		books        []*models.Book
+		expectTx     bool
+		mockBookErr  error
+		expectError  bool
+		errorMessage string
+	}{
+		{
+			name:         "book without title",
+			books:        []*models.Book{{Title: " "}},
+			expectError:  true,
+			errorMessage: "invalid book data: title required",
+		},
+		{
+			name:         "negative stock",
+			books:        []*models.Book{{Title: "Mat Biec", Stock: -1}},
+			expectError:  true,
+			errorMessage: "book quantity cannot be negative",
+		},
+		{
+			name:         "create book error",
+			books:        []*models.Book{{Title: "Mat Biec", Stock: 1}},
+			expectTx:     true,
+			mockBookErr:  errors.New("DB error"),
+			expectError:  true,
+			errorMessage: `failed to create book "Mat Biec": DB error`,
+		},
+		{
+			name:     "success",
+			books:    []*models.Book{{Title: "Mat Biec", Stock: 1}, {Title: "Toi thay hoa vang", Stock: 2}},
+			expectTx: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockAuthorRepo := new(mockrepo.MockAuthorRepository)
+			mockBookRepo := new(mockrepo.MockBookRepo)
+			mockTx := &mockrepo.MockTransactionManager{
+				Repos: repositories.Repositories{Authors: mockAuthorRepo, Books: mockBookRepo},
+			}
+			svc := author.NewAuthorService(mockAuthorRepo, mockTx)
+			input := &models.Author{Name: "Nguyen Nhat Anh"}
+
+			if tt.expectTx {
+				mockTx.On("WithinTransaction", mock.Anything, mock.Anything).Return(nil)
+				mockAuthorRepo.On("CreateAuthor", mock.Anything, input).Run(func(args mock.Arguments) {
+					args.Get(1).(*models.Author).ID = 7
+				}).Return(nil)
+				mockBookRepo.On("CreateBook", mock.Anything, mock.AnythingOfType("*models.Book")).Return(tt.mockBookErr)
+			}
+
+			err := svc.CreateAuthorWithBooks(context.Background(), input, tt.books)
+
+			if tt.expectError {
+				assert.EqualError(t, err, tt.errorMessage)
+			} else {
+				assert.NoError(t, err)
+				for _, b := range tt.books {
+					assert.Equal(t, 7, b.AuthorID)
+				}
+			}
+			mockTx.AssertExpectations(t)
+			mockAuthorRepo.AssertExpectations(t)
+		})
+	}
+}
+
+func TestMergeAuthors(t *testing.T) {
+	tests := []struct {
+		name         string
+		targetID     int
+		sourceIDs    []int
+		expectIDs    []int
+		mockResult   *models.AuthorMergeResult
+		mockErr      error
+		expectError  bool
+		errorMessage string
+	}{
+		{
+			name:         "invalid target",
+			targetID:     0,
+			sourceIDs:    []int{2},
+			expectError:  true,
+			errorMessage: "invalid author ID",
+		},
+		{
+			name:         "no sources",
+			targetID:     1,
+			expectError:  true,
+			errorMessage: "source author IDs are required",
+		},
+		{
+			name:         "merge into itself",
+			targetID:     1,
+			sourceIDs:    []int{2, 1},
+			expectError:  true,
+			errorMessage: "cannot merge an author into itself",
+		},
+		{
+			name:         "repository error",
+			targetID:     1,
+			sourceIDs:    []int{9},
+			expectIDs:    []int{9},
+			mockErr:      repositories.ErrNotFound,
+			expectError:  true,
+			errorMessage: "failed to merge authors: not found",
+		},
+		{
+			name:       "duplicate source IDs are collapsed",
+			targetID:   1,
+			sourceIDs:  []int{2, 3, 2},
+			expectIDs:  []int{2, 3},
+			mockResult: &models.AuthorMergeResult{Author: &models.Author{ID: 1}, MergedIDs: []int{2, 3}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockRepo := new(mockrepo.MockAuthorRepository)
+			svc := author.NewAuthorService(mockRepo, new(mockrepo.MockTransactionManager))
+			if tt.expectIDs != nil {
+				mockRepo.On("Merge", mock.Anything, tt.targetID, tt.expectIDs).Return(tt.mockResult, tt.mockErr)
+			}
+
+			result, err := svc.MergeAuthors(context.Background(), tt.targetID, tt.sourceIDs)
+
+			if tt.expectError {
+				assert.Nil(t, result)
+				assert.EqualError(t, err, tt.errorMessage)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, tt.mockResult, result)
+			}
+			mockRepo.AssertExpectations(t)
+		})
+	}
+}
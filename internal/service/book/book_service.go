@@ -1,14 +1,25 @@
 package book
 
 import (
+	"context"
 	"errors"
+	"fmt"
 	"strings"
 	"time"
+	"unicode/utf8"
 
 	"github.com/maithuc2003/Test_GIN_golang/internal/interfaces/repositories"
+	"github.com/maithuc2003/Test_GIN_golang/internal/interfaces/service"
 	"github.com/maithuc2003/Test_GIN_golang/internal/models"
+	"github.com/maithuc2003/Test_GIN_golang/internal/tracing"
+	"github.com/maithuc2003/Test_GIN_golang/pkg/isbn"
+
+	"golang.org/x/text/currency"
+	"golang.org/x/text/language"
 )
 
+const maxDescriptionLength = 5000
+
 type BookService struct {
 	bookRepo repositories.BookRepository
 }
@@ -18,17 +29,32 @@ func NewBookService(bookRepo repositories.BookRepository) *BookService {
 
 }
 
-func (s *BookService) CreateBook(book *models.Book) error {
+func (s *BookService) CreateBook(ctx context.Context, book *models.Book) error {
+	ctx, span := tracing.Start(ctx, "BookService.CreateBook")
+	defer span.End()
+
 	if book.Title == "" || book.AuthorID == 0 {
 		return errors.New("invalid book data: title and author_id required")
 	}
+	if err := validateAuthorIDs(book.AuthorIDs); err != nil {
+		return err
+	}
+	if err := validateMetadata(book); err != nil {
+		return err
+	}
+	if err := validateCategoryIDs(book.CategoryIDs); err != nil {
+		return err
+	}
 	book.CreatedAt = time.Now()
 	book.UpdatedAt = time.Now()
-	return s.bookRepo.CreateBook(book)
+	return s.bookRepo.CreateBook(ctx, book)
 }
 
-func (s *BookService) GetAllBooks() ([]models.Book, error) {
-	books, err := s.bookRepo.GetAllBooks()
+func (s *BookService) GetAllBooks(ctx context.Context, filter models.BookFilter) ([]models.Book, error) {
+	ctx, span := tracing.Start(ctx, "BookService.GetAllBooks")
+	defer span.End()
+
+	books, err := s.bookRepo.GetAllBooks(ctx, filter)
 	if err != nil {
 		return nil, err
 	}
@@ -39,21 +65,83 @@ func (s *BookService) GetAllBooks() ([]models.Book, error) {
 
 	return books, nil
 }
-func (s *BookService) GetByBookID(id int) (*models.Book, error) {
+func (s *BookService) GetByBookID(ctx context.Context, id int) (*models.Book, error) {
+	ctx, span := tracing.Start(ctx, "BookService.GetByBookID")
+	defer span.End()
+
 	if id <= 0 {
 		return nil, errors.New("invalid book ID")
 	}
-	return s.bookRepo.GetByBookID(id)
+	return s.bookRepo.GetByBookID(ctx, id)
 }
 
-func (s *BookService) DeleteById(id int) (*models.Book, error) {
+func (s *BookService) GetByBookIDWithAuthors(ctx context.Context, id int) (*models.Book, error) {
+	ctx, span := tracing.Start(ctx, "BookService.GetByBookIDWithAuthors")
+	defer span.End()
+
 	if id <= 0 {
 		return nil, errors.New("invalid book ID")
 	}
-	return s.bookRepo.DeleteById(id)
+	return s.bookRepo.GetByBookIDWithAuthors(ctx, id)
 }
 
-func (s *BookService) UpdateById(book *models.Book) (*models.Book, error) {
+// GetBooksByAuthor trả về danh sách rỗng (không lỗi) nếu tác giả chưa có sách
+func (s *BookService) GetBooksByAuthor(ctx context.Context, authorID int, filter models.BookFilter) ([]models.Book, error) {
+	ctx, span := tracing.Start(ctx, "BookService.GetBooksByAuthor")
+	defer span.End()
+
+	if authorID <= 0 {
+		return nil, errors.New("invalid author ID")
+	}
+	books, err := s.bookRepo.GetBooksByAuthor(ctx, authorID, filter)
+	if err != nil {
+		return nil, err
+	}
+	if books == nil {
+		books = []models.Book{}
+	}
+	return books, nil
+}
+
+// GetBooksByCategory gồm cả sách của các thể loại con; trả về danh sách rỗng nếu không có sách
+func (s *BookService) GetBooksByCategory(ctx context.Context, categoryID uint, filter models.BookFilter) ([]models.Book, error) {
+	ctx, span := tracing.Start(ctx, "BookService.GetBooksByCategory")
+	defer span.End()
+
+	if categoryID == 0 {
+		return nil, errors.New("invalid category ID")
+	}
+	books, err := s.bookRepo.GetBooksByCategory(ctx, categoryID, filter)
+	if err != nil {
+		return nil, err
+	}
+	if books == nil {
+		books = []models.Book{}
+	}
+	return books, nil
+}
+
+func (s *BookService) GetCategoryFacets(ctx context.Context, filter models.BookFilter) ([]models.CategoryFacet, error) {
+	ctx, span := tracing.Start(ctx, "BookService.GetCategoryFacets")
+	defer span.End()
+
+	return s.bookRepo.CategoryFacets(ctx, filter)
+}
+
+func (s *BookService) DeleteById(ctx context.Context, id int) (*models.Book, error) {
+	ctx, span := tracing.Start(ctx, "BookService.DeleteById")
+	defer span.End()
+
+	if id <= 0 {
+		return nil, errors.New("invalid book ID")
+	}
+	return s.bookRepo.DeleteById(ctx, id)
+}
+
+func (s *BookService) UpdateById(ctx context.Context, book *models.Book) (*models.Book, error) {
+	ctx, span := tracing.Start(ctx, "BookService.UpdateById")
+	defer span.End()
+
 	if book == nil {
 		return nil, errors.New("book is nil")
 	}
@@ -69,6 +157,89 @@ func (s *BookService) UpdateById(book *models.Book) (*models.Book, error) {
 	if book.Stock < 0 {
 		return nil, errors.New("book quantity cannot be negative")
 	}
+	if err := validateAuthorIDs(book.AuthorIDs); err != nil {
+		return nil, err
+	}
+	if err := validateMetadata(book); err != nil {
+		return nil, err
+	}
+	if err := validateCategoryIDs(book.CategoryIDs); err != nil {
+		return nil, err
+	}
+
+	return s.bookRepo.UpdateById(ctx, book)
+}
+
+func validateCategoryIDs(ids []uint) error {
+	for _, id := range ids {
+		if id == 0 {
+			return fmt.Errorf("%w: invalid category ID", service.ErrInvalidInput)
+		}
+	}
+	return nil
+}
+
+func validateAuthorIDs(ids []int) error {
+	for _, id := range ids {
+		if id <= 0 {
+			return errors.New("invalid co-author ID")
+		}
+	}
+	return nil
+}
+
+// validateMetadata kiểm tra và chuẩn hoá ISBN, giá, tiền tệ, ngôn ngữ... của sách
+func validateMetadata(book *models.Book) error {
+	if book.ISBN != nil {
+		if strings.TrimSpace(*book.ISBN) == "" {
+			book.ISBN = nil
+		} else {
+			code, err := isbn.Normalize(*book.ISBN)
+			if err != nil {
+				return fmt.Errorf("%w: %v", service.ErrInvalidInput, err)
+			}
+			book.ISBN = &code
+		}
+	}
+	if book.PublisherID != nil && *book.PublisherID == 0 {
+		return fmt.Errorf("%w: invalid publisher ID", service.ErrInvalidInput)
+	}
 
-	return s.bookRepo.UpdateById(book)
+	if book.Price != nil {
+		if book.Price.IsNegative() {
+			return fmt.Errorf("%w: price cannot be negative", service.ErrInvalidInput)
+		}
+		if !book.Price.Equal(book.Price.Round(2)) {
+			return fmt.Errorf("%w: price supports at most 2 decimal places", service.ErrInvalidInput)
+		}
+		if book.Currency == "" {
+			return fmt.Errorf("%w: currency is required when price is set", service.ErrInvalidInput)
+		}
+	}
+	if book.Currency != "" {
+		unit, err := currency.ParseISO(strings.ToUpper(book.Currency))
+		if err != nil {
+			return fmt.Errorf("%w: unknown currency %q", service.ErrInvalidInput, book.Currency)
+		}
+		book.Currency = unit.String()
+	}
+
+	if book.Language != "" {
+		tag, err := language.Parse(book.Language)
+		if err != nil {
+			return fmt.Errorf("%w: invalid language tag %q", service.ErrInvalidInput, book.Language)
+		}
+		book.Language = tag.String()
+	}
+	if book.PageCount < 0 {
+		return fmt.Errorf("%w: page count cannot be negative", service.ErrInvalidInput)
+	}
+	// Cho phép sách sắp phát hành nhưng không quá một năm
+	if book.PublishedAt != nil && book.PublishedAt.After(time.Now().AddDate(1, 0, 0)) {
+		return fmt.Errorf("%w: publication date is too far in the future", service.ErrInvalidInput)
+	}
+	if utf8.RuneCountInString(book.Description) > maxDescriptionLength {
+		return fmt.Errorf("%w: description exceeds %d characters", service.ErrInvalidInput, maxDescriptionLength)
+	}
+	return nil
 }
@@ -1,12 +1,18 @@
 package book_test
 
 import (
+	"context"
 	"errors"
+	"strings"
 	"testing"
+	"time"
+
+	"github.com/shopspring/decimal"
 
 	"github.com/stretchr/testify/mock"
 	"github.com/stretchr/testify/require"
 
+	"github.com/maithuc2003/Test_GIN_golang/internal/interfaces/service"
 	mocks "github.com/maithuc2003/Test_GIN_golang/internal/mocks/repositories"
 	"github.com/maithuc2003/Test_GIN_golang/internal/models"
 	"github.com/maithuc2003/Test_GIN_golang/internal/service/book"
@@ -38,7 +44,7 @@ func TestCreateBook(t *testing.T) {
 				AuthorID: 1,
 			},
 			setupMock: func() {
-				mockRepo.On("CreateBook", mock.AnythingOfType("*models.Book")).Return(nil).Once()
+				mockRepo.On("CreateBook", mock.Anything, mock.AnythingOfType("*models.Book")).Return(nil).Once()
 			},
 			expectError: false,
 		},
@@ -47,7 +53,7 @@ func TestCreateBook(t *testing.T) {
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			tt.setupMock()
-			err := service.CreateBook(tt.input)
+			err := service.CreateBook(context.Background(), tt.input)
 			if tt.expectError {
 				require.Error(t, err)
 			} else {
@@ -92,8 +98,8 @@ func TestGetAllBooks(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			mockRepo.On("GetAllBooks").Return(tt.mockReturn, tt.mockError).Once()
-			result, err := service.GetAllBooks()
+			mockRepo.On("GetAllBooks", mock.Anything, mock.Anything).Return(tt.mockReturn, tt.mockError).Once()
+			result, err := service.GetAllBooks(context.Background(), models.BookFilter{})
 			if tt.expectError {
 				require.Error(t, err)
 				require.Nil(t, result)
@@ -134,9 +140,9 @@ func TestGetByBookID(t *testing.T) {
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			if tt.inputID > 0 {
-				mockRepo.On("GetByBookID", tt.inputID).Return(tt.mockReturn, tt.mockError).Once()
+				mockRepo.On("GetByBookID", mock.Anything, tt.inputID).Return(tt.mockReturn, tt.mockError).Once()
 			}
-			result, err := service.GetByBookID(tt.inputID)
+			result, err := service.GetByBookID(context.Background(), tt.inputID)
 			if tt.expectError {
 				require.Error(t, err)
 				require.Nil(t, result)
@@ -149,6 +155,98 @@ func TestGetByBookID(t *testing.T) {
 	}
 }
 
+func TestGetBooksByAuthor(t *testing.T) {
+	mockRepo := new(mocks.MockBookRepo)
+	service := book.NewBookService(mockRepo)
+
+	tests := []struct {
+		name        string
+		authorID    int
+		mockReturn  []models.Book
+		mockError   error
+		expectError bool
+		wantLen     int
+	}{
+		{
+			name:        "invalid author ID",
+			authorID:    0,
+			expectError: true,
+		},
+		{
+			name:       "author without books",
+			authorID:   2,
+			mockReturn: nil,
+			wantLen:    0,
+		},
+		{
+			name:       "author with books",
+			authorID:   3,
+			mockReturn: []models.Book{{ID: 1, Title: "Good Omens"}},
+			wantLen:    1,
+		},
+		{
+			name:        "repository error",
+			authorID:    4,
+			mockError:   errors.New("author with ID 4 not found"),
+			expectError: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if tt.authorID > 0 {
+				mockRepo.On("GetBooksByAuthor", mock.Anything, tt.authorID, models.BookFilter{}).Return(tt.mockReturn, tt.mockError).Once()
+			}
+			result, err := service.GetBooksByAuthor(context.Background(), tt.authorID, models.BookFilter{})
+			if tt.expectError {
+				require.Error(t, err)
+				require.Nil(t, result)
+			} else {
+				require.NoError(t, err)
+				require.NotNil(t, result)
+				require.Len(t, result, tt.wantLen)
+			}
+			mockRepo.AssertExpectations(t)
+		})
+	}
+}
+
+func TestGetBooksByCategory(t *testing.T) {
+	tests := []struct {
+		name        string
+		categoryID  uint
+		mockReturn  []models.Book
+		mockError   error
+		expectError bool
+		wantLen     int
+	}{
+		{name: "invalid category ID", categoryID: 0, expectError: true},
+		{name: "category without books", categoryID: 2, mockReturn: nil, wantLen: 0},
+		{name: "category with books", categoryID: 3, mockReturn: []models.Book{{ID: 1, Title: "The Hobbit"}}, wantLen: 1},
+		{name: "repository error", categoryID: 4, mockError: errors.New("category not found"), expectError: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockRepo := new(mocks.MockBookRepo)
+			svc := book.NewBookService(mockRepo)
+			if tt.categoryID > 0 {
+				mockRepo.On("GetBooksByCategory", mock.Anything, tt.categoryID, models.BookFilter{}).Return(tt.mockReturn, tt.mockError).Once()
+			}
+			result, err := svc.GetBooksByCategory(context.Background(), tt.categoryID, models.BookFilter{})
+			if tt.expectError {
+				require.Error(t, err)
+				require.Nil(t, result)
+			} else {
+				require.NoError(t, err)
+				require.NotNil(t, result)
+				require.Len(t, result, tt.wantLen)
+			}
+			mockRepo.AssertExpectations(t)
+		})
+	}
+}
+
 func TestDeleteById(t *testing.T) {
 	mockRepo := new(mocks.MockBookRepo)
 	service := book.NewBookService(mockRepo)
@@ -177,9 +275,9 @@ func TestDeleteById(t *testing.T) {
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			if tt.inputID > 0 {
-				mockRepo.On("DeleteById", tt.inputID).Return(tt.mockReturn, tt.mockError).Once()
+				mockRepo.On("DeleteById", mock.Anything, tt.inputID).Return(tt.mockReturn, tt.mockError).Once()
 			}
-			result, err := service.DeleteById(tt.inputID)
+			result, err := service.DeleteById(context.Background(), tt.inputID)
 			if tt.expectError {
 				require.Error(t, err)
 				require.Nil(t, result)
@@ -245,9 +343,9 @@ func TestUpdateById(t *testing.T) {
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			if tt.input != nil && tt.input.ID > 0 && tt.input.AuthorID > 0 && tt.input.Stock >= 0 && tt.input.Title != "" {
-				mockRepo.On("UpdateById", tt.input).Return(tt.mockReturn, tt.mockError).Once()
+				mockRepo.On("UpdateById", mock.Anything, tt.input).Return(tt.mockReturn, tt.mockError).Once()
 			}
-			result, err := service.UpdateById(tt.input)
+			result, err := service.UpdateById(context.Background(), tt.input)
 			if tt.expectError {
 				require.Error(t, err)
 				require.Nil(t, result)
@@ -259,3 +357,59 @@ func TestUpdateById(t *testing.T) {
 		})
 	}
 }
+
+func TestCreateBook_Metadata(t *testing.T) {
+	strPtr := func(s string) *string { return &s }
+	price := func(s string) *decimal.Decimal { d := decimal.RequireFromString(s); return &d }
+	future := time.Now().AddDate(2, 0, 0)
+	zero := uint(0)
+
+	tests := []struct {
+		name         string
+		input        *models.Book
+		expectError  bool
+		wantISBN     string
+		wantCurrency string
+		wantLanguage string
+	}{
+		{
+			name:         "valid metadata is normalized",
+			input:        &models.Book{Title: "Mắt Biếc", AuthorID: 1, ISBN: strPtr("0-306-40615-2"), Price: price("89000"), Currency: "vnd", Language: "VI"},
+			wantISBN:     "9780306406157",
+			wantCurrency: "VND",
+			wantLanguage: "vi",
+		},
+		{name: "bad isbn checksum", input: &models.Book{Title: "Go", AuthorID: 1, ISBN: strPtr("9780306406158")}, expectError: true},
+		{name: "negative price", input: &models.Book{Title: "Go", AuthorID: 1, Price: price("-1"), Currency: "USD"}, expectError: true},
+		{name: "too many decimals", input: &models.Book{Title: "Go", AuthorID: 1, Price: price("1.999"), Currency: "USD"}, expectError: true},
+		{name: "price without currency", input: &models.Book{Title: "Go", AuthorID: 1, Price: price("10")}, expectError: true},
+		{name: "unknown currency", input: &models.Book{Title: "Go", AuthorID: 1, Price: price("10"), Currency: "ABC"}, expectError: true},
+		{name: "invalid language", input: &models.Book{Title: "Go", AuthorID: 1, Language: "not a tag"}, expectError: true},
+		{name: "negative page count", input: &models.Book{Title: "Go", AuthorID: 1, PageCount: -5}, expectError: true},
+		{name: "publication too far ahead", input: &models.Book{Title: "Go", AuthorID: 1, PublishedAt: &future}, expectError: true},
+		{name: "zero publisher ID", input: &models.Book{Title: "Go", AuthorID: 1, PublisherID: &zero}, expectError: true},
+		{name: "description too long", input: &models.Book{Title: "Go", AuthorID: 1, Description: strings.Repeat("a", 5001)}, expectError: true},
+		{name: "zero category ID", input: &models.Book{Title: "Go", AuthorID: 1, CategoryIDs: []uint{3, 0}}, expectError: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockRepo := new(mocks.MockBookRepo)
+			svc := book.NewBookService(mockRepo)
+			if !tt.expectError {
+				mockRepo.On("CreateBook", mock.Anything, tt.input).Return(nil).Once()
+			}
+
+			err := svc.CreateBook(context.Background(), tt.input)
+			if tt.expectError {
+				require.ErrorIs(t, err, service.ErrInvalidInput)
+			} else {
+				require.NoError(t, err)
+				require.Equal(t, tt.wantISBN, *tt.input.ISBN)
+				require.Equal(t, tt.wantCurrency, tt.input.Currency)
+				require.Equal(t, tt.wantLanguage, tt.input.Language)
+			}
+			mockRepo.AssertExpectations(t)
+		})
+	}
+}
@@ -0,0 +1,228 @@
+package cart
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/maithuc2003/Test_GIN_golang/internal/interfaces/repositories"
+	"github.com/maithuc2003/Test_GIN_golang/internal/interfaces/service"
+	"github.com/maithuc2003/Test_GIN_golang/internal/metrics"
+	"github.com/maithuc2003/Test_GIN_golang/internal/models"
+	"github.com/maithuc2003/Test_GIN_golang/internal/pricing"
+	"github.com/maithuc2003/Test_GIN_golang/internal/tracing"
+	"github.com/shopspring/decimal"
+)
+
+// maxLineQuantity giới hạn số lượng một đầu sách trong giỏ
+const maxLineQuantity = 99
+
+type CartService struct {
+	cartRepo  repositories.CartRepository
+	bookRepo  repositories.BookRepository
+	txManager repositories.TransactionManager
+	// calculator chốt giá và thuế cho các đơn tạo ra khi checkout
+	calculator *pricing.Calculator
+	// ttl là thời gian giỏ không thay đổi trước khi hết hạn
+	ttl time.Duration
+	// reservationTTL là thời gian giữ hàng cho các đơn tạo ra khi checkout
+	reservationTTL time.Duration
+}
+
+func NewCartService(cartRepo repositories.CartRepository, bookRepo repositories.BookRepository, txManager repositories.TransactionManager, calculator *pricing.Calculator, ttl, reservationTTL time.Duration) *CartService {
+	return &CartService{cartRepo: cartRepo, bookRepo: bookRepo, txManager: txManager, calculator: calculator, ttl: ttl, reservationTTL: reservationTTL}
+}
+
+func (s *CartService) GetCart(ctx context.Context, userID uint) (*models.CartView, error) {
+	ctx, span := tracing.Start(ctx, "CartService.GetCart")
+	defer span.End()
+
+	cart, err := s.activeCart(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	return buildView(userID, cart), nil
+}
+
+func (s *CartService) AddItem(ctx context.Context, userID uint, item models.CartItemRequest) (*models.CartView, error) {
+	ctx, span := tracing.Start(ctx, "CartService.AddItem")
+	defer span.End()
+
+	if item.Quantity <= 0 {
+		return nil, fmt.Errorf("%w: quantity must be greater than zero", service.ErrInvalidInput)
+	}
+	cart, err := s.activeCart(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	current := 0
+	if cart != nil {
+		for _, line := range cart.Items {
+			if line.BookID == item.BookID {
+				current = line.Quantity
+			}
+		}
+	}
+	return s.setQuantity(ctx, userID, item.BookID, current+item.Quantity)
+}
+
+func (s *CartService) UpdateItem(ctx context.Context, userID uint, item models.CartItemRequest) (*models.CartView, error) {
+	ctx, span := tracing.Start(ctx, "CartService.UpdateItem")
+	defer span.End()
+
+	if item.Quantity < 0 {
+		return nil, fmt.Errorf("%w: quantity cannot be negative", service.ErrInvalidInput)
+	}
+	if item.Quantity == 0 {
+		return s.RemoveItem(ctx, userID, item.BookID)
+	}
+	return s.setQuantity(ctx, userID, item.BookID, item.Quantity)
+}
+
+func (s *CartService) RemoveItem(ctx context.Context, userID, bookID uint) (*models.CartView, error) {
+	ctx, span := tracing.Start(ctx, "CartService.RemoveItem")
+	defer span.End()
+
+	if err := validateIDs(userID, bookID); err != nil {
+		return nil, err
+	}
+	if err := s.cartRepo.RemoveItem(ctx, userID, bookID, time.Now().Add(s.ttl)); err != nil {
+		return nil, err
+	}
+	return s.GetCart(ctx, userID)
+}
+
+// Checkout chốt giá rồi tạo đơn pending qua orderRepo.Create (khoá dòng sách và giữ
+// hàng trong reservationTTL) cho từng dòng trong cùng một transaction: một dòng thiếu hàng thì
+// không đơn nào được tạo và giỏ giữ nguyên.
+func (s *CartService) Checkout(ctx context.Context, userID uint) ([]*models.Order, error) {
+	ctx, span := tracing.Start(ctx, "CartService.Checkout")
+	defer span.End()
+
+	cart, err := s.activeCart(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if cart == nil || len(cart.Items) == 0 {
+		return nil, fmt.Errorf("%w: cart is empty", service.ErrInvalidInput)
+	}
+
+	var orders []*models.Order
+	reservedUntil := time.Now().Add(s.reservationTTL)
+	err = s.txManager.WithinTransaction(ctx, func(ctx context.Context, repos repositories.Repositories) error {
+		for _, item := range cart.Items {
+			order := &models.Order{
+				BookID:        item.BookID,
+				UserID:        userID,
+				Quantity:      item.Quantity,
+				Status:        models.OrderStatusPending,
+				OrderedAt:     time.Now(),
+				ReservedUntil: &reservedUntil,
+			}
+			if err := s.calculator.Price(ctx, repos, order); err != nil {
+				return fmt.Errorf("book %d: %w", item.BookID, err)
+			}
+			if err := repos.Orders.Create(ctx, order); err != nil {
+				return fmt.Errorf("book %d: %w", item.BookID, err)
+			}
+			orders = append(orders, order)
+		}
+		return repos.Carts.Clear(ctx, userID)
+	})
+	if err != nil {
+		if errors.Is(err, repositories.ErrInsufficientStock) {
+			metrics.StockOuts.WithLabelValues(metrics.SourceCheckout).Inc()
+		}
+		return nil, fmt.Errorf("checkout failed: %w", err)
+	}
+	metrics.OrdersCreated.WithLabelValues(metrics.SourceCheckout).Add(float64(len(orders)))
+	return orders, nil
+}
+
+func (s *CartService) PurgeExpired(ctx context.Context) (int64, error) {
+	ctx, span := tracing.Start(ctx, "CartService.PurgeExpired")
+	defer span.End()
+
+	return s.cartRepo.DeleteExpired(ctx, time.Now())
+}
+
+// setQuantity kiểm tra sách và tồn kho còn bán được rồi ghi số lượng mới cho dòng trong giỏ
+func (s *CartService) setQuantity(ctx context.Context, userID, bookID uint, quantity int) (*models.CartView, error) {
+	if err := validateIDs(userID, bookID); err != nil {
+		return nil, err
+	}
+	if quantity > maxLineQuantity {
+		return nil, fmt.Errorf("%w: at most %d copies per book", service.ErrInvalidInput, maxLineQuantity)
+	}
+	book, err := s.bookRepo.GetByBookID(ctx, int(bookID))
+	if err != nil {
+		return nil, err
+	}
+	if book.AvailableStock < quantity {
+		return nil, fmt.Errorf("%w: only %d copies of book %d left", repositories.ErrInsufficientStock, book.AvailableStock, bookID)
+	}
+
+	if err := s.cartRepo.SaveItem(ctx, userID, bookID, quantity, time.Now().Add(s.ttl)); err != nil {
+		return nil, err
+	}
+	return s.GetCart(ctx, userID)
+}
+
+// activeCart trả về nil (không lỗi) khi user chưa có giỏ hoặc giỏ đã hết hạn
+func (s *CartService) activeCart(ctx context.Context, userID uint) (*models.Cart, error) {
+	if userID == 0 {
+		return nil, fmt.Errorf("%w: invalid user ID", service.ErrInvalidInput)
+	}
+	cart, err := s.cartRepo.GetCart(ctx, userID)
+	if err != nil {
+		if errors.Is(err, repositories.ErrNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	if cart.ExpiresAt.Before(time.Now()) {
+		return nil, nil
+	}
+	return cart, nil
+}
+
+func validateIDs(userID, bookID uint) error {
+	if userID == 0 {
+		return fmt.Errorf("%w: invalid user ID", service.ErrInvalidInput)
+	}
+	if bookID == 0 {
+		return fmt.Errorf("%w: invalid book ID", service.ErrInvalidInput)
+	}
+	return nil
+}
+
+// buildView tính giá và tình trạng tồn kho theo dữ liệu sách hiện tại
+func buildView(userID uint, cart *models.Cart) *models.CartView {
+	view := &models.CartView{UserID: userID, Lines: []models.CartLine{}, Totals: map[string]decimal.Decimal{}}
+	if cart == nil {
+		return view
+	}
+	expiresAt := cart.ExpiresAt
+	view.ExpiresAt = &expiresAt
+
+	for _, item := range cart.Items {
+		line := models.CartLine{BookID: item.BookID, Quantity: item.Quantity}
+		if book := item.Book; book != nil {
+			line.Title = book.Title
+			line.Stock = book.Stock
+			line.AvailableStock = book.AvailableStock
+			line.Available = book.AvailableStock >= item.Quantity
+			line.Currency = book.Currency
+			if book.Price != nil {
+				price := *book.Price
+				total := price.Mul(decimal.NewFromInt(int64(item.Quantity)))
+				line.UnitPrice = &price
+				line.LineTotal = &total
+				view.Totals[book.Currency] = view.Totals[book.Currency].Add(total)
+			}
+		}
+		view.Lines = append(view.Lines, line)
+	}
+	return view
+}
@@ -0,0 +1,220 @@
+package cart_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+
+	"github.com/maithuc2003/Test_GIN_golang/internal/interfaces/repositories"
+	"github.com/maithuc2003/Test_GIN_golang/internal/interfaces/service"
+	mocks "github.com/maithuc2003/Test_GIN_golang/internal/mocks/repositories"
+	"github.com/maithuc2003/Test_GIN_golang/internal/models"
+	"github.com/maithuc2003/Test_GIN_golang/internal/pricing"
+	"github.com/maithuc2003/Test_GIN_golang/internal/repositories/book"
+	cartrepo "github.com/maithuc2003/Test_GIN_golang/internal/repositories/cart"
+	"github.com/maithuc2003/Test_GIN_golang/internal/repositories/transaction"
+	"github.com/maithuc2003/Test_GIN_golang/internal/service/cart"
+	sqlitedriver "gorm.io/driver/sqlite"
+
+	_ "modernc.org/sqlite"
+)
+
+func price(s string) *decimal.Decimal {
+	d := decimal.RequireFromString(s)
+	return &d
+}
+
+func TestGetCart(t *testing.T) {
+	tests := []struct {
+		name       string
+		mockCart   *models.Cart
+		mockErr    error
+		wantLines  int
+		wantTotals map[string]string
+		wantErr    bool
+	}{
+		{name: "no cart", mockErr: fmt.Errorf("%w: cart", repositories.ErrNotFound), wantTotals: map[string]string{}},
+		{name: "expired cart", mockCart: &models.Cart{ExpiresAt: time.Now().Add(-time.Hour), Items: []models.CartItem{{BookID: 1, Quantity: 1}}}, wantTotals: map[string]string{}},
+		{name: "repo error", mockErr: errors.New("db down"), wantErr: true},
+		{
+			name: "priced lines",
+			mockCart: &models.Cart{ExpiresAt: time.Now().Add(time.Hour), Items: []models.CartItem{
+				{BookID: 1, Quantity: 2, Book: &models.Book{Title: "Dune", Stock: 5, AvailableStock: 5, Price: price("10.50"), Currency: "USD"}},
+				{BookID: 2, Quantity: 3, Book: &models.Book{Title: "Emma", Stock: 3, AvailableStock: 1, Price: price("4"), Currency: "USD"}},
+				{BookID: 3, Quantity: 1, Book: &models.Book{Title: "Mắt Biếc", Stock: 2, AvailableStock: 2, Price: price("89000"), Currency: "VND"}},
+			}},
+			wantLines:  3,
+			wantTotals: map[string]string{"USD": "33", "VND": "89000"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockRepo := new(mocks.MockCartRepo)
+			svc := cart.NewCartService(mockRepo, new(mocks.MockBookRepo), new(mocks.MockTransactionManager), pricing.NewCalculator(nil), time.Hour, 15*time.Minute)
+			mockRepo.On("GetCart", mock.Anything, uint(7)).Return(tt.mockCart, tt.mockErr)
+
+			view, err := svc.GetCart(context.Background(), 7)
+			if tt.wantErr {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			require.Len(t, view.Lines, tt.wantLines)
+			totals := map[string]string{}
+			for currency, total := range view.Totals {
+				totals[currency] = total.String()
+			}
+			require.Equal(t, tt.wantTotals, totals)
+			if tt.wantLines > 0 {
+				require.True(t, view.Lines[0].Available)
+				require.False(t, view.Lines[1].Available, "available stock below cart quantity")
+			}
+		})
+	}
+}
+
+func TestAddItem(t *testing.T) {
+	existing := &models.Cart{ExpiresAt: time.Now().Add(time.Hour), Items: []models.CartItem{{BookID: 1, Quantity: 2}}}
+
+	tests := []struct {
+		name      string
+		req       models.CartItemRequest
+		book      *models.Book
+		bookErr   error
+		wantQty   int
+		wantErrIs error
+	}{
+		{name: "zero quantity", req: models.CartItemRequest{BookID: 1, Quantity: 0}, wantErrIs: service.ErrInvalidInput},
+		{name: "missing book ID", req: models.CartItemRequest{Quantity: 1}, wantErrIs: service.ErrInvalidInput},
+		{name: "adds to existing line", req: models.CartItemRequest{BookID: 1, Quantity: 3}, book: &models.Book{ID: 1, Stock: 10, AvailableStock: 10}, wantQty: 5},
+		{name: "exceeds stock", req: models.CartItemRequest{BookID: 1, Quantity: 3}, book: &models.Book{ID: 1, Stock: 6, AvailableStock: 4}, wantErrIs: repositories.ErrInsufficientStock},
+		{name: "exceeds line limit", req: models.CartItemRequest{BookID: 1, Quantity: 98}, wantErrIs: service.ErrInvalidInput},
+		{name: "unknown book", req: models.CartItemRequest{BookID: 9, Quantity: 1}, bookErr: fmt.Errorf("book with ID 9 %w", repositories.ErrNotFound), wantErrIs: repositories.ErrNotFound},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cartRepo := new(mocks.MockCartRepo)
+			bookRepo := new(mocks.MockBookRepo)
+			svc := cart.NewCartService(cartRepo, bookRepo, new(mocks.MockTransactionManager), pricing.NewCalculator(nil), time.Hour, 15*time.Minute)
+
+			cartRepo.On("GetCart", mock.Anything, uint(7)).Return(existing, nil).Maybe()
+			if tt.book != nil || tt.bookErr != nil {
+				bookRepo.On("GetByBookID", mock.Anything, int(tt.req.BookID)).Return(tt.book, tt.bookErr)
+			}
+			if tt.wantQty > 0 {
+				cartRepo.On("SaveItem", mock.Anything, uint(7), tt.req.BookID, tt.wantQty, mock.AnythingOfType("time.Time")).Return(nil)
+			}
+
+			_, err := svc.AddItem(context.Background(), 7, tt.req)
+			if tt.wantErrIs != nil {
+				require.ErrorIs(t, err, tt.wantErrIs)
+			} else {
+				require.NoError(t, err)
+			}
+			cartRepo.AssertExpectations(t)
+			bookRepo.AssertExpectations(t)
+		})
+	}
+}
+
+func TestUpdateItem_ZeroRemoves(t *testing.T) {
+	cartRepo := new(mocks.MockCartRepo)
+	svc := cart.NewCartService(cartRepo, new(mocks.MockBookRepo), new(mocks.MockTransactionManager), pricing.NewCalculator(nil), time.Hour, 15*time.Minute)
+	cartRepo.On("RemoveItem", mock.Anything, uint(7), uint(1), mock.AnythingOfType("time.Time")).Return(nil)
+	cartRepo.On("GetCart", mock.Anything, uint(7)).Return(nil, repositories.ErrNotFound)
+
+	view, err := svc.UpdateItem(context.Background(), 7, models.CartItemRequest{BookID: 1, Quantity: 0})
+	require.NoError(t, err)
+	require.Empty(t, view.Lines)
+	cartRepo.AssertExpectations(t)
+}
+
+func setupCheckoutDB(t *testing.T) *gorm.DB {
+	dsn := fmt.Sprintf("file:testdb_%d?mode=memory&cache=shared", time.Now().UnixNano())
+	db, err := gorm.Open(sqlitedriver.New(sqlitedriver.Config{DSN: dsn, DriverName: "sqlite"}), &gorm.Config{})
+	require.NoError(t, err)
+	require.NoError(t, db.AutoMigrate(&models.Author{}, &models.Book{}, &models.Order{}, &models.OrderDiscount{}, &models.Cart{}, &models.CartItem{}, &models.StockReservation{}, &models.OutboxEvent{}))
+	return db
+}
+
+func TestCheckout(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("creates orders and clears cart", func(t *testing.T) {
+		db := setupCheckoutDB(t)
+		dune := models.Book{Title: "Dune", AuthorID: 1, Stock: 5, Price: price("10.50"), Currency: "USD"}
+		emma := models.Book{Title: "Emma", AuthorID: 1, Stock: 2, Price: price("8.00"), Currency: "USD"}
+		require.NoError(t, db.Create(&dune).Error)
+		require.NoError(t, db.Create(&emma).Error)
+
+		taxes := &pricing.TaxTable{DefaultRegion: "US-NY", Rules: []pricing.TaxRule{{Region: "US-NY", Rate: decimal.RequireFromString("0.08")}}}
+		svc := cart.NewCartService(cartrepo.NewCartRepo(db), book.NewRepository(db), transaction.NewTransactionManager(db), pricing.NewCalculator(taxes), time.Hour, 15*time.Minute)
+		_, err := svc.AddItem(ctx, 7, models.CartItemRequest{BookID: dune.ID, Quantity: 3})
+		require.NoError(t, err)
+		_, err = svc.AddItem(ctx, 7, models.CartItemRequest{BookID: emma.ID, Quantity: 2})
+		require.NoError(t, err)
+
+		orders, err := svc.Checkout(ctx, 7)
+		require.NoError(t, err)
+		require.Len(t, orders, 2)
+		require.Equal(t, "pending", orders[0].Status)
+		// 3 × 10.50 = 31.50, thuế 8% = 2.52
+		require.True(t, decimal.RequireFromString("31.50").Equal(orders[0].Subtotal))
+		require.True(t, decimal.RequireFromString("2.52").Equal(orders[0].TaxTotal))
+		require.True(t, decimal.RequireFromString("34.02").Equal(orders[0].GrandTotal))
+		require.Equal(t, "US-NY", orders[0].TaxRegion)
+
+		// Checkout chỉ giữ hàng, tồn kho thực tế chưa bị trừ
+		stored, err := book.NewRepository(db).GetByBookID(ctx, int(dune.ID))
+		require.NoError(t, err)
+		require.Equal(t, 5, stored.Stock)
+		require.Equal(t, 2, stored.AvailableStock)
+		view, err := svc.GetCart(ctx, 7)
+		require.NoError(t, err)
+		require.Empty(t, view.Lines)
+	})
+
+	t.Run("insufficient stock rolls back every line", func(t *testing.T) {
+		db := setupCheckoutDB(t)
+		dune := models.Book{Title: "Dune", AuthorID: 1, Stock: 5, Price: price("10.50"), Currency: "USD"}
+		emma := models.Book{Title: "Emma", AuthorID: 1, Stock: 2, Price: price("8.00"), Currency: "USD"}
+		require.NoError(t, db.Create(&dune).Error)
+		require.NoError(t, db.Create(&emma).Error)
+
+		svc := cart.NewCartService(cartrepo.NewCartRepo(db), book.NewRepository(db), transaction.NewTransactionManager(db), pricing.NewCalculator(nil), time.Hour, 15*time.Minute)
+		_, err := svc.AddItem(ctx, 7, models.CartItemRequest{BookID: dune.ID, Quantity: 3})
+		require.NoError(t, err)
+		_, err = svc.AddItem(ctx, 7, models.CartItemRequest{BookID: emma.ID, Quantity: 2})
+		require.NoError(t, err)
+		// Người khác mua hết sách trước khi checkout
+		require.NoError(t, db.Model(&emma).Update("stock", 1).Error)
+
+		_, err = svc.Checkout(ctx, 7)
+		require.ErrorIs(t, err, repositories.ErrInsufficientStock)
+
+		var orders int64
+		require.NoError(t, db.Model(&models.Order{}).Count(&orders).Error)
+		require.Zero(t, orders)
+		require.NoError(t, db.First(&dune, dune.ID).Error)
+		require.Equal(t, 5, dune.Stock)
+		view, err := svc.GetCart(ctx, 7)
+		require.NoError(t, err)
+		require.Len(t, view.Lines, 2, "cart is kept when checkout fails")
+	})
+
+	t.Run("empty cart", func(t *testing.T) {
+		db := setupCheckoutDB(t)
+		svc := cart.NewCartService(cartrepo.NewCartRepo(db), book.NewRepository(db), transaction.NewTransactionManager(db), pricing.NewCalculator(nil), time.Hour, 15*time.Minute)
+		_, err := svc.Checkout(ctx, 7)
+		require.ErrorIs(t, err, service.ErrInvalidInput)
+	})
+}
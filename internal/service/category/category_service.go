@@ -0,0 +1,130 @@
+package category
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/maithuc2003/Test_GIN_golang/internal/interfaces/repositories"
+	"github.com/maithuc2003/Test_GIN_golang/internal/interfaces/service"
+	"github.com/maithuc2003/Test_GIN_golang/internal/models"
+	"github.com/maithuc2003/Test_GIN_golang/internal/tracing"
+)
+
+type CategoryService struct {
+	repo repositories.CategoryRepository
+}
+
+func NewCategoryService(repo repositories.CategoryRepository) *CategoryService {
+	return &CategoryService{repo: repo}
+}
+
+// GetCategoryTree dựng cây từ danh sách phẳng; thứ tự con giữ theo thứ tự repo trả về
+func (s *CategoryService) GetCategoryTree(ctx context.Context) ([]models.Category, error) {
+	ctx, span := tracing.Start(ctx, "CategoryService.GetCategoryTree")
+	defer span.End()
+
+	categories, err := s.repo.GetAllCategories(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	children := make(map[uint][]models.Category)
+	var roots []models.Category
+	for _, c := range categories {
+		if c.ParentID == nil {
+			roots = append(roots, c)
+		} else {
+			children[*c.ParentID] = append(children[*c.ParentID], c)
+		}
+	}
+
+	var attach func(nodes []models.Category) []models.Category
+	attach = func(nodes []models.Category) []models.Category {
+		for i := range nodes {
+			nodes[i].Children = attach(children[nodes[i].ID])
+		}
+		return nodes
+	}
+	if roots == nil {
+		return []models.Category{}, nil
+	}
+	return attach(roots), nil
+}
+
+func (s *CategoryService) GetByCategoryID(ctx context.Context, id uint) (*models.Category, error) {
+	ctx, span := tracing.Start(ctx, "CategoryService.GetByCategoryID")
+	defer span.End()
+
+	if id == 0 {
+		return nil, errors.New("invalid category ID")
+	}
+	return s.repo.GetByCategoryID(ctx, id)
+}
+
+func (s *CategoryService) CreateCategory(ctx context.Context, category *models.Category) error {
+	ctx, span := tracing.Start(ctx, "CategoryService.CreateCategory")
+	defer span.End()
+
+	if err := validateCategory(category); err != nil {
+		return err
+	}
+	now := time.Now()
+	category.CreatedAt = now
+	category.UpdatedAt = now
+	return s.repo.CreateCategory(ctx, category)
+}
+
+func (s *CategoryService) UpdateById(ctx context.Context, category *models.Category) (*models.Category, error) {
+	ctx, span := tracing.Start(ctx, "CategoryService.UpdateById")
+	defer span.End()
+
+	if err := validateCategory(category); err != nil {
+		return nil, err
+	}
+	if category.ID == 0 {
+		return nil, errors.New("invalid category ID")
+	}
+
+	// Không cho chuyển thể loại vào chính nó hoặc vào một thể loại con cháu (tạo vòng)
+	if category.ParentID != nil {
+		descendants, err := s.repo.DescendantIDs(ctx, category.ID)
+		if err != nil {
+			return nil, err
+		}
+		for _, id := range descendants {
+			if id == *category.ParentID {
+				return nil, fmt.Errorf("%w: category cannot be moved under itself or its descendants", service.ErrInvalidInput)
+			}
+		}
+	}
+
+	category.UpdatedAt = time.Now()
+	return s.repo.UpdateById(ctx, category)
+}
+
+func (s *CategoryService) DeleteById(ctx context.Context, id uint) (*models.Category, error) {
+	ctx, span := tracing.Start(ctx, "CategoryService.DeleteById")
+	defer span.End()
+
+	if id == 0 {
+		return nil, errors.New("invalid category ID")
+	}
+	return s.repo.DeleteById(ctx, id)
+}
+
+func validateCategory(category *models.Category) error {
+	if category == nil {
+		return errors.New("category is nil")
+	}
+	category.Name = strings.TrimSpace(category.Name)
+	if category.Name == "" {
+		return fmt.Errorf("%w: category name cannot be empty", service.ErrInvalidInput)
+	}
+	if category.ParentID != nil && *category.ParentID == 0 {
+		category.ParentID = nil
+	}
+	return nil
+}
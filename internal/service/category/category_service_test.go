@@ -0,0 +1,133 @@
+package category_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/maithuc2003/Test_GIN_golang/internal/interfaces/service"
+	mocks "github.com/maithuc2003/Test_GIN_golang/internal/mocks/repositories"
+	"github.com/maithuc2003/Test_GIN_golang/internal/models"
+	"github.com/maithuc2003/Test_GIN_golang/internal/service/category"
+)
+
+func uintPtr(v uint) *uint { return &v }
+
+func TestGetCategoryTree(t *testing.T) {
+	tests := []struct {
+		name       string
+		mockReturn []models.Category
+		mockErr    error
+		wantRoots  int
+		wantErr    bool
+	}{
+		{name: "empty", mockReturn: nil, wantRoots: 0},
+		{name: "repo error", mockErr: errors.New("db error"), wantErr: true},
+		{
+			name: "nested",
+			mockReturn: []models.Category{
+				{ID: 1, Name: "Fiction"},
+				{ID: 2, Name: "Fantasy", ParentID: uintPtr(1)},
+				{ID: 3, Name: "Epic", ParentID: uintPtr(2)},
+				{ID: 4, Name: "Science"},
+			},
+			wantRoots: 2,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockRepo := new(mocks.MockCategoryRepo)
+			svc := category.NewCategoryService(mockRepo)
+			mockRepo.On("GetAllCategories", mock.Anything).Return(tt.mockReturn, tt.mockErr)
+
+			tree, err := svc.GetCategoryTree(context.Background())
+			if tt.wantErr {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			require.NotNil(t, tree)
+			require.Len(t, tree, tt.wantRoots)
+			if tt.wantRoots == 2 {
+				require.Equal(t, "Epic", tree[0].Children[0].Children[0].Name)
+				require.Empty(t, tree[1].Children)
+			}
+		})
+	}
+}
+
+func TestCreateCategory(t *testing.T) {
+	tests := []struct {
+		name      string
+		input     *models.Category
+		callsRepo bool
+		wantErr   error
+	}{
+		{name: "nil category", input: nil, wantErr: errors.New("category is nil")},
+		{name: "empty name", input: &models.Category{Name: "  "}, wantErr: service.ErrInvalidInput},
+		{name: "success", input: &models.Category{Name: " Fantasy ", ParentID: uintPtr(0)}, callsRepo: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockRepo := new(mocks.MockCategoryRepo)
+			svc := category.NewCategoryService(mockRepo)
+			if tt.callsRepo {
+				mockRepo.On("CreateCategory", mock.Anything, tt.input).Return(nil).Once()
+			}
+
+			err := svc.CreateCategory(context.Background(), tt.input)
+			if tt.wantErr != nil {
+				require.Error(t, err)
+				if errors.Is(tt.wantErr, service.ErrInvalidInput) {
+					require.ErrorIs(t, err, service.ErrInvalidInput)
+				}
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, "Fantasy", tt.input.Name)
+			require.Nil(t, tt.input.ParentID)
+			mockRepo.AssertExpectations(t)
+		})
+	}
+}
+
+func TestUpdateCategory(t *testing.T) {
+	tests := []struct {
+		name        string
+		input       *models.Category
+		descendants []uint
+		callsRepo   bool
+		wantInvalid bool
+	}{
+		{name: "move under itself", input: &models.Category{ID: 1, Name: "Fiction", ParentID: uintPtr(1)}, descendants: []uint{1, 2}, wantInvalid: true},
+		{name: "move under descendant", input: &models.Category{ID: 1, Name: "Fiction", ParentID: uintPtr(2)}, descendants: []uint{1, 2}, wantInvalid: true},
+		{name: "move under other branch", input: &models.Category{ID: 2, Name: "Fantasy", ParentID: uintPtr(5)}, descendants: []uint{2, 3}, callsRepo: true},
+		{name: "make root", input: &models.Category{ID: 2, Name: "Fantasy"}, callsRepo: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockRepo := new(mocks.MockCategoryRepo)
+			svc := category.NewCategoryService(mockRepo)
+			if tt.descendants != nil {
+				mockRepo.On("DescendantIDs", mock.Anything, tt.input.ID).Return(tt.descendants, nil)
+			}
+			if tt.callsRepo {
+				mockRepo.On("UpdateById", mock.Anything, tt.input).Return(tt.input, nil).Once()
+			}
+
+			_, err := svc.UpdateById(context.Background(), tt.input)
+			if tt.wantInvalid {
+				require.ErrorIs(t, err, service.ErrInvalidInput)
+			} else {
+				require.NoError(t, err)
+			}
+			mockRepo.AssertExpectations(t)
+		})
+	}
+}
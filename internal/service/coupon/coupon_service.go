@@ -0,0 +1,150 @@
+package coupon
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/maithuc2003/Test_GIN_golang/internal/interfaces/repositories"
+	"github.com/maithuc2003/Test_GIN_golang/internal/interfaces/service"
+	"github.com/maithuc2003/Test_GIN_golang/internal/models"
+	"github.com/maithuc2003/Test_GIN_golang/internal/promotion"
+	"github.com/maithuc2003/Test_GIN_golang/internal/tracing"
+	"github.com/shopspring/decimal"
+	"golang.org/x/text/currency"
+)
+
+var codePattern = regexp.MustCompile(`^[A-Z0-9_-]{3,64}$`)
+
+type CouponService struct {
+	repo repositories.CouponRepository
+}
+
+func NewCouponService(repo repositories.CouponRepository) *CouponService {
+	return &CouponService{repo: repo}
+}
+
+func (s *CouponService) GetAllCoupons(ctx context.Context) ([]models.Coupon, error) {
+	ctx, span := tracing.Start(ctx, "CouponService.GetAllCoupons")
+	defer span.End()
+
+	coupons, err := s.repo.List(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if coupons == nil {
+		coupons = []models.Coupon{}
+	}
+	return coupons, nil
+}
+
+func (s *CouponService) GetByCouponID(ctx context.Context, id uint) (*models.Coupon, error) {
+	ctx, span := tracing.Start(ctx, "CouponService.GetByCouponID")
+	defer span.End()
+
+	if id == 0 {
+		return nil, fmt.Errorf("%w: invalid coupon ID", service.ErrInvalidInput)
+	}
+	return s.repo.GetByID(ctx, id)
+}
+
+func (s *CouponService) CreateCoupon(ctx context.Context, coupon *models.Coupon) error {
+	ctx, span := tracing.Start(ctx, "CouponService.CreateCoupon")
+	defer span.End()
+
+	if err := validateCoupon(coupon); err != nil {
+		return err
+	}
+	now := time.Now()
+	coupon.ID = 0
+	coupon.UsedCount = 0
+	coupon.CreatedAt = now
+	coupon.UpdatedAt = now
+	return s.repo.Create(ctx, coupon)
+}
+
+func (s *CouponService) UpdateById(ctx context.Context, coupon *models.Coupon) (*models.Coupon, error) {
+	ctx, span := tracing.Start(ctx, "CouponService.UpdateById")
+	defer span.End()
+
+	if coupon != nil && coupon.ID == 0 {
+		return nil, fmt.Errorf("%w: invalid coupon ID", service.ErrInvalidInput)
+	}
+	if err := validateCoupon(coupon); err != nil {
+		return nil, err
+	}
+	coupon.UpdatedAt = time.Now()
+	return s.repo.Update(ctx, coupon)
+}
+
+func (s *CouponService) DeleteById(ctx context.Context, id uint) (*models.Coupon, error) {
+	ctx, span := tracing.Start(ctx, "CouponService.DeleteById")
+	defer span.End()
+
+	if id == 0 {
+		return nil, fmt.Errorf("%w: invalid coupon ID", service.ErrInvalidInput)
+	}
+	return s.repo.Delete(ctx, id)
+}
+
+// validateCoupon chuẩn hoá mã, tiền tệ và kiểm tra các ràng buộc của chương trình khuyến mãi
+func validateCoupon(coupon *models.Coupon) error {
+	if coupon == nil {
+		return fmt.Errorf("%w: coupon is nil", service.ErrInvalidInput)
+	}
+	coupon.Code = promotion.NormalizeCode(coupon.Code)
+	if !codePattern.MatchString(coupon.Code) {
+		return fmt.Errorf("%w: code must be 3-64 characters of A-Z, 0-9, _ or -", service.ErrInvalidInput)
+	}
+	coupon.Description = strings.TrimSpace(coupon.Description)
+
+	switch coupon.Type {
+	case models.CouponTypePercentage:
+		if coupon.Value.GreaterThan(decimal.NewFromInt(100)) {
+			return fmt.Errorf("%w: percentage value must be at most 100", service.ErrInvalidInput)
+		}
+	case models.CouponTypeFixed:
+	default:
+		return fmt.Errorf("%w: type must be %s or %s", service.ErrInvalidInput, models.CouponTypePercentage, models.CouponTypeFixed)
+	}
+	if !coupon.Value.IsPositive() {
+		return fmt.Errorf("%w: value must be greater than zero", service.ErrInvalidInput)
+	}
+
+	if coupon.MinOrderValue != nil && coupon.MinOrderValue.IsNegative() {
+		return fmt.Errorf("%w: min_order_value cannot be negative", service.ErrInvalidInput)
+	}
+	if coupon.Currency == "" && (coupon.Type == models.CouponTypeFixed || coupon.MinOrderValue != nil) {
+		return fmt.Errorf("%w: currency is required for fixed coupons and minimum order values", service.ErrInvalidInput)
+	}
+	if coupon.Currency != "" {
+		unit, err := currency.ParseISO(strings.ToUpper(coupon.Currency))
+		if err != nil {
+			return fmt.Errorf("%w: unknown currency %q", service.ErrInvalidInput, coupon.Currency)
+		}
+		coupon.Currency = unit.String()
+	}
+
+	if coupon.MaxUses != nil && *coupon.MaxUses <= 0 {
+		return fmt.Errorf("%w: max_uses must be greater than zero", service.ErrInvalidInput)
+	}
+	if coupon.MaxUsesPerUser != nil && *coupon.MaxUsesPerUser <= 0 {
+		return fmt.Errorf("%w: max_uses_per_user must be greater than zero", service.ErrInvalidInput)
+	}
+	if coupon.StartsAt != nil && coupon.EndsAt != nil && !coupon.EndsAt.After(*coupon.StartsAt) {
+		return fmt.Errorf("%w: ends_at must be after starts_at", service.ErrInvalidInput)
+	}
+	for _, id := range coupon.BookIDs {
+		if id == 0 {
+			return fmt.Errorf("%w: invalid book ID in book_ids", service.ErrInvalidInput)
+		}
+	}
+	for _, id := range coupon.CategoryIDs {
+		if id == 0 {
+			return fmt.Errorf("%w: invalid category ID in category_ids", service.ErrInvalidInput)
+		}
+	}
+	return nil
+}
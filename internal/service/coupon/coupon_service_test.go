@@ -0,0 +1,107 @@
+package coupon_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/maithuc2003/Test_GIN_golang/internal/interfaces/service"
+	mocks "github.com/maithuc2003/Test_GIN_golang/internal/mocks/repositories"
+	"github.com/maithuc2003/Test_GIN_golang/internal/models"
+	"github.com/maithuc2003/Test_GIN_golang/internal/service/coupon"
+)
+
+func TestCreateCoupon(t *testing.T) {
+	now := time.Now()
+	zero := 0
+	minValue := decimal.NewFromInt(20)
+
+	tests := []struct {
+		name        string
+		input       *models.Coupon
+		expectError bool
+		wantCode    string
+		wantCurr    string
+	}{
+		{name: "nil coupon", input: nil, expectError: true},
+		{
+			name:     "percentage normalized",
+			input:    &models.Coupon{Code: " summer-10 ", Type: models.CouponTypePercentage, Value: decimal.NewFromInt(10)},
+			wantCode: "SUMMER-10",
+		},
+		{
+			name:     "fixed with currency",
+			input:    &models.Coupon{Code: "FIVE", Type: models.CouponTypeFixed, Value: decimal.NewFromInt(5), Currency: "usd"},
+			wantCode: "FIVE", wantCurr: "USD",
+		},
+		{name: "code too short", input: &models.Coupon{Code: "AB", Type: models.CouponTypePercentage, Value: decimal.NewFromInt(10)}, expectError: true},
+		{name: "code with spaces", input: &models.Coupon{Code: "SUMMER SALE", Type: models.CouponTypePercentage, Value: decimal.NewFromInt(10)}, expectError: true},
+		{name: "unknown type", input: &models.Coupon{Code: "BOGO", Type: "bogo", Value: decimal.NewFromInt(1)}, expectError: true},
+		{name: "zero value", input: &models.Coupon{Code: "ZERO", Type: models.CouponTypePercentage}, expectError: true},
+		{name: "percentage over 100", input: &models.Coupon{Code: "FREE", Type: models.CouponTypePercentage, Value: decimal.NewFromInt(101)}, expectError: true},
+		{name: "fixed without currency", input: &models.Coupon{Code: "FIVE", Type: models.CouponTypeFixed, Value: decimal.NewFromInt(5)}, expectError: true},
+		{
+			name:        "min order value without currency",
+			input:       &models.Coupon{Code: "MIN20", Type: models.CouponTypePercentage, Value: decimal.NewFromInt(5), MinOrderValue: &minValue},
+			expectError: true,
+		},
+		{name: "unknown currency", input: &models.Coupon{Code: "FIVE", Type: models.CouponTypeFixed, Value: decimal.NewFromInt(5), Currency: "ABC"}, expectError: true},
+		{name: "zero max uses", input: &models.Coupon{Code: "ONCE", Type: models.CouponTypePercentage, Value: decimal.NewFromInt(5), MaxUses: &zero}, expectError: true},
+		{
+			name:        "ends before starts",
+			input:       &models.Coupon{Code: "WINDOW", Type: models.CouponTypePercentage, Value: decimal.NewFromInt(5), StartsAt: &now, EndsAt: &now},
+			expectError: true,
+		},
+		{name: "zero book ID", input: &models.Coupon{Code: "BOOKS", Type: models.CouponTypePercentage, Value: decimal.NewFromInt(5), BookIDs: []uint{0}}, expectError: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockRepo := new(mocks.MockCouponRepo)
+			s := coupon.NewCouponService(mockRepo)
+			if !tt.expectError {
+				mockRepo.On("Create", mock.Anything, tt.input).Return(nil).Once()
+			}
+
+			err := s.CreateCoupon(context.Background(), tt.input)
+			if tt.expectError {
+				require.Error(t, err)
+				require.True(t, errors.Is(err, service.ErrInvalidInput))
+			} else {
+				require.NoError(t, err)
+				require.Equal(t, tt.wantCode, tt.input.Code)
+				require.Equal(t, tt.wantCurr, tt.input.Currency)
+			}
+			mockRepo.AssertExpectations(t)
+		})
+	}
+}
+
+func TestUpdateAndDeleteCoupon(t *testing.T) {
+	mockRepo := new(mocks.MockCouponRepo)
+	s := coupon.NewCouponService(mockRepo)
+	ctx := context.Background()
+
+	_, err := s.UpdateById(ctx, &models.Coupon{Code: "FIVE", Type: models.CouponTypePercentage, Value: decimal.NewFromInt(5)})
+	require.ErrorIs(t, err, service.ErrInvalidInput)
+
+	input := &models.Coupon{ID: 3, Code: "five", Type: models.CouponTypePercentage, Value: decimal.NewFromInt(5)}
+	mockRepo.On("Update", mock.Anything, input).Return(&models.Coupon{ID: 3, Code: "FIVE"}, nil).Once()
+	updated, err := s.UpdateById(ctx, input)
+	require.NoError(t, err)
+	require.Equal(t, "FIVE", updated.Code)
+	require.WithinDuration(t, time.Now(), input.UpdatedAt, time.Second)
+
+	_, err = s.DeleteById(ctx, 0)
+	require.ErrorIs(t, err, service.ErrInvalidInput)
+	mockRepo.On("Delete", mock.Anything, uint(3)).Return(&models.Coupon{ID: 3}, nil).Once()
+	_, err = s.DeleteById(ctx, 3)
+	require.NoError(t, err)
+
+	mockRepo.AssertExpectations(t)
+}
@@ -0,0 +1,99 @@
+package cover
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"log/slog"
+
+	"github.com/maithuc2003/Test_GIN_golang/internal/interfaces/repositories"
+	"github.com/maithuc2003/Test_GIN_golang/internal/interfaces/service"
+	"github.com/maithuc2003/Test_GIN_golang/internal/models"
+	"github.com/maithuc2003/Test_GIN_golang/internal/storage"
+	"github.com/maithuc2003/Test_GIN_golang/internal/tracing"
+)
+
+type CoverService struct {
+	bookRepo repositories.BookRepository
+	store    storage.Storage
+}
+
+func NewCoverService(bookRepo repositories.BookRepository, store storage.Storage) *CoverService {
+	return &CoverService{bookRepo: bookRepo, store: store}
+}
+
+// storedFile là một file sẽ được ghi vào thư mục ảnh bìa của sách
+type storedFile struct {
+	name        string
+	data        []byte
+	contentType string
+}
+
+// UploadCover lưu ảnh gốc và thumbnail vào thư mục mới covers/<bookID>/<token>, cập
+// nhật sách rồi mới xoá thư mục ảnh cũ, để response cũ (đã cache) không trỏ vào file
+// đang bị ghi đè.
+func (s *CoverService) UploadCover(ctx context.Context, bookID int, data []byte) (*models.Book, error) {
+	ctx, span := tracing.Start(ctx, "CoverService.UploadCover")
+	defer span.End()
+
+	if bookID <= 0 {
+		return nil, fmt.Errorf("%w: invalid book ID", service.ErrInvalidInput)
+	}
+	book, err := s.bookRepo.GetByBookID(ctx, bookID)
+	if err != nil {
+		return nil, err
+	}
+
+	img, contentType, err := decodeCover(data)
+	if err != nil {
+		return nil, err
+	}
+	files := []storedFile{{name: "original" + allowedTypes[contentType], data: data, contentType: contentType}}
+	for _, t := range thumbnails {
+		resized, err := resizeJPEG(img, t.width)
+		if err != nil {
+			return nil, fmt.Errorf("failed to generate %s thumbnail: %w", t.name, err)
+		}
+		files = append(files, storedFile{name: t.name + ".jpg", data: resized, contentType: "image/jpeg"})
+	}
+
+	token := make([]byte, 8)
+	if _, err := rand.Read(token); err != nil {
+		return nil, err
+	}
+	prefix := fmt.Sprintf("covers/%d/%s", bookID, hex.EncodeToString(token))
+
+	urls := make([]string, len(files))
+	for i, f := range files {
+		key := prefix + "/" + f.name
+		if err := s.store.Put(ctx, key, bytes.NewReader(f.data), int64(len(f.data)), f.contentType); err != nil {
+			s.cleanup(ctx, prefix)
+			return nil, fmt.Errorf("failed to store cover: %w", err)
+		}
+		urls[i] = s.store.URL(key)
+	}
+
+	cover := &models.BookCover{URL: urls[0], MediumURL: urls[1], ThumbnailURL: urls[2]}
+	if err := s.bookRepo.UpdateCover(ctx, bookID, prefix, cover); err != nil {
+		s.cleanup(ctx, prefix)
+		return nil, fmt.Errorf("failed to update cover: %w", err)
+	}
+	if book.CoverKey != "" {
+		s.cleanup(ctx, book.CoverKey)
+	}
+
+	book.CoverKey = prefix
+	book.Cover = cover
+	return book, nil
+}
+
+// cleanup xoá thư mục ảnh không còn dùng; lỗi chỉ ghi log vì ảnh mồ côi không ảnh
+// hưởng dữ liệu, và ctx của request có thể đã bị huỷ nên chỉ giữ lại giá trị (request ID)
+func (s *CoverService) cleanup(ctx context.Context, prefix string) {
+	ctx = context.WithoutCancel(ctx)
+	if err := s.store.DeletePrefix(ctx, prefix); err != nil {
+		slog.ErrorContext(ctx, "failed to delete cover files", "prefix", prefix, "error", err)
+	}
+}
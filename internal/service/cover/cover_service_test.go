@@ -0,0 +1,140 @@
+package cover_test
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"image"
+	"image/color"
+	"image/jpeg"
+	"image/png"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/maithuc2003/Test_GIN_golang/internal/interfaces/repositories"
+	"github.com/maithuc2003/Test_GIN_golang/internal/interfaces/service"
+	mocks "github.com/maithuc2003/Test_GIN_golang/internal/mocks/repositories"
+	"github.com/maithuc2003/Test_GIN_golang/internal/models"
+	"github.com/maithuc2003/Test_GIN_golang/internal/service/cover"
+	"github.com/maithuc2003/Test_GIN_golang/internal/storage"
+)
+
+func pngImage(t *testing.T, w, h int) []byte {
+	img := image.NewNRGBA(image.Rect(0, 0, w, h))
+	for x := 0; x < w; x++ {
+		img.Set(x, h/2, color.NRGBA{R: 200, A: 255})
+	}
+	var buf bytes.Buffer
+	require.NoError(t, png.Encode(&buf, img))
+	return buf.Bytes()
+}
+
+func TestUploadCover(t *testing.T) {
+	tests := []struct {
+		name        string
+		bookID      int
+		data        []byte
+		findErr     error
+		callsUpdate bool
+		wantErr     error
+	}{
+		{name: "invalid book ID", bookID: 0, data: pngImage(t, 10, 10), wantErr: service.ErrInvalidInput},
+		{name: "book not found", bookID: 9, data: pngImage(t, 10, 10), findErr: errors.New("book with ID 9 not found"), wantErr: errors.New("book with ID 9 not found")},
+		{name: "not an image", bookID: 1, data: []byte("%PDF-1.4 not an image"), wantErr: service.ErrInvalidInput},
+		{name: "empty file", bookID: 1, data: nil, wantErr: service.ErrInvalidInput},
+		{name: "too large", bookID: 1, data: append(pngImage(t, 10, 10), make([]byte, service.MaxCoverSize)...), wantErr: service.ErrInvalidInput},
+		{name: "dimensions too large", bookID: 1, data: pngImage(t, 7000, 1), wantErr: service.ErrInvalidInput},
+		{name: "png cover", bookID: 1, data: pngImage(t, 1200, 1800), callsUpdate: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			root := t.TempDir()
+			store, err := storage.NewLocalStorage(root, "/uploads")
+			require.NoError(t, err)
+			mockRepo := new(mocks.MockBookRepo)
+			svc := cover.NewCoverService(mockRepo, store)
+
+			if tt.bookID > 0 {
+				var found *models.Book
+				if tt.findErr == nil {
+					found = &models.Book{ID: uint(tt.bookID), Title: "Dune"}
+				}
+				mockRepo.On("GetByBookID", mock.Anything, tt.bookID).Return(found, tt.findErr)
+			}
+			if tt.callsUpdate {
+				mockRepo.On("UpdateCover", mock.Anything, tt.bookID, mock.AnythingOfType("string"), mock.AnythingOfType("*models.BookCover")).Return(nil)
+			}
+
+			book, err := svc.UploadCover(context.Background(), tt.bookID, tt.data)
+			mockRepo.AssertExpectations(t)
+			if tt.wantErr != nil {
+				if errors.Is(tt.wantErr, service.ErrInvalidInput) {
+					require.ErrorIs(t, err, service.ErrInvalidInput)
+				} else {
+					require.EqualError(t, err, tt.wantErr.Error())
+				}
+				return
+			}
+			require.NoError(t, err)
+			require.NotNil(t, book.Cover)
+			require.True(t, strings.HasPrefix(book.Cover.URL, "/uploads/covers/1/"))
+			require.True(t, strings.HasSuffix(book.Cover.URL, "/original.png"))
+
+			// Thumbnail giữ tỉ lệ và không lớn hơn chiều rộng cấu hình
+			thumbPath := filepath.Join(root, strings.TrimPrefix(book.Cover.ThumbnailURL, "/uploads/"))
+			thumb, err := os.ReadFile(thumbPath)
+			require.NoError(t, err)
+			cfg, err := jpeg.DecodeConfig(bytes.NewReader(thumb))
+			require.NoError(t, err)
+			require.Equal(t, 200, cfg.Width)
+			require.Equal(t, 300, cfg.Height)
+		})
+	}
+}
+
+func TestUploadCover_ReplacesOldFiles(t *testing.T) {
+	root := t.TempDir()
+	store, err := storage.NewLocalStorage(root, "/uploads")
+	require.NoError(t, err)
+	ctx := context.Background()
+
+	oldKey := "covers/1/old"
+	require.NoError(t, store.Put(ctx, oldKey+"/original.png", bytes.NewReader([]byte("x")), 1, "image/png"))
+
+	mockRepo := new(mocks.MockBookRepo)
+	mockRepo.On("GetByBookID", mock.Anything, 1).Return(&models.Book{ID: 1, CoverKey: oldKey}, nil)
+	mockRepo.On("UpdateCover", mock.Anything, 1, mock.AnythingOfType("string"), mock.AnythingOfType("*models.BookCover")).Return(nil)
+
+	book, err := cover.NewCoverService(mockRepo, store).UploadCover(ctx, 1, pngImage(t, 100, 150))
+	require.NoError(t, err)
+	require.NotEqual(t, oldKey, book.CoverKey)
+
+	_, err = os.Stat(filepath.Join(root, "covers", "1", "old"))
+	require.True(t, os.IsNotExist(err), "old cover must be removed")
+	_, err = os.Stat(filepath.Join(root, filepath.FromSlash(book.CoverKey), "medium.jpg"))
+	require.NoError(t, err)
+}
+
+func TestUploadCover_UpdateFailsCleansUp(t *testing.T) {
+	root := t.TempDir()
+	store, err := storage.NewLocalStorage(root, "/uploads")
+	require.NoError(t, err)
+
+	mockRepo := new(mocks.MockBookRepo)
+	mockRepo.On("GetByBookID", mock.Anything, 1).Return(&models.Book{ID: 1}, nil)
+	mockRepo.On("UpdateCover", mock.Anything, 1, mock.AnythingOfType("string"), mock.AnythingOfType("*models.BookCover")).
+		Return(repositories.ErrNotFound)
+
+	_, err = cover.NewCoverService(mockRepo, store).UploadCover(context.Background(), 1, pngImage(t, 50, 50))
+	require.ErrorIs(t, err, repositories.ErrNotFound)
+
+	entries, err := os.ReadDir(filepath.Join(root, "covers", "1"))
+	require.NoError(t, err)
+	require.Empty(t, entries, "uploaded files must be removed when the book update fails")
+}
@@ -0,0 +1,91 @@
+package cover
+
+import (
+	"bytes"
+	"fmt"
+	"image"
+	"image/color"
+	"image/jpeg"
+	"net/http"
+
+	// Đăng ký decoder cho image.Decode
+	_ "image/png"
+
+	"golang.org/x/image/draw"
+	_ "golang.org/x/image/webp"
+
+	"github.com/maithuc2003/Test_GIN_golang/internal/interfaces/service"
+)
+
+// maxDimension chặn ảnh quá lớn (decompression bomb) trước khi decode toàn bộ
+const maxDimension = 6000
+
+// allowedTypes ánh xạ MIME type được chấp nhận sang phần mở rộng khi lưu file gốc
+var allowedTypes = map[string]string{
+	"image/jpeg": ".jpg",
+	"image/png":  ".png",
+	"image/webp": ".webp",
+}
+
+// thumbnail là một kích thước ảnh được sinh thêm, giữ tỉ lệ theo chiều rộng
+type thumbnail struct {
+	name  string
+	width int
+}
+
+var thumbnails = []thumbnail{
+	{name: "medium", width: 600},
+	{name: "thumb", width: 200},
+}
+
+// decodeCover kiểm tra dung lượng, định dạng (theo nội dung, không tin Content-Type
+// của client) và kích thước ảnh rồi decode
+func decodeCover(data []byte) (image.Image, string, error) {
+	if len(data) == 0 {
+		return nil, "", fmt.Errorf("%w: cover image is empty", service.ErrInvalidInput)
+	}
+	if len(data) > service.MaxCoverSize {
+		return nil, "", fmt.Errorf("%w: cover image exceeds %d bytes", service.ErrInvalidInput, service.MaxCoverSize)
+	}
+	contentType := http.DetectContentType(data)
+	if _, ok := allowedTypes[contentType]; !ok {
+		return nil, "", fmt.Errorf("%w: unsupported cover image type %s", service.ErrInvalidInput, contentType)
+	}
+
+	cfg, _, err := image.DecodeConfig(bytes.NewReader(data))
+	if err != nil {
+		return nil, "", fmt.Errorf("%w: cannot read cover image: %v", service.ErrInvalidInput, err)
+	}
+	if cfg.Width > maxDimension || cfg.Height > maxDimension {
+		return nil, "", fmt.Errorf("%w: cover image must be at most %dx%d pixels", service.ErrInvalidInput, maxDimension, maxDimension)
+	}
+	img, _, err := image.Decode(bytes.NewReader(data))
+	if err != nil {
+		return nil, "", fmt.Errorf("%w: cannot decode cover image: %v", service.ErrInvalidInput, err)
+	}
+	return img, contentType, nil
+}
+
+// resizeJPEG thu nhỏ ảnh về chiều rộng width (không phóng to ảnh nhỏ hơn) và encode
+// JPEG. Nền trắng thay cho vùng trong suốt của PNG/WebP vì JPEG không có alpha.
+func resizeJPEG(src image.Image, width int) ([]byte, error) {
+	bounds := src.Bounds()
+	w, h := bounds.Dx(), bounds.Dy()
+	if w > width {
+		h = h * width / w
+		w = width
+	}
+	if h < 1 {
+		h = 1
+	}
+
+	dst := image.NewRGBA(image.Rect(0, 0, w, h))
+	draw.Draw(dst, dst.Bounds(), image.NewUniform(color.White), image.Point{}, draw.Src)
+	draw.CatmullRom.Scale(dst, dst.Bounds(), src, bounds, draw.Over, nil)
+
+	var buf bytes.Buffer
+	if err := jpeg.Encode(&buf, dst, &jpeg.Options{Quality: 85}); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
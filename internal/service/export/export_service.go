@@ -0,0 +1,114 @@
+package export
+
+import (
+	"context"
+	"io"
+
+	"github.com/maithuc2003/Test_GIN_golang/internal/interfaces/repositories"
+	"github.com/maithuc2003/Test_GIN_golang/internal/models"
+	"github.com/maithuc2003/Test_GIN_golang/internal/tracing"
+)
+
+const batchSize = 500
+
+var (
+	bookColumns   = []string{"id", "title", "stock", "author_id", "isbn", "publisher_id", "price", "currency", "language", "page_count", "published_at", "created_at", "updated_at"}
+	authorColumns = []string{"id", "name", "nationality", "created_at", "updated_at"}
+	orderColumns  = []string{"id", "book_id", "book_title", "user_id", "username", "quantity", "status", "currency", "subtotal", "discount_total", "tax_total", "grand_total", "ordered_at", "updated_at"}
+)
+
+type ExportService struct {
+	repo repositories.ExportRepository
+}
+
+func NewExportService(repo repositories.ExportRepository) *ExportService {
+	return &ExportService{repo: repo}
+}
+
+func (s *ExportService) ExportBooks(ctx context.Context, w io.Writer, format string, filter models.BookFilter) error {
+	ctx, span := tracing.Start(ctx, "ExportService.ExportBooks")
+	defer span.End()
+
+	return export(w, format, bookColumns, func(rw recordWriter) error {
+		return s.repo.StreamBooks(ctx, filter, batchSize, func(books []models.Book) error {
+			for i := range books {
+				b := &books[i]
+				if err := rw.Write(b, bookValues(b)); err != nil {
+					return err
+				}
+			}
+			return nil
+		})
+	})
+}
+
+func (s *ExportService) ExportAuthors(ctx context.Context, w io.Writer, format string, filter models.AuthorFilter) error {
+	ctx, span := tracing.Start(ctx, "ExportService.ExportAuthors")
+	defer span.End()
+
+	return export(w, format, authorColumns, func(rw recordWriter) error {
+		return s.repo.StreamAuthors(ctx, filter, batchSize, func(authors []models.Author) error {
+			for i := range authors {
+				a := &authors[i]
+				if err := rw.Write(a, []interface{}{a.ID, a.Name, a.Nationality, a.CreatedAt, a.UpdatedAt}); err != nil {
+					return err
+				}
+			}
+			return nil
+		})
+	})
+}
+
+func (s *ExportService) ExportOrders(ctx context.Context, w io.Writer, format string, filter models.OrderFilter) error {
+	ctx, span := tracing.Start(ctx, "ExportService.ExportOrders")
+	defer span.End()
+
+	return export(w, format, orderColumns, func(rw recordWriter) error {
+		return s.repo.StreamOrders(ctx, filter, batchSize, func(orders []models.OrderExportRow) error {
+			for i := range orders {
+				o := &orders[i]
+				values := []interface{}{o.ID, o.BookID, o.BookTitle, o.UserID, o.Username, o.Quantity, o.Status, o.Currency,
+					o.Subtotal.StringFixed(2), o.DiscountTotal.StringFixed(2), o.TaxTotal.StringFixed(2), o.GrandTotal.StringFixed(2),
+					o.OrderedAt, o.UpdatedAt}
+				if err := rw.Write(o, values); err != nil {
+					return err
+				}
+			}
+			return nil
+		})
+	})
+}
+
+// bookValues trả về giá trị từng cột của sách; trường nullable rỗng thành ô trống
+func bookValues(b *models.Book) []interface{} {
+	var isbn, publisherID, price, publishedAt interface{}
+	if b.ISBN != nil {
+		isbn = *b.ISBN
+	}
+	if b.PublisherID != nil {
+		publisherID = *b.PublisherID
+	}
+	if b.Price != nil {
+		price = b.Price.StringFixed(2)
+	}
+	if b.PublishedAt != nil {
+		publishedAt = b.PublishedAt.Format("2006-01-02")
+	}
+	return []interface{}{b.ID, b.Title, b.Stock, b.AuthorID, isbn, publisherID, price, b.Currency, b.Language, b.PageCount, publishedAt, b.CreatedAt, b.UpdatedAt}
+}
+
+func export(w io.Writer, format string, columns []string, stream func(recordWriter) error) error {
+	rw, err := newRecordWriter(w, format)
+	if err != nil {
+		return err
+	}
+	if err := rw.WriteHeader(columns); err != nil {
+		rw.Abort()
+		return err
+	}
+	if err := stream(rw); err != nil {
+		rw.Abort()
+		return err
+	}
+	return rw.Close()
+}
@@ -0,0 +1,135 @@
+package export_test
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/require"
+	"github.com/xuri/excelize/v2"
+	"gorm.io/gorm"
+
+	"github.com/maithuc2003/Test_GIN_golang/internal/models"
+	exportRepo "github.com/maithuc2003/Test_GIN_golang/internal/repositories/export"
+	"github.com/maithuc2003/Test_GIN_golang/internal/service/export"
+	sqlitedriver "gorm.io/driver/sqlite"
+
+	_ "modernc.org/sqlite"
+)
+
+func setupTestDB(t *testing.T) *gorm.DB {
+	dsn := fmt.Sprintf("file:testdb_%d?mode=memory&cache=shared", time.Now().UnixNano())
+
+	db, err := gorm.Open(sqlitedriver.New(sqlitedriver.Config{
+		DSN:        dsn,
+		DriverName: "sqlite",
+	}), &gorm.Config{})
+
+	require.NoError(t, err)
+	require.NoError(t, db.AutoMigrate(&models.Author{}, &models.Book{}, &models.Order{}, &models.OrderDiscount{}, &models.User{}, &models.StockReservation{}, &models.OutboxEvent{}))
+
+	return db
+}
+
+func TestExportBooks_CSV(t *testing.T) {
+	db := setupTestDB(t)
+	// Nhiều hơn một batch để kiểm tra phân trang keyset
+	for i := 1; i <= 1200; i++ {
+		require.NoError(t, db.Create(&models.Book{Title: fmt.Sprintf("Book %d", i), Stock: i % 3, AuthorID: 1 + i%2}).Error)
+	}
+	svc := export.NewExportService(exportRepo.NewExportRepo(db))
+
+	tests := []struct {
+		name     string
+		filter   models.BookFilter
+		wantRows int
+	}{
+		{name: "all books", wantRows: 1200},
+		{name: "filtered by author", filter: models.BookFilter{AuthorID: 2}, wantRows: 600},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			require.NoError(t, svc.ExportBooks(context.Background(), &buf, models.FormatCSV, tt.filter))
+
+			records, err := csv.NewReader(&buf).ReadAll()
+			require.NoError(t, err)
+			require.Equal(t, []string{"id", "title", "stock", "author_id", "isbn", "publisher_id", "price", "currency", "language", "page_count", "published_at", "created_at", "updated_at"}, records[0])
+			require.Len(t, records, tt.wantRows+1)
+		})
+	}
+}
+
+func TestExportOrders_NDJSONJoinsTitleAndUsername(t *testing.T) {
+	db := setupTestDB(t)
+	user := models.User{Username: "maithuc", Password: "x"}
+	require.NoError(t, db.Create(&user).Error)
+	book := models.Book{Title: "Mat Biec", Stock: 5, AuthorID: 1}
+	require.NoError(t, db.Create(&book).Error)
+	require.NoError(t, db.Create(&models.Order{
+		BookID: book.ID, UserID: user.ID, Quantity: 2, Status: "pending", Currency: "USD",
+		Subtotal: decimal.RequireFromString("30"), DiscountTotal: decimal.RequireFromString("5"),
+		TaxTotal: decimal.RequireFromString("2.5"), GrandTotal: decimal.RequireFromString("27.5"),
+	}).Error)
+	require.NoError(t, db.Create(&models.Order{BookID: book.ID, UserID: user.ID, Quantity: 1, Status: "shipped"}).Error)
+
+	svc := export.NewExportService(exportRepo.NewExportRepo(db))
+	var buf bytes.Buffer
+	require.NoError(t, svc.ExportOrders(context.Background(), &buf, models.FormatCSV, models.OrderFilter{Status: "pending"}))
+	records, err := csv.NewReader(&buf).ReadAll()
+	require.NoError(t, err)
+	require.Len(t, records, 2)
+	require.Equal(t, []string{"currency", "subtotal", "discount_total", "tax_total", "grand_total"}, records[0][7:12])
+	require.Equal(t, []string{"USD", "30.00", "5.00", "2.50", "27.50"}, records[1][7:12])
+
+	buf.Reset()
+	require.NoError(t, svc.ExportOrders(context.Background(), &buf, models.FormatNDJSON, models.OrderFilter{Status: "pending"}))
+
+	var rows []models.OrderExportRow
+	scanner := bufio.NewScanner(&buf)
+	for scanner.Scan() {
+		var row models.OrderExportRow
+		require.NoError(t, json.Unmarshal(scanner.Bytes(), &row))
+		rows = append(rows, row)
+	}
+	require.Len(t, rows, 1)
+	require.Equal(t, "Mat Biec", rows[0].BookTitle)
+	require.Equal(t, "maithuc", rows[0].Username)
+	require.Equal(t, 2, rows[0].Quantity)
+	require.True(t, decimal.RequireFromString("27.5").Equal(rows[0].GrandTotal))
+}
+
+func TestExportAuthors_XLSX(t *testing.T) {
+	db := setupTestDB(t)
+	require.NoError(t, db.Create(&models.Author{Name: "Nguyen Nhat Anh", Nationality: "VN"}).Error)
+	require.NoError(t, db.Create(&models.Author{Name: "Frank Herbert", Nationality: "US"}).Error)
+
+	svc := export.NewExportService(exportRepo.NewExportRepo(db))
+	var buf bytes.Buffer
+	require.NoError(t, svc.ExportAuthors(context.Background(), &buf, models.FormatXLSX, models.AuthorFilter{Nationality: "vn"}))
+
+	f, err := excelize.OpenReader(&buf)
+	require.NoError(t, err)
+	defer f.Close()
+	rows, err := f.GetRows("Sheet1")
+	require.NoError(t, err)
+	require.Len(t, rows, 2)
+	require.Equal(t, "name", rows[0][1])
+	require.Equal(t, "Nguyen Nhat Anh", rows[1][1])
+}
+
+func TestExport_UnsupportedFormat(t *testing.T) {
+	db := setupTestDB(t)
+	svc := export.NewExportService(exportRepo.NewExportRepo(db))
+
+	var buf bytes.Buffer
+	require.Error(t, svc.ExportBooks(context.Background(), &buf, "pdf", models.BookFilter{}))
+	require.Zero(t, buf.Len())
+}
@@ -0,0 +1,146 @@
+package export
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+	"time"
+
+	"github.com/maithuc2003/Test_GIN_golang/internal/models"
+	"github.com/xuri/excelize/v2"
+)
+
+// recordWriter ghi từng dòng ra định dạng đích; item dùng cho NDJSON, values cho CSV/XLSX
+type recordWriter interface {
+	WriteHeader(columns []string) error
+	Write(item interface{}, values []interface{}) error
+	Close() error
+	// Abort giải phóng tài nguyên khi export bị lỗi giữa chừng
+	Abort()
+}
+
+func newRecordWriter(w io.Writer, format string) (recordWriter, error) {
+	switch format {
+	case models.FormatCSV:
+		return &csvWriter{w: csv.NewWriter(w)}, nil
+	case models.FormatNDJSON:
+		return &ndjsonWriter{enc: json.NewEncoder(w)}, nil
+	case models.FormatXLSX:
+		return newXLSXWriter(w)
+	default:
+		return nil, fmt.Errorf("unsupported export format %q", format)
+	}
+}
+
+type csvWriter struct {
+	w *csv.Writer
+}
+
+func (c *csvWriter) WriteHeader(columns []string) error {
+	return c.w.Write(columns)
+}
+
+func (c *csvWriter) Write(_ interface{}, values []interface{}) error {
+	record := make([]string, len(values))
+	for i, v := range values {
+		record[i] = formatValue(v)
+	}
+	if err := c.w.Write(record); err != nil {
+		return err
+	}
+	// Flush theo từng dòng để dữ liệu được đẩy ra client ngay
+	c.w.Flush()
+	return c.w.Error()
+}
+
+func (c *csvWriter) Close() error {
+	c.w.Flush()
+	return c.w.Error()
+}
+
+func (c *csvWriter) Abort() {}
+
+func formatValue(v interface{}) string {
+	switch val := v.(type) {
+	case string:
+		return val
+	case int:
+		return strconv.Itoa(val)
+	case uint:
+		return strconv.FormatUint(uint64(val), 10)
+	case time.Time:
+		return val.Format(time.RFC3339)
+	case nil:
+		return ""
+	default:
+		return fmt.Sprint(val)
+	}
+}
+
+type ndjsonWriter struct {
+	enc *json.Encoder
+}
+
+func (n *ndjsonWriter) WriteHeader([]string) error { return nil }
+
+func (n *ndjsonWriter) Write(item interface{}, _ []interface{}) error {
+	return n.enc.Encode(item)
+}
+
+func (n *ndjsonWriter) Close() error { return nil }
+
+func (n *ndjsonWriter) Abort() {}
+
+// xlsxWriter dùng StreamWriter của excelize: các dòng được ghi ra file tạm,
+// file xlsx hoàn chỉnh chỉ được ghi vào w khi Close
+type xlsxWriter struct {
+	out    io.Writer
+	file   *excelize.File
+	stream *excelize.StreamWriter
+	row    int
+}
+
+func newXLSXWriter(w io.Writer) (*xlsxWriter, error) {
+	f := excelize.NewFile()
+	sw, err := f.NewStreamWriter("Sheet1")
+	if err != nil {
+		f.Close()
+		return nil, err
+	}
+	return &xlsxWriter{out: w, file: f, stream: sw}, nil
+}
+
+func (x *xlsxWriter) WriteHeader(columns []string) error {
+	values := make([]interface{}, len(columns))
+	for i, c := range columns {
+		values[i] = c
+	}
+	return x.writeRow(values)
+}
+
+func (x *xlsxWriter) Write(_ interface{}, values []interface{}) error {
+	return x.writeRow(values)
+}
+
+func (x *xlsxWriter) writeRow(values []interface{}) error {
+	x.row++
+	cell, err := excelize.CoordinatesToCellName(1, x.row)
+	if err != nil {
+		return err
+	}
+	return x.stream.SetRow(cell, values)
+}
+
+func (x *xlsxWriter) Close() error {
+	defer x.file.Close()
+	if err := x.stream.Flush(); err != nil {
+		return err
+	}
+	return x.file.Write(x.out)
+}
+
+func (x *xlsxWriter) Abort() {
+	x.file.Close()
+}
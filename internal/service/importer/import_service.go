@@ -0,0 +1,219 @@
+package importer
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/maithuc2003/Test_GIN_golang/internal/interfaces/repositories"
+	"github.com/maithuc2003/Test_GIN_golang/internal/models"
+	"github.com/maithuc2003/Test_GIN_golang/internal/tracing"
+	"github.com/maithuc2003/Test_GIN_golang/pkg/textnorm"
+)
+
+const DefaultBatchSize = 500
+
+// errDryRun dùng để rollback batch khi chạy dry-run
+var errDryRun = errors.New("dry run")
+
+type ImportService struct {
+	tx repositories.TransactionManager
+}
+
+func NewImportService(tx repositories.TransactionManager) *ImportService {
+	return &ImportService{tx: tx}
+}
+
+// ImportBooks đọc sách từ CSV/NDJSON, tạo tác giả theo tên nếu chưa có và upsert
+// sách theo từng batch trong transaction. Mỗi dòng chạy trong một savepoint để
+// dòng lỗi không làm hỏng cả batch.
+func (s *ImportService) ImportBooks(ctx context.Context, r io.Reader, opts models.ImportOptions) (*models.ImportReport, error) {
+	ctx, span := tracing.Start(ctx, "ImportService.ImportBooks")
+	defer span.End()
+
+	reader, err := newRowReader(r, opts.Format)
+	if err != nil {
+		return nil, err
+	}
+	batchSize := opts.BatchSize
+	if batchSize <= 0 {
+		batchSize = DefaultBatchSize
+	}
+
+	report := &models.ImportReport{DryRun: opts.DryRun, Rows: []models.ImportRowResult{}}
+	// Cache tên tác giả (lowercase) -> ID của các batch đã commit
+	authorIDs := map[string]int{}
+
+	batch := make([]bookRow, 0, batchSize)
+	for {
+		row, err := reader.Next()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to read import data: %w", err)
+		}
+		batch = append(batch, row)
+		if len(batch) == batchSize {
+			if err := s.importBatch(ctx, batch, opts.DryRun, authorIDs, report); err != nil {
+				return nil, err
+			}
+			batch = batch[:0]
+		}
+	}
+	if len(batch) > 0 {
+		if err := s.importBatch(ctx, batch, opts.DryRun, authorIDs, report); err != nil {
+			return nil, err
+		}
+	}
+	return report, nil
+}
+
+func (s *ImportService) importBatch(ctx context.Context, batch []bookRow, dryRun bool, authorIDs map[string]int, report *models.ImportReport) error {
+	results := make([]models.ImportRowResult, 0, len(batch))
+	pending := map[string]int{}
+
+	err := s.tx.WithinTransaction(ctx, func(ctx context.Context, repos repositories.Repositories) error {
+		for _, row := range batch {
+			if err := ctx.Err(); err != nil {
+				return err
+			}
+			result := models.ImportRowResult{Row: row.Line, Title: row.Title, Author: row.Author}
+			if err := validateRow(row); err != nil {
+				result.Status = models.ImportRowFailed
+				result.Error = err.Error()
+				results = append(results, result)
+				continue
+			}
+
+			var created string
+			err := s.tx.WithinTransaction(ctx, func(ctx context.Context, repos repositories.Repositories) error {
+				var err error
+				created, err = upsertRow(ctx, repos, row, authorIDs, pending, &result)
+				return err
+			})
+			if err != nil {
+				result.Status = models.ImportRowFailed
+				result.BookID = 0
+				result.Error = err.Error()
+			} else if created != "" {
+				pending[created] = result.AuthorID
+			}
+			results = append(results, result)
+		}
+		if dryRun {
+			return errDryRun
+		}
+		return nil
+	})
+
+	if err != nil && !errors.Is(err, errDryRun) {
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		// Batch không commit được -> mọi dòng trong batch đều thất bại
+		for i := range results {
+			if results[i].Status != models.ImportRowFailed {
+				results[i].Status = models.ImportRowFailed
+				results[i].Error = fmt.Sprintf("batch rolled back: %v", err)
+			}
+		}
+	} else if !dryRun {
+		for name, id := range pending {
+			authorIDs[name] = id
+		}
+	}
+
+	for _, result := range results {
+		switch result.Status {
+		case models.ImportRowCreated:
+			report.Created++
+		case models.ImportRowUpdated:
+			report.Updated++
+		default:
+			report.Failed++
+		}
+	}
+	report.Total += len(results)
+	report.Rows = append(report.Rows, results...)
+	return nil
+}
+
+func validateRow(row bookRow) error {
+	if row.Err != nil {
+		return row.Err
+	}
+	if row.Title == "" {
+		return errors.New("title is required")
+	}
+	if row.Author == "" {
+		return errors.New("author is required")
+	}
+	if row.Stock < 0 {
+		return errors.New("stock cannot be negative")
+	}
+	if row.ID < 0 {
+		return errors.New("invalid id")
+	}
+	return nil
+}
+
+// upsertRow trả về khoá cache của tác giả nếu tác giả vừa được tạo mới
+func upsertRow(ctx context.Context, repos repositories.Repositories, row bookRow, authorIDs, pending map[string]int, result *models.ImportRowResult) (string, error) {
+	key := textnorm.Name(row.Author)
+	authorID, ok := pending[key]
+	if !ok {
+		authorID, ok = authorIDs[key]
+	}
+	createdAuthor := ""
+	if !ok {
+		author, err := repos.Authors.GetByName(ctx, row.Author)
+		if err != nil {
+			return "", err
+		}
+		if author == nil {
+			author = &models.Author{Name: row.Author, Nationality: row.Nationality}
+			if err := repos.Authors.CreateAuthor(ctx, author); err != nil {
+				return "", err
+			}
+			createdAuthor = key
+		}
+		authorID = author.ID
+	}
+	result.AuthorID = authorID
+
+	var existing *models.Book
+	var err error
+	if row.ID > 0 {
+		existing, err = repos.Books.GetByBookID(ctx, row.ID)
+	} else {
+		existing, err = repos.Books.GetByTitleAndAuthor(ctx, row.Title, authorID)
+	}
+	if err != nil {
+		return "", err
+	}
+
+	now := time.Now()
+	if existing == nil {
+		book := &models.Book{Title: row.Title, Stock: row.Stock, AuthorID: authorID, CreatedAt: now, UpdatedAt: now}
+		if err := repos.Books.CreateBook(ctx, book); err != nil {
+			return "", fmt.Errorf("failed to create book: %w", err)
+		}
+		result.Status = models.ImportRowCreated
+		result.BookID = book.ID
+		return createdAuthor, nil
+	}
+
+	existing.Title = row.Title
+	existing.Stock = row.Stock
+	existing.AuthorID = authorID
+	existing.UpdatedAt = now
+	if _, err := repos.Books.UpdateById(ctx, existing); err != nil {
+		return "", fmt.Errorf("failed to update book: %w", err)
+	}
+	result.Status = models.ImportRowUpdated
+	result.BookID = existing.ID
+	return createdAuthor, nil
+}
@@ -0,0 +1,163 @@
+package importer_test
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+
+	"github.com/maithuc2003/Test_GIN_golang/internal/events"
+	"github.com/maithuc2003/Test_GIN_golang/internal/models"
+	"github.com/maithuc2003/Test_GIN_golang/internal/repositories/transaction"
+	"github.com/maithuc2003/Test_GIN_golang/internal/service/importer"
+	sqlitedriver "gorm.io/driver/sqlite"
+
+	_ "modernc.org/sqlite"
+)
+
+func setupTestDB(t *testing.T) *gorm.DB {
+	dsn := fmt.Sprintf("file:testdb_%d?mode=memory&cache=shared", time.Now().UnixNano())
+
+	db, err := gorm.Open(sqlitedriver.New(sqlitedriver.Config{
+		DSN:        dsn,
+		DriverName: "sqlite",
+	}), &gorm.Config{})
+
+	require.NoError(t, err)
+	require.NoError(t, db.AutoMigrate(&models.Author{}, &models.AuthorAlias{}, &models.Book{}, &models.StockReservation{}, &models.OutboxEvent{}))
+
+	return db
+}
+
+func TestImportBooks(t *testing.T) {
+	tests := []struct {
+		name        string
+		format      string
+		input       string
+		dryRun      bool
+		batchSize   int
+		wantCreated int
+		wantUpdated int
+		wantFailed  int
+		wantBooks   int64
+		wantAuthors int64
+		wantErrors  map[int]string
+	}{
+		{
+			name:   "csv creates authors once and upserts by title",
+			format: models.FormatCSV,
+			input: "title,author,nationality,stock\n" +
+				"Mat Biec,Nguyen Nhat Anh,VN,5\n" +
+				"Cho toi xin mot ve di tuoi tho,nguyen nhat anh,VN,3\n" +
+				"Mat Biec,Nguyen Nhat Anh,VN,9\n" +
+				",Nguyen Nhat Anh,VN,1\n" +
+				"De Men,To Hoai,VN,abc\n",
+			batchSize:   2,
+			wantCreated: 2,
+			wantUpdated: 1,
+			wantFailed:  2,
+			wantBooks:   2,
+			wantAuthors: 1,
+			wantErrors:  map[int]string{5: "title is required", 6: `invalid stock "abc"`},
+		},
+		{
+			name:   "ndjson with unknown id fails only that row",
+			format: models.FormatNDJSON,
+			input: `{"title":"Dune","author":"Frank Herbert","stock":2}` + "\n\n" +
+				`{"id":999,"title":"Ghost","author":"Frank Herbert","stock":1}` + "\n" +
+				`{"title":"broken"` + "\n",
+			wantCreated: 1,
+			wantFailed:  2,
+			wantBooks:   1,
+			wantAuthors: 1,
+			wantErrors:  map[int]string{3: "book with ID 999 not found"},
+		},
+		{
+			name:        "dry run validates without writing",
+			format:      models.FormatCSV,
+			input:       "title,author,stock\nMat Biec,Nguyen Nhat Anh,5\nDune,,1\n",
+			dryRun:      true,
+			wantCreated: 1,
+			wantFailed:  1,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			db := setupTestDB(t)
+			svc := importer.NewImportService(transaction.NewTransactionManager(db))
+
+			report, err := svc.ImportBooks(context.Background(), strings.NewReader(tt.input), models.ImportOptions{
+				Format:    tt.format,
+				DryRun:    tt.dryRun,
+				BatchSize: tt.batchSize,
+			})
+			require.NoError(t, err)
+
+			require.Equal(t, tt.dryRun, report.DryRun)
+			require.Equal(t, tt.wantCreated, report.Created)
+			require.Equal(t, tt.wantUpdated, report.Updated)
+			require.Equal(t, tt.wantFailed, report.Failed)
+			require.Equal(t, report.Created+report.Updated+report.Failed, report.Total)
+			for _, row := range report.Rows {
+				if want, ok := tt.wantErrors[row.Row]; ok {
+					require.Equal(t, models.ImportRowFailed, row.Status)
+					require.Contains(t, row.Error, want)
+				}
+			}
+
+			var books, authors int64
+			require.NoError(t, db.Model(&models.Book{}).Count(&books).Error)
+			require.NoError(t, db.Model(&models.Author{}).Count(&authors).Error)
+			require.Equal(t, tt.wantBooks, books)
+			require.Equal(t, tt.wantAuthors, authors)
+		})
+	}
+}
+
+func TestImportBooks_InvalidInput(t *testing.T) {
+	db := setupTestDB(t)
+	svc := importer.NewImportService(transaction.NewTransactionManager(db))
+
+	tests := []struct {
+		name   string
+		format string
+		input  string
+	}{
+		{name: "unsupported format", format: "xml", input: "<books/>"},
+		{name: "missing header", format: models.FormatCSV, input: ""},
+		{name: "missing author column", format: models.FormatCSV, input: "title,stock\nDune,1\n"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := svc.ImportBooks(context.Background(), strings.NewReader(tt.input), models.ImportOptions{Format: tt.format})
+			require.Error(t, err)
+		})
+	}
+}
+
+func TestImportBooks_UpdateStockToZero(t *testing.T) {
+	db := setupTestDB(t)
+	svc := importer.NewImportService(transaction.NewTransactionManager(db))
+	author := models.Author{Name: "Nguyen Nhat Anh"}
+	require.NoError(t, db.Create(&author).Error)
+	book := models.Book{Title: "Mat Biec", Stock: 5, AuthorID: author.ID}
+	require.NoError(t, db.Create(&book).Error)
+
+	// stock 0 là cách thường dùng để đánh dấu hết hàng, không được bị bỏ qua như giá trị rỗng
+	report, err := svc.ImportBooks(context.Background(), strings.NewReader("title,author,stock\nMat Biec,Nguyen Nhat Anh,0\n"),
+		models.ImportOptions{Format: models.FormatCSV})
+	require.NoError(t, err)
+	require.Equal(t, 1, report.Updated)
+
+	require.NoError(t, db.First(&book, book.ID).Error)
+	require.Zero(t, book.Stock)
+	var changed models.OutboxEvent
+	require.NoError(t, db.Where("type = ?", events.TypeStockChanged).First(&changed).Error)
+	require.JSONEq(t, fmt.Sprintf(`{"book_id":%d,"delta":-5,"stock":0,"reason":"update"}`, book.ID), changed.Payload)
+}
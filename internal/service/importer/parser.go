@@ -0,0 +1,153 @@
+package importer
+
+import (
+	"bufio"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	"github.com/maithuc2003/Test_GIN_golang/internal/models"
+)
+
+// bookRow là một dòng dữ liệu đã đọc; Err khác nil nếu dòng không parse được
+type bookRow struct {
+	Line        int
+	ID          int
+	Title       string
+	Author      string
+	Nationality string
+	Stock       int
+	Err         error
+}
+
+type rowReader interface {
+	// Next trả về io.EOF khi hết dữ liệu
+	Next() (bookRow, error)
+}
+
+func newRowReader(r io.Reader, format string) (rowReader, error) {
+	switch strings.ToLower(format) {
+	case models.FormatCSV:
+		return newCSVReader(r)
+	case models.FormatNDJSON, "jsonl":
+		scanner := bufio.NewScanner(r)
+		scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+		return &ndjsonReader{scanner: scanner}, nil
+	default:
+		return nil, fmt.Errorf("unsupported import format %q", format)
+	}
+}
+
+type csvReader struct {
+	reader  *csv.Reader
+	columns map[string]int
+}
+
+func newCSVReader(r io.Reader) (*csvReader, error) {
+	reader := csv.NewReader(r)
+	reader.FieldsPerRecord = -1
+	reader.TrimLeadingSpace = true
+
+	header, err := reader.Read()
+	if err != nil {
+		if errors.Is(err, io.EOF) {
+			return nil, errors.New("csv header is missing")
+		}
+		return nil, fmt.Errorf("failed to read csv header: %w", err)
+	}
+
+	columns := make(map[string]int, len(header))
+	for i, name := range header {
+		columns[strings.ToLower(strings.TrimSpace(strings.TrimPrefix(name, "\ufeff")))] = i
+	}
+	for _, required := range []string{"title", "author"} {
+		if _, ok := columns[required]; !ok {
+			return nil, fmt.Errorf("csv header must contain column %q", required)
+		}
+	}
+	return &csvReader{reader: reader, columns: columns}, nil
+}
+
+func (c *csvReader) field(record []string, name string) string {
+	i, ok := c.columns[name]
+	if !ok || i >= len(record) {
+		return ""
+	}
+	return strings.TrimSpace(record[i])
+}
+
+func (c *csvReader) Next() (bookRow, error) {
+	record, err := c.reader.Read()
+	if errors.Is(err, io.EOF) {
+		return bookRow{}, io.EOF
+	}
+	line, _ := c.reader.FieldPos(0)
+	if err != nil {
+		var parseErr *csv.ParseError
+		if errors.As(err, &parseErr) {
+			return bookRow{Line: parseErr.StartLine, Err: err}, nil
+		}
+		return bookRow{}, err
+	}
+
+	row := bookRow{
+		Line:        line,
+		Title:       c.field(record, "title"),
+		Author:      c.field(record, "author"),
+		Nationality: c.field(record, "nationality"),
+	}
+	if raw := c.field(record, "id"); raw != "" {
+		if row.ID, err = strconv.Atoi(raw); err != nil {
+			row.Err = fmt.Errorf("invalid id %q", raw)
+			return row, nil
+		}
+	}
+	if raw := c.field(record, "stock"); raw != "" {
+		if row.Stock, err = strconv.Atoi(raw); err != nil {
+			row.Err = fmt.Errorf("invalid stock %q", raw)
+		}
+	}
+	return row, nil
+}
+
+type ndjsonReader struct {
+	scanner *bufio.Scanner
+	line    int
+}
+
+func (n *ndjsonReader) Next() (bookRow, error) {
+	for n.scanner.Scan() {
+		n.line++
+		text := strings.TrimSpace(n.scanner.Text())
+		if text == "" {
+			continue
+		}
+
+		var payload struct {
+			ID          int    `json:"id"`
+			Title       string `json:"title"`
+			Author      string `json:"author"`
+			Nationality string `json:"nationality"`
+			Stock       int    `json:"stock"`
+		}
+		if err := json.Unmarshal([]byte(text), &payload); err != nil {
+			return bookRow{Line: n.line, Err: fmt.Errorf("invalid json: %v", err)}, nil
+		}
+		return bookRow{
+			Line:        n.line,
+			ID:          payload.ID,
+			Title:       strings.TrimSpace(payload.Title),
+			Author:      strings.TrimSpace(payload.Author),
+			Nationality: strings.TrimSpace(payload.Nationality),
+			Stock:       payload.Stock,
+		}, nil
+	}
+	if err := n.scanner.Err(); err != nil {
+		return bookRow{}, err
+	}
+	return bookRow{}, io.EOF
+}
@@ -0,0 +1,115 @@
+package invoice
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/maithuc2003/Test_GIN_golang/internal/interfaces/repositories"
+	"github.com/maithuc2003/Test_GIN_golang/internal/invoice"
+	"github.com/maithuc2003/Test_GIN_golang/internal/models"
+	orderservice "github.com/maithuc2003/Test_GIN_golang/internal/service/order"
+	"github.com/maithuc2003/Test_GIN_golang/internal/tracing"
+)
+
+type InvoiceService struct {
+	orderRepo    repositories.OrderRepositoryInterface
+	invoiceRepo  repositories.InvoiceRepository
+	txManager    repositories.TransactionManager
+	issuer       invoice.Issuer
+	numberPrefix string
+}
+
+func NewInvoiceService(orderRepo repositories.OrderRepositoryInterface, invoiceRepo repositories.InvoiceRepository, txManager repositories.TransactionManager, issuer invoice.Issuer, numberPrefix string) *InvoiceService {
+	return &InvoiceService{
+		orderRepo:    orderRepo,
+		invoiceRepo:  invoiceRepo,
+		txManager:    txManager,
+		issuer:       issuer,
+		numberPrefix: numberPrefix,
+	}
+}
+
+func (s *InvoiceService) GetInvoice(ctx context.Context, userID, orderID uint) (*models.Invoice, error) {
+	ctx, span := tracing.Start(ctx, "InvoiceService.GetInvoice")
+	defer span.End()
+
+	order, err := orderservice.Owned(ctx, s.orderRepo, userID, orderID)
+	if err != nil {
+		return nil, err
+	}
+
+	issued, err := s.invoiceRepo.GetByOrderID(ctx, order.ID)
+	if err == nil {
+		return issued, nil
+	}
+	if !errors.Is(err, repositories.ErrNotFound) {
+		return nil, err
+	}
+	// Đơn đã hoàn tiền vẫn được xuất hoá đơn cho lần thanh toán trước đó
+	if order.Status != models.OrderStatusPaid && order.Status != models.OrderStatusRefunded {
+		return nil, fmt.Errorf("%w: order %d is %s, invoices are issued once it is paid", repositories.ErrConflict, order.ID, order.Status)
+	}
+	if order.Currency == "" {
+		return nil, fmt.Errorf("%w: order %d has no price", repositories.ErrConflict, order.ID)
+	}
+
+	issued, err = s.issue(ctx, order)
+	if errors.Is(err, repositories.ErrConflict) {
+		// Request khác vừa phát hành hoá đơn cho đơn này; số đã cấp trong transaction
+		// của request này bị rollback nên dãy số không bị hụt
+		return s.invoiceRepo.GetByOrderID(ctx, order.ID)
+	}
+	return issued, err
+}
+
+// issue cấp số, render và lưu hoá đơn trong cùng transaction
+func (s *InvoiceService) issue(ctx context.Context, order *models.Order) (*models.Invoice, error) {
+	issuedAt := time.Now().UTC().Truncate(time.Second)
+	var issued *models.Invoice
+	err := s.txManager.WithinTransaction(ctx, func(ctx context.Context, repos repositories.Repositories) error {
+		item := fmt.Sprintf("Book #%d", order.BookID)
+		book, err := repos.Books.GetByBookID(ctx, int(order.BookID))
+		if err == nil {
+			item = book.Title
+		} else if !errors.Is(err, repositories.ErrNotFound) {
+			return err
+		}
+		customer := fmt.Sprintf("Customer #%d", order.UserID)
+		if user, err := repos.Users.GetByID(ctx, order.UserID); err == nil {
+			customer = user.Username
+		} else if !errors.Is(err, repositories.ErrNotFound) {
+			return err
+		}
+
+		series := invoice.Series(s.numberPrefix, issuedAt)
+		seq, err := repos.Invoices.NextNumber(ctx, series)
+		if err != nil {
+			return err
+		}
+		doc := invoice.NewDocument(invoice.Number(series, seq), issuedAt, s.issuer, order, item, customer)
+		html, err := invoice.RenderHTML(doc)
+		if err != nil {
+			return err
+		}
+		pdf, err := invoice.RenderPDF(doc)
+		if err != nil {
+			return err
+		}
+		issued = &models.Invoice{
+			OrderID:    order.ID,
+			Number:     doc.Number,
+			IssuedAt:   issuedAt,
+			Currency:   order.Currency,
+			GrandTotal: order.GrandTotal,
+			HTML:       html,
+			PDF:        pdf,
+		}
+		return repos.Invoices.Create(ctx, issued)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return issued, nil
+}
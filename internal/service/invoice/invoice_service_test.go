@@ -0,0 +1,143 @@
+package invoice_test
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+
+	"github.com/maithuc2003/Test_GIN_golang/internal/interfaces/repositories"
+	"github.com/maithuc2003/Test_GIN_golang/internal/interfaces/service"
+	"github.com/maithuc2003/Test_GIN_golang/internal/invoice"
+	"github.com/maithuc2003/Test_GIN_golang/internal/models"
+	invoicerepo "github.com/maithuc2003/Test_GIN_golang/internal/repositories/invoice"
+	"github.com/maithuc2003/Test_GIN_golang/internal/repositories/order"
+	"github.com/maithuc2003/Test_GIN_golang/internal/repositories/transaction"
+	invoiceservice "github.com/maithuc2003/Test_GIN_golang/internal/service/invoice"
+	sqlitedriver "gorm.io/driver/sqlite"
+
+	_ "modernc.org/sqlite"
+)
+
+func setup(t *testing.T) (*gorm.DB, *invoiceservice.InvoiceService) {
+	dsn := fmt.Sprintf("file:testdb_%d?mode=memory&cache=shared", time.Now().UnixNano())
+	db, err := gorm.Open(sqlitedriver.New(sqlitedriver.Config{DSN: dsn, DriverName: "sqlite"}), &gorm.Config{})
+	require.NoError(t, err)
+	require.NoError(t, db.AutoMigrate(&models.User{}, &models.Author{}, &models.Book{}, &models.Order{}, &models.OrderDiscount{},
+		&models.StockReservation{}, &models.Invoice{}, &models.InvoiceSequence{}, &models.OutboxEvent{}))
+
+	require.NoError(t, db.Create(&models.User{ID: 7, Username: "alice"}).Error)
+	require.NoError(t, db.Create(&models.Book{ID: 1, Title: "Dune", AuthorID: 1, Stock: 5}).Error)
+
+	svc := invoiceservice.NewInvoiceService(order.NewOrderRepo(db), invoicerepo.NewInvoiceRepo(db), transaction.NewTransactionManager(db),
+		invoice.Issuer{Name: "Bookstore"}, "INV")
+	return db, svc
+}
+
+func createOrder(t *testing.T, db *gorm.DB, status string) models.Order {
+	o := models.Order{
+		BookID: 1, UserID: 7, Quantity: 2, Status: status, Currency: "USD",
+		UnitPrice: decimal.RequireFromString("10.50"), Subtotal: decimal.RequireFromString("21.00"), GrandTotal: decimal.RequireFromString("21.00"),
+	}
+	require.NoError(t, db.Create(&o).Error)
+	return o
+}
+
+func TestInvoiceService_GetInvoice(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("issues once and returns the stored invoice afterwards", func(t *testing.T) {
+		db, svc := setup(t)
+		o := createOrder(t, db, models.OrderStatusPaid)
+
+		first, err := svc.GetInvoice(ctx, 7, o.ID)
+		require.NoError(t, err)
+		require.Equal(t, fmt.Sprintf("INV-%d-000001", time.Now().UTC().Year()), first.Number)
+		require.True(t, first.GrandTotal.Equal(decimal.RequireFromString("21.00")))
+		require.Contains(t, string(first.HTML), "Dune")
+		require.Contains(t, string(first.HTML), "alice")
+		require.True(t, bytes.HasPrefix(first.PDF, []byte("%PDF-")))
+
+		// Đổi dữ liệu nguồn sau khi phát hành không làm đổi hoá đơn
+		require.NoError(t, db.Model(&models.Book{}).Where("id = 1").Update("title", "Dune Messiah").Error)
+		again, err := svc.GetInvoice(ctx, 7, o.ID)
+		require.NoError(t, err)
+		require.Equal(t, first.Number, again.Number)
+		require.Equal(t, first.HTML, again.HTML)
+		require.Equal(t, first.PDF, again.PDF)
+	})
+
+	t.Run("numbers are sequential across orders", func(t *testing.T) {
+		db, svc := setup(t)
+		var numbers []string
+		for i := 0; i < 3; i++ {
+			o := createOrder(t, db, models.OrderStatusPaid)
+			issued, err := svc.GetInvoice(ctx, 7, o.ID)
+			require.NoError(t, err)
+			numbers = append(numbers, issued.Number)
+		}
+		for i, number := range numbers {
+			require.True(t, strings.HasSuffix(number, fmt.Sprintf("-%06d", i+1)), number)
+		}
+	})
+
+	t.Run("concurrent requests share one invoice", func(t *testing.T) {
+		db, svc := setup(t)
+		o := createOrder(t, db, models.OrderStatusPaid)
+
+		var wg sync.WaitGroup
+		numbers := make([]string, 4)
+		for i := range numbers {
+			wg.Add(1)
+			go func(i int) {
+				defer wg.Done()
+				issued, err := svc.GetInvoice(ctx, 7, o.ID)
+				if err == nil {
+					numbers[i] = issued.Number
+				}
+			}(i)
+		}
+		wg.Wait()
+		for _, number := range numbers {
+			if number != "" {
+				require.True(t, strings.HasSuffix(number, "-000001"), number)
+			}
+		}
+
+		var count int64
+		require.NoError(t, db.Model(&models.Invoice{}).Count(&count).Error)
+		require.Equal(t, int64(1), count)
+		var seq models.InvoiceSequence
+		require.NoError(t, db.First(&seq).Error)
+		require.Equal(t, int64(1), seq.LastNumber, "rolled back issues must not consume numbers")
+	})
+
+	t.Run("unpaid order", func(t *testing.T) {
+		db, svc := setup(t)
+		o := createOrder(t, db, models.OrderStatusPending)
+		_, err := svc.GetInvoice(ctx, 7, o.ID)
+		require.ErrorIs(t, err, repositories.ErrConflict)
+	})
+
+	t.Run("other user's order", func(t *testing.T) {
+		db, svc := setup(t)
+		o := createOrder(t, db, models.OrderStatusPaid)
+		_, err := svc.GetInvoice(ctx, 8, o.ID)
+		require.ErrorIs(t, err, repositories.ErrNotFound)
+	})
+
+	t.Run("invalid input", func(t *testing.T) {
+		_, svc := setup(t)
+		_, err := svc.GetInvoice(ctx, 0, 1)
+		require.ErrorIs(t, err, service.ErrInvalidInput)
+		_, err = svc.GetInvoice(ctx, 7, 0)
+		require.ErrorIs(t, err, service.ErrInvalidInput)
+	})
+}
@@ -1,24 +1,40 @@
 package order
 
 import (
+	"context"
 	"errors"
+	"fmt"
 	"strings"
 	"time"
 
 	"github.com/maithuc2003/Test_GIN_golang/internal/interfaces/repositories"
+	"github.com/maithuc2003/Test_GIN_golang/internal/interfaces/service"
+	"github.com/maithuc2003/Test_GIN_golang/internal/metrics"
 	"github.com/maithuc2003/Test_GIN_golang/internal/models"
+	"github.com/maithuc2003/Test_GIN_golang/internal/pricing"
+	"github.com/maithuc2003/Test_GIN_golang/internal/tracing"
 )
 
 type OrderService struct {
-	repo repositories.OrderRepositoryInterface
+	repo            repositories.OrderRepositoryInterface
+	reservationRepo repositories.ReservationRepository
+	txManager       repositories.TransactionManager
+	calculator      *pricing.Calculator
+	// reservationTTL là thời gian giữ hàng cho đơn pending trước khi bị huỷ
+	reservationTTL time.Duration
 }
 
-func NewOrderService(repo repositories.OrderRepositoryInterface) *OrderService {
-	return &OrderService{repo: repo}
+func NewOrderService(repo repositories.OrderRepositoryInterface, reservationRepo repositories.ReservationRepository, txManager repositories.TransactionManager, calculator *pricing.Calculator, reservationTTL time.Duration) *OrderService {
+	return &OrderService{repo: repo, reservationRepo: reservationRepo, txManager: txManager, calculator: calculator, reservationTTL: reservationTTL}
 }
 
-// CreateOrder kiểm tra dữ liệu đầu vào trước khi tạo
-func (s *OrderService) CreateOrder(order *models.Order) error {
+// CreateOrder kiểm tra dữ liệu đầu vào trước khi tạo; đơn pending chỉ giữ hàng
+// trong reservationTTL, các trạng thái khác trừ tồn kho ngay. Giá, giảm giá từ
+// CouponCodes và thuế được chốt cùng lượt dùng coupon trong một transaction.
+func (s *OrderService) CreateOrder(ctx context.Context, order *models.Order) error {
+	ctx, span := tracing.Start(ctx, "OrderService.CreateOrder")
+	defer span.End()
+
 	if order == nil {
 		return errors.New("order is nil")
 	}
@@ -34,16 +50,95 @@ func (s *OrderService) CreateOrder(order *models.Order) error {
 	if strings.TrimSpace(order.Status) == "" {
 		return errors.New("status is required")
 	}
+	if err := checkManualStatus(order.Status); err != nil {
+		return err
+	}
 
 	order.OrderedAt = time.Now()
 	order.UpdatedAt = time.Now()
+	order.ReservedUntil = nil
+	if order.Status == models.OrderStatusPending {
+		reservedUntil := order.OrderedAt.Add(s.reservationTTL)
+		order.ReservedUntil = &reservedUntil
+	}
+
+	err := s.txManager.WithinTransaction(ctx, func(ctx context.Context, repos repositories.Repositories) error {
+		if err := s.calculator.Price(ctx, repos, order); err != nil {
+			return err
+		}
+		if err := repos.Orders.Create(ctx, order); err != nil {
+			return err
+		}
+		for _, d := range order.Discounts {
+			if err := repos.Coupons.Redeem(ctx, d.CouponID, order.UserID, order.ID); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	switch {
+	case err == nil:
+		metrics.OrdersCreated.WithLabelValues(metrics.SourceOrder).Inc()
+	case errors.Is(err, repositories.ErrInsufficientStock):
+		metrics.StockOuts.WithLabelValues(metrics.SourceOrder).Inc()
+	}
+	return err
+}
 
-	return s.repo.Create(order)
+// checkManualStatus chặn gán tay các trạng thái chỉ được đặt qua luồng thanh toán
+func checkManualStatus(status string) error {
+	switch status {
+	case models.OrderStatusPaid, models.OrderStatusRefunded:
+		return fmt.Errorf("status %s is set by payments", status)
+	}
+	return nil
+}
+
+// checkStatusChange chặn đổi tay trạng thái của đơn đã thanh toán hoặc hoàn tiền; nếu
+// không, đơn có thể về pending rồi đổi sách, số lượng ở lần cập nhật sau
+func checkStatusChange(existing *models.Order, status string) error {
+	switch existing.Status {
+	case models.OrderStatusPaid, models.OrderStatusRefunded:
+		if status != existing.Status {
+			return fmt.Errorf("%w: order %d is %s, its status is set by payments", repositories.ErrConflict, existing.ID, existing.Status)
+		}
+	}
+	return nil
+}
+
+// Owned lấy đơn orderID của userID; trả về ErrNotFound cả khi đơn thuộc user khác để
+// không lộ đơn của người khác
+func Owned(ctx context.Context, repo repositories.OrderRepositoryInterface, userID, orderID uint) (*models.Order, error) {
+	if userID == 0 {
+		return nil, fmt.Errorf("%w: invalid user ID", service.ErrInvalidInput)
+	}
+	if orderID == 0 {
+		return nil, fmt.Errorf("%w: invalid order ID", service.ErrInvalidInput)
+	}
+	order, err := repo.GetByOrderID(ctx, orderID)
+	if err != nil {
+		return nil, err
+	}
+	if order.UserID != userID {
+		return nil, fmt.Errorf("order with ID %d %w", orderID, repositories.ErrNotFound)
+	}
+	return order, nil
+}
+
+// ReleaseExpiredReservations trả lại tồn kho của các đơn pending đã quá hạn giữ hàng
+func (s *OrderService) ReleaseExpiredReservations(ctx context.Context) (int64, error) {
+	ctx, span := tracing.Start(ctx, "OrderService.ReleaseExpiredReservations")
+	defer span.End()
+
+	return s.reservationRepo.ReleaseExpired(ctx, time.Now())
 }
 
 // GetAllOrders kiểm tra lỗi khi lấy danh sách
-func (s *OrderService) GetAllOrders() ([]*models.Order, error) {
-	orders, err := s.repo.GetAllOrders()
+func (s *OrderService) GetAllOrders(ctx context.Context, filter models.OrderFilter) ([]*models.Order, error) {
+	ctx, span := tracing.Start(ctx, "OrderService.GetAllOrders")
+	defer span.End()
+
+	orders, err := s.repo.GetAllOrders(ctx, filter)
 	if err != nil {
 		return nil, err
 	}
@@ -52,22 +147,31 @@ func (s *OrderService) GetAllOrders() ([]*models.Order, error) {
 	}
 	return orders, nil
 }
-func (s *OrderService) GetByOrderID(id int) (*models.Order, error) {
+func (s *OrderService) GetByOrderID(ctx context.Context, id int) (*models.Order, error) {
+	ctx, span := tracing.Start(ctx, "OrderService.GetByOrderID")
+	defer span.End()
+
 	if id <= 0 {
 		return nil, errors.New("invalid order ID")
 	}
-	return s.repo.GetByOrderID(uint(id)) // convert int -> uint
+	return s.repo.GetByOrderID(ctx, uint(id)) // convert int -> uint
 }
 
-func (s *OrderService) DeleteByOrderID(id int) (*models.Order, error) {
+func (s *OrderService) DeleteByOrderID(ctx context.Context, id int) (*models.Order, error) {
+	ctx, span := tracing.Start(ctx, "OrderService.DeleteByOrderID")
+	defer span.End()
+
 	if id <= 0 {
 		return nil, errors.New("invalid order ID")
 	}
-	return s.repo.DeleteByOrderID(uint(id)) // convert int -> uint
+	return s.repo.DeleteByOrderID(ctx, uint(id)) // convert int -> uint
 }
 
 // UpdateByOrderID kiểm tra dữ liệu trước khi cập nhật
-func (s *OrderService) UpdateByOrderID(order *models.Order) (*models.Order, error) {
+func (s *OrderService) UpdateByOrderID(ctx context.Context, order *models.Order) (*models.Order, error) {
+	ctx, span := tracing.Start(ctx, "OrderService.UpdateByOrderID")
+	defer span.End()
+
 	if order == nil {
 		return nil, errors.New("order is nil")
 	}
@@ -86,10 +190,66 @@ func (s *OrderService) UpdateByOrderID(order *models.Order) (*models.Order, erro
 	if strings.TrimSpace(order.Status) == "" {
 		return nil, errors.New("status is required")
 	}
+	if err := checkManualStatus(order.Status); err != nil {
+		return nil, err
+	}
 
 	order.UpdatedAt = time.Now()
+	// Hạn giữ hàng khi đơn chuyển về pending; đơn đang pending giữ nguyên hạn cũ
+	order.ReservedUntil = nil
+	if order.Status == models.OrderStatusPending {
+		reservedUntil := order.UpdatedAt.Add(s.reservationTTL)
+		order.ReservedUntil = &reservedUntil
+	}
 
-	return s.repo.UpdateByOrderID(order)
+	var updated *models.Order
+	err := s.txManager.WithinTransaction(ctx, func(ctx context.Context, repos repositories.Repositories) error {
+		existing, err := repos.Orders.GetByOrderID(ctx, order.ID)
+		if err != nil {
+			return err
+		}
+		if err := checkStatusChange(existing, order.Status); err != nil {
+			return err
+		}
+		if err := s.reprice(ctx, repos, existing, order); err != nil {
+			return err
+		}
+		updated, err = repos.Orders.UpdateByOrderID(ctx, order)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return updated, nil
+}
+
+// reprice giữ nguyên giá đã chốt của existing cho order; chỉ tính lại (với các coupon
+// đã dùng) khi đổi sách hoặc số lượng, và không cho đổi khi đơn đã thanh toán
+func (s *OrderService) reprice(ctx context.Context, repos repositories.Repositories, existing, order *models.Order) error {
+	order.OrderedAt = existing.OrderedAt
+	order.Currency = existing.Currency
+	order.UnitPrice = existing.UnitPrice
+	order.Subtotal = existing.Subtotal
+	order.Discounts = existing.Discounts
+	order.DiscountTotal = existing.DiscountTotal
+	order.TaxRegion = existing.TaxRegion
+	order.TaxRate = existing.TaxRate
+	order.TaxInclusive = existing.TaxInclusive
+	order.TaxTotal = existing.TaxTotal
+	order.GrandTotal = existing.GrandTotal
+	if order.BookID == existing.BookID && order.Quantity == existing.Quantity {
+		return nil
+	}
+
+	switch existing.Status {
+	case models.OrderStatusPaid, models.OrderStatusRefunded:
+		return fmt.Errorf("%w: order %d is %s, its items and totals can no longer change", repositories.ErrConflict, existing.ID, existing.Status)
+	}
+	order.CouponCodes = make([]string, 0, len(existing.Discounts))
+	for _, d := range existing.Discounts {
+		order.CouponCodes = append(order.CouponCodes, d.Code)
+	}
+	return s.calculator.Price(ctx, repos, order)
 }
 
 
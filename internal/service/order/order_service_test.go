@@ -1,21 +1,36 @@
 package order_test
 
 import (
+	"context"
 	"errors"
 	"testing"
 	"time"
 
+	"github.com/maithuc2003/Test_GIN_golang/internal/interfaces/repositories"
+	"github.com/maithuc2003/Test_GIN_golang/internal/interfaces/service"
 	mocks "github.com/maithuc2003/Test_GIN_golang/internal/mocks/repositories"
 	"github.com/maithuc2003/Test_GIN_golang/internal/models"
+	"github.com/maithuc2003/Test_GIN_golang/internal/pricing"
 	"github.com/maithuc2003/Test_GIN_golang/internal/service/order"
+	"github.com/shopspring/decimal"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
 	"github.com/stretchr/testify/require"
 )
 
+// inTx trả về TransactionManager chạy fn với orderRepo và sách 1 giá 20 USD
+func inTx(orderRepo *mocks.MockOrderRepository) *mocks.MockTransactionManager {
+	price := decimal.NewFromInt(20)
+	bookRepo := new(mocks.MockBookRepo)
+	bookRepo.On("GetByBookID", mock.Anything, 1).Return(&models.Book{ID: 1, Price: &price, Currency: "USD"}, nil).Maybe()
+	txManager := &mocks.MockTransactionManager{Repos: repositories.Repositories{Orders: orderRepo, Books: bookRepo, Coupons: new(mocks.MockCouponRepo)}}
+	txManager.On("WithinTransaction", mock.Anything, mock.Anything).Return(nil).Maybe()
+	return txManager
+}
+
 func TestCreateOrder(t *testing.T) {
 	mockRepo := new(mocks.MockOrderRepository)
-	service := order.NewOrderService(mockRepo)
+	service := order.NewOrderService(mockRepo, new(mocks.MockReservationRepo), inTx(mockRepo), pricing.NewCalculator(nil), 15*time.Minute)
 
 	tests := []struct {
 		name        string
@@ -48,6 +63,11 @@ func TestCreateOrder(t *testing.T) {
 			input:       &models.Order{BookID: 1, UserID: 1, Quantity: 1, Status: ""},
 			expectError: true,
 		},
+		{
+			name:        "refunded set manually",
+			input:       &models.Order{BookID: 1, UserID: 1, Quantity: 1, Status: models.OrderStatusRefunded},
+			expectError: true,
+		},
 		{
 			name:        "valid order",
 			input:       &models.Order{BookID: 1, UserID: 1, Quantity: 1, Status: "confirmed"},
@@ -59,9 +79,9 @@ func TestCreateOrder(t *testing.T) {
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			if tt.input != nil && !tt.expectError {
-				mockRepo.On("Create", mock.AnythingOfType("*models.Order")).Return(tt.mockError).Once()
+				mockRepo.On("Create", mock.Anything, mock.AnythingOfType("*models.Order")).Return(tt.mockError).Once()
 			}
-			err := service.CreateOrder(tt.input)
+			err := service.CreateOrder(context.Background(), tt.input)
 			if tt.expectError {
 				require.Error(t, err)
 			} else {
@@ -71,9 +91,111 @@ func TestCreateOrder(t *testing.T) {
 		})
 	}
 }
+
+func TestCreateOrder_Reservation(t *testing.T) {
+	tests := []struct {
+		name        string
+		status      string
+		wantReserve bool
+	}{
+		{name: "pending order holds stock", status: models.OrderStatusPending, wantReserve: true},
+		{name: "confirmed order takes stock", status: "confirmed", wantReserve: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockRepo := new(mocks.MockOrderRepository)
+			s := order.NewOrderService(mockRepo, new(mocks.MockReservationRepo), inTx(mockRepo), pricing.NewCalculator(nil), 15*time.Minute)
+			mockRepo.On("Create", mock.Anything, mock.AnythingOfType("*models.Order")).Return(nil).Once()
+
+			input := &models.Order{BookID: 1, UserID: 1, Quantity: 1, Status: tt.status}
+			require.NoError(t, s.CreateOrder(context.Background(), input))
+			if !tt.wantReserve {
+				require.Nil(t, input.ReservedUntil)
+				return
+			}
+			require.NotNil(t, input.ReservedUntil)
+			require.WithinDuration(t, input.OrderedAt.Add(15*time.Minute), *input.ReservedUntil, time.Second)
+		})
+	}
+}
+
+func TestCreateOrder_Coupons(t *testing.T) {
+	price := decimal.NewFromInt(20)
+	book := &models.Book{ID: 1, Price: &price, Currency: "USD"}
+	tenOff := &models.Coupon{ID: 4, Code: "TENOFF", Type: models.CouponTypePercentage, Value: decimal.NewFromInt(10), Active: true}
+	fiveUSD := &models.Coupon{ID: 5, Code: "FIVE", Type: models.CouponTypeFixed, Value: decimal.NewFromInt(5), Currency: "USD", Active: true}
+	fictionOnly := &models.Coupon{ID: 6, Code: "FICTION", Type: models.CouponTypePercentage, Value: decimal.NewFromInt(50), CategoryIDs: []uint{8}, Active: true}
+
+	tests := []struct {
+		name       string
+		codes      []string
+		redeemErr  error
+		wantTotal  string
+		wantErr    error
+		wantCreate bool
+	}{
+		{name: "stacked coupons", codes: []string{"tenoff", " five "}, wantTotal: "9", wantCreate: true},
+		{name: "unknown coupon", codes: []string{"NOPE"}, wantErr: service.ErrInvalidInput},
+		{name: "coupon for other category", codes: []string{"FICTION"}, wantErr: service.ErrInvalidInput},
+		{name: "usage limit reached", codes: []string{"TENOFF"}, redeemErr: repositories.ErrConflict, wantErr: repositories.ErrConflict, wantCreate: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			orderRepo := new(mocks.MockOrderRepository)
+			bookRepo := new(mocks.MockBookRepo)
+			couponRepo := new(mocks.MockCouponRepo)
+			txManager := &mocks.MockTransactionManager{Repos: repositories.Repositories{Orders: orderRepo, Books: bookRepo, Coupons: couponRepo}}
+			txManager.On("WithinTransaction", mock.Anything, mock.Anything).Return(nil)
+			s := order.NewOrderService(new(mocks.MockOrderRepository), new(mocks.MockReservationRepo), txManager, pricing.NewCalculator(nil), 15*time.Minute)
+
+			bookRepo.On("GetByBookID", mock.Anything, 1).Return(book, nil)
+			couponRepo.On("BookCategoryIDs", mock.Anything, uint(1)).Return([]uint{2, 1}, nil)
+			couponRepo.On("GetByCode", mock.Anything, "TENOFF").Return(tenOff, nil)
+			couponRepo.On("GetByCode", mock.Anything, "FIVE").Return(fiveUSD, nil)
+			couponRepo.On("GetByCode", mock.Anything, "FICTION").Return(fictionOnly, nil)
+			couponRepo.On("GetByCode", mock.Anything, "NOPE").Return((*models.Order)(nil), repositories.ErrNotFound)
+			if tt.wantCreate {
+				orderRepo.On("Create", mock.Anything, mock.AnythingOfType("*models.Order")).Run(func(args mock.Arguments) {
+					args.Get(1).(*models.Order).ID = 42
+				}).Return(nil).Once()
+				couponRepo.On("Redeem", mock.Anything, mock.Anything, uint(7), uint(42)).Return(tt.redeemErr)
+			}
+
+			input := &models.Order{BookID: 1, UserID: 7, Quantity: 2, Status: models.OrderStatusPending, CouponCodes: tt.codes}
+			err := s.CreateOrder(context.Background(), input)
+			if tt.wantErr != nil {
+				require.ErrorIs(t, err, tt.wantErr)
+				orderRepo.AssertExpectations(t)
+				return
+			}
+			require.NoError(t, err)
+			require.True(t, decimal.RequireFromString(tt.wantTotal).Equal(input.DiscountTotal), "discount total = %s", input.DiscountTotal)
+			require.Equal(t, "USD", input.Currency)
+			require.True(t, decimal.NewFromInt(40).Sub(input.DiscountTotal).Equal(input.GrandTotal), "grand total = %s", input.GrandTotal)
+			require.Len(t, input.Discounts, len(tt.codes))
+			require.NotNil(t, input.ReservedUntil)
+			orderRepo.AssertExpectations(t)
+			couponRepo.AssertNumberOfCalls(t, "Redeem", len(tt.codes))
+		})
+	}
+}
+
+func TestOrderService_ReleaseExpiredReservations(t *testing.T) {
+	reservationRepo := new(mocks.MockReservationRepo)
+	s := order.NewOrderService(new(mocks.MockOrderRepository), reservationRepo, new(mocks.MockTransactionManager), pricing.NewCalculator(nil), 15*time.Minute)
+	reservationRepo.On("ReleaseExpired", mock.Anything, mock.AnythingOfType("time.Time")).Return(int64(3), nil).Once()
+
+	n, err := s.ReleaseExpiredReservations(context.Background())
+	require.NoError(t, err)
+	require.Equal(t, int64(3), n)
+	reservationRepo.AssertExpectations(t)
+}
+
 func TestOrderService_GetAllOrders(t *testing.T) {
 	mockRepo := new(mocks.MockOrderRepository)
-	s := order.NewOrderService(mockRepo)
+	s := order.NewOrderService(mockRepo, new(mocks.MockReservationRepo), inTx(mockRepo), pricing.NewCalculator(nil), 15*time.Minute)
 
 	tests := []struct {
 		name        string
@@ -108,9 +230,9 @@ func TestOrderService_GetAllOrders(t *testing.T) {
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			mockRepo.ExpectedCalls = nil // reset mock
-			mockRepo.On("GetAllOrders").Return(tt.mockOrders, tt.mockError).Once()
+			mockRepo.On("GetAllOrders", mock.Anything, mock.Anything).Return(tt.mockOrders, tt.mockError).Once()
 
-			result, err := s.GetAllOrders()
+			result, err := s.GetAllOrders(context.Background(), models.OrderFilter{})
 
 			if tt.expectedErr != "" {
 				require.Error(t, err)
@@ -128,7 +250,7 @@ func TestOrderService_GetAllOrders(t *testing.T) {
 
 func TestOrderService_GetByOrderID(t *testing.T) {
 	mockRepo := new(mocks.MockOrderRepository)
-	s := order.NewOrderService(mockRepo)
+	s := order.NewOrderService(mockRepo, new(mocks.MockReservationRepo), inTx(mockRepo), pricing.NewCalculator(nil), 15*time.Minute)
 
 	tests := []struct {
 		name        string
@@ -160,10 +282,10 @@ func TestOrderService_GetByOrderID(t *testing.T) {
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			if tt.id > 0 {
-				mockRepo.On("GetByOrderID", uint(tt.id)).Return(tt.mockOrder, tt.mockError).Once()
+				mockRepo.On("GetByOrderID", mock.Anything, uint(tt.id)).Return(tt.mockOrder, tt.mockError).Once()
 			}
 
-			result, err := s.GetByOrderID(tt.id)
+			result, err := s.GetByOrderID(context.Background(), tt.id)
 
 			if tt.expectedErr != "" {
 				require.Error(t, err)
@@ -178,7 +300,7 @@ func TestOrderService_GetByOrderID(t *testing.T) {
 
 func TestOrderService_DeleteByOrderID(t *testing.T) {
 	mockRepo := new(mocks.MockOrderRepository)
-	s := order.NewOrderService(mockRepo)
+	s := order.NewOrderService(mockRepo, new(mocks.MockReservationRepo), inTx(mockRepo), pricing.NewCalculator(nil), 15*time.Minute)
 
 	tests := []struct {
 		name        string
@@ -210,10 +332,10 @@ func TestOrderService_DeleteByOrderID(t *testing.T) {
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			if tt.id > 0 {
-				mockRepo.On("DeleteByOrderID", uint(tt.id)).Return(tt.mockOrder, tt.mockError).Once()
+				mockRepo.On("DeleteByOrderID", mock.Anything, uint(tt.id)).Return(tt.mockOrder, tt.mockError).Once()
 			}
 
-			result, err := s.DeleteByOrderID(tt.id)
+			result, err := s.DeleteByOrderID(context.Background(), tt.id)
 
 			if tt.expectedErr != "" {
 				require.Error(t, err)
@@ -228,7 +350,7 @@ func TestOrderService_DeleteByOrderID(t *testing.T) {
 
 func TestOrderService_UpdateByOrderID(t *testing.T) {
 	mockRepo := new(mocks.MockOrderRepository)
-	s := order.NewOrderService(mockRepo)
+	s := order.NewOrderService(mockRepo, new(mocks.MockReservationRepo), inTx(mockRepo), pricing.NewCalculator(nil), 15*time.Minute)
 
 	// now := time.Now()
 
@@ -279,6 +401,13 @@ func TestOrderService_UpdateByOrderID(t *testing.T) {
 			},
 			expectedErr: "status is required",
 		},
+		{
+			name: "paid set manually",
+			order: &models.Order{
+				ID: 1, BookID: 1, UserID: 1, Quantity: 1, Status: models.OrderStatusPaid,
+			},
+			expectedErr: "status paid is set by payments",
+		},
 		{
 			name: "success",
 			order: &models.Order{
@@ -293,10 +422,11 @@ func TestOrderService_UpdateByOrderID(t *testing.T) {
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			if tt.expectedErr == "" {
-				mockRepo.On("UpdateByOrderID", tt.order).Return(tt.mockReturn, tt.mockError).Once()
+				mockRepo.On("GetByOrderID", mock.Anything, tt.order.ID).Return(&models.Order{ID: tt.order.ID, BookID: tt.order.BookID, Quantity: tt.order.Quantity}, nil).Once()
+				mockRepo.On("UpdateByOrderID", mock.Anything, tt.order).Return(tt.mockReturn, tt.mockError).Once()
 			}
 
-			result, err := s.UpdateByOrderID(tt.order)
+			result, err := s.UpdateByOrderID(context.Background(), tt.order)
 
 			if tt.expectedErr != "" {
 				require.Error(t, err)
@@ -305,7 +435,90 @@ func TestOrderService_UpdateByOrderID(t *testing.T) {
 				require.NoError(t, err)
 				assert.Equal(t, tt.mockReturn, result)
 				assert.WithinDuration(t, time.Now(), tt.order.UpdatedAt, time.Second)
+				require.NotNil(t, tt.order.ReservedUntil, "pending order gets a reservation deadline")
+				assert.WithinDuration(t, time.Now().Add(15*time.Minute), *tt.order.ReservedUntil, time.Second)
 			}
 		})
 	}
 }
+
+func TestOrderService_UpdateByOrderID_Totals(t *testing.T) {
+	stored := func(status string) *models.Order {
+		return &models.Order{
+			ID: 1, BookID: 1, UserID: 1, Quantity: 1, Status: status, Currency: "USD",
+			UnitPrice: decimal.NewFromInt(15), Subtotal: decimal.NewFromInt(15), GrandTotal: decimal.NewFromInt(15),
+		}
+	}
+
+	tests := []struct {
+		name      string
+		stored    *models.Order
+		quantity  int
+		wantErr   error
+		wantGrand string
+	}{
+		{name: "keeps fixed totals when items do not change", stored: stored(models.OrderStatusPending), quantity: 1, wantGrand: "15"},
+		{name: "reprices pending order at current price", stored: stored(models.OrderStatusPending), quantity: 2, wantGrand: "40"},
+		{name: "paid order cannot change", stored: stored(models.OrderStatusPaid), quantity: 2, wantErr: repositories.ErrConflict},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockRepo := new(mocks.MockOrderRepository)
+			s := order.NewOrderService(mockRepo, new(mocks.MockReservationRepo), inTx(mockRepo), pricing.NewCalculator(nil), 15*time.Minute)
+			mockRepo.On("GetByOrderID", mock.Anything, uint(1)).Return(tt.stored, nil).Once()
+			if tt.wantErr == nil {
+				mockRepo.On("UpdateByOrderID", mock.Anything, mock.AnythingOfType("*models.Order")).Return(&models.Order{ID: 1}, nil).Once()
+			}
+
+			// Client gửi kèm tổng tiền tuỳ ý, service phải bỏ qua
+			input := &models.Order{ID: 1, BookID: 1, UserID: 1, Quantity: tt.quantity, Status: "shipped", GrandTotal: decimal.NewFromInt(1)}
+			_, err := s.UpdateByOrderID(context.Background(), input)
+			if tt.wantErr != nil {
+				require.ErrorIs(t, err, tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			require.True(t, decimal.RequireFromString(tt.wantGrand).Equal(input.GrandTotal), "grand total = %s", input.GrandTotal)
+			mockRepo.AssertExpectations(t)
+		})
+	}
+}
+
+func TestOrderService_UpdateByOrderID_PaidStatus(t *testing.T) {
+	for _, status := range []string{models.OrderStatusPaid, models.OrderStatusRefunded} {
+		t.Run(status, func(t *testing.T) {
+			mockRepo := new(mocks.MockOrderRepository)
+			s := order.NewOrderService(mockRepo, new(mocks.MockReservationRepo), inTx(mockRepo), pricing.NewCalculator(nil), 15*time.Minute)
+			mockRepo.On("GetByOrderID", mock.Anything, uint(1)).Return(&models.Order{
+				ID: 1, BookID: 1, UserID: 1, Quantity: 1, Status: status, GrandTotal: decimal.NewFromInt(15),
+			}, nil)
+
+			// Đưa đơn về pending (giữ nguyên sách, số lượng) để lần sau đổi số lượng: bị chặn ngay bước đầu
+			_, err := s.UpdateByOrderID(context.Background(), &models.Order{ID: 1, BookID: 1, UserID: 1, Quantity: 1, Status: models.OrderStatusPending})
+			require.ErrorIs(t, err, repositories.ErrConflict)
+			_, err = s.UpdateByOrderID(context.Background(), &models.Order{ID: 1, BookID: 1, UserID: 1, Quantity: 2, Status: models.OrderStatusPending})
+			require.ErrorIs(t, err, repositories.ErrConflict)
+			mockRepo.AssertNotCalled(t, "UpdateByOrderID", mock.Anything, mock.Anything)
+		})
+	}
+}
+
+func TestOwned(t *testing.T) {
+	mockRepo := new(mocks.MockOrderRepository)
+	mockRepo.On("GetByOrderID", mock.Anything, uint(1)).Return(&models.Order{ID: 1, UserID: 7}, nil)
+	mockRepo.On("GetByOrderID", mock.Anything, uint(2)).Return((*models.Order)(nil), repositories.ErrNotFound)
+
+	got, err := order.Owned(context.Background(), mockRepo, 7, 1)
+	require.NoError(t, err)
+	assert.Equal(t, uint(1), got.ID)
+
+	_, err = order.Owned(context.Background(), mockRepo, 8, 1)
+	require.ErrorIs(t, err, repositories.ErrNotFound, "other user's order looks missing")
+	_, err = order.Owned(context.Background(), mockRepo, 7, 2)
+	require.ErrorIs(t, err, repositories.ErrNotFound)
+	_, err = order.Owned(context.Background(), mockRepo, 0, 1)
+	require.ErrorIs(t, err, service.ErrInvalidInput)
+	_, err = order.Owned(context.Background(), mockRepo, 7, 0)
+	require.ErrorIs(t, err, service.ErrInvalidInput)
+}
@@ -0,0 +1,221 @@
+package payment
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+
+	"github.com/maithuc2003/Test_GIN_golang/internal/interfaces/repositories"
+	"github.com/maithuc2003/Test_GIN_golang/internal/interfaces/service"
+	"github.com/maithuc2003/Test_GIN_golang/internal/models"
+	"github.com/maithuc2003/Test_GIN_golang/internal/payment"
+	orderservice "github.com/maithuc2003/Test_GIN_golang/internal/service/order"
+	"github.com/maithuc2003/Test_GIN_golang/internal/tracing"
+)
+
+type PaymentService struct {
+	orderRepo   repositories.OrderRepositoryInterface
+	paymentRepo repositories.PaymentRepository
+	txManager   repositories.TransactionManager
+	provider    payment.Provider
+}
+
+func NewPaymentService(orderRepo repositories.OrderRepositoryInterface, paymentRepo repositories.PaymentRepository, txManager repositories.TransactionManager, provider payment.Provider) *PaymentService {
+	return &PaymentService{orderRepo: orderRepo, paymentRepo: paymentRepo, txManager: txManager, provider: provider}
+}
+
+// Pay giữ tiền qua cổng thanh toán rồi, nếu được chấp nhận, trừ hẳn tồn kho đang giữ và
+// chuyển đơn sang paid (commit) trước khi capture, để không có khoản tiền đã thu nào mà
+// đơn không được ghi nhận. Capture lỗi thì hoàn tác: trả lại tồn kho và chuyển đơn sang
+// expired. Thẻ bị từ chối hoặc cổng trả về pending thì đơn vẫn pending (có thể thanh
+// toán lại trong thời gian giữ hàng).
+func (s *PaymentService) Pay(ctx context.Context, userID, orderID uint, req models.PayRequest) (*models.Payment, error) {
+	ctx, span := tracing.Start(ctx, "PaymentService.Pay")
+	defer span.End()
+
+	order, err := orderservice.Owned(ctx, s.orderRepo, userID, orderID)
+	if err != nil {
+		return nil, err
+	}
+	if order.Status != models.OrderStatusPending {
+		return nil, fmt.Errorf("%w: order %d is %s, not awaiting payment", repositories.ErrConflict, order.ID, order.Status)
+	}
+	// Số tiền thanh toán là GrandTotal đã chốt khi tạo đơn, không tính lại theo giá hiện tại
+	if order.Currency == "" {
+		return nil, fmt.Errorf("%w: order %d has no price", service.ErrInvalidInput, order.ID)
+	}
+	amount := order.GrandTotal
+
+	result, err := s.provider.Authorize(ctx, payment.AuthorizeRequest{
+		OrderID:  order.ID,
+		Amount:   amount,
+		Currency: order.Currency,
+		Token:    req.Token,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("payment authorization failed: %w", err)
+	}
+	record := &models.Payment{
+		OrderID:       order.ID,
+		Provider:      s.provider.Name(),
+		Reference:     result.Reference,
+		Status:        result.Status,
+		Amount:        amount,
+		Currency:      order.Currency,
+		FailureReason: result.FailureReason,
+	}
+	if result.Status != models.PaymentStatusAuthorized {
+		if err := s.paymentRepo.Create(ctx, record); err != nil {
+			return nil, err
+		}
+		return record, nil
+	}
+
+	err = s.txManager.WithinTransaction(ctx, func(ctx context.Context, repos repositories.Repositories) error {
+		if err := markPaid(ctx, repos, order.ID); err != nil {
+			return err
+		}
+		return repos.Payments.Create(ctx, record)
+	})
+	if err != nil {
+		// Tiền mới chỉ được giữ (chưa capture); lần giữ này sẽ tự hết hạn phía cổng thanh toán
+		record.Status = models.PaymentStatusFailed
+		record.FailureReason = err.Error()
+		if createErr := s.paymentRepo.Create(context.Background(), record); createErr != nil {
+			slog.ErrorContext(ctx, "failed to record payment", "reference", record.Reference, "order_id", order.ID, "error", createErr)
+		}
+		return nil, err
+	}
+
+	captured, err := s.provider.Capture(ctx, record.Reference, record.Amount)
+	if err != nil {
+		err = fmt.Errorf("payment capture failed: %w", err)
+		if releaseErr := s.releasePaid(context.Background(), order, record, err); releaseErr != nil {
+			slog.ErrorContext(ctx, "failed to release order after capture failure", "reference", record.Reference, "order_id", order.ID, "error", releaseErr)
+			return nil, errors.Join(err, releaseErr)
+		}
+		return nil, err
+	}
+	record.Status = captured.Status
+	if err := s.paymentRepo.Update(context.Background(), record); err != nil {
+		// Đơn đã paid; payment vẫn là authorized tới khi webhook captured cập nhật lại
+		slog.ErrorContext(ctx, "failed to record captured payment", "reference", record.Reference, "order_id", order.ID, "error", err)
+	}
+	return record, nil
+}
+
+// releasePaid hoàn tác markPaid khi capture thất bại: đơn chuyển từ paid sang expired,
+// tồn kho đã trừ được trả lại và payment bị đánh dấu failed
+func (s *PaymentService) releasePaid(ctx context.Context, order *models.Order, record *models.Payment, cause error) error {
+	return s.txManager.WithinTransaction(ctx, func(ctx context.Context, repos repositories.Repositories) error {
+		if err := repos.Orders.UpdateStatus(ctx, order.ID, models.OrderStatusPaid, models.OrderStatusExpired); err != nil {
+			return err
+		}
+		if err := repos.Books.Restock(ctx, int(order.BookID), order.Quantity); err != nil {
+			return err
+		}
+		if err := repos.Coupons.Release(ctx, order.ID); err != nil {
+			return err
+		}
+		record.Status = models.PaymentStatusFailed
+		record.FailureReason = cause.Error()
+		return repos.Payments.Update(ctx, record)
+	})
+}
+
+func (s *PaymentService) HandleWebhook(ctx context.Context, payload []byte, signature string) error {
+	ctx, span := tracing.Start(ctx, "PaymentService.HandleWebhook")
+	defer span.End()
+
+	event, err := s.provider.VerifyWebhook(payload, signature)
+	if err != nil {
+		if errors.Is(err, payment.ErrInvalidSignature) {
+			return err
+		}
+		return fmt.Errorf("%w: %v", service.ErrInvalidInput, err)
+	}
+	record, err := s.paymentRepo.GetByReference(ctx, s.provider.Name(), event.Reference)
+	if err != nil {
+		return err
+	}
+	if record.Status == event.Status {
+		return nil
+	}
+
+	from := record.Status
+	switch {
+	case from == models.PaymentStatusPending && event.Status == models.PaymentStatusFailed:
+		record.Status = models.PaymentStatusFailed
+		record.FailureReason = event.FailureReason
+		return s.paymentRepo.Update(ctx, record)
+
+	case from == models.PaymentStatusAuthorized && event.Status == models.PaymentStatusCaptured:
+		// Pay đã chuyển đơn sang paid trước khi capture; chỉ còn cập nhật payment
+		record.Status = models.PaymentStatusCaptured
+		return s.paymentRepo.Update(ctx, record)
+
+	case from == models.PaymentStatusPending && event.Status == models.PaymentStatusCaptured:
+		err := s.txManager.WithinTransaction(ctx, func(ctx context.Context, repos repositories.Repositories) error {
+			if err := markPaid(ctx, repos, record.OrderID); err != nil {
+				return err
+			}
+			record.Status = models.PaymentStatusCaptured
+			return repos.Payments.Update(ctx, record)
+		})
+		if errors.Is(err, repositories.ErrConflict) {
+			// Tiền đã về nhưng hàng không còn được giữ cho đơn: hoàn lại toàn bộ
+			return s.refundUnfulfillable(ctx, record, err)
+		}
+		return err
+
+	case from == models.PaymentStatusCaptured && event.Status == models.PaymentStatusRefunded:
+		return s.txManager.WithinTransaction(ctx, func(ctx context.Context, repos repositories.Repositories) error {
+			record.Status = models.PaymentStatusRefunded
+			if err := repos.Payments.Update(ctx, record); err != nil {
+				return err
+			}
+			return repos.Orders.UpdateStatus(ctx, record.OrderID, models.OrderStatusPaid, models.OrderStatusRefunded)
+		})
+
+	default:
+		return fmt.Errorf("%w: cannot move payment %s from %s to %s", repositories.ErrConflict, record.Reference, from, event.Status)
+	}
+}
+
+func (s *PaymentService) GetPayments(ctx context.Context, userID, orderID uint) ([]models.Payment, error) {
+	ctx, span := tracing.Start(ctx, "PaymentService.GetPayments")
+	defer span.End()
+
+	if _, err := orderservice.Owned(ctx, s.orderRepo, userID, orderID); err != nil {
+		return nil, err
+	}
+	payments, err := s.paymentRepo.ListByOrder(ctx, orderID)
+	if err != nil {
+		return nil, err
+	}
+	if payments == nil {
+		payments = []models.Payment{}
+	}
+	return payments, nil
+}
+
+// markPaid trừ hẳn tồn kho đang giữ cho đơn rồi chuyển đơn từ pending sang paid
+func markPaid(ctx context.Context, repos repositories.Repositories, orderID uint) error {
+	if err := repos.Reservations.Commit(ctx, orderID); err != nil {
+		if errors.Is(err, repositories.ErrNotFound) {
+			return fmt.Errorf("%w: stock reservation for order %d has expired", repositories.ErrConflict, orderID)
+		}
+		return err
+	}
+	return repos.Orders.UpdateStatus(ctx, orderID, models.OrderStatusPending, models.OrderStatusPaid)
+}
+
+func (s *PaymentService) refundUnfulfillable(ctx context.Context, record *models.Payment, cause error) error {
+	if _, err := s.provider.Refund(ctx, record.Reference, record.Amount); err != nil {
+		return fmt.Errorf("failed to refund payment %s after %v: %w", record.Reference, cause, err)
+	}
+	record.Status = models.PaymentStatusRefunded
+	record.FailureReason = cause.Error()
+	return s.paymentRepo.Update(ctx, record)
+}
@@ -0,0 +1,282 @@
+package payment_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+
+	"github.com/maithuc2003/Test_GIN_golang/internal/interfaces/repositories"
+	"github.com/maithuc2003/Test_GIN_golang/internal/interfaces/service"
+	"github.com/maithuc2003/Test_GIN_golang/internal/models"
+	"github.com/maithuc2003/Test_GIN_golang/internal/payment"
+	"github.com/maithuc2003/Test_GIN_golang/internal/repositories/order"
+	paymentrepo "github.com/maithuc2003/Test_GIN_golang/internal/repositories/payment"
+	"github.com/maithuc2003/Test_GIN_golang/internal/repositories/transaction"
+	paymentservice "github.com/maithuc2003/Test_GIN_golang/internal/service/payment"
+	sqlitedriver "gorm.io/driver/sqlite"
+
+	_ "modernc.org/sqlite"
+)
+
+type fixture struct {
+	db       *gorm.DB
+	provider *payment.FakeProvider
+	svc      *paymentservice.PaymentService
+	book     models.Book
+}
+
+func setup(t *testing.T) *fixture {
+	dsn := fmt.Sprintf("file:testdb_%d?mode=memory&cache=shared", time.Now().UnixNano())
+	db, err := gorm.Open(sqlitedriver.New(sqlitedriver.Config{DSN: dsn, DriverName: "sqlite"}), &gorm.Config{})
+	require.NoError(t, err)
+	require.NoError(t, db.AutoMigrate(&models.Author{}, &models.Book{}, &models.Order{}, &models.OrderDiscount{}, &models.StockReservation{}, &models.Payment{}, &models.Coupon{}, &models.CouponRedemption{}, &models.OutboxEvent{}))
+
+	price := decimal.RequireFromString("10.50")
+	dune := models.Book{Title: "Dune", AuthorID: 1, Stock: 5, Price: &price, Currency: "USD"}
+	require.NoError(t, db.Create(&dune).Error)
+
+	provider := payment.NewFakeProvider("secret")
+	svc := paymentservice.NewPaymentService(order.NewOrderRepo(db), paymentrepo.NewPaymentRepo(db), transaction.NewTransactionManager(db), provider)
+	return &fixture{db: db, provider: provider, svc: svc, book: dune}
+}
+
+// reservedOrder tạo đơn pending giữ quantity cuốn trong ttl cho user 7
+func (f *fixture) reservedOrder(t *testing.T, quantity int, ttl time.Duration) models.Order {
+	until := time.Now().Add(ttl)
+	total := f.book.Price.Mul(decimal.NewFromInt(int64(quantity)))
+	o := models.Order{
+		BookID: f.book.ID, UserID: 7, Quantity: quantity, Status: models.OrderStatusPending, ReservedUntil: &until,
+		Currency: "USD", UnitPrice: *f.book.Price, Subtotal: total, GrandTotal: total,
+	}
+	require.NoError(t, order.NewOrderRepo(f.db).Create(context.Background(), &o))
+	return o
+}
+
+func (f *fixture) requireState(t *testing.T, orderID uint, wantOrderStatus string, wantStock int) {
+	var stored models.Order
+	require.NoError(t, f.db.First(&stored, orderID).Error)
+	require.Equal(t, wantOrderStatus, stored.Status)
+	var dune models.Book
+	require.NoError(t, f.db.First(&dune, f.book.ID).Error)
+	require.Equal(t, wantStock, dune.Stock)
+}
+
+func TestPay(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("captured payment marks order paid and takes stock", func(t *testing.T) {
+		f := setup(t)
+		o := f.reservedOrder(t, 2, time.Minute)
+
+		p, err := f.svc.Pay(ctx, 7, o.ID, models.PayRequest{Token: "tok_visa"})
+		require.NoError(t, err)
+		require.Equal(t, models.PaymentStatusCaptured, p.Status)
+		require.True(t, p.Amount.Equal(decimal.NewFromInt(21)))
+		require.Equal(t, "USD", p.Currency)
+		f.requireState(t, o.ID, models.OrderStatusPaid, 3)
+
+		var reservations int64
+		require.NoError(t, f.db.Model(&models.StockReservation{}).Count(&reservations).Error)
+		require.Zero(t, reservations)
+
+		_, err = f.svc.Pay(ctx, 7, o.ID, models.PayRequest{})
+		require.ErrorIs(t, err, repositories.ErrConflict, "order already paid")
+	})
+
+	t.Run("declined card keeps order pending", func(t *testing.T) {
+		f := setup(t)
+		o := f.reservedOrder(t, 2, time.Minute)
+
+		p, err := f.svc.Pay(ctx, 7, o.ID, models.PayRequest{Token: payment.FakeTokenDeclined})
+		require.NoError(t, err)
+		require.Equal(t, models.PaymentStatusFailed, p.Status)
+		require.Equal(t, "card declined", p.FailureReason)
+		f.requireState(t, o.ID, models.OrderStatusPending, 5)
+
+		// Thanh toán lại bằng thẻ khác trong thời gian giữ hàng
+		p, err = f.svc.Pay(ctx, 7, o.ID, models.PayRequest{Token: "tok_visa"})
+		require.NoError(t, err)
+		require.Equal(t, models.PaymentStatusCaptured, p.Status)
+
+		payments, err := f.svc.GetPayments(ctx, 7, o.ID)
+		require.NoError(t, err)
+		require.Len(t, payments, 2)
+	})
+
+	t.Run("expired reservation rolls back", func(t *testing.T) {
+		f := setup(t)
+		o := f.reservedOrder(t, 2, -time.Minute)
+
+		_, err := f.svc.Pay(ctx, 7, o.ID, models.PayRequest{})
+		require.ErrorIs(t, err, repositories.ErrConflict)
+		f.requireState(t, o.ID, models.OrderStatusPending, 5)
+
+		payments, err := f.svc.GetPayments(ctx, 7, o.ID)
+		require.NoError(t, err)
+		require.Len(t, payments, 1)
+		require.Equal(t, models.PaymentStatusFailed, payments[0].Status)
+	})
+
+	t.Run("other user's order", func(t *testing.T) {
+		f := setup(t)
+		o := f.reservedOrder(t, 1, time.Minute)
+
+		_, err := f.svc.Pay(ctx, 8, o.ID, models.PayRequest{})
+		require.ErrorIs(t, err, repositories.ErrNotFound)
+		_, err = f.svc.GetPayments(ctx, 8, o.ID)
+		require.ErrorIs(t, err, repositories.ErrNotFound)
+	})
+
+	t.Run("order without price", func(t *testing.T) {
+		f := setup(t)
+		o := f.reservedOrder(t, 1, time.Minute)
+		require.NoError(t, f.db.Model(&models.Order{}).Where("id = ?", o.ID).Update("currency", "").Error)
+
+		_, err := f.svc.Pay(ctx, 7, o.ID, models.PayRequest{})
+		require.ErrorIs(t, err, service.ErrInvalidInput)
+	})
+
+	t.Run("charges the total fixed at order creation", func(t *testing.T) {
+		f := setup(t)
+		o := f.reservedOrder(t, 2, time.Minute)
+		require.NoError(t, f.db.Model(&models.Order{}).Where("id = ?", o.ID).Update("grand_total", decimal.RequireFromString("19.95")).Error)
+		require.NoError(t, f.db.Model(&f.book).Update("price", decimal.NewFromInt(99)).Error)
+
+		p, err := f.svc.Pay(ctx, 7, o.ID, models.PayRequest{Token: "tok_visa"})
+		require.NoError(t, err)
+		require.True(t, p.Amount.Equal(decimal.RequireFromString("19.95")), "amount = %s", p.Amount)
+	})
+}
+
+// failingCapture là cổng thanh toán giữ tiền được nhưng capture luôn lỗi
+type failingCapture struct {
+	*payment.FakeProvider
+}
+
+func (failingCapture) Capture(context.Context, string, decimal.Decimal) (*payment.Result, error) {
+	return nil, errors.New("gateway timeout")
+}
+
+func TestPay_CaptureFailure(t *testing.T) {
+	ctx := context.Background()
+	f := setup(t)
+	o := f.reservedOrder(t, 2, time.Minute)
+	svc := paymentservice.NewPaymentService(order.NewOrderRepo(f.db), paymentrepo.NewPaymentRepo(f.db),
+		transaction.NewTransactionManager(f.db), failingCapture{f.provider})
+
+	_, err := svc.Pay(ctx, 7, o.ID, models.PayRequest{Token: "tok_visa"})
+	require.ErrorContains(t, err, "gateway timeout")
+	// Đơn đã được commit trước khi capture nên phải được hoàn tác: trả lại tồn kho
+	f.requireState(t, o.ID, models.OrderStatusExpired, 5)
+
+	payments, err := f.svc.GetPayments(ctx, 7, o.ID)
+	require.NoError(t, err)
+	require.Len(t, payments, 1)
+	require.Equal(t, models.PaymentStatusFailed, payments[0].Status)
+	require.Contains(t, payments[0].FailureReason, "gateway timeout")
+}
+
+func TestHandleWebhook_CapturedAfterPay(t *testing.T) {
+	ctx := context.Background()
+	f := setup(t)
+	o := f.reservedOrder(t, 2, time.Minute)
+	p, err := f.svc.Pay(ctx, 7, o.ID, models.PayRequest{Token: "tok_visa"})
+	require.NoError(t, err)
+
+	// Payment còn authorized (vd: lưu kết quả capture bị lỗi); webhook captured chỉ cập
+	// nhật payment, không trừ tồn kho lần nữa và không hoàn tiền
+	require.NoError(t, f.db.Model(&models.Payment{}).Where("id = ?", p.ID).Update("status", models.PaymentStatusAuthorized).Error)
+	payload := []byte(fmt.Sprintf(`{"reference":%q,"status":"captured"}`, p.Reference))
+	require.NoError(t, f.svc.HandleWebhook(ctx, payload, f.provider.Sign(payload)))
+	f.requireState(t, o.ID, models.OrderStatusPaid, 3)
+
+	payments, err := f.svc.GetPayments(ctx, 7, o.ID)
+	require.NoError(t, err)
+	require.Equal(t, models.PaymentStatusCaptured, payments[0].Status)
+}
+
+func TestHandleWebhook(t *testing.T) {
+	ctx := context.Background()
+
+	// payAsync tạo payment pending chờ webhook
+	payAsync := func(t *testing.T, f *fixture, o models.Order) *models.Payment {
+		p, err := f.svc.Pay(ctx, 7, o.ID, models.PayRequest{Token: payment.FakeTokenAsync})
+		require.NoError(t, err)
+		require.Equal(t, models.PaymentStatusPending, p.Status)
+		return p
+	}
+
+	t.Run("captured then refunded", func(t *testing.T) {
+		f := setup(t)
+		o := f.reservedOrder(t, 2, time.Minute)
+		p := payAsync(t, f, o)
+
+		payload, signature, err := f.provider.Complete(p.Reference, models.PaymentStatusCaptured, "")
+		require.NoError(t, err)
+		require.NoError(t, f.svc.HandleWebhook(ctx, payload, signature))
+		f.requireState(t, o.ID, models.OrderStatusPaid, 3)
+
+		// Cổng thanh toán gửi lại cùng sự kiện
+		require.NoError(t, f.svc.HandleWebhook(ctx, payload, signature))
+		f.requireState(t, o.ID, models.OrderStatusPaid, 3)
+
+		payload, signature, err = f.provider.Complete(p.Reference, models.PaymentStatusRefunded, "")
+		require.NoError(t, err)
+		require.NoError(t, f.svc.HandleWebhook(ctx, payload, signature))
+		f.requireState(t, o.ID, models.OrderStatusRefunded, 3)
+	})
+
+	t.Run("failed", func(t *testing.T) {
+		f := setup(t)
+		o := f.reservedOrder(t, 2, time.Minute)
+		p := payAsync(t, f, o)
+
+		payload, signature, err := f.provider.Complete(p.Reference, models.PaymentStatusFailed, "insufficient funds")
+		require.NoError(t, err)
+		require.NoError(t, f.svc.HandleWebhook(ctx, payload, signature))
+		f.requireState(t, o.ID, models.OrderStatusPending, 5)
+
+		payments, err := f.svc.GetPayments(ctx, 7, o.ID)
+		require.NoError(t, err)
+		require.Equal(t, "insufficient funds", payments[0].FailureReason)
+
+		payload, signature, err = f.provider.Complete(p.Reference, models.PaymentStatusRefunded, "")
+		require.NoError(t, err)
+		require.ErrorIs(t, f.svc.HandleWebhook(ctx, payload, signature), repositories.ErrConflict)
+	})
+
+	t.Run("capture after reservation expired is refunded", func(t *testing.T) {
+		f := setup(t)
+		o := f.reservedOrder(t, 2, time.Minute)
+		p := payAsync(t, f, o)
+		require.NoError(t, f.db.Model(&models.StockReservation{}).Where("order_id = ?", o.ID).
+			Update("expires_at", time.Now().Add(-time.Minute)).Error)
+
+		payload, signature, err := f.provider.Complete(p.Reference, models.PaymentStatusCaptured, "")
+		require.NoError(t, err)
+		require.NoError(t, f.svc.HandleWebhook(ctx, payload, signature))
+		f.requireState(t, o.ID, models.OrderStatusPending, 5)
+
+		payments, err := f.svc.GetPayments(ctx, 7, o.ID)
+		require.NoError(t, err)
+		require.Equal(t, models.PaymentStatusRefunded, payments[0].Status)
+	})
+
+	t.Run("invalid signature", func(t *testing.T) {
+		f := setup(t)
+		err := f.svc.HandleWebhook(ctx, []byte(`{"reference":"fake_x","status":"captured"}`), "bad")
+		require.ErrorIs(t, err, payment.ErrInvalidSignature)
+	})
+
+	t.Run("unknown reference", func(t *testing.T) {
+		f := setup(t)
+		payload := []byte(`{"reference":"fake_x","status":"captured"}`)
+		require.ErrorIs(t, f.svc.HandleWebhook(ctx, payload, f.provider.Sign(payload)), repositories.ErrNotFound)
+	})
+}
@@ -0,0 +1,72 @@
+package publisher
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/maithuc2003/Test_GIN_golang/internal/interfaces/repositories"
+	"github.com/maithuc2003/Test_GIN_golang/internal/models"
+	"github.com/maithuc2003/Test_GIN_golang/internal/tracing"
+)
+
+type PublisherService struct {
+	repo repositories.PublisherRepository
+}
+
+func NewPublisherService(repo repositories.PublisherRepository) *PublisherService {
+	return &PublisherService{repo: repo}
+}
+
+func (s *PublisherService) GetAllPublishers(ctx context.Context) ([]models.Publisher, error) {
+	ctx, span := tracing.Start(ctx, "PublisherService.GetAllPublishers")
+	defer span.End()
+
+	return s.repo.GetAllPublishers(ctx)
+}
+
+func (s *PublisherService) GetByPublisherID(ctx context.Context, id uint) (*models.Publisher, error) {
+	ctx, span := tracing.Start(ctx, "PublisherService.GetByPublisherID")
+	defer span.End()
+
+	if id == 0 {
+		return nil, errors.New("invalid publisher ID")
+	}
+	return s.repo.GetByPublisherID(ctx, id)
+}
+
+func (s *PublisherService) CreatePublisher(ctx context.Context, publisher *models.Publisher) error {
+	ctx, span := tracing.Start(ctx, "PublisherService.CreatePublisher")
+	defer span.End()
+
+	if publisher == nil {
+		return errors.New("publisher is nil")
+	}
+	publisher.Name = strings.TrimSpace(publisher.Name)
+	if publisher.Name == "" {
+		return errors.New("publisher name cannot be empty")
+	}
+	now := time.Now()
+	publisher.CreatedAt = now
+	publisher.UpdatedAt = now
+	return s.repo.CreatePublisher(ctx, publisher)
+}
+
+func (s *PublisherService) UpdateById(ctx context.Context, publisher *models.Publisher) (*models.Publisher, error) {
+	ctx, span := tracing.Start(ctx, "PublisherService.UpdateById")
+	defer span.End()
+
+	if publisher == nil {
+		return nil, errors.New("publisher is nil")
+	}
+	if publisher.ID == 0 {
+		return nil, errors.New("invalid publisher ID")
+	}
+	publisher.Name = strings.TrimSpace(publisher.Name)
+	if publisher.Name == "" {
+		return nil, errors.New("publisher name cannot be empty")
+	}
+	publisher.UpdatedAt = time.Now()
+	return s.repo.UpdateById(ctx, publisher)
+}
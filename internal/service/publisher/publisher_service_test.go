@@ -0,0 +1,117 @@
+package publisher_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	mocks "github.com/maithuc2003/Test_GIN_golang/internal/mocks/repositories"
+	"github.com/maithuc2003/Test_GIN_golang/internal/models"
+	"github.com/maithuc2003/Test_GIN_golang/internal/service/publisher"
+)
+
+func TestCreatePublisher(t *testing.T) {
+	tests := []struct {
+		name        string
+		input       *models.Publisher
+		mockErr     error
+		callsRepo   bool
+		expectError bool
+	}{
+		{name: "nil publisher", input: nil, expectError: true},
+		{name: "empty name", input: &models.Publisher{Name: "  "}, expectError: true},
+		{name: "repo error", input: &models.Publisher{Name: "NXB Trẻ"}, mockErr: errors.New("db error"), callsRepo: true, expectError: true},
+		{name: "success", input: &models.Publisher{Name: " NXB Trẻ "}, callsRepo: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockRepo := new(mocks.MockPublisherRepo)
+			svc := publisher.NewPublisherService(mockRepo)
+			if tt.callsRepo {
+				mockRepo.On("CreatePublisher", mock.Anything, tt.input).Return(tt.mockErr).Once()
+			}
+
+			err := svc.CreatePublisher(context.Background(), tt.input)
+			if tt.expectError {
+				require.Error(t, err)
+			} else {
+				require.NoError(t, err)
+				require.Equal(t, "NXB Trẻ", tt.input.Name)
+				require.False(t, tt.input.CreatedAt.IsZero())
+			}
+			mockRepo.AssertExpectations(t)
+		})
+	}
+}
+
+func TestGetByPublisherID(t *testing.T) {
+	tests := []struct {
+		name        string
+		id          uint
+		mockReturn  *models.Publisher
+		mockErr     error
+		expectError bool
+	}{
+		{name: "invalid ID", id: 0, expectError: true},
+		{name: "found", id: 1, mockReturn: &models.Publisher{ID: 1, Name: "NXB Trẻ"}},
+		{name: "not found", id: 2, mockErr: errors.New("publisher with ID 2 not found"), expectError: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockRepo := new(mocks.MockPublisherRepo)
+			svc := publisher.NewPublisherService(mockRepo)
+			if tt.id > 0 {
+				mockRepo.On("GetByPublisherID", mock.Anything, tt.id).Return(tt.mockReturn, tt.mockErr).Once()
+			}
+
+			got, err := svc.GetByPublisherID(context.Background(), tt.id)
+			if tt.expectError {
+				require.Error(t, err)
+				require.Nil(t, got)
+			} else {
+				require.NoError(t, err)
+				require.Equal(t, tt.mockReturn, got)
+			}
+			mockRepo.AssertExpectations(t)
+		})
+	}
+}
+
+func TestUpdatePublisher(t *testing.T) {
+	tests := []struct {
+		name        string
+		input       *models.Publisher
+		callsRepo   bool
+		expectError bool
+	}{
+		{name: "nil publisher", input: nil, expectError: true},
+		{name: "invalid ID", input: &models.Publisher{Name: "NXB Trẻ"}, expectError: true},
+		{name: "empty name", input: &models.Publisher{ID: 1, Name: ""}, expectError: true},
+		{name: "success", input: &models.Publisher{ID: 1, Name: "NXB Trẻ"}, callsRepo: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockRepo := new(mocks.MockPublisherRepo)
+			svc := publisher.NewPublisherService(mockRepo)
+			if tt.callsRepo {
+				mockRepo.On("UpdateById", mock.Anything, tt.input).Return(tt.input, nil).Once()
+			}
+
+			got, err := svc.UpdateById(context.Background(), tt.input)
+			if tt.expectError {
+				require.Error(t, err)
+				require.Nil(t, got)
+			} else {
+				require.NoError(t, err)
+				require.Equal(t, tt.input, got)
+			}
+			mockRepo.AssertExpectations(t)
+		})
+	}
+}
@@ -0,0 +1,226 @@
+package returns
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/maithuc2003/Test_GIN_golang/internal/interfaces/repositories"
+	"github.com/maithuc2003/Test_GIN_golang/internal/interfaces/service"
+	"github.com/maithuc2003/Test_GIN_golang/internal/models"
+	"github.com/maithuc2003/Test_GIN_golang/internal/money"
+	"github.com/maithuc2003/Test_GIN_golang/internal/payment"
+	orderservice "github.com/maithuc2003/Test_GIN_golang/internal/service/order"
+	"github.com/maithuc2003/Test_GIN_golang/internal/tracing"
+	"github.com/shopspring/decimal"
+)
+
+// maxReasonLength giới hạn độ dài lý do trả hàng và ghi chú duyệt
+const maxReasonLength = 1000
+
+// openStatuses là các yêu cầu đã chiếm số lượng có thể trả của đơn
+var openStatuses = []string{models.ReturnStatusRequested, models.ReturnStatusApproved}
+
+type ReturnService struct {
+	orderRepo  repositories.OrderRepositoryInterface
+	returnRepo repositories.ReturnRepository
+	txManager  repositories.TransactionManager
+	provider   payment.Provider
+}
+
+func NewReturnService(orderRepo repositories.OrderRepositoryInterface, returnRepo repositories.ReturnRepository, txManager repositories.TransactionManager, provider payment.Provider) *ReturnService {
+	return &ReturnService{orderRepo: orderRepo, returnRepo: returnRepo, txManager: txManager, provider: provider}
+}
+
+func (s *ReturnService) RequestReturn(ctx context.Context, userID, orderID uint, req models.ReturnCreateRequest) (*models.ReturnRequest, error) {
+	ctx, span := tracing.Start(ctx, "ReturnService.RequestReturn")
+	defer span.End()
+
+	if req.Quantity <= 0 {
+		return nil, fmt.Errorf("%w: quantity must be greater than zero", service.ErrInvalidInput)
+	}
+	reason := strings.TrimSpace(req.Reason)
+	if reason == "" || len(reason) > maxReasonLength {
+		return nil, fmt.Errorf("%w: reason is required and must be at most %d characters", service.ErrInvalidInput, maxReasonLength)
+	}
+	order, err := orderservice.Owned(ctx, s.orderRepo, userID, orderID)
+	if err != nil {
+		return nil, err
+	}
+	if order.Status != models.OrderStatusPaid {
+		return nil, fmt.Errorf("%w: order %d is %s, only paid orders can be returned", repositories.ErrConflict, order.ID, order.Status)
+	}
+	taken, err := s.returnRepo.ReturnedQuantity(ctx, order.ID, openStatuses...)
+	if err != nil {
+		return nil, err
+	}
+	if remaining := order.Quantity - taken; req.Quantity > remaining {
+		return nil, fmt.Errorf("%w: only %d of %d copies can still be returned", service.ErrInvalidInput, remaining, order.Quantity)
+	}
+
+	ret := &models.ReturnRequest{
+		OrderID:  order.ID,
+		UserID:   userID,
+		Quantity: req.Quantity,
+		Reason:   reason,
+		Status:   models.ReturnStatusRequested,
+	}
+	if err := s.returnRepo.Create(ctx, ret); err != nil {
+		return nil, err
+	}
+	return ret, nil
+}
+
+func (s *ReturnService) GetOrderReturns(ctx context.Context, userID, orderID uint) ([]models.ReturnRequest, error) {
+	ctx, span := tracing.Start(ctx, "ReturnService.GetOrderReturns")
+	defer span.End()
+
+	if _, err := orderservice.Owned(ctx, s.orderRepo, userID, orderID); err != nil {
+		return nil, err
+	}
+	return s.ListReturns(ctx, models.ReturnFilter{OrderID: orderID})
+}
+
+func (s *ReturnService) ListReturns(ctx context.Context, filter models.ReturnFilter) ([]models.ReturnRequest, error) {
+	ctx, span := tracing.Start(ctx, "ReturnService.ListReturns")
+	defer span.End()
+
+	returns, err := s.returnRepo.List(ctx, filter)
+	if err != nil {
+		return nil, err
+	}
+	if returns == nil {
+		returns = []models.ReturnRequest{}
+	}
+	return returns, nil
+}
+
+// Approve chạy trong một transaction: khóa đơn để các lần duyệt song song trên cùng đơn
+// chạy lần lượt, tính số lượng đã trả và số tiền hoàn trong transaction, ghi kết quả
+// duyệt, cộng lại tồn kho, cộng số tiền đã hoàn của payment (và chuyển trạng thái đơn khi
+// trả hết) rồi mới gọi cổng thanh toán. Cổng thanh toán lỗi thì rollback toàn bộ và yêu
+// cầu vẫn ở trạng thái requested.
+func (s *ReturnService) Approve(ctx context.Context, reviewerID, returnID uint, req models.ReturnReviewRequest) (*models.ReturnRequest, error) {
+	ctx, span := tracing.Start(ctx, "ReturnService.Approve")
+	defer span.End()
+
+	ret, err := s.reviewable(ctx, returnID, req)
+	if err != nil {
+		return nil, err
+	}
+
+	err = s.txManager.WithinTransaction(ctx, func(ctx context.Context, repos repositories.Repositories) error {
+		order, err := repos.Orders.GetForUpdate(ctx, ret.OrderID)
+		if err != nil {
+			return err
+		}
+		if order.Status != models.OrderStatusPaid {
+			return fmt.Errorf("%w: order %d is %s", repositories.ErrConflict, order.ID, order.Status)
+		}
+		captured, err := capturedPayment(ctx, repos.Payments, order.ID)
+		if err != nil {
+			return err
+		}
+		returned, err := repos.Returns.ReturnedQuantity(ctx, order.ID, models.ReturnStatusApproved)
+		if err != nil {
+			return err
+		}
+		if returned+ret.Quantity > order.Quantity {
+			return fmt.Errorf("%w: return exceeds the ordered quantity", repositories.ErrConflict)
+		}
+
+		fullyReturned := returned+ret.Quantity == order.Quantity
+		amount := refundAmount(captured, order.Quantity, ret.Quantity, fullyReturned)
+
+		now := time.Now()
+		ret.Status = models.ReturnStatusApproved
+		ret.RefundAmount = &amount
+		ret.ReviewedBy = &reviewerID
+		ret.ReviewNote = strings.TrimSpace(req.Note)
+		ret.ReviewedAt = &now
+		if err := repos.Returns.SaveReview(ctx, ret); err != nil {
+			return err
+		}
+		if err := repos.Books.Restock(ctx, int(order.BookID), ret.Quantity); err != nil {
+			return err
+		}
+		if _, err := repos.Payments.AddRefund(ctx, captured.ID, amount); err != nil {
+			return err
+		}
+		if fullyReturned {
+			if err := repos.Orders.UpdateStatus(ctx, order.ID, models.OrderStatusPaid, models.OrderStatusRefunded); err != nil {
+				return err
+			}
+		}
+		if amount.IsPositive() {
+			if _, err := s.provider.Refund(ctx, captured.Reference, amount); err != nil {
+				return fmt.Errorf("refund failed: %w", err)
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return ret, nil
+}
+
+func (s *ReturnService) Reject(ctx context.Context, reviewerID, returnID uint, req models.ReturnReviewRequest) (*models.ReturnRequest, error) {
+	ctx, span := tracing.Start(ctx, "ReturnService.Reject")
+	defer span.End()
+
+	ret, err := s.reviewable(ctx, returnID, req)
+	if err != nil {
+		return nil, err
+	}
+	now := time.Now()
+	ret.Status = models.ReturnStatusRejected
+	ret.ReviewedBy = &reviewerID
+	ret.ReviewNote = strings.TrimSpace(req.Note)
+	ret.ReviewedAt = &now
+	if err := s.returnRepo.SaveReview(ctx, ret); err != nil {
+		return nil, err
+	}
+	return ret, nil
+}
+
+func (s *ReturnService) reviewable(ctx context.Context, returnID uint, req models.ReturnReviewRequest) (*models.ReturnRequest, error) {
+	if returnID == 0 {
+		return nil, fmt.Errorf("%w: invalid return ID", service.ErrInvalidInput)
+	}
+	if len(req.Note) > maxReasonLength {
+		return nil, fmt.Errorf("%w: note must be at most %d characters", service.ErrInvalidInput, maxReasonLength)
+	}
+	ret, err := s.returnRepo.GetByID(ctx, returnID)
+	if err != nil {
+		return nil, err
+	}
+	if ret.Status != models.ReturnStatusRequested {
+		return nil, fmt.Errorf("%w: return request %d is already %s", repositories.ErrConflict, ret.ID, ret.Status)
+	}
+	return ret, nil
+}
+
+func capturedPayment(ctx context.Context, payments repositories.PaymentRepository, orderID uint) (*models.Payment, error) {
+	list, err := payments.ListByOrder(ctx, orderID)
+	if err != nil {
+		return nil, err
+	}
+	for i := range list {
+		if list[i].Status == models.PaymentStatusCaptured {
+			return &list[i], nil
+		}
+	}
+	return nil, fmt.Errorf("%w: order %d has no captured payment to refund", repositories.ErrConflict, orderID)
+}
+
+// refundAmount hoàn theo tỉ lệ số lượng trả, làm tròn theo tiền tệ của payment; lần trả
+// cuối hoàn phần còn lại để tổng các lần hoàn luôn đúng bằng số tiền đã thu dù có làm tròn
+func refundAmount(p *models.Payment, ordered, returned int, last bool) decimal.Decimal {
+	if last {
+		return p.Amount.Sub(p.RefundedAmount)
+	}
+	return money.Round(p.Amount.Mul(decimal.NewFromInt(int64(returned))).
+		Div(decimal.NewFromInt(int64(ordered))), p.Currency)
+}
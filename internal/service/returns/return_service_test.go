@@ -0,0 +1,181 @@
+package returns_test
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+
+	"github.com/maithuc2003/Test_GIN_golang/internal/interfaces/repositories"
+	"github.com/maithuc2003/Test_GIN_golang/internal/interfaces/service"
+	"github.com/maithuc2003/Test_GIN_golang/internal/models"
+	"github.com/maithuc2003/Test_GIN_golang/internal/payment"
+	"github.com/maithuc2003/Test_GIN_golang/internal/repositories/order"
+	returnrepo "github.com/maithuc2003/Test_GIN_golang/internal/repositories/returns"
+	"github.com/maithuc2003/Test_GIN_golang/internal/repositories/transaction"
+	"github.com/maithuc2003/Test_GIN_golang/internal/service/returns"
+	sqlitedriver "gorm.io/driver/sqlite"
+
+	_ "modernc.org/sqlite"
+)
+
+type fixture struct {
+	db       *gorm.DB
+	provider *payment.FakeProvider
+	svc      *returns.ReturnService
+	book     models.Book
+	order    models.Order
+	payment  models.Payment
+}
+
+// setup tạo đơn 3 cuốn đã thanh toán 31.00 USD (tồn kho còn 2) của user 7
+func setup(t *testing.T) *fixture {
+	ctx := context.Background()
+	dsn := fmt.Sprintf("file:testdb_%d?mode=memory&cache=shared", time.Now().UnixNano())
+	db, err := gorm.Open(sqlitedriver.New(sqlitedriver.Config{DSN: dsn, DriverName: "sqlite"}), &gorm.Config{})
+	require.NoError(t, err)
+	require.NoError(t, db.AutoMigrate(&models.Author{}, &models.Book{}, &models.Order{}, &models.OrderDiscount{}, &models.StockReservation{}, &models.Payment{}, &models.ReturnRequest{}, &models.OutboxEvent{}))
+
+	f := &fixture{db: db, provider: payment.NewFakeProvider("secret")}
+	f.book = models.Book{Title: "Dune", AuthorID: 1, Stock: 2}
+	require.NoError(t, db.Create(&f.book).Error)
+	f.order = models.Order{BookID: f.book.ID, UserID: 7, Quantity: 3, Status: models.OrderStatusPaid}
+	require.NoError(t, db.Create(&f.order).Error)
+
+	// Số tiền không chia hết cho 3 để kiểm tra làm tròn
+	amount := decimal.RequireFromString("31.00")
+	auth, err := f.provider.Authorize(ctx, payment.AuthorizeRequest{OrderID: f.order.ID, Amount: amount, Currency: "USD"})
+	require.NoError(t, err)
+	_, err = f.provider.Capture(ctx, auth.Reference, amount)
+	require.NoError(t, err)
+	f.payment = models.Payment{OrderID: f.order.ID, Provider: f.provider.Name(), Reference: auth.Reference, Status: models.PaymentStatusCaptured, Amount: amount, Currency: "USD"}
+	require.NoError(t, db.Create(&f.payment).Error)
+
+	f.svc = returns.NewReturnService(order.NewOrderRepo(db), returnrepo.NewReturnRepo(db), transaction.NewTransactionManager(db), f.provider)
+	return f
+}
+
+func (f *fixture) request(t *testing.T, quantity int) *models.ReturnRequest {
+	ret, err := f.svc.RequestReturn(context.Background(), 7, f.order.ID, models.ReturnCreateRequest{Quantity: quantity, Reason: "damaged"})
+	require.NoError(t, err)
+	return ret
+}
+
+func TestRequestReturn(t *testing.T) {
+	ctx := context.Background()
+	f := setup(t)
+	f.request(t, 2)
+
+	tests := []struct {
+		name      string
+		userID    uint
+		req       models.ReturnCreateRequest
+		wantErrIs error
+	}{
+		{name: "more than remaining", userID: 7, req: models.ReturnCreateRequest{Quantity: 2, Reason: "damaged"}, wantErrIs: service.ErrInvalidInput},
+		{name: "missing reason", userID: 7, req: models.ReturnCreateRequest{Quantity: 1}, wantErrIs: service.ErrInvalidInput},
+		{name: "zero quantity", userID: 7, req: models.ReturnCreateRequest{Reason: "damaged"}, wantErrIs: service.ErrInvalidInput},
+		{name: "other user's order", userID: 8, req: models.ReturnCreateRequest{Quantity: 1, Reason: "damaged"}, wantErrIs: repositories.ErrNotFound},
+		{name: "remaining copy", userID: 7, req: models.ReturnCreateRequest{Quantity: 1, Reason: "damaged"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ret, err := f.svc.RequestReturn(ctx, tt.userID, f.order.ID, tt.req)
+			if tt.wantErrIs != nil {
+				require.ErrorIs(t, err, tt.wantErrIs)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, models.ReturnStatusRequested, ret.Status)
+		})
+	}
+
+	t.Run("unpaid order", func(t *testing.T) {
+		pending := models.Order{BookID: f.book.ID, UserID: 7, Quantity: 1, Status: models.OrderStatusPending}
+		require.NoError(t, f.db.Create(&pending).Error)
+		_, err := f.svc.RequestReturn(ctx, 7, pending.ID, models.ReturnCreateRequest{Quantity: 1, Reason: "damaged"})
+		require.ErrorIs(t, err, repositories.ErrConflict)
+	})
+}
+
+func TestApprove(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("partial then full return", func(t *testing.T) {
+		f := setup(t)
+		first := f.request(t, 1)
+		second := f.request(t, 2)
+
+		approved, err := f.svc.Approve(ctx, 1, first.ID, models.ReturnReviewRequest{Note: "ok"})
+		require.NoError(t, err)
+		require.Equal(t, "10.33", approved.RefundAmount.StringFixed(2))
+		f.requireState(t, models.OrderStatusPaid, models.PaymentStatusCaptured, "10.33", 3)
+
+		approved, err = f.svc.Approve(ctx, 1, second.ID, models.ReturnReviewRequest{})
+		require.NoError(t, err)
+		require.Equal(t, "20.67", approved.RefundAmount.StringFixed(2))
+		f.requireState(t, models.OrderStatusRefunded, models.PaymentStatusRefunded, "31.00", 5)
+
+		_, err = f.svc.Approve(ctx, 1, second.ID, models.ReturnReviewRequest{})
+		require.ErrorIs(t, err, repositories.ErrConflict, "already approved")
+	})
+
+	t.Run("refund rounds to currency minor units", func(t *testing.T) {
+		f := setup(t)
+		ret := f.request(t, 1)
+		// JPY không có số lẻ nên 31/3 làm tròn thành 10
+		require.NoError(t, f.db.Model(&f.payment).Update("currency", "JPY").Error)
+
+		approved, err := f.svc.Approve(ctx, 1, ret.ID, models.ReturnReviewRequest{})
+		require.NoError(t, err)
+		require.Equal(t, "10", approved.RefundAmount.String())
+	})
+
+	t.Run("provider failure rolls back", func(t *testing.T) {
+		f := setup(t)
+		ret := f.request(t, 1)
+		// Cổng thanh toán không biết giao dịch nên Refund lỗi
+		require.NoError(t, f.db.Model(&f.payment).Update("reference", "fake_unknown").Error)
+
+		_, err := f.svc.Approve(ctx, 1, ret.ID, models.ReturnReviewRequest{})
+		require.ErrorIs(t, err, payment.ErrUnknownReference)
+		f.requireState(t, models.OrderStatusPaid, models.PaymentStatusCaptured, "0", 2)
+
+		list, err := f.svc.GetOrderReturns(ctx, 7, f.order.ID)
+		require.NoError(t, err)
+		require.Equal(t, models.ReturnStatusRequested, list[0].Status)
+	})
+
+	t.Run("reject", func(t *testing.T) {
+		f := setup(t)
+		ret := f.request(t, 3)
+
+		rejected, err := f.svc.Reject(ctx, 1, ret.ID, models.ReturnReviewRequest{Note: "used"})
+		require.NoError(t, err)
+		require.Equal(t, models.ReturnStatusRejected, rejected.Status)
+		f.requireState(t, models.OrderStatusPaid, models.PaymentStatusCaptured, "0", 2)
+
+		// Số lượng bị từ chối có thể được yêu cầu trả lại
+		f.request(t, 3)
+	})
+}
+
+func (f *fixture) requireState(t *testing.T, orderStatus, paymentStatus, refunded string, stock int) {
+	var o models.Order
+	require.NoError(t, f.db.First(&o, f.order.ID).Error)
+	require.Equal(t, orderStatus, o.Status)
+
+	var p models.Payment
+	require.NoError(t, f.db.First(&p, f.payment.ID).Error)
+	require.Equal(t, paymentStatus, p.Status)
+	require.True(t, p.RefundedAmount.Equal(decimal.RequireFromString(refunded)), "refunded %s", p.RefundedAmount)
+
+	var b models.Book
+	require.NoError(t, f.db.First(&b, f.book.ID).Error)
+	require.Equal(t, stock, b.Stock)
+}
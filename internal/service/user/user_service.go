@@ -1,28 +1,59 @@
 package user
 
 import (
+	"context"
 	"errors"
+	"log/slog"
+	"time"
 
 	"github.com/maithuc2003/Test_GIN_golang/internal/interfaces/repositories"
+	"github.com/maithuc2003/Test_GIN_golang/internal/interfaces/service"
+	"github.com/maithuc2003/Test_GIN_golang/internal/metrics"
 	"github.com/maithuc2003/Test_GIN_golang/internal/models"
+	"github.com/maithuc2003/Test_GIN_golang/internal/tracing"
 	"golang.org/x/crypto/bcrypt"
 )
 
+// Lockout là chính sách khoá tài khoản sau nhiều lần đăng nhập sai liên tiếp. Khi số
+// lần sai đạt Threshold tài khoản bị khoá trong Base, mỗi lần sai tiếp theo thời gian
+// khoá gấp đôi nhưng không quá Max. Threshold 0 tắt tính năng khoá.
+type Lockout struct {
+	Threshold int
+	Base      time.Duration
+	Max       time.Duration
+}
+
+// Duration trả về thời gian khoá sau failures lần sai liên tiếp, 0 nếu chưa phải khoá
+func (l Lockout) Duration(failures int) time.Duration {
+	if l.Threshold <= 0 || failures < l.Threshold {
+		return 0
+	}
+	d := l.Base
+	for i := l.Threshold; i < failures && d < l.Max; i++ {
+		d *= 2
+	}
+	return min(d, l.Max)
+}
+
 type UserService struct {
 	userRepo repositories.UserRepository
+	lockout  Lockout
+	now      func() time.Time
 }
 
-func NewUserService(userRepo repositories.UserRepository) *UserService {
-	return &UserService{userRepo: userRepo}
+func NewUserService(userRepo repositories.UserRepository, lockout Lockout) *UserService {
+	return &UserService{userRepo: userRepo, lockout: lockout, now: time.Now}
 }
 
-func (r *UserService) GetByUsername(username string) (*models.User, error) {
+func (r *UserService) GetByUsername(ctx context.Context, username string) (*models.User, error) {
+	ctx, span := tracing.Start(ctx, "UserService.GetByUsername")
+	defer span.End()
 
 	if username == "" {
 		return nil, errors.New("username cannot be empty")
 	}
 
-	user, err := r.userRepo.GetByUsername(username)
+	user, err := r.userRepo.GetByUsername(ctx, username)
 	if err != nil {
 		return nil, errors.New("invalid username or password")
 	}
@@ -30,11 +61,19 @@ func (r *UserService) GetByUsername(username string) (*models.User, error) {
 	return user, nil
 }
 
-func (s *UserService) LoginUser(username, password string) (*models.User, error) {
-	user, err := s.userRepo.GetByUsername(username)
+func (s *UserService) LoginUser(ctx context.Context, username, password string) (*models.User, error) {
+	ctx, span := tracing.Start(ctx, "UserService.LoginUser")
+	defer span.End()
+
+	user, err := s.userRepo.GetByUsername(ctx, username)
 	if err != nil {
+		metrics.LoginsFailed.WithLabelValues(metrics.LoginUnknownUser).Inc()
 		return nil, errors.New("invalid username or password")
 	}
+	if user.LockedUntil != nil && s.now().Before(*user.LockedUntil) {
+		metrics.LoginsFailed.WithLabelValues(metrics.LoginLocked).Inc()
+		return nil, &service.AccountLockedError{Until: *user.LockedUntil}
+	}
 	// hash, _ := bcrypt.GenerateFromPassword([]byte(password), bcrypt.DefaultCost)
 	// fmt.Println(string(hash)) // → chuỗi mã hóa kiểu: $2a$10$...
 
@@ -42,8 +81,39 @@ func (s *UserService) LoginUser(username, password string) (*models.User, error)
 	err = bcrypt.CompareHashAndPassword([]byte(user.Password), []byte(password))
 
 	if err != nil {
-		return nil, errors.New("invalid username or password")
+		metrics.LoginsFailed.WithLabelValues(metrics.LoginBadPassword).Inc()
+		return nil, s.recordFailure(ctx, user)
 	}
 
+	if user.FailedLogins > 0 || user.LockedUntil != nil {
+		if err := s.userRepo.ResetFailedLogins(ctx, user.ID); err != nil {
+			return nil, err
+		}
+	}
 	return user, nil
 }
+
+// recordFailure tăng số lần đăng nhập sai và khoá tài khoản khi đạt ngưỡng. Lỗi trả về
+// là lỗi đăng nhập cho người dùng.
+func (s *UserService) recordFailure(ctx context.Context, user *models.User) error {
+	invalid := errors.New("invalid username or password")
+	if s.lockout.Threshold <= 0 {
+		return invalid
+	}
+	failures, err := s.userRepo.RecordFailedLogin(ctx, user.ID)
+	if err != nil {
+		slog.ErrorContext(ctx, "failed to record failed login", "user_id", user.ID, "error", err)
+		return invalid
+	}
+	d := s.lockout.Duration(failures)
+	if d == 0 {
+		return invalid
+	}
+	until := s.now().Add(d)
+	if err := s.userRepo.LockUntil(ctx, user.ID, until); err != nil {
+		slog.ErrorContext(ctx, "failed to lock account", "user_id", user.ID, "error", err)
+		return invalid
+	}
+	slog.WarnContext(ctx, "account locked after failed logins", "user_id", user.ID, "failures", failures, "until", until)
+	return &service.AccountLockedError{Until: until}
+}
@@ -1,13 +1,17 @@
 package user_test
 
 import (
+	"context"
 	"errors"
 	"testing"
+	"time"
 
+	"github.com/maithuc2003/Test_GIN_golang/internal/interfaces/service"
 	mocks "github.com/maithuc2003/Test_GIN_golang/internal/mocks/repositories"
 	"github.com/maithuc2003/Test_GIN_golang/internal/models"
 	"github.com/maithuc2003/Test_GIN_golang/internal/service/user"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
 	"golang.org/x/crypto/bcrypt"
 )
 
@@ -57,12 +61,12 @@ func TestGetByUsername(t *testing.T) {
 
 			// Setup mock if username is not empty
 			if tt.username != "" {
-				mockRepo.On("GetByUsername", tt.username).
+				mockRepo.On("GetByUsername", mock.Anything, tt.username).
 					Return(tt.mockReturnUser, tt.mockReturnErr)
 			}
 
-			service := user.NewUserService(mockRepo)
-			actualUser, err := service.GetByUsername(tt.username)
+			service := user.NewUserService(mockRepo, user.Lockout{})
+			actualUser, err := service.GetByUsername(context.Background(), tt.username)
 
 			if tt.expectedErr != "" {
 				assert.Nil(t, actualUser)
@@ -129,11 +133,11 @@ func TestLoginUser(t *testing.T) {
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			mockRepo := new(mocks.MockUserRepo)
-			mockRepo.On("GetByUsername", tt.username).Return(tt.mockUser, tt.mockError)
+			mockRepo.On("GetByUsername", mock.Anything, tt.username).Return(tt.mockUser, tt.mockError)
 
-			service := user.NewUserService(mockRepo)
+			service := user.NewUserService(mockRepo, user.Lockout{})
 
-			result, err := service.LoginUser(tt.username, tt.password)
+			result, err := service.LoginUser(context.Background(), tt.username, tt.password)
 
 			if tt.expectedError {
 				assert.Error(t, err)
@@ -147,3 +151,110 @@ func TestLoginUser(t *testing.T) {
 		})
 	}
 }
+
+func TestLockoutDuration(t *testing.T) {
+	l := user.Lockout{Threshold: 3, Base: time.Minute, Max: 10 * time.Minute}
+	tests := []struct {
+		failures int
+		want     time.Duration
+	}{
+		{failures: 1, want: 0},
+		{failures: 2, want: 0},
+		{failures: 3, want: time.Minute},
+		{failures: 4, want: 2 * time.Minute},
+		{failures: 6, want: 8 * time.Minute},
+		{failures: 7, want: 10 * time.Minute},
+		{failures: 100, want: 10 * time.Minute},
+	}
+	for _, tt := range tests {
+		assert.Equal(t, tt.want, l.Duration(tt.failures), "failures=%d", tt.failures)
+	}
+	assert.Zero(t, user.Lockout{}.Duration(100), "threshold 0 disables lockout")
+}
+
+func TestLoginUser_Lockout(t *testing.T) {
+	hashedPassword, _ := bcrypt.GenerateFromPassword([]byte("correct_password"), bcrypt.MinCost)
+	lockout := user.Lockout{Threshold: 3, Base: time.Minute, Max: time.Hour}
+	future := time.Now().Add(time.Hour)
+	past := time.Now().Add(-time.Minute)
+
+	tests := []struct {
+		name       string
+		password   string
+		user       models.User
+		setup      func(m *mocks.MockUserRepo)
+		wantLocked bool
+		wantErr    bool
+	}{
+		{
+			name:     "below threshold",
+			password: "wrong_password",
+			setup: func(m *mocks.MockUserRepo) {
+				m.On("RecordFailedLogin", mock.Anything, uint(1)).Return(2, nil)
+			},
+			wantErr: true,
+		},
+		{
+			name:     "threshold reached locks account",
+			password: "wrong_password",
+			setup: func(m *mocks.MockUserRepo) {
+				m.On("RecordFailedLogin", mock.Anything, uint(1)).Return(3, nil)
+				m.On("LockUntil", mock.Anything, uint(1), mock.MatchedBy(func(until time.Time) bool {
+					return until.After(time.Now().Add(50 * time.Second))
+				})).Return(nil)
+			},
+			wantLocked: true,
+		},
+		{
+			name:     "record failure error still rejects login",
+			password: "wrong_password",
+			setup: func(m *mocks.MockUserRepo) {
+				m.On("RecordFailedLogin", mock.Anything, uint(1)).Return(0, errors.New("db down"))
+			},
+			wantErr: true,
+		},
+		{
+			name:       "locked account rejects correct password",
+			password:   "correct_password",
+			user:       models.User{LockedUntil: &future, FailedLogins: 3},
+			wantLocked: true,
+		},
+		{
+			name:     "expired lock resets counters on success",
+			password: "correct_password",
+			user:     models.User{LockedUntil: &past, FailedLogins: 3},
+			setup: func(m *mocks.MockUserRepo) {
+				m.On("ResetFailedLogins", mock.Anything, uint(1)).Return(nil)
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockRepo := new(mocks.MockUserRepo)
+			u := tt.user
+			u.ID, u.Username, u.Password = 1, "john", string(hashedPassword)
+			mockRepo.On("GetByUsername", mock.Anything, "john").Return(&u, nil)
+			if tt.setup != nil {
+				tt.setup(mockRepo)
+			}
+
+			result, err := user.NewUserService(mockRepo, lockout).LoginUser(context.Background(), "john", tt.password)
+
+			switch {
+			case tt.wantLocked:
+				var locked *service.AccountLockedError
+				assert.ErrorAs(t, err, &locked)
+				assert.ErrorIs(t, err, service.ErrAccountLocked)
+				assert.Nil(t, result)
+			case tt.wantErr:
+				assert.EqualError(t, err, "invalid username or password")
+				assert.Nil(t, result)
+			default:
+				assert.NoError(t, err)
+				assert.Equal(t, uint(1), result.ID)
+			}
+			mockRepo.AssertExpectations(t)
+		})
+	}
+}
@@ -0,0 +1,182 @@
+package webhook
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/maithuc2003/Test_GIN_golang/internal/events"
+	"github.com/maithuc2003/Test_GIN_golang/internal/interfaces/repositories"
+	"github.com/maithuc2003/Test_GIN_golang/internal/interfaces/service"
+	"github.com/maithuc2003/Test_GIN_golang/internal/models"
+	"github.com/maithuc2003/Test_GIN_golang/internal/tracing"
+	"github.com/maithuc2003/Test_GIN_golang/internal/webhook"
+)
+
+const (
+	// deliveryLogLimit là số delivery gần nhất trả về trong log của một subscription
+	deliveryLogLimit = 100
+	minSecretLength  = 16
+	maxSecretLength  = 128
+	maxURLLength     = 2048
+)
+
+type WebhookService struct {
+	repo repositories.WebhookRepository
+}
+
+func NewWebhookService(repo repositories.WebhookRepository) *WebhookService {
+	return &WebhookService{repo: repo}
+}
+
+func (s *WebhookService) GetAllSubscriptions(ctx context.Context) ([]models.WebhookSubscription, error) {
+	ctx, span := tracing.Start(ctx, "WebhookService.GetAllSubscriptions")
+	defer span.End()
+
+	subs, err := s.repo.ListSubscriptions(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if subs == nil {
+		subs = []models.WebhookSubscription{}
+	}
+	for i := range subs {
+		subs[i].Secret = ""
+	}
+	return subs, nil
+}
+
+func (s *WebhookService) GetBySubscriptionID(ctx context.Context, id uint) (*models.WebhookSubscription, error) {
+	ctx, span := tracing.Start(ctx, "WebhookService.GetBySubscriptionID")
+	defer span.End()
+
+	if id == 0 {
+		return nil, fmt.Errorf("%w: invalid webhook subscription ID", service.ErrInvalidInput)
+	}
+	sub, err := s.repo.GetSubscription(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	sub.Secret = ""
+	return sub, nil
+}
+
+func (s *WebhookService) CreateSubscription(ctx context.Context, sub *models.WebhookSubscription) error {
+	ctx, span := tracing.Start(ctx, "WebhookService.CreateSubscription")
+	defer span.End()
+
+	if err := validateSubscription(sub); err != nil {
+		return err
+	}
+	if sub.Secret == "" {
+		secret, err := webhook.NewSecret()
+		if err != nil {
+			return fmt.Errorf("failed to generate webhook secret: %w", err)
+		}
+		sub.Secret = secret
+	}
+	now := time.Now()
+	sub.ID = 0
+	sub.CreatedAt = now
+	sub.UpdatedAt = now
+	return s.repo.CreateSubscription(ctx, sub)
+}
+
+func (s *WebhookService) UpdateById(ctx context.Context, sub *models.WebhookSubscription) (*models.WebhookSubscription, error) {
+	ctx, span := tracing.Start(ctx, "WebhookService.UpdateById")
+	defer span.End()
+
+	if sub != nil && sub.ID == 0 {
+		return nil, fmt.Errorf("%w: invalid webhook subscription ID", service.ErrInvalidInput)
+	}
+	if err := validateSubscription(sub); err != nil {
+		return nil, err
+	}
+	sub.UpdatedAt = time.Now()
+	updated, err := s.repo.UpdateSubscription(ctx, sub)
+	if err != nil {
+		return nil, err
+	}
+	// Chỉ trả lại secret khi người gọi vừa đổi nó
+	if sub.Secret == "" {
+		updated.Secret = ""
+	}
+	return updated, nil
+}
+
+func (s *WebhookService) DeleteById(ctx context.Context, id uint) (*models.WebhookSubscription, error) {
+	ctx, span := tracing.Start(ctx, "WebhookService.DeleteById")
+	defer span.End()
+
+	if id == 0 {
+		return nil, fmt.Errorf("%w: invalid webhook subscription ID", service.ErrInvalidInput)
+	}
+	sub, err := s.repo.DeleteSubscription(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	sub.Secret = ""
+	return sub, nil
+}
+
+func (s *WebhookService) GetDeliveries(ctx context.Context, subscriptionID uint) ([]models.WebhookDelivery, error) {
+	ctx, span := tracing.Start(ctx, "WebhookService.GetDeliveries")
+	defer span.End()
+
+	if subscriptionID == 0 {
+		return nil, fmt.Errorf("%w: invalid webhook subscription ID", service.ErrInvalidInput)
+	}
+	deliveries, err := s.repo.ListDeliveries(ctx, subscriptionID, deliveryLogLimit)
+	if err != nil {
+		return nil, err
+	}
+	if deliveries == nil {
+		deliveries = []models.WebhookDelivery{}
+	}
+	return deliveries, nil
+}
+
+func (s *WebhookService) Redeliver(ctx context.Context, subscriptionID, deliveryID uint) (*models.WebhookDelivery, error) {
+	ctx, span := tracing.Start(ctx, "WebhookService.Redeliver")
+	defer span.End()
+
+	if subscriptionID == 0 || deliveryID == 0 {
+		return nil, fmt.Errorf("%w: invalid webhook subscription or delivery ID", service.ErrInvalidInput)
+	}
+	return s.repo.Redeliver(ctx, subscriptionID, deliveryID, time.Now())
+}
+
+// validateSubscription kiểm tra URL, loại event và secret; loại event trùng được gộp lại
+func validateSubscription(sub *models.WebhookSubscription) error {
+	if sub == nil {
+		return fmt.Errorf("%w: webhook subscription is nil", service.ErrInvalidInput)
+	}
+	sub.URL = strings.TrimSpace(sub.URL)
+	u, err := url.Parse(sub.URL)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" || len(sub.URL) > maxURLLength {
+		return fmt.Errorf("%w: url must be an absolute http or https URL", service.ErrInvalidInput)
+	}
+
+	if len(sub.EventTypes) == 0 {
+		return fmt.Errorf("%w: event_types must not be empty", service.ErrInvalidInput)
+	}
+	types := make([]string, 0, len(sub.EventTypes))
+	for _, t := range sub.EventTypes {
+		if !slices.Contains(events.Types, t) {
+			return fmt.Errorf("%w: unknown event type %q, expected one of %s", service.ErrInvalidInput, t, strings.Join(events.Types, ", "))
+		}
+		if !slices.Contains(types, t) {
+			types = append(types, t)
+		}
+	}
+	sub.EventTypes = types
+
+	if sub.Secret != "" && (len(sub.Secret) < minSecretLength || len(sub.Secret) > maxSecretLength) {
+		return fmt.Errorf("%w: secret must be %d-%d characters", service.ErrInvalidInput, minSecretLength, maxSecretLength)
+	}
+	sub.Description = strings.TrimSpace(sub.Description)
+	return nil
+}
@@ -0,0 +1,116 @@
+package webhook_test
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/maithuc2003/Test_GIN_golang/internal/events"
+	"github.com/maithuc2003/Test_GIN_golang/internal/interfaces/service"
+	mocks "github.com/maithuc2003/Test_GIN_golang/internal/mocks/repositories"
+	"github.com/maithuc2003/Test_GIN_golang/internal/models"
+	"github.com/maithuc2003/Test_GIN_golang/internal/service/webhook"
+)
+
+func TestCreateSubscription(t *testing.T) {
+	tests := []struct {
+		name        string
+		input       *models.WebhookSubscription
+		expectError bool
+		wantTypes   []string
+		wantSecret  string
+	}{
+		{name: "nil subscription", input: nil, expectError: true},
+		{
+			name:      "generates secret and dedupes types",
+			input:     &models.WebhookSubscription{URL: " https://partner.example/hooks ", EventTypes: []string{events.TypeOrderCreated, events.TypeOrderCreated}},
+			wantTypes: []string{events.TypeOrderCreated},
+		},
+		{
+			name:       "keeps given secret",
+			input:      &models.WebhookSubscription{URL: "http://localhost:9000/in", EventTypes: []string{events.TypeStockChanged}, Secret: "a-long-enough-secret"},
+			wantTypes:  []string{events.TypeStockChanged},
+			wantSecret: "a-long-enough-secret",
+		},
+		{name: "relative url", input: &models.WebhookSubscription{URL: "/hooks", EventTypes: []string{events.TypeOrderCreated}}, expectError: true},
+		{name: "ftp url", input: &models.WebhookSubscription{URL: "ftp://partner.example", EventTypes: []string{events.TypeOrderCreated}}, expectError: true},
+		{name: "no event types", input: &models.WebhookSubscription{URL: "https://partner.example"}, expectError: true},
+		{name: "unknown event type", input: &models.WebhookSubscription{URL: "https://partner.example", EventTypes: []string{"OrderShipped"}}, expectError: true},
+		{name: "short secret", input: &models.WebhookSubscription{URL: "https://partner.example", EventTypes: []string{events.TypeOrderCreated}, Secret: "short"}, expectError: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockRepo := new(mocks.MockWebhookRepo)
+			s := webhook.NewWebhookService(mockRepo)
+			if !tt.expectError {
+				mockRepo.On("CreateSubscription", mock.Anything, tt.input).Return(nil).Once()
+			}
+
+			err := s.CreateSubscription(context.Background(), tt.input)
+			if tt.expectError {
+				require.ErrorIs(t, err, service.ErrInvalidInput)
+				mockRepo.AssertNotCalled(t, "CreateSubscription", mock.Anything, mock.Anything)
+				return
+			}
+			require.NoError(t, err)
+			require.False(t, strings.HasPrefix(tt.input.URL, " "))
+			require.Equal(t, tt.wantTypes, tt.input.EventTypes)
+			if tt.wantSecret != "" {
+				require.Equal(t, tt.wantSecret, tt.input.Secret)
+			} else {
+				require.True(t, strings.HasPrefix(tt.input.Secret, "whsec_"))
+			}
+			mockRepo.AssertExpectations(t)
+		})
+	}
+}
+
+func TestSecretIsHidden(t *testing.T) {
+	ctx := context.Background()
+	mockRepo := new(mocks.MockWebhookRepo)
+	s := webhook.NewWebhookService(mockRepo)
+	stored := func() *models.WebhookSubscription {
+		return &models.WebhookSubscription{ID: 1, URL: "https://partner.example", EventTypes: []string{events.TypeOrderCreated}, Secret: "stored-secret-value"}
+	}
+	mockRepo.On("ListSubscriptions", mock.Anything).Return([]models.WebhookSubscription{*stored()}, nil)
+	mockRepo.On("GetSubscription", mock.Anything, uint(1)).Return(stored(), nil)
+	mockRepo.On("UpdateSubscription", mock.Anything, mock.Anything).Return(stored(), nil).Once()
+	mockRepo.On("UpdateSubscription", mock.Anything, mock.Anything).Return(stored(), nil).Once()
+
+	subs, err := s.GetAllSubscriptions(ctx)
+	require.NoError(t, err)
+	require.Empty(t, subs[0].Secret)
+
+	sub, err := s.GetBySubscriptionID(ctx, 1)
+	require.NoError(t, err)
+	require.Empty(t, sub.Secret)
+
+	sub, err = s.UpdateById(ctx, &models.WebhookSubscription{ID: 1, URL: "https://partner.example", EventTypes: []string{events.TypeOrderCreated}})
+	require.NoError(t, err)
+	require.Empty(t, sub.Secret)
+
+	// Đổi secret thì trả về secret mới
+	sub, err = s.UpdateById(ctx, &models.WebhookSubscription{ID: 1, URL: "https://partner.example", EventTypes: []string{events.TypeOrderCreated}, Secret: "stored-secret-value"})
+	require.NoError(t, err)
+	require.Equal(t, "stored-secret-value", sub.Secret)
+}
+
+func TestRedeliver(t *testing.T) {
+	ctx := context.Background()
+	mockRepo := new(mocks.MockWebhookRepo)
+	s := webhook.NewWebhookService(mockRepo)
+	mockRepo.On("Redeliver", mock.Anything, uint(1), uint(5), mock.Anything).
+		Return(&models.WebhookDelivery{ID: 5, Status: models.WebhookDeliveryPending}, nil)
+
+	d, err := s.Redeliver(ctx, 1, 5)
+	require.NoError(t, err)
+	require.Equal(t, models.WebhookDeliveryPending, d.Status)
+
+	_, err = s.Redeliver(ctx, 1, 0)
+	require.ErrorIs(t, err, service.ErrInvalidInput)
+	mockRepo.AssertExpectations(t)
+}
@@ -0,0 +1,52 @@
+package storage_test
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/maithuc2003/Test_GIN_golang/internal/storage"
+)
+
+func TestLocalStorage(t *testing.T) {
+	root := t.TempDir()
+	store, err := storage.NewLocalStorage(root, "/uploads/")
+	require.NoError(t, err)
+	ctx := context.Background()
+
+	tests := []struct {
+		name    string
+		key     string
+		wantErr bool
+	}{
+		{name: "nested key", key: "covers/1/abc/original.png"},
+		{name: "second file", key: "covers/1/abc/thumb.jpg"},
+		{name: "absolute path", key: "/etc/passwd", wantErr: true},
+		{name: "path traversal", key: "covers/../../secret", wantErr: true},
+		{name: "empty key", key: "", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := store.Put(ctx, tt.key, bytes.NewReader([]byte("data")), 4, "image/png")
+			if tt.wantErr {
+				require.ErrorIs(t, err, storage.ErrInvalidKey)
+				return
+			}
+			require.NoError(t, err)
+			got, err := os.ReadFile(filepath.Join(root, filepath.FromSlash(tt.key)))
+			require.NoError(t, err)
+			require.Equal(t, "data", string(got))
+		})
+	}
+
+	require.Equal(t, "/uploads/covers/1/abc/thumb.jpg", store.URL("covers/1/abc/thumb.jpg"))
+
+	require.NoError(t, store.DeletePrefix(ctx, "covers/1/abc"))
+	_, err = os.Stat(filepath.Join(root, "covers", "1", "abc"))
+	require.True(t, os.IsNotExist(err))
+	require.NoError(t, store.DeletePrefix(ctx, "covers/1/abc"), "deleting a missing prefix is not an error")
+}
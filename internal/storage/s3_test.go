@@ -0,0 +1,162 @@
+package storage_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/maithuc2003/Test_GIN_golang/internal/storage"
+)
+
+// fakeS3 là S3 tối giản chạy trong bộ nhớ (path-style), chỉ hỗ trợ các API mà
+// S3Storage dùng: PutObject, ListObjectsV2 và DeleteObject
+type fakeS3 struct {
+	mu      sync.Mutex
+	bucket  string
+	objects map[string][]byte
+	types   map[string]string
+}
+
+func newFakeS3(bucket string) *fakeS3 {
+	return &fakeS3{bucket: bucket, objects: map[string][]byte{}, types: map[string]string{}}
+}
+
+type listResult struct {
+	XMLName  xml.Name `xml:"ListBucketResult"`
+	Name     string   `xml:"Name"`
+	Prefix   string   `xml:"Prefix"`
+	KeyCount int      `xml:"KeyCount"`
+	Contents []struct {
+		Key  string `xml:"Key"`
+		Size int    `xml:"Size"`
+	} `xml:"Contents"`
+	IsTruncated bool `xml:"IsTruncated"`
+}
+
+func (f *fakeS3) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	bucketPath := "/" + f.bucket
+	if r.URL.Path != bucketPath && !strings.HasPrefix(r.URL.Path, bucketPath+"/") {
+		http.Error(w, "NoSuchBucket", http.StatusNotFound)
+		return
+	}
+	key := strings.TrimPrefix(strings.TrimPrefix(r.URL.Path, bucketPath), "/")
+
+	switch {
+	case r.Method == http.MethodPut && key != "":
+		body, err := io.ReadAll(r.Body)
+		if err == nil && strings.HasPrefix(r.Header.Get("X-Amz-Content-Sha256"), "STREAMING-") {
+			body, err = decodeAWSChunked(body)
+		}
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		f.objects[key] = body
+		f.types[key] = r.Header.Get("Content-Type")
+		w.Header().Set("ETag", `"fake"`)
+	case r.Method == http.MethodGet && key == "":
+		prefix := r.URL.Query().Get("prefix")
+		res := listResult{Name: f.bucket, Prefix: prefix}
+		keys := make([]string, 0, len(f.objects))
+		for k := range f.objects {
+			if strings.HasPrefix(k, prefix) {
+				keys = append(keys, k)
+			}
+		}
+		sort.Strings(keys)
+		for _, k := range keys {
+			res.Contents = append(res.Contents, struct {
+				Key  string `xml:"Key"`
+				Size int    `xml:"Size"`
+			}{Key: k, Size: len(f.objects[k])})
+		}
+		res.KeyCount = len(keys)
+		w.Header().Set("Content-Type", "application/xml")
+		_ = xml.NewEncoder(w).Encode(res)
+	case r.Method == http.MethodDelete && key != "":
+		delete(f.objects, key)
+		w.WriteHeader(http.StatusNoContent)
+	default:
+		http.Error(w, "NotImplemented", http.StatusNotImplemented)
+	}
+}
+
+// decodeAWSChunked bỏ phần khung "<size>;chunk-signature=...\r\n<data>\r\n" của
+// upload dạng streaming SigV4 (client S3 dùng khi kết nối không có TLS)
+func decodeAWSChunked(body []byte) ([]byte, error) {
+	var out []byte
+	for {
+		header, rest, ok := bytes.Cut(body, []byte("\r\n"))
+		if !ok {
+			return nil, fmt.Errorf("malformed chunk header")
+		}
+		sizeHex, _, _ := strings.Cut(string(header), ";")
+		size, err := strconv.ParseInt(sizeHex, 16, 64)
+		if err != nil || int64(len(rest)) < size+2 {
+			return nil, fmt.Errorf("malformed chunk size %q", sizeHex)
+		}
+		if size == 0 {
+			return out, nil
+		}
+		out = append(out, rest[:size]...)
+		body = rest[size+2:]
+	}
+}
+
+func TestS3Storage(t *testing.T) {
+	fake := newFakeS3("covers")
+	srv := httptest.NewServer(fake)
+	defer srv.Close()
+
+	store, err := storage.NewS3Storage(storage.S3Config{
+		Endpoint:  strings.TrimPrefix(srv.URL, "http://"),
+		Bucket:    "covers",
+		AccessKey: "test",
+		SecretKey: "testsecret",
+		Region:    "us-east-1",
+	})
+	require.NoError(t, err)
+	ctx := context.Background()
+
+	data := []byte("fake image bytes")
+	require.NoError(t, store.Put(ctx, "covers/7/abc/original.png", bytes.NewReader(data), int64(len(data)), "image/png"))
+	require.NoError(t, store.Put(ctx, "covers/7/abc/thumb.jpg", bytes.NewReader(data), int64(len(data)), "image/jpeg"))
+	require.NoError(t, store.Put(ctx, "covers/7/other/original.png", bytes.NewReader(data), int64(len(data)), "image/png"))
+	require.ErrorIs(t, store.Put(ctx, "../escape", bytes.NewReader(data), int64(len(data)), "image/png"), storage.ErrInvalidKey)
+
+	require.Equal(t, data, fake.objects["covers/7/abc/original.png"])
+	require.Equal(t, "image/png", fake.types["covers/7/abc/original.png"])
+	require.Equal(t, srv.URL+"/covers/covers/7/abc/thumb.jpg", store.URL("covers/7/abc/thumb.jpg"))
+
+	require.NoError(t, store.DeletePrefix(ctx, "covers/7/abc"))
+	require.Len(t, fake.objects, 1)
+	require.Contains(t, fake.objects, "covers/7/other/original.png")
+}
+
+func TestS3Storage_PublicURL(t *testing.T) {
+	store, err := storage.NewS3Storage(storage.S3Config{
+		Endpoint:  "localhost:9000",
+		Bucket:    "covers",
+		Region:    "us-east-1",
+		PublicURL: "https://cdn.example.com/",
+	})
+	require.NoError(t, err)
+	require.Equal(t, "https://cdn.example.com/a/b.jpg", store.URL("a/b.jpg"))
+
+	_, err = storage.NewS3Storage(storage.S3Config{Endpoint: "localhost:9000"})
+	require.Error(t, err)
+}
@@ -0,0 +1,38 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"io"
+	"path"
+	"strings"
+)
+
+// ErrInvalidKey được trả về khi key rỗng, tuyệt đối hoặc cố thoát ra ngoài thư mục gốc
+var ErrInvalidKey = errors.New("invalid storage key")
+
+// Storage lưu file nhị phân (ảnh bìa...) theo key dạng "covers/12/abc/original.jpg".
+// Có hai bản cài đặt: LocalStorage (thư mục trên đĩa) và S3Storage (S3 hoặc dịch vụ tương thích).
+type Storage interface {
+	Put(ctx context.Context, key string, r io.Reader, size int64, contentType string) error
+	// DeletePrefix xoá mọi file nằm dưới "thư mục" prefix; không có file nào thì không lỗi
+	DeletePrefix(ctx context.Context, prefix string) error
+	// URL trả về địa chỉ public của key
+	URL(key string) string
+}
+
+// cleanKey chuẩn hoá key và chặn path traversal
+func cleanKey(key string) (string, error) {
+	if key == "" || strings.HasPrefix(key, "/") || strings.Contains(key, "\\") {
+		return "", ErrInvalidKey
+	}
+	cleaned := path.Clean(key)
+	if cleaned == "." || cleaned == ".." || strings.HasPrefix(cleaned, "../") {
+		return "", ErrInvalidKey
+	}
+	return cleaned, nil
+}
+
+func joinURL(base, key string) string {
+	return strings.TrimRight(base, "/") + "/" + key
+}
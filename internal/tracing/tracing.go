@@ -0,0 +1,52 @@
+// Package tracing cấu hình OpenTelemetry và cung cấp span cho handler, service,
+// repository và câu lệnh GORM.
+package tracing
+
+import (
+	"context"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	semconv "go.opentelemetry.io/otel/semconv/v1.37.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const (
+	// ServiceName là tên dịch vụ mặc định; OTEL_SERVICE_NAME ghi đè
+	ServiceName     = "bookstore"
+	instrumentation = "github.com/maithuc2003/Test_GIN_golang"
+)
+
+// Setup đăng ký TracerProvider toàn cục xuất span qua exporter và propagator W3C
+// (traceparent, baggage). exporter nil thì giữ provider no-op: span không được ghi
+// nhưng trace ID từ client vẫn được truyền tiếp. Hàm trả về cần được gọi khi tắt
+// ứng dụng để đẩy nốt các span còn trong bộ đệm.
+func Setup(ctx context.Context, exporter sdktrace.SpanExporter) (func(context.Context) error, error) {
+	otel.SetTextMapPropagator(propagation.NewCompositeTextMapPropagator(propagation.TraceContext{}, propagation.Baggage{}))
+	if exporter == nil {
+		return func(context.Context) error { return nil }, nil
+	}
+
+	res, err := resource.New(ctx,
+		resource.WithAttributes(semconv.ServiceName(ServiceName)),
+		resource.WithFromEnv(),
+		resource.WithTelemetrySDK(),
+	)
+	if err != nil {
+		return nil, err
+	}
+	// Sampler mặc định đọc OTEL_TRACES_SAMPLER/OTEL_TRACES_SAMPLER_ARG
+	provider := sdktrace.NewTracerProvider(sdktrace.WithBatcher(exporter), sdktrace.WithResource(res))
+	otel.SetTracerProvider(provider)
+	return provider.Shutdown, nil
+}
+
+// Start mở span con của span trong ctx, dùng theo mẫu:
+//
+//	ctx, span := tracing.Start(ctx, "orderRepo.Create")
+//	defer span.End()
+func Start(ctx context.Context, name string, opts ...trace.SpanStartOption) (context.Context, trace.Span) {
+	return otel.Tracer(instrumentation).Start(ctx, name, opts...)
+}
@@ -0,0 +1,88 @@
+package tracing_test
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"gorm.io/gorm"
+
+	"github.com/maithuc2003/Test_GIN_golang/internal/models"
+	"github.com/maithuc2003/Test_GIN_golang/internal/tracing"
+	sqlitedriver "gorm.io/driver/sqlite"
+
+	_ "modernc.org/sqlite"
+)
+
+// record đặt TracerProvider toàn cục ghi span vào bộ nhớ
+func record(t *testing.T) *tracetest.SpanRecorder {
+	recorder := tracetest.NewSpanRecorder()
+	provider := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder))
+	otel.SetTracerProvider(provider)
+	t.Cleanup(func() { _ = provider.Shutdown(context.Background()) })
+	return recorder
+}
+
+func attr(span sdktrace.ReadOnlySpan, key attribute.Key) attribute.Value {
+	for _, kv := range span.Attributes() {
+		if kv.Key == key {
+			return kv.Value
+		}
+	}
+	return attribute.Value{}
+}
+
+func TestGormPlugin(t *testing.T) {
+	recorder := record(t)
+	dsn := fmt.Sprintf("file:testdb_%d?mode=memory&cache=shared", time.Now().UnixNano())
+	db, err := gorm.Open(sqlitedriver.New(sqlitedriver.Config{DSN: dsn, DriverName: "sqlite"}), &gorm.Config{})
+	require.NoError(t, err)
+	require.NoError(t, db.AutoMigrate(&models.Author{}))
+	require.NoError(t, db.Use(tracing.GormPlugin{}))
+
+	// Không có span cha thì không sinh span
+	require.NoError(t, db.Create(&models.Author{Name: "Ann"}).Error)
+	require.Empty(t, recorder.Ended())
+
+	ctx, parent := tracing.Start(context.Background(), "authorRepo.Test")
+	var author models.Author
+	require.NoError(t, db.WithContext(ctx).First(&author).Error)
+	require.ErrorIs(t, db.WithContext(ctx).First(&models.Author{}, 999).Error, gorm.ErrRecordNotFound)
+	require.Error(t, db.WithContext(ctx).Exec("SELECT * FROM missing_table").Error)
+	parent.End()
+
+	spans := recorder.Ended()
+	require.Len(t, spans, 4)
+	for _, span := range spans[:3] {
+		require.Equal(t, parent.SpanContext().SpanID(), span.Parent().SpanID())
+	}
+
+	found := spans[0]
+	require.Equal(t, "gorm.query", found.Name())
+	require.Equal(t, "sqlite", attr(found, "db.system.name").AsString())
+	require.Equal(t, "authors", attr(found, "db.collection.name").AsString())
+	require.Contains(t, attr(found, "db.query.text").AsString(), "SELECT * FROM `authors`")
+	require.EqualValues(t, 1, attr(found, "db.response.returned_rows").AsInt64())
+	require.Equal(t, codes.Unset, found.Status().Code)
+
+	require.Equal(t, codes.Unset, spans[1].Status().Code, "record not found is not an error")
+
+	require.Equal(t, "gorm.raw", spans[2].Name())
+	require.Equal(t, codes.Error, spans[2].Status().Code)
+	require.NotEmpty(t, spans[2].Events(), "error is recorded on the span")
+
+	require.Equal(t, "authorRepo.Test", spans[3].Name())
+}
+
+func TestSetupWithoutExporter(t *testing.T) {
+	shutdown, err := tracing.Setup(context.Background(), nil)
+	require.NoError(t, err)
+	require.NoError(t, shutdown(context.Background()))
+}
@@ -0,0 +1,167 @@
+package webhook
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/maithuc2003/Test_GIN_golang/internal/interfaces/repositories"
+	"github.com/maithuc2003/Test_GIN_golang/internal/models"
+)
+
+const (
+	DefaultMaxAttempts = 8
+	defaultBatchSize   = 50
+	// maxResponseBody giới hạn phần body phản hồi được lưu vào log
+	maxResponseBody = 4 << 10
+	// firstRetryDelay tăng gấp đôi sau mỗi lần thất bại, tối đa maxRetryDelay
+	firstRetryDelay = 10 * time.Second
+	maxRetryDelay   = time.Hour
+)
+
+// Sender gửi các delivery pending. Phản hồi 2xx là thành công; lỗi mạng, timeout hoặc
+// mã khác (kể cả redirect) được thử lại với thời gian chờ tăng dần tới maxAttempts lần.
+type Sender struct {
+	repo        repositories.WebhookRepository
+	client      *http.Client
+	maxAttempts int
+	batchSize   int
+}
+
+// NewSender dùng timeout cho mỗi lần gửi; maxAttempts <= 0 dùng DefaultMaxAttempts
+func NewSender(repo repositories.WebhookRepository, timeout time.Duration, maxAttempts int) *Sender {
+	if maxAttempts <= 0 {
+		maxAttempts = DefaultMaxAttempts
+	}
+	return &Sender{
+		repo: repo,
+		client: &http.Client{
+			Timeout: timeout,
+			CheckRedirect: func(*http.Request, []*http.Request) error {
+				return http.ErrUseLastResponse
+			},
+		},
+		maxAttempts: maxAttempts,
+		batchSize:   defaultBatchSize,
+	}
+}
+
+// SendPending gửi một lượt các delivery tới hạn, trả về số delivery đã gửi thành công
+func (s *Sender) SendPending(ctx context.Context) (int, error) {
+	// Lease dài hơn thời gian gửi cả lượt để sender khác không lấy trùng
+	lease := s.client.Timeout*time.Duration(s.batchSize) + time.Minute
+	deliveries, err := s.repo.ClaimDeliveries(ctx, time.Now(), lease, s.batchSize)
+	if err != nil {
+		return 0, err
+	}
+	subs := map[uint]*models.WebhookSubscription{}
+	succeeded := 0
+	for i := range deliveries {
+		delivery := &deliveries[i]
+		sub, ok := subs[delivery.SubscriptionID]
+		if !ok {
+			if sub, err = s.repo.GetSubscription(ctx, delivery.SubscriptionID); err != nil && !errors.Is(err, repositories.ErrNotFound) {
+				return succeeded, err
+			}
+			subs[delivery.SubscriptionID] = sub
+		}
+		attempt := s.send(ctx, sub, delivery)
+		if err := s.repo.RecordAttempt(ctx, delivery, attempt); err != nil {
+			return succeeded, err
+		}
+		if delivery.Status == models.WebhookDeliverySucceeded {
+			succeeded++
+		}
+	}
+	return succeeded, nil
+}
+
+// Run gửi delivery mỗi interval cho tới khi ctx bị huỷ
+func (s *Sender) Run(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		if _, err := s.SendPending(ctx); err != nil && ctx.Err() == nil {
+			slog.ErrorContext(ctx, "failed to send webhooks", "error", err)
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// send gửi delivery một lần và cập nhật trạng thái của nó theo kết quả
+func (s *Sender) send(ctx context.Context, sub *models.WebhookSubscription, delivery *models.WebhookDelivery) *models.WebhookDeliveryAttempt {
+	now := time.Now()
+	attempt := &models.WebhookDeliveryAttempt{CreatedAt: now}
+	delivery.Attempts++
+	delivery.UpdatedAt = now
+
+	var err error
+	// Subscription bị tắt thì dừng luôn; bật lại rồi redeliver nếu cần
+	giveUp := sub == nil || !sub.Active
+	if giveUp {
+		err = errors.New("subscription is disabled")
+	} else {
+		attempt.ResponseCode, attempt.ResponseBody, err = s.post(ctx, sub, delivery)
+		attempt.DurationMs = time.Since(now).Milliseconds()
+	}
+	delivery.ResponseCode = attempt.ResponseCode
+
+	if err == nil {
+		delivery.Status = models.WebhookDeliverySucceeded
+		delivery.DeliveredAt = &now
+		delivery.LastError = ""
+		return attempt
+	}
+	attempt.Error = err.Error()
+	delivery.LastError = err.Error()
+	if giveUp || delivery.Attempts >= s.maxAttempts {
+		delivery.Status = models.WebhookDeliveryFailed
+		return attempt
+	}
+	delivery.NextAttemptAt = now.Add(retryDelay(delivery.Attempts))
+	return attempt
+}
+
+func (s *Sender) post(ctx context.Context, sub *models.WebhookSubscription, delivery *models.WebhookDelivery) (int, string, error) {
+	body := []byte(delivery.Body)
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, sub.URL, bytes.NewReader(body))
+	if err != nil {
+		return 0, "", fmt.Errorf("invalid request: %w", err)
+	}
+	timestamp := time.Now().Unix()
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(HeaderEvent, delivery.EventType)
+	req.Header.Set(HeaderDelivery, strconv.FormatUint(uint64(delivery.ID), 10))
+	req.Header.Set(HeaderTimestamp, strconv.FormatInt(timestamp, 10))
+	req.Header.Set(HeaderSignature, Sign(sub.Secret, timestamp, body))
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return 0, "", err
+	}
+	defer resp.Body.Close()
+	respBody, _ := io.ReadAll(io.LimitReader(resp.Body, maxResponseBody))
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return resp.StatusCode, string(respBody), fmt.Errorf("endpoint responded %d", resp.StatusCode)
+	}
+	return resp.StatusCode, string(respBody), nil
+}
+
+// retryDelay là thời gian chờ sau lần thất bại thứ attempts
+func retryDelay(attempts int) time.Duration {
+	delay := firstRetryDelay
+	for i := 1; i < attempts && delay < maxRetryDelay; i++ {
+		delay *= 2
+	}
+	return min(delay, maxRetryDelay)
+}
@@ -0,0 +1,68 @@
+// Package webhook giao domain event tới các endpoint đối tác đã đăng ký, có ký HMAC
+// và thử lại khi endpoint lỗi.
+package webhook
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Header của mỗi lần gửi. Chữ ký là HMAC-SHA256 với secret của subscription trên
+// chuỗi "<timestamp>.<body>", gửi dạng "sha256=<hex>".
+const (
+	HeaderEvent      = "X-Webhook-Event"
+	HeaderDelivery   = "X-Webhook-Delivery"
+	HeaderTimestamp  = "X-Webhook-Timestamp"
+	HeaderSignature  = "X-Webhook-Signature"
+	signaturePrefix  = "sha256="
+	secretRandomSize = 32
+)
+
+var ErrInvalidSignature = errors.New("invalid webhook signature")
+
+// Sign trả về giá trị header chữ ký cho body gửi lúc timestamp (Unix giây)
+func Sign(secret string, timestamp int64, body []byte) string {
+	return signaturePrefix + hex.EncodeToString(mac(secret, timestamp, body))
+}
+
+// Verify kiểm tra chữ ký và độ lệch thời gian (tolerance) như phía nhận cần làm để
+// chống giả mạo và gửi lại (replay); tolerance <= 0 thì bỏ qua kiểm tra thời gian
+func Verify(secret, timestamp, signature string, body []byte, tolerance time.Duration, now time.Time) error {
+	ts, err := strconv.ParseInt(timestamp, 10, 64)
+	if err != nil {
+		return fmt.Errorf("%w: bad timestamp", ErrInvalidSignature)
+	}
+	if tolerance > 0 && math.Abs(now.Sub(time.Unix(ts, 0)).Seconds()) > tolerance.Seconds() {
+		return fmt.Errorf("%w: timestamp outside tolerance", ErrInvalidSignature)
+	}
+	got, err := hex.DecodeString(strings.TrimPrefix(signature, signaturePrefix))
+	if err != nil || !strings.HasPrefix(signature, signaturePrefix) || !hmac.Equal(got, mac(secret, ts, body)) {
+		return ErrInvalidSignature
+	}
+	return nil
+}
+
+// NewSecret sinh secret ngẫu nhiên cho subscription không tự đặt secret
+func NewSecret() (string, error) {
+	b := make([]byte, secretRandomSize)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return "whsec_" + hex.EncodeToString(b), nil
+}
+
+func mac(secret string, timestamp int64, body []byte) []byte {
+	h := hmac.New(sha256.New, []byte(secret))
+	h.Write([]byte(strconv.FormatInt(timestamp, 10)))
+	h.Write([]byte("."))
+	h.Write(body)
+	return h.Sum(nil)
+}
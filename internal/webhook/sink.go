@@ -0,0 +1,49 @@
+package webhook
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/maithuc2003/Test_GIN_golang/internal/events"
+	"github.com/maithuc2003/Test_GIN_golang/internal/interfaces/repositories"
+	"github.com/maithuc2003/Test_GIN_golang/internal/models"
+)
+
+// Sink nhận event từ outbox dispatcher và tạo delivery cho mỗi subscription đăng ký
+// loại event đó; việc gửi HTTP do Sender làm để mỗi endpoint được thử lại độc lập
+type Sink struct {
+	repo repositories.WebhookRepository
+}
+
+func NewSink(repo repositories.WebhookRepository) *Sink {
+	return &Sink{repo: repo}
+}
+
+func (s *Sink) Name() string { return "webhook" }
+
+// Deliver bỏ qua delivery đã tạo nên dispatcher giao lại cùng event không gửi trùng
+func (s *Sink) Deliver(ctx context.Context, event events.Event) error {
+	subs, err := s.repo.ActiveSubscriptions(ctx, event.Type)
+	if err != nil || len(subs) == 0 {
+		return err
+	}
+	body, err := json.Marshal(event)
+	if err != nil {
+		return fmt.Errorf("failed to encode webhook body: %w", err)
+	}
+	now := time.Now()
+	deliveries := make([]models.WebhookDelivery, len(subs))
+	for i, sub := range subs {
+		deliveries[i] = models.WebhookDelivery{
+			SubscriptionID: sub.ID,
+			EventID:        event.ID,
+			EventType:      event.Type,
+			Body:           string(body),
+			Status:         models.WebhookDeliveryPending,
+			NextAttemptAt:  now,
+		}
+	}
+	return s.repo.EnqueueDeliveries(ctx, deliveries)
+}
@@ -0,0 +1,300 @@
+package webhook_test
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+
+	"github.com/maithuc2003/Test_GIN_golang/internal/events"
+	"github.com/maithuc2003/Test_GIN_golang/internal/interfaces/repositories"
+	"github.com/maithuc2003/Test_GIN_golang/internal/models"
+	"github.com/maithuc2003/Test_GIN_golang/internal/repositories/outbox"
+	WebhookRepo "github.com/maithuc2003/Test_GIN_golang/internal/repositories/webhook"
+	"github.com/maithuc2003/Test_GIN_golang/internal/webhook"
+	sqlitedriver "gorm.io/driver/sqlite"
+
+	_ "modernc.org/sqlite"
+)
+
+const secret = "whsec_test_secret_value"
+
+func setupTestDB(t *testing.T) *gorm.DB {
+	dsn := fmt.Sprintf("file:testdb_%d?mode=memory&cache=shared", time.Now().UnixNano())
+	db, err := gorm.Open(sqlitedriver.New(sqlitedriver.Config{DSN: dsn, DriverName: "sqlite"}), &gorm.Config{})
+	require.NoError(t, err)
+	require.NoError(t, db.AutoMigrate(&models.OutboxEvent{}, &models.WebhookSubscription{}, &models.WebhookDelivery{}, &models.WebhookDeliveryAttempt{}))
+	return db
+}
+
+// receiver là endpoint đối tác giả: kiểm tra chữ ký và trả về lần lượt các mã trong statuses
+// (mã cuối được lặp lại)
+type receiver struct {
+	mu       sync.Mutex
+	statuses []int
+	bodies   []events.Event
+	headers  []http.Header
+}
+
+func newReceiver(t *testing.T, statuses ...int) (*receiver, *httptest.Server) {
+	rcv := &receiver{statuses: statuses}
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, err := io.ReadAll(r.Body)
+		require.NoError(t, err)
+		if err := webhook.Verify(secret, r.Header.Get(webhook.HeaderTimestamp), r.Header.Get(webhook.HeaderSignature), body, 5*time.Minute, time.Now()); err != nil {
+			http.Error(w, err.Error(), http.StatusUnauthorized)
+			return
+		}
+		var event events.Event
+		require.NoError(t, json.Unmarshal(body, &event))
+
+		rcv.mu.Lock()
+		defer rcv.mu.Unlock()
+		status := rcv.statuses[min(len(rcv.bodies), len(rcv.statuses)-1)]
+		rcv.bodies = append(rcv.bodies, event)
+		rcv.headers = append(rcv.headers, r.Header.Clone())
+		w.WriteHeader(status)
+		fmt.Fprintf(w, "status %d", status)
+	}))
+	t.Cleanup(srv.Close)
+	return rcv, srv
+}
+
+func (r *receiver) received() []events.Event {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return append([]events.Event(nil), r.bodies...)
+}
+
+type fixture struct {
+	db         *gorm.DB
+	repo       repositories.WebhookRepository
+	dispatcher *events.Dispatcher
+}
+
+func setup(t *testing.T) *fixture {
+	db := setupTestDB(t)
+	repo := WebhookRepo.NewWebhookRepo(db)
+	return &fixture{db: db, repo: repo, dispatcher: events.NewDispatcher(outbox.NewOutboxRepo(db), webhook.NewSink(repo))}
+}
+
+func (f *fixture) subscribe(t *testing.T, url string, types ...string) models.WebhookSubscription {
+	sub := models.WebhookSubscription{URL: url, EventTypes: types, Secret: secret, Active: true}
+	require.NoError(t, f.repo.CreateSubscription(context.Background(), &sub))
+	return sub
+}
+
+// publish ghi event vào outbox rồi cho dispatcher chuyển sang hàng đợi webhook
+func (f *fixture) publish(t *testing.T, domain ...events.Domain) {
+	require.NoError(t, outbox.Append(f.db, domain...))
+	_, err := f.dispatcher.DispatchPending(context.Background())
+	require.NoError(t, err)
+}
+
+func (f *fixture) delivery(t *testing.T, id uint) models.WebhookDelivery {
+	var d models.WebhookDelivery
+	require.NoError(t, f.db.Preload("Log").First(&d, id).Error)
+	return d
+}
+
+// makeDue đưa các delivery đang chờ thử lại tới hạn ngay
+func (f *fixture) makeDue(t *testing.T) {
+	require.NoError(t, f.db.Model(&models.WebhookDelivery{}).Where("1 = 1").
+		Update("next_attempt_at", time.Now().Add(-time.Second)).Error)
+}
+
+func TestSignature(t *testing.T) {
+	body := []byte(`{"id":1}`)
+	now := time.Unix(1_700_000_000, 0)
+	signature := webhook.Sign(secret, now.Unix(), body)
+	ts := strconv.FormatInt(now.Unix(), 10)
+
+	require.NoError(t, webhook.Verify(secret, ts, signature, body, time.Minute, now))
+	require.ErrorIs(t, webhook.Verify("other", ts, signature, body, time.Minute, now), webhook.ErrInvalidSignature)
+	require.ErrorIs(t, webhook.Verify(secret, ts, signature, []byte(`{"id":2}`), time.Minute, now), webhook.ErrInvalidSignature)
+	require.ErrorIs(t, webhook.Verify(secret, "1700000001", signature, body, time.Minute, now), webhook.ErrInvalidSignature)
+	require.ErrorIs(t, webhook.Verify(secret, ts, signature, body, time.Minute, now.Add(2*time.Minute)), webhook.ErrInvalidSignature, "replayed too late")
+	require.ErrorIs(t, webhook.Verify(secret, ts, "deadbeef", body, time.Minute, now), webhook.ErrInvalidSignature)
+}
+
+func TestSender(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("delivers signed event to matching subscriptions only", func(t *testing.T) {
+		f := setup(t)
+		orders, ordersSrv := newReceiver(t, http.StatusOK)
+		stock, stockSrv := newReceiver(t, http.StatusNoContent)
+		sub := f.subscribe(t, ordersSrv.URL, events.TypeOrderStatusChanged)
+		f.subscribe(t, stockSrv.URL, events.TypeStockChanged)
+
+		f.publish(t, events.OrderStatusChanged{OrderID: 4, From: models.OrderStatusPending, To: models.OrderStatusPaid})
+		n, err := webhook.NewSender(f.repo, time.Second, 3).SendPending(ctx)
+		require.NoError(t, err)
+		require.Equal(t, 1, n)
+
+		require.Empty(t, stock.received())
+		got := orders.received()
+		require.Len(t, got, 1)
+		require.Equal(t, events.TypeOrderStatusChanged, got[0].Type)
+		require.Equal(t, uint(4), got[0].AggregateID)
+		require.Equal(t, events.TypeOrderStatusChanged, orders.headers[0].Get(webhook.HeaderEvent))
+
+		deliveries, err := f.repo.ListDeliveries(ctx, sub.ID, 10)
+		require.NoError(t, err)
+		require.Len(t, deliveries, 1)
+		d := deliveries[0]
+		require.Equal(t, strconv.FormatUint(uint64(d.ID), 10), orders.headers[0].Get(webhook.HeaderDelivery))
+		require.Equal(t, models.WebhookDeliverySucceeded, d.Status)
+		require.Equal(t, http.StatusOK, d.ResponseCode)
+		require.NotNil(t, d.DeliveredAt)
+		require.Len(t, d.Log, 1)
+		require.Equal(t, "status 200", d.Log[0].ResponseBody)
+
+		// Dispatcher giao lại cùng event không tạo delivery thứ hai
+		require.NoError(t, webhook.NewSink(f.repo).Deliver(ctx, got[0]))
+		var count int64
+		require.NoError(t, f.db.Model(&models.WebhookDelivery{}).Count(&count).Error)
+		require.EqualValues(t, 1, count)
+	})
+
+	t.Run("retries with backoff until success", func(t *testing.T) {
+		f := setup(t)
+		rcv, srv := newReceiver(t, http.StatusInternalServerError, http.StatusServiceUnavailable, http.StatusOK)
+		f.subscribe(t, srv.URL, events.TypeBookCreated)
+		f.publish(t, events.BookCreated{BookID: 1, Title: "Dune", AuthorID: 1})
+		sender := webhook.NewSender(f.repo, time.Second, 5)
+
+		start := time.Now()
+		n, err := sender.SendPending(ctx)
+		require.NoError(t, err)
+		require.Zero(t, n)
+		first := f.delivery(t, 1)
+		require.Equal(t, models.WebhookDeliveryPending, first.Status)
+		require.Equal(t, 1, first.Attempts)
+		require.Equal(t, http.StatusInternalServerError, first.ResponseCode)
+		require.Equal(t, "endpoint responded 500", first.LastError)
+		require.WithinDuration(t, start.Add(10*time.Second), first.NextAttemptAt, 2*time.Second)
+
+		// Chưa tới hạn thì không gửi lại
+		n, err = sender.SendPending(ctx)
+		require.NoError(t, err)
+		require.Zero(t, n)
+		require.Len(t, rcv.received(), 1)
+
+		f.makeDue(t)
+		start = time.Now()
+		_, err = sender.SendPending(ctx)
+		require.NoError(t, err)
+		second := f.delivery(t, 1)
+		require.Equal(t, 2, second.Attempts)
+		require.WithinDuration(t, start.Add(20*time.Second), second.NextAttemptAt, 2*time.Second, "delay doubles")
+
+		f.makeDue(t)
+		n, err = sender.SendPending(ctx)
+		require.NoError(t, err)
+		require.Equal(t, 1, n)
+		done := f.delivery(t, 1)
+		require.Equal(t, models.WebhookDeliverySucceeded, done.Status)
+		require.Equal(t, 3, done.Attempts)
+		require.Empty(t, done.LastError)
+		require.Len(t, done.Log, 3)
+		require.Equal(t, []int{500, 503, 200}, []int{done.Log[0].ResponseCode, done.Log[1].ResponseCode, done.Log[2].ResponseCode})
+
+		// Nội dung gửi giống nhau qua các lần thử
+		got := rcv.received()
+		require.Len(t, got, 3)
+		require.Equal(t, got[0], got[2])
+	})
+
+	t.Run("gives up after max attempts and redelivers", func(t *testing.T) {
+		f := setup(t)
+		rcv, srv := newReceiver(t, http.StatusBadGateway, http.StatusBadGateway, http.StatusOK)
+		sub := f.subscribe(t, srv.URL, events.TypeAuthorDeleted)
+		f.publish(t, events.AuthorDeleted{AuthorID: 3, Name: "Ann"})
+		sender := webhook.NewSender(f.repo, time.Second, 2)
+
+		_, err := sender.SendPending(ctx)
+		require.NoError(t, err)
+		f.makeDue(t)
+		_, err = sender.SendPending(ctx)
+		require.NoError(t, err)
+		failed := f.delivery(t, 1)
+		require.Equal(t, models.WebhookDeliveryFailed, failed.Status)
+		require.Equal(t, 2, failed.Attempts)
+
+		f.makeDue(t)
+		n, err := sender.SendPending(ctx)
+		require.NoError(t, err)
+		require.Zero(t, n)
+		require.Len(t, rcv.received(), 2)
+
+		_, err = f.repo.Redeliver(ctx, sub.ID, failed.ID, time.Now())
+		require.NoError(t, err)
+		n, err = sender.SendPending(ctx)
+		require.NoError(t, err)
+		require.Equal(t, 1, n)
+		done := f.delivery(t, 1)
+		require.Equal(t, models.WebhookDeliverySucceeded, done.Status)
+		require.Len(t, done.Log, 3, "log keeps earlier attempts")
+	})
+
+	t.Run("connection error is retried", func(t *testing.T) {
+		f := setup(t)
+		_, srv := newReceiver(t, http.StatusOK)
+		f.subscribe(t, srv.URL, events.TypeStockChanged)
+		srv.Close()
+		f.publish(t, events.StockChanged{BookID: 1, Delta: 2, Stock: 5, Reason: events.StockReasonRestock})
+
+		_, err := webhook.NewSender(f.repo, time.Second, 3).SendPending(ctx)
+		require.NoError(t, err)
+		d := f.delivery(t, 1)
+		require.Equal(t, models.WebhookDeliveryPending, d.Status)
+		require.Zero(t, d.ResponseCode)
+		require.NotEmpty(t, d.LastError)
+		require.Len(t, d.Log, 1)
+	})
+
+	t.Run("wrong secret is rejected by receiver", func(t *testing.T) {
+		f := setup(t)
+		_, srv := newReceiver(t, http.StatusOK)
+		sub := models.WebhookSubscription{URL: srv.URL, EventTypes: []string{events.TypeBookCreated}, Secret: "whsec_some_other_secret", Active: true}
+		require.NoError(t, f.repo.CreateSubscription(ctx, &sub))
+		f.publish(t, events.BookCreated{BookID: 1, Title: "Dune", AuthorID: 1})
+
+		_, err := webhook.NewSender(f.repo, time.Second, 3).SendPending(ctx)
+		require.NoError(t, err)
+		require.Equal(t, http.StatusUnauthorized, f.delivery(t, 1).ResponseCode)
+	})
+
+	t.Run("disabled subscription stops delivery", func(t *testing.T) {
+		f := setup(t)
+		rcv, srv := newReceiver(t, http.StatusOK)
+		sub := f.subscribe(t, srv.URL, events.TypeBookCreated)
+		f.publish(t, events.BookCreated{BookID: 1, Title: "Dune", AuthorID: 1})
+		sub.Active = false
+		_, err := f.repo.UpdateSubscription(ctx, &sub)
+		require.NoError(t, err)
+
+		_, err = webhook.NewSender(f.repo, time.Second, 3).SendPending(ctx)
+		require.NoError(t, err)
+		d := f.delivery(t, 1)
+		require.Equal(t, models.WebhookDeliveryFailed, d.Status)
+		require.Equal(t, "subscription is disabled", d.LastError)
+		require.Empty(t, rcv.received())
+
+		// Subscription tắt không nhận event mới
+		f.publish(t, events.BookCreated{BookID: 2, Title: "Emma", AuthorID: 1})
+		var count int64
+		require.NoError(t, f.db.Model(&models.WebhookDelivery{}).Count(&count).Error)
+		require.EqualValues(t, 1, count)
+	})
+}
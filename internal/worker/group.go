@@ -0,0 +1,63 @@
+// Package worker quản lý các goroutine nền (dispatcher, sweeper...) để ứng dụng có thể
+// dừng chúng và chờ chúng kết thúc khi tắt.
+package worker
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// Group chạy các worker với chung một context; Stop huỷ context rồi chờ mọi worker trả về
+type Group struct {
+	ctx    context.Context
+	cancel context.CancelFunc
+	wg     sync.WaitGroup
+}
+
+func NewGroup(ctx context.Context) *Group {
+	ctx, cancel := context.WithCancel(ctx)
+	return &Group{ctx: ctx, cancel: cancel}
+}
+
+// Go chạy fn trong goroutine riêng; fn phải trả về khi ctx bị huỷ
+func (g *Group) Go(fn func(ctx context.Context)) {
+	g.wg.Add(1)
+	go func() {
+		defer g.wg.Done()
+		fn(g.ctx)
+	}()
+}
+
+// Every gọi fn sau mỗi interval cho tới khi group dừng. Lần chạy đang dở được chạy
+// hết, chỉ bị ngắt qua ctx.
+func (g *Group) Every(interval time.Duration, fn func(ctx context.Context)) {
+	g.Go(func(ctx context.Context) {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				fn(ctx)
+			}
+		}
+	})
+}
+
+// Stop huỷ context của các worker và chờ chúng kết thúc hoặc tới khi ctx hết hạn
+func (g *Group) Stop(ctx context.Context) error {
+	g.cancel()
+	done := make(chan struct{})
+	go func() {
+		g.wg.Wait()
+		close(done)
+	}()
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
@@ -0,0 +1,44 @@
+package worker_test
+
+import (
+	"context"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/maithuc2003/Test_GIN_golang/internal/worker"
+)
+
+func TestGroup_Stop(t *testing.T) {
+	g := worker.NewGroup(context.Background())
+
+	var ticks atomic.Int32
+	g.Every(time.Millisecond, func(ctx context.Context) { ticks.Add(1) })
+	var stopped atomic.Bool
+	g.Go(func(ctx context.Context) {
+		<-ctx.Done()
+		stopped.Store(true)
+	})
+
+	require.Eventually(t, func() bool { return ticks.Load() >= 3 }, time.Second, time.Millisecond)
+	require.NoError(t, g.Stop(context.Background()))
+	require.True(t, stopped.Load())
+
+	after := ticks.Load()
+	time.Sleep(5 * time.Millisecond)
+	require.Equal(t, after, ticks.Load(), "no ticks after stop")
+}
+
+func TestGroup_StopTimeout(t *testing.T) {
+	g := worker.NewGroup(context.Background())
+	release := make(chan struct{})
+	defer close(release)
+	// Worker bỏ qua ctx
+	g.Go(func(context.Context) { <-release })
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	require.ErrorIs(t, g.Stop(ctx), context.DeadlineExceeded)
+}
@@ -1,12 +1,147 @@
 package main
 
 import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
 	"github.com/maithuc2003/Test_GIN_golang/config"
+	"github.com/maithuc2003/Test_GIN_golang/internal/database"
+	"github.com/maithuc2003/Test_GIN_golang/internal/redis"
 	"github.com/maithuc2003/Test_GIN_golang/internal/routes"
+	"github.com/maithuc2003/Test_GIN_golang/internal/tracing"
+	"github.com/maithuc2003/Test_GIN_golang/internal/worker"
+	jwtutil "github.com/maithuc2003/Test_GIN_golang/pkg/jwt"
+	"gorm.io/gorm"
 )
 
 func main() {
-	db := config.ConnectDB()
-	r := routes.SetupRouter(db)
-	r.Run(":8080")
+	// "import" là lệnh con có flag riêng; cấu hình khi đó chỉ lấy từ file và env
+	args := os.Args[1:]
+	importCmd := len(args) > 0 && args[0] == "import"
+	if importCmd {
+		args = nil
+	}
+	cfg, err := config.Load(args)
+	if errors.Is(err, flag.ErrHelp) {
+		os.Exit(0)
+	}
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "config:", err)
+		os.Exit(2)
+	}
+
+	// Logger mặc định; thư viện còn dùng package log cũng ghi qua slog
+	slog.SetDefault(cfg.Log.Logger())
+	if cfg.JWT.Secret == "" {
+		slog.Warn("JWT_SECRET is not set, using the insecure development key")
+	}
+	jwtutil.Configure(cfg.JWT.Secret, cfg.JWT.TTL)
+
+	exporter, err := cfg.Tracing.SpanExporter(context.Background())
+	if err != nil {
+		slog.Error("Failed to configure tracing", "error", err)
+		os.Exit(1)
+	}
+	shutdownTracing, err := tracing.Setup(context.Background(), exporter)
+	if err != nil {
+		slog.Error("Failed to configure tracing", "error", err)
+		os.Exit(1)
+	}
+
+	db, err := config.ConnectDB(cfg.Database)
+	if err != nil {
+		slog.Error("Failed to connect to database", "error", err)
+		os.Exit(1)
+	}
+	if err := database.Migrate(db); err != nil {
+		slog.Error("Failed to migrate database", "error", err)
+		os.Exit(1)
+	}
+
+	if importCmd {
+		os.Exit(runImport(db, os.Args[2:]))
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	cache := newCacheClient(cfg.Cache)
+	workers := worker.NewGroup(context.Background())
+	srv := &http.Server{
+		Addr:              cfg.Server.Addr(),
+		Handler:           routes.SetupRouter(cfg, db, cache, workers),
+		ReadHeaderTimeout: cfg.Server.ReadHeaderTimeout,
+		ReadTimeout:       cfg.Server.ReadTimeout,
+		WriteTimeout:      cfg.Server.WriteTimeout,
+		IdleTimeout:       cfg.Server.IdleTimeout,
+	}
+
+	serveErr := make(chan error, 1)
+	go func() {
+		slog.Info("Server listening", "addr", srv.Addr)
+		serveErr <- srv.ListenAndServe()
+	}()
+
+	select {
+	case err := <-serveErr:
+		slog.Error("Server failed", "error", err)
+		os.Exit(1)
+	case <-ctx.Done():
+	}
+	// Tín hiệu thứ hai sẽ dừng tiến trình ngay
+	stop()
+
+	slog.Info("Shutting down")
+	if err := shutdown(cfg.Server.ShutdownTimeout, srv, workers, db, cache, shutdownTracing); err != nil {
+		slog.Error("Shutdown incomplete", "error", err)
+		os.Exit(1)
+	}
+	slog.Info("Shutdown complete")
+}
+
+// newCacheClient tạo client redis dùng chung cho readiness và rate limit; nil khi không có Addr
+func newCacheClient(c config.CacheConfig) *redis.Client {
+	if c.Addr == "" {
+		return nil
+	}
+	return redis.NewClient(redis.Config{Addr: c.Addr, Password: c.Password, DB: c.DB, Timeout: c.Timeout})
+}
+
+// shutdown ngừng nhận kết nối mới và chờ request đang chạy, rồi dừng worker nền, đẩy
+// nốt span và đóng connection pool của database và redis. Mọi bước dùng chung timeout.
+func shutdown(timeout time.Duration, srv *http.Server, workers *worker.Group, db *gorm.DB, cache *redis.Client, shutdownTracing func(context.Context) error) error {
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+
+	var errs []error
+	if err := srv.Shutdown(ctx); err != nil {
+		errs = append(errs, err)
+	}
+	if err := workers.Stop(ctx); err != nil {
+		errs = append(errs, err)
+	}
+	if err := shutdownTracing(ctx); err != nil {
+		errs = append(errs, err)
+	}
+	sqlDB, err := db.DB()
+	if err == nil {
+		err = sqlDB.Close()
+	}
+	if err != nil {
+		errs = append(errs, err)
+	}
+	if cache != nil {
+		if err := cache.Close(); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
 }
@@ -0,0 +1,80 @@
+package isbn
+
+import (
+	"errors"
+	"strings"
+)
+
+var (
+	ErrInvalidLength   = errors.New("isbn must have 10 or 13 digits")
+	ErrInvalidChar     = errors.New("isbn contains invalid characters")
+	ErrInvalidChecksum = errors.New("isbn checksum is invalid")
+)
+
+// Normalize kiểm tra ISBN-10/ISBN-13 (cho phép dấu gạch và khoảng trắng) và trả về
+// dạng ISBN-13 không dấu gạch. ISBN-10 được đổi sang ISBN-13 để một cuốn sách chỉ có
+// một giá trị duy nhất trong DB.
+func Normalize(s string) (string, error) {
+	compact := strings.ToUpper(strings.NewReplacer("-", "", " ", "").Replace(s))
+	switch len(compact) {
+	case 10:
+		if err := validate10(compact); err != nil {
+			return "", err
+		}
+		body := "978" + compact[:9]
+		return body + string(checkDigit13(body)), nil
+	case 13:
+		if err := validate13(compact); err != nil {
+			return "", err
+		}
+		return compact, nil
+	default:
+		return "", ErrInvalidLength
+	}
+}
+
+func validate10(s string) error {
+	sum := 0
+	for i := 0; i < 10; i++ {
+		c := s[i]
+		var d int
+		switch {
+		case c >= '0' && c <= '9':
+			d = int(c - '0')
+		case c == 'X' && i == 9:
+			d = 10
+		default:
+			return ErrInvalidChar
+		}
+		sum += d * (10 - i)
+	}
+	if sum%11 != 0 {
+		return ErrInvalidChecksum
+	}
+	return nil
+}
+
+func validate13(s string) error {
+	for i := 0; i < 13; i++ {
+		if s[i] < '0' || s[i] > '9' {
+			return ErrInvalidChar
+		}
+	}
+	if checkDigit13(s[:12]) != s[12] {
+		return ErrInvalidChecksum
+	}
+	return nil
+}
+
+// checkDigit13 tính chữ số kiểm tra cho 12 chữ số đầu của ISBN-13
+func checkDigit13(body string) byte {
+	sum := 0
+	for i := 0; i < 12; i++ {
+		d := int(body[i] - '0')
+		if i%2 == 1 {
+			d *= 3
+		}
+		sum += d
+	}
+	return byte('0' + (10-sum%10)%10)
+}
@@ -0,0 +1,39 @@
+package isbn_test
+
+import (
+	"testing"
+
+	"github.com/maithuc2003/Test_GIN_golang/pkg/isbn"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNormalize(t *testing.T) {
+	tests := []struct {
+		name    string
+		input   string
+		want    string
+		wantErr error
+	}{
+		{"isbn-13 with hyphens", "978-0-306-40615-7", "9780306406157", nil},
+		{"isbn-10 converted to isbn-13", "0-306-40615-2", "9780306406157", nil},
+		{"isbn-10 with X check digit", "0-8044-2957-X", "9780804429573", nil},
+		{"lowercase x", "080442957x", "9780804429573", nil},
+		{"bad isbn-13 checksum", "9780306406158", "", isbn.ErrInvalidChecksum},
+		{"bad isbn-10 checksum", "0306406153", "", isbn.ErrInvalidChecksum},
+		{"letters", "97803064061AB", "", isbn.ErrInvalidChar},
+		{"X not last", "X306406152", "", isbn.ErrInvalidChar},
+		{"wrong length", "12345", "", isbn.ErrInvalidLength},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := isbn.Normalize(tt.input)
+			if tt.wantErr != nil {
+				require.ErrorIs(t, err, tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tt.want, got)
+		})
+	}
+}
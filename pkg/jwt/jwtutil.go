@@ -1,20 +1,29 @@
 package jwtutil
 
 import (
-	"os"
 	"time"
 
 	"github.com/golang-jwt/jwt/v5"
 )
 
-var jwtSecret = func() []byte {
-	secret := os.Getenv("JWT_SECRET")
-	if secret == "" {
-		// fallback default for local dev/test
-		secret = "default_secret"
+// defaultSecret chỉ dùng cho local dev/test khi chưa gọi Configure
+const defaultSecret = "default_secret"
+
+var (
+	jwtSecret = []byte(defaultSecret)
+	tokenTTL  = 72 * time.Hour
+)
+
+// Configure đặt khoá ký và thời hạn token; gọi một lần lúc khởi động trước khi phục vụ
+// request. secret rỗng giữ khoá mặc định, ttl <= 0 giữ thời hạn mặc định.
+func Configure(secret string, ttl time.Duration) {
+	if secret != "" {
+		jwtSecret = []byte(secret)
 	}
-	return []byte(secret)
-}()
+	if ttl > 0 {
+		tokenTTL = ttl
+	}
+}
 
 func JwtSecret() []byte {
 	return jwtSecret
@@ -23,7 +32,7 @@ func GenerateJWT(userID uint, username string) (string, error) {
 	claims := jwt.MapClaims{
 		"user_id":  userID,
 		"username": username,
-		"exp":      time.Now().Add(72 * time.Hour).Unix(), // Expiration
+		"exp":      time.Now().Add(tokenTTL).Unix(), // Expiration
 	}
 	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
 	return token.SignedString(jwtSecret)
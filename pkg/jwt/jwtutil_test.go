@@ -54,3 +54,22 @@ func TestGenerateJWT_TableDriven(t *testing.T) {
 		})
 	}
 }
+
+func TestConfigure(t *testing.T) {
+	defer jwtutil.Configure("default_secret", 72*time.Hour)
+	jwtutil.Configure("a-much-longer-secret-for-production", time.Hour)
+
+	tokenStr, err := jwtutil.GenerateJWT(1, "john")
+	require.NoError(t, err)
+
+	token, err := jwt.Parse(tokenStr, func(token *jwt.Token) (interface{}, error) {
+		return []byte("a-much-longer-secret-for-production"), nil
+	})
+	require.NoError(t, err)
+	claims := token.Claims.(jwt.MapClaims)
+	require.WithinDuration(t, time.Now().Add(time.Hour), time.Unix(int64(claims["exp"].(float64)), 0), time.Minute)
+
+	// Giá trị rỗng giữ cấu hình hiện tại
+	jwtutil.Configure("", 0)
+	require.Equal(t, []byte("a-much-longer-secret-for-production"), jwtutil.JwtSecret())
+}
@@ -0,0 +1,24 @@
+package textnorm
+
+import (
+	"strings"
+	"unicode"
+
+	"golang.org/x/text/runes"
+	"golang.org/x/text/transform"
+	"golang.org/x/text/unicode/norm"
+)
+
+// đ/Đ không tách được dấu qua NFD nên phải thay thủ công
+var vietnameseReplacer = strings.NewReplacer("đ", "d", "Đ", "d")
+
+// Name chuẩn hoá tên để so sánh trùng lặp: bỏ dấu, chữ thường, gộp khoảng trắng.
+// "Nguyễn  Nhật Ánh" và "nguyen nhat anh" cho cùng một kết quả.
+func Name(s string) string {
+	t := transform.Chain(norm.NFD, runes.Remove(runes.In(unicode.Mn)), norm.NFC)
+	stripped, _, err := transform.String(t, vietnameseReplacer.Replace(s))
+	if err != nil {
+		stripped = s
+	}
+	return strings.Join(strings.Fields(strings.ToLower(stripped)), " ")
+}
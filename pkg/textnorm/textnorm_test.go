@@ -0,0 +1,29 @@
+package textnorm_test
+
+import (
+	"testing"
+
+	"github.com/maithuc2003/Test_GIN_golang/pkg/textnorm"
+	"github.com/stretchr/testify/require"
+)
+
+func TestName(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+		want  string
+	}{
+		{"plain ascii", "Neil Gaiman", "neil gaiman"},
+		{"vietnamese diacritics", "Nguyễn Nhật Ánh", "nguyen nhat anh"},
+		{"d with stroke", "Đặng Thuỳ Trâm", "dang thuy tram"},
+		{"extra whitespace", "  Tô   Hoài ", "to hoai"},
+		{"latin accents", "Gabriel García Márquez", "gabriel garcia marquez"},
+		{"empty", "", ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			require.Equal(t, tt.want, textnorm.Name(tt.input))
+		})
+	}
+}